@@ -1,15 +1,29 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"net"
 	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
 
 	"github.com/moabdelazem/k8s-app/internal/api"
 	"github.com/moabdelazem/k8s-app/internal/config"
 	"github.com/moabdelazem/k8s-app/internal/database"
+	"github.com/moabdelazem/k8s-app/internal/selfcheck"
 	"github.com/moabdelazem/k8s-app/pkg/logger"
+	"github.com/moabdelazem/k8s-app/pkg/response"
 	"go.uber.org/zap"
+	"google.golang.org/grpc"
 )
 
+// shutdownTimeout bounds how long in-flight requests may take to finish on shutdown
+const shutdownTimeout = 10 * time.Second
+
 func main() {
 	// Initialize configuration
 	cfg, err := config.NewConfig()
@@ -23,25 +37,127 @@ func main() {
 	}
 	defer logger.Sync()
 
-	// Initialize database connection
+	// Only expose internal error details outside production
+	response.SetExposeInternalErrors(cfg.Env != "production")
+
+	// Polls kept in memory need no database
+	if cfg.RepoBackend == config.RepoBackendMemory {
+		logger.Warn("REPO_BACKEND=memory: skipping the database connection; polls are lost on restart")
+	} else {
+		connectDatabase(cfg)
+	}
+	defer database.Close()
+
+	// Exit with the full list of problems rather than serving in a partially broken state
+	report := selfcheck.Run(context.Background(), selfcheck.Checks(cfg, selfcheck.DefaultDependencies()))
+	report.Log()
+	if err := report.Err(); err != nil {
+		logger.Fatal("Startup self-check failed", zap.Error(err))
+	}
+
+	// Cancel background workers on SIGINT/SIGTERM
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// Setup routes with database and config
+	router, grpcServer := api.Setup(ctx, database.GetDB(), cfg)
+
+	server := &http.Server{
+		Addr:    cfg.Addr,
+		Handler: router,
+	}
+
+	// Start server
+	logger.Info("Starting server",
+		zap.String("address", cfg.Addr),
+		zap.String("environment", cfg.Env),
+	)
+
+	go func() {
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logger.Fatal("Server failed to start", zap.Error(err))
+		}
+	}()
+
+	// The gRPC API shares the poll service and access controls, and so every rule, with the HTTP routes
+	if grpcServer != nil {
+		startGRPCServer(cfg.GRPC.Addr, grpcServer)
+	}
+
+	// Wait for a shutdown signal, then drain in-flight requests on both servers
+	<-ctx.Done()
+	logger.Info("Shutting down server", zap.Duration("timeout", shutdownTimeout))
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	if grpcServer != nil {
+		wg.Go(func() { stopGRPCServer(shutdownCtx, grpcServer) })
+	}
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		logger.Error("Server shutdown failed", zap.Error(err))
+	}
+	wg.Wait()
+}
+
+// startGRPCServer serves grpcServer on addr in the background, exiting when the port cannot be bound
+func startGRPCServer(addr string, grpcServer *grpc.Server) {
+	lis, err := net.Listen("tcp", addr)
+	if err != nil {
+		logger.Fatal("gRPC server failed to start", zap.Error(err))
+	}
+
+	logger.Info("Starting gRPC server", zap.String("address", addr))
+
+	go func() {
+		if err := grpcServer.Serve(lis); err != nil && !errors.Is(err, grpc.ErrServerStopped) {
+			logger.Fatal("gRPC server failed", zap.Error(err))
+		}
+	}()
+}
+
+// stopGRPCServer lets in-flight RPCs finish, cutting them off once ctx is done
+func stopGRPCServer(ctx context.Context, grpcServer *grpc.Server) {
+	stopped := make(chan struct{})
+	go func() {
+		grpcServer.GracefulStop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+	case <-ctx.Done():
+		logger.Error("gRPC server shutdown timed out; closing remaining connections")
+		grpcServer.Stop()
+	}
+}
+
+// connectDatabase opens the connection pool and pre-fills it, exiting when the database is unreachable
+func connectDatabase(cfg *config.Config) {
 	dbConfig := &database.Config{
-		Host:            cfg.DB.Host,
-		Port:            cfg.DB.Port,
-		User:            cfg.DB.User,
-		Password:        cfg.DB.Password,
-		DBName:          cfg.DB.DBName,
-		SSLMode:         cfg.DB.SSLMode,
-		MaxOpenConns:    cfg.DB.MaxOpenConns,
-		MaxIdleConns:    cfg.DB.MaxIdleConns,
-		ConnMaxLifetime: cfg.DB.ConnMaxLifetime,
-		MaxRetries:      cfg.DB.MaxRetries,
-		RetryDelay:      cfg.DB.RetryDelay,
+		Driver:            cfg.DB.Driver,
+		Host:              cfg.DB.Host,
+		Port:              cfg.DB.Port,
+		User:              cfg.DB.User,
+		Password:          cfg.DB.Password,
+		DBName:            cfg.DB.DBName,
+		SSLMode:           cfg.DB.SSLMode,
+		MaxOpenConns:      cfg.DB.MaxOpenConns,
+		MaxIdleConns:      cfg.DB.MaxIdleConns,
+		ConnMaxLifetime:   cfg.DB.ConnMaxLifetime,
+		MaxRetries:        cfg.DB.MaxRetries,
+		RetryDelay:        cfg.DB.RetryDelay,
+		ValidationQuery:   cfg.DB.ValidationQuery,
+		ValidationTimeout: cfg.DB.ValidationTimeout,
 	}
 
 	if _, err := database.NewConnection(dbConfig); err != nil {
 		logger.Fatal("Failed to connect to database", zap.Error(err))
 	}
-	defer database.Close()
+	if cfg.DB.Driver == config.DBDriverSQLite {
+		logger.Warn("DB_DRIVER=sqlite: only creating, reading, listing and voting on polls are supported; other features need Postgres")
+	}
 
 	logger.Info("Database connection pool initialized",
 		zap.Int("max_open_conns", cfg.DB.MaxOpenConns),
@@ -51,16 +167,19 @@ func main() {
 		zap.Duration("retry_delay", cfg.DB.RetryDelay),
 	)
 
-	// Setup routes with database and config
-	router := api.SetupRoutes(database.GetDB(), cfg)
-
-	// Start server
-	logger.Info("Starting server",
-		zap.String("address", cfg.Addr),
-		zap.String("environment", cfg.Env),
-	)
-
-	if err := http.ListenAndServe(cfg.Addr, router); err != nil {
-		logger.Fatal("Server failed to start", zap.Error(err))
+	// Pre-fill the pool so the first requests after a deploy skip the connect latency
+	if cfg.DB.WarmupConns > 0 {
+		warmupCtx, cancel := context.WithTimeout(context.Background(), cfg.DB.ValidationTimeout)
+		warmed, err := database.Warmup(warmupCtx, cfg.DB.WarmupConns)
+		cancel()
+		if err != nil {
+			logger.Warn("Database pool warmup incomplete",
+				zap.Int("requested", cfg.DB.WarmupConns),
+				zap.Int("warmed", warmed),
+				zap.Error(err),
+			)
+		} else {
+			logger.Info("Database pool warmed up", zap.Int("warmed", warmed))
+		}
 	}
 }
@@ -17,7 +17,31 @@ CREATE TABLE IF NOT EXISTS polls (
     created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
     expires_at TIMESTAMP WITH TIME ZONE,
     is_active BOOLEAN DEFAULT true,
-    total_votes BIGINT DEFAULT 0
+    total_votes BIGINT DEFAULT 0,
+    owner_id VARCHAR(255), -- User ID or voter identifier of the creator
+    allow_weighted BOOLEAN DEFAULT false, -- Votes may carry a weight other than 1
+    require_confirmation BOOLEAN DEFAULT false, -- Votes only count once confirmed with a token
+    quiz_mode BOOLEAN DEFAULT false, -- Options are marked correct/incorrect, revealed after voting
+    poll_group VARCHAR(100), -- Poll series a voter may vote on only once; matched case-insensitively
+    randomize_options BOOLEAN DEFAULT false, -- Options are shown to each voter in a shuffled order
+    allowlist_only BOOLEAN DEFAULT false, -- Only voters in poll_allowed_voters may vote
+    allow_write_in BOOLEAN DEFAULT false, -- Voters may answer with free text, stored in write_in_votes
+    max_votes BIGINT CHECK (max_votes >= 1), -- Capacity; NULL means unlimited
+    voting_window_start VARCHAR(5), -- Time of day (HH:MM) votes open in timezone; NULL for no window
+    voting_window_end VARCHAR(5), -- Time of day (HH:MM) votes close; earlier than the start for overnight windows
+    timezone VARCHAR(64), -- IANA zone of the voting window; NULL means UTC
+    acknowledgement_mode BOOLEAN DEFAULT false, -- Single-option poll whose votes are acknowledgements
+    featured BOOLEAN DEFAULT false, -- Pinned by editors to the featured listing
+    featured_rank INTEGER, -- Position in the featured listing, lowest first; NULL lists the poll after ranked ones
+    metadata JSONB, -- Integrator-defined JSON object, e.g. campaign IDs or UI hints; NULL when none
+    closed_at TIMESTAMP WITH TIME ZONE, -- When the poll was deleted or closed on expiry; drives archival
+    deleted_at TIMESTAMP WITH TIME ZONE, -- When the poll was deleted; NULL for live and expired polls
+    -- The vote triggers raise check_violation (23514) tagged with this name when a vote would take
+    -- a poll past its capacity; the row lock they take keeps concurrent votes from overshooting
+    CONSTRAINT poll_within_capacity CHECK (
+        max_votes IS NULL
+        OR total_votes <= max_votes
+    )
 );
 
 -- Poll options table
@@ -30,6 +54,7 @@ CREATE TABLE IF NOT EXISTS poll_options (
     ),
     vote_count BIGINT DEFAULT 0,
     position INTEGER NOT NULL,
+    is_correct BOOLEAN NOT NULL DEFAULT false, -- Correct answer on quiz polls
     created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
     CONSTRAINT unique_poll_position UNIQUE (poll_id, position)
 );
@@ -40,10 +65,102 @@ CREATE TABLE IF NOT EXISTS votes (
     poll_id UUID NOT NULL REFERENCES polls (id) ON DELETE CASCADE,
     option_id UUID NOT NULL REFERENCES poll_options (id) ON DELETE CASCADE,
     voter_identifier VARCHAR(255) NOT NULL, -- Could be IP, session ID, or user ID
+    weight BIGINT NOT NULL DEFAULT 1 CHECK (weight >= 1),
+    campaign VARCHAR(64), -- Campaign tag of the share link the vote came through
     voted_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
     CONSTRAINT unique_voter_per_poll UNIQUE (poll_id, voter_identifier)
 );
 
+-- Write-in votes table (free-text answers on polls allowing write-ins)
+-- A voter has either a vote or a write-in vote on a poll; vote transactions lock the poll row
+-- before checking the other table, so the two cannot race
+CREATE TABLE IF NOT EXISTS write_in_votes (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4 (),
+    poll_id UUID NOT NULL REFERENCES polls (id) ON DELETE CASCADE,
+    voter_identifier VARCHAR(255) NOT NULL,
+    write_in_text TEXT NOT NULL CHECK (
+        length(write_in_text) >= 1
+        AND length(write_in_text) <= 200
+    ),
+    weight BIGINT NOT NULL DEFAULT 1 CHECK (weight >= 1),
+    campaign VARCHAR(64),
+    voted_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT unique_write_in_voter_per_poll UNIQUE (poll_id, voter_identifier)
+);
+
+-- Allowed voters table (invited voters of allowlist-only polls)
+CREATE TABLE IF NOT EXISTS poll_allowed_voters (
+    poll_id UUID NOT NULL REFERENCES polls (id) ON DELETE CASCADE,
+    voter_identifier VARCHAR(255) NOT NULL, -- Matched against votes.voter_identifier
+    added_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (poll_id, voter_identifier)
+);
+
+-- Vote milestones reached by each poll (VOTE_MILESTONES), so each poll.milestone event is written once
+CREATE TABLE IF NOT EXISTS poll_milestones (
+    poll_id UUID NOT NULL REFERENCES polls (id) ON DELETE CASCADE,
+    milestone BIGINT NOT NULL,
+    reached_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (poll_id, milestone)
+);
+
+-- Webhooks table (callback URLs notified of poll events)
+CREATE TABLE IF NOT EXISTS webhooks (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4 (),
+    poll_id UUID NOT NULL REFERENCES polls (id) ON DELETE CASCADE,
+    url TEXT NOT NULL,
+    events TEXT [] NOT NULL,
+    secret TEXT NOT NULL, -- HMAC key used to sign deliveries
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Poll templates table (reusable question and options, instantiated into new polls)
+CREATE TABLE IF NOT EXISTS poll_templates (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4 (),
+    name VARCHAR(100) NOT NULL,
+    question TEXT NOT NULL,
+    description TEXT,
+    options TEXT [] NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Outbox table (poll events written in the same transaction as the change, published by a relay)
+CREATE TABLE IF NOT EXISTS outbox_events (
+    id BIGSERIAL PRIMARY KEY, -- Publication order
+    poll_id UUID NOT NULL,
+    event_type VARCHAR(50) NOT NULL,
+    data JSONB,
+    occurred_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    published_at TIMESTAMP WITH TIME ZONE, -- NULL until the relay has published the event
+    attempts INTEGER NOT NULL DEFAULT 0, -- Failed publish attempts
+    next_attempt_at TIMESTAMP WITH TIME ZONE, -- Not offered to the relay before this; NULL when due
+    dead_lettered_at TIMESTAMP WITH TIME ZONE -- Set when the relay gives up on the event
+);
+
+-- Archive tables (closed polls moved out of the live tables once ARCHIVE_RETENTION has passed)
+-- They copy the live tables' columns, so changes to those tables must be repeated here
+CREATE TABLE IF NOT EXISTS polls_archive (
+    LIKE polls INCLUDING DEFAULTS,
+    archived_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (id)
+);
+
+CREATE TABLE IF NOT EXISTS poll_options_archive (
+    LIKE poll_options INCLUDING DEFAULTS,
+    PRIMARY KEY (id)
+);
+
+CREATE TABLE IF NOT EXISTS votes_archive (
+    LIKE votes INCLUDING DEFAULTS,
+    PRIMARY KEY (id)
+);
+
+CREATE TABLE IF NOT EXISTS write_in_votes_archive (
+    LIKE write_in_votes INCLUDING DEFAULTS,
+    PRIMARY KEY (id)
+);
+
 -- Indexes for performance
 CREATE INDEX idx_polls_created_at ON polls (created_at DESC);
 
@@ -51,6 +168,21 @@ CREATE INDEX idx_polls_active ON polls (is_active, expires_at)
 WHERE
     is_active = true;
 
+CREATE INDEX idx_polls_owner_active ON polls (owner_id)
+WHERE
+    is_active = true;
+
+CREATE INDEX idx_polls_featured ON polls (featured_rank, created_at DESC)
+WHERE
+    featured = true;
+
+-- Serves metadata key/value lookups, which use jsonb containment (@>)
+CREATE INDEX idx_polls_metadata ON polls USING GIN (metadata jsonb_path_ops);
+
+CREATE INDEX idx_polls_group ON polls (LOWER(poll_group))
+WHERE
+    poll_group IS NOT NULL;
+
 CREATE INDEX idx_poll_options_poll_id ON poll_options (poll_id, position);
 
 CREATE INDEX idx_votes_poll_id ON votes (poll_id);
@@ -59,14 +191,43 @@ CREATE INDEX idx_votes_option_id ON votes (option_id);
 
 CREATE INDEX idx_votes_voter ON votes (poll_id, voter_identifier);
 
+CREATE INDEX idx_write_in_votes_poll_text ON write_in_votes (poll_id, LOWER(write_in_text));
+
+-- A voter's own history across polls, newest first
+CREATE INDEX idx_votes_voter_history ON votes (voter_identifier, voted_at DESC);
+
+CREATE INDEX idx_write_in_votes_voter_history ON write_in_votes (voter_identifier, voted_at DESC);
+
+CREATE INDEX idx_webhooks_poll_id ON webhooks (poll_id);
+
+CREATE INDEX idx_polls_closed ON polls (COALESCE(closed_at, expires_at, created_at))
+WHERE
+    is_active = false;
+
+CREATE INDEX idx_poll_options_archive_poll_id ON poll_options_archive (poll_id, position);
+
+CREATE INDEX idx_votes_archive_voter ON votes_archive (poll_id, voter_identifier);
+
+CREATE INDEX idx_write_in_votes_archive_voter ON write_in_votes_archive (poll_id, voter_identifier);
+
+CREATE INDEX idx_outbox_unpublished ON outbox_events (id)
+WHERE
+    published_at IS NULL
+    AND dead_lettered_at IS NULL;
+
+CREATE INDEX idx_outbox_unpublished_poll ON outbox_events (poll_id, id)
+WHERE
+    published_at IS NULL
+    AND dead_lettered_at IS NULL;
+
 -- Function to update poll total votes (trigger)
 CREATE OR REPLACE FUNCTION update_poll_total_votes()
 RETURNS TRIGGER AS $$
 BEGIN
     IF TG_OP = 'INSERT' THEN
-        UPDATE polls SET total_votes = total_votes + 1 WHERE id = NEW.poll_id;
+        UPDATE polls SET total_votes = total_votes + NEW.weight WHERE id = NEW.poll_id;
     ELSIF TG_OP = 'DELETE' THEN
-        UPDATE polls SET total_votes = total_votes - 1 WHERE id = OLD.poll_id;
+        UPDATE polls SET total_votes = total_votes - OLD.weight WHERE id = OLD.poll_id;
     END IF;
     RETURN NULL;
 END;
@@ -76,4 +237,64 @@ $$ LANGUAGE plpgsql;
 CREATE TRIGGER trigger_update_poll_votes
 AFTER INSERT OR DELETE ON votes
 FOR EACH ROW
-EXECUTE FUNCTION update_poll_total_votes();
\ No newline at end of file
+EXECUTE FUNCTION update_poll_total_votes();
+
+-- Write-in votes count toward the total too
+CREATE TRIGGER trigger_update_poll_write_in_votes
+AFTER INSERT OR DELETE ON write_in_votes
+FOR EACH ROW
+EXECUTE FUNCTION update_poll_total_votes();
+
+-- Option count bounds (defense in depth for the service validation)
+-- Every poll must have between 2 and 10 options, or exactly one in acknowledgement mode.
+-- Violations raise check_violation (23514)
+-- tagged with the poll_options_count constraint name, which the API maps to a validation error.
+CREATE OR REPLACE FUNCTION check_poll_option_max()
+RETURNS TRIGGER AS $$
+BEGIN
+    -- Lock the poll so concurrent inserts cannot both pass the count check
+    PERFORM 1 FROM polls WHERE id = NEW.poll_id FOR UPDATE;
+    IF (SELECT COUNT(*) FROM poll_options WHERE poll_id = NEW.poll_id) >= 10 THEN
+        RAISE EXCEPTION 'poll % cannot have more than 10 options', NEW.poll_id
+            USING ERRCODE = 'check_violation', CONSTRAINT = 'poll_options_count';
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER trigger_poll_option_max
+BEFORE INSERT ON poll_options
+FOR EACH ROW
+EXECUTE FUNCTION check_poll_option_max();
+
+-- The minimum is checked at commit so a poll and its options can be inserted in one transaction
+CREATE OR REPLACE FUNCTION check_poll_option_min()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF NEW.acknowledgement_mode THEN
+        IF (SELECT COUNT(*) FROM poll_options WHERE poll_id = NEW.id) <> 1 THEN
+            RAISE EXCEPTION 'acknowledgement poll % must have exactly 1 option', NEW.id
+                USING ERRCODE = 'check_violation', CONSTRAINT = 'poll_options_count';
+        END IF;
+    ELSIF (SELECT COUNT(*) FROM poll_options WHERE poll_id = NEW.id) < 2 THEN
+        RAISE EXCEPTION 'poll % must have at least 2 options', NEW.id
+            USING ERRCODE = 'check_violation', CONSTRAINT = 'poll_options_count';
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE CONSTRAINT TRIGGER trigger_poll_option_min
+AFTER INSERT ON polls
+DEFERRABLE INITIALLY DEFERRED
+FOR EACH ROW
+EXECUTE FUNCTION check_poll_option_min();
+
+-- Schema version applied to this database
+-- Readiness stays down while the highest version is below the app's database.SchemaVersion
+CREATE TABLE IF NOT EXISTS schema_migrations (
+    version INTEGER PRIMARY KEY,
+    applied_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+INSERT INTO schema_migrations (version) VALUES (1), (2), (3), (4), (5), (6), (7), (8), (9), (10), (11), (12), (13), (14), (15), (16) ON CONFLICT (version) DO NOTHING;
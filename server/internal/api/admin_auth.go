@@ -0,0 +1,41 @@
+package api
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"github.com/moabdelazem/k8s-app/pkg/logger"
+	"github.com/moabdelazem/k8s-app/pkg/response"
+	"go.uber.org/zap"
+)
+
+// AdminAuthMiddleware guards admin routes with a shared API key.
+// The key is read from "Authorization: Bearer <key>" or the X-Admin-Key header.
+// When no key is configured every admin request is rejected.
+func AdminAuthMiddleware(apiKey string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if apiKey == "" {
+				response.Forbidden(w, "Admin API is disabled")
+				return
+			}
+
+			provided := r.Header.Get("X-Admin-Key")
+			if bearer, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); ok {
+				provided = bearer
+			}
+
+			if subtle.ConstantTimeCompare([]byte(provided), []byte(apiKey)) != 1 {
+				logger.Warn("Rejected admin request",
+					zap.String("path", r.URL.Path),
+					zap.String("remote_addr", r.RemoteAddr),
+				)
+				response.Unauthorized(w, "Invalid admin credentials")
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/moabdelazem/k8s-app/pkg/response"
+)
+
+// API version negotiation headers; X-API-Version wins when both are sent
+const (
+	apiVersionHeader    = "X-API-Version"
+	acceptVersionHeader = "Accept-Version"
+)
+
+// defaultAPIVersion is assumed for requests without a version header in lenient mode
+const defaultAPIVersion = "v1"
+
+// supportedAPIVersions are the versions clients may request, oldest first
+var supportedAPIVersions = []string{"v1"}
+
+// APIVersionMiddleware rejects requests asking for an API version not in supported with 400.
+// Requests without a version header are served as defaultAPIVersion, or rejected when required is set.
+// The version a request was served with is echoed in the X-API-Version response header.
+func APIVersionMiddleware(supported []string, required bool) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requested := r.Header.Get(apiVersionHeader)
+			if requested == "" {
+				requested = r.Header.Get(acceptVersionHeader)
+			}
+
+			version := normalizeAPIVersion(requested)
+			switch {
+			case version == "" && required:
+				rejectAPIVersion(w, supported, fmt.Sprintf("%s header is required", apiVersionHeader))
+				return
+			case version == "":
+				version = defaultAPIVersion
+			}
+
+			if !slices.Contains(supported, version) {
+				rejectAPIVersion(w, supported, fmt.Sprintf("Unsupported API version %q", requested))
+				return
+			}
+
+			w.Header().Set(apiVersionHeader, version)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// normalizeAPIVersion accepts "v1", "V1" and "1" as the same version
+func normalizeAPIVersion(version string) string {
+	version = strings.ToLower(strings.TrimSpace(version))
+	if version == "" || strings.HasPrefix(version, "v") {
+		return version
+	}
+	return "v" + version
+}
+
+// rejectAPIVersion responds with 400, listing the supported versions
+func rejectAPIVersion(w http.ResponseWriter, supported []string, reason string) {
+	response.JSON(w, http.StatusBadRequest, response.Response{
+		Success: false,
+		Error:   fmt.Sprintf("%s; supported versions: %s", reason, strings.Join(supported, ", ")),
+		Data:    map[string][]string{"supported_versions": supported},
+	})
+}
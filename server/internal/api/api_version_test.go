@@ -0,0 +1,75 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/moabdelazem/k8s-app/pkg/response"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPIVersionMiddleware(t *testing.T) {
+	tests := []struct {
+		name        string
+		required    bool
+		headers     map[string]string
+		wantCode    int
+		wantVersion string
+	}{
+		{name: "supported X-API-Version", headers: map[string]string{"X-API-Version": "v1"}, wantCode: http.StatusOK, wantVersion: "v1"},
+		{name: "supported Accept-Version", headers: map[string]string{"Accept-Version": "v1"}, wantCode: http.StatusOK, wantVersion: "v1"},
+		{name: "bare version number", headers: map[string]string{"X-API-Version": "1"}, wantCode: http.StatusOK, wantVersion: "v1"},
+		{name: "X-API-Version wins over Accept-Version", headers: map[string]string{"X-API-Version": "v1", "Accept-Version": "v2"}, wantCode: http.StatusOK, wantVersion: "v1"},
+		{name: "unsupported version", headers: map[string]string{"X-API-Version": "v2"}, wantCode: http.StatusBadRequest},
+		{name: "absent defaults to v1", wantCode: http.StatusOK, wantVersion: "v1"},
+		{name: "strict supported", required: true, headers: map[string]string{"Accept-Version": "v1"}, wantCode: http.StatusOK, wantVersion: "v1"},
+		{name: "strict unsupported", required: true, headers: map[string]string{"X-API-Version": "v9"}, wantCode: http.StatusBadRequest},
+		{name: "strict absent", required: true, wantCode: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := APIVersionMiddleware([]string{"v1"}, tt.required)(okHandler)
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/polls", nil)
+			for key, value := range tt.headers {
+				req.Header.Set(key, value)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.wantCode, rec.Code)
+			assert.Equal(t, tt.wantVersion, rec.Header().Get("X-API-Version"))
+			if tt.wantCode == http.StatusBadRequest {
+				var body response.Response
+				require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+				assert.Contains(t, body.Error, "supported versions: v1")
+				assert.Equal(t, map[string]any{"supported_versions": []any{"v1"}}, body.Data)
+			}
+		})
+	}
+}
+
+func TestSetupRoutes_APIVersionScope(t *testing.T) {
+	cfg := newTestConfig()
+	cfg.RequireAPIVersion = true
+	router := SetupRoutes(context.Background(), nil, cfg)
+
+	// API routes need a version header; probes and docs do not
+	rec := serve(t, router, http.MethodGet, "/api/v1/polls/not-a-uuid")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), "X-API-Version header is required")
+	assert.Equal(t, http.StatusOK, serve(t, router, http.MethodGet, "/live").Code)
+	assert.Equal(t, http.StatusOK, serve(t, router, http.MethodGet, "/openapi.json").Code)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/polls/not-a-uuid", nil)
+	req.Header.Set("X-API-Version", "v1")
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), "Invalid poll ID")
+}
@@ -0,0 +1,39 @@
+package api
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/moabdelazem/k8s-app/pkg/auth"
+	"github.com/moabdelazem/k8s-app/pkg/logger"
+	"github.com/moabdelazem/k8s-app/pkg/response"
+	"go.uber.org/zap"
+)
+
+// AuthMiddleware requires a valid HS256 JWT in "Authorization: Bearer <token>".
+// The verified claims are stored in the request context for handlers.
+func AuthMiddleware(secret string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+			if !ok || token == "" {
+				response.Unauthorized(w, "Authentication required")
+				return
+			}
+
+			claims, err := auth.ParseToken(secret, token, time.Now())
+			if err != nil {
+				logger.Warn("Rejected unauthenticated request",
+					zap.Error(err),
+					zap.String("path", r.URL.Path),
+					zap.String("remote_addr", r.RemoteAddr),
+				)
+				response.Unauthorized(w, "Invalid or expired token")
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(auth.WithClaims(r.Context(), claims)))
+		})
+	}
+}
@@ -0,0 +1,47 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/moabdelazem/k8s-app/pkg/response"
+)
+
+// bodilessMethods are the methods whose requests carry no body, which the body limit leaves alone
+var bodilessMethods = map[string]bool{
+	http.MethodGet:     true,
+	http.MethodHead:    true,
+	http.MethodOptions: true,
+	http.MethodTrace:   true,
+}
+
+// BodyLimitMiddleware bounds the bodies of POST, PUT, PATCH, DELETE and any other method that can carry one.
+// A declared Content-Length above maxBytes is rejected with 413 before the body is read,
+// and bodies of unknown length (chunked transfer encoding) are rejected with 411 when requireLength is set.
+// Bodies that are still accepted are capped at maxBytes while being read; maxBytes 0 disables the limit.
+// Requests for which exempt returns true (e.g. bulk uploads with a limit of their own) are not checked.
+func BodyLimitMiddleware(maxBytes int64, requireLength bool, exempt func(r *http.Request) bool) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if bodilessMethods[r.Method] || (exempt != nil && exempt(r)) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			// ContentLength is -1 when the client did not declare a length
+			if requireLength && r.ContentLength < 0 {
+				response.Error(w, http.StatusLengthRequired, "Content-Length header is required")
+				return
+			}
+
+			if maxBytes > 0 {
+				if r.ContentLength > maxBytes {
+					response.Error(w, http.StatusRequestEntityTooLarge, fmt.Sprintf("Request body must not exceed %d bytes", maxBytes))
+					return
+				}
+				r.Body = http.MaxBytesReader(w, r.Body, maxBytes)
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,76 @@
+package api
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBodyLimitMiddleware(t *testing.T) {
+	tests := []struct {
+		name          string
+		method        string
+		contentLength int64 // -1 = chunked, no Content-Length
+		maxBytes      int64
+		requireLength bool
+		wantCode      int
+	}{
+		{name: "declared length within limit", method: http.MethodPost, contentLength: 10, maxBytes: 100, wantCode: http.StatusOK},
+		{name: "declared length over limit", method: http.MethodPost, contentLength: 101, maxBytes: 100, wantCode: http.StatusRequestEntityTooLarge},
+		{name: "put over limit", method: http.MethodPut, contentLength: 101, maxBytes: 100, wantCode: http.StatusRequestEntityTooLarge},
+		{name: "patch over limit", method: http.MethodPatch, contentLength: 101, maxBytes: 100, wantCode: http.StatusRequestEntityTooLarge},
+		{name: "delete over limit", method: http.MethodDelete, contentLength: 101, maxBytes: 100, wantCode: http.StatusRequestEntityTooLarge},
+		{name: "missing length when required", method: http.MethodPost, contentLength: -1, maxBytes: 100, requireLength: true, wantCode: http.StatusLengthRequired},
+		{name: "missing length when allowed", method: http.MethodPost, contentLength: -1, maxBytes: 100, wantCode: http.StatusOK},
+		{name: "unlimited", method: http.MethodPost, contentLength: 1 << 20, maxBytes: 0, wantCode: http.StatusOK},
+		{name: "reads are not checked", method: http.MethodGet, contentLength: -1, maxBytes: 100, requireLength: true, wantCode: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, "/api/v1/polls", strings.NewReader("{}"))
+			req.ContentLength = tt.contentLength
+			rec := httptest.NewRecorder()
+
+			BodyLimitMiddleware(tt.maxBytes, tt.requireLength, nil)(okHandler).ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.wantCode, rec.Code)
+		})
+	}
+}
+
+func TestBodyLimitMiddleware_CapsUndeclaredBody(t *testing.T) {
+	var readErr error
+	handler := BodyLimitMiddleware(8, false, nil)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, readErr = io.ReadAll(r.Body)
+	}))
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/polls", strings.NewReader(strings.Repeat("x", 64)))
+	req.ContentLength = -1
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	var maxBytesErr *http.MaxBytesError
+	assert.ErrorAs(t, readErr, &maxBytesErr)
+}
+
+func TestBodyLimitMiddleware_Exempt(t *testing.T) {
+	exempt := func(r *http.Request) bool { return r.URL.Path == "/api/v1/admin/backup" }
+	handler := BodyLimitMiddleware(100, true, exempt)(okHandler)
+
+	for path, wantCode := range map[string]int{
+		"/api/v1/admin/backup": http.StatusOK,
+		"/api/v1/polls":        http.StatusRequestEntityTooLarge,
+	} {
+		req := httptest.NewRequest(http.MethodPost, path, strings.NewReader("{}"))
+		req.ContentLength = 101
+		rec := httptest.NewRecorder()
+
+		handler.ServeHTTP(rec, req)
+
+		assert.Equal(t, wantCode, rec.Code, path)
+	}
+}
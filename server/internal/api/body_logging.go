@@ -0,0 +1,94 @@
+package api
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"regexp"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/moabdelazem/k8s-app/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// sensitiveFieldPattern matches JSON string fields whose values must never be logged
+var sensitiveFieldPattern = regexp.MustCompile(
+	`(?i)"(token|access_token|refresh_token|password|secret|api_key|authorization)"\s*:\s*"[^"]*"`,
+)
+
+// BodyLoggingMiddleware logs request and response bodies at debug level.
+// Only the first maxLength bytes are captured; the request body is re-buffered
+// so the handler still reads it in full.
+func BodyLoggingMiddleware(maxLength int) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var reqBody []byte
+			if r.Body != nil && r.Body != http.NoBody {
+				// Read only a bounded prefix and stitch it back in front of the remaining body
+				prefix, err := io.ReadAll(io.LimitReader(r.Body, int64(maxLength)+1))
+				if err != nil {
+					logger.Warn("Failed to read request body for logging", zap.Error(err))
+				}
+				r.Body = readCloser{io.MultiReader(bytes.NewReader(prefix), r.Body), r.Body}
+				reqBody = prefix
+			}
+
+			respBody := &limitedBuffer{max: maxLength + 1}
+			ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+			ww.Tee(respBody)
+
+			next.ServeHTTP(ww, r)
+
+			logger.Debug("Request/response bodies",
+				zap.String("request_id", middleware.GetReqID(r.Context())),
+				zap.String("method", r.Method),
+				zap.String("path", r.URL.Path),
+				zap.Int("status", ww.Status()),
+				zap.String("request_body", formatBody(reqBody, maxLength)),
+				zap.String("response_body", formatBody(respBody.Bytes(), maxLength)),
+			)
+		})
+	}
+}
+
+// formatBody truncates and redacts a captured body for logging
+func formatBody(body []byte, maxLength int) string {
+	truncated := len(body) > maxLength
+	if truncated {
+		body = body[:maxLength]
+	}
+
+	out := redactSensitiveFields(string(body))
+	if truncated {
+		out += "...(truncated)"
+	}
+	return out
+}
+
+// redactSensitiveFields masks the values of known sensitive JSON fields
+func redactSensitiveFields(body string) string {
+	return sensitiveFieldPattern.ReplaceAllString(body, `"$1":"[REDACTED]"`)
+}
+
+// readCloser pairs a replacement reader with the original body's Close
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+// limitedBuffer keeps at most max bytes and silently discards the rest
+type limitedBuffer struct {
+	bytes.Buffer
+	max int
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	if remaining := b.max - b.Len(); remaining > 0 {
+		if len(p) > remaining {
+			b.Buffer.Write(p[:remaining])
+		} else {
+			b.Buffer.Write(p)
+		}
+	}
+	return len(p), nil
+}
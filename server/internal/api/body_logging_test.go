@@ -0,0 +1,65 @@
+package api
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/moabdelazem/k8s-app/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// observeLogs swaps the global logger for an in-memory observer for the test duration
+func observeLogs(t *testing.T, level zapcore.Level) *observer.ObservedLogs {
+	core, logs := observer.New(level)
+	previous := logger.Log
+	logger.Log = zap.New(core)
+	t.Cleanup(func() { logger.Log = previous })
+	return logs
+}
+
+func TestBodyLoggingMiddleware_HandlerStillReadsBody(t *testing.T) {
+	logs := observeLogs(t, zapcore.DebugLevel)
+
+	payload := `{"question":"What is your favourite colour?","token":"s3cr3t","options":["red","blue"]}`
+
+	var received string
+	handler := BodyLoggingMiddleware(16)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		received = string(body)
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"success":true}`))
+	}))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/v1/polls", strings.NewReader(payload)))
+
+	// The handler sees the complete body even though only a prefix was captured
+	assert.Equal(t, payload, received)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	assert.Equal(t, `{"success":true}`, rec.Body.String())
+
+	entries := logs.FilterMessage("Request/response bodies").All()
+	require.Len(t, entries, 1)
+	fields := entries[0].ContextMap()
+	assert.Equal(t, payload[:16]+"...(truncated)", fields["request_body"])
+	assert.Equal(t, `{"success":true}`, fields["response_body"])
+}
+
+func TestRedactSensitiveFields(t *testing.T) {
+	body := `{"question":"Q?","token":"abc","Password" : "hunter2"}`
+
+	redacted := redactSensitiveFields(body)
+
+	assert.NotContains(t, redacted, "abc")
+	assert.NotContains(t, redacted, "hunter2")
+	assert.Contains(t, redacted, `"token":"[REDACTED]"`)
+	assert.Contains(t, redacted, `"question":"Q?"`)
+}
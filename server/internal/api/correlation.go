@@ -0,0 +1,67 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"regexp"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/google/uuid"
+)
+
+// Correlation headers; X-Correlation-ID wins over X-Request-Id, which wins over traceparent
+const (
+	correlationIDHeader = "X-Correlation-ID"
+	traceparentHeader   = "traceparent"
+)
+
+// maxCorrelationIDLength bounds the IDs reused from callers; longer ones are replaced
+const maxCorrelationIDLength = 128
+
+// traceparentPattern matches a W3C traceparent header, capturing its trace ID
+var traceparentPattern = regexp.MustCompile(`^[0-9a-f]{2}-([0-9a-f]{32})-[0-9a-f]{16}-[0-9a-f]{2}$`)
+
+// CorrelationIDMiddleware gives each request an ID tying its logs to the caller's, in place of chi's RequestID.
+// An ID sent by the caller, or the trace ID of its traceparent, is reused; a new one is generated only when
+// none is usable. The ID is stored where middleware.GetReqID finds it and echoed in X-Correlation-ID.
+func CorrelationIDMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := incomingCorrelationID(r)
+		if id == "" {
+			id = uuid.NewString()
+		}
+
+		w.Header().Set(correlationIDHeader, id)
+		ctx := context.WithValue(r.Context(), middleware.RequestIDKey, id)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// incomingCorrelationID returns the caller's correlation ID, or "" when it sent none that can be trusted in logs
+func incomingCorrelationID(r *http.Request) string {
+	for _, header := range []string{correlationIDHeader, middleware.RequestIDHeader} {
+		if id := r.Header.Get(header); validCorrelationID(id) {
+			return id
+		}
+	}
+	if match := traceparentPattern.FindStringSubmatch(r.Header.Get(traceparentHeader)); match != nil {
+		// An all-zero trace ID is invalid per the W3C spec
+		if match[1] != "00000000000000000000000000000000" {
+			return match[1]
+		}
+	}
+	return ""
+}
+
+// validCorrelationID accepts short IDs of visible ASCII characters, so a caller cannot forge log lines
+func validCorrelationID(id string) bool {
+	if id == "" || len(id) > maxCorrelationIDLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] < '!' || id[i] > '~' {
+			return false
+		}
+	}
+	return true
+}
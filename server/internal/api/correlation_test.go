@@ -0,0 +1,55 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCorrelationIDMiddleware(t *testing.T) {
+	tests := []struct {
+		name    string
+		headers map[string]string
+		wantID  string // Empty when a new ID must be generated
+	}{
+		{name: "correlation ID reused", headers: map[string]string{"X-Correlation-ID": "checkout-7f3a"}, wantID: "checkout-7f3a"},
+		{name: "correlation ID wins over request ID", headers: map[string]string{"X-Correlation-ID": "checkout-7f3a", "X-Request-Id": "req-1"}, wantID: "checkout-7f3a"},
+		{name: "request ID reused", headers: map[string]string{"X-Request-Id": "req-1"}, wantID: "req-1"},
+		{name: "trace ID taken from traceparent", headers: map[string]string{"traceparent": "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"}, wantID: "4bf92f3577b34da6a3ce929d0e0e4736"},
+		{name: "malformed traceparent ignored", headers: map[string]string{"traceparent": "00-not-a-trace-01"}},
+		{name: "all-zero trace ID ignored", headers: map[string]string{"traceparent": "00-00000000000000000000000000000000-00f067aa0ba902b7-01"}},
+		{name: "ID with spaces replaced", headers: map[string]string{"X-Correlation-ID": "fake\" level=error msg=forged"}},
+		{name: "overlong ID replaced", headers: map[string]string{"X-Correlation-ID": strings.Repeat("a", maxCorrelationIDLength+1)}},
+		{name: "generated when absent"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var seen string
+			handler := CorrelationIDMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				seen = middleware.GetReqID(r.Context())
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/polls", nil)
+			for name, value := range tt.headers {
+				req.Header.Set(name, value)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			returned := rec.Header().Get("X-Correlation-ID")
+			assert.Equal(t, seen, returned, "the ID in the logs is the one returned")
+			if tt.wantID != "" {
+				assert.Equal(t, tt.wantID, returned)
+				return
+			}
+			_, err := uuid.Parse(returned)
+			assert.NoError(t, err, "a new ID is generated")
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package docs
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// openAPISpec is the hand-maintained OpenAPI 3 description of the API.
+// Keep it in sync with the handlers and models when routes change.
+//
+//go:embed openapi.json
+var openAPISpec []byte
+
+// swaggerUIPage renders Swagger UI from a CDN, pointed at the sibling openapi.json route
+const swaggerUIPage = `<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="utf-8" />
+  <title>Quick Poll API Docs</title>
+  <link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@5/swagger-ui.css" />
+</head>
+<body>
+  <div id="swagger-ui"></div>
+  <script src="https://unpkg.com/swagger-ui-dist@5/swagger-ui-bundle.js" crossorigin></script>
+  <script>
+    window.onload = () => {
+      window.ui = SwaggerUIBundle({ url: "openapi.json", dom_id: "#swagger-ui" });
+    };
+  </script>
+</body>
+</html>
+`
+
+// SpecHandler serves the OpenAPI spec with its server URL set to basePath
+func SpecHandler(basePath string) (http.HandlerFunc, error) {
+	spec, err := specForBasePath(basePath)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(spec)
+	}, nil
+}
+
+// UIHandler serves the Swagger UI page
+func UIHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	_, _ = w.Write([]byte(swaggerUIPage))
+}
+
+// specForBasePath rewrites the spec's servers list so "Try it out" requests hit the mounted prefix
+func specForBasePath(basePath string) ([]byte, error) {
+	var spec map[string]any
+	if err := json.Unmarshal(openAPISpec, &spec); err != nil {
+		return nil, fmt.Errorf("failed to parse embedded OpenAPI spec: %w", err)
+	}
+
+	serverURL := basePath
+	if serverURL == "" {
+		serverURL = "/"
+	}
+	spec["servers"] = []map[string]string{{"url": serverURL}}
+
+	return json.Marshal(spec)
+}
@@ -0,0 +1,184 @@
+package docs
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/moabdelazem/k8s-app/internal/models"
+	"github.com/moabdelazem/k8s-app/pkg/response"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type openAPIDoc struct {
+	Servers []struct {
+		URL string `json:"url"`
+	} `json:"servers"`
+	Paths      map[string]map[string]json.RawMessage `json:"paths"`
+	Components struct {
+		Schemas map[string]struct {
+			Properties map[string]json.RawMessage `json:"properties"`
+		} `json:"schemas"`
+	} `json:"components"`
+}
+
+func loadSpec(t *testing.T, basePath string) openAPIDoc {
+	handler, err := SpecHandler(basePath)
+	require.NoError(t, err)
+
+	rec := httptest.NewRecorder()
+	handler(rec, httptest.NewRequest(http.MethodGet, "/openapi.json", nil))
+	require.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+
+	var doc openAPIDoc
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &doc))
+	return doc
+}
+
+// jsonFieldNames returns the JSON keys a struct type marshals to, flattening embedded structs
+func jsonFieldNames(typ reflect.Type) []string {
+	seen := map[string]bool{}
+	var collect func(reflect.Type)
+	collect = func(typ reflect.Type) {
+		for i := 0; i < typ.NumField(); i++ {
+			field := typ.Field(i)
+			tag := field.Tag.Get("json")
+			if tag == "-" {
+				continue
+			}
+			name := strings.Split(tag, ",")[0]
+			if field.Anonymous && name == "" {
+				collect(field.Type)
+				continue
+			}
+			if name == "" {
+				name = field.Name
+			}
+			seen[name] = true
+		}
+	}
+	collect(typ)
+
+	names := make([]string, 0, len(seen))
+	for name := range seen {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func TestSpec_SchemasMatchModels(t *testing.T) {
+	doc := loadSpec(t, "")
+
+	models := map[string]any{
+		"Response":             response.Response{},
+		"Poll":                 models.Poll{},
+		"PollOption":           models.PollOption{},
+		"PollWithOptions":      models.PollWithOptions{},
+		"OptionResult":         models.OptionResult{},
+		"PollResults":          models.PollResults{},
+		"Link":                 response.Link{},
+		"WriteInResult":        models.WriteInResult{},
+		"WriteInTally":         models.WriteInTally{},
+		"VoteTimeline":         models.VoteTimeline{},
+		"TimelineBucket":       models.TimelineBucket{},
+		"OptionVoteCount":      models.OptionVoteCount{},
+		"VoteImportSummary":    models.VoteImportSummary{},
+		"PollList":             models.PollList{},
+		"FeatureRequest":       models.FeatureRequest{},
+		"CreatePollRequest":    models.CreatePollRequest{},
+		"MetadataRequest":      models.MetadataRequest{},
+		"UpdateOptionsRequest": models.UpdateOptionsRequest{},
+		"OptionUpdate":         models.OptionUpdate{},
+		"VoteRequest":          models.VoteRequest{},
+		"VoteConfirmation":     models.VoteConfirmation{},
+		"VoteValidation":       models.VoteValidation{},
+		"VoteReceipt":          models.VoteReceipt{},
+		"VoteStatus":           models.VoteStatus{},
+		"VotingStatus":         models.VotingStatus{},
+		"VoterVote":            models.VoterVote{},
+		"VoterVoteList":        models.VoterVoteList{},
+		"ConfirmVoteRequest":   models.ConfirmVoteRequest{},
+		"ShareLinkRequest":     models.ShareLinkRequest{},
+		"ShareLink":            models.ShareLink{},
+		"SharedPoll":           models.SharedPoll{},
+		"OEmbed":               models.OEmbed{},
+		"PollTemplate":         models.PollTemplate{},
+		"TemplateRequest":      models.TemplateRequest{},
+		"Webhook":              models.Webhook{},
+		"AllowedVoter":         models.AllowedVoter{},
+		"AllowlistRequest":     models.AllowlistRequest{},
+		"BulkDeleteRequest":    models.BulkDeleteRequest{},
+		"SuspiciousVoteReport": models.SuspiciousVoteReport{},
+		"SuspicionThresholds":  models.SuspicionThresholds{},
+		"SubnetCluster":        models.SubnetCluster{},
+		"VoteBurst":            models.VoteBurst{},
+		"VoteRateReport":       models.VoteRateReport{},
+		"VoteRatePeak":         models.VoteRatePeak{},
+		"BackupRecord":         models.BackupRecord{},
+		"BackupHeader":         models.BackupHeader{},
+		"BackupPoll":           models.BackupPoll{},
+		"BackupOption":         models.BackupOption{},
+		"BackupVote":           models.BackupVote{},
+		"BackupWriteInVote":    models.BackupWriteInVote{},
+		"BackupAllowedVoter":   models.BackupAllowedVoter{},
+		"BackupSummary":        models.BackupSummary{},
+		"CreateWebhookRequest": models.CreateWebhookRequest{},
+		"ReadOnlyRequest":      models.ReadOnlyRequest{},
+	}
+
+	for name, model := range models {
+		t.Run(name, func(t *testing.T) {
+			schema, ok := doc.Components.Schemas[name]
+			require.True(t, ok, "schema %s missing from spec", name)
+
+			var specFields []string
+			for field := range schema.Properties {
+				specFields = append(specFields, field)
+			}
+			sort.Strings(specFields)
+
+			assert.Equal(t, jsonFieldNames(reflect.TypeOf(model)), specFields)
+		})
+	}
+}
+
+func TestSpec_DescribesPollRoutes(t *testing.T) {
+	doc := loadSpec(t, "")
+
+	expected := map[string][]string{
+		"/api/v1/polls":                      {"get", "post"},
+		"/api/v1/polls/{id}":                 {"get", "delete"},
+		"/api/v1/polls/{id}/options":         {"get", "put"},
+		"/api/v1/polls/{id}/voted":           {"get"},
+		"/api/v1/polls/{id}/timeline":        {"get"},
+		"/api/v1/polls/{id}/preview":         {"get"},
+		"/api/v1/polls/{id}/chart.svg":       {"get"},
+		"/api/v1/polls/{id}/results/stream":  {"get"},
+		"/api/v1/polls/{id}/vote":            {"post"},
+		"/api/v1/polls/{id}/vote/confirm":    {"post"},
+		"/api/v1/polls/{id}/share":           {"post"},
+		"/api/v1/polls/{id}/receipt/verify":  {"get"},
+		"/api/v1/templates":                  {"get", "post"},
+		"/api/v1/templates/{id}":             {"get", "put", "delete"},
+		"/api/v1/templates/{id}/instantiate": {"post"},
+	}
+
+	for path, methods := range expected {
+		operations, ok := doc.Paths[path]
+		require.True(t, ok, "path %s missing from spec", path)
+		for _, method := range methods {
+			assert.Contains(t, operations, method, "%s %s missing from spec", method, path)
+		}
+	}
+}
+
+func TestSpec_ServerURLFollowsBasePath(t *testing.T) {
+	assert.Equal(t, "/", loadSpec(t, "").Servers[0].URL)
+	assert.Equal(t, "/polls-service", loadSpec(t, "/polls-service").Servers[0].URL)
+}
@@ -0,0 +1,291 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/moabdelazem/k8s-app/internal/models"
+	"github.com/moabdelazem/k8s-app/internal/service"
+	"github.com/moabdelazem/k8s-app/pkg/logger"
+	"github.com/moabdelazem/k8s-app/pkg/response"
+	"go.uber.org/zap"
+)
+
+// AdminHandler serves operator-only endpoints
+type AdminHandler struct {
+	service *service.PollService
+}
+
+func NewAdminHandler(service *service.PollService) *AdminHandler {
+	return &AdminHandler{service: service}
+}
+
+// CloseExpiredPolls deactivates all polls whose expiry has passed
+func (h *AdminHandler) CloseExpiredPolls(w http.ResponseWriter, r *http.Request) {
+	logger.Info("Closing expired polls", zap.String("handler", "CloseExpiredPolls"))
+
+	closed, err := h.service.CloseExpiredPolls(r.Context())
+	if err != nil {
+		renderError(w, r, err, "Failed to close expired polls")
+		return
+	}
+
+	response.Success(w, "Expired polls closed", map[string]int64{
+		"closed": closed,
+	})
+}
+
+// BulkDeletePolls soft deletes every poll matching a filter, e.g. to clean up spam
+func (h *AdminHandler) BulkDeletePolls(w http.ResponseWriter, r *http.Request) {
+	var req models.BulkDeleteRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Error("Failed to decode bulk delete request", zap.Error(err))
+		response.BadRequest(w, "Invalid request body")
+		return
+	}
+
+	logger.Info("Bulk deleting polls", zap.String("handler", "BulkDeletePolls"))
+
+	deleted, err := h.service.BulkDeletePolls(r.Context(), &req)
+	if err != nil {
+		renderError(w, r, err, "Failed to delete polls")
+		return
+	}
+
+	response.Success(w, "Polls deleted", map[string]int64{
+		"deleted": deleted,
+	})
+}
+
+// ExpirePoll sets a poll's expiry to now so it behaves as naturally expired
+func (h *AdminHandler) ExpirePoll(w http.ResponseWriter, r *http.Request) {
+	pollID := PollIDFromContext(r.Context())
+
+	logger.Info("Expiring poll",
+		zap.String("handler", "ExpirePoll"),
+		zap.String("poll_id", pollID.String()),
+	)
+
+	expiresAt, err := h.service.ExpirePoll(r.Context(), pollID)
+	if err != nil {
+		renderError(w, r, err, "Failed to expire poll")
+		return
+	}
+
+	response.Success(w, "Poll expired", map[string]time.Time{
+		"expires_at": expiresAt,
+	})
+}
+
+// DeletePoll deletes a poll, including one with too many votes for its creator to delete
+func (h *AdminHandler) DeletePoll(w http.ResponseWriter, r *http.Request) {
+	pollID := PollIDFromContext(r.Context())
+
+	logger.Info("Deleting poll",
+		zap.String("handler", "DeletePoll"),
+		zap.String("poll_id", pollID.String()),
+	)
+
+	if err := h.service.DeletePollAsAdmin(r.Context(), pollID); err != nil {
+		renderError(w, r, err, "Failed to delete poll")
+		return
+	}
+
+	response.Success(w, "Poll deleted successfully", nil)
+}
+
+// SetFeatured pins a poll to the featured listing, or removes it from the listing
+func (h *AdminHandler) SetFeatured(w http.ResponseWriter, r *http.Request) {
+	pollID := PollIDFromContext(r.Context())
+
+	var req models.FeatureRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Error("Failed to decode feature request", zap.Error(err))
+		response.BadRequest(w, "Invalid request body")
+		return
+	}
+
+	poll, err := h.service.SetFeatured(r.Context(), pollID, &req)
+	if err != nil {
+		renderError(w, r, err, "Failed to update featured status")
+		return
+	}
+
+	response.Success(w, "Featured status updated", poll)
+}
+
+// ImportVotes bulk-loads historical votes for a poll from a CSV request body
+// Expected columns: option_id, voter_identifier, voted_at (RFC 3339)
+func (h *AdminHandler) ImportVotes(w http.ResponseWriter, r *http.Request) {
+	pollID := PollIDFromContext(r.Context())
+
+	logger.Info("Importing votes",
+		zap.String("handler", "ImportVotes"),
+		zap.String("poll_id", pollID.String()),
+	)
+
+	summary, err := h.service.ImportVotes(r.Context(), pollID, r.Body)
+	if err != nil {
+		renderError(w, r, err, "Failed to import votes")
+		return
+	}
+
+	response.Success(w, "Votes imported", summary)
+}
+
+// ExportBackup streams every poll with its options, votes and allowlist as an NDJSON bundle
+func (h *AdminHandler) ExportBackup(w http.ResponseWriter, r *http.Request) {
+	logger.Info("Exporting backup", zap.String("handler", "ExportBackup"))
+
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	w.Header().Set("Content-Disposition", `attachment; filename="polls-backup.ndjson"`)
+	w.WriteHeader(http.StatusOK)
+
+	// The status is already sent; a bundle cut short by a failure lacks its end record,
+	// so a restore rejects it
+	if err := h.service.ExportAll(r.Context(), w); err != nil {
+		logger.Error("Backup export aborted", zap.Error(err))
+	}
+}
+
+// RestoreBackup restores an NDJSON bundle from the request body into an empty database
+func (h *AdminHandler) RestoreBackup(w http.ResponseWriter, r *http.Request) {
+	logger.Info("Restoring backup", zap.String("handler", "RestoreBackup"))
+
+	summary, err := h.service.ImportAll(r.Context(), r.Body)
+	if err != nil {
+		renderError(w, r, err, "Failed to restore backup")
+		return
+	}
+
+	response.Success(w, "Backup restored", summary)
+}
+
+// RemoveVote deletes a single voter's vote from a poll, e.g. a fraudulent one
+func (h *AdminHandler) RemoveVote(w http.ResponseWriter, r *http.Request) {
+	pollID := PollIDFromContext(r.Context())
+
+	// Voter identifiers may contain reserved characters, e.g. IPv6 addresses, so clients escape them
+	voterID, err := url.PathUnescape(chi.URLParam(r, "voterID"))
+	if err != nil || voterID == "" {
+		response.BadRequest(w, "Invalid voter ID")
+		return
+	}
+
+	logger.Info("Removing vote",
+		zap.String("handler", "RemoveVote"),
+		zap.String("poll_id", pollID.String()),
+		zap.String("voter", voterID),
+	)
+
+	if err := h.service.RemoveVote(r.Context(), pollID, voterID); err != nil {
+		renderError(w, r, err, "Failed to remove vote")
+		return
+	}
+
+	response.Success(w, "Vote removed", nil)
+}
+
+// ListAllowedVoters lists the voters invited to vote on a poll
+func (h *AdminHandler) ListAllowedVoters(w http.ResponseWriter, r *http.Request) {
+	pollID := PollIDFromContext(r.Context())
+
+	voters, err := h.service.ListAllowedVoters(r.Context(), pollID)
+	if err != nil {
+		renderError(w, r, err, "Failed to retrieve allowed voters")
+		return
+	}
+
+	response.Success(w, "", voters)
+}
+
+// AddAllowedVoters adds voters to a poll's allowlist
+func (h *AdminHandler) AddAllowedVoters(w http.ResponseWriter, r *http.Request) {
+	pollID := PollIDFromContext(r.Context())
+
+	var req models.AllowlistRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Error("Failed to decode allowlist request", zap.Error(err))
+		response.BadRequest(w, "Invalid request body")
+		return
+	}
+
+	logger.Info("Adding allowed voters",
+		zap.String("handler", "AddAllowedVoters"),
+		zap.String("poll_id", pollID.String()),
+	)
+
+	added, err := h.service.AddAllowedVoters(r.Context(), pollID, req.Voters)
+	if err != nil {
+		renderError(w, r, err, "Failed to add allowed voters")
+		return
+	}
+
+	response.Success(w, "Allowed voters added", map[string]int64{
+		"added": added,
+	})
+}
+
+// RemoveAllowedVoter removes a voter from a poll's allowlist
+func (h *AdminHandler) RemoveAllowedVoter(w http.ResponseWriter, r *http.Request) {
+	pollID := PollIDFromContext(r.Context())
+
+	// Voter identifiers may contain reserved characters, e.g. IPv6 addresses, so clients escape them
+	voterID, err := url.PathUnescape(chi.URLParam(r, "voterID"))
+	if err != nil || voterID == "" {
+		response.BadRequest(w, "Invalid voter ID")
+		return
+	}
+
+	logger.Info("Removing allowed voter",
+		zap.String("handler", "RemoveAllowedVoter"),
+		zap.String("poll_id", pollID.String()),
+		zap.String("voter", voterID),
+	)
+
+	if err := h.service.RemoveAllowedVoter(r.Context(), pollID, voterID); err != nil {
+		renderError(w, r, err, "Failed to remove allowed voter")
+		return
+	}
+
+	response.Success(w, "Allowed voter removed", nil)
+}
+
+// GetSuspiciousVotes reports subnet clusters and vote bursts on a poll for review
+func (h *AdminHandler) GetSuspiciousVotes(w http.ResponseWriter, r *http.Request) {
+	pollID := PollIDFromContext(r.Context())
+
+	report, err := h.service.GetSuspiciousVotes(r.Context(), pollID)
+	if err != nil {
+		renderError(w, r, err, "Failed to retrieve suspicious votes")
+		return
+	}
+
+	response.Success(w, "", report)
+}
+
+// GetVoteRate reports the peak vote rate a poll sustained, for capacity planning
+// The bucket query parameter is a Go duration such as 1s or 1m (default 1s)
+func (h *AdminHandler) GetVoteRate(w http.ResponseWriter, r *http.Request) {
+	pollID := PollIDFromContext(r.Context())
+
+	bucket := service.DefaultVoteRateBucket
+	if bucketStr := r.URL.Query().Get("bucket"); bucketStr != "" {
+		var err error
+		if bucket, err = time.ParseDuration(bucketStr); err != nil {
+			response.BadRequest(w, "Invalid bucket duration")
+			return
+		}
+	}
+
+	report, err := h.service.GetPeakVoteRate(r.Context(), pollID, bucket)
+	if err != nil {
+		renderError(w, r, err, "Failed to retrieve vote rate")
+		return
+	}
+
+	response.Success(w, "", report)
+}
@@ -0,0 +1,30 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/moabdelazem/k8s-app/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// requestCanceled reports whether the client went away before the response was written.
+// Handlers check it after their database work and skip the response, which could not be
+// delivered anyway; the disconnect is logged at debug level instead of as a failure.
+// Timeouts are not cancellations: the timeout middleware still answers those with 504.
+func requestCanceled(r *http.Request, handler string) bool {
+	err := r.Context().Err()
+	if !errors.Is(err, context.Canceled) {
+		return false
+	}
+	logger.Debug("Client disconnected, skipping response",
+		zap.String("handler", handler),
+		zap.String("request_id", middleware.GetReqID(r.Context())),
+		zap.String("method", r.Method),
+		zap.String("path", r.URL.Path),
+		zap.Error(err),
+	)
+	return true
+}
@@ -0,0 +1,68 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/moabdelazem/k8s-app/internal/mocks"
+	"github.com/moabdelazem/k8s-app/internal/models"
+	"github.com/moabdelazem/k8s-app/internal/repository"
+	"github.com/moabdelazem/k8s-app/internal/service"
+	"github.com/moabdelazem/k8s-app/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// observeLogs swaps the global logger for an in-memory observer for the test duration
+func observeLogs(t *testing.T) *observer.ObservedLogs {
+	core, logs := observer.New(zapcore.DebugLevel)
+	previous := logger.Log
+	logger.Log = zap.New(core)
+	t.Cleanup(func() { logger.Log = previous })
+	return logs
+}
+
+// canceledRequest returns a request whose client has already gone away
+func canceledRequest(method, target, body string) *http.Request {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	return httptest.NewRequest(method, target, strings.NewReader(body)).WithContext(ctx)
+}
+
+func TestCreatePoll_ClientGoneSkipsResponse(t *testing.T) {
+	logs := observeLogs(t)
+	repo := new(mocks.MockPollRepository)
+	repo.On("CreatePoll", mock.Anything, mock.Anything, mock.Anything).Return(context.Canceled)
+
+	rec := httptest.NewRecorder()
+	newTestPollHandler(repo).CreatePoll(rec, canceledRequest(http.MethodPost, "/api/v1/polls", `{"question":"Ship it on Friday?","options":["Yes","No"]}`))
+
+	assert.Empty(t, rec.Body.String(), "nothing is written to a client that went away")
+	assert.Zero(t, logs.FilterLevelExact(zapcore.ErrorLevel).Len())
+	assert.Equal(t, 1, logs.FilterMessage("Client disconnected, skipping response").Len())
+}
+
+func TestVoteOnPoll_ClientGoneSkipsResponse(t *testing.T) {
+	svc := service.NewPollService(repository.NewInMemoryPollRepository(), service.PollServiceConfig{})
+	poll, _, err := svc.CreatePoll(context.Background(), &models.CreatePollRequest{
+		Question: "Ship it on Friday?",
+		Options:  []string{"Yes", "No"},
+	}, "owner-1")
+	require.NoError(t, err)
+	logs := observeLogs(t)
+
+	req := canceledRequest(http.MethodPost, "/api/v1/polls/"+poll.ID.String()+"/vote", `{"option_id":"`+poll.Options[0].ID.String()+`"}`)
+	rec := httptest.NewRecorder()
+	withPollID(NewPollHandler(svc, nil, nil).VoteOnPoll).ServeHTTP(rec, withURLParam(req, "id", poll.ID.String()))
+
+	assert.Empty(t, rec.Body.String())
+	assert.Zero(t, logs.FilterLevelExact(zapcore.ErrorLevel).Len())
+	assert.Zero(t, logs.FilterLevelExact(zapcore.WarnLevel).Len())
+}
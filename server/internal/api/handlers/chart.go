@@ -0,0 +1,85 @@
+package handlers
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+
+	"github.com/moabdelazem/k8s-app/internal/models"
+)
+
+// svgContentType is the content type of rendered result charts
+const svgContentType = "image/svg+xml"
+
+// Result chart layout, in pixels
+const (
+	chartWidth       = 600
+	chartPadding     = 16
+	chartTitleHeight = 32
+	chartRowHeight   = 32
+	chartBarHeight   = 20
+	chartLabelWidth  = 200
+	chartBarMaxWidth = 300
+	chartMaxLabel    = 28 // Longer option texts are truncated to this many characters
+	chartMaxTitle    = 70 // Likewise for the question
+)
+
+// GetPollResultsChart renders a poll's results as a horizontal bar chart SVG
+// so results can be embedded in emails and static pages
+func (h *PollHandler) GetPollResultsChart(w http.ResponseWriter, r *http.Request) {
+	pollID := PollIDFromContext(r.Context())
+
+	results, err := h.service.GetPollResults(r.Context(), pollID, "")
+	if err != nil {
+		renderError(w, r, err, "Failed to retrieve poll results")
+		return
+	}
+
+	w.Header().Set("Content-Type", svgContentType)
+	w.WriteHeader(http.StatusOK)
+	writePollResultsChart(w, results)
+}
+
+// writePollResultsChart writes one labeled bar per option, scaled to the option's percentage
+// All user-provided text is escaped, so option texts cannot inject markup into the SVG
+func writePollResultsChart(w io.Writer, results *models.PollResults) {
+	height := chartHeight(len(results.Options))
+	barX := chartPadding + chartLabelWidth
+
+	fmt.Fprintf(w, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" font-family="sans-serif" font-size="14">`+"\n",
+		chartWidth, height, chartWidth, height)
+	fmt.Fprintf(w, `<title>%s</title>`+"\n", html.EscapeString(results.Question))
+	fmt.Fprintf(w, `<rect width="%d" height="%d" fill="#ffffff"/>`+"\n", chartWidth, height)
+	fmt.Fprintf(w, `<text x="%d" y="%d" font-size="16" font-weight="bold">%s</text>`+"\n",
+		chartPadding, chartPadding+chartTitleHeight/2, html.EscapeString(truncateLabel(results.Question, chartMaxTitle)))
+
+	for i, opt := range results.Options {
+		y := chartPadding + chartTitleHeight + i*chartRowHeight
+		textY := y + chartBarHeight/2 + 5
+		barWidth := opt.Percentage / 100 * chartBarMaxWidth
+
+		fmt.Fprintf(w, `<text x="%d" y="%d">%s</text>`+"\n",
+			chartPadding, textY, html.EscapeString(truncateLabel(opt.OptionText, chartMaxLabel)))
+		fmt.Fprintf(w, `<rect class="bar" x="%d" y="%d" width="%.1f" height="%d" fill="#4f81bd"/>`+"\n",
+			barX, y, barWidth, chartBarHeight)
+		fmt.Fprintf(w, `<text x="%.1f" y="%d">%.1f%%</text>`+"\n",
+			float64(barX)+barWidth+6, textY, opt.Percentage)
+	}
+
+	fmt.Fprintln(w, `</svg>`)
+}
+
+// chartHeight is the height of the results chart of a poll with the given number of options
+func chartHeight(options int) int {
+	return 2*chartPadding + chartTitleHeight + options*chartRowHeight
+}
+
+// truncateLabel shortens text to at most limit characters, marking the cut with an ellipsis
+func truncateLabel(text string, limit int) string {
+	runes := []rune(text)
+	if len(runes) <= limit {
+		return text
+	}
+	return string(runes[:limit-1]) + "…"
+}
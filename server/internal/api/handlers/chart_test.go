@@ -0,0 +1,91 @@
+package handlers
+
+import (
+	"encoding/xml"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/moabdelazem/k8s-app/internal/mocks"
+	"github.com/moabdelazem/k8s-app/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetPollResultsChart(t *testing.T) {
+	pollID := uuid.New()
+
+	repo := new(mocks.MockPollRepository)
+	repo.On("GetPollWithResults", mock.Anything, pollID, "").Return(withOptions(
+		&models.Poll{ID: pollID, Question: "Best <editor>?", IsActive: true, TotalVotes: 8},
+		models.PollOption{ID: uuid.New(), PollID: pollID, OptionText: "Vim", VoteCount: 6},
+		models.PollOption{ID: uuid.New(), PollID: pollID, OptionText: "Emacs & <script>alert(1)</script>", VoteCount: 1},
+		models.PollOption{ID: uuid.New(), PollID: pollID, OptionText: "Nano", VoteCount: 1},
+	), nil)
+
+	req := withURLParam(httptest.NewRequest(http.MethodGet, "/api/v1/polls/"+pollID.String()+"/chart.svg", nil), "id", pollID.String())
+	rec := httptest.NewRecorder()
+
+	withPollID(newTestPollHandler(repo).GetPollResultsChart).ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "image/svg+xml", rec.Header().Get("Content-Type"))
+
+	svg := rec.Body.String()
+	assert.Equal(t, 3, strings.Count(svg, `class="bar"`), "one bar per option")
+	assert.Contains(t, svg, `width="225.0"`, "bar scaled to 75% of the maximum width")
+	assert.Contains(t, svg, ">75.0%<")
+	assert.Equal(t, 2, strings.Count(svg, ">12.5%<"))
+
+	// Option and question text is escaped, so the document stays well-formed
+	assert.NotContains(t, svg, "<script>")
+	assert.Contains(t, svg, "Emacs &amp; &lt;script&gt;")
+	assert.Contains(t, svg, "Best &lt;editor&gt;?")
+	decoder := xml.NewDecoder(strings.NewReader(svg))
+	for {
+		_, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		require.NoError(t, err)
+	}
+}
+
+func TestGetPollResultsChart_NoVotes(t *testing.T) {
+	pollID := uuid.New()
+
+	repo := new(mocks.MockPollRepository)
+	repo.On("GetPollWithResults", mock.Anything, pollID, "").Return(withOptions(
+		&models.Poll{ID: pollID, Question: "Lunch?", IsActive: true},
+		models.PollOption{ID: uuid.New(), PollID: pollID, OptionText: "Pizza"},
+		models.PollOption{ID: uuid.New(), PollID: pollID, OptionText: "Sushi"},
+	), nil)
+
+	req := withURLParam(httptest.NewRequest(http.MethodGet, "/api/v1/polls/"+pollID.String()+"/chart.svg", nil), "id", pollID.String())
+	rec := httptest.NewRecorder()
+
+	withPollID(newTestPollHandler(repo).GetPollResultsChart).ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, 2, strings.Count(rec.Body.String(), `width="0.0"`))
+	assert.Equal(t, 2, strings.Count(rec.Body.String(), ">0.0%<"))
+}
+
+func TestGetPollResultsChart_NotFound(t *testing.T) {
+	repo := new(mocks.MockPollRepository)
+	pollID := uuid.New()
+	repo.On("GetPollWithResults", mock.Anything, pollID, "").Return(nil, nil)
+	repo.On("GetArchivedPollWithResults", mock.Anything, pollID, "").Return(nil, nil)
+
+	req := withURLParam(httptest.NewRequest(http.MethodGet, "/api/v1/polls/"+pollID.String()+"/chart.svg", nil), "id", pollID.String())
+	rec := httptest.NewRecorder()
+
+	withPollID(newTestPollHandler(repo).GetPollResultsChart).ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
@@ -0,0 +1,24 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/moabdelazem/k8s-app/internal/config"
+	"github.com/moabdelazem/k8s-app/pkg/response"
+)
+
+// ConfigHandler exposes the configuration the server booted with
+type ConfigHandler struct {
+	cfg config.Config
+}
+
+// NewConfigHandler captures a sanitized copy of cfg so secrets never reach a response
+func NewConfigHandler(cfg *config.Config) *ConfigHandler {
+	return &ConfigHandler{cfg: cfg.Sanitize()}
+}
+
+// GetConfig returns the boot-time configuration with secrets redacted
+// Runtime toggles such as read-only mode are reported by their own endpoints
+func (h *ConfigHandler) GetConfig(w http.ResponseWriter, r *http.Request) {
+	response.Success(w, "", h.cfg)
+}
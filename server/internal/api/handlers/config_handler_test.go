@@ -0,0 +1,31 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/moabdelazem/k8s-app/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetConfig_RedactsSecrets(t *testing.T) {
+	cfg := &config.Config{
+		Env:   "production",
+		DB:    config.DBConfig{Host: "postgres", Password: "hunter2"},
+		Admin: config.AdminConfig{APIKey: "admin-key"},
+		Auth:  config.AuthConfig{JWTSecret: "jwt-secret"},
+	}
+
+	rec := httptest.NewRecorder()
+	NewConfigHandler(cfg).GetConfig(rec, httptest.NewRequest(http.MethodGet, "/api/v1/admin/config", nil))
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	body := rec.Body.String()
+	assert.Contains(t, body, `"password":"[REDACTED]"`)
+	assert.Contains(t, body, `"host":"postgres"`)
+	for _, secret := range []string{"hunter2", "admin-key", "jwt-secret"} {
+		assert.NotContains(t, body, secret)
+	}
+}
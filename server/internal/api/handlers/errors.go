@@ -0,0 +1,76 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/moabdelazem/k8s-app/internal/service"
+	"github.com/moabdelazem/k8s-app/pkg/i18n"
+	"github.com/moabdelazem/k8s-app/pkg/logger"
+	"github.com/moabdelazem/k8s-app/pkg/response"
+	"go.uber.org/zap"
+)
+
+// transientRetryAfter is the back-off suggested to clients on transient database failures
+const transientRetryAfter = 2 * time.Second
+
+// renderError maps a service error to an HTTP response.
+// Domain and validation errors keep their user-facing message in every environment,
+// transient database failures become a retryable 503, and anything else is an internal error rendered with fallback as the public message.
+// Coded messages are localized to the language negotiated from Accept-Language.
+func renderError(w http.ResponseWriter, r *http.Request, err error, fallback string) {
+	var validationErr *service.ValidationError
+
+	lang := i18n.Negotiate(r.Header.Get("Accept-Language"))
+
+	switch {
+	case errors.Is(err, service.ErrPollNotFound):
+		response.NotFound(w, localize(w, lang, service.CodePollNotFound, err.Error()))
+	case errors.Is(err, service.ErrPollDeleted):
+		response.Error(w, http.StatusGone, localize(w, lang, service.CodePollDeleted, err.Error()))
+	case errors.Is(err, service.ErrPollDeleteProtected):
+		response.Forbidden(w, localize(w, lang, service.CodePollDeleteProtected, err.Error()))
+	case errors.Is(err, service.ErrVoteNotFound):
+		response.NotFound(w, localize(w, lang, service.CodeVoteNotFound, err.Error()))
+	case errors.Is(err, service.ErrWebhookNotFound):
+		response.NotFound(w, localize(w, lang, service.CodeWebhookNotFound, err.Error()))
+	case errors.Is(err, service.ErrTemplateNotFound):
+		response.NotFound(w, localize(w, lang, service.CodeTemplateNotFound, err.Error()))
+	case errors.Is(err, service.ErrAllowedVoterNotFound):
+		response.NotFound(w, localize(w, lang, service.CodeAllowedVoterNotFound, err.Error()))
+	case errors.Is(err, service.ErrActivePollLimitReached):
+		response.Error(w, http.StatusTooManyRequests, localize(w, lang, service.CodeActivePollLimitReached, err.Error()))
+	case errors.Is(err, service.ErrVoterNetworkBlocked):
+		response.Forbidden(w, localize(w, lang, service.CodeVoterNetworkBlocked, err.Error()))
+	case errors.Is(err, service.ErrVoterNotAllowed):
+		response.Forbidden(w, localize(w, lang, service.CodeVoterNotAllowed, err.Error()))
+	case errors.Is(err, service.ErrRestoreTargetNotEmpty):
+		response.Error(w, http.StatusConflict, localize(w, lang, service.CodeRestoreTargetNotEmpty, err.Error()))
+	case errors.As(err, &validationErr):
+		response.BadRequest(w, localize(w, lang, validationErr.Code, validationErr.Message, validationErr.Args...))
+	case errors.Is(err, service.ErrTemporarilyUnavailable):
+		logger.Warn("Transient database error",
+			zap.Error(err),
+			zap.String("method", r.Method),
+			zap.String("path", r.URL.Path),
+		)
+		response.ServiceUnavailable(w, localize(w, lang, service.CodeTemporarilyUnavailable, "Service temporarily unavailable, please retry"), transientRetryAfter)
+	default:
+		response.InternalError(w, r, fallback, err)
+	}
+}
+
+// localize renders the catalog message for code in lang, or fallback when there is none
+// Content-Language is only set when the catalog message is used
+func localize(w http.ResponseWriter, lang, code, fallback string, args ...any) string {
+	if code == "" {
+		return fallback
+	}
+	msg, ok := i18n.Message(lang, code, args...)
+	if !ok {
+		return fallback
+	}
+	w.Header().Set("Content-Language", lang)
+	return msg
+}
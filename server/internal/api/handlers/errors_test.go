@@ -0,0 +1,90 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/moabdelazem/k8s-app/internal/service"
+	"github.com/moabdelazem/k8s-app/pkg/i18n"
+	"github.com/stretchr/testify/assert"
+)
+
+// serviceErrorCodes lists every code the service layer can return
+var serviceErrorCodes = []string{
+	service.CodePollNotFound,
+	service.CodePollDeleted,
+	service.CodePollDeleteProtected,
+	service.CodeVoteNotFound,
+	service.CodeWebhookNotFound,
+	service.CodeTemplateNotFound,
+	service.CodeActivePollLimitReached,
+	service.CodeVoterNetworkBlocked,
+	service.CodeVoterNotAllowed,
+	service.CodeAllowedVoterNotFound,
+	service.CodeRestoreTargetNotEmpty,
+	service.CodeTemporarilyUnavailable,
+	service.CodeQuestionLength,
+	service.CodeTooFewOptions,
+	service.CodeTooManyOptions,
+	service.CodeOptionLength,
+	service.CodeExpiryNotInFuture,
+	service.CodeExpiryBeyondHorizon,
+	service.CodeGroupLength,
+	service.CodeQuizNeedsCorrectOption,
+	service.CodeCorrectOptionsWithoutQuiz,
+	service.CodeInvalidCorrectOption,
+	service.CodeOptionCountOutOfBounds,
+	service.CodeOptionsLocked,
+	service.CodeOptionSetMismatch,
+	service.CodeInvalidOptionUpdate,
+	service.CodeInvalidBucket,
+	service.CodeConfirmationRequired,
+	service.CodeConfirmationInvalid,
+	service.CodePollInactive,
+	service.CodePollExpired,
+	service.CodeAlreadyVoted,
+	service.CodeAlreadyVotedInGroup,
+	service.CodeInvalidOption,
+	service.CodeWeightedVotingDisabled,
+	service.CodeVoteWeightOutOfRange,
+	service.CodeBatchIDsRequired,
+	service.CodeBatchTooManyIDs,
+	service.CodeCampaignLength,
+	service.CodeShareLinkInvalid,
+	service.CodeReceiptInvalid,
+	service.CodeWriteInDisabled,
+	service.CodeWriteInLength,
+	service.CodeWriteInWithQuiz,
+	service.CodeMaxVotesInvalid,
+	service.CodePollFull,
+	service.CodeVotingWindowInvalid,
+	service.CodeTimezoneInvalid,
+	service.CodeOutsideVotingWindow,
+	service.CodeAcknowledgementOptionCount,
+	service.CodeAcknowledgementConflict,
+	service.CodeTemplateNameLength,
+	service.CodeMetadataInvalid,
+	service.CodeMetadataTooLarge,
+}
+
+func TestErrorCodesHaveTranslations(t *testing.T) {
+	for _, lang := range []string{"en", "ar"} {
+		for _, code := range serviceErrorCodes {
+			_, ok := i18n.Message(lang, code)
+			assert.True(t, ok, "%s catalog is missing %s", lang, code)
+		}
+	}
+}
+
+func TestRenderError_UncodedValidationKeepsMessage(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/", nil)
+	req.Header.Set("Accept-Language", "ar")
+	rec := httptest.NewRecorder()
+
+	renderError(rec, req, &service.ValidationError{Message: "line 3: invalid option_id"}, "Failed")
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Empty(t, rec.Header().Get("Content-Language"))
+	assert.Equal(t, "line 3: invalid option_id", decodeResponse(t, rec).Error)
+}
@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"fmt"
 	"net/http"
 	"runtime"
 	"time"
@@ -107,6 +108,26 @@ func ReadinessProbe(w http.ResponseWriter, r *http.Request) {
 	} else {
 		checks["database"] = "healthy"
 
+		// A new release must not serve traffic before the schema it depends on is applied
+		versions, err := database.MigrationStatus(r.Context())
+		switch {
+		case err != nil:
+			checks["migrations"] = "unknown"
+			isReady = false
+			logger.Error("Migration status check failed",
+				zap.Error(err),
+			)
+		case versions.Behind():
+			checks["migrations"] = fmt.Sprintf("behind (version %d, expected %d)", versions.Current, versions.Latest)
+			isReady = false
+			logger.Warn("Database schema is behind the application",
+				zap.Int("current_version", versions.Current),
+				zap.Int("latest_version", versions.Latest),
+			)
+		default:
+			checks["migrations"] = "up to date"
+		}
+
 		// Add connection pool stats
 		stats := database.Stats()
 		logger.Debug("Database connection pool stats",
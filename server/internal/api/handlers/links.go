@@ -0,0 +1,49 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/moabdelazem/k8s-app/internal/models"
+	"github.com/moabdelazem/k8s-app/pkg/response"
+)
+
+// apiPrefix is the path prefix of the versioned API; whatever precedes it in a request path is the base path
+const apiPrefix = "/api/v1/"
+
+// pollLinks returns the links of a poll: itself, voting on it, its results and deleting it
+// Hrefs are absolute paths under the base path the request came in on, so they follow BASE_PATH.
+// Results are part of the poll itself, so results and self share a URL.
+func pollLinks(r *http.Request, pollID uuid.UUID) func() map[string]response.Link {
+	return func() map[string]response.Link {
+		base := ""
+		if i := strings.Index(r.URL.Path, apiPrefix); i >= 0 {
+			base = r.URL.Path[:i]
+		}
+		poll := base + apiPrefix + "polls/" + pollID.String()
+
+		return map[string]response.Link{
+			"self":    {Href: poll, Method: http.MethodGet},
+			"vote":    {Href: poll + "/vote", Method: http.MethodPost},
+			"results": {Href: poll, Method: http.MethodGet},
+			"delete":  {Href: poll, Method: http.MethodDelete},
+		}
+	}
+}
+
+// linkPolls adds each poll's links to a list of polls, when the response asks for links
+func linkPolls(w http.ResponseWriter, r *http.Request, polls []models.PollWithOptions) (any, error) {
+	if !response.WantsLinks(w) {
+		return polls, nil
+	}
+
+	linked := make([]any, len(polls))
+	for i, poll := range polls {
+		var err error
+		if linked[i], err = response.AddLinks(w, poll, pollLinks(r, poll.ID)); err != nil {
+			return nil, err
+		}
+	}
+	return linked, nil
+}
@@ -0,0 +1,108 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/moabdelazem/k8s-app/internal/live"
+	"github.com/moabdelazem/k8s-app/internal/service"
+	"github.com/moabdelazem/k8s-app/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// liveKeepAliveInterval is how often an idle results stream sends a comment, so proxies
+// and load balancers do not close it
+const liveKeepAliveInterval = 15 * time.Second
+
+// LiveHandler streams poll results to clients as Server-Sent Events
+type LiveHandler struct {
+	service   *service.PollService
+	hub       *live.Hub
+	keepAlive time.Duration
+}
+
+// NewLiveHandler creates a LiveHandler pushing results whenever hub reports a change;
+// hub must be the PollService's LiveResults notifier
+func NewLiveHandler(service *service.PollService, hub *live.Hub) *LiveHandler {
+	return &LiveHandler{service: service, hub: hub, keepAlive: liveKeepAliveInterval}
+}
+
+// StreamResults sends the poll's results as a "results" event, then again after every vote,
+// until the client disconnects. Idle streams get a keep-alive comment every keepAlive.
+func (h *LiveHandler) StreamResults(w http.ResponseWriter, r *http.Request) {
+	pollID := PollIDFromContext(r.Context())
+
+	// Subscribe before the first read so no vote falls between it and the stream
+	changes, cancel := h.hub.Subscribe(pollID)
+	defer cancel()
+
+	// Unknown polls still get a regular error response, before the stream starts
+	event, err := h.resultsEvent(r.Context(), pollID)
+	if err != nil {
+		renderError(w, r, err, "Failed to retrieve poll results")
+		return
+	}
+
+	rc := http.NewResponseController(w)
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no") // Stop nginx from buffering the stream
+	w.WriteHeader(http.StatusOK)
+
+	send := func(chunk []byte) bool {
+		if _, err := w.Write(chunk); err != nil {
+			return false
+		}
+		return rc.Flush() == nil
+	}
+
+	if !send(event) {
+		return
+	}
+
+	ticker := time.NewTicker(h.keepAlive)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-ticker.C:
+			if !send([]byte(": keep-alive\n\n")) {
+				return
+			}
+		case <-changes:
+			event, err := h.resultsEvent(r.Context(), pollID)
+			if err != nil {
+				// Keep the stream open; the next vote or a reconnect retries the read
+				logger.Warn("Failed to refresh live results",
+					zap.Error(err),
+					zap.String("poll_id", pollID.String()),
+				)
+				continue
+			}
+			if !send(event) {
+				return
+			}
+			ticker.Reset(h.keepAlive)
+		}
+	}
+}
+
+// resultsEvent renders the poll's anonymous results as a "results" event
+func (h *LiveHandler) resultsEvent(ctx context.Context, pollID uuid.UUID) ([]byte, error) {
+	results, err := h.service.GetPollResults(ctx, pollID, "")
+	if err != nil {
+		return nil, err
+	}
+	data, err := json.Marshal(results)
+	if err != nil {
+		return nil, err
+	}
+	return fmt.Appendf(nil, "event: results\ndata: %s\n\n", data), nil
+}
@@ -0,0 +1,157 @@
+package handlers
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/moabdelazem/k8s-app/internal/live"
+	"github.com/moabdelazem/k8s-app/internal/mocks"
+	"github.com/moabdelazem/k8s-app/internal/models"
+	"github.com/moabdelazem/k8s-app/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// sseEvent is one Server-Sent Event, or a comment when Comment is set
+type sseEvent struct {
+	Event   string
+	Data    string
+	Comment string
+}
+
+// readSSEEvent reads the next blank-line terminated event from r
+func readSSEEvent(t *testing.T, r *bufio.Reader) sseEvent {
+	t.Helper()
+	var event sseEvent
+	for {
+		line, err := r.ReadString('\n')
+		require.NoError(t, err)
+		line = strings.TrimSuffix(line, "\n")
+		switch {
+		case line == "":
+			return event
+		case strings.HasPrefix(line, ":"):
+			event.Comment = strings.TrimSpace(line[1:])
+		case strings.HasPrefix(line, "event: "):
+			event.Event = strings.TrimPrefix(line, "event: ")
+		case strings.HasPrefix(line, "data: "):
+			event.Data = strings.TrimPrefix(line, "data: ")
+		}
+	}
+}
+
+// newLiveTestServer serves StreamResults for a poll backed by repo
+func newLiveTestServer(t *testing.T, repo *mocks.MockPollRepository, keepAlive time.Duration) (*httptest.Server, *service.PollService, *live.Hub) {
+	t.Helper()
+	hub := live.NewHub()
+	svc := service.NewPollService(repo, service.PollServiceConfig{LiveResults: hub})
+	handler := NewLiveHandler(svc, hub)
+	handler.keepAlive = keepAlive
+
+	r := chi.NewRouter()
+	r.With(PollIDMiddleware).Get("/api/v1/polls/{id}/results/stream", handler.StreamResults)
+	server := httptest.NewServer(r)
+	t.Cleanup(server.Close)
+	return server, svc, hub
+}
+
+// openStream connects to the poll's results stream; canceling ctx disconnects
+func openStream(t *testing.T, ctx context.Context, server *httptest.Server, pollID uuid.UUID) (*http.Response, *bufio.Reader) {
+	t.Helper()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/api/v1/polls/"+pollID.String()+"/results/stream", nil)
+	require.NoError(t, err)
+	resp, err := server.Client().Do(req)
+	require.NoError(t, err)
+	t.Cleanup(func() { resp.Body.Close() })
+	return resp, bufio.NewReader(resp.Body)
+}
+
+func TestStreamResults_PushesCastVotes(t *testing.T) {
+	pollID := uuid.New()
+	yes, no := uuid.New(), uuid.New()
+	poll := withOptions(
+		&models.Poll{ID: pollID, Question: "Ship it?", IsActive: true},
+		models.PollOption{ID: yes, PollID: pollID, OptionText: "Yes"},
+		models.PollOption{ID: no, PollID: pollID, OptionText: "No"},
+	)
+
+	repo := new(mocks.MockPollRepository)
+	repo.On("GetPollWithResults", mock.Anything, pollID, "").Return(poll, nil)
+	repo.On("GetPollByID", mock.Anything, pollID).Return(&poll.Poll, nil)
+	repo.On("GetPollOptions", mock.Anything, pollID).Return(poll.Options, nil)
+	repo.On("HasVoted", mock.Anything, pollID, "voter-1").Return(false, nil, nil)
+	repo.On("CastVote", mock.Anything, mock.Anything).Return(nil).Run(func(mock.Arguments) {
+		// Mirror the database update so the next read sees the vote
+		poll.Options[0].VoteCount++
+		poll.TotalVotes++
+	})
+
+	server, svc, _ := newLiveTestServer(t, repo, time.Minute)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	resp, stream := openStream(t, ctx, server, pollID)
+
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+	assert.Equal(t, "no-cache", resp.Header.Get("Cache-Control"))
+
+	// The current results are sent on connect
+	initial := readSSEEvent(t, stream)
+	assert.Equal(t, "results", initial.Event)
+	var results models.PollResults
+	require.NoError(t, json.Unmarshal([]byte(initial.Data), &results))
+	assert.Zero(t, results.TotalVotes)
+
+	_, _, err := svc.CastVote(context.Background(), pollID, yes, "voter-1", 0, "")
+	require.NoError(t, err)
+
+	update := readSSEEvent(t, stream)
+	assert.Equal(t, "results", update.Event)
+	require.NoError(t, json.Unmarshal([]byte(update.Data), &results))
+	assert.Equal(t, int64(1), results.TotalVotes)
+	assert.Equal(t, int64(1), results.Options[0].VoteCount)
+	assert.Equal(t, 100.0, results.Options[0].Percentage)
+}
+
+func TestStreamResults_KeepAliveAndDisconnect(t *testing.T) {
+	pollID := uuid.New()
+
+	repo := new(mocks.MockPollRepository)
+	repo.On("GetPollWithResults", mock.Anything, pollID, "").Return(withOptions(&models.Poll{ID: pollID, IsActive: true}), nil)
+
+	server, _, hub := newLiveTestServer(t, repo, 10*time.Millisecond)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	_, stream := openStream(t, ctx, server, pollID)
+
+	assert.Equal(t, "results", readSSEEvent(t, stream).Event)
+	assert.Equal(t, "keep-alive", readSSEEvent(t, stream).Comment)
+	assert.Equal(t, 1, hub.Subscribers(pollID))
+
+	// Disconnecting ends the handler and drops its subscription
+	cancel()
+	assert.Eventually(t, func() bool { return hub.Subscribers(pollID) == 0 }, time.Second, 5*time.Millisecond)
+}
+
+func TestStreamResults_NotFound(t *testing.T) {
+	pollID := uuid.New()
+
+	repo := new(mocks.MockPollRepository)
+	repo.On("GetPollWithResults", mock.Anything, pollID, "").Return(nil, nil)
+	repo.On("GetArchivedPollWithResults", mock.Anything, pollID, "").Return(nil, nil)
+
+	server, _, hub := newLiveTestServer(t, repo, time.Minute)
+	resp, _ := openStream(t, context.Background(), server, pollID)
+
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	assert.Equal(t, "application/json", resp.Header.Get("Content-Type"))
+	assert.Equal(t, 0, hub.Subscribers(pollID))
+}
@@ -0,0 +1,41 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/moabdelazem/k8s-app/internal/maintenance"
+	"github.com/moabdelazem/k8s-app/internal/models"
+	"github.com/moabdelazem/k8s-app/pkg/response"
+)
+
+// MaintenanceHandler serves the runtime maintenance toggles
+type MaintenanceHandler struct {
+	mode *maintenance.Mode
+}
+
+func NewMaintenanceHandler(mode *maintenance.Mode) *MaintenanceHandler {
+	return &MaintenanceHandler{mode: mode}
+}
+
+// GetReadOnly reports whether read-only mode is on
+func (h *MaintenanceHandler) GetReadOnly(w http.ResponseWriter, r *http.Request) {
+	response.Success(w, "", map[string]bool{
+		"read_only": h.mode.ReadOnly(),
+	})
+}
+
+// SetReadOnly turns read-only mode on or off
+func (h *MaintenanceHandler) SetReadOnly(w http.ResponseWriter, r *http.Request) {
+	var req models.ReadOnlyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Enabled == nil {
+		response.BadRequest(w, `Request body must be {"enabled": true|false}`)
+		return
+	}
+
+	h.mode.SetReadOnly(*req.Enabled)
+
+	response.Success(w, "Read-only mode updated", map[string]bool{
+		"read_only": h.mode.ReadOnly(),
+	})
+}
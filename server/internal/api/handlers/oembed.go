@@ -0,0 +1,79 @@
+package handlers
+
+import (
+	"fmt"
+	"html"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/moabdelazem/k8s-app/internal/models"
+	"github.com/moabdelazem/k8s-app/pkg/response"
+)
+
+// oEmbedProviderName is the provider_name of oEmbed responses
+const oEmbedProviderName = "Polls"
+
+// OEmbed answers an oEmbed request for the poll a URL points at, such as the poll's API URL
+// The html is an iframe of the poll's results chart, served from the origin and base path of the URL.
+// Only the json format is supported; other formats get 501, as the oEmbed spec requires.
+func (h *PollHandler) OEmbed(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	if format := query.Get("format"); format != "" && format != "json" {
+		response.Error(w, http.StatusNotImplemented, "Only the json format is supported")
+		return
+	}
+
+	rawURL := query.Get("url")
+	if rawURL == "" {
+		response.BadRequest(w, "url is required")
+		return
+	}
+	chartURL, pollID, ok := pollChartURL(rawURL)
+	if !ok {
+		response.NotFound(w, "URL does not point at a poll")
+		return
+	}
+
+	results, err := h.service.GetPollResults(r.Context(), pollID, "")
+	if err != nil {
+		renderError(w, r, err, "Failed to retrieve poll results")
+		return
+	}
+
+	height := chartHeight(len(results.Options))
+	response.JSON(w, http.StatusOK, models.OEmbed{
+		Version:      "1.0",
+		Type:         "rich",
+		Title:        results.Question,
+		ProviderName: oEmbedProviderName,
+		HTML: fmt.Sprintf(`<iframe src="%s" width="%d" height="%d" title="%s" frameborder="0" loading="lazy"></iframe>`,
+			html.EscapeString(chartURL), chartWidth, height, html.EscapeString(results.Question)),
+		Width:  chartWidth,
+		Height: height,
+	})
+}
+
+// pollChartURL parses the poll ID out of an http(s) URL whose path has a polls/{id} pair of segments,
+// such as https://example.com/api/v1/polls/{id}, and returns the URL of the poll's results chart
+func pollChartURL(rawURL string) (string, uuid.UUID, bool) {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return "", uuid.Nil, false
+	}
+
+	segments := strings.Split(strings.Trim(u.Path, "/"), "/")
+	for i := 0; i+1 < len(segments); i++ {
+		if segments[i] != "polls" {
+			continue
+		}
+		pollID, err := uuid.Parse(segments[i+1])
+		if err != nil {
+			continue
+		}
+		chart := url.URL{Scheme: u.Scheme, Host: u.Host, Path: "/" + strings.Join(segments[:i+2], "/") + "/chart.svg"}
+		return chart.String(), pollID, true
+	}
+	return "", uuid.Nil, false
+}
@@ -0,0 +1,96 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/moabdelazem/k8s-app/internal/mocks"
+	"github.com/moabdelazem/k8s-app/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// oEmbedRequest asks the oEmbed endpoint about pollURL
+func oEmbedRequest(pollURL string) *http.Request {
+	return httptest.NewRequest(http.MethodGet, "/oembed?url="+url.QueryEscape(pollURL), nil)
+}
+
+func TestOEmbed(t *testing.T) {
+	pollID := uuid.New()
+
+	repo := new(mocks.MockPollRepository)
+	repo.On("GetPollWithResults", mock.Anything, pollID, "").Return(withOptions(
+		&models.Poll{ID: pollID, Question: `Best "editor"?`, IsActive: true, TotalVotes: 3},
+		models.PollOption{ID: uuid.New(), PollID: pollID, OptionText: "Vim", VoteCount: 2},
+		models.PollOption{ID: uuid.New(), PollID: pollID, OptionText: "Emacs", VoteCount: 1},
+	), nil)
+
+	rec := httptest.NewRecorder()
+	newTestPollHandler(repo).OEmbed(rec, oEmbedRequest("https://polls.example.com/svc/api/v1/polls/"+pollID.String()+"?lang=en"))
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+
+	// oEmbed responses are bare JSON objects, without the API's response envelope
+	var body map[string]any
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	assert.ElementsMatch(t, []string{"version", "type", "title", "provider_name", "html", "width", "height"}, keys(body))
+	assert.Equal(t, "1.0", body["version"])
+	assert.Equal(t, "rich", body["type"])
+	assert.Equal(t, `Best "editor"?`, body["title"])
+	assert.Equal(t, float64(chartWidth), body["width"])
+	assert.Equal(t, float64(chartHeight(2)), body["height"])
+	assert.Equal(t,
+		`<iframe src="https://polls.example.com/svc/api/v1/polls/`+pollID.String()+`/chart.svg" width="600" height="128" title="Best &#34;editor&#34;?" frameborder="0" loading="lazy"></iframe>`,
+		body["html"])
+}
+
+func TestOEmbed_NotAPoll(t *testing.T) {
+	unknown := uuid.New()
+	repo := new(mocks.MockPollRepository)
+	repo.On("GetPollWithResults", mock.Anything, unknown, "").Return(nil, nil)
+	repo.On("GetArchivedPollWithResults", mock.Anything, unknown, "").Return(nil, nil)
+
+	tests := []struct {
+		name   string
+		url    string
+		status int
+	}{
+		{name: "unknown poll", url: "https://polls.example.com/api/v1/polls/" + unknown.String(), status: http.StatusNotFound},
+		{name: "no poll ID", url: "https://polls.example.com/api/v1/polls", status: http.StatusNotFound},
+		{name: "invalid poll ID", url: "https://polls.example.com/api/v1/polls/42", status: http.StatusNotFound},
+		{name: "not http", url: "javascript:alert(1)//polls/" + unknown.String(), status: http.StatusNotFound},
+		{name: "missing url", url: "", status: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			newTestPollHandler(repo).OEmbed(rec, oEmbedRequest(tt.url))
+			assert.Equal(t, tt.status, rec.Code)
+		})
+	}
+}
+
+func TestOEmbed_XMLFormat(t *testing.T) {
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/oembed?format=xml&url="+url.QueryEscape("https://polls.example.com/api/v1/polls/"+uuid.NewString()), nil)
+
+	newTestPollHandler(new(mocks.MockPollRepository)).OEmbed(rec, req)
+
+	assert.Equal(t, http.StatusNotImplemented, rec.Code)
+}
+
+// keys returns the keys of a decoded JSON object
+func keys(object map[string]any) []string {
+	var out []string
+	for key := range object {
+		out = append(out, key)
+	}
+	return out
+}
@@ -2,38 +2,77 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
+	"io"
 	"net/http"
+	"net/netip"
+	"slices"
 	"strconv"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
 	"github.com/moabdelazem/k8s-app/internal/models"
+	"github.com/moabdelazem/k8s-app/internal/netblock"
 	"github.com/moabdelazem/k8s-app/internal/service"
 	"github.com/moabdelazem/k8s-app/pkg/logger"
 	"github.com/moabdelazem/k8s-app/pkg/response"
 	"go.uber.org/zap"
 )
 
+// pollFields are the top-level fields of a listed poll that ?fields= may select
+var pollFields = []string{
+	"id", "question", "description", "created_at", "expires_at", "is_active", "total_votes",
+	"allow_weighted", "require_confirmation", "quiz_mode", "group", "randomize_options", "allowlist_only", "allow_write_in", "max_votes", "voting_window_start", "voting_window_end", "timezone", "acknowledgement_mode", "featured", "featured_rank", "metadata", "options",
+}
+
+// pollResultFields extends pollFields with the caller's vote status returned by GetPoll
+var pollResultFields = append(slices.Clone(pollFields), "has_voted", "voted_option", "answered_correctly", "leading", "winning_option", "write_ins", "acknowledgements")
+
 type PollHandler struct {
-	service *service.PollService
+	service         *service.PollService
+	voters          voterIdentity
+	blockedNetworks *netblock.List // Votes from these ranges are rejected; nil = accept all
 }
 
-func NewPollHandler(service *service.PollService) *PollHandler {
-	return &PollHandler{service: service}
+// NewPollHandler creates a PollHandler deduplicating anonymous voters by dedupFactors
+// (see config.VoterFactorIP and friends); nil means the client IP alone.
+// Votes from addresses in blockedNetworks are rejected; nil disables the check.
+func NewPollHandler(service *service.PollService, dedupFactors []string, blockedNetworks *netblock.List) *PollHandler {
+	return &PollHandler{service: service, voters: newVoterIdentity(dedupFactors), blockedNetworks: blockedNetworks}
 }
 
-// getVoterIdentifier generates a voter identifier from request
-// In production, this could be user ID from auth token
-// For now, we use IP address as identifier
-func (h *PollHandler) getVoterIdentifier(r *http.Request) string {
-	// Try to get real IP from headers (for load balancer/proxy scenarios)
-	if forwarded := r.Header.Get("X-Forwarded-For"); forwarded != "" {
-		return forwarded
+// checkVoterNetwork rejects votes whose client address falls in a blocked range
+// Addresses that cannot be parsed are rejected too, as they cannot be shown to be outside one
+func (h *PollHandler) checkVoterNetwork(r *http.Request) error {
+	if h.blockedNetworks == nil {
+		return nil
+	}
+	addr, err := netip.ParseAddr(ClientAddr(r))
+	if err != nil {
+		logger.Warn("Rejected vote from unparseable client address", zap.String("client_addr", ClientAddr(r)))
+		return service.ErrVoterNetworkBlocked
 	}
-	if realIP := r.Header.Get("X-Real-IP"); realIP != "" {
-		return realIP
+	if !h.blockedNetworks.Contains(addr) {
+		return nil
 	}
-	return r.RemoteAddr
+	logger.Warn("Rejected vote from blocked network", zap.String("client_addr", addr.String()))
+	return service.ErrVoterNetworkBlocked
+}
+
+// getVoterIdentifier generates a voter identifier from request
+func (h *PollHandler) getVoterIdentifier(r *http.Request) string {
+	return h.voters.identify(r)
+}
+
+// VoterIdentifier returns the identifier r votes and creates polls as, for other transports served over HTTP
+func (h *PollHandler) VoterIdentifier(r *http.Request) string {
+	return h.getVoterIdentifier(r)
+}
+
+// CheckVoterNetwork applies the vote blocklist to r, for other transports served over HTTP
+func (h *PollHandler) CheckVoterNetwork(r *http.Request) error {
+	return h.checkVoterNetwork(r)
 }
 
 // CreatePoll creates a new poll
@@ -47,40 +86,321 @@ func (h *PollHandler) CreatePoll(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	poll, err := h.service.CreatePoll(r.Context(), &req)
+	poll, warnings, err := h.service.CreatePoll(r.Context(), &req, h.getVoterIdentifier(r))
+	if requestCanceled(r, "CreatePoll") {
+		return
+	}
+	if err != nil {
+		renderError(w, r, err, "Failed to create poll")
+		return
+	}
+
+	data, err := response.AddLinks(w, poll, pollLinks(r, poll.ID))
 	if err != nil {
-		logger.Error("Failed to create poll", zap.Error(err))
-		response.BadRequest(w, err.Error())
+		response.InternalError(w, r, "Failed to create poll", err)
 		return
 	}
 
-	response.Created(w, "Poll created successfully", poll)
+	response.CreatedWithWarnings(w, "Poll created successfully", data, warnings)
 }
 
 // GetPoll retrieves a poll with results
 func (h *PollHandler) GetPoll(w http.ResponseWriter, r *http.Request) {
-	pollIDStr := chi.URLParam(r, "id")
-	pollID, err := uuid.Parse(pollIDStr)
+	pollID := PollIDFromContext(r.Context())
+
+	fields, err := response.ParseFields(r.URL.Query().Get("fields"), pollResultFields)
 	if err != nil {
-		response.BadRequest(w, "Invalid poll ID")
+		response.BadRequest(w, "Invalid fields: "+err.Error())
 		return
 	}
 
 	voterIdentifier := h.getVoterIdentifier(r)
 	results, err := h.service.GetPollResults(r.Context(), pollID, voterIdentifier)
 	if err != nil {
-		logger.Error("Failed to get poll results",
-			zap.Error(err),
-			zap.String("poll_id", pollIDStr),
-		)
-		response.NotFound(w, err.Error())
+		renderError(w, r, err, "Failed to retrieve poll")
+		return
+	}
+
+	data, err := response.SelectFields(results, fields)
+	if err == nil {
+		data, err = response.AddLinks(w, data, pollLinks(r, pollID))
+	}
+	if err != nil {
+		response.InternalError(w, r, "Failed to retrieve poll", err)
+		return
+	}
+
+	response.Success(w, "", data)
+}
+
+// GetPollOptions retrieves only the options of a poll
+func (h *PollHandler) GetPollOptions(w http.ResponseWriter, r *http.Request) {
+	pollID := PollIDFromContext(r.Context())
+
+	options, err := h.service.GetPollOptions(r.Context(), pollID, h.getVoterIdentifier(r))
+	if err != nil {
+		renderError(w, r, err, "Failed to retrieve poll options")
+		return
+	}
+
+	response.Success(w, "", options)
+}
+
+// GetVoteStatus tells the requester whether they have voted on a poll, e.g. to choose
+// between showing the ballot and the results without fetching the results
+func (h *PollHandler) GetVoteStatus(w http.ResponseWriter, r *http.Request) {
+	pollID := PollIDFromContext(r.Context())
+
+	status, err := h.service.GetVoteStatus(r.Context(), pollID, h.getVoterIdentifier(r))
+	if err != nil {
+		renderError(w, r, err, "Failed to retrieve vote status")
+		return
+	}
+
+	response.Success(w, "", status)
+}
+
+// GetVotingStatus tells whether a poll accepts votes right now
+func (h *PollHandler) GetVotingStatus(w http.ResponseWriter, r *http.Request) {
+	status, err := h.service.VotingStatus(r.Context(), PollIDFromContext(r.Context()))
+	if err != nil {
+		renderError(w, r, err, "Failed to retrieve voting status")
+		return
+	}
+
+	response.Success(w, "", status)
+}
+
+// ListMyVotes returns the requester's own votes across all polls, newest first
+// The route requires authentication, so the identifier is always the token subject.
+func (h *PollHandler) ListMyVotes(w http.ResponseWriter, r *http.Request) {
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+	offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+
+	votes, err := h.service.ListVotesByVoter(r.Context(), h.getVoterIdentifier(r), limit, offset)
+	if err != nil {
+		renderError(w, r, err, "Failed to retrieve votes")
+		return
+	}
+
+	response.Success(w, "", votes)
+}
+
+// UpdatePollOptions replaces the texts of a poll's options while it has no votes
+func (h *PollHandler) UpdatePollOptions(w http.ResponseWriter, r *http.Request) {
+	pollID := PollIDFromContext(r.Context())
+
+	var req models.UpdateOptionsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Error("Failed to decode update options request", zap.Error(err))
+		response.BadRequest(w, "Invalid request body")
+		return
+	}
+
+	options, err := h.service.UpdateOptions(r.Context(), pollID, req.Options)
+	if err != nil {
+		renderError(w, r, err, "Failed to update poll options")
+		return
+	}
+
+	response.Success(w, "Poll options updated successfully", options)
+}
+
+// UpdateMetadata replaces a poll's integrator-defined metadata; a null metadata removes it
+func (h *PollHandler) UpdateMetadata(w http.ResponseWriter, r *http.Request) {
+	pollID := PollIDFromContext(r.Context())
+
+	var req models.MetadataRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Error("Failed to decode metadata request", zap.Error(err))
+		response.BadRequest(w, "Invalid request body")
+		return
+	}
+
+	poll, err := h.service.SetMetadata(r.Context(), pollID, req.Metadata)
+	if err != nil {
+		renderError(w, r, err, "Failed to update poll metadata")
+		return
+	}
+
+	response.Success(w, "Poll metadata updated successfully", poll)
+}
+
+// PreviewVote shows the results as they would be with one more vote for ?option=, without voting
+func (h *PollHandler) PreviewVote(w http.ResponseWriter, r *http.Request) {
+	pollID := PollIDFromContext(r.Context())
+
+	optionID, err := uuid.Parse(r.URL.Query().Get("option"))
+	if err != nil {
+		response.BadRequest(w, "Invalid option ID")
+		return
+	}
+
+	results, err := h.service.PreviewVote(r.Context(), pollID, optionID)
+	if err != nil {
+		renderError(w, r, err, "Failed to preview vote")
 		return
 	}
 
 	response.Success(w, "", results)
 }
 
+// SharePoll issues a signed share link for a poll, optionally tagged with a campaign
+// The request body is optional
+func (h *PollHandler) SharePoll(w http.ResponseWriter, r *http.Request) {
+	pollID := PollIDFromContext(r.Context())
+
+	var req models.ShareLinkRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		logger.Error("Failed to decode share link request", zap.Error(err))
+		response.BadRequest(w, "Invalid request body")
+		return
+	}
+
+	link, err := h.service.SharePoll(r.Context(), pollID, req.Campaign)
+	if err != nil {
+		renderError(w, r, err, "Failed to create share link")
+		return
+	}
+
+	response.Created(w, "Share link created", link)
+}
+
+// OpenShareLink resolves a share link token to the poll it points at
+func (h *PollHandler) OpenShareLink(w http.ResponseWriter, r *http.Request) {
+	shared, err := h.service.OpenShareLink(r.Context(), chi.URLParam(r, "token"), h.getVoterIdentifier(r))
+	if err != nil {
+		renderError(w, r, err, "Failed to open share link")
+		return
+	}
+
+	response.Success(w, "", shared)
+}
+
+// GetVoteTimeline retrieves per-option vote counts over time buckets
+// The bucket query parameter is a Go duration such as 15m or 1h (default 1h)
+func (h *PollHandler) GetVoteTimeline(w http.ResponseWriter, r *http.Request) {
+	pollID := PollIDFromContext(r.Context())
+
+	bucket := time.Hour
+	if bucketStr := r.URL.Query().Get("bucket"); bucketStr != "" {
+		var err error
+		bucket, err = time.ParseDuration(bucketStr)
+		if err != nil {
+			response.BadRequest(w, "Invalid bucket duration")
+			return
+		}
+	}
+
+	timeline, err := h.service.GetVoteTimeline(r.Context(), pollID, bucket)
+	if err != nil {
+		renderError(w, r, err, "Failed to retrieve vote timeline")
+		return
+	}
+
+	response.Success(w, "", timeline)
+}
+
+// GetPollsBatch retrieves several polls by ID in one request
+// IDs are passed as repeated query parameters (?id=...&id=...); results follow the requested order
+func (h *PollHandler) GetPollsBatch(w http.ResponseWriter, r *http.Request) {
+	idStrs := r.URL.Query()["id"]
+
+	ids := make([]uuid.UUID, 0, len(idStrs))
+	for _, idStr := range idStrs {
+		id, err := uuid.Parse(idStr)
+		if err != nil {
+			response.BadRequest(w, "Invalid poll ID: "+idStr)
+			return
+		}
+		ids = append(ids, id)
+	}
+
+	polls, err := h.service.GetPollsByIDs(r.Context(), ids)
+	if err != nil {
+		renderError(w, r, err, "Failed to retrieve polls")
+		return
+	}
+
+	data, err := linkPolls(w, r, polls)
+	if err != nil {
+		response.InternalError(w, r, "Failed to retrieve polls", err)
+		return
+	}
+	response.Success(w, "", data)
+}
+
+// GetRelatedPolls lists other active polls by the same creator, for "more from this creator"
+func (h *PollHandler) GetRelatedPolls(w http.ResponseWriter, r *http.Request) {
+	pollID := PollIDFromContext(r.Context())
+
+	polls, err := h.service.GetRelatedPolls(r.Context(), pollID)
+	if err != nil {
+		renderError(w, r, err, "Failed to retrieve related polls")
+		return
+	}
+
+	data, err := linkPolls(w, r, polls)
+	if err != nil {
+		response.InternalError(w, r, "Failed to retrieve related polls", err)
+		return
+	}
+	response.Success(w, "", data)
+}
+
+// ListFeaturedPolls lists the open polls editors pinned to the featured listing, in rank order
+func (h *PollHandler) ListFeaturedPolls(w http.ResponseWriter, r *http.Request) {
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+
+	polls, err := h.service.ListFeaturedPolls(r.Context(), limit)
+	if err != nil {
+		renderError(w, r, err, "Failed to retrieve featured polls")
+		return
+	}
+
+	if writeEmptyList(w, r, len(polls) == 0) {
+		return
+	}
+
+	data, err := linkPolls(w, r, polls)
+	if err != nil {
+		response.InternalError(w, r, "Failed to retrieve featured polls", err)
+		return
+	}
+	response.Success(w, "", data)
+}
+
+// ListPollsByMetadata lists the polls whose metadata has the string ?value= under ?key=
+func (h *PollHandler) ListPollsByMetadata(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	key := query.Get("key")
+	if key == "" {
+		response.BadRequest(w, "key is required")
+		return
+	}
+	limit, _ := strconv.Atoi(query.Get("limit"))
+	offset, _ := strconv.Atoi(query.Get("offset"))
+
+	polls, err := h.service.ListPollsByMetadata(r.Context(), key, query.Get("value"), limit, offset)
+	if err != nil {
+		renderError(w, r, err, "Failed to retrieve polls")
+		return
+	}
+
+	if writeEmptyList(w, r, len(polls) == 0) {
+		return
+	}
+
+	data, err := linkPolls(w, r, polls)
+	if err != nil {
+		response.InternalError(w, r, "Failed to retrieve polls", err)
+		return
+	}
+	response.Success(w, "", data)
+}
+
 // ListPolls lists all polls with pagination
+// With ?stream=true the polls array is written as rows are read instead of being buffered
 func (h *PollHandler) ListPolls(w http.ResponseWriter, r *http.Request) {
 	// Parse query parameters
 	limitStr := r.URL.Query().Get("limit")
@@ -96,77 +416,246 @@ func (h *PollHandler) ListPolls(w http.ResponseWriter, r *http.Request) {
 
 	activeOnly := activeOnlyStr == "true"
 
-	polls, total, err := h.service.ListPolls(r.Context(), limit, offset, activeOnly)
+	// ?fields= applies to each listed poll; the page fields are always returned
+	fields, err := response.ParseFields(r.URL.Query().Get("fields"), pollFields)
+	if err != nil {
+		response.BadRequest(w, "Invalid fields: "+err.Error())
+		return
+	}
+
+	if r.URL.Query().Get("stream") == "true" {
+		h.streamPolls(w, r, limit, offset, activeOnly, fields)
+		return
+	}
+
+	page, err := h.service.ListPolls(r.Context(), limit, offset, activeOnly)
 	if err != nil {
-		logger.Error("Failed to list polls", zap.Error(err))
-		response.InternalServerError(w, "Failed to retrieve polls")
+		renderError(w, r, err, "Failed to retrieve polls")
 		return
 	}
 
-	response.Success(w, "", map[string]interface{}{
+	// RFC 5988 pagination links, following the effective limit
+	if links := response.PaginationLinks(r.URL, page.Limit, page.Offset, page.Total); links != "" {
+		w.Header().Set("Link", links)
+	}
+
+	if writeEmptyList(w, r, len(page.Polls) == 0) {
+		return
+	}
+
+	if fields == nil && !response.WantsLinks(w) {
+		response.Success(w, "", page)
+		return
+	}
+
+	polls := make([]any, len(page.Polls))
+	for i, poll := range page.Polls {
+		polls[i], err = response.SelectFields(poll, fields)
+		if err == nil {
+			polls[i], err = response.AddLinks(w, polls[i], pollLinks(r, poll.ID))
+		}
+		if err != nil {
+			response.InternalError(w, r, "Failed to retrieve polls", err)
+			return
+		}
+	}
+	response.Success(w, "", map[string]any{
 		"polls":  polls,
+		"total":  page.Total,
+		"limit":  page.Limit,
+		"offset": page.Offset,
+	})
+}
+
+// streamPolls writes the ListPolls response incrementally, one poll at a time, each projected to selected
+// The body parses to the same JSON as the buffered response; as the status is sent before any rows are read,
+// an empty stream is still a 200 even when the client prefers 204 No Content
+func (h *PollHandler) streamPolls(w http.ResponseWriter, r *http.Request, limit, offset int, activeOnly bool, selected []string) {
+	total := h.service.CountPolls(r.Context(), activeOnly)
+
+	// RFC 5988 pagination links
+	if links := response.PaginationLinks(r.URL, limit, offset, total); links != "" {
+		w.Header().Set("Link", links)
+	}
+
+	fields := map[string]any{
 		"total":  total,
 		"limit":  limit,
 		"offset": offset,
+	}
+	err := response.StreamArray(w, fields, "polls", "Failed to retrieve polls", func(emit func(any) error) error {
+		return h.service.StreamPolls(r.Context(), limit, offset, activeOnly, func(poll models.PollWithOptions) error {
+			projected, err := response.SelectFields(poll, selected)
+			if err != nil {
+				return err
+			}
+			linked, err := response.AddLinks(w, projected, pollLinks(r, poll.ID))
+			if err != nil {
+				return err
+			}
+			return emit(linked)
+		})
 	})
+	if err != nil {
+		renderError(w, r, err, "Failed to retrieve polls")
+	}
 }
 
-// VoteOnPoll casts a vote on a poll
+// VoteOnPoll casts a vote on a poll, for an option or as a write-in answer
 func (h *PollHandler) VoteOnPoll(w http.ResponseWriter, r *http.Request) {
-	pollIDStr := chi.URLParam(r, "id")
-	pollID, err := uuid.Parse(pollIDStr)
+	pollID := PollIDFromContext(r.Context())
+
+	var req models.VoteRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Error("Failed to decode vote request", zap.Error(err))
+		response.BadRequest(w, "Invalid request body")
+		return
+	}
+
+	if err := service.ValidateRequest(&req); err != nil {
+		renderError(w, r, err, "Failed to cast vote")
+		return
+	}
+
+	if err := h.checkVoterNetwork(r); err != nil {
+		renderError(w, r, err, "Failed to cast vote")
+		return
+	}
+
+	voterIdentifier := h.getVoterIdentifier(r)
+
+	var confirmation *models.VoteConfirmation
+	var receipt *models.VoteReceipt
+	var err error
+	if req.WriteIn != "" {
+		confirmation, receipt, err = h.service.CastWriteInVote(r.Context(), pollID, req.WriteIn, voterIdentifier, req.Weight, req.ShareToken)
+	} else {
+		confirmation, receipt, err = h.service.CastVote(r.Context(), pollID, req.OptionID, voterIdentifier, req.Weight, req.ShareToken)
+	}
+	// Nobody is left to read the results, so they are not fetched either
+	if requestCanceled(r, "VoteOnPoll") {
+		return
+	}
 	if err != nil {
-		response.BadRequest(w, "Invalid poll ID")
+		renderError(w, r, err, "Failed to cast vote")
+		return
+	}
+
+	// The poll requires confirmation; the vote does not count until confirmed
+	if confirmation != nil {
+		response.Accepted(w, "Vote pending confirmation", confirmation)
 		return
 	}
 
+	h.renderVoteResults(w, r, pollID, voterIdentifier, receipt)
+}
+
+// ValidateVote checks a vote request as VoteOnPoll would, without casting the vote
+// A vote that would be accepted gets its outcome; one that would not gets the error VoteOnPoll would answer with.
+func (h *PollHandler) ValidateVote(w http.ResponseWriter, r *http.Request) {
+	pollID := PollIDFromContext(r.Context())
+
 	var req models.VoteRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		logger.Error("Failed to decode vote request", zap.Error(err))
+		logger.Error("Failed to decode vote validation request", zap.Error(err))
 		response.BadRequest(w, "Invalid request body")
 		return
 	}
+	if err := service.ValidateRequest(&req); err != nil {
+		renderError(w, r, err, "Failed to validate vote")
+		return
+	}
+
+	if err := h.checkVoterNetwork(r); err != nil {
+		renderError(w, r, err, "Failed to validate vote")
+		return
+	}
+
+	validation, err := h.service.ValidateVote(r.Context(), pollID, req.OptionID, req.WriteIn, h.getVoterIdentifier(r), req.Weight)
+	if err != nil {
+		renderError(w, r, err, "Failed to validate vote")
+		return
+	}
+
+	response.Success(w, "Vote would be accepted", validation)
+}
+
+// ConfirmVote commits a pending vote using the token returned by VoteOnPoll
+func (h *PollHandler) ConfirmVote(w http.ResponseWriter, r *http.Request) {
+	pollID := PollIDFromContext(r.Context())
+
+	var req models.ConfirmVoteRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Error("Failed to decode vote confirmation request", zap.Error(err))
+		response.BadRequest(w, "Invalid request body")
+		return
+	}
+
+	if err := h.checkVoterNetwork(r); err != nil {
+		renderError(w, r, err, "Failed to confirm vote")
+		return
+	}
 
 	voterIdentifier := h.getVoterIdentifier(r)
 
-	err = h.service.CastVote(r.Context(), pollID, req.OptionID, voterIdentifier)
+	receipt, err := h.service.ConfirmVote(r.Context(), pollID, req.Token, voterIdentifier)
 	if err != nil {
-		logger.Error("Failed to cast vote",
-			zap.Error(err),
-			zap.String("poll_id", pollIDStr),
-			zap.String("option_id", req.OptionID.String()),
-		)
-		response.BadRequest(w, err.Error())
+		renderError(w, r, err, "Failed to confirm vote")
 		return
 	}
 
+	h.renderVoteResults(w, r, pollID, voterIdentifier, receipt)
+}
+
+// renderVoteResults responds to a recorded vote with the updated poll results and the vote's receipt, if any
+func (h *PollHandler) renderVoteResults(w http.ResponseWriter, r *http.Request, pollID uuid.UUID, voterIdentifier string, receipt *models.VoteReceipt) {
 	// Get updated results
 	results, err := h.service.GetPollResults(r.Context(), pollID, voterIdentifier)
 	if err != nil {
 		logger.Warn("Failed to get updated results after vote", zap.Error(err))
+		// The vote is recorded either way, so the receipt is still handed out
+		if receipt != nil {
+			response.Success(w, "Vote cast successfully", map[string]*models.VoteReceipt{"receipt": receipt})
+			return
+		}
 		response.Success(w, "Vote cast successfully", nil)
 		return
 	}
+	results.Receipt = receipt
 
-	response.Success(w, "Vote cast successfully", results)
+	data, err := response.AddLinks(w, results, pollLinks(r, pollID))
+	if err != nil {
+		response.InternalError(w, r, "Failed to retrieve poll", err)
+		return
+	}
+	response.Success(w, "Vote cast successfully", data)
 }
 
-// DeletePoll soft deletes a poll
-func (h *PollHandler) DeletePoll(w http.ResponseWriter, r *http.Request) {
-	pollIDStr := chi.URLParam(r, "id")
-	pollID, err := uuid.Parse(pollIDStr)
-	if err != nil {
-		response.BadRequest(w, "Invalid poll ID")
+// VerifyReceipt checks a vote receipt presented in the receipt query parameter
+func (h *PollHandler) VerifyReceipt(w http.ResponseWriter, r *http.Request) {
+	pollID := PollIDFromContext(r.Context())
+
+	token := r.URL.Query().Get("receipt")
+	if token == "" {
+		response.BadRequest(w, "receipt query parameter is required")
 		return
 	}
 
-	err = h.service.DeletePoll(r.Context(), pollID)
+	verified, err := h.service.VerifyReceipt(r.Context(), pollID, token)
 	if err != nil {
-		logger.Error("Failed to delete poll",
-			zap.Error(err),
-			zap.String("poll_id", pollIDStr),
-		)
-		response.InternalServerError(w, err.Error())
+		renderError(w, r, err, "Failed to verify receipt")
+		return
+	}
+
+	response.Success(w, "Receipt is valid", verified)
+}
+
+// DeletePoll soft deletes a poll
+func (h *PollHandler) DeletePoll(w http.ResponseWriter, r *http.Request) {
+	pollID := PollIDFromContext(r.Context())
+
+	if err := h.service.DeletePoll(r.Context(), pollID); err != nil {
+		renderError(w, r, err, "Failed to delete poll")
 		return
 	}
 
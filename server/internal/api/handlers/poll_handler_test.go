@@ -0,0 +1,792 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/moabdelazem/k8s-app/internal/mocks"
+	"github.com/moabdelazem/k8s-app/internal/models"
+	"github.com/moabdelazem/k8s-app/internal/netblock"
+	"github.com/moabdelazem/k8s-app/internal/repository"
+	"github.com/moabdelazem/k8s-app/internal/service"
+	"github.com/moabdelazem/k8s-app/pkg/auth"
+	"github.com/moabdelazem/k8s-app/pkg/response"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestPollHandler wires a PollHandler on top of a mocked repository
+func newTestPollHandler(repo *mocks.MockPollRepository) *PollHandler {
+	return NewPollHandler(service.NewPollService(repo, service.PollServiceConfig{}), nil, nil)
+}
+
+// withOptions is what GetPollWithResults returns for a poll the caller has not voted on
+func withOptions(poll *models.Poll, options ...models.PollOption) *models.PollWithVote {
+	return &models.PollWithVote{PollWithOptions: models.PollWithOptions{Poll: *poll, Options: options}}
+}
+
+// withURLParam attaches a chi URL parameter to the request
+func withURLParam(r *http.Request, key, value string) *http.Request {
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add(key, value)
+	return r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+}
+
+// withPollID runs h behind PollIDMiddleware, as the router does
+func withPollID(h http.HandlerFunc) http.Handler {
+	return PollIDMiddleware(h)
+}
+
+// decodeResponse decodes the standard response envelope
+func decodeResponse(t *testing.T, rec *httptest.ResponseRecorder) response.Response {
+	var body response.Response
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	return body
+}
+
+func TestGetPoll_NotFound(t *testing.T) {
+	repo := new(mocks.MockPollRepository)
+	pollID := uuid.New()
+	repo.On("GetPollWithResults", mock.Anything, pollID, mock.Anything).Return(nil, nil)
+	repo.On("GetArchivedPollWithResults", mock.Anything, pollID, mock.Anything).Return(nil, nil)
+
+	req := withURLParam(httptest.NewRequest(http.MethodGet, "/api/v1/polls/"+pollID.String(), nil), "id", pollID.String())
+	rec := httptest.NewRecorder()
+
+	withPollID(newTestPollHandler(repo).GetPoll).ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	body := decodeResponse(t, rec)
+	assert.False(t, body.Success)
+	assert.Equal(t, service.ErrPollNotFound.Error(), body.Error)
+	repo.AssertExpectations(t)
+}
+
+func TestGetPoll_Deleted(t *testing.T) {
+	tests := []struct {
+		name       string
+		gone       bool
+		wantStatus int
+		wantError  error
+	}{
+		{"gone", true, http.StatusGone, service.ErrPollDeleted},
+		{"not found", false, http.StatusNotFound, service.ErrPollNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(mocks.MockPollRepository)
+			pollID := uuid.New()
+			deletedAt := time.Now()
+			repo.On("GetPollWithResults", mock.Anything, pollID, mock.Anything).Return(withOptions(
+				&models.Poll{ID: pollID, Question: "Deleted?", DeletedAt: &deletedAt},
+				models.PollOption{ID: uuid.New(), PollID: pollID, OptionText: "Yes"},
+			), nil)
+
+			req := withURLParam(httptest.NewRequest(http.MethodGet, "/api/v1/polls/"+pollID.String(), nil), "id", pollID.String())
+			rec := httptest.NewRecorder()
+
+			h := NewPollHandler(service.NewPollService(repo, service.PollServiceConfig{DeletedPollsGone: tt.gone}), nil, nil)
+			withPollID(h.GetPoll).ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.wantStatus, rec.Code)
+			body := decodeResponse(t, rec)
+			assert.False(t, body.Success)
+			assert.Equal(t, tt.wantError.Error(), body.Error)
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestGetPoll_InternalError(t *testing.T) {
+	response.SetExposeInternalErrors(false)
+	t.Cleanup(func() { response.SetExposeInternalErrors(true) })
+
+	repo := new(mocks.MockPollRepository)
+	pollID := uuid.New()
+	repo.On("GetPollWithResults", mock.Anything, pollID, mock.Anything).Return(nil, errors.New("connection refused"))
+
+	req := withURLParam(httptest.NewRequest(http.MethodGet, "/api/v1/polls/"+pollID.String(), nil), "id", pollID.String())
+	rec := httptest.NewRecorder()
+
+	withPollID(newTestPollHandler(repo).GetPoll).ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	body := decodeResponse(t, rec)
+	assert.False(t, body.Success)
+	assert.NotContains(t, body.Error, "connection refused")
+	assert.Equal(t, "Failed to retrieve poll", body.Error)
+	repo.AssertExpectations(t)
+}
+
+func TestVoteOnPoll_ValidationErrorKeepsMessage(t *testing.T) {
+	response.SetExposeInternalErrors(false)
+	t.Cleanup(func() { response.SetExposeInternalErrors(true) })
+
+	repo := new(mocks.MockPollRepository)
+	pollID := uuid.New()
+	repo.On("GetPollByID", mock.Anything, pollID).Return(&models.Poll{ID: pollID, IsActive: false}, nil)
+
+	body := strings.NewReader(`{"option_id":"` + uuid.New().String() + `"}`)
+	req := withURLParam(httptest.NewRequest(http.MethodPost, "/api/v1/polls/"+pollID.String()+"/vote", body), "id", pollID.String())
+	rec := httptest.NewRecorder()
+
+	withPollID(newTestPollHandler(repo).VoteOnPoll).ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Equal(t, "poll is not active", decodeResponse(t, rec).Error)
+}
+
+func TestVoteOnPoll_OptionAndWriteInRejected(t *testing.T) {
+	repo := new(mocks.MockPollRepository)
+	pollID := uuid.New()
+
+	body := strings.NewReader(`{"option_id":"` + uuid.New().String() + `","write_in":"Something else"}`)
+	req := withURLParam(httptest.NewRequest(http.MethodPost, "/api/v1/polls/"+pollID.String()+"/vote", body), "id", pollID.String())
+	rec := httptest.NewRecorder()
+
+	withPollID(newTestPollHandler(repo).VoteOnPoll).ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Equal(t, "give either option_id or write_in, not both", decodeResponse(t, rec).Error)
+	repo.AssertNotCalled(t, "CastVote", mock.Anything, mock.Anything)
+	repo.AssertNotCalled(t, "CastWriteInVote", mock.Anything, mock.Anything)
+}
+
+func TestGetPollOptions(t *testing.T) {
+	repo := new(mocks.MockPollRepository)
+	pollID := uuid.New()
+	options := []models.PollOption{
+		{ID: uuid.New(), PollID: pollID, OptionText: "Yes", Position: 0},
+		{ID: uuid.New(), PollID: pollID, OptionText: "No", Position: 1},
+	}
+	repo.On("GetPollByID", mock.Anything, pollID).Return(&models.Poll{ID: pollID, IsActive: true}, nil)
+	repo.On("GetPollOptions", mock.Anything, pollID).Return(options, nil)
+
+	req := withURLParam(httptest.NewRequest(http.MethodGet, "/api/v1/polls/"+pollID.String()+"/options", nil), "id", pollID.String())
+	rec := httptest.NewRecorder()
+
+	withPollID(newTestPollHandler(repo).GetPollOptions).ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var body struct {
+		Success bool                `json:"success"`
+		Data    []models.PollOption `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	assert.True(t, body.Success)
+	assert.Equal(t, options[0].ID, body.Data[0].ID)
+	assert.Equal(t, "No", body.Data[1].OptionText)
+
+	// The lightweight endpoint must not compute results or look up vote status
+	repo.AssertExpectations(t)
+	repo.AssertNotCalled(t, "HasVoted", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestGetPollOptions_NotFound(t *testing.T) {
+	repo := new(mocks.MockPollRepository)
+	pollID := uuid.New()
+	repo.On("GetPollByID", mock.Anything, pollID).Return(nil, nil)
+
+	req := withURLParam(httptest.NewRequest(http.MethodGet, "/api/v1/polls/"+pollID.String()+"/options", nil), "id", pollID.String())
+	rec := httptest.NewRecorder()
+
+	withPollID(newTestPollHandler(repo).GetPollOptions).ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	repo.AssertNotCalled(t, "GetPollOptions", mock.Anything, mock.Anything)
+}
+
+func TestGetVoteStatus(t *testing.T) {
+	pollID := uuid.New()
+	optionID := uuid.New()
+
+	tests := []struct {
+		name        string
+		hasVoted    bool
+		votedOption *uuid.UUID
+	}{
+		{name: "voted", hasVoted: true, votedOption: &optionID},
+		{name: "not voted", hasVoted: false, votedOption: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(mocks.MockPollRepository)
+			repo.On("GetPollByID", mock.Anything, pollID).Return(&models.Poll{ID: pollID, IsActive: true}, nil)
+			repo.On("HasVoted", mock.Anything, pollID, mock.Anything).Return(tt.hasVoted, tt.votedOption, nil)
+
+			req := withURLParam(httptest.NewRequest(http.MethodGet, "/api/v1/polls/"+pollID.String()+"/voted", nil), "id", pollID.String())
+			rec := httptest.NewRecorder()
+
+			withPollID(newTestPollHandler(repo).GetVoteStatus).ServeHTTP(rec, req)
+
+			assert.Equal(t, http.StatusOK, rec.Code)
+
+			var body struct {
+				Data models.VoteStatus `json:"data"`
+			}
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+			assert.Equal(t, tt.hasVoted, body.Data.HasVoted)
+			assert.Equal(t, tt.votedOption, body.Data.VotedOption)
+
+			// Only the vote status is read, not the results
+			repo.AssertExpectations(t)
+			repo.AssertNotCalled(t, "GetPollWithResults", mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestListMyVotes_OnlyRequesterVotes(t *testing.T) {
+	ctx := context.Background()
+	svc := service.NewPollService(repository.NewInMemoryPollRepository(), service.PollServiceConfig{})
+	poll, _, err := svc.CreatePoll(ctx, &models.CreatePollRequest{Question: "Ship it on Friday?", Options: []string{"Yes", "No"}}, "owner-1")
+	require.NoError(t, err)
+	_, _, err = svc.CastVote(ctx, poll.ID, poll.Options[0].ID, "user:alice", 0, "")
+	require.NoError(t, err)
+	_, _, err = svc.CastVote(ctx, poll.ID, poll.Options[1].ID, "user:bob", 0, "")
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/me/votes", nil)
+	req = req.WithContext(auth.WithClaims(req.Context(), &auth.Claims{Subject: "alice"}))
+	rec := httptest.NewRecorder()
+
+	NewPollHandler(svc, nil, nil).ListMyVotes(rec, req)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	var body struct {
+		Data models.VoterVoteList `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	require.Len(t, body.Data.Votes, 1)
+	assert.Equal(t, poll.ID, body.Data.Votes[0].PollID)
+	assert.Equal(t, &poll.Options[0].ID, body.Data.Votes[0].OptionID)
+	assert.Equal(t, 20, body.Data.Limit)
+}
+
+func TestGetVoteStatus_NotFound(t *testing.T) {
+	repo := new(mocks.MockPollRepository)
+	pollID := uuid.New()
+	repo.On("GetPollByID", mock.Anything, pollID).Return(nil, nil)
+
+	req := withURLParam(httptest.NewRequest(http.MethodGet, "/api/v1/polls/"+pollID.String()+"/voted", nil), "id", pollID.String())
+	rec := httptest.NewRecorder()
+
+	withPollID(newTestPollHandler(repo).GetVoteStatus).ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	repo.AssertNotCalled(t, "HasVoted", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestVoteOnPoll_TransientErrorReturnsRetryAfter(t *testing.T) {
+	repo := new(mocks.MockPollRepository)
+	pollID := uuid.New()
+	repo.On("GetPollByID", mock.Anything, pollID).Return(nil, &pq.Error{Code: "53300", Message: "too many connections"})
+
+	body := strings.NewReader(`{"option_id":"` + uuid.New().String() + `"}`)
+	req := withURLParam(httptest.NewRequest(http.MethodPost, "/api/v1/polls/"+pollID.String()+"/vote", body), "id", pollID.String())
+	rec := httptest.NewRecorder()
+
+	withPollID(newTestPollHandler(repo).VoteOnPoll).ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	assert.Equal(t, "2", rec.Header().Get("Retry-After"))
+	assert.False(t, decodeResponse(t, rec).Success)
+}
+
+func TestListPolls_StreamMatchesBuffered(t *testing.T) {
+	description := "Pick one"
+	expiresAt := time.Date(2030, time.January, 1, 0, 0, 0, 0, time.UTC)
+	pollA := uuid.New()
+	pollB := uuid.New()
+	polls := []models.PollWithOptions{
+		{
+			Poll: models.Poll{ID: pollA, Question: "First poll?", Description: &description, ExpiresAt: &expiresAt, IsActive: true, TotalVotes: 3},
+			Options: []models.PollOption{
+				{ID: uuid.New(), PollID: pollA, OptionText: "Yes", VoteCount: 2, Position: 0},
+				{ID: uuid.New(), PollID: pollA, OptionText: "No", VoteCount: 1, Position: 1},
+			},
+		},
+		{
+			Poll:    models.Poll{ID: pollB, Question: "Second poll?", IsActive: false},
+			Options: []models.PollOption{},
+		},
+	}
+
+	tests := []struct {
+		name  string
+		polls []models.PollWithOptions
+	}{
+		{name: "with polls", polls: polls},
+		{name: "empty page", polls: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(mocks.MockPollRepository)
+			repo.On("ListPollsWithOptions", mock.Anything, 2, 4, true).Return(tt.polls, nil)
+			repo.On("StreamPollsWithOptions", mock.Anything, 2, 4, true).Return(tt.polls, nil)
+			repo.On("GetTotalPollsCount", mock.Anything, true).Return(int64(7), nil)
+			handler := newTestPollHandler(repo)
+
+			list := func(target string) *httptest.ResponseRecorder {
+				rec := httptest.NewRecorder()
+				handler.ListPolls(rec, httptest.NewRequest(http.MethodGet, target, nil))
+				require.Equal(t, http.StatusOK, rec.Code)
+				return rec
+			}
+
+			buffered := list("/api/v1/polls?limit=2&offset=4&active=true")
+			streamed := list("/api/v1/polls?limit=2&offset=4&active=true&stream=true")
+
+			var bufferedBody, streamedBody any
+			require.NoError(t, json.Unmarshal(buffered.Body.Bytes(), &bufferedBody))
+			require.NoError(t, json.Unmarshal(streamed.Body.Bytes(), &streamedBody))
+			assert.Equal(t, bufferedBody, streamedBody)
+
+			// Pagination links keep the stream parameter so clients can page through streamed results
+			assert.NotEmpty(t, buffered.Header().Get("Link"))
+			assert.Equal(t, buffered.Header().Get("Link"), strings.ReplaceAll(streamed.Header().Get("Link"), "&stream=true", ""))
+		})
+	}
+}
+
+func TestCreatePoll_ReturnsWarnings(t *testing.T) {
+	repo := new(mocks.MockPollRepository)
+	repo.On("CreatePoll", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	body := strings.NewReader(`{"question":"Ship it on Friday?","options":["Yes","No"]}`)
+	rec := httptest.NewRecorder()
+
+	newTestPollHandler(repo).CreatePoll(rec, httptest.NewRequest(http.MethodPost, "/api/v1/polls", body))
+
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	resp := decodeResponse(t, rec)
+	assert.True(t, resp.Success)
+	assert.NotNil(t, resp.Data)
+	assert.Equal(t, []string{"poll has only 2 options"}, resp.Warnings)
+}
+
+func TestGetPollsBatch(t *testing.T) {
+	first, second := uuid.New(), uuid.New()
+	repo := new(mocks.MockPollRepository)
+	repo.On("GetPollsByIDs", mock.Anything, []uuid.UUID{second, first}).Return([]models.PollWithOptions{
+		{Poll: models.Poll{ID: second}, Options: []models.PollOption{}},
+		{Poll: models.Poll{ID: first}, Options: []models.PollOption{}},
+	}, nil)
+
+	rec := httptest.NewRecorder()
+	target := "/api/v1/polls/batch?id=" + second.String() + "&id=" + first.String()
+
+	newTestPollHandler(repo).GetPollsBatch(rec, httptest.NewRequest(http.MethodGet, target, nil))
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var body struct {
+		Data []models.PollWithOptions `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	require.Len(t, body.Data, 2)
+	assert.Equal(t, second, body.Data[0].ID)
+	assert.Equal(t, first, body.Data[1].ID)
+}
+
+func TestGetPollsBatch_InvalidID(t *testing.T) {
+	repo := new(mocks.MockPollRepository)
+	rec := httptest.NewRecorder()
+
+	newTestPollHandler(repo).GetPollsBatch(rec, httptest.NewRequest(http.MethodGet, "/api/v1/polls/batch?id=nope", nil))
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	repo.AssertNotCalled(t, "GetPollsByIDs", mock.Anything, mock.Anything)
+}
+
+func TestVoteOnPoll_RequiresConfirmation(t *testing.T) {
+	repo := new(mocks.MockPollRepository)
+	pollID := uuid.New()
+	optionID := uuid.New()
+	repo.On("GetPollByID", mock.Anything, pollID).Return(&models.Poll{ID: pollID, IsActive: true, RequireConfirmation: true}, nil)
+	repo.On("HasVoted", mock.Anything, pollID, mock.Anything).Return(false, nil, nil)
+	repo.On("GetPollOptions", mock.Anything, pollID).Return([]models.PollOption{{ID: optionID, PollID: pollID}}, nil)
+	repo.On("CastVote", mock.Anything, mock.Anything).Return(nil).Once()
+	repo.On("GetPollWithResults", mock.Anything, pollID, mock.Anything).Return(withOptions(
+		&models.Poll{ID: pollID, IsActive: true, RequireConfirmation: true, TotalVotes: 1},
+		models.PollOption{ID: optionID, PollID: pollID, VoteCount: 1},
+	), nil)
+
+	handler := newTestPollHandler(repo)
+
+	body := strings.NewReader(`{"option_id":"` + optionID.String() + `"}`)
+	req := withURLParam(httptest.NewRequest(http.MethodPost, "/api/v1/polls/"+pollID.String()+"/vote", body), "id", pollID.String())
+	rec := httptest.NewRecorder()
+	withPollID(handler.VoteOnPoll).ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusAccepted, rec.Code)
+	var pending struct {
+		Data models.VoteConfirmation `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &pending))
+	require.NotEmpty(t, pending.Data.Token)
+	repo.AssertNotCalled(t, "CastVote", mock.Anything, mock.Anything)
+
+	body = strings.NewReader(`{"confirmation_token":"` + pending.Data.Token + `"}`)
+	req = withURLParam(httptest.NewRequest(http.MethodPost, "/api/v1/polls/"+pollID.String()+"/vote/confirm", body), "id", pollID.String())
+	rec = httptest.NewRecorder()
+	withPollID(handler.ConfirmVote).ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "Vote cast successfully", decodeResponse(t, rec).Message)
+	repo.AssertExpectations(t)
+}
+
+func TestValidateVote(t *testing.T) {
+	pollID := uuid.New()
+	optionID := uuid.New()
+	past := time.Now().Add(-time.Hour)
+	maxVotes := int64(3)
+
+	tests := []struct {
+		name         string
+		poll         *models.Poll
+		body         string
+		hasVoted     bool
+		wantStatus   int
+		wantOutcome  string
+		wantError    string
+		checksOption bool
+	}{
+		{name: "valid", poll: &models.Poll{ID: pollID, IsActive: true}, body: `{"option_id":"` + optionID.String() + `"}`,
+			wantStatus: http.StatusOK, wantOutcome: models.VoteOutcomeRecorded, checksOption: true},
+		{name: "valid, pending confirmation", poll: &models.Poll{ID: pollID, IsActive: true, RequireConfirmation: true}, body: `{"option_id":"` + optionID.String() + `"}`,
+			wantStatus: http.StatusOK, wantOutcome: models.VoteOutcomePendingConfirmation, checksOption: true},
+		{name: "valid write-in", poll: &models.Poll{ID: pollID, IsActive: true, AllowWriteIn: true}, body: `{"write_in":"Something else"}`,
+			wantStatus: http.StatusOK, wantOutcome: models.VoteOutcomeRecorded},
+		{name: "poll not found", body: `{"option_id":"` + optionID.String() + `"}`,
+			wantStatus: http.StatusNotFound, wantError: "poll not found"},
+		{name: "poll inactive", poll: &models.Poll{ID: pollID}, body: `{"option_id":"` + optionID.String() + `"}`,
+			wantStatus: http.StatusBadRequest, wantError: "poll is not active"},
+		{name: "poll expired", poll: &models.Poll{ID: pollID, IsActive: true, ExpiresAt: &past}, body: `{"option_id":"` + optionID.String() + `"}`,
+			wantStatus: http.StatusBadRequest, wantError: "poll has expired"},
+		{name: "poll full", poll: &models.Poll{ID: pollID, IsActive: true, MaxVotes: &maxVotes, TotalVotes: 3}, body: `{"option_id":"` + optionID.String() + `"}`,
+			wantStatus: http.StatusBadRequest, wantError: "poll is full"},
+		{name: "already voted", poll: &models.Poll{ID: pollID, IsActive: true}, body: `{"option_id":"` + optionID.String() + `"}`, hasVoted: true,
+			wantStatus: http.StatusBadRequest, wantError: "you have already voted on this poll"},
+		{name: "option of another poll", poll: &models.Poll{ID: pollID, IsActive: true}, body: `{"option_id":"` + uuid.New().String() + `"}`,
+			wantStatus: http.StatusBadRequest, wantError: "invalid option for this poll", checksOption: true},
+		{name: "option and write-in", body: `{"option_id":"` + optionID.String() + `","write_in":"Something else"}`,
+			wantStatus: http.StatusBadRequest, wantError: "give either option_id or write_in, not both"},
+		{name: "neither option nor write-in", body: `{}`,
+			wantStatus: http.StatusBadRequest, wantError: "option_id or write_in is required"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(mocks.MockPollRepository)
+			repo.On("GetPollByID", mock.Anything, pollID).Return(tt.poll, nil)
+			repo.On("HasVoted", mock.Anything, pollID, mock.Anything).Return(tt.hasVoted, nil, nil)
+			repo.On("GetPollOptions", mock.Anything, pollID).Return([]models.PollOption{{ID: optionID, PollID: pollID}}, nil)
+
+			req := withURLParam(httptest.NewRequest(http.MethodPost, "/api/v1/polls/"+pollID.String()+"/vote/validate", strings.NewReader(tt.body)), "id", pollID.String())
+			rec := httptest.NewRecorder()
+			withPollID(newTestPollHandler(repo).ValidateVote).ServeHTTP(rec, req)
+
+			require.Equal(t, tt.wantStatus, rec.Code, rec.Body.String())
+			if tt.wantError != "" {
+				assert.Equal(t, tt.wantError, decodeResponse(t, rec).Error)
+			} else {
+				var body struct {
+					Data models.VoteValidation `json:"data"`
+				}
+				require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+				assert.Equal(t, models.VoteValidation{Outcome: tt.wantOutcome, Weight: 1}, body.Data)
+			}
+			if !tt.checksOption {
+				repo.AssertNotCalled(t, "GetPollOptions", mock.Anything, mock.Anything)
+			}
+			// Nothing is ever recorded
+			repo.AssertNotCalled(t, "CastVote", mock.Anything, mock.Anything)
+			repo.AssertNotCalled(t, "CastWriteInVote", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestConfirmVote_InvalidToken(t *testing.T) {
+	repo := new(mocks.MockPollRepository)
+	pollID := uuid.New()
+
+	body := strings.NewReader(`{"confirmation_token":"nope"}`)
+	req := withURLParam(httptest.NewRequest(http.MethodPost, "/api/v1/polls/"+pollID.String()+"/vote/confirm", body), "id", pollID.String())
+	rec := httptest.NewRecorder()
+
+	withPollID(newTestPollHandler(repo).ConfirmVote).ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Equal(t, "confirmation token is invalid or has expired", decodeResponse(t, rec).Error)
+}
+
+func TestVoteOnPoll_AlreadyVotedLocalized(t *testing.T) {
+	tests := []struct {
+		name           string
+		acceptLanguage string
+		wantLanguage   string
+		wantMessage    string
+	}{
+		{name: "default english", acceptLanguage: "", wantLanguage: "en", wantMessage: "you have already voted on this poll"},
+		{name: "arabic", acceptLanguage: "ar-EG,ar;q=0.9,en;q=0.8", wantLanguage: "ar", wantMessage: "لقد قمت بالتصويت في هذا الاستطلاع بالفعل"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(mocks.MockPollRepository)
+			pollID := uuid.New()
+			repo.On("GetPollByID", mock.Anything, pollID).Return(&models.Poll{ID: pollID, IsActive: true}, nil)
+			repo.On("HasVoted", mock.Anything, pollID, mock.Anything).Return(true, nil, nil)
+
+			body := strings.NewReader(`{"option_id":"` + uuid.New().String() + `"}`)
+			req := withURLParam(httptest.NewRequest(http.MethodPost, "/api/v1/polls/"+pollID.String()+"/vote", body), "id", pollID.String())
+			if tt.acceptLanguage != "" {
+				req.Header.Set("Accept-Language", tt.acceptLanguage)
+			}
+			rec := httptest.NewRecorder()
+
+			withPollID(newTestPollHandler(repo).VoteOnPoll).ServeHTTP(rec, req)
+
+			assert.Equal(t, http.StatusBadRequest, rec.Code)
+			assert.Equal(t, tt.wantLanguage, rec.Header().Get("Content-Language"))
+			assert.Equal(t, tt.wantMessage, decodeResponse(t, rec).Error)
+		})
+	}
+}
+
+func TestPreviewVote_InvalidOptionID(t *testing.T) {
+	repo := new(mocks.MockPollRepository)
+	pollID := uuid.New()
+
+	req := withURLParam(httptest.NewRequest(http.MethodGet, "/api/v1/polls/"+pollID.String()+"/preview?option=nope", nil), "id", pollID.String())
+	rec := httptest.NewRecorder()
+
+	withPollID(newTestPollHandler(repo).PreviewVote).ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Equal(t, "Invalid option ID", decodeResponse(t, rec).Error)
+	repo.AssertNotCalled(t, "GetPollByID", mock.Anything, mock.Anything)
+}
+
+func TestVoteOnPoll_BlockedNetwork(t *testing.T) {
+	blocked, err := netblock.Load(strings.NewReader("203.0.113.0/24\n2001:db8:10::/48\n"))
+	require.NoError(t, err)
+
+	tests := []struct {
+		name           string
+		remoteAddr     string
+		forwarded      string
+		trustedProxies int
+		wantStatus     int
+		wantMessage    string
+	}{
+		{name: "peer inside blocked range", remoteAddr: "203.0.113.7:4321", wantStatus: http.StatusForbidden, wantMessage: "votes from this network are not accepted"},
+		{name: "spoofed header ignored without trusted proxies", remoteAddr: "203.0.113.7:4321", forwarded: "198.51.100.7", wantStatus: http.StatusForbidden, wantMessage: "votes from this network are not accepted"},
+		{name: "trusted proxy hop decides", remoteAddr: "10.0.0.1:4321", forwarded: "2001:db8:10::1", trustedProxies: 1, wantStatus: http.StatusForbidden, wantMessage: "votes from this network are not accepted"},
+		{name: "spoofed leading hop ignored", remoteAddr: "10.0.0.1:4321", forwarded: "198.51.100.7, 203.0.113.7", trustedProxies: 1, wantStatus: http.StatusForbidden, wantMessage: "votes from this network are not accepted"},
+		{name: "unparseable hop falls back to peer", remoteAddr: "203.0.113.7:4321", forwarded: "x", trustedProxies: 1, wantStatus: http.StatusForbidden, wantMessage: "votes from this network are not accepted"},
+		{name: "unparseable peer rejected", remoteAddr: "x", wantStatus: http.StatusForbidden, wantMessage: "votes from this network are not accepted"},
+		{name: "outside blocked ranges", remoteAddr: "198.51.100.7:4321", forwarded: "203.0.113.7", wantStatus: http.StatusBadRequest, wantMessage: "poll is not active"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(mocks.MockPollRepository)
+			pollID := uuid.New()
+			repo.On("GetPollByID", mock.Anything, pollID).Return(&models.Poll{ID: pollID, IsActive: false}, nil).Maybe()
+			handler := NewPollHandler(service.NewPollService(repo, service.PollServiceConfig{}), nil, blocked)
+
+			body := strings.NewReader(`{"option_id":"` + uuid.New().String() + `"}`)
+			req := withURLParam(httptest.NewRequest(http.MethodPost, "/api/v1/polls/"+pollID.String()+"/vote", body), "id", pollID.String())
+			req.RemoteAddr = tt.remoteAddr
+			if tt.forwarded != "" {
+				req.Header.Set("X-Forwarded-For", tt.forwarded)
+			}
+			rec := httptest.NewRecorder()
+
+			ClientAddrMiddleware(tt.trustedProxies)(withPollID(handler.VoteOnPoll)).ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.wantStatus, rec.Code)
+			assert.Equal(t, tt.wantMessage, decodeResponse(t, rec).Error)
+			if tt.wantStatus == http.StatusForbidden {
+				repo.AssertNotCalled(t, "GetPollByID", mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
+// dataKeys returns the top-level keys of an object in the response data
+func dataKeys(t *testing.T, data any) []string {
+	t.Helper()
+	object, ok := data.(map[string]any)
+	require.True(t, ok, "data is not an object: %v", data)
+	keys := make([]string, 0, len(object))
+	for key := range object {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func TestGetPoll_FieldSelection(t *testing.T) {
+	tests := []struct {
+		name     string
+		fields   string
+		wantKeys []string
+	}{
+		{name: "question and total votes", fields: "question,total_votes", wantKeys: []string{"question", "total_votes"}},
+		{name: "vote status and options", fields: "has_voted, options", wantKeys: []string{"has_voted", "options"}},
+		{name: "omitted field stays omitted", fields: "id,description", wantKeys: []string{"id"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(mocks.MockPollRepository)
+			pollID := uuid.New()
+			repo.On("GetPollWithResults", mock.Anything, pollID, mock.Anything).Return(withOptions(
+				&models.Poll{ID: pollID, Question: "Tabs or spaces?", IsActive: true, TotalVotes: 4},
+				models.PollOption{ID: uuid.New(), PollID: pollID, OptionText: "Tabs", VoteCount: 4},
+			), nil)
+
+			target := "/api/v1/polls/" + pollID.String() + "?fields=" + url.QueryEscape(tt.fields)
+			req := withURLParam(httptest.NewRequest(http.MethodGet, target, nil), "id", pollID.String())
+			rec := httptest.NewRecorder()
+
+			withPollID(newTestPollHandler(repo).GetPoll).ServeHTTP(rec, req)
+
+			require.Equal(t, http.StatusOK, rec.Code)
+			body := decodeResponse(t, rec)
+			assert.Equal(t, tt.wantKeys, dataKeys(t, body.Data))
+			if slices.Contains(tt.wantKeys, "total_votes") {
+				assert.Equal(t, float64(4), body.Data.(map[string]any)["total_votes"])
+			}
+		})
+	}
+}
+
+func TestGetPoll_UnknownField(t *testing.T) {
+	repo := new(mocks.MockPollRepository)
+	pollID := uuid.New()
+
+	req := withURLParam(httptest.NewRequest(http.MethodGet, "/api/v1/polls/"+pollID.String()+"?fields=question,owner_id", nil), "id", pollID.String())
+	rec := httptest.NewRecorder()
+
+	withPollID(newTestPollHandler(repo).GetPoll).ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Equal(t, `Invalid fields: unknown field "owner_id"`, decodeResponse(t, rec).Error)
+	repo.AssertNotCalled(t, "GetPollByID", mock.Anything, mock.Anything)
+}
+
+func TestListPolls_FieldSelection(t *testing.T) {
+	polls := []models.PollWithOptions{
+		{Poll: models.Poll{ID: uuid.New(), Question: "First poll?", TotalVotes: 3}, Options: []models.PollOption{}},
+		{Poll: models.Poll{ID: uuid.New(), Question: "Second poll?"}, Options: []models.PollOption{}},
+	}
+
+	repo := new(mocks.MockPollRepository)
+	repo.On("ListPollsWithOptions", mock.Anything, 20, 0, false).Return(polls, nil)
+	repo.On("StreamPollsWithOptions", mock.Anything, 20, 0, false).Return(polls, nil)
+	repo.On("GetTotalPollsCount", mock.Anything, false).Return(int64(2), nil)
+	handler := newTestPollHandler(repo)
+
+	list := func(target string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		handler.ListPolls(rec, httptest.NewRequest(http.MethodGet, target, nil))
+		require.Equal(t, http.StatusOK, rec.Code)
+		return rec
+	}
+
+	buffered := list("/api/v1/polls?fields=question,total_votes")
+	body := decodeResponse(t, buffered)
+	assert.Equal(t, []string{"limit", "offset", "polls", "total"}, dataKeys(t, body.Data))
+	listed := body.Data.(map[string]any)["polls"].([]any)
+	require.Len(t, listed, 2)
+	for _, poll := range listed {
+		assert.Equal(t, []string{"question", "total_votes"}, dataKeys(t, poll))
+	}
+
+	// Streaming applies the same projection
+	streamed := list("/api/v1/polls?fields=question,total_votes&stream=true")
+	var bufferedBody, streamedBody any
+	require.NoError(t, json.Unmarshal(buffered.Body.Bytes(), &bufferedBody))
+	require.NoError(t, json.Unmarshal(streamed.Body.Bytes(), &streamedBody))
+	assert.Equal(t, bufferedBody, streamedBody)
+}
+
+func TestListPolls_EmptyList(t *testing.T) {
+	tests := []struct {
+		name       string
+		prefer     string
+		wantStatus int
+	}{
+		{"200 with an empty array by default", "", http.StatusOK},
+		{"204 when the client prefers a minimal response", "return=minimal", http.StatusNoContent},
+		{"preferences are matched case-insensitively among others", "respond-async, Return=Minimal; x=1", http.StatusNoContent},
+		{"other preferences keep the 200", "return=representation", http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(mocks.MockPollRepository)
+			repo.On("ListPollsWithOptions", mock.Anything, 20, 40, false).Return([]models.PollWithOptions{}, nil)
+			repo.On("GetTotalPollsCount", mock.Anything, false).Return(int64(3), nil)
+			repo.On("ListFeaturedPolls", mock.Anything, service.MaxFeaturedPolls).Return([]models.PollWithOptions(nil), nil)
+			handler := newTestPollHandler(repo)
+
+			for _, list := range []struct {
+				target string
+				serve  http.HandlerFunc
+			}{
+				{"/api/v1/polls?offset=40", handler.ListPolls},
+				{"/api/v1/polls/featured", handler.ListFeaturedPolls},
+			} {
+				req := httptest.NewRequest(http.MethodGet, list.target, nil)
+				if tt.prefer != "" {
+					req.Header.Set("Prefer", tt.prefer)
+				}
+				rec := httptest.NewRecorder()
+				list.serve(rec, req)
+
+				require.Equal(t, tt.wantStatus, rec.Code, list.target)
+				assert.Equal(t, "Prefer", rec.Header().Get("Vary"))
+				if tt.wantStatus == http.StatusNoContent {
+					assert.Empty(t, rec.Body.String())
+					assert.Equal(t, "return=minimal", rec.Header().Get("Preference-Applied"))
+					continue
+				}
+				assert.Empty(t, rec.Header().Get("Preference-Applied"))
+				body := decodeResponse(t, rec)
+				if polls, ok := body.Data.(map[string]any); ok {
+					assert.Equal(t, []any{}, polls["polls"])
+				} else {
+					assert.Equal(t, []any{}, body.Data)
+				}
+			}
+		})
+	}
+}
+
+func TestListPolls_UnknownField(t *testing.T) {
+	rec := httptest.NewRecorder()
+	newTestPollHandler(new(mocks.MockPollRepository)).ListPolls(rec, httptest.NewRequest(http.MethodGet, "/api/v1/polls?fields=has_voted", nil))
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Equal(t, `Invalid fields: unknown field "has_voted"`, decodeResponse(t, rec).Error)
+}
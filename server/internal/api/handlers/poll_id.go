@@ -0,0 +1,31 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/moabdelazem/k8s-app/internal/service"
+)
+
+type pollIDKey struct{}
+
+// PollIDMiddleware parses the {id} URL parameter of the routes below it as a poll ID
+// Invalid IDs are rejected with 400 before the handler runs; valid ones are read with PollIDFromContext.
+func PollIDMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		pollID, err := uuid.Parse(chi.URLParam(r, "id"))
+		if err != nil {
+			renderError(w, r, &service.ValidationError{Code: service.CodeInvalidPollID, Message: "Invalid poll ID"}, "Invalid poll ID")
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), pollIDKey{}, pollID)))
+	})
+}
+
+// PollIDFromContext returns the poll ID parsed by PollIDMiddleware, or uuid.Nil outside it
+func PollIDFromContext(ctx context.Context) uuid.UUID {
+	pollID, _ := ctx.Value(pollIDKey{}).(uuid.UUID)
+	return pollID
+}
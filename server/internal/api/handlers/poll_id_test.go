@@ -0,0 +1,42 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPollIDMiddleware(t *testing.T) {
+	var got uuid.UUID
+	called := false
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+		got = PollIDFromContext(r.Context())
+	})
+
+	req := withURLParam(httptest.NewRequest(http.MethodGet, "/api/v1/polls/not-a-uuid", nil), "id", "not-a-uuid")
+	rec := httptest.NewRecorder()
+	PollIDMiddleware(next).ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Equal(t, "Invalid poll ID", decodeResponse(t, rec).Error)
+	assert.False(t, called, "the handler must not run for an invalid ID")
+
+	// The error is coded, so it is localized like the service's validation errors
+	req.Header.Set("Accept-Language", "ar")
+	rec = httptest.NewRecorder()
+	PollIDMiddleware(next).ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Equal(t, "ar", rec.Header().Get("Content-Language"))
+
+	pollID := uuid.New()
+	req = withURLParam(httptest.NewRequest(http.MethodGet, "/api/v1/polls/"+pollID.String(), nil), "id", pollID.String())
+	PollIDMiddleware(next).ServeHTTP(httptest.NewRecorder(), req)
+
+	assert.True(t, called)
+	assert.Equal(t, pollID, got)
+}
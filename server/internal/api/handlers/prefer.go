@@ -0,0 +1,35 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+)
+
+// preferMinimal is the RFC 7240 preference with which clients opt into 204 No Content for empty lists
+const preferMinimal = "return=minimal"
+
+// prefersMinimal reports whether the request carries Prefer: return=minimal
+// Preference parameters such as "; foo=bar" are ignored.
+func prefersMinimal(r *http.Request) bool {
+	for _, header := range r.Header.Values("Prefer") {
+		for _, preference := range strings.Split(header, ",") {
+			token, _, _ := strings.Cut(preference, ";")
+			if strings.EqualFold(strings.TrimSpace(token), preferMinimal) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// writeEmptyList answers an empty list with 204 No Content when the client prefers a minimal response,
+// reporting whether it did; otherwise the caller writes the usual 200, with an empty array if need be.
+func writeEmptyList(w http.ResponseWriter, r *http.Request, empty bool) bool {
+	w.Header().Add("Vary", "Prefer")
+	if !empty || !prefersMinimal(r) {
+		return false
+	}
+	w.Header().Set("Preference-Applied", preferMinimal)
+	w.WriteHeader(http.StatusNoContent)
+	return true
+}
@@ -0,0 +1,56 @@
+package handlers
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/moabdelazem/k8s-app/internal/models"
+)
+
+// prometheusContentType is the content type of the Prometheus text exposition format
+const prometheusContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// GetPollResultsPrometheus renders a poll's vote counts in the Prometheus text exposition format
+// so results can be scraped directly into dashboards
+func (h *PollHandler) GetPollResultsPrometheus(w http.ResponseWriter, r *http.Request) {
+	pollID := PollIDFromContext(r.Context())
+
+	results, err := h.service.GetPollResults(r.Context(), pollID, "")
+	if err != nil {
+		renderError(w, r, err, "Failed to retrieve poll results")
+		return
+	}
+
+	w.Header().Set("Content-Type", prometheusContentType)
+	w.WriteHeader(http.StatusOK)
+	writePollResultsPrometheus(w, results)
+}
+
+// writePollResultsPrometheus writes one gauge sample per option plus the poll's total votes
+func writePollResultsPrometheus(w io.Writer, results *models.PollResults) {
+	pollLabel := escapeLabelValue(results.ID.String())
+
+	fmt.Fprintln(w, "# HELP poll_option_votes Votes received by a poll option.")
+	fmt.Fprintln(w, "# TYPE poll_option_votes gauge")
+	for _, opt := range results.Options {
+		fmt.Fprintf(w, "poll_option_votes{poll=\"%s\",option=\"%s\",option_id=\"%s\"} %d\n",
+			pollLabel,
+			escapeLabelValue(opt.OptionText),
+			escapeLabelValue(opt.ID.String()),
+			opt.VoteCount,
+		)
+	}
+
+	fmt.Fprintln(w, "# HELP poll_total_votes Total votes cast on a poll.")
+	fmt.Fprintln(w, "# TYPE poll_total_votes gauge")
+	fmt.Fprintf(w, "poll_total_votes{poll=\"%s\"} %d\n", pollLabel, results.TotalVotes)
+}
+
+// labelValueEscaper escapes backslashes, double quotes and line feeds as the exposition format requires
+var labelValueEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func escapeLabelValue(value string) string {
+	return labelValueEscaper.Replace(value)
+}
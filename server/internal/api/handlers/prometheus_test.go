@@ -0,0 +1,59 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/moabdelazem/k8s-app/internal/mocks"
+	"github.com/moabdelazem/k8s-app/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestGetPollResultsPrometheus(t *testing.T) {
+	pollID := uuid.MustParse("11111111-1111-1111-1111-111111111111")
+	yesID := uuid.MustParse("22222222-2222-2222-2222-222222222222")
+	otherID := uuid.MustParse("33333333-3333-3333-3333-333333333333")
+
+	repo := new(mocks.MockPollRepository)
+	repo.On("GetPollWithResults", mock.Anything, pollID, "").Return(withOptions(
+		&models.Poll{ID: pollID, IsActive: true, TotalVotes: 5},
+		models.PollOption{ID: yesID, PollID: pollID, OptionText: "Yes", VoteCount: 3},
+		models.PollOption{ID: otherID, PollID: pollID, OptionText: "Say \"hi\"\\\nbye", VoteCount: 2},
+	), nil)
+
+	req := withURLParam(httptest.NewRequest(http.MethodGet, "/api/v1/polls/"+pollID.String()+"/results.prom", nil), "id", pollID.String())
+	rec := httptest.NewRecorder()
+
+	withPollID(newTestPollHandler(repo).GetPollResultsPrometheus).ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "text/plain; version=0.0.4; charset=utf-8", rec.Header().Get("Content-Type"))
+	assert.Equal(t, `# HELP poll_option_votes Votes received by a poll option.
+# TYPE poll_option_votes gauge
+poll_option_votes{poll="11111111-1111-1111-1111-111111111111",option="Yes",option_id="22222222-2222-2222-2222-222222222222"} 3
+poll_option_votes{poll="11111111-1111-1111-1111-111111111111",option="Say \"hi\"\\\nbye",option_id="33333333-3333-3333-3333-333333333333"} 2
+# HELP poll_total_votes Total votes cast on a poll.
+# TYPE poll_total_votes gauge
+poll_total_votes{poll="11111111-1111-1111-1111-111111111111"} 5
+`, rec.Body.String())
+
+	// Results are anonymous, so no vote status lookup is made
+	repo.AssertExpectations(t)
+}
+
+func TestGetPollResultsPrometheus_NotFound(t *testing.T) {
+	repo := new(mocks.MockPollRepository)
+	pollID := uuid.New()
+	repo.On("GetPollWithResults", mock.Anything, pollID, "").Return(nil, nil)
+	repo.On("GetArchivedPollWithResults", mock.Anything, pollID, "").Return(nil, nil)
+
+	req := withURLParam(httptest.NewRequest(http.MethodGet, "/api/v1/polls/"+pollID.String()+"/results.prom", nil), "id", pollID.String())
+	rec := httptest.NewRecorder()
+
+	withPollID(newTestPollHandler(repo).GetPollResultsPrometheus).ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
@@ -0,0 +1,128 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/moabdelazem/k8s-app/internal/models"
+	"github.com/moabdelazem/k8s-app/internal/service"
+	"github.com/moabdelazem/k8s-app/pkg/logger"
+	"github.com/moabdelazem/k8s-app/pkg/response"
+	"go.uber.org/zap"
+)
+
+type TemplateHandler struct {
+	service *service.TemplateService
+	voters  voterIdentity
+}
+
+// NewTemplateHandler creates a TemplateHandler; polls created from templates are owned
+// by the caller as identified with dedupFactors, like polls created directly
+func NewTemplateHandler(service *service.TemplateService, dedupFactors []string) *TemplateHandler {
+	return &TemplateHandler{service: service, voters: newVoterIdentity(dedupFactors)}
+}
+
+// CreateTemplate stores a new poll template
+func (h *TemplateHandler) CreateTemplate(w http.ResponseWriter, r *http.Request) {
+	var req models.TemplateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Error("Failed to decode template request", zap.Error(err))
+		response.BadRequest(w, "Invalid request body")
+		return
+	}
+
+	template, err := h.service.CreateTemplate(r.Context(), &req)
+	if err != nil {
+		renderError(w, r, err, "Failed to create template")
+		return
+	}
+
+	response.Created(w, "Template created successfully", template)
+}
+
+// ListTemplates lists every poll template
+func (h *TemplateHandler) ListTemplates(w http.ResponseWriter, r *http.Request) {
+	templates, err := h.service.ListTemplates(r.Context())
+	if err != nil {
+		renderError(w, r, err, "Failed to retrieve templates")
+		return
+	}
+
+	response.Success(w, "", templates)
+}
+
+// GetTemplate retrieves a poll template
+func (h *TemplateHandler) GetTemplate(w http.ResponseWriter, r *http.Request) {
+	templateID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "Invalid template ID")
+		return
+	}
+
+	template, err := h.service.GetTemplate(r.Context(), templateID)
+	if err != nil {
+		renderError(w, r, err, "Failed to retrieve template")
+		return
+	}
+
+	response.Success(w, "", template)
+}
+
+// UpdateTemplate replaces the contents of a poll template
+func (h *TemplateHandler) UpdateTemplate(w http.ResponseWriter, r *http.Request) {
+	templateID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "Invalid template ID")
+		return
+	}
+
+	var req models.TemplateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Error("Failed to decode template request", zap.Error(err))
+		response.BadRequest(w, "Invalid request body")
+		return
+	}
+
+	template, err := h.service.UpdateTemplate(r.Context(), templateID, &req)
+	if err != nil {
+		renderError(w, r, err, "Failed to update template")
+		return
+	}
+
+	response.Success(w, "Template updated successfully", template)
+}
+
+// DeleteTemplate removes a poll template
+func (h *TemplateHandler) DeleteTemplate(w http.ResponseWriter, r *http.Request) {
+	templateID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "Invalid template ID")
+		return
+	}
+
+	if err := h.service.DeleteTemplate(r.Context(), templateID); err != nil {
+		renderError(w, r, err, "Failed to delete template")
+		return
+	}
+
+	response.Success(w, "Template deleted successfully", nil)
+}
+
+// InstantiateTemplate creates a new live poll from a template
+func (h *TemplateHandler) InstantiateTemplate(w http.ResponseWriter, r *http.Request) {
+	templateID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "Invalid template ID")
+		return
+	}
+
+	poll, warnings, err := h.service.Instantiate(r.Context(), templateID, h.voters.identify(r))
+	if err != nil {
+		renderError(w, r, err, "Failed to create poll from template")
+		return
+	}
+
+	response.CreatedWithWarnings(w, "Poll created successfully", poll, warnings)
+}
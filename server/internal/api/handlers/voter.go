@@ -0,0 +1,126 @@
+package handlers
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+
+	"github.com/moabdelazem/k8s-app/internal/config"
+	"github.com/moabdelazem/k8s-app/pkg/auth"
+)
+
+// voterCookieName is the cookie read by the cookie dedup factor
+const voterCookieName = "voter_id"
+
+// voterIdentity derives the identifier used to deduplicate votes and attribute polls
+type voterIdentity struct {
+	factors []string
+}
+
+// newVoterIdentity combines the given factors; an empty list means the client address alone
+func newVoterIdentity(factors []string) voterIdentity {
+	if len(factors) == 0 {
+		factors = []string{config.VoterFactorIP}
+	}
+	return voterIdentity{factors: factors}
+}
+
+// identify returns the voter identifier for r
+// Authenticated requests use the token subject. Anonymous ones use the client address
+// resolved by ClientAddrMiddleware when it is the only factor, and a hash of all configured
+// factors otherwise; proxy headers only count as far as TRUSTED_PROXY_COUNT trusts them,
+// so clients cannot vote repeatedly by sending made-up X-Forwarded-For values.
+func (v voterIdentity) identify(r *http.Request) string {
+	if claims := auth.ClaimsFromContext(r.Context()); claims != nil {
+		return "user:" + claims.Subject
+	}
+
+	if len(v.factors) == 1 && v.factors[0] == config.VoterFactorIP {
+		return ClientAddr(r)
+	}
+
+	h := sha256.New()
+	for _, factor := range v.factors {
+		// NUL separators keep adjacent values apart; HTTP header values cannot contain NUL
+		h.Write([]byte(factor))
+		h.Write([]byte{0})
+		h.Write([]byte(factorValue(r, factor)))
+		h.Write([]byte{0})
+	}
+	return "anon:" + hex.EncodeToString(h.Sum(nil))
+}
+
+// factorValue extracts a single dedup factor from r; missing values are empty
+func factorValue(r *http.Request, factor string) string {
+	switch factor {
+	case config.VoterFactorIP:
+		return ClientAddr(r)
+	case config.VoterFactorUserAgent:
+		return r.UserAgent()
+	case config.VoterFactorCookie:
+		if cookie, err := r.Cookie(voterCookieName); err == nil {
+			return cookie.Value
+		}
+	}
+	return ""
+}
+
+// ClientAddr returns the client IP resolved by ClientAddrMiddleware, or the peer address
+// without the port outside it, as proxy headers are only trusted behind known proxies
+func ClientAddr(r *http.Request) string {
+	if addr, ok := r.Context().Value(clientAddrKey{}).(string); ok {
+		return addr
+	}
+	return resolveClientAddr(r, 0)
+}
+
+type clientAddrKey struct{}
+
+// ClientAddrMiddleware resolves the client IP once for the routes below it, read with ClientAddr
+// trustedProxies is the number of proxies in front of the server that append to X-Forwarded-For;
+// 0 uses the peer address and ignores the header entirely.
+func ClientAddrMiddleware(trustedProxies int) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			addr := resolveClientAddr(r, trustedProxies)
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), clientAddrKey{}, addr)))
+		})
+	}
+}
+
+// resolveClientAddr takes the X-Forwarded-For hop added by the outermost trusted proxy,
+// counting from the right since clients control everything to its left.
+// Requests with fewer hops or an unparseable one fall back to the peer address.
+func resolveClientAddr(r *http.Request, trustedProxies int) string {
+	peer := stripPort(r.RemoteAddr)
+	if trustedProxies <= 0 {
+		return peer
+	}
+
+	var hops []string
+	for _, value := range r.Header.Values("X-Forwarded-For") {
+		for _, hop := range strings.Split(value, ",") {
+			hops = append(hops, strings.TrimSpace(hop))
+		}
+	}
+	if len(hops) < trustedProxies {
+		return peer
+	}
+	addr, err := netip.ParseAddr(stripPort(hops[len(hops)-trustedProxies]))
+	if err != nil {
+		return peer
+	}
+	return addr.String()
+}
+
+// stripPort drops the port from host:port, leaving bare addresses as they are
+func stripPort(addr string) string {
+	if host, _, err := net.SplitHostPort(addr); err == nil {
+		return host
+	}
+	return addr
+}
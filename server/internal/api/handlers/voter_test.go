@@ -0,0 +1,180 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/moabdelazem/k8s-app/internal/config"
+	"github.com/moabdelazem/k8s-app/pkg/auth"
+	"github.com/stretchr/testify/assert"
+)
+
+// voterRequest builds an anonymous request from the given client attributes
+func voterRequest(ip, userAgent, cookie string) *http.Request {
+	r := httptest.NewRequest(http.MethodPost, "/api/v1/polls/1/vote", nil)
+	r.RemoteAddr = ip
+	r.Header.Set("User-Agent", userAgent)
+	if cookie != "" {
+		r.AddCookie(&http.Cookie{Name: voterCookieName, Value: cookie})
+	}
+	return r
+}
+
+func TestVoterIdentity_DedupFactors(t *testing.T) {
+	tests := []struct {
+		name     string
+		factors  []string
+		a, b     *http.Request
+		wantSame bool
+	}{
+		{
+			name:     "default ignores user agent",
+			factors:  nil,
+			a:        voterRequest("10.0.0.1:1234", "Firefox", ""),
+			b:        voterRequest("10.0.0.1:1234", "Chrome", ""),
+			wantSame: true,
+		},
+		{
+			name:     "ip and user agent split on user agent",
+			factors:  []string{config.VoterFactorIP, config.VoterFactorUserAgent},
+			a:        voterRequest("10.0.0.1:1234", "Firefox", ""),
+			b:        voterRequest("10.0.0.1:1234", "Chrome", ""),
+			wantSame: false,
+		},
+		{
+			name:     "ip and user agent ignore cookie",
+			factors:  []string{config.VoterFactorIP, config.VoterFactorUserAgent},
+			a:        voterRequest("10.0.0.1:1234", "Firefox", "abc"),
+			b:        voterRequest("10.0.0.1:1234", "Firefox", "xyz"),
+			wantSame: true,
+		},
+		{
+			name:     "ip and cookie split on cookie",
+			factors:  []string{config.VoterFactorIP, config.VoterFactorCookie},
+			a:        voterRequest("10.0.0.1:1234", "Firefox", "abc"),
+			b:        voterRequest("10.0.0.1:1234", "Firefox", "xyz"),
+			wantSame: false,
+		},
+		{
+			name:     "ip and cookie split on ip",
+			factors:  []string{config.VoterFactorIP, config.VoterFactorCookie},
+			a:        voterRequest("10.0.0.1:1234", "Firefox", "abc"),
+			b:        voterRequest("10.0.0.2:1234", "Firefox", "abc"),
+			wantSame: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			voters := newVoterIdentity(tt.factors)
+			a, b := voters.identify(tt.a), voters.identify(tt.b)
+			if tt.wantSame {
+				assert.Equal(t, a, b)
+			} else {
+				assert.NotEqual(t, a, b)
+			}
+		})
+	}
+}
+
+func TestVoterIdentity_SingleIPFactorKeepsPlainAddress(t *testing.T) {
+	voters := newVoterIdentity([]string{config.VoterFactorIP})
+
+	assert.Equal(t, "10.0.0.1", voters.identify(voterRequest("10.0.0.1:1234", "Firefox", "")))
+}
+
+func TestVoterIdentity_IgnoresUntrustedForwardedFor(t *testing.T) {
+	tests := []struct {
+		name           string
+		factors        []string
+		trustedProxies int
+		wantSame       bool
+	}{
+		{name: "ip alone", factors: []string{config.VoterFactorIP}, wantSame: true},
+		{name: "ip and user agent", factors: []string{config.VoterFactorIP, config.VoterFactorUserAgent}, wantSame: true},
+		{name: "hop added by a trusted proxy", factors: []string{config.VoterFactorIP}, trustedProxies: 1, wantSame: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			voters := newVoterIdentity(tt.factors)
+			identify := func(forwarded string) string {
+				var id string
+				r := voterRequest("10.0.0.1:1234", "Firefox", "")
+				r.Header.Set("X-Forwarded-For", forwarded)
+				ClientAddrMiddleware(tt.trustedProxies)(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+					id = voters.identify(r)
+				})).ServeHTTP(httptest.NewRecorder(), r)
+				return id
+			}
+
+			// A client making up a new header for each vote must not get a new identity each time
+			a, b := identify("198.51.100.1"), identify("198.51.100.2")
+			if tt.wantSame {
+				assert.Equal(t, a, b)
+			} else {
+				assert.NotEqual(t, a, b)
+			}
+		})
+	}
+}
+
+func TestVoterIdentity_CompositeKeyIsHashed(t *testing.T) {
+	voters := newVoterIdentity([]string{config.VoterFactorIP, config.VoterFactorUserAgent})
+
+	id := voters.identify(voterRequest("10.0.0.1:1234", "Firefox", ""))
+
+	assert.Regexp(t, `^anon:[0-9a-f]{64}$`, id)
+}
+
+func TestVoterIdentity_AuthenticatedUserWins(t *testing.T) {
+	voters := newVoterIdentity([]string{config.VoterFactorIP, config.VoterFactorUserAgent})
+	r := voterRequest("10.0.0.1:1234", "Firefox", "")
+	r = r.WithContext(auth.WithClaims(r.Context(), &auth.Claims{Subject: "alice"}))
+
+	assert.Equal(t, "user:alice", voters.identify(r))
+}
+
+func TestClientAddr(t *testing.T) {
+	tests := []struct {
+		name           string
+		headers        map[string]string
+		remote         string
+		trustedProxies int
+		want           string
+	}{
+		{name: "remote address without port", remote: "10.0.0.1:4000", want: "10.0.0.1"},
+		{name: "ipv6 remote address", remote: "[2001:db8::1]:4000", want: "2001:db8::1"},
+		{name: "forwarded header ignored without trusted proxies", headers: map[string]string{"X-Forwarded-For": "203.0.113.7"}, remote: "10.0.0.1:4000", want: "10.0.0.1"},
+		{name: "real ip header ignored", headers: map[string]string{"X-Real-IP": "203.0.113.8"}, remote: "10.0.0.1:4000", trustedProxies: 1, want: "10.0.0.1"},
+		{name: "hop added by the trusted proxy", headers: map[string]string{"X-Forwarded-For": "203.0.113.7"}, remote: "10.0.0.1:4000", trustedProxies: 1, want: "203.0.113.7"},
+		{name: "spoofed leading hop ignored", headers: map[string]string{"X-Forwarded-For": "1.2.3.4, 203.0.113.7"}, remote: "10.0.0.1:4000", trustedProxies: 1, want: "203.0.113.7"},
+		{name: "hop added by the outermost of two proxies", headers: map[string]string{"X-Forwarded-For": "1.2.3.4, 203.0.113.7, 10.0.0.2"}, remote: "10.0.0.1:4000", trustedProxies: 2, want: "203.0.113.7"},
+		{name: "too few hops fall back to peer", headers: map[string]string{"X-Forwarded-For": "203.0.113.7"}, remote: "10.0.0.1:4000", trustedProxies: 2, want: "10.0.0.1"},
+		{name: "unparseable hop falls back to peer", headers: map[string]string{"X-Forwarded-For": "x"}, remote: "10.0.0.1:4000", trustedProxies: 1, want: "10.0.0.1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.RemoteAddr = tt.remote
+			for k, v := range tt.headers {
+				req.Header.Set(k, v)
+			}
+			var got string
+			ClientAddrMiddleware(tt.trustedProxies)(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+				got = ClientAddr(r)
+			})).ServeHTTP(httptest.NewRecorder(), req)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestClientAddr_OutsideMiddlewareUsesPeer(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.RemoteAddr = "10.0.0.1:4000"
+	req.Header.Set("X-Forwarded-For", "203.0.113.7")
+
+	assert.Equal(t, "10.0.0.1", ClientAddr(req))
+}
@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/moabdelazem/k8s-app/internal/models"
+	"github.com/moabdelazem/k8s-app/internal/service"
+	"github.com/moabdelazem/k8s-app/pkg/logger"
+	"github.com/moabdelazem/k8s-app/pkg/response"
+	"go.uber.org/zap"
+)
+
+type WebhookHandler struct {
+	service *service.WebhookService
+}
+
+func NewWebhookHandler(service *service.WebhookService) *WebhookHandler {
+	return &WebhookHandler{service: service}
+}
+
+// CreateWebhook registers a webhook for a poll
+func (h *WebhookHandler) CreateWebhook(w http.ResponseWriter, r *http.Request) {
+	pollID := PollIDFromContext(r.Context())
+
+	var req models.CreateWebhookRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Error("Failed to decode webhook request", zap.Error(err))
+		response.BadRequest(w, "Invalid request body")
+		return
+	}
+
+	webhook, err := h.service.RegisterWebhook(r.Context(), pollID, &req)
+	if err != nil {
+		renderError(w, r, err, "Failed to register webhook")
+		return
+	}
+
+	response.Created(w, "Webhook registered successfully", webhook)
+}
+
+// ListWebhooks lists the webhooks registered for a poll
+func (h *WebhookHandler) ListWebhooks(w http.ResponseWriter, r *http.Request) {
+	pollID := PollIDFromContext(r.Context())
+
+	webhooks, err := h.service.ListWebhooks(r.Context(), pollID)
+	if err != nil {
+		renderError(w, r, err, "Failed to retrieve webhooks")
+		return
+	}
+
+	response.Success(w, "", webhooks)
+}
+
+// DeleteWebhook removes a webhook from a poll
+func (h *WebhookHandler) DeleteWebhook(w http.ResponseWriter, r *http.Request) {
+	pollID := PollIDFromContext(r.Context())
+
+	webhookIDStr := chi.URLParam(r, "webhookID")
+	webhookID, err := uuid.Parse(webhookIDStr)
+	if err != nil {
+		response.BadRequest(w, "Invalid webhook ID")
+		return
+	}
+
+	err = h.service.DeleteWebhook(r.Context(), pollID, webhookID)
+	if err != nil {
+		renderError(w, r, err, "Failed to delete webhook")
+		return
+	}
+
+	response.Success(w, "Webhook deleted successfully", nil)
+}
@@ -0,0 +1,35 @@
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/moabdelazem/k8s-app/pkg/response"
+)
+
+// inFlightRetryAfter is the back-off suggested to clients turned away by the in-flight limit
+const inFlightRetryAfter = time.Second
+
+// InFlightLimitMiddleware serves at most limit requests at once, answering 503 with Retry-After
+// beyond that instead of queueing, so bursts cannot pile up goroutines waiting on the database pool.
+// Requests for which exempt returns true (e.g. health probes) are never limited and take no slot.
+func InFlightLimitMiddleware(limit int, exempt func(r *http.Request) bool) func(http.Handler) http.Handler {
+	slots := make(chan struct{}, limit)
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if exempt != nil && exempt(r) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			select {
+			case slots <- struct{}{}:
+				defer func() { <-slots }()
+				next.ServeHTTP(w, r)
+			default:
+				response.ServiceUnavailable(w, "Server is busy, please retry", inFlightRetryAfter)
+			}
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInFlightLimitMiddleware_RejectsBeyondLimit(t *testing.T) {
+	const limit = 2
+	entered := make(chan struct{})
+	release := make(chan struct{})
+	blocking := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/v1/polls" {
+			entered <- struct{}{}
+			<-release
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+	h := InFlightLimitMiddleware(limit, func(r *http.Request) bool { return r.URL.Path == "/health" })(blocking)
+
+	var wg sync.WaitGroup
+	codes := make(chan int, limit)
+	for i := 0; i < limit; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			codes <- serve(t, h, http.MethodGet, "/api/v1/polls").Code
+		}()
+	}
+	for i := 0; i < limit; i++ {
+		<-entered
+	}
+
+	// Every slot is held, so the next request is turned away
+	rec := serve(t, h, http.MethodGet, "/api/v1/polls")
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	assert.Equal(t, "1", rec.Header().Get("Retry-After"))
+
+	// Health probes are exempt
+	assert.Equal(t, http.StatusOK, serve(t, h, http.MethodGet, "/health").Code)
+
+	close(release)
+	wg.Wait()
+	close(codes)
+	for code := range codes {
+		assert.Equal(t, http.StatusOK, code)
+	}
+
+	// Finished requests free their slots
+	done := make(chan *httptest.ResponseRecorder)
+	go func() { done <- serve(t, h, http.MethodGet, "/api/v1/polls") }()
+	<-entered
+	require.Equal(t, http.StatusOK, (<-done).Code)
+}
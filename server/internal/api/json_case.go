@@ -0,0 +1,49 @@
+package api
+
+import (
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/moabdelazem/k8s-app/internal/config"
+	"github.com/moabdelazem/k8s-app/pkg/response"
+)
+
+// jsonCaseParam is the Accept header parameter choosing the case of JSON keys, e.g.
+// Accept: application/json; case=camel
+const jsonCaseParam = "case"
+
+// JSONCaseMiddleware writes JSON object keys in the case the request asks for with a case
+// parameter on its Accept header, camel or snake, and in defaultCase otherwise
+// Unknown cases leave the default in place.
+func JSONCaseMiddleware(defaultCase string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			fieldCase := defaultCase
+			if requested := acceptedJSONCase(r.Header.Values("Accept")); requested != "" {
+				fieldCase = requested
+			}
+			if fieldCase == config.JSONCaseCamel {
+				w = response.WithCamelCaseJSON(w)
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// acceptedJSONCase returns the first known case parameter among the media ranges of Accept headers, or ""
+func acceptedJSONCase(accept []string) string {
+	for _, header := range accept {
+		for _, mediaRange := range strings.Split(header, ",") {
+			_, params, err := mime.ParseMediaType(mediaRange)
+			if err != nil {
+				continue
+			}
+			switch fieldCase := strings.ToLower(params[jsonCaseParam]); fieldCase {
+			case config.JSONCaseCamel, config.JSONCaseSnake:
+				return fieldCase
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,49 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/moabdelazem/k8s-app/internal/config"
+	"github.com/moabdelazem/k8s-app/pkg/response"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJSONCaseMiddleware(t *testing.T) {
+	handler := func(defaultCase string) http.Handler {
+		return JSONCaseMiddleware(defaultCase)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			response.Success(w, "", map[string]any{"total_votes": 3, "created_at": "2026-01-01T00:00:00Z"})
+		}))
+	}
+	const (
+		snake = `{"success":true,"data":{"created_at":"2026-01-01T00:00:00Z","total_votes":3}}` + "\n"
+		camel = `{"data":{"createdAt":"2026-01-01T00:00:00Z","totalVotes":3},"success":true}` + "\n"
+	)
+
+	tests := []struct {
+		name        string
+		defaultCase string
+		accept      string
+		want        string
+	}{
+		{name: "snake by default", defaultCase: config.JSONCaseSnake, accept: "", want: snake},
+		{name: "camel requested", defaultCase: config.JSONCaseSnake, accept: "application/json; case=camel", want: camel},
+		{name: "camel requested among ranges", defaultCase: config.JSONCaseSnake, accept: "text/html, application/json;q=0.9;case=CAMEL", want: camel},
+		{name: "camel by default", defaultCase: config.JSONCaseCamel, accept: "application/json", want: camel},
+		{name: "snake requested", defaultCase: config.JSONCaseCamel, accept: "application/json; case=snake", want: snake},
+		{name: "unknown case keeps default", defaultCase: config.JSONCaseSnake, accept: "application/json; case=kebab", want: snake},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/polls", nil)
+			if tt.accept != "" {
+				req.Header.Set("Accept", tt.accept)
+			}
+			rec := httptest.NewRecorder()
+			handler(tt.defaultCase).ServeHTTP(rec, req)
+			assert.Equal(t, tt.want, rec.Body.String())
+		})
+	}
+}
@@ -0,0 +1,48 @@
+package api
+
+import (
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/moabdelazem/k8s-app/pkg/response"
+)
+
+// linksParam is the Accept header parameter turning hypermedia links on or off, e.g.
+// Accept: application/json; links=true
+const linksParam = "links"
+
+// LinksMiddleware adds _links to the resources in responses when the request asks for them with
+// a links parameter on its Accept header, or, when byDefault is set, unless it opts out with links=false
+// Values of links that do not parse as a boolean leave the default in place.
+func LinksMiddleware(byDefault bool) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			links := byDefault
+			if requested, ok := acceptedLinks(r.Header.Values("Accept")); ok {
+				links = requested
+			}
+			if links {
+				w = response.WithLinks(w)
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// acceptedLinks returns the first boolean links parameter among the media ranges of Accept headers
+func acceptedLinks(accept []string) (links, ok bool) {
+	for _, header := range accept {
+		for _, mediaRange := range strings.Split(header, ",") {
+			_, params, err := mime.ParseMediaType(mediaRange)
+			if err != nil {
+				continue
+			}
+			if links, err := strconv.ParseBool(params[linksParam]); err == nil {
+				return links, true
+			}
+		}
+	}
+	return false, false
+}
@@ -0,0 +1,121 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/moabdelazem/k8s-app/internal/config"
+	"github.com/moabdelazem/k8s-app/pkg/response"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLinksMiddleware(t *testing.T) {
+	handler := func(byDefault bool) http.Handler {
+		return LinksMiddleware(byDefault)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			data, err := response.AddLinks(w, map[string]any{"id": "p1"}, func() map[string]response.Link {
+				return map[string]response.Link{"self": {Href: "/api/v1/polls/p1", Method: http.MethodGet}}
+			})
+			require.NoError(t, err)
+			response.Success(w, "", data)
+		}))
+	}
+	const (
+		plain  = `{"success":true,"data":{"id":"p1"}}` + "\n"
+		linked = `{"success":true,"data":{"_links":{"self":{"href":"/api/v1/polls/p1","method":"GET"}},"id":"p1"}}` + "\n"
+	)
+
+	tests := []struct {
+		name      string
+		byDefault bool
+		accept    string
+		want      string
+	}{
+		{name: "off by default", accept: "", want: plain},
+		{name: "requested", accept: "application/json; links=true", want: linked},
+		{name: "requested among ranges", accept: "text/html, application/json;q=0.9;links=1", want: linked},
+		{name: "on by default", byDefault: true, accept: "application/json", want: linked},
+		{name: "opted out", byDefault: true, accept: "application/json; links=false", want: plain},
+		{name: "unparsable value keeps default", accept: "application/json; links=maybe", want: plain},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/polls/p1", nil)
+			if tt.accept != "" {
+				req.Header.Set("Accept", tt.accept)
+			}
+			rec := httptest.NewRecorder()
+			handler(tt.byDefault).ServeHTTP(rec, req)
+			assert.Equal(t, tt.want, rec.Body.String())
+		})
+	}
+}
+
+func TestSetupRoutes_PollLinksResolve(t *testing.T) {
+	cfg := newTestConfig()
+	cfg.RepoBackend = config.RepoBackendMemory
+	cfg.BasePath = "/polls-service"
+	router := SetupRoutes(context.Background(), nil, cfg)
+
+	request := func(method, href, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, href, strings.NewReader(body))
+		req.Header.Set("Accept", "application/json; links=true")
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+	type poll struct {
+		ID      string                   `json:"id"`
+		Options []struct{ ID string }    `json:"options"`
+		Votes   int64                    `json:"total_votes"`
+		Links   map[string]response.Link `json:"_links"`
+	}
+	decode := func(rec *httptest.ResponseRecorder) poll {
+		var body struct{ Data poll }
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body), rec.Body.String())
+		return body.Data
+	}
+
+	rec := request(http.MethodPost, "/polls-service/api/v1/polls", `{"question": "Tabs or spaces?", "options": ["Tabs", "Spaces"]}`)
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	created := decode(rec)
+
+	pollPath := "/polls-service/api/v1/polls/" + created.ID
+	assert.Equal(t, map[string]response.Link{
+		"self":    {Href: pollPath, Method: http.MethodGet},
+		"vote":    {Href: pollPath + "/vote", Method: http.MethodPost},
+		"results": {Href: pollPath, Method: http.MethodGet},
+		"delete":  {Href: pollPath, Method: http.MethodDelete},
+	}, created.Links)
+
+	// Following each link reaches the route it names
+	links := created.Links
+	rec = request(links["self"].Method, links["self"].Href, "")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Equal(t, created.ID, decode(rec).ID)
+	assert.Equal(t, links, decode(rec).Links)
+
+	rec = request(links["vote"].Method, links["vote"].Href, `{"option_id": "`+created.Options[0].ID+`"}`)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Equal(t, links, decode(rec).Links)
+
+	rec = request(links["results"].Method, links["results"].Href, "")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Equal(t, int64(1), decode(rec).Votes)
+
+	rec = request(links["delete"].Method, links["delete"].Href, "")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Equal(t, http.StatusNotFound, request(links["self"].Method, links["self"].Href, "").Code)
+
+	// Listed polls carry their links too, and nothing asks for them by default
+	request(http.MethodPost, "/polls-service/api/v1/polls", `{"question": "Vim or Emacs?", "options": ["Vim", "Emacs"]}`)
+	rec = request(http.MethodGet, "/polls-service/api/v1/polls", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"_links":{"delete":`)
+	assert.NotContains(t, serve(t, router, http.MethodGet, "/polls-service/api/v1/polls").Body.String(), "_links")
+}
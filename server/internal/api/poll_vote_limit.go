@@ -0,0 +1,70 @@
+package api
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/moabdelazem/k8s-app/internal/api/handlers"
+	"github.com/moabdelazem/k8s-app/pkg/response"
+)
+
+// pollVoteRetryAfter is the back-off suggested to voters turned away by the per-poll limit
+const pollVoteRetryAfter = time.Second
+
+// keyedLimiter allows at most limit holders per key at once.
+// A key's entry is removed as soon as its last holder releases it, so idle keys take no memory.
+type keyedLimiter struct {
+	mu     sync.Mutex
+	limit  int
+	active map[string]int
+}
+
+func newKeyedLimiter(limit int) *keyedLimiter {
+	return &keyedLimiter{limit: limit, active: make(map[string]int)}
+}
+
+// acquire takes a slot for key, reporting false without waiting when all its slots are held
+func (l *keyedLimiter) acquire(key string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.active[key] >= l.limit {
+		return false
+	}
+	l.active[key]++
+	return true
+}
+
+// release frees a slot taken by acquire
+func (l *keyedLimiter) release(key string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.active[key] <= 1 {
+		delete(l.active, key)
+		return
+	}
+	l.active[key]--
+}
+
+// PollVoteLimitMiddleware casts at most limit votes at once on each poll, answering 503 with Retry-After
+// beyond that, so one hot poll cannot hold the whole database pool while votes on other polls wait.
+// Polls are told apart by the ID parsed by handlers.PollIDMiddleware, so it must run below that middleware.
+func PollVoteLimitMiddleware(limit int) func(http.Handler) http.Handler {
+	return pollVoteLimitMiddleware(newKeyedLimiter(limit))
+}
+
+func pollVoteLimitMiddleware(limiter *keyedLimiter) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			pollID := handlers.PollIDFromContext(r.Context()).String()
+			if !limiter.acquire(pollID) {
+				response.ServiceUnavailable(w, "Too many votes on this poll at once, please retry", pollVoteRetryAfter)
+				return
+			}
+			defer limiter.release(pollID)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package api
+
+import (
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/moabdelazem/k8s-app/internal/api/handlers"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPollVoteLimitMiddleware_LimitsEachPollSeparately(t *testing.T) {
+	const limit = 2
+	hotPoll, quietPoll := "/polls/"+uuid.NewString()+"/vote", "/polls/"+uuid.NewString()+"/vote"
+	entered := make(chan struct{})
+	release := make(chan struct{})
+
+	limiter := newKeyedLimiter(limit)
+	router := chi.NewRouter()
+	router.With(handlers.PollIDMiddleware, pollVoteLimitMiddleware(limiter)).Post("/polls/{id}/vote", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == hotPoll {
+			entered <- struct{}{}
+			<-release
+		}
+		w.WriteHeader(http.StatusCreated)
+	})
+
+	// Fire more concurrent votes on the hot poll than it allows; the excess is turned away at once
+	const votes = limit + 3
+	codes := make(chan int, votes)
+	var wg sync.WaitGroup
+	for i := 0; i < votes; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			codes <- serve(t, router, http.MethodPost, hotPoll).Code
+		}()
+	}
+	for i := 0; i < limit; i++ {
+		<-entered
+	}
+	for i := 0; i < votes-limit; i++ {
+		assert.Equal(t, http.StatusServiceUnavailable, <-codes)
+	}
+
+	// The hot poll's slots are all held, yet votes on another poll go through
+	rec := serve(t, router, http.MethodPost, quietPoll)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+
+	limited := serve(t, router, http.MethodPost, hotPoll)
+	assert.Equal(t, http.StatusServiceUnavailable, limited.Code)
+	assert.Equal(t, "1", limited.Header().Get("Retry-After"))
+
+	close(release)
+	wg.Wait()
+	close(codes)
+	for code := range codes {
+		assert.Equal(t, http.StatusCreated, code)
+	}
+
+	// Polls without votes in flight are forgotten
+	limiter.mu.Lock()
+	defer limiter.mu.Unlock()
+	assert.Empty(t, limiter.active)
+}
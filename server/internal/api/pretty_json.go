@@ -0,0 +1,26 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/moabdelazem/k8s-app/pkg/response"
+)
+
+// PrettyJSONMiddleware indents JSON responses when the request asks for it with ?pretty=true,
+// or, when byDefault is set, unless it opts out with ?pretty=false
+// Values of pretty that do not parse as a boolean leave the default in place.
+func PrettyJSONMiddleware(byDefault bool) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			pretty := byDefault
+			if value, err := strconv.ParseBool(r.URL.Query().Get("pretty")); err == nil {
+				pretty = value
+			}
+			if pretty {
+				w = response.WithPrettyJSON(w)
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,40 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/moabdelazem/k8s-app/pkg/response"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrettyJSONMiddleware(t *testing.T) {
+	handler := func(byDefault bool) http.Handler {
+		return PrettyJSONMiddleware(byDefault)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			response.JSON(w, http.StatusOK, map[string]int{"total": 1})
+		}))
+	}
+	const pretty, compact = "{\n  \"total\": 1\n}\n", "{\"total\":1}\n"
+
+	tests := []struct {
+		name      string
+		byDefault bool
+		query     string
+		want      string
+	}{
+		{name: "compact by default", byDefault: false, query: "", want: compact},
+		{name: "requested", byDefault: false, query: "?pretty=true", want: pretty},
+		{name: "pretty by default", byDefault: true, query: "", want: pretty},
+		{name: "opted out", byDefault: true, query: "?pretty=false", want: compact},
+		{name: "unparsable value keeps default", byDefault: true, query: "?pretty=yes-please", want: pretty},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			handler(tt.byDefault).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/polls"+tt.query, nil))
+			assert.Equal(t, tt.want, rec.Body.String())
+		})
+	}
+}
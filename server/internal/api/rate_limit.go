@@ -0,0 +1,109 @@
+package api
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/moabdelazem/k8s-app/internal/api/handlers"
+	"github.com/moabdelazem/k8s-app/pkg/response"
+)
+
+// tokenBucket tracks the request budget of one client
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// rateLimiter is a per-key token bucket allowing limit requests per window with bursts up to limit.
+// Keys idle for a full window have a full bucket again and are evicted, bounding memory use.
+type rateLimiter struct {
+	mu        sync.Mutex
+	limit     float64
+	rate      float64 // Tokens refilled per second
+	window    time.Duration
+	buckets   map[string]*tokenBucket
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+func newRateLimiter(limit int, window time.Duration) *rateLimiter {
+	return &rateLimiter{
+		limit:   float64(limit),
+		rate:    float64(limit) / window.Seconds(),
+		window:  window,
+		buckets: make(map[string]*tokenBucket),
+		now:     time.Now,
+	}
+}
+
+// allow spends one token for key, or reports how long until one is available
+func (l *rateLimiter) allow(key string) (bool, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.sweep(now)
+
+	bucket, ok := l.buckets[key]
+	if !ok {
+		bucket = &tokenBucket{tokens: l.limit, last: now}
+		l.buckets[key] = bucket
+	}
+
+	// Refill for the time elapsed since the last request
+	bucket.tokens += now.Sub(bucket.last).Seconds() * l.rate
+	if bucket.tokens > l.limit {
+		bucket.tokens = l.limit
+	}
+	bucket.last = now
+
+	if bucket.tokens < 1 {
+		wait := time.Duration((1 - bucket.tokens) / l.rate * float64(time.Second))
+		return false, wait
+	}
+	bucket.tokens--
+	return true, 0
+}
+
+// sweep evicts keys idle for at least a window, at most once per window
+func (l *rateLimiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < l.window {
+		return
+	}
+	for key, bucket := range l.buckets {
+		if now.Sub(bucket.last) >= l.window {
+			delete(l.buckets, key)
+		}
+	}
+	l.lastSweep = now
+}
+
+// RateLimitMiddleware limits every client IP to limit requests per window, answering 429 with Retry-After.
+// Clients are keyed by handlers.ClientAddr, so X-Forwarded-For only counts behind trusted proxies.
+// Requests to exemptPaths (e.g. health probes) are never limited.
+func RateLimitMiddleware(limit int, window time.Duration, exemptPaths ...string) func(http.Handler) http.Handler {
+	return rateLimitMiddleware(newRateLimiter(limit, window), exemptPaths)
+}
+
+func rateLimitMiddleware(limiter *rateLimiter, exemptPaths []string) func(http.Handler) http.Handler {
+	exempt := make(map[string]bool, len(exemptPaths))
+	for _, path := range exemptPaths {
+		exempt[path] = true
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if exempt[r.URL.Path] {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if ok, retryAfter := limiter.allow(handlers.ClientAddr(r)); !ok {
+				response.TooManyRequests(w, "Too many requests, please slow down", retryAfter)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
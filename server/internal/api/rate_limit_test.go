@@ -0,0 +1,115 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/moabdelazem/k8s-app/internal/api/handlers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testLimiter returns a limiter driven by a clock the test advances by hand
+func testLimiter(limit int, window time.Duration) (*rateLimiter, *time.Time) {
+	now := time.Date(2025, time.June, 1, 12, 0, 0, 0, time.UTC)
+	limiter := newRateLimiter(limit, window)
+	limiter.now = func() time.Time { return now }
+	return limiter, &now
+}
+
+// requestFrom sends a GET for path from the given remote address
+func requestFrom(h http.Handler, remoteAddr, path string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	req.RemoteAddr = remoteAddr
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestRateLimitMiddleware_Limit(t *testing.T) {
+	limiter, now := testLimiter(3, time.Minute)
+	handler := rateLimitMiddleware(limiter, nil)(okHandler)
+
+	for i := 0; i < 3; i++ {
+		// A new source port must not reset the budget
+		rec := requestFrom(handler, "10.0.0.1:"+strconv.Itoa(4000+i), "/api/v1/polls")
+		require.Equal(t, http.StatusOK, rec.Code, "request %d", i+1)
+	}
+
+	rec := requestFrom(handler, "10.0.0.1:5000", "/api/v1/polls")
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+	assert.Equal(t, "20", rec.Header().Get("Retry-After"))
+
+	// Other clients have their own budget
+	assert.Equal(t, http.StatusOK, requestFrom(handler, "10.0.0.2:4000", "/api/v1/polls").Code)
+
+	// One token is refilled every window/limit
+	*now = now.Add(20 * time.Second)
+	assert.Equal(t, http.StatusOK, requestFrom(handler, "10.0.0.1:4000", "/api/v1/polls").Code)
+	assert.Equal(t, http.StatusTooManyRequests, requestFrom(handler, "10.0.0.1:4000", "/api/v1/polls").Code)
+}
+
+func TestRateLimitMiddleware_ForwardedForDoesNotResetLimit(t *testing.T) {
+	tests := []struct {
+		name           string
+		trustedProxies int
+		forwarded      func(i int) string
+	}{
+		{name: "no trusted proxies", forwarded: func(i int) string { return "198.51.100." + strconv.Itoa(i) }},
+		{name: "spoofed hop before the trusted proxy's", trustedProxies: 1, forwarded: func(i int) string { return "198.51.100." + strconv.Itoa(i) + ", 203.0.113.7" }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			limiter, _ := testLimiter(2, time.Minute)
+			handler := handlers.ClientAddrMiddleware(tt.trustedProxies)(rateLimitMiddleware(limiter, nil)(okHandler))
+
+			codes := make([]int, 3)
+			for i := range codes {
+				req := httptest.NewRequest(http.MethodGet, "/api/v1/polls", nil)
+				req.RemoteAddr = "10.0.0.1:4000"
+				req.Header.Set("X-Forwarded-For", tt.forwarded(i))
+				rec := httptest.NewRecorder()
+				handler.ServeHTTP(rec, req)
+				codes[i] = rec.Code
+			}
+
+			assert.Equal(t, []int{http.StatusOK, http.StatusOK, http.StatusTooManyRequests}, codes)
+			assert.Len(t, limiter.buckets, 1)
+		})
+	}
+}
+
+func TestRateLimitMiddleware_HealthPathsExempt(t *testing.T) {
+	limiter, _ := testLimiter(1, time.Minute)
+	handler := rateLimitMiddleware(limiter, []string{"/health", "/live", "/ready"})(okHandler)
+
+	require.Equal(t, http.StatusOK, requestFrom(handler, "10.0.0.1:4000", "/api/v1/polls").Code)
+	require.Equal(t, http.StatusTooManyRequests, requestFrom(handler, "10.0.0.1:4000", "/api/v1/polls").Code)
+
+	for _, path := range []string{"/health", "/live", "/ready"} {
+		assert.Equal(t, http.StatusOK, requestFrom(handler, "10.0.0.1:4000", path).Code, path)
+	}
+}
+
+func TestRateLimiter_EvictsIdleKeys(t *testing.T) {
+	limiter, now := testLimiter(5, time.Minute)
+
+	limiter.allow("10.0.0.1")
+	limiter.allow("10.0.0.2")
+	require.Len(t, limiter.buckets, 2)
+
+	*now = now.Add(30 * time.Second)
+	limiter.allow("10.0.0.2")
+
+	*now = now.Add(40 * time.Second)
+	limiter.allow("10.0.0.3")
+
+	// 10.0.0.1 was idle for a full window; 10.0.0.2 was seen 40s ago
+	assert.NotContains(t, limiter.buckets, "10.0.0.1")
+	assert.Contains(t, limiter.buckets, "10.0.0.2")
+	assert.Contains(t, limiter.buckets, "10.0.0.3")
+}
@@ -0,0 +1,31 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/moabdelazem/k8s-app/internal/maintenance"
+	"github.com/moabdelazem/k8s-app/pkg/response"
+)
+
+// ReadOnlyMiddleware rejects write requests with 503 while read-only mode is on.
+// GET, HEAD and OPTIONS requests are always served.
+func ReadOnlyMiddleware(mode *maintenance.Mode) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if mode.ReadOnly() && !isReadMethod(r.Method) {
+				response.Error(w, http.StatusServiceUnavailable, "Service is in read-only mode; writes are temporarily disabled")
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// isReadMethod reports whether method is safe to serve in read-only mode
+func isReadMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	}
+	return false
+}
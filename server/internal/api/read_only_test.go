@@ -0,0 +1,54 @@
+package api
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/moabdelazem/k8s-app/internal/maintenance"
+	"github.com/stretchr/testify/assert"
+)
+
+// okHandler is a terminal handler that always succeeds
+var okHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusOK)
+})
+
+func TestReadOnlyMiddleware(t *testing.T) {
+	tests := []struct {
+		name     string
+		readOnly bool
+		method   string
+		path     string
+		wantCode int
+	}{
+		{name: "read allowed in read-only mode", readOnly: true, method: http.MethodGet, path: "/api/v1/polls", wantCode: http.StatusOK},
+		{name: "create blocked in read-only mode", readOnly: true, method: http.MethodPost, path: "/api/v1/polls", wantCode: http.StatusServiceUnavailable},
+		{name: "vote blocked in read-only mode", readOnly: true, method: http.MethodPost, path: "/api/v1/polls/1/vote", wantCode: http.StatusServiceUnavailable},
+		{name: "delete blocked in read-only mode", readOnly: true, method: http.MethodDelete, path: "/api/v1/polls/1", wantCode: http.StatusServiceUnavailable},
+		{name: "write allowed normally", readOnly: false, method: http.MethodPost, path: "/api/v1/polls", wantCode: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := ReadOnlyMiddleware(maintenance.NewMode(tt.readOnly))(okHandler)
+
+			rec := serve(t, handler, tt.method, tt.path)
+
+			assert.Equal(t, tt.wantCode, rec.Code)
+			if tt.wantCode == http.StatusServiceUnavailable {
+				assert.Contains(t, rec.Body.String(), "read-only mode")
+			}
+		})
+	}
+}
+
+func TestReadOnlyMiddleware_RuntimeToggle(t *testing.T) {
+	mode := maintenance.NewMode(false)
+	handler := ReadOnlyMiddleware(mode)(okHandler)
+
+	mode.SetReadOnly(true)
+	assert.Equal(t, http.StatusServiceUnavailable, serve(t, handler, http.MethodPost, "/api/v1/polls").Code)
+
+	mode.SetReadOnly(false)
+	assert.Equal(t, http.StatusOK, serve(t, handler, http.MethodPost, "/api/v1/polls").Code)
+}
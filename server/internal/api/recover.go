@@ -0,0 +1,48 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"runtime/debug"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/moabdelazem/k8s-app/pkg/logger"
+	"github.com/moabdelazem/k8s-app/pkg/response"
+	"go.uber.org/zap"
+)
+
+// RecoverMiddleware turns a panicking handler into a 500, in place of chi's Recoverer, which prints to stderr.
+// The panic value and stack are logged with the request's correlation ID so the panic can be traced.
+// http.ErrAbortHandler is panicked again, so net/http still aborts the response silently.
+func RecoverMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer func() {
+			recovered := recover()
+			if recovered == nil {
+				return
+			}
+			if recovered == http.ErrAbortHandler {
+				panic(recovered)
+			}
+
+			err, ok := recovered.(error)
+			if !ok {
+				err = fmt.Errorf("%v", recovered)
+			}
+			logger.Error("Recovered from panic",
+				zap.Error(err),
+				zap.String("request_id", middleware.GetReqID(r.Context())),
+				zap.String("method", r.Method),
+				zap.String("path", r.URL.Path),
+				zap.ByteString("stack", debug.Stack()),
+			)
+
+			// Upgraded connections, e.g. WebSockets, no longer speak HTTP
+			if r.Header.Get("Connection") != "Upgrade" {
+				response.InternalServerError(w, "Internal server error")
+			}
+		}()
+
+		next.ServeHTTP(w, r)
+	})
+}
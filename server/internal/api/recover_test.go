@@ -0,0 +1,53 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/moabdelazem/k8s-app/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestRecoverMiddleware_LogsPanicWithCorrelationID(t *testing.T) {
+	core, logs := observer.New(zapcore.ErrorLevel)
+	previous := logger.Log
+	logger.Log = zap.New(core)
+	t.Cleanup(func() { logger.Log = previous })
+
+	h := CorrelationIDMiddleware(RecoverMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("nil map write")
+	})))
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/polls", nil)
+	req.Header.Set("X-Correlation-ID", "checkout-7f3a")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusInternalServerError, rec.Code)
+	var body map[string]any
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	assert.Equal(t, false, body["success"])
+	assert.Equal(t, "Internal server error", body["error"])
+
+	entries := logs.FilterMessage("Recovered from panic").All()
+	require.Len(t, entries, 1)
+	fields := entries[0].ContextMap()
+	assert.Equal(t, "nil map write", fields["error"])
+	assert.Equal(t, "checkout-7f3a", fields["request_id"])
+	assert.Contains(t, fields["stack"], "TestRecoverMiddleware_LogsPanicWithCorrelationID")
+}
+
+func TestRecoverMiddleware_RepanicsAbortHandler(t *testing.T) {
+	h := RecoverMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic(http.ErrAbortHandler)
+	}))
+
+	assert.PanicsWithValue(t, http.ErrAbortHandler, func() {
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/api/v1/polls", nil))
+	})
+}
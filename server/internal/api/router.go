@@ -1,21 +1,46 @@
 package api
 
 import (
+	"context"
 	"database/sql"
 	"net/http"
+	"net/netip"
+	"strings"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
 	"github.com/go-chi/cors"
+	"github.com/moabdelazem/k8s-app/internal/api/docs"
 	"github.com/moabdelazem/k8s-app/internal/api/handlers"
+	"github.com/moabdelazem/k8s-app/internal/archive"
 	"github.com/moabdelazem/k8s-app/internal/config"
+	"github.com/moabdelazem/k8s-app/internal/database"
+	"github.com/moabdelazem/k8s-app/internal/geoip"
+	"github.com/moabdelazem/k8s-app/internal/graphqlapi"
+	"github.com/moabdelazem/k8s-app/internal/grpcapi"
+	"github.com/moabdelazem/k8s-app/internal/live"
+	"github.com/moabdelazem/k8s-app/internal/maintenance"
+	"github.com/moabdelazem/k8s-app/internal/metrics"
+	"github.com/moabdelazem/k8s-app/internal/netblock"
+	"github.com/moabdelazem/k8s-app/internal/outbox"
 	"github.com/moabdelazem/k8s-app/internal/repository"
 	"github.com/moabdelazem/k8s-app/internal/service"
+	"github.com/moabdelazem/k8s-app/internal/webhook"
 	"github.com/moabdelazem/k8s-app/pkg/logger"
 	"go.uber.org/zap"
+	"google.golang.org/grpc"
 )
 
-func SetupRoutes(db *sql.DB, cfg *config.Config) *chi.Mux {
+// SetupRoutes wires dependencies and registers all routes.
+// Background workers started here stop when ctx is canceled.
+func SetupRoutes(ctx context.Context, db *sql.DB, cfg *config.Config) *chi.Mux {
+	r, _ := Setup(ctx, db, cfg)
+	return r
+}
+
+// Setup is SetupRoutes also returning the gRPC server, or nil when it is disabled
+// It shares the routes' poll service, auth settings, read-only mode and vote blocklist.
+func Setup(ctx context.Context, db *sql.DB, cfg *config.Config) (*chi.Mux, *grpc.Server) {
 	r := chi.NewRouter()
 
 	// CORS middleware - configured from environment variables
@@ -34,44 +59,517 @@ func SetupRoutes(db *sql.DB, cfg *config.Config) *chi.Mux {
 	)
 
 	// Middlewares
-	r.Use(middleware.RequestID)
-	r.Use(middleware.Recoverer)
-	r.Use(LoggingMiddleware)
+	r.Use(CorrelationIDMiddleware)
+	r.Use(RecoverMiddleware)
+	r.Use(handlers.ClientAddrMiddleware(cfg.TrustedProxies))
+
+	// Indented JSON for reading responses with curl; production always answers compact
+	if cfg.Env != "production" {
+		r.Use(PrettyJSONMiddleware(cfg.PrettyJSON))
+	}
+	r.Use(JSONCaseMiddleware(cfg.JSONFieldCase))
+	r.Use(LinksMiddleware(cfg.PollLinks))
+	r.Use(LoggingMiddleware(loadGeoResolver(cfg.Log.GeoIPFile), logExcludedPaths(cfg)...))
+
+	// Global per-IP rate limit; health probes are exempt so k8s never sees a 429
+	if cfg.RateLimit.Requests > 0 {
+		r.Use(RateLimitMiddleware(cfg.RateLimit.Requests, cfg.RateLimit.Window, healthPaths(cfg)...))
+		logger.Info("Global rate limit enabled",
+			zap.Int("requests", cfg.RateLimit.Requests),
+			zap.Duration("window", cfg.RateLimit.Window),
+		)
+	}
+
+	// Shed load beyond the in-flight limit instead of queueing on the database pool
+	if cfg.RateLimit.MaxInFlight > 0 {
+		r.Use(InFlightLimitMiddleware(cfg.RateLimit.MaxInFlight, inFlightExempt(cfg)))
+		logger.Info("In-flight request limit enabled", zap.Int("max_in_flight", cfg.RateLimit.MaxInFlight))
+	}
+
+	// Bound write bodies so clients cannot stream unbounded payloads; bulk uploads get their own limit below
+	if cfg.Body.MaxBytes > 0 || cfg.Body.RequireContentLength {
+		r.Use(BodyLimitMiddleware(cfg.Body.MaxBytes, cfg.Body.RequireContentLength, bulkUpload))
+	}
+
+	// Body logging is only active for debug-level loggers
+	if cfg.Log.Bodies && logger.DebugEnabled() {
+		r.Use(BodyLoggingMiddleware(cfg.Log.BodyMaxLength))
+		logger.Debug("Request/response body logging enabled",
+			zap.Int("max_length", cfg.Log.BodyMaxLength),
+		)
+	}
+
+	// Repositories share the pool, instrumented when slow query logging is enabled
+	conn := database.WithSlowQueryLog(db, cfg.DB.SlowQueryThreshold)
+
+	// Initialize webhook dependencies
+	webhookRepo := repository.NewWebhookRepository(conn)
+	dispatcher := webhook.NewDispatcher(webhookRepo, webhook.Config{
+		MaxRetries: cfg.Webhook.MaxRetries,
+		RetryDelay: cfg.Webhook.RetryDelay,
+		Timeout:    cfg.Webhook.Timeout,
+	})
+
+	// The relay delivers outbox events to webhooks through the dispatcher; it needs a live database
+	if db != nil {
+		outboxRepo := repository.NewOutboxRepository(conn).WithDialect(sqlDialect(cfg.DB.Driver))
+		outbox.NewRelay(outboxRepo, dispatcher, outbox.Config{
+			Interval:     cfg.Outbox.RelayInterval,
+			BatchSize:    cfg.Outbox.BatchSize,
+			MaxAttempts:  cfg.Outbox.MaxAttempts,
+			RetryBackoff: cfg.Outbox.RetryBackoff,
+			ClaimTimeout: cfg.Outbox.ClaimTimeout,
+		}).Start(ctx)
+	}
+
+	// Pool gauges need a live database to read statistics from
+	registry := metrics.NewRegistry()
+	if db != nil && cfg.DB.PoolMetricsInterval > 0 {
+		metrics.NewDBPoolCollector(registry, database.Stats).Start(ctx, cfg.DB.PoolMetricsInterval)
+	}
+
+	// Initialize poll dependencies; votes are pushed to live result streams as they are recorded
+	liveHub := live.NewHub()
+	pollRepo := newPollRepository(cfg, conn)
+	pollService := service.NewPollService(pollRepo, service.PollServiceConfig{
+		MaxActivePollsPerOwner:   cfg.Poll.MaxActivePollsPerUser,
+		MaxListOptionRows:        cfg.Poll.ListMaxOptionRows,
+		MinVoteWeight:            cfg.Poll.MinVoteWeight,
+		MaxVoteWeight:            cfg.Poll.MaxVoteWeight,
+		DefaultPollTTL:           cfg.Poll.DefaultTTL,
+		MaxExpiryHorizon:         cfg.Poll.MaxExpiryHorizon,
+		VoteConfirmationTTL:      cfg.Poll.VoteConfirmationTTL,
+		GroupVoterDedup:          cfg.Poll.GroupVoterDedup,
+		ResultsCacheTTL:          cfg.Poll.ResultsCacheTTL,
+		ListWriteInsIndividually: cfg.Poll.WriteInResults == config.WriteInResultsIndividual,
+		DeletedPollsGone:         cfg.Poll.DeletedPollsGone,
+		DeleteProtectVotes:       cfg.Poll.DeleteProtectVotes,
+		SuspiciousSubnetMinVotes: cfg.Poll.SuspiciousSubnetVotes,
+		SuspiciousBurstWindow:    cfg.Poll.SuspiciousBurstWindow,
+		SuspiciousBurstMinVotes:  cfg.Poll.SuspiciousBurstVotes,
+		ArchiveRetention:         cfg.Archive.Retention,
+		ShareSecret:              cfg.Share.Secret,
+		ShareLinkTTL:             cfg.Share.TTL,
+		ReceiptSecret:            cfg.Receipt.Secret,
+		LiveResults:              liveHub,
+	})
+
+	// Long-closed polls are moved to the archive tables until shutdown
+	if cfg.Archive.Retention > 0 {
+		archive.NewJob(pollService, archive.Config{Interval: cfg.Archive.Interval}).Start(ctx)
+	}
+
+	voteBlocklist := loadVoteBlocklist(cfg.Poll.VoteBlocklistFile)
+	pollHandler := handlers.NewPollHandler(pollService, cfg.Poll.VoterDedupFactors, voteBlocklist)
+	adminHandler := handlers.NewAdminHandler(pollService)
+	liveHandler := handlers.NewLiveHandler(pollService, liveHub)
+
+	webhookService := service.NewWebhookService(webhookRepo, pollRepo)
+	webhookHandler := handlers.NewWebhookHandler(webhookService)
+
+	templateRepo := repository.NewTemplateRepository(conn)
+	templateService := service.NewTemplateService(templateRepo, pollService)
+	templateHandler := handlers.NewTemplateHandler(templateService, cfg.Poll.VoterDedupFactors)
+
+	// Poll writes may require an authenticated user; reads and votes stay public
+	var writeAuth []func(http.Handler) http.Handler
+	if cfg.Auth.RequireAuthForCreate {
+		writeAuth = append(writeAuth, AuthMiddleware(cfg.Auth.JWTSecret))
+		logger.Info("Authentication required for poll creation, option edits and deletion")
+	}
+
+	// Votes on a single poll may be capped so one hot poll cannot starve the others
+	var voteLimit []func(http.Handler) http.Handler
+	if cfg.RateLimit.MaxInFlightVotesPerPoll > 0 {
+		voteLimit = append(voteLimit, PollVoteLimitMiddleware(cfg.RateLimit.MaxInFlightVotesPerPoll))
+		logger.Info("Per-poll vote concurrency limit enabled", zap.Int("max_in_flight_votes_per_poll", cfg.RateLimit.MaxInFlightVotesPerPoll))
+	}
+
+	// Read-only mode starts from config and can be toggled at runtime by admins
+	maintenanceMode := maintenance.NewMode(cfg.ReadOnly)
+	maintenanceHandler := handlers.NewMaintenanceHandler(maintenanceMode)
+	configHandler := handlers.NewConfigHandler(cfg)
+	readOnly := ReadOnlyMiddleware(maintenanceMode)
+	if cfg.ReadOnly {
+		logger.Warn("Starting in read-only mode; write requests will be rejected")
+	}
+
+	// Request deadlines; bulk routes get the long one and live streams none
+	timeout := TimeoutMiddleware(cfg.Timeout.Default)
+	longTimeout := TimeoutMiddleware(cfg.Timeout.Long)
+	bulkBody := BodyLimitMiddleware(cfg.Body.BulkMaxBytes, cfg.Body.RequireContentLength, nil)
+
+	// API documentation
+	specHandler, err := docs.SpecHandler(cfg.BasePath)
+	if err != nil {
+		logger.Fatal("Failed to load OpenAPI spec", zap.Error(err))
+	}
+
+	// The experimental GraphQL endpoint identifies voters and applies access controls as the routes below do
+	var graphqlHandler http.Handler
+	if cfg.GraphQL.Enabled {
+		graphqlHandler, err = graphqlapi.NewHandler(pollService, graphqlapi.Options{
+			JWTSecret:            cfg.Auth.JWTSecret,
+			RequireAuthForCreate: cfg.Auth.RequireAuthForCreate,
+			Maintenance:          maintenanceMode,
+			Identify:             pollHandler.VoterIdentifier,
+			CheckVoter:           pollHandler.CheckVoterNetwork,
+		})
+		if err != nil {
+			logger.Fatal("Failed to load GraphQL schema", zap.Error(err))
+		}
+		logger.Info("Experimental GraphQL endpoint enabled")
+	}
+
+	// Health probes may be kept at fixed root paths for k8s
+	if cfg.HealthExcludeBasePath {
+		registerHealthRoutes(r, registry)
+	}
+
+	mountUnderBasePath(r, cfg.BasePath, func(r chi.Router) {
+		if !cfg.HealthExcludeBasePath {
+			registerHealthRoutes(r, registry)
+		}
+
+		r.Get("/openapi.json", specHandler) // Machine-readable API spec
+		r.Get("/docs", docs.UIHandler)      // Swagger UI
+
+		// Short share links resolve to their poll
+		if cfg.Share.Secret != "" {
+			r.With(timeout).Get("/s/{token}", pollHandler.OpenShareLink)
+		}
+
+		r.With(timeout).Get("/oembed", pollHandler.OEmbed) // Embed a poll's results chart in blogs and other pages
+
+		// Queries and mutations are both POSTed, so read-only mode is enforced by the mutations themselves
+		if graphqlHandler != nil {
+			r.With(timeout).Handle("/graphql", graphqlHandler)
+		}
+
+		// API v1 routes
+		r.Route("/api/v1", func(r chi.Router) {
+			r.Use(APIVersionMiddleware(supportedAPIVersions, cfg.RequireAPIVersion))
+
+			// Poll routes
+			r.Route("/polls", func(r chi.Router) {
+				r.Use(readOnly)
+
+				r.With(longTimeout).Get("/", pollHandler.ListPolls)                                      // List polls; large pages are streamed
+				r.With(handlers.PollIDMiddleware).Get("/{id}/results/stream", liveHandler.StreamResults) // Stream live results as Server-Sent Events
+
+				r.Group(func(r chi.Router) {
+					r.Use(timeout)
+
+					r.With(writeAuth...).Post("/", pollHandler.CreatePoll) // Create poll
+					r.Get("/batch", pollHandler.GetPollsBatch)             // Get several polls by ID
+					r.Get("/featured", pollHandler.ListFeaturedPolls)      // List featured polls in rank order
+					r.Get("/by-metadata", pollHandler.ListPollsByMetadata) // List polls by a metadata key/value
+
+					// Routes of a single poll parse {id} once; invalid IDs get a 400 before the handler runs
+					r.Group(func(r chi.Router) {
+						r.Use(handlers.PollIDMiddleware)
+
+						r.Get("/{id}", pollHandler.GetPoll)                               // Get poll with results
+						r.Get("/{id}/options", pollHandler.GetPollOptions)                // Get poll options only
+						r.Get("/{id}/voted", pollHandler.GetVoteStatus)                   // Check whether the requester has voted
+						r.Get("/{id}/status", pollHandler.GetVotingStatus)                // Check whether the poll accepts votes now
+						r.Get("/{id}/timeline", pollHandler.GetVoteTimeline)              // Get vote counts over time
+						r.Get("/{id}/related", pollHandler.GetRelatedPolls)               // Get the creator's other active polls
+						r.Get("/{id}/preview", pollHandler.PreviewVote)                   // Preview results with a hypothetical vote
+						r.Get("/{id}/results.prom", pollHandler.GetPollResultsPrometheus) // Get results for Prometheus scraping
+						r.Get("/{id}/chart.svg", pollHandler.GetPollResultsChart)         // Get results as an SVG bar chart
+						r.With(voteLimit...).Post("/{id}/vote", pollHandler.VoteOnPoll)   // Vote on poll
+						r.Post("/{id}/vote/confirm", pollHandler.ConfirmVote)             // Confirm a pending vote
+						r.Post("/{id}/vote/validate", pollHandler.ValidateVote)           // Check a vote without casting it
+
+						// Share links are only served when a signing secret is configured
+						if cfg.Share.Secret != "" {
+							r.Post("/{id}/share", pollHandler.SharePoll) // Create a signed share link
+						}
+
+						// Vote receipts are only verified when a signing secret is configured
+						if cfg.Receipt.Secret != "" {
+							r.Get("/{id}/receipt/verify", pollHandler.VerifyReceipt) // Verify a vote receipt
+						}
+					})
+
+					// Poll writes authenticate before the ID is checked
+					r.Group(func(r chi.Router) {
+						r.Use(writeAuth...)
+						r.Use(handlers.PollIDMiddleware)
+
+						r.Put("/{id}/options", pollHandler.UpdatePollOptions) // Edit option texts before voting starts
+						r.Put("/{id}/metadata", pollHandler.UpdateMetadata)   // Replace the poll's metadata
+						r.Delete("/{id}", pollHandler.DeletePoll)             // Delete poll
+					})
+				})
+			})
+
+			// The requester's own data; only served when tokens can be verified
+			if cfg.Auth.JWTSecret != "" {
+				r.Route("/me", func(r chi.Router) {
+					r.Use(AuthMiddleware(cfg.Auth.JWTSecret), timeout)
+
+					r.Get("/votes", pollHandler.ListMyVotes) // List the requester's votes across polls
+				})
+			}
+
+			// Poll template routes; managing templates and creating polls from them are poll writes
+			r.Route("/templates", func(r chi.Router) {
+				r.Use(readOnly, timeout)
+
+				r.With(writeAuth...).Post("/", templateHandler.CreateTemplate)                      // Create template
+				r.Get("/", templateHandler.ListTemplates)                                           // List templates
+				r.Get("/{id}", templateHandler.GetTemplate)                                         // Get template
+				r.With(writeAuth...).Put("/{id}", templateHandler.UpdateTemplate)                   // Replace template
+				r.With(writeAuth...).Delete("/{id}", templateHandler.DeleteTemplate)                // Delete template
+				r.With(writeAuth...).Post("/{id}/instantiate", templateHandler.InstantiateTemplate) // Create a poll from a template
+			})
+
+			// Admin routes
+			r.Route("/admin", func(r chi.Router) {
+				r.Use(AdminAuthMiddleware(cfg.Admin.APIKey))
 
-	// Health endpoints
+				r.With(readOnly, longTimeout, bulkBody, handlers.PollIDMiddleware).Post("/polls/{id}/votes/import", adminHandler.ImportVotes) // Import votes from CSV
+				r.With(longTimeout).Get("/backup", adminHandler.ExportBackup)                                                                 // Download a full backup
+				r.With(readOnly, longTimeout, bulkBody).Post("/backup", adminHandler.RestoreBackup)                                           // Restore a backup into an empty database
+
+				r.Group(func(r chi.Router) {
+					r.Use(timeout)
+
+					// Maintenance toggles stay writable so read-only mode can be turned off
+					r.Get("/read-only", maintenanceHandler.GetReadOnly) // Get read-only mode
+					r.Put("/read-only", maintenanceHandler.SetReadOnly) // Toggle read-only mode
+
+					r.Get("/config", configHandler.GetConfig) // Get sanitized boot configuration
+				})
+
+				r.Group(func(r chi.Router) {
+					r.Use(readOnly, timeout)
+
+					r.Post("/polls/close-expired", adminHandler.CloseExpiredPolls) // Deactivate expired polls
+					r.Post("/polls/bulk-delete", adminHandler.BulkDeletePolls)     // Delete every poll matching a filter
+
+					// Routes of a single poll parse {id} once, as the public poll routes do
+					r.Group(func(r chi.Router) {
+						r.Use(handlers.PollIDMiddleware)
+
+						r.Post("/polls/{id}/expire", adminHandler.ExpirePoll)            // Expire a poll now
+						r.Delete("/polls/{id}", adminHandler.DeletePoll)                 // Delete a poll, even one protected by its votes
+						r.Patch("/polls/{id}/featured", adminHandler.SetFeatured)        // Pin a poll to, or remove it from, the featured listing
+						r.Delete("/polls/{id}/votes/{voterID}", adminHandler.RemoveVote) // Remove a single vote
+
+						// Allowlist management for allowlist-only polls
+						r.Get("/polls/{id}/allowed-voters", adminHandler.ListAllowedVoters)               // List allowed voters
+						r.Post("/polls/{id}/allowed-voters", adminHandler.AddAllowedVoters)               // Add allowed voters
+						r.Delete("/polls/{id}/allowed-voters/{voterID}", adminHandler.RemoveAllowedVoter) // Remove an allowed voter
+
+						r.Get("/polls/{id}/suspicious", adminHandler.GetSuspiciousVotes) // Report suspicious subnet clusters and vote bursts
+						r.Get("/polls/{id}/vote-rate", adminHandler.GetVoteRate)         // Report the peak vote rate for capacity planning
+
+						// Webhook management
+						r.Post("/polls/{id}/webhooks", webhookHandler.CreateWebhook)               // Register webhook
+						r.Get("/polls/{id}/webhooks", webhookHandler.ListWebhooks)                 // List webhooks
+						r.Delete("/polls/{id}/webhooks/{webhookID}", webhookHandler.DeleteWebhook) // Delete webhook
+					})
+				})
+			})
+		})
+	})
+
+	if cfg.BasePath != "" {
+		logger.Info("Routes mounted under base path",
+			zap.String("base_path", cfg.BasePath),
+			zap.Bool("health_exclude_base_path", cfg.HealthExcludeBasePath),
+		)
+	}
+
+	// The gRPC API enforces the same access controls as the routes above
+	var grpcServer *grpc.Server
+	if cfg.GRPC.Enabled {
+		grpcServer = grpcapi.NewGRPCServer(pollService, grpcapi.Options{
+			JWTSecret:            cfg.Auth.JWTSecret,
+			RequireAuthForCreate: cfg.Auth.RequireAuthForCreate,
+			Maintenance:          maintenanceMode,
+			BlockedNetworks:      voteBlocklist,
+		})
+	}
+
+	return r, grpcServer
+}
+
+// mountUnderBasePath registers routes under basePath, or directly on the root router when it is empty
+func mountUnderBasePath(r chi.Router, basePath string, fn func(r chi.Router)) {
+	if basePath == "" {
+		fn(r)
+		return
+	}
+	r.Route(basePath, fn)
+}
+
+// healthPaths returns the paths registerHealthRoutes serves under the configured base path
+func healthPaths(cfg *config.Config) []string {
+	prefix := cfg.BasePath
+	if cfg.HealthExcludeBasePath {
+		prefix = ""
+	}
+	return []string{prefix + "/health", prefix + "/live", prefix + "/ready"}
+}
+
+// inFlightExempt reports whether a request bypasses the in-flight limit: health probes, so k8s
+// never sees a 503 under load, and live result streams, which stay open while idle
+func inFlightExempt(cfg *config.Config) func(r *http.Request) bool {
+	probes := make(map[string]bool)
+	for _, path := range healthPaths(cfg) {
+		probes[path] = true
+	}
+	return func(r *http.Request) bool {
+		return probes[r.URL.Path] || strings.HasSuffix(r.URL.Path, "/results/stream")
+	}
+}
+
+// bulkUpload reports whether a request is a bulk upload, a CSV vote import or a backup restore,
+// which is exempt from the general body limit and bounded by MAX_BULK_BODY_BYTES on its route instead
+func bulkUpload(r *http.Request) bool {
+	if r.Method != http.MethodPost {
+		return false
+	}
+	path := r.URL.Path
+	return strings.HasSuffix(path, "/admin/backup") ||
+		(strings.Contains(path, "/admin/polls/") && strings.HasSuffix(path, "/votes/import"))
+}
+
+// logExcludedPaths returns the configured log exclusions, both as given and under the base path,
+// so defaults such as /live match wherever the probes are mounted
+func logExcludedPaths(cfg *config.Config) []string {
+	paths := make([]string, 0, 2*len(cfg.Log.ExcludePaths))
+	for _, path := range cfg.Log.ExcludePaths {
+		paths = append(paths, path)
+		if cfg.BasePath != "" {
+			paths = append(paths, cfg.BasePath+path)
+		}
+	}
+	return paths
+}
+
+// loadGeoResolver loads the region table used to tag request logs, or returns nil when none is configured
+// A table that fails to load disables region logging rather than blocking startup
+func loadGeoResolver(path string) geoip.Resolver {
+	if path == "" {
+		return nil
+	}
+	table, err := geoip.LoadFile(path)
+	if err != nil {
+		logger.Warn("Region logging disabled", zap.Error(err))
+		return nil
+	}
+	logger.Info("Region logging enabled", zap.String("geoip_file", path))
+	return table
+}
+
+// loadVoteBlocklist loads the network ranges whose votes are rejected, or returns nil when none is configured
+// Unlike the region table, a blocklist that fails to load stops startup rather than silently accepting every vote
+func loadVoteBlocklist(path string) *netblock.List {
+	if path == "" {
+		return nil
+	}
+	list, err := netblock.LoadFile(path)
+	if err != nil {
+		logger.Fatal("Failed to load vote blocklist", zap.Error(err))
+	}
+	logger.Info("Vote blocklist enabled",
+		zap.String("vote_blocklist_file", path),
+		zap.Int("ranges", list.Len()),
+	)
+	return list
+}
+
+// newPollRepository returns the poll store selected by REPO_BACKEND
+// Only a database store announces vote milestones, as they are published through its outbox,
+// and only it can derive vote counts from the vote tables
+func newPollRepository(cfg *config.Config, conn database.Conn) repository.PollRepositoryInterface {
+	order := listOrder(cfg.Poll.ListSortDirection)
+	if cfg.RepoBackend == config.RepoBackendMemory {
+		return repository.NewInMemoryPollRepository().WithListOrder(order)
+	}
+	return repository.NewPollRepository(conn).
+		WithListOrder(order).
+		WithDialect(sqlDialect(cfg.DB.Driver)).
+		WithMilestones(cfg.Poll.VoteMilestones).
+		WithCountMode(countMode(cfg.Poll.VoteCounting))
+}
+
+// countMode maps VOTE_COUNTING to the repository's count mode
+func countMode(counting string) repository.CountMode {
+	if counting == config.VoteCountingDerived {
+		return repository.DerivedCounts
+	}
+	return repository.IncrementedCounts
+}
+
+// sqlDialect maps DB_DRIVER to the repository's SQL dialect
+func sqlDialect(driver string) repository.Dialect {
+	if driver == config.DBDriverSQLite {
+		return repository.SQLiteDialect
+	}
+	return repository.PostgresDialect
+}
+
+// listOrder maps LIST_SORT_DIRECTION to the repository's listing order
+func listOrder(direction string) repository.ListOrder {
+	if direction == config.SortDirectionAsc {
+		return repository.OldestFirst
+	}
+	return repository.NewestFirst
+}
+
+// registerHealthRoutes registers the health and k8s probe endpoints, and the metrics scraped alongside them
+func registerHealthRoutes(r chi.Router, registry *metrics.Registry) {
 	r.Get("/health", handlers.Health)
 	r.Get("/live", handlers.LivenessProbe)
 	r.Get("/ready", handlers.ReadinessProbe)
+	r.Get("/metrics", registry.Handler())
+}
 
-	// Initialize poll dependencies
-	pollRepo := repository.NewPollRepository(db)
-	pollService := service.NewPollService(pollRepo)
-	pollHandler := handlers.NewPollHandler(pollService)
-
-	// API v1 routes
-	r.Route("/api/v1", func(r chi.Router) {
-		// Poll routes
-		r.Route("/polls", func(r chi.Router) {
-			r.Post("/", pollHandler.CreatePoll)          // Create poll
-			r.Get("/", pollHandler.ListPolls)            // List polls
-			r.Get("/{id}", pollHandler.GetPoll)          // Get poll with results
-			r.Post("/{id}/vote", pollHandler.VoteOnPoll) // Vote on poll
-			r.Delete("/{id}", pollHandler.DeletePoll)    // Delete poll
-		})
-	})
+// LoggingMiddleware logs incoming requests, except those to excludePaths (e.g. health probes)
+// When geo is set, lines carry the client's region; addresses it cannot resolve are logged without one
+func LoggingMiddleware(geo geoip.Resolver, excludePaths ...string) func(http.Handler) http.Handler {
+	excluded := make(map[string]bool, len(excludePaths))
+	for _, path := range excludePaths {
+		excluded[path] = true
+	}
 
-	return r
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if excluded[r.URL.Path] {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			fields := []zap.Field{
+				zap.String("request_id", middleware.GetReqID(r.Context())),
+				zap.String("method", r.Method),
+				zap.String("path", r.URL.Path),
+				zap.String("remote_addr", r.RemoteAddr),
+				zap.String("user_agent", r.UserAgent()),
+			}
+			if region, ok := clientRegion(geo, r); ok {
+				fields = append(fields, zap.String("region", region))
+			}
+			logger.Info("Incoming request", fields...)
+			next.ServeHTTP(w, r)
+		})
+	}
 }
 
-// LoggingMiddleware logs incoming requests
-func LoggingMiddleware(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		logger.Info("Incoming request",
-			zap.String("method", r.Method),
-			zap.String("path", r.URL.Path),
-			zap.String("remote_addr", r.RemoteAddr),
-			zap.String("user_agent", r.UserAgent()),
-		)
-		next.ServeHTTP(w, r)
-	})
+// clientRegion resolves the request's client address with geo, if any
+func clientRegion(geo geoip.Resolver, r *http.Request) (string, bool) {
+	if geo == nil {
+		return "", false
+	}
+	addr, err := netip.ParseAddr(handlers.ClientAddr(r))
+	if err != nil {
+		return "", false
+	}
+	return geo.Region(addr)
 }
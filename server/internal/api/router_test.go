@@ -0,0 +1,386 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/moabdelazem/k8s-app/internal/config"
+	"github.com/moabdelazem/k8s-app/internal/geoip"
+	"github.com/moabdelazem/k8s-app/pkg/auth"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+)
+
+// newTestConfig returns a minimal configuration for router tests
+func newTestConfig() *config.Config {
+	return &config.Config{
+		Addr: ":0",
+		Env:  "test",
+		CORS: config.CORSConfig{
+			AllowedOrigins: []string{"*"},
+			AllowedMethods: []string{"GET", "POST", "DELETE"},
+		},
+	}
+}
+
+func serve(t *testing.T, h http.Handler, method, path string) *httptest.ResponseRecorder {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(method, path, nil))
+	return rec
+}
+
+func TestSetupRoutes_BasePath(t *testing.T) {
+	cfg := newTestConfig()
+	cfg.BasePath = "/polls-service"
+
+	router := SetupRoutes(context.Background(), nil, cfg)
+
+	assert.Equal(t, http.StatusOK, serve(t, router, http.MethodGet, "/polls-service/live").Code)
+	assert.Equal(t, http.StatusNotFound, serve(t, router, http.MethodGet, "/live").Code)
+
+	// Invalid IDs are rejected by the handler, proving the API is mounted under the prefix
+	assert.Equal(t, http.StatusBadRequest, serve(t, router, http.MethodGet, "/polls-service/api/v1/polls/not-a-uuid").Code)
+	assert.Equal(t, http.StatusNotFound, serve(t, router, http.MethodGet, "/api/v1/polls/not-a-uuid").Code)
+}
+
+func TestSetupRoutes_BasePathExcludingHealth(t *testing.T) {
+	cfg := newTestConfig()
+	cfg.BasePath = "/polls-service"
+	cfg.HealthExcludeBasePath = true
+
+	router := SetupRoutes(context.Background(), nil, cfg)
+
+	assert.Equal(t, http.StatusOK, serve(t, router, http.MethodGet, "/live").Code)
+	assert.Equal(t, http.StatusNotFound, serve(t, router, http.MethodGet, "/polls-service/live").Code)
+	assert.Equal(t, http.StatusBadRequest, serve(t, router, http.MethodGet, "/polls-service/api/v1/polls/not-a-uuid").Code)
+}
+
+func TestSetupRoutes_MetricsFollowHealthRoutes(t *testing.T) {
+	cfg := newTestConfig()
+	cfg.BasePath = "/polls-service"
+
+	rec := serve(t, SetupRoutes(context.Background(), nil, cfg), http.MethodGet, "/polls-service/metrics")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "text/plain; version=0.0.4; charset=utf-8", rec.Header().Get("Content-Type"))
+
+	cfg.HealthExcludeBasePath = true
+	assert.Equal(t, http.StatusOK, serve(t, SetupRoutes(context.Background(), nil, cfg), http.MethodGet, "/metrics").Code)
+}
+
+func TestSetupRoutes_NoBasePath(t *testing.T) {
+	router := SetupRoutes(context.Background(), nil, newTestConfig())
+
+	assert.Equal(t, http.StatusOK, serve(t, router, http.MethodGet, "/live").Code)
+	assert.Equal(t, http.StatusBadRequest, serve(t, router, http.MethodGet, "/api/v1/polls/not-a-uuid").Code)
+}
+
+func TestSetupRoutes_RequireAuthForCreate(t *testing.T) {
+	cfg := newTestConfig()
+	cfg.Auth = config.AuthConfig{JWTSecret: "test-secret", RequireAuthForCreate: true}
+
+	router := SetupRoutes(context.Background(), nil, cfg)
+
+	createPoll := func(token string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/polls", strings.NewReader("{"))
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	assert.Equal(t, http.StatusUnauthorized, createPoll("").Code)
+	assert.Equal(t, http.StatusUnauthorized, createPoll("not-a-jwt").Code)
+
+	// A valid token reaches the handler, which rejects the malformed body
+	token, err := auth.SignToken("test-secret", auth.Claims{Subject: "user-1", ExpiresAt: time.Now().Add(time.Hour).Unix()})
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, createPoll(token).Code)
+
+	// Reads and deletes follow the same split: reads stay public, deletes need a token
+	assert.Equal(t, http.StatusBadRequest, serve(t, router, http.MethodGet, "/api/v1/polls/not-a-uuid").Code)
+	assert.Equal(t, http.StatusUnauthorized, serve(t, router, http.MethodDelete, "/api/v1/polls/not-a-uuid").Code)
+}
+
+func TestSetupRoutes_AnonymousCreateAllowedByDefault(t *testing.T) {
+	router := SetupRoutes(context.Background(), nil, newTestConfig())
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/v1/polls", strings.NewReader("{")))
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestSetupRoutes_ReadOnlyToggle(t *testing.T) {
+	cfg := newTestConfig()
+	cfg.ReadOnly = true
+	cfg.Admin.APIKey = "admin-key"
+
+	router := SetupRoutes(context.Background(), nil, cfg)
+
+	adminRequest := func(method, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, "/api/v1/admin/read-only", strings.NewReader(body))
+		req.Header.Set("X-Admin-Key", "admin-key")
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+	createPoll := func() int {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/v1/polls", strings.NewReader("{")))
+		return rec.Code
+	}
+
+	assert.Equal(t, http.StatusServiceUnavailable, createPoll())
+	assert.Contains(t, adminRequest(http.MethodGet, "").Body.String(), `"read_only":true`)
+
+	// The toggle itself is never blocked by read-only mode
+	assert.Equal(t, http.StatusOK, adminRequest(http.MethodPut, `{"enabled":false}`).Code)
+	assert.Equal(t, http.StatusBadRequest, createPoll())
+
+	assert.Equal(t, http.StatusBadRequest, adminRequest(http.MethodPut, `{}`).Code)
+}
+
+func TestSetupRoutes_BatchIsNotAPollID(t *testing.T) {
+	router := SetupRoutes(context.Background(), nil, newTestConfig())
+
+	rec := serve(t, router, http.MethodGet, "/api/v1/polls/batch?id=nope")
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), "Invalid poll ID: nope")
+}
+
+func TestSetupRoutes_BulkUploadsHaveTheirOwnBodyLimit(t *testing.T) {
+	cfg := newTestConfig()
+	cfg.Admin.APIKey = "admin-key"
+	cfg.Body.MaxBytes = 16
+	cfg.Body.BulkMaxBytes = 64
+
+	router := SetupRoutes(context.Background(), nil, cfg)
+
+	send := func(method, path string, size int64) int {
+		req := httptest.NewRequest(method, path, strings.NewReader(strings.Repeat("x", int(size))))
+		req.Header.Set("X-Admin-Key", "admin-key")
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	// A CSV import above the general limit reaches the handler, which rejects the poll ID
+	assert.Equal(t, http.StatusBadRequest, send(http.MethodPost, "/api/v1/admin/polls/nope/votes/import", 32))
+	assert.Equal(t, http.StatusRequestEntityTooLarge, send(http.MethodPost, "/api/v1/admin/polls/nope/votes/import", 65))
+	assert.Equal(t, http.StatusRequestEntityTooLarge, send(http.MethodPost, "/api/v1/admin/backup", 65))
+
+	// Other writes keep the general limit, PATCH included
+	assert.Equal(t, http.StatusRequestEntityTooLarge, send(http.MethodPost, "/api/v1/polls", 32))
+	assert.Equal(t, http.StatusRequestEntityTooLarge, send(http.MethodPatch, "/api/v1/admin/polls/nope/featured", 32))
+}
+
+func TestSetupRoutes_AdminRoutesParsePollIDs(t *testing.T) {
+	cfg := newTestConfig()
+	cfg.Admin.APIKey = "admin-key"
+
+	router := SetupRoutes(context.Background(), nil, cfg)
+
+	routes := []struct{ method, path string }{
+		{http.MethodPost, "/api/v1/admin/polls/nope/expire"},
+		{http.MethodDelete, "/api/v1/admin/polls/nope"},
+		{http.MethodPatch, "/api/v1/admin/polls/nope/featured"},
+		{http.MethodPost, "/api/v1/admin/polls/nope/votes/import"},
+		{http.MethodDelete, "/api/v1/admin/polls/nope/votes/voter-1"},
+		{http.MethodGet, "/api/v1/admin/polls/nope/allowed-voters"},
+		{http.MethodGet, "/api/v1/admin/polls/nope/vote-rate"},
+		{http.MethodPost, "/api/v1/admin/polls/nope/webhooks"},
+		{http.MethodDelete, "/api/v1/admin/polls/nope/webhooks/" + uuid.NewString()},
+	}
+	for _, route := range routes {
+		req := httptest.NewRequest(route.method, route.path, strings.NewReader("{}"))
+		req.Header.Set("X-Admin-Key", "admin-key")
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code, route.path)
+		assert.Contains(t, rec.Body.String(), `"Invalid poll ID"`, route.path)
+	}
+}
+
+func TestSetupRoutes_ConfigRequiresAdmin(t *testing.T) {
+	cfg := newTestConfig()
+	cfg.Admin.APIKey = "admin-key"
+	cfg.DB.Password = "hunter2"
+
+	router := SetupRoutes(context.Background(), nil, cfg)
+
+	rec := serve(t, router, http.MethodGet, "/api/v1/admin/config")
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/admin/config", nil)
+	req.Header.Set("X-Admin-Key", "admin-key")
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.NotContains(t, rec.Body.String(), "hunter2")
+	assert.NotContains(t, rec.Body.String(), "admin-key")
+}
+
+func TestSetupRoutes_GlobalRateLimitExemptsHealth(t *testing.T) {
+	cfg := newTestConfig()
+	cfg.BasePath = "/polls-service"
+	cfg.RateLimit.Requests = 1
+	cfg.RateLimit.Window = time.Minute
+
+	router := SetupRoutes(context.Background(), nil, cfg)
+
+	assert.Equal(t, http.StatusOK, serve(t, router, http.MethodGet, "/polls-service/openapi.json").Code)
+	assert.Equal(t, http.StatusTooManyRequests, serve(t, router, http.MethodGet, "/polls-service/openapi.json").Code)
+	assert.Equal(t, http.StatusOK, serve(t, router, http.MethodGet, "/polls-service/live").Code)
+}
+
+func TestSetupRoutes_ShareLinksRequireSecret(t *testing.T) {
+	disabled := SetupRoutes(context.Background(), nil, newTestConfig())
+	assert.Equal(t, http.StatusNotFound, serve(t, disabled, http.MethodGet, "/s/some-token").Code)
+	assert.Equal(t, http.StatusNotFound, serve(t, disabled, http.MethodPost, "/api/v1/polls/nope/share").Code)
+
+	cfg := newTestConfig()
+	cfg.Share.Secret = "share-secret"
+	cfg.Share.TTL = time.Hour
+	enabled := SetupRoutes(context.Background(), nil, cfg)
+
+	assert.Equal(t, http.StatusBadRequest, serve(t, enabled, http.MethodGet, "/s/some-token").Code)
+	assert.Equal(t, http.StatusBadRequest, serve(t, enabled, http.MethodPost, "/api/v1/polls/nope/share").Code)
+}
+
+// stubGeoResolver resolves a fixed set of addresses
+type stubGeoResolver map[string]string
+
+func (s stubGeoResolver) Region(addr netip.Addr) (string, bool) {
+	region, ok := s[addr.String()]
+	return region, ok
+}
+
+func TestLoggingMiddleware_Region(t *testing.T) {
+	geo := stubGeoResolver{"203.0.113.7": "AU"}
+
+	tests := []struct {
+		name       string
+		geo        stubGeoResolver
+		remote     string
+		wantRegion string
+	}{
+		{name: "resolved address", geo: geo, remote: "203.0.113.7:4000", wantRegion: "AU"},
+		{name: "unresolved address", geo: geo, remote: "198.51.100.1:4000"},
+		{name: "unparsable address", geo: geo, remote: "not-an-ip"},
+		{name: "no resolver configured", remote: "203.0.113.7:4000"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logs := observeLogs(t, zapcore.InfoLevel)
+
+			var resolver geoip.Resolver
+			if tt.geo != nil {
+				resolver = tt.geo
+			}
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/polls", nil)
+			req.RemoteAddr = tt.remote
+			LoggingMiddleware(resolver)(okHandler).ServeHTTP(httptest.NewRecorder(), req)
+
+			entries := logs.FilterMessage("Incoming request").All()
+			require.Len(t, entries, 1)
+			region, ok := entries[0].ContextMap()["region"]
+			if tt.wantRegion == "" {
+				assert.False(t, ok, "region should be omitted")
+			} else {
+				assert.Equal(t, tt.wantRegion, region)
+			}
+		})
+	}
+}
+
+func TestLoggingMiddleware_ExcludedPaths(t *testing.T) {
+	tests := []struct {
+		path    string
+		wantLog bool
+	}{
+		{path: "/live", wantLog: false},
+		{path: "/ready", wantLog: false},
+		{path: "/health", wantLog: true},
+		{path: "/api/v1/polls", wantLog: true},
+		{path: "/live/extra", wantLog: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			logs := observeLogs(t, zapcore.InfoLevel)
+
+			rec := serve(t, LoggingMiddleware(nil, "/live", "/ready")(okHandler), http.MethodGet, tt.path)
+
+			assert.Equal(t, http.StatusOK, rec.Code)
+			if tt.wantLog {
+				assert.Equal(t, 1, logs.FilterMessage("Incoming request").Len())
+			} else {
+				assert.Zero(t, logs.FilterMessage("Incoming request").Len())
+			}
+		})
+	}
+}
+
+func TestSetupRoutes_LogExclusionsFollowBasePath(t *testing.T) {
+	cfg := newTestConfig()
+	cfg.BasePath = "/polls-service"
+	cfg.Log.ExcludePaths = []string{"/live"}
+	h := SetupRoutes(context.Background(), nil, cfg)
+
+	logs := observeLogs(t, zapcore.InfoLevel)
+	serve(t, h, http.MethodGet, "/polls-service/live")
+	assert.Zero(t, logs.FilterMessage("Incoming request").Len())
+
+	serve(t, h, http.MethodGet, "/polls-service/health")
+	assert.Equal(t, 1, logs.FilterMessage("Incoming request").Len())
+}
+
+func TestSetupRoutes_MemoryBackend(t *testing.T) {
+	cfg := newTestConfig()
+	cfg.RepoBackend = config.RepoBackendMemory
+
+	// No database is passed; polls are served from memory
+	router := SetupRoutes(context.Background(), nil, cfg)
+
+	rec := httptest.NewRecorder()
+	body := `{"question": "Tabs or spaces?", "options": ["Tabs", "Spaces"]}`
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/v1/polls", strings.NewReader(body)))
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	assert.Contains(t, rec.Body.String(), "Tabs or spaces?")
+
+	rec = serve(t, router, http.MethodGet, "/api/v1/polls")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), "Tabs or spaces?")
+}
+
+func TestSetupRoutes_GraphQLBehindFlag(t *testing.T) {
+	query := `{"query": "{ polls { total } }"}`
+
+	cfg := newTestConfig()
+	cfg.RepoBackend = config.RepoBackendMemory
+	router := SetupRoutes(context.Background(), nil, cfg)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(query)))
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	cfg.GraphQL.Enabled = true
+	router = SetupRoutes(context.Background(), nil, cfg)
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(query)))
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.JSONEq(t, `{"data": {"polls": {"total": 0}}}`, rec.Body.String())
+}
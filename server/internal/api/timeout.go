@@ -0,0 +1,20 @@
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+// TimeoutMiddleware puts a deadline of timeout on the request context; 0 means no deadline.
+// Repository queries run with the request context, so a query still running at the deadline
+// is canceled and the handler answers 503 like any transient database failure. A handler
+// that returns past the deadline without responding gets chi's 504.
+// Deadlines only shorten: a route wrapped twice keeps the earlier one.
+func TimeoutMiddleware(timeout time.Duration) func(http.Handler) http.Handler {
+	if timeout <= 0 {
+		return func(next http.Handler) http.Handler { return next }
+	}
+	return middleware.Timeout(timeout)
+}
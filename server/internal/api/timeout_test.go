@@ -0,0 +1,54 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTimeoutMiddleware_SlowHandlerTimesOut(t *testing.T) {
+	var handlerErr error
+	slow := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Stands in for a query that outlives the deadline
+		select {
+		case <-r.Context().Done():
+			handlerErr = r.Context().Err()
+		case <-time.After(time.Second):
+			w.WriteHeader(http.StatusOK)
+		}
+	})
+
+	start := time.Now()
+	rec := serve(t, TimeoutMiddleware(20*time.Millisecond)(slow), http.MethodGet, "/api/v1/polls")
+
+	assert.Equal(t, http.StatusGatewayTimeout, rec.Code)
+	assert.ErrorIs(t, handlerErr, context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), time.Second)
+}
+
+func TestTimeoutMiddleware_FastHandlerUnaffected(t *testing.T) {
+	rec := serve(t, TimeoutMiddleware(time.Second)(okHandler), http.MethodGet, "/api/v1/polls")
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestTimeoutMiddleware_Deadlines(t *testing.T) {
+	var deadline time.Time
+	var hasDeadline bool
+	record := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		deadline, hasDeadline = r.Context().Deadline()
+	})
+
+	serve(t, TimeoutMiddleware(0)(record), http.MethodGet, "/api/v1/polls/1/results/stream")
+	assert.False(t, hasDeadline, "a zero timeout sets no deadline")
+
+	// A longer timeout nested inside a shorter one cannot extend the request
+	start := time.Now()
+	serve(t, TimeoutMiddleware(time.Second)(TimeoutMiddleware(time.Hour)(record)), http.MethodGet, "/api/v1/polls")
+	require.True(t, hasDeadline)
+	assert.WithinDuration(t, start.Add(time.Second), deadline, 100*time.Millisecond)
+}
@@ -0,0 +1,67 @@
+// Package archive periodically moves long-closed polls out of the live tables.
+package archive
+
+import (
+	"context"
+	"time"
+
+	"github.com/moabdelazem/k8s-app/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// Archiver moves closed polls past their retention to the archive, returning how many it moved
+type Archiver interface {
+	ArchiveClosedPolls(ctx context.Context) (int64, error)
+}
+
+// Config represents archival job configuration
+type Config struct {
+	Interval time.Duration // Delay between archival runs
+}
+
+// Job runs an Archiver on a fixed interval
+type Job struct {
+	archiver Archiver
+	cfg      Config
+}
+
+// NewJob creates a job running archiver every cfg.Interval
+func NewJob(archiver Archiver, cfg Config) *Job {
+	// Set default values if not provided
+	if cfg.Interval <= 0 {
+		cfg.Interval = time.Hour
+	}
+
+	return &Job{archiver: archiver, cfg: cfg}
+}
+
+// Start runs the archiver immediately and then every interval; it stops when ctx is canceled.
+// A run interrupted by shutdown rolls back, leaving its polls for the next start.
+func (j *Job) Start(ctx context.Context) {
+	go j.run(ctx)
+
+	logger.Info("Poll archival started", zap.Duration("interval", j.cfg.Interval))
+}
+
+func (j *Job) run(ctx context.Context) {
+	j.archive(ctx)
+
+	ticker := time.NewTicker(j.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			j.archive(ctx)
+		}
+	}
+}
+
+// archive performs one run; failures are retried on the next tick
+func (j *Job) archive(ctx context.Context) {
+	if _, err := j.archiver.ArchiveClosedPolls(ctx); err != nil && ctx.Err() == nil {
+		logger.Warn("Poll archival incomplete, retrying on the next run", zap.Error(err))
+	}
+}
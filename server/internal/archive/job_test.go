@@ -0,0 +1,56 @@
+package archive
+
+import (
+	"context"
+	"errors"
+	"os"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/moabdelazem/k8s-app/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+// TestMain installs the logger up front; the jobs' goroutines outlive each test and log from the background
+func TestMain(m *testing.M) {
+	logger.Log = zap.NewNop()
+	os.Exit(m.Run())
+}
+
+// countingArchiver counts its runs and fails each of them with err
+type countingArchiver struct {
+	runs atomic.Int64
+	err  error
+}
+
+func (a *countingArchiver) ArchiveClosedPolls(context.Context) (int64, error) {
+	a.runs.Add(1)
+	return 0, a.err
+}
+
+func TestJob_RunsOnStartAndEveryInterval(t *testing.T) {
+	archiver := &countingArchiver{err: errors.New("database unavailable")}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// A failing run is retried on the next tick
+	NewJob(archiver, Config{Interval: 10 * time.Millisecond}).Start(ctx)
+
+	assert.Eventually(t, func() bool { return archiver.runs.Load() >= 3 }, time.Second, 5*time.Millisecond)
+}
+
+func TestJob_StopsOnShutdown(t *testing.T) {
+	archiver := &countingArchiver{}
+	ctx, cancel := context.WithCancel(context.Background())
+
+	NewJob(archiver, Config{Interval: 10 * time.Millisecond}).Start(ctx)
+	assert.Eventually(t, func() bool { return archiver.runs.Load() >= 1 }, time.Second, 5*time.Millisecond)
+
+	cancel()
+	time.Sleep(20 * time.Millisecond) // Let a tick racing the cancel finish
+	runs := archiver.runs.Load()
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, runs, archiver.runs.Load())
+}
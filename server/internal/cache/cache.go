@@ -0,0 +1,137 @@
+// Package cache holds short-lived values in memory, loading each missing key at most
+// once at a time so a burst of requests for a cold key reaches the backing store once.
+package cache
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// Cache maps keys to values that expire after a fixed TTL.
+// Concurrent misses for the same key share a single load (single-flight): the first
+// caller runs the loader and the others wait for its result. Failed loads are not cached,
+// and a loader that panics panics in every caller sharing its load.
+// Expired values are swept at most once per TTL, so keys that are never read again do not pile up.
+type Cache[K comparable, V any] struct {
+	ttl time.Duration
+	now func() time.Time
+
+	group singleflight.Group
+
+	mu          sync.Mutex
+	entries     map[K]entry[V]
+	generations map[K]uint64 // Bumped by Invalidate, so loads of the key overlapping one are not stored
+	loading     map[K]int    // Loads in progress per key, whose generations the sweep keeps
+	lastSweep   time.Time
+}
+
+type entry[V any] struct {
+	value     V
+	expiresAt time.Time
+}
+
+// New returns a cache whose values expire ttl after they are loaded, as told by now
+func New[K comparable, V any](ttl time.Duration, now func() time.Time) *Cache[K, V] {
+	return &Cache[K, V]{
+		ttl:         ttl,
+		now:         now,
+		entries:     make(map[K]entry[V]),
+		generations: make(map[K]uint64),
+		loading:     make(map[K]int),
+		lastSweep:   now(),
+	}
+}
+
+// Get returns the cached value for key, calling load when it is missing or expired.
+// Callers sharing a load get the same value, so values must be treated as read-only.
+// Keys share a load when they format alike with %v, as they do for the IDs used as keys.
+func (c *Cache[K, V]) Get(key K, load func() (V, error)) (V, error) {
+	c.mu.Lock()
+	now := c.now()
+	c.sweep(now)
+	if e, ok := c.entries[key]; ok && now.Before(e.expiresAt) {
+		c.mu.Unlock()
+		return e.value, nil
+	}
+	c.mu.Unlock()
+
+	v, err, _ := c.group.Do(fmt.Sprint(key), func() (any, error) {
+		generation := c.startLoad(key)
+		defer c.endLoad(key)
+
+		value, err := load()
+		if err != nil {
+			return value, err
+		}
+
+		c.mu.Lock()
+		if c.generations[key] == generation {
+			c.entries[key] = entry[V]{value: value, expiresAt: c.now().Add(c.ttl)}
+		}
+		c.mu.Unlock()
+		return value, nil
+	})
+	value, _ := v.(V)
+	return value, err
+}
+
+// Invalidate drops the cached value for key; a load already in progress is not stored,
+// and later callers start a new load rather than waiting for it
+func (c *Cache[K, V]) Invalidate(key K) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.entries, key)
+	c.generations[key]++
+	c.group.Forget(fmt.Sprint(key))
+}
+
+// startLoad registers a load of key and returns the key's generation as it starts
+func (c *Cache[K, V]) startLoad(key K) uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.loading[key]++
+	return c.generations[key]
+}
+
+// endLoad unregisters a load of key, whether it returned or panicked
+func (c *Cache[K, V]) endLoad(key K) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.loading[key] <= 1 {
+		delete(c.loading, key)
+		return
+	}
+	c.loading[key]--
+}
+
+// Len returns the number of cached values, including expired ones not yet swept
+func (c *Cache[K, V]) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.entries)
+}
+
+// sweep evicts expired values, at most once per TTL; c.mu must be held
+// Generations only matter to loads in progress, so those of keys not being loaded are dropped too.
+func (c *Cache[K, V]) sweep(now time.Time) {
+	if now.Sub(c.lastSweep) < c.ttl {
+		return
+	}
+	for key, e := range c.entries {
+		if !now.Before(e.expiresAt) {
+			delete(c.entries, key)
+		}
+	}
+	for key := range c.generations {
+		if c.loading[key] == 0 {
+			delete(c.generations, key)
+		}
+	}
+	c.lastSweep = now
+}
@@ -0,0 +1,196 @@
+package cache
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testClock is a manually advanced clock
+type testClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *testClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *testClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+func newTestCache() (*Cache[string, int], *testClock) {
+	clock := &testClock{now: time.Date(2025, time.June, 1, 12, 0, 0, 0, time.UTC)}
+	return New[string, int](time.Second, clock.Now), clock
+}
+
+func TestCache_ConcurrentMissesLoadOnce(t *testing.T) {
+	c, _ := newTestCache()
+
+	var loads atomic.Int32
+	release := make(chan struct{})
+	load := func() (int, error) {
+		loads.Add(1)
+		<-release // Hold the load open until every caller is waiting on it
+		return 42, nil
+	}
+
+	const callers = 50
+	var started, finished sync.WaitGroup
+	results := make([]int, callers)
+	started.Add(callers)
+	finished.Add(callers)
+	for i := range callers {
+		go func() {
+			defer finished.Done()
+			started.Done()
+			value, err := c.Get("poll", load)
+			assert.NoError(t, err)
+			results[i] = value
+		}()
+	}
+
+	started.Wait()
+	// Give the callers time to reach the in-flight load before it completes
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	finished.Wait()
+
+	assert.Equal(t, int32(1), loads.Load())
+	for _, value := range results {
+		assert.Equal(t, 42, value)
+	}
+}
+
+func TestCache_ExpiresAfterTTL(t *testing.T) {
+	c, clock := newTestCache()
+	loads := 0
+	load := func() (int, error) {
+		loads++
+		return loads, nil
+	}
+
+	first, _ := c.Get("poll", load)
+	clock.Advance(999 * time.Millisecond)
+	cached, _ := c.Get("poll", load)
+	clock.Advance(time.Millisecond)
+	reloaded, _ := c.Get("poll", load)
+
+	assert.Equal(t, 1, first)
+	assert.Equal(t, 1, cached)
+	assert.Equal(t, 2, reloaded)
+}
+
+func TestCache_DoesNotCacheErrors(t *testing.T) {
+	c, _ := newTestCache()
+
+	_, err := c.Get("poll", func() (int, error) { return 0, errors.New("db down") })
+	require.Error(t, err)
+	assert.Zero(t, c.Len())
+
+	value, err := c.Get("poll", func() (int, error) { return 7, nil })
+	require.NoError(t, err)
+	assert.Equal(t, 7, value)
+}
+
+func TestCache_Invalidate(t *testing.T) {
+	c, _ := newTestCache()
+
+	_, _ = c.Get("poll", func() (int, error) { return 1, nil })
+	_, _ = c.Get("other", func() (int, error) { return 1, nil })
+	c.Invalidate("poll")
+
+	value, _ := c.Get("poll", func() (int, error) { return 2, nil })
+	assert.Equal(t, 2, value)
+	other, _ := c.Get("other", func() (int, error) { return 2, nil })
+	assert.Equal(t, 1, other)
+}
+
+func TestCache_InvalidateDuringLoadDiscardsResult(t *testing.T) {
+	c, _ := newTestCache()
+
+	// The value was read before the change that invalidated it, so it must not be kept
+	value, err := c.Get("poll", func() (int, error) {
+		c.Invalidate("poll")
+		return 1, nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 1, value)
+	assert.Zero(t, c.Len())
+}
+
+func TestCache_InvalidatingAnotherKeyKeepsResult(t *testing.T) {
+	c, _ := newTestCache()
+
+	// Writes to other polls during the load do not make this value stale
+	value, err := c.Get("poll", func() (int, error) {
+		c.Invalidate("other")
+		return 1, nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 1, value)
+	assert.Equal(t, 1, c.Len())
+}
+
+func TestCache_SweepKeepsGenerationsOfLoadingKeys(t *testing.T) {
+	c, clock := newTestCache()
+	c.Invalidate("idle")
+
+	// A sweep during the load must not forget the invalidation the load overlapped
+	_, err := c.Get("poll", func() (int, error) {
+		c.Invalidate("poll")
+		clock.Advance(time.Second)
+		_, _ = c.Get("other", func() (int, error) { return 2, nil })
+		return 1, nil
+	})
+	require.NoError(t, err)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	assert.NotContains(t, c.entries, "poll")
+	assert.NotContains(t, c.generations, "idle", "generations of keys not being loaded are swept")
+	assert.Empty(t, c.loading)
+}
+
+func TestCache_SweepsExpiredValues(t *testing.T) {
+	c, clock := newTestCache()
+
+	_, _ = c.Get("a", func() (int, error) { return 1, nil })
+	_, _ = c.Get("b", func() (int, error) { return 1, nil })
+	require.Equal(t, 2, c.Len())
+
+	// Keys that are never read again are still evicted once expired
+	clock.Advance(time.Second)
+	_, _ = c.Get("c", func() (int, error) { return 1, nil })
+	assert.Equal(t, 1, c.Len())
+}
+
+func TestCache_PanickingLoadDoesNotBlockLaterCallers(t *testing.T) {
+	c, _ := newTestCache()
+
+	assert.Panics(t, func() {
+		_, _ = c.Get("poll", func() (int, error) { panic("loader bug") })
+	})
+
+	done := make(chan int)
+	go func() {
+		value, _ := c.Get("poll", func() (int, error) { return 7, nil })
+		done <- value
+	}()
+	select {
+	case value := <-done:
+		assert.Equal(t, 7, value)
+	case <-time.After(2 * time.Second):
+		t.Fatal("Get blocked after a loader panicked")
+	}
+}
@@ -3,6 +3,7 @@ package config
 import (
 	"errors"
 	"fmt"
+	"os"
 	"strconv"
 	"strings"
 	"time"
@@ -12,33 +13,199 @@ import (
 )
 
 type Config struct {
-	Addr string `json:"addr"`
-	Env  string `json:"env"`
-	DB   DBConfig
-	CORS CORSConfig
+	Addr                  string          `json:"addr"`
+	Env                   string          `json:"env"`
+	BasePath              string          `json:"base_path"`                // Route prefix, e.g. /polls-service
+	HealthExcludeBasePath bool            `json:"health_exclude_base_path"` // Keep health probes at root paths
+	ReadOnly              bool            `json:"read_only"`                // Reject writes at startup (toggleable at runtime)
+	RequireAPIVersion     bool            `json:"require_api_version"`      // Reject API requests without a version header instead of assuming v1
+	RepoBackend           string          `json:"repo_backend"`             // Where polls are stored: postgres or memory
+	PrettyJSON            bool            `json:"pretty_json"`              // Indent JSON responses unless ?pretty=false; never applied in production
+	JSONFieldCase         string          `json:"json_field_case"`          // Case of JSON response keys unless the Accept header asks otherwise: snake or camel
+	PollLinks             bool            `json:"poll_links"`               // Add _links to polls in responses unless the Accept header asks otherwise
+	TrustedProxies        int             `json:"trusted_proxies"`          // Proxies in front of the server appending to X-Forwarded-For; 0 = use the peer address
+	DB                    DBConfig        `json:"db"`
+	CORS                  CORSConfig      `json:"cors"`
+	Log                   LogConfig       `json:"log"`
+	Poll                  PollConfig      `json:"poll"`
+	Admin                 AdminConfig     `json:"admin"`
+	Auth                  AuthConfig      `json:"auth"`
+	Webhook               WebhookConfig   `json:"webhook"`
+	RateLimit             RateLimitConfig `json:"rate_limit"`
+	Body                  BodyConfig      `json:"body"`
+	Share                 ShareConfig     `json:"share"`
+	Receipt               ReceiptConfig   `json:"receipt"`
+	Outbox                OutboxConfig    `json:"outbox"`
+	Archive               ArchiveConfig   `json:"archive"`
+	Timeout               TimeoutConfig   `json:"timeout"`
+	GRPC                  GRPCConfig      `json:"grpc"`
+	GraphQL               GraphQLConfig   `json:"graphql"`
 }
 
 type DBConfig struct {
-	Host            string
-	Port            string
-	User            string
-	Password        string
-	DBName          string
-	SSLMode         string
-	MaxOpenConns    int
-	MaxIdleConns    int
-	ConnMaxLifetime time.Duration
-	MaxRetries      int
-	RetryDelay      time.Duration
+	Driver              string        `json:"driver"` // SQL driver: postgres or sqlite, for which DBName is the database file
+	Host                string        `json:"host"`
+	Port                string        `json:"port"`
+	User                string        `json:"user"`
+	Password            string        `json:"password"`
+	DBName              string        `json:"db_name"`
+	SSLMode             string        `json:"ssl_mode"`
+	MaxOpenConns        int           `json:"max_open_conns"`
+	MaxIdleConns        int           `json:"max_idle_conns"`
+	ConnMaxLifetime     time.Duration `json:"conn_max_lifetime"`
+	MaxRetries          int           `json:"max_retries"`
+	RetryDelay          time.Duration `json:"retry_delay"`
+	ValidationQuery     string        `json:"validation_query"`      // Health check statement; empty = driver-level Ping
+	ValidationTimeout   time.Duration `json:"validation_timeout"`    // Deadline for a single health check
+	SlowQueryThreshold  time.Duration `json:"slow_query_threshold"`  // Log statements at least this slow; 0 = disabled
+	WarmupConns         int           `json:"warmup_conns"`          // Connections opened at startup, capped at MaxIdleConns; 0 = disabled
+	PoolMetricsInterval time.Duration `json:"pool_metrics_interval"` // How often pool statistics are copied to /metrics; 0 = disabled
 }
 
 type CORSConfig struct {
-	AllowedOrigins   []string
-	AllowedMethods   []string
-	AllowedHeaders   []string
-	ExposedHeaders   []string
-	AllowCredentials bool
-	MaxAge           int
+	AllowedOrigins   []string `json:"allowed_origins"`
+	AllowedMethods   []string `json:"allowed_methods"`
+	AllowedHeaders   []string `json:"allowed_headers"`
+	ExposedHeaders   []string `json:"exposed_headers"`
+	AllowCredentials bool     `json:"allow_credentials"`
+	MaxAge           int      `json:"max_age"`
+}
+
+type LogConfig struct {
+	Bodies        bool     `json:"bodies"`          // Log request/response bodies (requires debug level)
+	BodyMaxLength int      `json:"body_max_length"` // Maximum number of body bytes included in a log line
+	GeoIPFile     string   `json:"geoip_file"`      // CSV of cidr,region used to tag request logs; empty = no region
+	ExcludePaths  []string `json:"exclude_paths"`   // Paths, relative to the base path, whose requests are not logged
+}
+
+type PollConfig struct {
+	MaxActivePollsPerUser int           `json:"max_active_polls_per_user"` // 0 = unlimited
+	ListMaxOptionRows     int           `json:"list_max_option_rows"`      // Most options across a listed page of polls; 0 = unlimited
+	MinVoteWeight         int64         `json:"min_vote_weight"`           // Bounds for weighted votes
+	MaxVoteWeight         int64         `json:"max_vote_weight"`
+	DefaultTTL            time.Duration `json:"default_ttl"`             // Expiry for polls created without one; 0 = never expire
+	MaxExpiryHorizon      time.Duration `json:"max_expiry_horizon"`      // Furthest from now a poll may be set to expire; 0 = unlimited
+	VoteConfirmationTTL   time.Duration `json:"vote_confirmation_ttl"`   // How long votes on confirmation-required polls await confirmation
+	VoterDedupFactors     []string      `json:"voter_dedup_factors"`     // Request attributes combined into anonymous voter identifiers
+	VoteBlocklistFile     string        `json:"vote_blocklist_file"`     // CIDR ranges, one per line, whose votes are rejected; empty = none
+	GroupVoterDedup       bool          `json:"group_voter_dedup"`       // One vote per voter across polls sharing a group
+	ResultsCacheTTL       time.Duration `json:"results_cache_ttl"`       // How long results reads reuse a poll's counts; 0 = disabled
+	WriteInResults        string        `json:"write_in_results"`        // How results list write-in answers: grouped or individual
+	ListSortDirection     string        `json:"list_sort_direction"`     // Creation-time order of poll listings: desc (newest first) or asc
+	DeletedPollsGone      bool          `json:"deleted_polls_gone"`      // Answer reads of deleted polls with 410 Gone instead of 404
+	DeleteProtectVotes    int64         `json:"delete_protect_votes"`    // Polls with more votes than this can only be deleted by admins; 0 = never protected
+	SuspiciousSubnetVotes int64         `json:"suspicious_subnet_votes"` // Votes from one /24 subnet flagged by the suspicious vote report
+	SuspiciousBurstWindow time.Duration `json:"suspicious_burst_window"` // Window the suspicious vote report buckets votes into
+	SuspiciousBurstVotes  int64         `json:"suspicious_burst_votes"`  // Votes within one window flagged as a burst
+	VoteMilestones        []int64       `json:"vote_milestones"`         // Total votes announced once per poll with a poll.milestone event
+	VoteCounting          string        `json:"vote_counting"`           // How vote counts are kept: incremented on write or derived on read
+}
+
+// Database drivers accepted in DB_DRIVER
+const (
+	DBDriverPostgres = "postgres"
+	DBDriverSQLite   = "sqlite" // Core poll operations only, for local development without Postgres
+)
+
+// Poll storage backends accepted in REPO_BACKEND
+const (
+	RepoBackendPostgres = "postgres" // Polls live in the database
+	RepoBackendMemory   = "memory"   // Polls live in process memory and are lost on restart, for demos and local development
+)
+
+// JSON key cases accepted in JSON_FIELD_CASE and the case parameter of Accept headers
+const (
+	JSONCaseSnake = "snake" // total_votes, as the models are tagged
+	JSONCaseCamel = "camel" // totalVotes
+)
+
+// Write-in listings accepted in WRITE_IN_RESULTS
+const (
+	WriteInResultsGrouped    = "grouped"    // One tally per answer, ignoring case
+	WriteInResultsIndividual = "individual" // One entry per write-in vote, newest first
+)
+
+// Vote counting modes accepted in VOTE_COUNTING
+const (
+	VoteCountingIncremented = "incremented" // Option counts are incremented as votes are cast
+	VoteCountingDerived     = "derived"     // Counts are summed from the append-only vote tables on every read
+)
+
+// Poll listing orders accepted in LIST_SORT_DIRECTION
+const (
+	SortDirectionDesc = "desc" // Newest polls first
+	SortDirectionAsc  = "asc"  // Oldest polls first
+)
+
+// Voter dedup factors accepted in VOTER_DEDUP_FACTORS
+const (
+	VoterFactorIP        = "ip"         // Client IP, honoring X-Forwarded-For and X-Real-IP
+	VoterFactorUserAgent = "user_agent" // User-Agent header
+	VoterFactorCookie    = "cookie"     // voter_id cookie set by the client
+)
+
+type AdminConfig struct {
+	APIKey string `json:"api_key"` // Admin endpoints are rejected when empty
+}
+
+type AuthConfig struct {
+	JWTSecret            string `json:"jwt_secret"`              // HMAC secret for verifying HS256 tokens
+	RequireAuthForCreate bool   `json:"require_auth_for_create"` // Require a token to create, edit or delete polls
+}
+
+type WebhookConfig struct {
+	MaxRetries int           `json:"max_retries"`
+	RetryDelay time.Duration `json:"retry_delay"`
+	Timeout    time.Duration `json:"timeout"`
+}
+
+type RateLimitConfig struct {
+	Requests                int           `json:"requests"` // Requests allowed per client IP per window; 0 = unlimited
+	Window                  time.Duration `json:"window"`
+	MaxInFlight             int           `json:"max_in_flight"`                // Requests served at once across all clients; 0 = unlimited
+	MaxInFlightVotesPerPoll int           `json:"max_in_flight_votes_per_poll"` // Votes cast at once on any one poll; 0 = unlimited
+}
+
+type BodyConfig struct {
+	MaxBytes             int64 `json:"max_bytes"`              // Largest request body accepted; 0 = unlimited
+	BulkMaxBytes         int64 `json:"bulk_max_bytes"`         // Largest body of CSV vote imports and backup restores; 0 = unlimited
+	RequireContentLength bool  `json:"require_content_length"` // Reject bodies of unknown length, e.g. chunked
+}
+
+type ShareConfig struct {
+	Secret string        `json:"secret"` // HMAC secret signing share links; share endpoints are disabled when empty
+	TTL    time.Duration `json:"ttl"`    // How long share links stay valid
+}
+
+type ReceiptConfig struct {
+	Secret string `json:"secret"` // HMAC secret signing vote receipts; receipts are disabled when empty
+}
+
+type OutboxConfig struct {
+	RelayInterval time.Duration `json:"relay_interval"` // Delay between outbox polls once it is drained
+	BatchSize     int           `json:"batch_size"`     // Events relayed per query
+	MaxAttempts   int           `json:"max_attempts"`   // Failed publishes before an event is dead-lettered
+	RetryBackoff  time.Duration `json:"retry_backoff"`  // Delay before a failed event's first retry, doubling with each further failure
+	ClaimTimeout  time.Duration `json:"claim_timeout"`  // How long a relay has to publish the batch it fetched before others may take it
+}
+
+type ArchiveConfig struct {
+	Retention time.Duration `json:"retention"` // How long closed polls stay in the live tables before archival; 0 = disabled
+	Interval  time.Duration `json:"interval"`  // Delay between archival runs
+}
+
+type TimeoutConfig struct {
+	Default time.Duration `json:"default"` // Deadline for API requests; 0 = none
+	Long    time.Duration `json:"long"`    // Deadline for bulk routes such as list exports and vote imports; 0 = none
+}
+
+type GRPCConfig struct {
+	Enabled bool   `json:"enabled"` // Serve the gRPC API alongside HTTP
+	Addr    string `json:"addr"`    // Listen address of the gRPC server
+}
+
+type GraphQLConfig struct {
+	Enabled bool `json:"enabled"` // Serve the experimental /graphql endpoint alongside the REST API
 }
 
 func NewConfig() (*Config, error) {
@@ -53,29 +220,146 @@ func NewConfig() (*Config, error) {
 	maxRetries, _ := strconv.Atoi(env.GetEnv("DB_MAX_RETRIES", "5"))
 	retryDelay, _ := time.ParseDuration(env.GetEnv("DB_RETRY_DELAY", "2s"))
 
+	// Parse connection validation settings; an explicitly empty query selects the driver-level Ping
+	validationQuery, ok := os.LookupEnv("DB_VALIDATION_QUERY")
+	if !ok {
+		validationQuery = "SELECT 1"
+	}
+	validationTimeout, _ := time.ParseDuration(env.GetEnv("DB_VALIDATION_TIMEOUT", "2s"))
+	slowQueryThreshold, _ := time.ParseDuration(env.GetEnv("DB_SLOW_QUERY_THRESHOLD", "0"))
+	poolMetricsInterval, _ := time.ParseDuration(env.GetEnv("DB_POOL_METRICS_INTERVAL", "15s"))
+	warmupConns, _ := strconv.Atoi(env.GetEnv("DB_WARMUP_CONNS", "0"))
+
 	// Parse CORS settings
 	allowedOrigins := strings.Split(env.GetEnv("CORS_ALLOWED_ORIGINS", "http://localhost:5173,http://localhost:3000"), ",")
-	allowedMethods := strings.Split(env.GetEnv("CORS_ALLOWED_METHODS", "GET,POST,PUT,DELETE,OPTIONS"), ",")
-	allowedHeaders := strings.Split(env.GetEnv("CORS_ALLOWED_HEADERS", "Accept,Authorization,Content-Type,X-CSRF-Token"), ",")
-	exposedHeaders := strings.Split(env.GetEnv("CORS_EXPOSED_HEADERS", "Link"), ",")
+	allowedMethods := strings.Split(env.GetEnv("CORS_ALLOWED_METHODS", "GET,POST,PUT,PATCH,DELETE,OPTIONS"), ",")
+	allowedHeaders := strings.Split(env.GetEnv("CORS_ALLOWED_HEADERS", "Accept,Authorization,Content-Type,X-CSRF-Token,X-API-Version,Accept-Version,X-Correlation-ID,traceparent,Prefer"), ",")
+	exposedHeaders := strings.Split(env.GetEnv("CORS_EXPOSED_HEADERS", "Link,X-API-Version,X-Correlation-ID,Preference-Applied"), ",")
 	allowCredentials, _ := strconv.ParseBool(env.GetEnv("CORS_ALLOW_CREDENTIALS", "true"))
 	corsMaxAge, _ := strconv.Atoi(env.GetEnv("CORS_MAX_AGE", "300"))
 
+	// Parse routing settings
+	healthExcludeBasePath, _ := strconv.ParseBool(env.GetEnv("HEALTH_EXCLUDE_BASE_PATH", "false"))
+	requireAPIVersion, _ := strconv.ParseBool(env.GetEnv("REQUIRE_API_VERSION", "false"))
+
+	// Responses are indented by default while developing
+	appEnv := env.GetEnv("ENV", "development")
+	prettyJSON, _ := strconv.ParseBool(env.GetEnv("PRETTY_JSON", strconv.FormatBool(appEnv == "development")))
+	jsonFieldCase := strings.ToLower(strings.TrimSpace(env.GetEnv("JSON_FIELD_CASE", JSONCaseSnake)))
+	pollLinks, _ := strconv.ParseBool(env.GetEnv("POLL_LINKS", "false"))
+	trustedProxies, _ := strconv.Atoi(env.GetEnv("TRUSTED_PROXY_COUNT", "0"))
+
+	// Parse storage settings
+	repoBackend := strings.ToLower(strings.TrimSpace(env.GetEnv("REPO_BACKEND", RepoBackendPostgres)))
+	dbDriver := strings.ToLower(strings.TrimSpace(env.GetEnv("DB_DRIVER", DBDriverPostgres)))
+
+	// Parse maintenance settings
+	readOnly, _ := strconv.ParseBool(env.GetEnv("READ_ONLY", "false"))
+
+	// Parse logging settings
+	logBodies, _ := strconv.ParseBool(env.GetEnv("LOG_BODIES", "false"))
+	logBodyMaxLength, _ := strconv.Atoi(env.GetEnv("LOG_BODY_MAX_LENGTH", "2048"))
+
+	// Probe traffic is not logged by default; an explicitly empty list logs every request
+	logExcludePaths, ok := os.LookupEnv("LOG_EXCLUDE_PATHS")
+	if !ok {
+		logExcludePaths = "/live,/ready"
+	}
+
+	// Parse poll rules
+	maxActivePollsPerUser, _ := strconv.Atoi(env.GetEnv("MAX_ACTIVE_POLLS_PER_USER", "0"))
+	listMaxOptionRows, _ := strconv.Atoi(env.GetEnv("LIST_MAX_OPTION_ROWS", "0"))
+	minVoteWeight, _ := strconv.ParseInt(env.GetEnv("VOTE_WEIGHT_MIN", "1"), 10, 64)
+	maxVoteWeight, _ := strconv.ParseInt(env.GetEnv("VOTE_WEIGHT_MAX", "10"), 10, 64)
+	defaultPollTTL, _ := time.ParseDuration(env.GetEnv("DEFAULT_POLL_TTL", "0"))
+	maxExpiryHorizon, _ := time.ParseDuration(env.GetEnv("MAX_EXPIRY_HORIZON", "8760h"))
+	voteConfirmationTTL, _ := time.ParseDuration(env.GetEnv("VOTE_CONFIRMATION_TTL", "2m"))
+	voterDedupFactors := parseList(env.GetEnv("VOTER_DEDUP_FACTORS", VoterFactorIP))
+	groupVoterDedup, _ := strconv.ParseBool(env.GetEnv("POLL_GROUP_DEDUP", "true"))
+	resultsCacheTTL, _ := time.ParseDuration(env.GetEnv("RESULTS_CACHE_TTL", "1s"))
+	writeInResults := strings.ToLower(strings.TrimSpace(env.GetEnv("WRITE_IN_RESULTS", WriteInResultsGrouped)))
+	listSortDirection := strings.ToLower(strings.TrimSpace(env.GetEnv("LIST_SORT_DIRECTION", SortDirectionDesc)))
+	deletedPollsGone, _ := strconv.ParseBool(env.GetEnv("DELETED_POLLS_GONE", "true"))
+	deleteProtectVotes, _ := strconv.ParseInt(env.GetEnv("DELETE_PROTECT_VOTES", "0"), 10, 64)
+	suspiciousSubnetVotes, _ := strconv.ParseInt(env.GetEnv("SUSPICIOUS_SUBNET_MIN_VOTES", "10"), 10, 64)
+	suspiciousBurstWindow, _ := time.ParseDuration(env.GetEnv("SUSPICIOUS_BURST_WINDOW", "1m"))
+	suspiciousBurstVotes, _ := strconv.ParseInt(env.GetEnv("SUSPICIOUS_BURST_MIN_VOTES", "20"), 10, 64)
+	voteMilestones := parseInt64List(env.GetEnv("VOTE_MILESTONES", "100,1000"))
+	voteCounting := strings.ToLower(strings.TrimSpace(env.GetEnv("VOTE_COUNTING", VoteCountingIncremented)))
+
+	// Parse auth settings
+	requireAuthForCreate, _ := strconv.ParseBool(env.GetEnv("REQUIRE_AUTH_FOR_CREATE", "false"))
+
+	// Parse global rate limit settings
+	globalRateLimit, _ := strconv.Atoi(env.GetEnv("GLOBAL_RATE_LIMIT", "0"))
+	globalRateWindow, _ := time.ParseDuration(env.GetEnv("GLOBAL_RATE_WINDOW", "1m"))
+	maxInFlight, _ := strconv.Atoi(env.GetEnv("MAX_INFLIGHT_REQUESTS", "0"))
+	maxInFlightVotesPerPoll, _ := strconv.Atoi(env.GetEnv("MAX_INFLIGHT_VOTES_PER_POLL", "0"))
+
+	// Parse request body limits
+	maxBodyBytes, _ := strconv.ParseInt(env.GetEnv("MAX_REQUEST_BODY_BYTES", "1048576"), 10, 64)
+	maxBulkBodyBytes, _ := strconv.ParseInt(env.GetEnv("MAX_BULK_BODY_BYTES", "104857600"), 10, 64)
+	requireContentLength, _ := strconv.ParseBool(env.GetEnv("REQUIRE_CONTENT_LENGTH", "false"))
+
+	// Parse share link settings
+	shareLinkTTL, _ := time.ParseDuration(env.GetEnv("SHARE_LINK_TTL", "720h"))
+
+	// Parse webhook delivery settings
+	webhookMaxRetries, _ := strconv.Atoi(env.GetEnv("WEBHOOK_MAX_RETRIES", "3"))
+	webhookRetryDelay, _ := time.ParseDuration(env.GetEnv("WEBHOOK_RETRY_DELAY", "1s"))
+	webhookTimeout, _ := time.ParseDuration(env.GetEnv("WEBHOOK_TIMEOUT", "5s"))
+
+	// Parse outbox relay settings
+	outboxRelayInterval, _ := time.ParseDuration(env.GetEnv("OUTBOX_RELAY_INTERVAL", "1s"))
+	outboxBatchSize, _ := strconv.Atoi(env.GetEnv("OUTBOX_BATCH_SIZE", "100"))
+	outboxMaxAttempts, _ := strconv.Atoi(env.GetEnv("OUTBOX_MAX_ATTEMPTS", "10"))
+	outboxRetryBackoff, _ := time.ParseDuration(env.GetEnv("OUTBOX_RETRY_BACKOFF", "10s"))
+	outboxClaimTimeout, _ := time.ParseDuration(env.GetEnv("OUTBOX_CLAIM_TIMEOUT", "5m"))
+
+	// Parse request timeouts
+	requestTimeout, _ := time.ParseDuration(env.GetEnv("REQUEST_TIMEOUT", "5s"))
+	requestTimeoutLong, _ := time.ParseDuration(env.GetEnv("REQUEST_TIMEOUT_LONG", "30s"))
+
+	// Parse archival settings
+	archiveRetention, _ := time.ParseDuration(env.GetEnv("ARCHIVE_RETENTION", "0"))
+	archiveInterval, _ := time.ParseDuration(env.GetEnv("ARCHIVE_INTERVAL", "1h"))
+
+	// Parse gRPC settings
+	enableGRPC, _ := strconv.ParseBool(env.GetEnv("ENABLE_GRPC", "false"))
+
+	// Parse GraphQL settings
+	enableGraphQL, _ := strconv.ParseBool(env.GetEnv("ENABLE_GRAPHQL", "false"))
+
 	cfg := &Config{
-		Addr: fmt.Sprintf(":%s", env.GetEnv("PORT", "8080")),
-		Env:  env.GetEnv("ENV", "development"),
+		Addr:                  fmt.Sprintf(":%s", env.GetEnv("PORT", "8080")),
+		Env:                   appEnv,
+		BasePath:              normalizeBasePath(env.GetEnv("API_BASE_PATH", "")),
+		HealthExcludeBasePath: healthExcludeBasePath,
+		ReadOnly:              readOnly,
+		RequireAPIVersion:     requireAPIVersion,
+		RepoBackend:           repoBackend,
+		PrettyJSON:            prettyJSON,
+		JSONFieldCase:         jsonFieldCase,
+		PollLinks:             pollLinks,
+		TrustedProxies:        trustedProxies,
 		DB: DBConfig{
-			Host:            env.GetEnv("DB_HOST", "localhost"),
-			Port:            env.GetEnv("DB_PORT", "5432"),
-			User:            env.GetEnv("DB_USER", "devuser"),
-			Password:        env.GetEnv("DB_PASSWORD", "devpassword"),
-			DBName:          env.GetEnv("DB_NAME", "k8s_app_dev"),
-			SSLMode:         env.GetEnv("DB_SSLMODE", "disable"),
-			MaxOpenConns:    maxOpenConns,
-			MaxIdleConns:    maxIdleConns,
-			ConnMaxLifetime: connMaxLifetime,
-			MaxRetries:      maxRetries,
-			RetryDelay:      retryDelay,
+			Driver:              dbDriver,
+			Host:                env.GetEnv("DB_HOST", "localhost"),
+			Port:                env.GetEnv("DB_PORT", "5432"),
+			User:                env.GetEnv("DB_USER", "devuser"),
+			Password:            env.GetEnv("DB_PASSWORD", "devpassword"),
+			DBName:              env.GetEnv("DB_NAME", "k8s_app_dev"),
+			SSLMode:             env.GetEnv("DB_SSLMODE", "disable"),
+			MaxOpenConns:        maxOpenConns,
+			MaxIdleConns:        maxIdleConns,
+			ConnMaxLifetime:     connMaxLifetime,
+			MaxRetries:          maxRetries,
+			RetryDelay:          retryDelay,
+			ValidationQuery:     validationQuery,
+			ValidationTimeout:   validationTimeout,
+			SlowQueryThreshold:  slowQueryThreshold,
+			WarmupConns:         warmupConns,
+			PoolMetricsInterval: poolMetricsInterval,
 		},
 		CORS: CORSConfig{
 			AllowedOrigins:   allowedOrigins,
@@ -85,6 +369,86 @@ func NewConfig() (*Config, error) {
 			AllowCredentials: allowCredentials,
 			MaxAge:           corsMaxAge,
 		},
+		Log: LogConfig{
+			Bodies:        logBodies,
+			BodyMaxLength: logBodyMaxLength,
+			GeoIPFile:     env.GetEnv("GEOIP_REGIONS_FILE", ""),
+			ExcludePaths:  parseList(logExcludePaths),
+		},
+		Poll: PollConfig{
+			MaxActivePollsPerUser: maxActivePollsPerUser,
+			ListMaxOptionRows:     listMaxOptionRows,
+			MinVoteWeight:         minVoteWeight,
+			MaxVoteWeight:         maxVoteWeight,
+			DefaultTTL:            defaultPollTTL,
+			MaxExpiryHorizon:      maxExpiryHorizon,
+			VoteConfirmationTTL:   voteConfirmationTTL,
+			VoterDedupFactors:     voterDedupFactors,
+			VoteBlocklistFile:     env.GetEnv("VOTE_BLOCKLIST_FILE", ""),
+			GroupVoterDedup:       groupVoterDedup,
+			ResultsCacheTTL:       resultsCacheTTL,
+			WriteInResults:        writeInResults,
+			ListSortDirection:     listSortDirection,
+			DeletedPollsGone:      deletedPollsGone,
+			DeleteProtectVotes:    deleteProtectVotes,
+			SuspiciousSubnetVotes: suspiciousSubnetVotes,
+			SuspiciousBurstWindow: suspiciousBurstWindow,
+			SuspiciousBurstVotes:  suspiciousBurstVotes,
+			VoteMilestones:        voteMilestones,
+			VoteCounting:          voteCounting,
+		},
+		Admin: AdminConfig{
+			APIKey: env.GetEnv("ADMIN_API_KEY", ""),
+		},
+		Auth: AuthConfig{
+			JWTSecret:            env.GetEnv("JWT_SECRET", ""),
+			RequireAuthForCreate: requireAuthForCreate,
+		},
+		Webhook: WebhookConfig{
+			MaxRetries: webhookMaxRetries,
+			RetryDelay: webhookRetryDelay,
+			Timeout:    webhookTimeout,
+		},
+		RateLimit: RateLimitConfig{
+			Requests:                globalRateLimit,
+			Window:                  globalRateWindow,
+			MaxInFlight:             maxInFlight,
+			MaxInFlightVotesPerPoll: maxInFlightVotesPerPoll,
+		},
+		Body: BodyConfig{
+			MaxBytes:             maxBodyBytes,
+			BulkMaxBytes:         maxBulkBodyBytes,
+			RequireContentLength: requireContentLength,
+		},
+		Share: ShareConfig{
+			Secret: env.GetEnv("SHARE_LINK_SECRET", ""),
+			TTL:    shareLinkTTL,
+		},
+		Receipt: ReceiptConfig{
+			Secret: env.GetEnv("VOTE_RECEIPT_SECRET", ""),
+		},
+		Outbox: OutboxConfig{
+			RelayInterval: outboxRelayInterval,
+			BatchSize:     outboxBatchSize,
+			MaxAttempts:   outboxMaxAttempts,
+			RetryBackoff:  outboxRetryBackoff,
+			ClaimTimeout:  outboxClaimTimeout,
+		},
+		Archive: ArchiveConfig{
+			Retention: archiveRetention,
+			Interval:  archiveInterval,
+		},
+		Timeout: TimeoutConfig{
+			Default: requestTimeout,
+			Long:    requestTimeoutLong,
+		},
+		GRPC: GRPCConfig{
+			Enabled: enableGRPC,
+			Addr:    fmt.Sprintf(":%s", env.GetEnv("GRPC_PORT", "9090")),
+		},
+		GraphQL: GraphQLConfig{
+			Enabled: enableGraphQL,
+		},
 	}
 
 	if err := validateConfig(cfg); err != nil {
@@ -101,5 +465,206 @@ func validateConfig(cfg *Config) error {
 	if cfg.Env == "" {
 		return errors.New("env is required")
 	}
+	if cfg.RepoBackend != RepoBackendPostgres && cfg.RepoBackend != RepoBackendMemory {
+		return fmt.Errorf("REPO_BACKEND: unknown backend %q (want %s or %s)", cfg.RepoBackend, RepoBackendPostgres, RepoBackendMemory)
+	}
+	if cfg.JSONFieldCase != JSONCaseSnake && cfg.JSONFieldCase != JSONCaseCamel {
+		return fmt.Errorf("JSON_FIELD_CASE: unknown case %q (want %s or %s)", cfg.JSONFieldCase, JSONCaseSnake, JSONCaseCamel)
+	}
+	if cfg.DB.Driver != DBDriverPostgres && cfg.DB.Driver != DBDriverSQLite {
+		return fmt.Errorf("DB_DRIVER: unknown driver %q (want %s or %s)", cfg.DB.Driver, DBDriverPostgres, DBDriverSQLite)
+	}
+	if cfg.DB.ValidationTimeout <= 0 {
+		return errors.New("DB_VALIDATION_TIMEOUT must be positive")
+	}
+	if cfg.DB.SlowQueryThreshold < 0 {
+		return errors.New("DB_SLOW_QUERY_THRESHOLD must not be negative")
+	}
+	if cfg.DB.WarmupConns < 0 {
+		return errors.New("DB_WARMUP_CONNS must not be negative")
+	}
+	if cfg.DB.PoolMetricsInterval < 0 {
+		return errors.New("DB_POOL_METRICS_INTERVAL must not be negative")
+	}
+	if cfg.Poll.ListMaxOptionRows < 0 {
+		return errors.New("LIST_MAX_OPTION_ROWS must not be negative")
+	}
+	if cfg.Poll.ResultsCacheTTL < 0 {
+		return errors.New("RESULTS_CACHE_TTL must not be negative")
+	}
+	if cfg.Poll.DefaultTTL < 0 {
+		return errors.New("DEFAULT_POLL_TTL must not be negative")
+	}
+	if cfg.Poll.DeleteProtectVotes < 0 {
+		return errors.New("DELETE_PROTECT_VOTES must not be negative")
+	}
+	if cfg.Poll.MaxExpiryHorizon < 0 {
+		return errors.New("MAX_EXPIRY_HORIZON must not be negative")
+	}
+	if cfg.Poll.MaxExpiryHorizon > 0 && cfg.Poll.DefaultTTL > cfg.Poll.MaxExpiryHorizon {
+		return errors.New("DEFAULT_POLL_TTL must not exceed MAX_EXPIRY_HORIZON")
+	}
+	if cfg.Poll.VoteConfirmationTTL <= 0 {
+		return errors.New("VOTE_CONFIRMATION_TTL must be positive")
+	}
+	if cfg.Poll.WriteInResults != WriteInResultsGrouped && cfg.Poll.WriteInResults != WriteInResultsIndividual {
+		return fmt.Errorf("WRITE_IN_RESULTS: unknown listing %q (want %s or %s)", cfg.Poll.WriteInResults, WriteInResultsGrouped, WriteInResultsIndividual)
+	}
+	if cfg.Poll.ListSortDirection != SortDirectionDesc && cfg.Poll.ListSortDirection != SortDirectionAsc {
+		return fmt.Errorf("LIST_SORT_DIRECTION: unknown direction %q (want %s or %s)", cfg.Poll.ListSortDirection, SortDirectionDesc, SortDirectionAsc)
+	}
+	if cfg.Poll.VoteCounting != VoteCountingIncremented && cfg.Poll.VoteCounting != VoteCountingDerived {
+		return fmt.Errorf("VOTE_COUNTING: unknown mode %q (want %s or %s)", cfg.Poll.VoteCounting, VoteCountingIncremented, VoteCountingDerived)
+	}
+	if cfg.Poll.SuspiciousSubnetVotes <= 0 {
+		return errors.New("SUSPICIOUS_SUBNET_MIN_VOTES must be positive")
+	}
+	if cfg.Poll.SuspiciousBurstWindow < time.Second {
+		return errors.New("SUSPICIOUS_BURST_WINDOW must be at least 1s")
+	}
+	if cfg.Poll.SuspiciousBurstVotes <= 0 {
+		return errors.New("SUSPICIOUS_BURST_MIN_VOTES must be positive")
+	}
+	for _, milestone := range cfg.Poll.VoteMilestones {
+		if milestone <= 0 {
+			return errors.New("VOTE_MILESTONES must list positive whole numbers")
+		}
+	}
+	if cfg.TrustedProxies < 0 {
+		return errors.New("TRUSTED_PROXY_COUNT must not be negative")
+	}
+	if cfg.RateLimit.Requests < 0 {
+		return errors.New("GLOBAL_RATE_LIMIT must not be negative")
+	}
+	if cfg.RateLimit.Requests > 0 && cfg.RateLimit.Window <= 0 {
+		return errors.New("GLOBAL_RATE_WINDOW must be positive when GLOBAL_RATE_LIMIT is set")
+	}
+	if cfg.RateLimit.MaxInFlight < 0 {
+		return errors.New("MAX_INFLIGHT_REQUESTS must not be negative")
+	}
+	if cfg.RateLimit.MaxInFlightVotesPerPoll < 0 {
+		return errors.New("MAX_INFLIGHT_VOTES_PER_POLL must not be negative")
+	}
+	if cfg.Body.MaxBytes < 0 {
+		return errors.New("MAX_REQUEST_BODY_BYTES must not be negative")
+	}
+	if cfg.Body.BulkMaxBytes < 0 {
+		return errors.New("MAX_BULK_BODY_BYTES must not be negative")
+	}
+	if cfg.Share.TTL <= 0 {
+		return errors.New("SHARE_LINK_TTL must be positive")
+	}
+	if cfg.Outbox.RelayInterval <= 0 {
+		return errors.New("OUTBOX_RELAY_INTERVAL must be positive")
+	}
+	if cfg.Outbox.BatchSize <= 0 {
+		return errors.New("OUTBOX_BATCH_SIZE must be positive")
+	}
+	if cfg.Outbox.MaxAttempts <= 0 {
+		return errors.New("OUTBOX_MAX_ATTEMPTS must be positive")
+	}
+	if cfg.Outbox.RetryBackoff <= 0 {
+		return errors.New("OUTBOX_RETRY_BACKOFF must be positive")
+	}
+	if cfg.Outbox.ClaimTimeout <= 0 {
+		return errors.New("OUTBOX_CLAIM_TIMEOUT must be positive")
+	}
+	if cfg.Archive.Retention < 0 {
+		return errors.New("ARCHIVE_RETENTION must not be negative")
+	}
+	if cfg.Archive.Retention > 0 && cfg.Archive.Interval <= 0 {
+		return errors.New("ARCHIVE_INTERVAL must be positive when ARCHIVE_RETENTION is set")
+	}
+	if cfg.Timeout.Default < 0 {
+		return errors.New("REQUEST_TIMEOUT must not be negative")
+	}
+	if cfg.Timeout.Long < 0 {
+		return errors.New("REQUEST_TIMEOUT_LONG must not be negative")
+	}
+	if cfg.GRPC.Enabled && cfg.GRPC.Addr == cfg.Addr {
+		return errors.New("GRPC_PORT must differ from PORT when ENABLE_GRPC is set")
+	}
+	if err := validateVoterDedupFactors(cfg.Poll.VoterDedupFactors); err != nil {
+		return err
+	}
+	if cfg.Auth.RequireAuthForCreate && cfg.Auth.JWTSecret == "" {
+		return errors.New("JWT_SECRET is required when REQUIRE_AUTH_FOR_CREATE is enabled")
+	}
+	return nil
+}
+
+// validateVoterDedupFactors requires at least one factor and rejects unknown or repeated ones
+func validateVoterDedupFactors(factors []string) error {
+	if len(factors) == 0 {
+		return errors.New("VOTER_DEDUP_FACTORS must list at least one factor")
+	}
+	seen := make(map[string]bool, len(factors))
+	for _, factor := range factors {
+		switch factor {
+		case VoterFactorIP, VoterFactorUserAgent, VoterFactorCookie:
+		default:
+			return fmt.Errorf("VOTER_DEDUP_FACTORS: unknown factor %q (want %s, %s or %s)",
+				factor, VoterFactorIP, VoterFactorUserAgent, VoterFactorCookie)
+		}
+		if seen[factor] {
+			return fmt.Errorf("VOTER_DEDUP_FACTORS: factor %q listed twice", factor)
+		}
+		seen[factor] = true
+	}
 	return nil
 }
+
+// parseList splits a comma-separated value, trimming spaces and dropping empty entries
+func parseList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// parseInt64List splits a comma-separated list of integers like parseList
+// Entries that are not integers are kept as 0, so validation rejects them rather than dropping them silently.
+func parseInt64List(value string) []int64 {
+	var numbers []int64
+	for _, item := range parseList(value) {
+		n, _ := strconv.ParseInt(item, 10, 64)
+		numbers = append(numbers, n)
+	}
+	return numbers
+}
+
+// normalizeBasePath ensures a leading slash and strips any trailing slash.
+// An empty or "/" path means routes are mounted at the root.
+func normalizeBasePath(path string) string {
+	path = strings.Trim(strings.TrimSpace(path), "/")
+	if path == "" {
+		return ""
+	}
+	return "/" + path
+}
+
+// redacted replaces secret values in sanitized output
+const redacted = "[REDACTED]"
+
+// Sanitize returns a copy of the config with secrets redacted, safe to log or expose.
+// Unset secrets stay empty so it remains visible whether one is configured.
+func (c *Config) Sanitize() Config {
+	sanitized := *c
+	sanitized.DB.Password = redactSecret(c.DB.Password)
+	sanitized.Admin.APIKey = redactSecret(c.Admin.APIKey)
+	sanitized.Auth.JWTSecret = redactSecret(c.Auth.JWTSecret)
+	sanitized.Share.Secret = redactSecret(c.Share.Secret)
+	sanitized.Receipt.Secret = redactSecret(c.Receipt.Secret)
+	return sanitized
+}
+
+// redactSecret hides a non-empty secret value
+func redactSecret(value string) string {
+	if value == "" {
+		return ""
+	}
+	return redacted
+}
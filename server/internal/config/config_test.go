@@ -0,0 +1,59 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSanitize_RedactsSecrets(t *testing.T) {
+	cfg := &Config{
+		DB:      DBConfig{Host: "postgres", Password: "hunter2"},
+		Admin:   AdminConfig{APIKey: "admin-key"},
+		Auth:    AuthConfig{JWTSecret: ""},
+		Share:   ShareConfig{Secret: "share-secret"},
+		Receipt: ReceiptConfig{Secret: "receipt-secret"},
+	}
+
+	sanitized := cfg.Sanitize()
+
+	assert.Equal(t, redacted, sanitized.DB.Password)
+	assert.Equal(t, redacted, sanitized.Admin.APIKey)
+	assert.Equal(t, redacted, sanitized.Share.Secret)
+	assert.Equal(t, redacted, sanitized.Receipt.Secret)
+	assert.Empty(t, sanitized.Auth.JWTSecret, "unset secrets stay empty")
+	assert.Equal(t, "postgres", sanitized.DB.Host)
+
+	// The original config is left untouched
+	assert.Equal(t, "hunter2", cfg.DB.Password)
+}
+
+func TestValidateVoterDedupFactors(t *testing.T) {
+	tests := []struct {
+		name    string
+		factors []string
+		wantErr bool
+	}{
+		{name: "default", factors: []string{VoterFactorIP}},
+		{name: "combination", factors: []string{VoterFactorIP, VoterFactorUserAgent, VoterFactorCookie}},
+		{name: "empty", factors: nil, wantErr: true},
+		{name: "unknown factor", factors: []string{VoterFactorIP, "fingerprint"}, wantErr: true},
+		{name: "repeated factor", factors: []string{VoterFactorIP, VoterFactorIP}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateVoterDedupFactors(tt.factors)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestParseList(t *testing.T) {
+	assert.Equal(t, []string{"ip", "user_agent"}, parseList(" ip, ,user_agent "))
+	assert.Nil(t, parseList(""))
+}
@@ -1,6 +1,7 @@
 package database
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"time"
@@ -13,8 +14,21 @@ import (
 // DB holds the database connection pool
 var DB *sql.DB
 
+// defaultValidationTimeout bounds a validation check when none is configured
+const defaultValidationTimeout = 2 * time.Second
+
+// Validation settings used by Ping; set by NewConnection
+var (
+	validationQuery   string
+	validationTimeout = defaultValidationTimeout
+)
+
+// maxIdleConns caps Warmup; set by NewConnection
+var maxIdleConns int
+
 // Config represents database configuration
 type Config struct {
+	Driver          string // DriverPostgres, the default, or DriverSQLite, for which DBName is the database file
 	Host            string
 	Port            string
 	User            string
@@ -26,10 +40,18 @@ type Config struct {
 	ConnMaxLifetime time.Duration
 	MaxRetries      int           // Maximum number of connection retry attempts
 	RetryDelay      time.Duration // Initial delay between retries
+	// ValidationQuery is run to check the connection instead of the driver-level Ping.
+	// Useful behind poolers such as pgbouncer; empty falls back to Ping.
+	ValidationQuery   string
+	ValidationTimeout time.Duration // Deadline for a single validation check
 }
 
 // NewConnection creates a new database connection pool with retry logic
 func NewConnection(cfg *Config) (*sql.DB, error) {
+	if cfg.Driver == DriverSQLite {
+		return newSQLiteConnection(cfg)
+	}
+
 	dsn := fmt.Sprintf(
 		"host=%s port=%s user=%s password=%s dbname=%s sslmode=%s",
 		cfg.Host,
@@ -51,11 +73,16 @@ func NewConnection(cfg *Config) (*sql.DB, error) {
 		retryDelay = 2 * time.Second // Default to 2 seconds initial delay
 	}
 
+	timeout := cfg.ValidationTimeout
+	if timeout == 0 {
+		timeout = defaultValidationTimeout
+	}
+
 	var db *sql.DB
 	var err error
 
 	// Try to open database connection
-	db, err = sql.Open("postgres", dsn)
+	db, err = sql.Open(DriverPostgres, dsn)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open database: %w", err)
 	}
@@ -74,7 +101,7 @@ func NewConnection(cfg *Config) (*sql.DB, error) {
 			zap.String("database", cfg.DBName),
 		)
 
-		err = db.Ping()
+		err = validate(db, cfg.ValidationQuery, timeout)
 		if err == nil {
 			// Connection successful
 			logger.Info("Database connection established",
@@ -84,6 +111,9 @@ func NewConnection(cfg *Config) (*sql.DB, error) {
 				zap.Int("attempts", attempt),
 			)
 			DB = db
+			validationQuery = cfg.ValidationQuery
+			validationTimeout = timeout
+			maxIdleConns = cfg.MaxIdleConns
 			return db, nil
 		}
 
@@ -124,11 +154,27 @@ func Close() error {
 }
 
 // Ping checks if the database connection is alive
+// It runs the configured validation query, or a driver-level Ping when none is set
 func Ping() error {
 	if DB == nil {
 		return fmt.Errorf("database connection is nil")
 	}
-	return DB.Ping()
+	return validate(DB, validationQuery, validationTimeout)
+}
+
+// validate checks db with query, or with a driver-level Ping when query is empty
+func validate(db *sql.DB, query string, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	if query == "" {
+		return db.PingContext(ctx)
+	}
+
+	if _, err := db.ExecContext(ctx, query); err != nil {
+		return fmt.Errorf("validation query failed: %w", err)
+	}
+	return nil
 }
 
 // GetDB returns the database instance
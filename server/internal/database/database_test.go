@@ -0,0 +1,213 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubDriver is a database/sql driver that records the statements and pings it receives
+type stubDriver struct {
+	mu      sync.Mutex
+	execs   []string
+	pings   int
+	execErr error
+	delay   time.Duration // Simulated statement latency
+
+	schemaVersion int64 // Answer to queries
+	queryErr      error
+}
+
+func (d *stubDriver) Open(string) (driver.Conn, error) {
+	return &stubConn{driver: d}, nil
+}
+
+type stubConn struct {
+	driver *stubDriver
+}
+
+func (c *stubConn) Prepare(string) (driver.Stmt, error) {
+	return nil, errors.New("prepare not supported")
+}
+
+func (c *stubConn) Close() error { return nil }
+
+func (c *stubConn) Begin() (driver.Tx, error) {
+	return nil, errors.New("transactions not supported")
+}
+
+func (c *stubConn) ExecContext(_ context.Context, query string, _ []driver.NamedValue) (driver.Result, error) {
+	time.Sleep(c.driver.delay)
+	c.driver.mu.Lock()
+	defer c.driver.mu.Unlock()
+	c.driver.execs = append(c.driver.execs, query)
+	if c.driver.execErr != nil {
+		return nil, c.driver.execErr
+	}
+	return driver.RowsAffected(0), nil
+}
+
+func (c *stubConn) QueryContext(context.Context, string, []driver.NamedValue) (driver.Rows, error) {
+	if c.driver.queryErr != nil {
+		return nil, c.driver.queryErr
+	}
+	return &stubRows{value: c.driver.schemaVersion}, nil
+}
+
+// stubRows is a single-column, single-row result
+type stubRows struct {
+	value int64
+	done  bool
+}
+
+func (r *stubRows) Columns() []string { return []string{"value"} }
+func (r *stubRows) Close() error      { return nil }
+
+func (r *stubRows) Next(dest []driver.Value) error {
+	if r.done {
+		return io.EOF
+	}
+	r.done = true
+	dest[0] = r.value
+	return nil
+}
+
+func (c *stubConn) Ping(context.Context) error {
+	c.driver.mu.Lock()
+	defer c.driver.mu.Unlock()
+	c.driver.pings++
+	return nil
+}
+
+// useStubDB points the package at a fresh stub database for the duration of the test
+func useStubDB(t *testing.T, query string) *stubDriver {
+	t.Helper()
+	stub := &stubDriver{}
+	db := sql.OpenDB(stubConnector{stub})
+
+	prevDB, prevQuery, prevTimeout := DB, validationQuery, validationTimeout
+	DB, validationQuery, validationTimeout = db, query, time.Second
+	t.Cleanup(func() {
+		db.Close()
+		DB, validationQuery, validationTimeout = prevDB, prevQuery, prevTimeout
+	})
+	return stub
+}
+
+type stubConnector struct {
+	driver *stubDriver
+}
+
+func (c stubConnector) Connect(context.Context) (driver.Conn, error) {
+	return c.driver.Open("")
+}
+
+func (c stubConnector) Driver() driver.Driver {
+	return c.driver
+}
+
+func TestPing_RunsValidationQuery(t *testing.T) {
+	stub := useStubDB(t, "SELECT 1 /* pgbouncer */")
+
+	require.NoError(t, Ping())
+
+	assert.Equal(t, []string{"SELECT 1 /* pgbouncer */"}, stub.execs)
+	assert.Zero(t, stub.pings)
+}
+
+func TestPing_FallsBackToDriverPing(t *testing.T) {
+	stub := useStubDB(t, "")
+
+	require.NoError(t, Ping())
+
+	assert.Empty(t, stub.execs)
+	assert.Equal(t, 1, stub.pings)
+}
+
+func TestPing_ValidationQueryFailure(t *testing.T) {
+	stub := useStubDB(t, "SELECT 1")
+	stub.execErr = errors.New("server closed the connection")
+
+	err := Ping()
+
+	require.Error(t, err)
+	assert.ErrorIs(t, err, stub.execErr)
+}
+
+func TestWarmup_PrefillsIdleConnections(t *testing.T) {
+	tests := []struct {
+		name       string
+		n          int
+		maxIdle    int
+		wantWarmed int
+	}{
+		{name: "within idle limit", n: 3, maxIdle: 5, wantWarmed: 3},
+		{name: "capped at idle limit", n: 10, maxIdle: 4, wantWarmed: 4},
+		{name: "disabled", n: 0, maxIdle: 5, wantWarmed: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stub := useStubDB(t, "")
+			DB.SetMaxIdleConns(tt.maxIdle)
+			prevMaxIdle := maxIdleConns
+			maxIdleConns = tt.maxIdle
+			t.Cleanup(func() { maxIdleConns = prevMaxIdle })
+
+			warmed, err := Warmup(context.Background(), tt.n)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantWarmed, warmed)
+			assert.Equal(t, tt.wantWarmed, stub.pings)
+			assert.Equal(t, tt.wantWarmed, DB.Stats().Idle)
+		})
+	}
+}
+
+func TestMigrationStatus(t *testing.T) {
+	tests := []struct {
+		name        string
+		version     int64
+		queryErr    error
+		wantCurrent int
+		wantBehind  bool
+	}{
+		{name: "up to date", version: SchemaVersion, wantCurrent: SchemaVersion, wantBehind: false},
+		{name: "ahead during a rollback", version: SchemaVersion + 1, wantCurrent: SchemaVersion + 1, wantBehind: false},
+		{name: "behind", version: SchemaVersion - 1, wantCurrent: SchemaVersion - 1, wantBehind: true},
+		{name: "versions not recorded", queryErr: &pq.Error{Code: undefinedTable}, wantCurrent: 0, wantBehind: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stub := useStubDB(t, "")
+			stub.schemaVersion = tt.version
+			stub.queryErr = tt.queryErr
+
+			versions, err := MigrationStatus(context.Background())
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantCurrent, versions.Current)
+			assert.Equal(t, SchemaVersion, versions.Latest)
+			assert.Equal(t, tt.wantBehind, versions.Behind())
+		})
+	}
+}
+
+func TestMigrationStatus_QueryFailure(t *testing.T) {
+	stub := useStubDB(t, "")
+	stub.queryErr = errors.New("server closed the connection")
+
+	_, err := MigrationStatus(context.Background())
+
+	assert.ErrorIs(t, err, stub.queryErr)
+}
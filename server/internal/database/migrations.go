@@ -0,0 +1,56 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/lib/pq"
+)
+
+// SchemaVersion is the schema version this build requires, as recorded in schema_migrations.
+// Bump it together with init-scripts/init.sql whenever a release depends on a schema change.
+const SchemaVersion = 16
+
+// undefinedTable is the Postgres error code for a missing relation
+const undefinedTable = "42P01"
+
+// MigrationVersions compares the schema version of the database with the one this build requires
+type MigrationVersions struct {
+	Current int `json:"current"` // Highest version applied to the database; 0 when none is recorded
+	Latest  int `json:"latest"`  // SchemaVersion of this build
+}
+
+// Behind reports whether the database lacks schema changes this build depends on
+// A database ahead of the build is fine, e.g. while rolling back to an older release
+func (v MigrationVersions) Behind() bool {
+	return v.Current < v.Latest
+}
+
+// MigrationStatus reads the schema version of the database, bounded by the validation timeout
+func MigrationStatus(ctx context.Context) (MigrationVersions, error) {
+	if DB == nil {
+		return MigrationVersions{Latest: SchemaVersion}, fmt.Errorf("database connection is nil")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, validationTimeout)
+	defer cancel()
+	return migrationStatus(ctx, DB)
+}
+
+func migrationStatus(ctx context.Context, db *sql.DB) (MigrationVersions, error) {
+	versions := MigrationVersions{Latest: SchemaVersion}
+
+	err := db.QueryRowContext(ctx, `SELECT COALESCE(MAX(version), 0) FROM schema_migrations`).Scan(&versions.Current)
+
+	// Databases created before versions were recorded have no schema_migrations table
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == undefinedTable {
+		return versions, nil
+	}
+	if err != nil {
+		return versions, fmt.Errorf("failed to read schema version: %w", err)
+	}
+	return versions, nil
+}
@@ -0,0 +1,89 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/moabdelazem/k8s-app/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// maxLoggedQueryLength caps the SQL text included in slow query warnings
+const maxLoggedQueryLength = 200
+
+// Conn is the subset of *sql.DB used by repositories
+type Conn interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+	BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error)
+}
+
+// WithSlowQueryLog returns db instrumented to log a warning for statements taking at least threshold.
+// A threshold of 0 disables logging and returns db itself, so the normal path has no overhead.
+// Only statements issued directly on the pool are timed; statements inside a transaction are not.
+func WithSlowQueryLog(db *sql.DB, threshold time.Duration) Conn {
+	if threshold <= 0 {
+		return db
+	}
+	return &slowQueryLogger{db: db, threshold: threshold}
+}
+
+// slowQueryLogger times statements issued on the wrapped pool
+type slowQueryLogger struct {
+	db        *sql.DB
+	threshold time.Duration
+}
+
+func (l *slowQueryLogger) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	start := time.Now()
+	result, err := l.db.ExecContext(ctx, query, args...)
+	l.observe(ctx, query, start)
+	return result, err
+}
+
+// QueryContext times the query until its first result is available; row iteration is not included
+func (l *slowQueryLogger) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	start := time.Now()
+	rows, err := l.db.QueryContext(ctx, query, args...)
+	l.observe(ctx, query, start)
+	return rows, err
+}
+
+func (l *slowQueryLogger) QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row {
+	start := time.Now()
+	row := l.db.QueryRowContext(ctx, query, args...)
+	l.observe(ctx, query, start)
+	return row
+}
+
+func (l *slowQueryLogger) BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error) {
+	return l.db.BeginTx(ctx, opts)
+}
+
+// observe logs query when it ran for at least the threshold
+func (l *slowQueryLogger) observe(ctx context.Context, query string, start time.Time) {
+	elapsed := time.Since(start)
+	if elapsed < l.threshold {
+		return
+	}
+
+	logger.Warn("Slow query",
+		zap.String("query", compactQuery(query)),
+		zap.Duration("duration", elapsed),
+		zap.Duration("threshold", l.threshold),
+		zap.String("request_id", middleware.GetReqID(ctx)),
+	)
+}
+
+// compactQuery collapses whitespace so multi-line SQL fits on one log line, truncating long statements
+func compactQuery(query string) string {
+	compact := strings.Join(strings.Fields(query), " ")
+	if len(compact) > maxLoggedQueryLength {
+		return compact[:maxLoggedQueryLength] + "..."
+	}
+	return compact
+}
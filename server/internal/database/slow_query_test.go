@@ -0,0 +1,76 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/moabdelazem/k8s-app/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// observeLogs swaps the global logger for an in-memory observer for the test duration
+func observeLogs(t *testing.T) *observer.ObservedLogs {
+	core, logs := observer.New(zapcore.WarnLevel)
+	previous := logger.Log
+	logger.Log = zap.New(core)
+	t.Cleanup(func() { logger.Log = previous })
+	return logs
+}
+
+func TestWithSlowQueryLog_WarnsOnSlowQuery(t *testing.T) {
+	logs := observeLogs(t)
+	stub := &stubDriver{delay: 20 * time.Millisecond}
+	db := sql.OpenDB(stubConnector{stub})
+	defer db.Close()
+
+	conn := WithSlowQueryLog(db, 10*time.Millisecond)
+	ctx := context.WithValue(context.Background(), middleware.RequestIDKey, "req-42")
+
+	_, err := conn.ExecContext(ctx, `
+		UPDATE polls
+		SET is_active = false
+		WHERE expires_at <= NOW()`)
+	require.NoError(t, err)
+
+	entries := logs.FilterMessage("Slow query").All()
+	require.Len(t, entries, 1)
+	fields := entries[0].ContextMap()
+	assert.Equal(t, "UPDATE polls SET is_active = false WHERE expires_at <= NOW()", fields["query"])
+	assert.Equal(t, "req-42", fields["request_id"])
+	assert.GreaterOrEqual(t, fields["duration"], 20*time.Millisecond)
+}
+
+func TestWithSlowQueryLog_FastQueryNotLogged(t *testing.T) {
+	logs := observeLogs(t)
+	db := sql.OpenDB(stubConnector{&stubDriver{}})
+	defer db.Close()
+
+	_, err := WithSlowQueryLog(db, time.Second).ExecContext(context.Background(), "SELECT 1")
+	require.NoError(t, err)
+
+	assert.Zero(t, logs.Len())
+}
+
+func TestWithSlowQueryLog_DisabledReturnsPool(t *testing.T) {
+	db := sql.OpenDB(stubConnector{&stubDriver{}})
+	defer db.Close()
+
+	assert.Same(t, db, WithSlowQueryLog(db, 0))
+}
+
+func TestCompactQuery_Truncates(t *testing.T) {
+	long := "SELECT " + strings.Repeat("column_name, ", 50) + "id FROM polls"
+
+	compact := compactQuery(long)
+
+	assert.Len(t, compact, maxLoggedQueryLength+len("..."))
+	assert.True(t, strings.HasSuffix(compact, "..."))
+}
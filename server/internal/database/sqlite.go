@@ -0,0 +1,78 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	_ "embed"
+	"fmt"
+
+	"github.com/moabdelazem/k8s-app/pkg/logger"
+	"go.uber.org/zap"
+	_ "modernc.org/sqlite"
+)
+
+// Drivers selectable with Config.Driver
+const (
+	DriverPostgres = "postgres"
+	DriverSQLite   = "sqlite"
+)
+
+// sqliteSchema creates the tables of a SQLite database; there is no init script to do it
+//
+//go:embed sqlite_schema.sql
+var sqliteSchema string
+
+// OpenSQLite opens the SQLite database file at path, or a private in-memory database for ":memory:",
+// and creates its schema. The pool is limited to one connection that is never recycled: SQLite runs
+// one writer at a time, and each new connection to ":memory:" would open an empty database.
+func OpenSQLite(ctx context.Context, path string) (*sql.DB, error) {
+	// Timestamps are written in a format SQLite's date functions understand
+	db, err := sql.Open(DriverSQLite, "file:"+path+"?_pragma=foreign_keys(1)&_time_format=sqlite")
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+	db.SetMaxOpenConns(1)
+	db.SetMaxIdleConns(1)
+	db.SetConnMaxLifetime(0)
+
+	if _, err := db.ExecContext(ctx, sqliteSchema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to create sqlite schema: %w", err)
+	}
+	// The schema is embedded in this build, so it is always the version the build requires
+	if _, err := db.ExecContext(ctx, `INSERT OR IGNORE INTO schema_migrations (version) VALUES (?)`, SchemaVersion); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to record schema version: %w", err)
+	}
+	return db, nil
+}
+
+// newSQLiteConnection opens the SQLite database named by cfg.DBName as the global connection
+// A local file needs no retries, and the pool settings are replaced by those of OpenSQLite.
+func newSQLiteConnection(cfg *Config) (*sql.DB, error) {
+	timeout := cfg.ValidationTimeout
+	if timeout == 0 {
+		timeout = defaultValidationTimeout
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	db, err := OpenSQLite(ctx, cfg.DBName)
+	if err != nil {
+		return nil, err
+	}
+	if err := validate(db, cfg.ValidationQuery, timeout); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to connect to database: %w", err)
+	}
+
+	logger.Info("Database connection established",
+		zap.String("driver", DriverSQLite),
+		zap.String("database", cfg.DBName),
+	)
+	DB = db
+	validationQuery = cfg.ValidationQuery
+	validationTimeout = timeout
+	maxIdleConns = 1
+	return db, nil
+}
@@ -0,0 +1,158 @@
+-- SQLite schema for DB_DRIVER=sqlite, applied on connect
+-- It mirrors the tables of init-scripts/init.sql that the core poll operations use: creating and
+-- reading polls, voting and listing. Changes to those tables must be repeated here.
+-- UUIDs are stored as text and timestamps as text with a zone offset, which the driver
+-- reads back as time.Time because the columns are declared TIMESTAMP.
+
+CREATE TABLE IF NOT EXISTS polls (
+    id TEXT PRIMARY KEY DEFAULT (
+        lower(hex(randomblob(4)) || '-' || hex(randomblob(2)) || '-4' || substr(hex(randomblob(2)), 2) || '-' ||
+        substr('89ab', 1 + (abs(random()) % 4), 1) || substr(hex(randomblob(2)), 2) || '-' || hex(randomblob(6)))
+    ),
+    question TEXT NOT NULL CHECK (
+        length(question) >= 5
+        AND length(question) <= 500
+    ),
+    description TEXT,
+    created_at TIMESTAMP DEFAULT (strftime('%Y-%m-%d %H:%M:%f+00:00', 'now')),
+    expires_at TIMESTAMP,
+    is_active BOOLEAN DEFAULT true,
+    total_votes INTEGER DEFAULT 0,
+    owner_id TEXT,
+    allow_weighted BOOLEAN DEFAULT false,
+    require_confirmation BOOLEAN DEFAULT false,
+    quiz_mode BOOLEAN DEFAULT false,
+    poll_group TEXT,
+    randomize_options BOOLEAN DEFAULT false,
+    allowlist_only BOOLEAN DEFAULT false,
+    allow_write_in BOOLEAN DEFAULT false,
+    max_votes INTEGER CHECK (max_votes >= 1),
+    voting_window_start TEXT,
+    voting_window_end TEXT,
+    timezone TEXT,
+    acknowledgement_mode BOOLEAN DEFAULT false,
+    featured BOOLEAN DEFAULT false,
+    featured_rank INTEGER,
+    metadata TEXT,
+    closed_at TIMESTAMP,
+    deleted_at TIMESTAMP,
+    -- The vote triggers update total_votes, so a vote past the capacity fails this check
+    CONSTRAINT poll_within_capacity CHECK (
+        max_votes IS NULL
+        OR total_votes <= max_votes
+    )
+);
+
+CREATE TABLE IF NOT EXISTS poll_options (
+    id TEXT PRIMARY KEY DEFAULT (
+        lower(hex(randomblob(4)) || '-' || hex(randomblob(2)) || '-4' || substr(hex(randomblob(2)), 2) || '-' ||
+        substr('89ab', 1 + (abs(random()) % 4), 1) || substr(hex(randomblob(2)), 2) || '-' || hex(randomblob(6)))
+    ),
+    poll_id TEXT NOT NULL REFERENCES polls (id) ON DELETE CASCADE,
+    option_text TEXT NOT NULL CHECK (
+        length(option_text) >= 1
+        AND length(option_text) <= 200
+    ),
+    vote_count INTEGER DEFAULT 0,
+    position INTEGER NOT NULL,
+    is_correct BOOLEAN NOT NULL DEFAULT false,
+    created_at TIMESTAMP DEFAULT (strftime('%Y-%m-%d %H:%M:%f+00:00', 'now')),
+    CONSTRAINT unique_poll_position UNIQUE (poll_id, position)
+);
+
+CREATE TABLE IF NOT EXISTS votes (
+    id TEXT PRIMARY KEY DEFAULT (
+        lower(hex(randomblob(4)) || '-' || hex(randomblob(2)) || '-4' || substr(hex(randomblob(2)), 2) || '-' ||
+        substr('89ab', 1 + (abs(random()) % 4), 1) || substr(hex(randomblob(2)), 2) || '-' || hex(randomblob(6)))
+    ),
+    poll_id TEXT NOT NULL REFERENCES polls (id) ON DELETE CASCADE,
+    option_id TEXT NOT NULL REFERENCES poll_options (id) ON DELETE CASCADE,
+    voter_identifier TEXT NOT NULL,
+    weight INTEGER NOT NULL DEFAULT 1 CHECK (weight >= 1),
+    campaign TEXT,
+    voted_at TIMESTAMP DEFAULT (strftime('%Y-%m-%d %H:%M:%f+00:00', 'now')),
+    CONSTRAINT unique_voter_per_poll UNIQUE (poll_id, voter_identifier)
+);
+
+CREATE TABLE IF NOT EXISTS write_in_votes (
+    id TEXT PRIMARY KEY DEFAULT (
+        lower(hex(randomblob(4)) || '-' || hex(randomblob(2)) || '-4' || substr(hex(randomblob(2)), 2) || '-' ||
+        substr('89ab', 1 + (abs(random()) % 4), 1) || substr(hex(randomblob(2)), 2) || '-' || hex(randomblob(6)))
+    ),
+    poll_id TEXT NOT NULL REFERENCES polls (id) ON DELETE CASCADE,
+    voter_identifier TEXT NOT NULL,
+    write_in_text TEXT NOT NULL CHECK (
+        length(write_in_text) >= 1
+        AND length(write_in_text) <= 200
+    ),
+    weight INTEGER NOT NULL DEFAULT 1 CHECK (weight >= 1),
+    campaign TEXT,
+    voted_at TIMESTAMP DEFAULT (strftime('%Y-%m-%d %H:%M:%f+00:00', 'now')),
+    CONSTRAINT unique_write_in_voter_per_poll UNIQUE (poll_id, voter_identifier)
+);
+
+CREATE TABLE IF NOT EXISTS poll_milestones (
+    poll_id TEXT NOT NULL REFERENCES polls (id) ON DELETE CASCADE,
+    milestone INTEGER NOT NULL,
+    reached_at TIMESTAMP DEFAULT (strftime('%Y-%m-%d %H:%M:%f+00:00', 'now')),
+    PRIMARY KEY (poll_id, milestone)
+);
+
+CREATE TABLE IF NOT EXISTS outbox_events (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    poll_id TEXT NOT NULL,
+    event_type TEXT NOT NULL,
+    data BLOB,
+    occurred_at TIMESTAMP DEFAULT (strftime('%Y-%m-%d %H:%M:%f+00:00', 'now')),
+    published_at TIMESTAMP,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMP,
+    dead_lettered_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_polls_created_at ON polls (created_at DESC);
+
+CREATE INDEX IF NOT EXISTS idx_poll_options_poll_id ON poll_options (poll_id, position);
+
+CREATE INDEX IF NOT EXISTS idx_votes_poll_id ON votes (poll_id);
+
+CREATE INDEX IF NOT EXISTS idx_votes_voter_history ON votes (voter_identifier, voted_at DESC);
+
+CREATE INDEX IF NOT EXISTS idx_write_in_votes_voter_history ON write_in_votes (voter_identifier, voted_at DESC);
+
+CREATE TRIGGER IF NOT EXISTS trigger_update_poll_votes_insert
+AFTER INSERT ON votes
+BEGIN
+    UPDATE polls SET total_votes = total_votes + NEW.weight WHERE id = NEW.poll_id;
+END;
+
+CREATE TRIGGER IF NOT EXISTS trigger_update_poll_votes_delete
+AFTER DELETE ON votes
+BEGIN
+    UPDATE polls SET total_votes = total_votes - OLD.weight WHERE id = OLD.poll_id;
+END;
+
+CREATE TRIGGER IF NOT EXISTS trigger_update_poll_write_in_votes_insert
+AFTER INSERT ON write_in_votes
+BEGIN
+    UPDATE polls SET total_votes = total_votes + NEW.weight WHERE id = NEW.poll_id;
+END;
+
+CREATE TRIGGER IF NOT EXISTS trigger_update_poll_write_in_votes_delete
+AFTER DELETE ON write_in_votes
+BEGIN
+    UPDATE polls SET total_votes = total_votes - OLD.weight WHERE id = OLD.poll_id;
+END;
+
+-- Only the maximum is enforced: SQLite has no deferred triggers to check the minimum at commit
+CREATE TRIGGER IF NOT EXISTS trigger_poll_option_max
+BEFORE INSERT ON poll_options
+WHEN (SELECT COUNT(*) FROM poll_options WHERE poll_id = NEW.poll_id) >= 10
+BEGIN
+    SELECT RAISE(ABORT, 'poll_options_count: a poll cannot have more than 10 options');
+END;
+
+CREATE TABLE IF NOT EXISTS schema_migrations (
+    version INTEGER PRIMARY KEY,
+    applied_at TIMESTAMP DEFAULT (strftime('%Y-%m-%d %H:%M:%f+00:00', 'now'))
+);
@@ -0,0 +1,63 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// Warmup pre-fills the pool by opening and pinging n connections concurrently,
+// avoiding the latency of lazily opened connections on the first requests after startup.
+// n is capped at MaxIdleConns since the pool would close any extra connections once released.
+// It returns how many connections were warmed; failed connections are reported but leave the pool usable.
+func Warmup(ctx context.Context, n int) (int, error) {
+	if DB == nil {
+		return 0, fmt.Errorf("database connection is nil")
+	}
+	return warmup(ctx, DB, min(n, maxIdleConns))
+}
+
+func warmup(ctx context.Context, db *sql.DB, n int) (int, error) {
+	if n <= 0 {
+		return 0, nil
+	}
+
+	// Every connection is held until all are open, otherwise the pool would hand out the same one again
+	conns := make([]*sql.Conn, n)
+	errs := make([]error, n)
+	var wg sync.WaitGroup
+	for i := range n {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			conn, err := db.Conn(ctx)
+			if err != nil {
+				errs[i] = err
+				return
+			}
+			if err := conn.PingContext(ctx); err != nil {
+				conn.Close()
+				errs[i] = err
+				return
+			}
+			conns[i] = conn
+		}()
+	}
+	wg.Wait()
+
+	// Releasing the connections returns them to the pool as idle
+	warmed := 0
+	for _, conn := range conns {
+		if conn != nil {
+			conn.Close()
+			warmed++
+		}
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		return warmed, fmt.Errorf("warmed %d of %d connections: %w", warmed, n, err)
+	}
+	return warmed, nil
+}
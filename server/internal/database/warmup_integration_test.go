@@ -0,0 +1,46 @@
+//go:build integration
+
+package database
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// integrationEnv reads a connection setting for the integration database
+func integrationEnv(key, fallback string) string {
+	if value, ok := os.LookupEnv(key); ok {
+		return value
+	}
+	return fallback
+}
+
+func TestWarmup_Integration(t *testing.T) {
+	db, err := NewConnection(&Config{
+		Host:            integrationEnv("DB_HOST", "localhost"),
+		Port:            integrationEnv("DB_PORT", "5432"),
+		User:            integrationEnv("DB_USER", "devuser"),
+		Password:        integrationEnv("DB_PASSWORD", "devpassword"),
+		DBName:          integrationEnv("DB_NAME", "k8s_app_dev"),
+		SSLMode:         integrationEnv("DB_SSLMODE", "disable"),
+		MaxOpenConns:    10,
+		MaxIdleConns:    4,
+		ConnMaxLifetime: time.Minute,
+		MaxRetries:      1,
+	})
+	require.NoError(t, err)
+	defer Close()
+
+	before := db.Stats().Idle
+
+	warmed, err := Warmup(context.Background(), 4)
+
+	require.NoError(t, err)
+	assert.Equal(t, 4, warmed)
+	assert.Greater(t, db.Stats().Idle, before)
+}
@@ -0,0 +1,93 @@
+// Package geoip resolves client addresses to coarse geographic regions.
+package geoip
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"net/netip"
+	"os"
+	"slices"
+	"strings"
+)
+
+// Resolver maps a client address to a coarse region such as a country code
+type Resolver interface {
+	Region(addr netip.Addr) (string, bool)
+}
+
+// Table is a Resolver backed by CIDR prefixes; the most specific matching prefix wins.
+// Lookups cost one map access per distinct prefix length, so they stay fast for large tables.
+// A Table must not be modified once it is in use.
+type Table struct {
+	regions map[netip.Prefix]string
+	bits    []int // Distinct prefix lengths, longest first
+}
+
+func NewTable() *Table {
+	return &Table{regions: make(map[netip.Prefix]string)}
+}
+
+// Add maps every address in prefix to region
+func (t *Table) Add(prefix netip.Prefix, region string) {
+	prefix = prefix.Masked()
+	t.regions[prefix] = region
+	if !slices.Contains(t.bits, prefix.Bits()) {
+		t.bits = append(t.bits, prefix.Bits())
+		slices.Sort(t.bits)
+		slices.Reverse(t.bits)
+	}
+}
+
+// Region returns the region of the most specific prefix containing addr
+func (t *Table) Region(addr netip.Addr) (string, bool) {
+	addr = addr.Unmap()
+	for _, bits := range t.bits {
+		prefix, err := addr.Prefix(bits)
+		if err != nil {
+			continue // Longer than the address family allows
+		}
+		if region, ok := t.regions[prefix]; ok {
+			return region, true
+		}
+	}
+	return "", false
+}
+
+// Load reads a table from CSV rows of cidr,region.
+// Blank lines and lines starting with # are ignored.
+func Load(r io.Reader) (*Table, error) {
+	reader := csv.NewReader(r)
+	reader.Comment = '#'
+	reader.FieldsPerRecord = 2
+	reader.TrimLeadingSpace = true
+
+	table := NewTable()
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return table, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid region table: %w", err)
+		}
+
+		prefix, err := netip.ParsePrefix(strings.TrimSpace(record[0]))
+		if err != nil {
+			line, _ := reader.FieldPos(0)
+			return nil, fmt.Errorf("invalid region table: line %d: %w", line, err)
+		}
+		table.Add(prefix, strings.TrimSpace(record[1]))
+	}
+}
+
+// LoadFile reads a table from a CSV file; see Load
+func LoadFile(path string) (*Table, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open region table: %w", err)
+	}
+	defer f.Close()
+	return Load(f)
+}
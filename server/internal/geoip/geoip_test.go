@@ -0,0 +1,49 @@
+package geoip
+
+import (
+	"net/netip"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTable_Region(t *testing.T) {
+	table, err := Load(strings.NewReader(`# cidr,region
+203.0.113.0/24,AU
+203.0.113.128/25,NZ
+
+2001:db8::/32,DE
+`))
+	require.NoError(t, err)
+
+	tests := []struct {
+		name   string
+		addr   string
+		want   string
+		wantOK bool
+	}{
+		{name: "ipv4 prefix", addr: "203.0.113.7", want: "AU", wantOK: true},
+		{name: "most specific prefix wins", addr: "203.0.113.200", want: "NZ", wantOK: true},
+		{name: "ipv4-mapped ipv6", addr: "::ffff:203.0.113.7", want: "AU", wantOK: true},
+		{name: "ipv6 prefix", addr: "2001:db8::1", want: "DE", wantOK: true},
+		{name: "unknown address", addr: "198.51.100.1", wantOK: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			region, ok := table.Region(netip.MustParseAddr(tt.addr))
+			assert.Equal(t, tt.wantOK, ok)
+			assert.Equal(t, tt.want, region)
+		})
+	}
+}
+
+func TestLoad_RejectsInvalidRows(t *testing.T) {
+	_, err := Load(strings.NewReader("203.0.113.0/24,AU\nnot-a-cidr,FR\n"))
+	assert.ErrorContains(t, err, "line 2")
+
+	_, err = Load(strings.NewReader("203.0.113.0/24\n"))
+	assert.Error(t, err)
+}
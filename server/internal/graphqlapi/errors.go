@@ -0,0 +1,66 @@
+package graphqlapi
+
+import (
+	"errors"
+
+	"github.com/moabdelazem/k8s-app/internal/service"
+	"github.com/moabdelazem/k8s-app/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// Codes of errors raised by the GraphQL layer itself; service errors keep their REST error code
+const (
+	codeBadRequest      = "bad_request"
+	codeUnauthenticated = "unauthenticated"
+	codeReadOnly        = "read_only"
+	codeInternal        = "internal"
+)
+
+// gqlError is a resolver error carrying its error code in the "code" extension
+type gqlError struct {
+	code    string
+	message string
+}
+
+func (e *gqlError) Error() string {
+	return e.message
+}
+
+// Extensions is read by the executor to fill the error's extensions
+func (e *gqlError) Extensions() map[string]any {
+	return map[string]any{"code": e.code}
+}
+
+// resolverError maps a service error to a GraphQL error, mirroring the REST error renderer.
+// Domain and validation errors keep their message and error code; anything unexpected is
+// logged and returned with fallback as the message.
+func resolverError(field string, err error, fallback string) error {
+	var validationErr *service.ValidationError
+
+	switch {
+	case errors.Is(err, service.ErrPollNotFound):
+		return &gqlError{code: service.CodePollNotFound, message: err.Error()}
+	case errors.Is(err, service.ErrPollDeleted):
+		return &gqlError{code: service.CodePollDeleted, message: err.Error()}
+	case errors.Is(err, service.ErrPollDeleteProtected):
+		return &gqlError{code: service.CodePollDeleteProtected, message: err.Error()}
+	case errors.Is(err, service.ErrActivePollLimitReached):
+		return &gqlError{code: service.CodeActivePollLimitReached, message: err.Error()}
+	case errors.Is(err, service.ErrVoterNetworkBlocked):
+		return &gqlError{code: service.CodeVoterNetworkBlocked, message: err.Error()}
+	case errors.Is(err, service.ErrVoterNotAllowed):
+		return &gqlError{code: service.CodeVoterNotAllowed, message: err.Error()}
+	case errors.As(err, &validationErr):
+		code := validationErr.Code
+		if code == "" {
+			code = codeBadRequest
+		}
+		return &gqlError{code: code, message: validationErr.Message}
+	case errors.Is(err, service.ErrTemporarilyUnavailable):
+		logger.Warn("Transient database error", zap.Error(err), zap.String("graphql_field", field))
+		return &gqlError{code: service.CodeTemporarilyUnavailable, message: "Service temporarily unavailable, please retry"}
+	default:
+		logger.Error(fallback, zap.Error(err), zap.String("graphql_field", field))
+		return &gqlError{code: codeInternal, message: fallback}
+	}
+}
@@ -0,0 +1,85 @@
+// Package graphqlapi serves an experimental GraphQL view of the poll API.
+// Every resolver delegates to service.PollService, so it applies the same rules as the REST routes.
+package graphqlapi
+
+import (
+	"context"
+	_ "embed"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	graphql "github.com/graph-gophers/graphql-go"
+	"github.com/graph-gophers/graphql-go/relay"
+	"github.com/moabdelazem/k8s-app/internal/maintenance"
+	"github.com/moabdelazem/k8s-app/internal/service"
+	"github.com/moabdelazem/k8s-app/pkg/auth"
+	"github.com/moabdelazem/k8s-app/pkg/logger"
+	"github.com/moabdelazem/k8s-app/pkg/response"
+	"go.uber.org/zap"
+)
+
+//go:embed schema.graphql
+var schema string
+
+// maxDepth bounds query nesting; the deepest useful query, results { poll { options } }, needs 4
+const maxDepth = 8
+
+// Options are the access controls the REST routes enforce, applied to resolvers by the handler
+type Options struct {
+	JWTSecret            string                       // Verifies "Authorization: Bearer <token>"; empty = tokens are rejected
+	RequireAuthForCreate bool                         // createPoll needs a valid token, as on the REST route
+	Maintenance          *maintenance.Mode            // Mutations fail while read-only; nil = never read-only
+	Identify             func(r *http.Request) string // Voter identifier of a request, as on the REST routes; required
+	CheckVoter           func(r *http.Request) error  // Rejects votes from a request, e.g. from a blocked network; nil = accept all
+}
+
+// NewHandler returns the /graphql handler resolving queries with svc
+func NewHandler(svc *service.PollService, opts Options) (http.Handler, error) {
+	if opts.Identify == nil {
+		return nil, errors.New("graphqlapi: Options.Identify is required")
+	}
+	parsed, err := graphql.ParseSchema(schema, &Resolver{service: svc, opts: opts}, graphql.MaxDepth(maxDepth))
+	if err != nil {
+		return nil, err
+	}
+	exec := &relay.Handler{Schema: parsed}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			response.Error(w, http.StatusMethodNotAllowed, "GraphQL queries are sent with POST")
+			return
+		}
+
+		// Tokens are optional, as on the REST routes; anonymous callers are identified by their address
+		if token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); ok && token != "" {
+			claims, err := auth.ParseToken(opts.JWTSecret, token, time.Now())
+			if opts.JWTSecret == "" || err != nil {
+				logger.Warn("Rejected unauthenticated GraphQL request",
+					zap.Error(err),
+					zap.String("remote_addr", r.RemoteAddr),
+				)
+				response.Unauthorized(w, "Invalid or expired token")
+				return
+			}
+			r = r.WithContext(auth.WithClaims(r.Context(), claims))
+		}
+
+		exec.ServeHTTP(w, r.WithContext(withRequest(r.Context(), r)))
+	}), nil
+}
+
+type requestKey struct{}
+
+// withRequest stores the HTTP request in ctx so resolvers can identify its caller
+func withRequest(ctx context.Context, r *http.Request) context.Context {
+	return context.WithValue(ctx, requestKey{}, r)
+}
+
+// requestFromContext returns the request stored by withRequest, or nil outside the handler
+func requestFromContext(ctx context.Context) *http.Request {
+	r, _ := ctx.Value(requestKey{}).(*http.Request)
+	return r
+}
@@ -0,0 +1,280 @@
+package graphqlapi
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/moabdelazem/k8s-app/internal/maintenance"
+	"github.com/moabdelazem/k8s-app/internal/models"
+	"github.com/moabdelazem/k8s-app/internal/repository"
+	"github.com/moabdelazem/k8s-app/internal/service"
+	"github.com/moabdelazem/k8s-app/pkg/auth"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testSecret signs the tokens of authenticated test requests
+const testSecret = "test-secret"
+
+// graphqlResponse is the GraphQL response envelope
+type graphqlResponse struct {
+	Data   json.RawMessage `json:"data"`
+	Errors []struct {
+		Message    string         `json:"message"`
+		Extensions map[string]any `json:"extensions"`
+	} `json:"errors"`
+}
+
+// testIdentify identifies requests as the REST routes do by default: the token subject, or the peer address
+func testIdentify(r *http.Request) string {
+	if claims := auth.ClaimsFromContext(r.Context()); claims != nil {
+		return "user:" + claims.Subject
+	}
+	return r.RemoteAddr
+}
+
+// newTestHandler serves svc with the default test options, overridden by opts when given
+func newTestHandler(t *testing.T, svc *service.PollService, opts Options) http.Handler {
+	t.Helper()
+	if opts.Identify == nil {
+		opts.Identify = testIdentify
+	}
+	if opts.JWTSecret == "" {
+		opts.JWTSecret = testSecret
+	}
+	h, err := NewHandler(svc, opts)
+	require.NoError(t, err)
+	return h
+}
+
+// execute posts query with variables as remoteAddr, authenticated as subject unless it is empty
+func execute(t *testing.T, h http.Handler, query string, variables map[string]any, remoteAddr, subject string) graphqlResponse {
+	t.Helper()
+	body, err := json.Marshal(map[string]any{"query": query, "variables": variables})
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(string(body)))
+	req.RemoteAddr = remoteAddr
+	if subject != "" {
+		token, err := auth.SignToken(testSecret, auth.Claims{Subject: subject, ExpiresAt: time.Now().Add(time.Hour).Unix()})
+		require.NoError(t, err)
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	var resp graphqlResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	return resp
+}
+
+// createTestPoll creates a two-option poll through the service
+func createTestPoll(t *testing.T, svc *service.PollService) *models.PollWithOptions {
+	t.Helper()
+	poll, _, err := svc.CreatePoll(context.Background(), &models.CreatePollRequest{
+		Question: "Which feature should we build next?",
+		Options:  []string{"Feature A", "Feature B"},
+	}, "owner-1")
+	require.NoError(t, err)
+	return poll
+}
+
+func TestPollQuery(t *testing.T) {
+	svc := service.NewPollService(repository.NewInMemoryPollRepository(), service.PollServiceConfig{})
+	h := newTestHandler(t, svc, Options{})
+	poll := createTestPoll(t, svc)
+	_, _, err := svc.CastVote(context.Background(), poll.ID, poll.Options[1].ID, "voter-1", 0, "")
+	require.NoError(t, err)
+
+	resp := execute(t, h, `query($id: ID!) { poll(id: $id) { id question totalVotes options { text voteCount percentage } } }`,
+		map[string]any{"id": poll.ID.String()}, "192.0.2.1:1234", "")
+	require.Empty(t, resp.Errors)
+
+	var data struct {
+		Poll struct {
+			ID         string
+			Question   string
+			TotalVotes int
+			Options    []struct {
+				Text       string
+				VoteCount  int
+				Percentage float64
+			}
+		}
+	}
+	require.NoError(t, json.Unmarshal(resp.Data, &data))
+	assert.Equal(t, poll.ID.String(), data.Poll.ID)
+	assert.Equal(t, "Which feature should we build next?", data.Poll.Question)
+	assert.Equal(t, 1, data.Poll.TotalVotes)
+	require.Len(t, data.Poll.Options, 2)
+	assert.Equal(t, "Feature B", data.Poll.Options[1].Text)
+	assert.Equal(t, 1, data.Poll.Options[1].VoteCount)
+	assert.Equal(t, 100.0, data.Poll.Options[1].Percentage)
+}
+
+func TestPollQuery_Errors(t *testing.T) {
+	svc := service.NewPollService(repository.NewInMemoryPollRepository(), service.PollServiceConfig{})
+	h := newTestHandler(t, svc, Options{})
+
+	resp := execute(t, h, `{ poll(id: "not-a-uuid") { id } }`, nil, "192.0.2.1:1234", "")
+	require.Len(t, resp.Errors, 1)
+	assert.Equal(t, codeBadRequest, resp.Errors[0].Extensions["code"])
+
+	resp = execute(t, h, `{ poll(id: "00000000-0000-0000-0000-000000000001") { id } }`, nil, "192.0.2.1:1234", "")
+	require.Len(t, resp.Errors, 1)
+	assert.Equal(t, service.CodePollNotFound, resp.Errors[0].Extensions["code"], "the REST error code is carried along")
+}
+
+func TestPollsQuery(t *testing.T) {
+	svc := service.NewPollService(repository.NewInMemoryPollRepository(), service.PollServiceConfig{})
+	h := newTestHandler(t, svc, Options{})
+	createTestPoll(t, svc)
+	createTestPoll(t, svc)
+
+	resp := execute(t, h, `{ polls(limit: 1) { total limit offset polls { question options { text } } } }`, nil, "192.0.2.1:1234", "")
+	require.Empty(t, resp.Errors)
+
+	var data struct {
+		Polls struct {
+			Total, Limit, Offset int
+			Polls                []struct {
+				Question string
+				Options  []struct{ Text string }
+			}
+		}
+	}
+	require.NoError(t, json.Unmarshal(resp.Data, &data))
+	assert.Equal(t, 2, data.Polls.Total)
+	assert.Equal(t, 1, data.Polls.Limit)
+	require.Len(t, data.Polls.Polls, 1)
+	assert.Len(t, data.Polls.Polls[0].Options, 2)
+}
+
+func TestVoteMutation(t *testing.T) {
+	svc := service.NewPollService(repository.NewInMemoryPollRepository(), service.PollServiceConfig{})
+	h := newTestHandler(t, svc, Options{})
+	poll := createTestPoll(t, svc)
+
+	vote := `mutation($poll: ID!, $option: ID!) {
+		vote(pollId: $poll, optionId: $option) { results { hasVoted votedOption poll { totalVotes } } confirmation { token } }
+	}`
+	variables := map[string]any{"poll": poll.ID.String(), "option": poll.Options[0].ID.String()}
+
+	resp := execute(t, h, vote, variables, "192.0.2.1:1234", "")
+	require.Empty(t, resp.Errors)
+	var data struct {
+		Vote struct {
+			Results struct {
+				HasVoted    bool
+				VotedOption string
+				Poll        struct{ TotalVotes int }
+			}
+			Confirmation *struct{ Token string }
+		}
+	}
+	require.NoError(t, json.Unmarshal(resp.Data, &data))
+	assert.True(t, data.Vote.Results.HasVoted)
+	assert.Equal(t, poll.Options[0].ID.String(), data.Vote.Results.VotedOption)
+	assert.Equal(t, 1, data.Vote.Results.Poll.TotalVotes)
+	assert.Nil(t, data.Vote.Confirmation)
+
+	// The requester is identified as on the REST routes, so a second vote is rejected
+	resp = execute(t, h, vote, variables, "192.0.2.1:1234", "")
+	require.Len(t, resp.Errors, 1)
+	assert.Equal(t, service.CodeAlreadyVoted, resp.Errors[0].Extensions["code"])
+
+	// ...and the results query reports the requester's vote
+	resp = execute(t, h, `query($poll: ID!) { results(pollId: $poll) { hasVoted leading } }`, variables, "192.0.2.1:1234", "")
+	require.Empty(t, resp.Errors)
+	assert.JSONEq(t, `{"results": {"hasVoted": true, "leading": ["`+poll.Options[0].ID.String()+`"]}}`, string(resp.Data))
+}
+
+func TestVoteMutation_CheckVoter(t *testing.T) {
+	svc := service.NewPollService(repository.NewInMemoryPollRepository(), service.PollServiceConfig{})
+	h := newTestHandler(t, svc, Options{CheckVoter: func(*http.Request) error { return service.ErrVoterNetworkBlocked }})
+	poll := createTestPoll(t, svc)
+
+	resp := execute(t, h, `mutation($poll: ID!, $option: ID!) { vote(pollId: $poll, optionId: $option) { results { hasVoted } } }`,
+		map[string]any{"poll": poll.ID.String(), "option": poll.Options[0].ID.String()}, "192.0.2.1:1234", "")
+	require.Len(t, resp.Errors, 1)
+	assert.Equal(t, service.CodeVoterNetworkBlocked, resp.Errors[0].Extensions["code"])
+
+	results, err := svc.GetPollResults(context.Background(), poll.ID, "")
+	require.NoError(t, err)
+	assert.Zero(t, results.TotalVotes)
+}
+
+func TestCreatePollMutation(t *testing.T) {
+	createPoll := `mutation { createPoll(input: {question: "Tabs or spaces?", options: ["Tabs", "Spaces"]}) { poll { question options { text } } warnings } }`
+
+	t.Run("owned by the requester", func(t *testing.T) {
+		repo := repository.NewInMemoryPollRepository()
+		svc := service.NewPollService(repo, service.PollServiceConfig{})
+		h := newTestHandler(t, svc, Options{})
+
+		resp := execute(t, h, createPoll, nil, "192.0.2.1:1234", "alice")
+		require.Empty(t, resp.Errors)
+		assert.JSONEq(t, `{"createPoll": {"poll": {"question": "Tabs or spaces?", "options": [{"text": "Tabs"}, {"text": "Spaces"}]}, "warnings": ["poll has only 2 options"]}}`, string(resp.Data))
+
+		page, err := svc.ListPolls(context.Background(), 10, 0, false)
+		require.NoError(t, err)
+		require.Len(t, page.Polls, 1)
+		require.NotNil(t, page.Polls[0].OwnerID)
+		assert.Equal(t, "user:alice", *page.Polls[0].OwnerID)
+	})
+
+	t.Run("validation errors keep their code", func(t *testing.T) {
+		svc := service.NewPollService(repository.NewInMemoryPollRepository(), service.PollServiceConfig{})
+		h := newTestHandler(t, svc, Options{})
+
+		resp := execute(t, h, `mutation { createPoll(input: {question: "Tabs or spaces?", options: ["Tabs"]}) { warnings } }`, nil, "192.0.2.1:1234", "")
+		require.Len(t, resp.Errors, 1)
+		assert.Equal(t, service.CodeTooFewOptions, resp.Errors[0].Extensions["code"])
+	})
+
+	t.Run("authentication required", func(t *testing.T) {
+		svc := service.NewPollService(repository.NewInMemoryPollRepository(), service.PollServiceConfig{})
+		h := newTestHandler(t, svc, Options{RequireAuthForCreate: true})
+
+		resp := execute(t, h, createPoll, nil, "192.0.2.1:1234", "")
+		require.Len(t, resp.Errors, 1)
+		assert.Equal(t, codeUnauthenticated, resp.Errors[0].Extensions["code"])
+
+		resp = execute(t, h, createPoll, nil, "192.0.2.1:1234", "alice")
+		assert.Empty(t, resp.Errors)
+	})
+
+	t.Run("read-only mode", func(t *testing.T) {
+		svc := service.NewPollService(repository.NewInMemoryPollRepository(), service.PollServiceConfig{})
+		h := newTestHandler(t, svc, Options{Maintenance: maintenance.NewMode(true)})
+
+		resp := execute(t, h, createPoll, nil, "192.0.2.1:1234", "")
+		require.Len(t, resp.Errors, 1)
+		assert.Equal(t, codeReadOnly, resp.Errors[0].Extensions["code"])
+
+		// Queries are still served
+		resp = execute(t, h, `{ polls { total } }`, nil, "192.0.2.1:1234", "")
+		assert.Empty(t, resp.Errors)
+	})
+}
+
+func TestHandler_RejectsInvalidTokens(t *testing.T) {
+	svc := service.NewPollService(repository.NewInMemoryPollRepository(), service.PollServiceConfig{})
+	h := newTestHandler(t, svc, Options{})
+
+	req := httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(`{"query": "{ polls { total } }"}`))
+	req.Header.Set("Authorization", "Bearer not-a-token")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/graphql", nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+}
@@ -0,0 +1,176 @@
+package graphqlapi
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	graphql "github.com/graph-gophers/graphql-go"
+	"github.com/moabdelazem/k8s-app/internal/models"
+	"github.com/moabdelazem/k8s-app/internal/service"
+	"github.com/moabdelazem/k8s-app/pkg/auth"
+	"github.com/moabdelazem/k8s-app/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// Resolver is the root resolver of the schema's queries and mutations
+type Resolver struct {
+	service *service.PollService
+	opts    Options
+}
+
+// Polls resolves one page of polls
+func (r *Resolver) Polls(ctx context.Context, args struct {
+	Limit      int32
+	Offset     int32
+	ActiveOnly bool
+}) (*pollListResolver, error) {
+	list, err := r.service.ListPolls(ctx, int(args.Limit), int(args.Offset), args.ActiveOnly)
+	if err != nil {
+		return nil, resolverError("polls", err, "Failed to retrieve polls")
+	}
+	return &pollListResolver{list: list}, nil
+}
+
+// Poll resolves a poll with its vote counts
+func (r *Resolver) Poll(ctx context.Context, args struct{ ID graphql.ID }) (*pollResolver, error) {
+	pollID, err := parseID("id", args.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	results, err := r.service.GetPollResults(ctx, pollID, "")
+	if err != nil {
+		return nil, resolverError("poll", err, "Failed to retrieve poll")
+	}
+	return newResultsPollResolver(results), nil
+}
+
+// Results resolves a poll's results as seen by the requester
+func (r *Resolver) Results(ctx context.Context, args struct{ PollID graphql.ID }) (*pollResultsResolver, error) {
+	pollID, err := parseID("pollId", args.PollID)
+	if err != nil {
+		return nil, err
+	}
+
+	results, err := r.service.GetPollResults(ctx, pollID, r.voterIdentifier(ctx))
+	if err != nil {
+		return nil, resolverError("results", err, "Failed to retrieve poll")
+	}
+	return &pollResultsResolver{results: results}, nil
+}
+
+// CreatePoll creates a poll owned by the requester
+func (r *Resolver) CreatePoll(ctx context.Context, args struct{ Input createPollInput }) (*createPollPayloadResolver, error) {
+	if err := r.checkWritable(); err != nil {
+		return nil, err
+	}
+	if r.opts.RequireAuthForCreate && auth.ClaimsFromContext(ctx) == nil {
+		return nil, &gqlError{code: codeUnauthenticated, message: "Authentication required"}
+	}
+
+	poll, warnings, err := r.service.CreatePoll(ctx, args.Input.request(), r.voterIdentifier(ctx))
+	if err != nil {
+		return nil, resolverError("createPoll", err, "Failed to create poll")
+	}
+	return &createPollPayloadResolver{poll: poll, warnings: warnings}, nil
+}
+
+// Vote records a vote for an option or a write-in answer
+func (r *Resolver) Vote(ctx context.Context, args struct {
+	PollID     graphql.ID
+	OptionID   *graphql.ID
+	WriteIn    *string
+	Weight     *int32
+	ShareToken *string
+}) (*votePayloadResolver, error) {
+	if err := r.checkWritable(); err != nil {
+		return nil, err
+	}
+	pollID, err := parseID("pollId", args.PollID)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.checkVoter(ctx); err != nil {
+		return nil, resolverError("vote", err, "Failed to cast vote")
+	}
+
+	var req models.VoteRequest
+	if args.OptionID != nil {
+		if req.OptionID, err = parseID("optionId", *args.OptionID); err != nil {
+			return nil, err
+		}
+	}
+	if args.WriteIn != nil {
+		req.WriteIn = *args.WriteIn
+	}
+	if args.Weight != nil {
+		req.Weight = int64(*args.Weight)
+	}
+	if args.ShareToken != nil {
+		req.ShareToken = *args.ShareToken
+	}
+	if err := service.ValidateRequest(&req); err != nil {
+		return nil, resolverError("vote", err, "Failed to cast vote")
+	}
+	voterIdentifier := r.voterIdentifier(ctx)
+
+	var confirmation *models.VoteConfirmation
+	var receipt *models.VoteReceipt
+	if req.WriteIn != "" {
+		confirmation, receipt, err = r.service.CastWriteInVote(ctx, pollID, req.WriteIn, voterIdentifier, req.Weight, req.ShareToken)
+	} else {
+		confirmation, receipt, err = r.service.CastVote(ctx, pollID, req.OptionID, voterIdentifier, req.Weight, req.ShareToken)
+	}
+	if err != nil {
+		return nil, resolverError("vote", err, "Failed to cast vote")
+	}
+
+	// The poll requires confirmation; the vote does not count until confirmed
+	if confirmation != nil {
+		return &votePayloadResolver{confirmation: &voteConfirmationResolver{confirmation: confirmation}}, nil
+	}
+
+	results, err := r.service.GetPollResults(ctx, pollID, voterIdentifier)
+	if err != nil {
+		logger.Warn("Failed to get updated results after vote", zap.Error(err))
+		// The vote is recorded either way, so the receipt is still handed out
+		return &votePayloadResolver{results: &pollResultsResolver{results: &models.PollResults{Receipt: receipt}}}, nil
+	}
+	results.Receipt = receipt
+	return &votePayloadResolver{results: &pollResultsResolver{results: results}}, nil
+}
+
+// checkWritable rejects mutations while read-only mode is on
+func (r *Resolver) checkWritable() error {
+	if r.opts.Maintenance != nil && r.opts.Maintenance.ReadOnly() {
+		return &gqlError{code: codeReadOnly, message: "Service is in read-only mode; writes are temporarily disabled"}
+	}
+	return nil
+}
+
+// checkVoter applies the vote checks of the REST routes to the requester
+func (r *Resolver) checkVoter(ctx context.Context) error {
+	req := requestFromContext(ctx)
+	if req == nil || r.opts.CheckVoter == nil {
+		return nil
+	}
+	return r.opts.CheckVoter(req)
+}
+
+// voterIdentifier returns the identifier the requester votes and creates polls as
+func (r *Resolver) voterIdentifier(ctx context.Context) string {
+	req := requestFromContext(ctx)
+	if req == nil {
+		return ""
+	}
+	return r.opts.Identify(req)
+}
+
+// parseID parses an ID argument, rejecting invalid ones as a bad request
+func parseID(field string, value graphql.ID) (uuid.UUID, error) {
+	id, err := uuid.Parse(string(value))
+	if err != nil {
+		return uuid.Nil, &gqlError{code: codeBadRequest, message: "Invalid " + field}
+	}
+	return id, nil
+}
@@ -0,0 +1,213 @@
+package graphqlapi
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	graphql "github.com/graph-gophers/graphql-go"
+	"github.com/moabdelazem/k8s-app/internal/models"
+)
+
+// pollResolver resolves a Poll with its options
+type pollResolver struct {
+	poll    *models.Poll
+	options []optionResolver
+}
+
+// newPollResolver resolves a poll listed with its options, which carry no percentages
+func newPollResolver(poll *models.PollWithOptions) *pollResolver {
+	options := make([]optionResolver, len(poll.Options))
+	for i := range poll.Options {
+		options[i] = optionResolver{option: &poll.Options[i]}
+	}
+	return &pollResolver{poll: &poll.Poll, options: options}
+}
+
+// newResultsPollResolver resolves the poll of a results view, whose options carry percentages
+func newResultsPollResolver(results *models.PollResults) *pollResolver {
+	poll := results.Poll
+	poll.TotalVotes = results.TotalVotes
+	options := make([]optionResolver, len(results.Options))
+	for i := range results.Options {
+		options[i] = optionResolver{option: &results.Options[i].PollOption, percentage: results.Options[i].Percentage}
+	}
+	return &pollResolver{poll: &poll, options: options}
+}
+
+func (p *pollResolver) ID() graphql.ID            { return graphql.ID(p.poll.ID.String()) }
+func (p *pollResolver) Question() string          { return p.poll.Question }
+func (p *pollResolver) Description() *string      { return p.poll.Description }
+func (p *pollResolver) CreatedAt() graphql.Time   { return graphql.Time{Time: p.poll.CreatedAt} }
+func (p *pollResolver) ExpiresAt() *graphql.Time  { return optionalTime(p.poll.ExpiresAt) }
+func (p *pollResolver) IsActive() bool            { return p.poll.IsActive }
+func (p *pollResolver) TotalVotes() int32         { return int32(p.poll.TotalVotes) }
+func (p *pollResolver) AllowWeighted() bool       { return p.poll.AllowWeighted }
+func (p *pollResolver) RequireConfirmation() bool { return p.poll.RequireConfirmation }
+func (p *pollResolver) QuizMode() bool            { return p.poll.QuizMode }
+func (p *pollResolver) Group() *string            { return p.poll.Group }
+func (p *pollResolver) RandomizeOptions() bool    { return p.poll.RandomizeOptions }
+func (p *pollResolver) AllowlistOnly() bool       { return p.poll.AllowlistOnly }
+func (p *pollResolver) AllowWriteIn() bool        { return p.poll.AllowWriteIn }
+func (p *pollResolver) AcknowledgementMode() bool { return p.poll.AcknowledgementMode }
+func (p *pollResolver) Featured() bool            { return p.poll.Featured }
+func (p *pollResolver) Options() []optionResolver { return p.options }
+func (p *pollResolver) MaxVotes() *int32 {
+	if p.poll.MaxVotes == nil {
+		return nil
+	}
+	maxVotes := int32(*p.poll.MaxVotes)
+	return &maxVotes
+}
+
+// optionResolver resolves an Option
+type optionResolver struct {
+	option     *models.PollOption
+	percentage float64
+}
+
+func (o optionResolver) ID() graphql.ID      { return graphql.ID(o.option.ID.String()) }
+func (o optionResolver) Text() string        { return o.option.OptionText }
+func (o optionResolver) VoteCount() int32    { return int32(o.option.VoteCount) }
+func (o optionResolver) Position() int32     { return int32(o.option.Position) }
+func (o optionResolver) Percentage() float64 { return o.percentage }
+
+// pollListResolver resolves a PollList
+type pollListResolver struct {
+	list *models.PollList
+}
+
+func (l *pollListResolver) Total() int32  { return int32(l.list.Total) }
+func (l *pollListResolver) Limit() int32  { return int32(l.list.Limit) }
+func (l *pollListResolver) Offset() int32 { return int32(l.list.Offset) }
+func (l *pollListResolver) Polls() []*pollResolver {
+	polls := make([]*pollResolver, len(l.list.Polls))
+	for i := range l.list.Polls {
+		polls[i] = newPollResolver(&l.list.Polls[i])
+	}
+	return polls
+}
+
+// pollResultsResolver resolves PollResults
+type pollResultsResolver struct {
+	results *models.PollResults
+}
+
+func (r *pollResultsResolver) Poll() *pollResolver        { return newResultsPollResolver(r.results) }
+func (r *pollResultsResolver) HasVoted() bool             { return r.results.HasVoted }
+func (r *pollResultsResolver) VotedOption() *graphql.ID   { return optionalID(r.results.VotedOption) }
+func (r *pollResultsResolver) WinningOption() *graphql.ID { return optionalID(r.results.WinningOption) }
+func (r *pollResultsResolver) Leading() []graphql.ID {
+	leading := make([]graphql.ID, len(r.results.Leading))
+	for i, id := range r.results.Leading {
+		leading[i] = graphql.ID(id.String())
+	}
+	return leading
+}
+func (r *pollResultsResolver) Receipt() *string {
+	if r.results.Receipt == nil {
+		return nil
+	}
+	return &r.results.Receipt.Receipt
+}
+
+// voteConfirmationResolver resolves a VoteConfirmation
+type voteConfirmationResolver struct {
+	confirmation *models.VoteConfirmation
+}
+
+func (c *voteConfirmationResolver) Token() string { return c.confirmation.Token }
+func (c *voteConfirmationResolver) ExpiresAt() graphql.Time {
+	return graphql.Time{Time: c.confirmation.ExpiresAt}
+}
+
+// votePayloadResolver resolves a VotePayload; exactly one of its fields is set
+type votePayloadResolver struct {
+	results      *pollResultsResolver
+	confirmation *voteConfirmationResolver
+}
+
+func (v *votePayloadResolver) Results() *pollResultsResolver           { return v.results }
+func (v *votePayloadResolver) Confirmation() *voteConfirmationResolver { return v.confirmation }
+
+// createPollPayloadResolver resolves a CreatePollPayload
+type createPollPayloadResolver struct {
+	poll     *models.PollWithOptions
+	warnings []string
+}
+
+func (c *createPollPayloadResolver) Poll() *pollResolver { return newPollResolver(c.poll) }
+func (c *createPollPayloadResolver) Warnings() []string {
+	if c.warnings == nil {
+		return []string{}
+	}
+	return c.warnings
+}
+
+// createPollInput mirrors CreatePollInput
+type createPollInput struct {
+	Question            string
+	Description         *string
+	ExpiresAt           *graphql.Time
+	Options             []string
+	AllowWeighted       *bool
+	RequireConfirmation *bool
+	QuizMode            *bool
+	CorrectOptions      *[]int32
+	Group               *string
+	RandomizeOptions    *bool
+	AllowlistOnly       *bool
+	AllowWriteIn        *bool
+	MaxVotes            *int32
+}
+
+// request converts the input to the model the service validates
+func (in *createPollInput) request() *models.CreatePollRequest {
+	req := &models.CreatePollRequest{
+		Question:            in.Question,
+		Description:         in.Description,
+		Options:             in.Options,
+		AllowWeighted:       flag(in.AllowWeighted),
+		RequireConfirmation: flag(in.RequireConfirmation),
+		QuizMode:            flag(in.QuizMode),
+		Group:               in.Group,
+		RandomizeOptions:    flag(in.RandomizeOptions),
+		AllowlistOnly:       flag(in.AllowlistOnly),
+		AllowWriteIn:        flag(in.AllowWriteIn),
+	}
+	if in.ExpiresAt != nil {
+		expiresAt := in.ExpiresAt.Time
+		req.ExpiresAt = &expiresAt
+	}
+	if in.CorrectOptions != nil {
+		for _, index := range *in.CorrectOptions {
+			req.CorrectOptions = append(req.CorrectOptions, int(index))
+		}
+	}
+	if in.MaxVotes != nil {
+		maxVotes := int64(*in.MaxVotes)
+		req.MaxVotes = &maxVotes
+	}
+	return req
+}
+
+// flag dereferences an optional boolean argument, which defaults to false
+func flag(b *bool) bool {
+	return b != nil && *b
+}
+
+// optionalTime converts an optional model time
+func optionalTime(t *time.Time) *graphql.Time {
+	if t == nil {
+		return nil
+	}
+	return &graphql.Time{Time: *t}
+}
+
+// optionalID converts an optional model ID
+func optionalID(id *uuid.UUID) *graphql.ID {
+	if id == nil {
+		return nil
+	}
+	gid := graphql.ID(id.String())
+	return &gid
+}
@@ -0,0 +1,136 @@
+package grpcapi
+
+import (
+	"time"
+
+	"github.com/moabdelazem/k8s-app/internal/grpcapi/pollv1"
+	"github.com/moabdelazem/k8s-app/internal/models"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// createPollRequest converts a CreatePoll request to the model the service validates
+func createPollRequest(req *pollv1.CreatePollRequest) *models.CreatePollRequest {
+	out := &models.CreatePollRequest{
+		Question:            req.GetQuestion(),
+		Description:         req.Description,
+		Options:             req.GetOptions(),
+		AllowWeighted:       req.GetAllowWeighted(),
+		RequireConfirmation: req.GetRequireConfirmation(),
+		QuizMode:            req.GetQuizMode(),
+		Group:               req.Group,
+		RandomizeOptions:    req.GetRandomizeOptions(),
+		AllowlistOnly:       req.GetAllowlistOnly(),
+		AllowWriteIn:        req.GetAllowWriteIn(),
+		MaxVotes:            req.MaxVotes,
+	}
+	if req.ExpiresAt != nil {
+		expiresAt := req.GetExpiresAt().AsTime()
+		out.ExpiresAt = &expiresAt
+	}
+	for _, index := range req.GetCorrectOptions() {
+		out.CorrectOptions = append(out.CorrectOptions, int(index))
+	}
+	return out
+}
+
+// pollMessage converts a poll with its options
+func pollMessage(poll *models.PollWithOptions) *pollv1.Poll {
+	msg := pollFields(&poll.Poll)
+	msg.Options = make([]*pollv1.Option, len(poll.Options))
+	for i, option := range poll.Options {
+		msg.Options[i] = optionMessage(&option, 0)
+	}
+	return msg
+}
+
+// pollResultsMessage converts a poll's results as seen by one voter
+func pollResultsMessage(results *models.PollResults) *pollv1.PollResults {
+	poll := pollFields(&results.Poll)
+	poll.TotalVotes = results.TotalVotes
+	poll.Options = make([]*pollv1.Option, len(results.Options))
+	for i, option := range results.Options {
+		poll.Options[i] = optionMessage(&option.PollOption, option.Percentage)
+	}
+
+	msg := &pollv1.PollResults{
+		Poll:     poll,
+		HasVoted: results.HasVoted,
+		Leading:  make([]string, len(results.Leading)),
+		Receipt:  receiptString(results.Receipt),
+	}
+	if results.VotedOption != nil {
+		votedOption := results.VotedOption.String()
+		msg.VotedOption = &votedOption
+	}
+	for i, id := range results.Leading {
+		msg.Leading[i] = id.String()
+	}
+	if writeIns := results.WriteIns; writeIns != nil {
+		msg.WriteIns = &pollv1.WriteInResult{
+			VoteCount:  writeIns.VoteCount,
+			Percentage: writeIns.Percentage,
+			Entries:    make([]*pollv1.WriteInTally, len(writeIns.Entries)),
+			Truncated:  writeIns.Truncated,
+		}
+		for i, entry := range writeIns.Entries {
+			msg.WriteIns.Entries[i] = &pollv1.WriteInTally{Text: entry.Text, VoteCount: entry.VoteCount}
+		}
+	}
+	return msg
+}
+
+// pollFields converts the fields shared by every poll message, leaving options empty
+func pollFields(poll *models.Poll) *pollv1.Poll {
+	return &pollv1.Poll{
+		Id:                  poll.ID.String(),
+		Question:            poll.Question,
+		Description:         poll.Description,
+		CreatedAt:           timestamppb.New(poll.CreatedAt),
+		ExpiresAt:           timestamp(poll.ExpiresAt),
+		IsActive:            poll.IsActive,
+		TotalVotes:          poll.TotalVotes,
+		AllowWeighted:       poll.AllowWeighted,
+		RequireConfirmation: poll.RequireConfirmation,
+		QuizMode:            poll.QuizMode,
+		Group:               poll.Group,
+		RandomizeOptions:    poll.RandomizeOptions,
+		AllowlistOnly:       poll.AllowlistOnly,
+		AllowWriteIn:        poll.AllowWriteIn,
+		MaxVotes:            poll.MaxVotes,
+	}
+}
+
+// optionMessage converts an option; percentage is zero outside results
+func optionMessage(option *models.PollOption, percentage float64) *pollv1.Option {
+	return &pollv1.Option{
+		Id:         option.ID.String(),
+		Text:       option.OptionText,
+		VoteCount:  option.VoteCount,
+		Position:   int32(option.Position),
+		Percentage: percentage,
+	}
+}
+
+// voteConfirmationMessage converts the token of a vote pending confirmation
+func voteConfirmationMessage(confirmation *models.VoteConfirmation) *pollv1.VoteConfirmation {
+	return &pollv1.VoteConfirmation{
+		ConfirmationToken: confirmation.Token,
+		ExpiresAt:         timestamppb.New(confirmation.ExpiresAt),
+	}
+}
+
+// receiptString returns the signed receipt, or "" when receipts are disabled
+func receiptString(receipt *models.VoteReceipt) string {
+	if receipt == nil {
+		return ""
+	}
+	return receipt.Receipt
+}
+
+// timestamp converts an optional time, keeping nil as unset
+func timestamp(t *time.Time) *timestamppb.Timestamp {
+	if t == nil {
+		return nil
+	}
+	return timestamppb.New(*t)
+}
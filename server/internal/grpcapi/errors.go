@@ -0,0 +1,65 @@
+package grpcapi
+
+import (
+	"errors"
+
+	"github.com/moabdelazem/k8s-app/internal/service"
+	"github.com/moabdelazem/k8s-app/pkg/logger"
+	"go.uber.org/zap"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// statusError maps a service error to a gRPC status, mirroring the REST error renderer.
+// Domain and validation errors keep their message and carry their error code as the reason of an
+// ErrorInfo detail; anything unexpected is logged and returned as Internal with fallback as the message.
+func statusError(method string, err error, fallback string) error {
+	var validationErr *service.ValidationError
+
+	switch {
+	case errors.Is(err, service.ErrPollNotFound):
+		return codedStatus(codes.NotFound, service.CodePollNotFound, err.Error())
+	case errors.Is(err, service.ErrPollDeleted):
+		// gRPC has no Gone; the reason tells a deleted poll from one that never existed
+		return codedStatus(codes.NotFound, service.CodePollDeleted, err.Error())
+	case errors.Is(err, service.ErrPollDeleteProtected):
+		return codedStatus(codes.PermissionDenied, service.CodePollDeleteProtected, err.Error())
+	case errors.Is(err, service.ErrActivePollLimitReached):
+		return codedStatus(codes.ResourceExhausted, service.CodeActivePollLimitReached, err.Error())
+	case errors.Is(err, service.ErrVoterNetworkBlocked):
+		return codedStatus(codes.PermissionDenied, service.CodeVoterNetworkBlocked, err.Error())
+	case errors.Is(err, service.ErrVoterNotAllowed):
+		return codedStatus(codes.PermissionDenied, service.CodeVoterNotAllowed, err.Error())
+	case errors.As(err, &validationErr):
+		return codedStatus(validationCode(validationErr.Code), validationErr.Code, validationErr.Message)
+	case errors.Is(err, service.ErrTemporarilyUnavailable):
+		logger.Warn("Transient database error", zap.Error(err), zap.String("grpc_method", method))
+		return codedStatus(codes.Unavailable, service.CodeTemporarilyUnavailable, "Service temporarily unavailable, please retry")
+	default:
+		logger.Error(fallback, zap.Error(err), zap.String("grpc_method", method))
+		return status.Error(codes.Internal, fallback)
+	}
+}
+
+// validationCode picks the status of a validation error: rules about the poll's current state
+// are FailedPrecondition, everything else is a bad argument
+func validationCode(code string) codes.Code {
+	switch code {
+	case service.CodeAlreadyVoted, service.CodeAlreadyVotedInGroup, service.CodePollInactive, service.CodePollExpired, service.CodePollFull, service.CodeOptionsLocked:
+		return codes.FailedPrecondition
+	}
+	return codes.InvalidArgument
+}
+
+// codedStatus builds a status carrying code as an ErrorInfo reason; uncoded errors get no detail
+func codedStatus(c codes.Code, code, message string) error {
+	st := status.New(c, message)
+	if code == "" {
+		return st.Err()
+	}
+	if detailed, err := st.WithDetails(&errdetails.ErrorInfo{Reason: code}); err == nil {
+		st = detailed
+	}
+	return st.Err()
+}
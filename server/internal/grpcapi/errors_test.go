@@ -0,0 +1,49 @@
+package grpcapi
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/moabdelazem/k8s-app/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestStatusError(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantCode   codes.Code
+		wantReason string // Empty when the status carries no ErrorInfo
+	}{
+		{name: "not found", err: service.ErrPollNotFound, wantCode: codes.NotFound, wantReason: service.CodePollNotFound},
+		{name: "deleted", err: service.ErrPollDeleted, wantCode: codes.NotFound, wantReason: service.CodePollDeleted},
+		{name: "delete protected", err: fmt.Errorf("delete: %w", service.ErrPollDeleteProtected), wantCode: codes.PermissionDenied, wantReason: service.CodePollDeleteProtected},
+		{name: "active poll limit", err: service.ErrActivePollLimitReached, wantCode: codes.ResourceExhausted, wantReason: service.CodeActivePollLimitReached},
+		{name: "voter not allowed", err: service.ErrVoterNotAllowed, wantCode: codes.PermissionDenied, wantReason: service.CodeVoterNotAllowed},
+		{name: "poll state", err: &service.ValidationError{Code: service.CodeAlreadyVoted, Message: "already voted"}, wantCode: codes.FailedPrecondition, wantReason: service.CodeAlreadyVoted},
+		{name: "bad argument", err: &service.ValidationError{Code: service.CodeQuestionLength, Message: "too short"}, wantCode: codes.InvalidArgument, wantReason: service.CodeQuestionLength},
+		{name: "transient", err: service.ErrTemporarilyUnavailable, wantCode: codes.Unavailable, wantReason: service.CodeTemporarilyUnavailable},
+		{name: "unexpected", err: errors.New("boom"), wantCode: codes.Internal},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			st := status.Convert(statusError("Test", tt.err, "Failed"))
+
+			assert.Equal(t, tt.wantCode, st.Code())
+			if tt.wantReason == "" {
+				assert.Empty(t, st.Details())
+				return
+			}
+			require.Len(t, st.Details(), 1)
+			info, ok := st.Details()[0].(*errdetails.ErrorInfo)
+			require.True(t, ok)
+			assert.Equal(t, tt.wantReason, info.GetReason())
+		})
+	}
+}
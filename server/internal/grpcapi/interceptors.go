@@ -0,0 +1,130 @@
+package grpcapi
+
+import (
+	"context"
+	"net"
+	"net/netip"
+	"strings"
+	"time"
+
+	"github.com/moabdelazem/k8s-app/internal/grpcapi/pollv1"
+	"github.com/moabdelazem/k8s-app/internal/maintenance"
+	"github.com/moabdelazem/k8s-app/internal/netblock"
+	"github.com/moabdelazem/k8s-app/internal/service"
+	"github.com/moabdelazem/k8s-app/pkg/auth"
+	"github.com/moabdelazem/k8s-app/pkg/logger"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// Options are the access controls the REST routes enforce, applied to RPCs by interceptors
+type Options struct {
+	JWTSecret            string            // Verifies "authorization: Bearer <token>" metadata; empty = tokens are rejected
+	RequireAuthForCreate bool              // CreatePoll and DeletePoll need a valid token, as on the REST routes
+	Maintenance          *maintenance.Mode // Writes fail with Unavailable while read-only; nil = never read-only
+	BlockedNetworks      *netblock.List    // Votes from these ranges are rejected; nil = accept all
+}
+
+// writeMethods are the RPCs rejected in read-only mode
+var writeMethods = map[string]bool{
+	pollv1.PollService_CreatePoll_FullMethodName: true,
+	pollv1.PollService_CastVote_FullMethodName:   true,
+	pollv1.PollService_DeletePoll_FullMethodName: true,
+}
+
+// authMethods are the RPCs that need a token when RequireAuthForCreate is set
+var authMethods = map[string]bool{
+	pollv1.PollService_CreatePoll_FullMethodName: true,
+	pollv1.PollService_DeletePoll_FullMethodName: true,
+}
+
+// interceptors returns the unary interceptors enforcing opts, in the order they run
+func interceptors(opts Options) []grpc.UnaryServerInterceptor {
+	return []grpc.UnaryServerInterceptor{
+		authInterceptor(opts.JWTSecret, opts.RequireAuthForCreate),
+		readOnlyInterceptor(opts.Maintenance),
+		voterNetworkInterceptor(opts.BlockedNetworks),
+	}
+}
+
+// authInterceptor verifies the bearer token of calls carrying one and stores its claims in the context.
+// Calls without a token stay anonymous, except for poll writes when requireForWrites is set.
+func authInterceptor(secret string, requireForWrites bool) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		token, ok := bearerToken(ctx)
+		if !ok {
+			if requireForWrites && authMethods[info.FullMethod] {
+				return nil, status.Error(codes.Unauthenticated, "Authentication required")
+			}
+			return handler(ctx, req)
+		}
+
+		if secret == "" {
+			return nil, status.Error(codes.Unauthenticated, "Invalid or expired token")
+		}
+		claims, err := auth.ParseToken(secret, token, time.Now())
+		if err != nil {
+			logger.Warn("Rejected unauthenticated RPC",
+				zap.Error(err),
+				zap.String("grpc_method", info.FullMethod),
+				zap.String("peer_addr", peerAddr(ctx)),
+			)
+			return nil, status.Error(codes.Unauthenticated, "Invalid or expired token")
+		}
+		return handler(auth.WithClaims(ctx, claims), req)
+	}
+}
+
+// readOnlyInterceptor rejects writes with Unavailable while read-only mode is on
+func readOnlyInterceptor(mode *maintenance.Mode) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if mode != nil && mode.ReadOnly() && writeMethods[info.FullMethod] {
+			return nil, status.Error(codes.Unavailable, "Service is in read-only mode; writes are temporarily disabled")
+		}
+		return handler(ctx, req)
+	}
+}
+
+// voterNetworkInterceptor rejects votes whose peer address falls in a blocked range
+// Addresses that cannot be parsed are rejected too, as they cannot be shown to be outside one
+func voterNetworkInterceptor(blocked *netblock.List) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if blocked == nil || info.FullMethod != pollv1.PollService_CastVote_FullMethodName {
+			return handler(ctx, req)
+		}
+		addr, err := netip.ParseAddr(peerAddr(ctx))
+		if err != nil || blocked.Contains(addr) {
+			logger.Warn("Rejected vote from blocked network", zap.String("peer_addr", peerAddr(ctx)))
+			return nil, statusError("CastVote", service.ErrVoterNetworkBlocked, "")
+		}
+		return handler(ctx, req)
+	}
+}
+
+// bearerToken returns the token of an "authorization: Bearer <token>" metadata entry
+func bearerToken(ctx context.Context) (string, bool) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	for _, value := range md.Get("authorization") {
+		if token, ok := strings.CutPrefix(value, "Bearer "); ok && token != "" {
+			return token, true
+		}
+	}
+	return "", false
+}
+
+// peerAddr returns the caller's network address without the port, or "" when unknown
+func peerAddr(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		return p.Addr.String()
+	}
+	return host
+}
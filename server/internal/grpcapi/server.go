@@ -0,0 +1,184 @@
+// Package grpcapi serves the poll API over gRPC for internal callers.
+// Every method delegates to service.PollService, so both transports apply the same rules.
+package grpcapi
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/moabdelazem/k8s-app/internal/grpcapi/pollv1"
+	"github.com/moabdelazem/k8s-app/internal/models"
+	"github.com/moabdelazem/k8s-app/internal/service"
+	"github.com/moabdelazem/k8s-app/pkg/auth"
+	"github.com/moabdelazem/k8s-app/pkg/logger"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// defaultListLimit is the page size used when ListPolls is called without a limit, as on the REST route
+const defaultListLimit = 20
+
+// Server implements pollv1.PollServiceServer on top of the poll service
+type Server struct {
+	pollv1.UnimplementedPollServiceServer
+	service *service.PollService
+}
+
+// NewServer creates a Server delegating to svc
+func NewServer(svc *service.PollService) *Server {
+	return &Server{service: svc}
+}
+
+// NewGRPCServer returns a gRPC server with the poll service registered behind interceptors enforcing opts
+func NewGRPCServer(svc *service.PollService, opts Options, serverOpts ...grpc.ServerOption) *grpc.Server {
+	s := grpc.NewServer(append(serverOpts, grpc.ChainUnaryInterceptor(interceptors(opts)...))...)
+	pollv1.RegisterPollServiceServer(s, NewServer(svc))
+	return s
+}
+
+// CreatePoll creates a new poll
+func (s *Server) CreatePoll(ctx context.Context, req *pollv1.CreatePollRequest) (*pollv1.CreatePollResponse, error) {
+	ownerID, err := callerIdentity(ctx, "owner_id", req.GetOwnerId())
+	if err != nil {
+		return nil, err
+	}
+
+	poll, warnings, err := s.service.CreatePoll(ctx, createPollRequest(req), ownerID)
+	if err != nil {
+		return nil, statusError("CreatePoll", err, "Failed to create poll")
+	}
+	return &pollv1.CreatePollResponse{Poll: pollMessage(poll), Warnings: warnings}, nil
+}
+
+// GetPoll retrieves a poll with its results
+func (s *Server) GetPoll(ctx context.Context, req *pollv1.GetPollRequest) (*pollv1.PollResults, error) {
+	pollID, err := parseID("id", req.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	voterIdentifier, err := callerIdentity(ctx, "voter_id", req.GetVoterId())
+	if err != nil {
+		return nil, err
+	}
+
+	results, err := s.service.GetPollResults(ctx, pollID, voterIdentifier)
+	if err != nil {
+		return nil, statusError("GetPoll", err, "Failed to retrieve poll")
+	}
+	return pollResultsMessage(results), nil
+}
+
+// ListPolls retrieves one page of polls
+func (s *Server) ListPolls(ctx context.Context, req *pollv1.ListPollsRequest) (*pollv1.ListPollsResponse, error) {
+	limit := int(req.GetLimit())
+	if limit == 0 {
+		limit = defaultListLimit
+	}
+
+	page, err := s.service.ListPolls(ctx, limit, int(req.GetOffset()), req.GetActiveOnly())
+	if err != nil {
+		return nil, statusError("ListPolls", err, "Failed to retrieve polls")
+	}
+
+	resp := &pollv1.ListPollsResponse{
+		Polls:  make([]*pollv1.Poll, len(page.Polls)),
+		Total:  page.Total,
+		Limit:  int32(page.Limit),
+		Offset: int32(page.Offset),
+	}
+	for i := range page.Polls {
+		resp.Polls[i] = pollMessage(&page.Polls[i])
+	}
+	return resp, nil
+}
+
+// CastVote records a vote for an option or a write-in answer
+func (s *Server) CastVote(ctx context.Context, req *pollv1.CastVoteRequest) (*pollv1.CastVoteResponse, error) {
+	pollID, err := parseID("poll_id", req.GetPollId())
+	if err != nil {
+		return nil, err
+	}
+	voterIdentifier, err := callerIdentity(ctx, "voter_id", req.GetVoterId())
+	if err != nil {
+		return nil, err
+	}
+
+	vote := models.VoteRequest{WriteIn: req.GetWriteIn(), Weight: req.GetWeight(), ShareToken: req.GetShareToken()}
+	if req.GetOptionId() != "" {
+		if vote.OptionID, err = parseID("option_id", req.GetOptionId()); err != nil {
+			return nil, err
+		}
+	}
+	if err := service.ValidateRequest(&vote); err != nil {
+		return nil, statusError("CastVote", err, "Failed to cast vote")
+	}
+
+	var confirmation *models.VoteConfirmation
+	var receipt *models.VoteReceipt
+	if vote.WriteIn != "" {
+		confirmation, receipt, err = s.service.CastWriteInVote(ctx, pollID, vote.WriteIn, voterIdentifier, vote.Weight, vote.ShareToken)
+	} else {
+		confirmation, receipt, err = s.service.CastVote(ctx, pollID, vote.OptionID, voterIdentifier, vote.Weight, vote.ShareToken)
+	}
+	if err != nil {
+		return nil, statusError("CastVote", err, "Failed to cast vote")
+	}
+
+	// The poll requires confirmation; the vote does not count until confirmed
+	if confirmation != nil {
+		return &pollv1.CastVoteResponse{Confirmation: voteConfirmationMessage(confirmation)}, nil
+	}
+
+	results, err := s.service.GetPollResults(ctx, pollID, voterIdentifier)
+	if err != nil {
+		logger.Warn("Failed to get updated results after vote", zap.Error(err))
+		// The vote is recorded either way, so the receipt is still handed out
+		return &pollv1.CastVoteResponse{Results: &pollv1.PollResults{Receipt: receiptString(receipt)}}, nil
+	}
+	results.Receipt = receipt
+	return &pollv1.CastVoteResponse{Results: pollResultsMessage(results)}, nil
+}
+
+// DeletePoll soft deletes a poll
+func (s *Server) DeletePoll(ctx context.Context, req *pollv1.DeletePollRequest) (*pollv1.DeletePollResponse, error) {
+	pollID, err := parseID("id", req.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.service.DeletePoll(ctx, pollID); err != nil {
+		return nil, statusError("DeletePoll", err, "Failed to delete poll")
+	}
+	return &pollv1.DeletePollResponse{}, nil
+}
+
+// parseID parses a UUID request field, rejecting invalid ones as InvalidArgument
+func parseID(field, value string) (uuid.UUID, error) {
+	id, err := uuid.Parse(value)
+	if err != nil {
+		return uuid.Nil, status.Errorf(codes.InvalidArgument, "Invalid %s", field)
+	}
+	return id, nil
+}
+
+// callerIdentity returns the identity a call acts as, matching the REST voter identifiers:
+// the token subject for authenticated callers and the network address for anonymous ones.
+// Only service tokens, held by internal services acting for their users, may name another
+// identity in field; anyone else doing so is rejected, as they could vote repeatedly or act as another owner.
+func callerIdentity(ctx context.Context, field, given string) (string, error) {
+	claims := auth.ClaimsFromContext(ctx)
+	switch {
+	case claims.IsService() && given != "":
+		return given, nil
+	case claims != nil && given != "":
+		return "", status.Errorf(codes.PermissionDenied, "%s requires a service token", field)
+	case claims != nil:
+		return "user:" + claims.Subject, nil
+	case given != "":
+		return "", status.Errorf(codes.Unauthenticated, "%s requires authentication", field)
+	}
+	return peerAddr(ctx), nil
+}
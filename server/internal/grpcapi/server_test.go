@@ -0,0 +1,281 @@
+package grpcapi
+
+import (
+	"context"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/moabdelazem/k8s-app/internal/grpcapi/pollv1"
+	"github.com/moabdelazem/k8s-app/internal/maintenance"
+	"github.com/moabdelazem/k8s-app/internal/models"
+	"github.com/moabdelazem/k8s-app/internal/netblock"
+	"github.com/moabdelazem/k8s-app/internal/repository"
+	"github.com/moabdelazem/k8s-app/internal/service"
+	"github.com/moabdelazem/k8s-app/pkg/auth"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// testSecret signs the tokens of authenticated test calls
+const testSecret = "test-secret"
+
+// newTestClient serves svc over an in-memory connection and returns a client for it
+func newTestClient(t *testing.T, svc *service.PollService, opts Options) pollv1.PollServiceClient {
+	t.Helper()
+	lis := bufconn.Listen(1 << 20)
+	srv := NewGRPCServer(svc, opts)
+	go srv.Serve(lis)
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return pollv1.NewPollServiceClient(conn)
+}
+
+// authenticated returns ctx carrying a valid bearer token for subject
+func authenticated(t *testing.T, ctx context.Context, subject string) context.Context {
+	t.Helper()
+	return withToken(t, ctx, auth.Claims{Subject: subject, ExpiresAt: time.Now().Add(time.Hour).Unix()})
+}
+
+// asService returns ctx carrying a valid service token, which may act for other voters
+func asService(t *testing.T, ctx context.Context) context.Context {
+	t.Helper()
+	return withToken(t, ctx, auth.Claims{Subject: "service", ExpiresAt: time.Now().Add(time.Hour).Unix(), Role: auth.RoleService})
+}
+
+// withToken returns ctx carrying claims as a bearer token
+func withToken(t *testing.T, ctx context.Context, claims auth.Claims) context.Context {
+	t.Helper()
+	token, err := auth.SignToken(testSecret, claims)
+	require.NoError(t, err)
+	return metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+token)
+}
+
+func TestGetPoll(t *testing.T) {
+	ctx := context.Background()
+	svc := service.NewPollService(repository.NewInMemoryPollRepository(), service.PollServiceConfig{})
+	client := newTestClient(t, svc, Options{JWTSecret: testSecret})
+
+	poll, _, err := svc.CreatePoll(ctx, &models.CreatePollRequest{
+		Question: "Which feature should we build next?",
+		Options:  []string{"Feature A", "Feature B"},
+	}, "owner-1")
+	require.NoError(t, err)
+	_, _, err = svc.CastVote(ctx, poll.ID, poll.Options[1].ID, "voter-1", 0, "")
+	require.NoError(t, err)
+
+	results, err := client.GetPoll(asService(t, ctx), &pollv1.GetPollRequest{Id: poll.ID.String(), VoterId: "voter-1"})
+	require.NoError(t, err)
+	assert.Equal(t, poll.ID.String(), results.GetPoll().GetId())
+	assert.Equal(t, "Which feature should we build next?", results.GetPoll().GetQuestion())
+	assert.Equal(t, int64(1), results.GetPoll().GetTotalVotes())
+	require.Len(t, results.GetPoll().GetOptions(), 2)
+	assert.Equal(t, "Feature B", results.GetPoll().GetOptions()[1].GetText())
+	assert.Equal(t, 100.0, results.GetPoll().GetOptions()[1].GetPercentage())
+	assert.True(t, results.GetHasVoted())
+	assert.Equal(t, poll.Options[1].ID.String(), results.GetVotedOption())
+	assert.Equal(t, []string{poll.Options[1].ID.String()}, results.GetLeading())
+
+	// Without a voter ID the caller's address is used, which has not voted
+	results, err = client.GetPoll(ctx, &pollv1.GetPollRequest{Id: poll.ID.String()})
+	require.NoError(t, err)
+	assert.False(t, results.GetHasVoted())
+}
+
+func TestGetPoll_Errors(t *testing.T) {
+	svc := service.NewPollService(repository.NewInMemoryPollRepository(), service.PollServiceConfig{})
+	client := newTestClient(t, svc, Options{JWTSecret: testSecret})
+
+	_, err := client.GetPoll(context.Background(), &pollv1.GetPollRequest{Id: "not-a-uuid"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = client.GetPoll(context.Background(), &pollv1.GetPollRequest{Id: uuid.NewString()})
+	st := status.Convert(err)
+	assert.Equal(t, codes.NotFound, st.Code())
+	require.Len(t, st.Details(), 1)
+	info, ok := st.Details()[0].(*errdetails.ErrorInfo)
+	require.True(t, ok)
+	assert.Equal(t, service.CodePollNotFound, info.GetReason(), "the REST error code is carried along")
+}
+
+func TestCastVote_AlreadyVotedIsFailedPrecondition(t *testing.T) {
+	ctx := asService(t, context.Background())
+	svc := service.NewPollService(repository.NewInMemoryPollRepository(), service.PollServiceConfig{})
+	client := newTestClient(t, svc, Options{JWTSecret: testSecret})
+
+	created, err := client.CreatePoll(ctx, &pollv1.CreatePollRequest{
+		Question: "Which feature should we build next?",
+		Options:  []string{"Feature A", "Feature B"},
+	})
+	require.NoError(t, err)
+	vote := &pollv1.CastVoteRequest{PollId: created.GetPoll().GetId(), OptionId: created.GetPoll().GetOptions()[0].GetId(), VoterId: "voter-1"}
+
+	resp, err := client.CastVote(ctx, vote)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), resp.GetResults().GetPoll().GetTotalVotes())
+
+	_, err = client.CastVote(ctx, vote)
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+}
+
+func TestCastVote_AnonymousCallers(t *testing.T) {
+	ctx := context.Background()
+	svc := service.NewPollService(repository.NewInMemoryPollRepository(), service.PollServiceConfig{})
+	client := newTestClient(t, svc, Options{JWTSecret: testSecret})
+
+	created, err := client.CreatePoll(ctx, &pollv1.CreatePollRequest{
+		Question: "Which feature should we build next?",
+		Options:  []string{"Feature A", "Feature B"},
+	})
+	require.NoError(t, err)
+	pollID, optionID := created.GetPoll().GetId(), created.GetPoll().GetOptions()[0].GetId()
+
+	// Anonymous callers cannot pick an identity, so they cannot vote once per made-up voter ID
+	_, err = client.CastVote(ctx, &pollv1.CastVoteRequest{PollId: pollID, OptionId: optionID, VoterId: "voter-1"})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	_, err = client.GetPoll(ctx, &pollv1.GetPollRequest{Id: pollID, VoterId: "voter-1"})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	// They are deduplicated by network address instead
+	_, err = client.CastVote(ctx, &pollv1.CastVoteRequest{PollId: pollID, OptionId: optionID})
+	require.NoError(t, err)
+	_, err = client.CastVote(ctx, &pollv1.CastVoteRequest{PollId: pollID, OptionId: optionID})
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+}
+
+func TestCastVote_UserTokensCannotActForOthers(t *testing.T) {
+	ctx := context.Background()
+	svc := service.NewPollService(repository.NewInMemoryPollRepository(), service.PollServiceConfig{})
+	client := newTestClient(t, svc, Options{JWTSecret: testSecret})
+
+	created, err := client.CreatePoll(ctx, &pollv1.CreatePollRequest{
+		Question: "Which feature should we build next?",
+		Options:  []string{"Feature A", "Feature B"},
+	})
+	require.NoError(t, err)
+	pollID, optionID := created.GetPoll().GetId(), created.GetPoll().GetOptions()[0].GetId()
+	alice := authenticated(t, ctx, "alice")
+
+	// A user token cannot vote, read or create polls as anyone else
+	_, err = client.CastVote(alice, &pollv1.CastVoteRequest{PollId: pollID, OptionId: optionID, VoterId: "voter-1"})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	_, err = client.GetPoll(alice, &pollv1.GetPollRequest{Id: pollID, VoterId: "voter-1"})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	_, err = client.CreatePoll(alice, &pollv1.CreatePollRequest{
+		Question: "Which feature should we build next?",
+		Options:  []string{"Feature A", "Feature B"},
+		OwnerId:  "bob",
+	})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	// It votes as its own subject, once
+	_, err = client.CastVote(alice, &pollv1.CastVoteRequest{PollId: pollID, OptionId: optionID})
+	require.NoError(t, err)
+	_, err = client.CastVote(alice, &pollv1.CastVoteRequest{PollId: pollID, OptionId: optionID})
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+
+	// A service token acts for the voter it names
+	internal := asService(t, ctx)
+	_, err = client.CastVote(internal, &pollv1.CastVoteRequest{PollId: pollID, OptionId: optionID, VoterId: "user:alice"})
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err), "alice has already voted")
+	resp, err := client.CastVote(internal, &pollv1.CastVoteRequest{PollId: pollID, OptionId: optionID, VoterId: "voter-1"})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), resp.GetResults().GetPoll().GetTotalVotes())
+}
+
+func TestAuth_RequiredForWrites(t *testing.T) {
+	ctx := context.Background()
+	svc := service.NewPollService(repository.NewInMemoryPollRepository(), service.PollServiceConfig{})
+	client := newTestClient(t, svc, Options{JWTSecret: testSecret, RequireAuthForCreate: true})
+	create := &pollv1.CreatePollRequest{Question: "Which feature should we build next?", Options: []string{"Feature A", "Feature B"}}
+
+	_, err := client.CreatePoll(ctx, create)
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	badToken := metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer not-a-token")
+	_, err = client.CreatePoll(badToken, create)
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	created, err := client.CreatePoll(authenticated(t, ctx, "alice"), create)
+	require.NoError(t, err)
+
+	// Reads stay public
+	_, err = client.GetPoll(ctx, &pollv1.GetPollRequest{Id: created.GetPoll().GetId()})
+	require.NoError(t, err)
+
+	_, err = client.DeletePoll(ctx, &pollv1.DeletePollRequest{Id: created.GetPoll().GetId()})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	_, err = client.DeletePoll(authenticated(t, ctx, "alice"), &pollv1.DeletePollRequest{Id: created.GetPoll().GetId()})
+	require.NoError(t, err)
+}
+
+func TestReadOnly_RejectsWrites(t *testing.T) {
+	ctx := context.Background()
+	svc := service.NewPollService(repository.NewInMemoryPollRepository(), service.PollServiceConfig{})
+	mode := maintenance.NewMode(false)
+	client := newTestClient(t, svc, Options{Maintenance: mode})
+
+	created, err := client.CreatePoll(ctx, &pollv1.CreatePollRequest{
+		Question: "Which feature should we build next?",
+		Options:  []string{"Feature A", "Feature B"},
+	})
+	require.NoError(t, err)
+	pollID := created.GetPoll().GetId()
+
+	mode.SetReadOnly(true)
+	_, err = client.CreatePoll(ctx, &pollv1.CreatePollRequest{Question: "Another question?", Options: []string{"Yes", "No"}})
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+	_, err = client.CastVote(ctx, &pollv1.CastVoteRequest{PollId: pollID, OptionId: created.GetPoll().GetOptions()[0].GetId()})
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+	_, err = client.DeletePoll(ctx, &pollv1.DeletePollRequest{Id: pollID})
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+
+	_, err = client.GetPoll(ctx, &pollv1.GetPollRequest{Id: pollID})
+	require.NoError(t, err)
+}
+
+func TestVoterNetworkInterceptor(t *testing.T) {
+	blocked, err := netblock.Load(strings.NewReader("203.0.113.0/24\n"))
+	require.NoError(t, err)
+	intercept := voterNetworkInterceptor(blocked)
+	handler := func(ctx context.Context, req any) (any, error) { return "ok", nil }
+
+	tests := []struct {
+		name     string
+		addr     net.Addr
+		method   string
+		wantCode codes.Code
+	}{
+		{name: "vote from blocked range", addr: &net.TCPAddr{IP: net.ParseIP("203.0.113.7"), Port: 4000}, method: pollv1.PollService_CastVote_FullMethodName, wantCode: codes.PermissionDenied},
+		{name: "vote from elsewhere", addr: &net.TCPAddr{IP: net.ParseIP("198.51.100.7"), Port: 4000}, method: pollv1.PollService_CastVote_FullMethodName, wantCode: codes.OK},
+		{name: "vote from unknown address", method: pollv1.PollService_CastVote_FullMethodName, wantCode: codes.PermissionDenied},
+		{name: "read from blocked range", addr: &net.TCPAddr{IP: net.ParseIP("203.0.113.7"), Port: 4000}, method: pollv1.PollService_GetPoll_FullMethodName, wantCode: codes.OK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.addr != nil {
+				ctx = peer.NewContext(ctx, &peer.Peer{Addr: tt.addr})
+			}
+			_, err := intercept(ctx, nil, &grpc.UnaryServerInfo{FullMethod: tt.method}, handler)
+			assert.Equal(t, tt.wantCode, status.Code(err))
+		})
+	}
+}
@@ -0,0 +1,67 @@
+// Package live fans poll change notifications out to in-process subscribers, such as
+// clients streaming live results.
+package live
+
+import (
+	"sync"
+
+	"github.com/google/uuid"
+	"github.com/moabdelazem/k8s-app/internal/models"
+)
+
+// Hub broadcasts poll events to the subscribers of each poll.
+// Notifications are signals, not payloads: a subscriber that falls behind sees a single
+// pending signal however many events arrived, and re-reads the poll's current state.
+// Only events raised in this process are seen, so with several replicas a subscriber
+// misses votes recorded by the others.
+type Hub struct {
+	mu   sync.Mutex
+	subs map[uuid.UUID]map[chan struct{}]struct{}
+}
+
+func NewHub() *Hub {
+	return &Hub{subs: make(map[uuid.UUID]map[chan struct{}]struct{})}
+}
+
+// Subscribe returns a channel signaled whenever pollID changes, and a function that
+// cancels the subscription; cancel must be called once the subscriber is done
+func (h *Hub) Subscribe(pollID uuid.UUID) (<-chan struct{}, func()) {
+	ch := make(chan struct{}, 1)
+
+	h.mu.Lock()
+	if h.subs[pollID] == nil {
+		h.subs[pollID] = make(map[chan struct{}]struct{})
+	}
+	h.subs[pollID][ch] = struct{}{}
+	h.mu.Unlock()
+
+	cancel := func() {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+		delete(h.subs[pollID], ch)
+		if len(h.subs[pollID]) == 0 {
+			delete(h.subs, pollID)
+		}
+	}
+	return ch, cancel
+}
+
+// Notify signals every subscriber of the event's poll without blocking
+func (h *Hub) Notify(event models.PollEvent) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for ch := range h.subs[event.PollID] {
+		select {
+		case ch <- struct{}{}:
+		default: // A signal is already pending
+		}
+	}
+}
+
+// Subscribers returns the number of subscribers of pollID
+func (h *Hub) Subscribers(pollID uuid.UUID) int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return len(h.subs[pollID])
+}
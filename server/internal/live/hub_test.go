@@ -0,0 +1,55 @@
+package live
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/moabdelazem/k8s-app/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHub_NotifiesSubscribersOfThePoll(t *testing.T) {
+	hub := NewHub()
+	pollID := uuid.New()
+
+	first, cancelFirst := hub.Subscribe(pollID)
+	defer cancelFirst()
+	second, cancelSecond := hub.Subscribe(pollID)
+	defer cancelSecond()
+	other, cancelOther := hub.Subscribe(uuid.New())
+	defer cancelOther()
+
+	hub.Notify(models.PollEvent{Type: models.EventVoteCast, PollID: pollID})
+
+	assert.Len(t, first, 1)
+	assert.Len(t, second, 1)
+	assert.Empty(t, other)
+}
+
+func TestHub_CoalescesPendingSignals(t *testing.T) {
+	hub := NewHub()
+	pollID := uuid.New()
+
+	ch, cancel := hub.Subscribe(pollID)
+	defer cancel()
+
+	// A slow subscriber never blocks Notify and sees one pending signal
+	for range 5 {
+		hub.Notify(models.PollEvent{Type: models.EventVoteCast, PollID: pollID})
+	}
+	assert.Len(t, ch, 1)
+}
+
+func TestHub_CancelUnsubscribes(t *testing.T) {
+	hub := NewHub()
+	pollID := uuid.New()
+
+	ch, cancel := hub.Subscribe(pollID)
+	assert.Equal(t, 1, hub.Subscribers(pollID))
+
+	cancel()
+	hub.Notify(models.PollEvent{Type: models.EventVoteCast, PollID: pollID})
+
+	assert.Equal(t, 0, hub.Subscribers(pollID))
+	assert.Empty(t, ch)
+}
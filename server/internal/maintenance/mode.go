@@ -0,0 +1,37 @@
+package maintenance
+
+import (
+	"sync/atomic"
+
+	"github.com/moabdelazem/k8s-app/pkg/logger"
+)
+
+// Mode holds the runtime maintenance state shared by middleware and admin handlers
+type Mode struct {
+	readOnly atomic.Bool
+}
+
+// NewMode creates a Mode with the initial read-only state from configuration
+func NewMode(readOnly bool) *Mode {
+	m := &Mode{}
+	m.readOnly.Store(readOnly)
+	return m
+}
+
+// ReadOnly reports whether writes are currently rejected
+func (m *Mode) ReadOnly() bool {
+	return m.readOnly.Load()
+}
+
+// SetReadOnly toggles read-only mode and logs the change
+func (m *Mode) SetReadOnly(enabled bool) {
+	if previous := m.readOnly.Swap(enabled); previous == enabled {
+		return
+	}
+
+	if enabled {
+		logger.Warn("Read-only mode enabled; write requests will be rejected")
+	} else {
+		logger.Info("Read-only mode disabled; write requests are accepted again")
+	}
+}
@@ -0,0 +1,67 @@
+package metrics
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/moabdelazem/k8s-app/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// DBPoolCollector copies connection pool statistics into gauges at a fixed interval,
+// so pool pressure is visible between /health calls
+type DBPoolCollector struct {
+	stats func() sql.DBStats
+
+	open         *Gauge
+	inUse        *Gauge
+	idle         *Gauge
+	waitCount    *Gauge
+	waitDuration *Gauge
+}
+
+// NewDBPoolCollector registers the pool gauges with reg, reading statistics from stats
+// (e.g. database.Stats)
+func NewDBPoolCollector(reg *Registry, stats func() sql.DBStats) *DBPoolCollector {
+	return &DBPoolCollector{
+		stats:        stats,
+		open:         reg.NewGauge("db_open_connections", "Established connections, in use or idle."),
+		inUse:        reg.NewGauge("db_in_use", "Connections currently in use."),
+		idle:         reg.NewGauge("db_idle", "Idle connections."),
+		waitCount:    reg.NewGauge("db_wait_count", "Total number of connections waited for."),
+		waitDuration: reg.NewGauge("db_wait_duration_seconds", "Total time blocked waiting for a new connection."),
+	}
+}
+
+// Collect updates the gauges from a single read of the pool statistics
+func (c *DBPoolCollector) Collect() {
+	stats := c.stats()
+	c.open.Set(float64(stats.OpenConnections))
+	c.inUse.Set(float64(stats.InUse))
+	c.idle.Set(float64(stats.Idle))
+	c.waitCount.Set(float64(stats.WaitCount))
+	c.waitDuration.Set(stats.WaitDuration.Seconds())
+}
+
+// Start collects immediately and then every interval until ctx is canceled
+func (c *DBPoolCollector) Start(ctx context.Context, interval time.Duration) {
+	c.Collect()
+	go c.run(ctx, interval)
+
+	logger.Info("Database pool metrics enabled", zap.Duration("interval", interval))
+}
+
+func (c *DBPoolCollector) run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			c.Collect()
+		}
+	}
+}
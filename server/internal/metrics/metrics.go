@@ -0,0 +1,92 @@
+// Package metrics exposes process gauges in the Prometheus text exposition format.
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"sync/atomic"
+)
+
+// contentType is the content type of the Prometheus text exposition format
+const contentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// Gauge is a value that can go up and down; it is safe for concurrent use
+type Gauge struct {
+	name string
+	help string
+	bits atomic.Uint64
+}
+
+// Set replaces the gauge's value
+func (g *Gauge) Set(value float64) {
+	g.bits.Store(math.Float64bits(value))
+}
+
+// Value returns the gauge's current value
+func (g *Gauge) Value() float64 {
+	return math.Float64frombits(g.bits.Load())
+}
+
+// Registry holds the gauges served by its handler, in registration order
+type Registry struct {
+	mu     sync.RWMutex
+	gauges []*Gauge
+	names  map[string]bool
+}
+
+func NewRegistry() *Registry {
+	return &Registry{names: make(map[string]bool)}
+}
+
+// NewGauge registers a gauge; registering the same name twice is a programming error and panics
+func (r *Registry) NewGauge(name, help string) *Gauge {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.names[name] {
+		panic(fmt.Sprintf("metrics: gauge %q registered twice", name))
+	}
+	r.names[name] = true
+
+	g := &Gauge{name: name, help: help}
+	r.gauges = append(r.gauges, g)
+	return g
+}
+
+// Gauge returns the registered gauge called name, if any
+func (r *Registry) Gauge(name string) (*Gauge, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, g := range r.gauges {
+		if g.name == name {
+			return g, true
+		}
+	}
+	return nil, false
+}
+
+// Write writes every gauge in the text exposition format
+func (r *Registry) Write(w io.Writer) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, g := range r.gauges {
+		fmt.Fprintf(w, "# HELP %s %s\n", g.name, g.help)
+		fmt.Fprintf(w, "# TYPE %s gauge\n", g.name)
+		fmt.Fprintf(w, "%s %s\n", g.name, strconv.FormatFloat(g.Value(), 'g', -1, 64))
+	}
+}
+
+// Handler serves the registry for Prometheus scraping
+func (r *Registry) Handler() http.HandlerFunc {
+	return func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", contentType)
+		w.WriteHeader(http.StatusOK)
+		r.Write(w)
+	}
+}
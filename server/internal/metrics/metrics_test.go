@@ -0,0 +1,92 @@
+package metrics
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistry_Handler(t *testing.T) {
+	reg := NewRegistry()
+	reg.NewGauge("first_gauge", "The first gauge.").Set(3)
+	reg.NewGauge("second_gauge", "The second gauge.").Set(0.25)
+
+	rec := httptest.NewRecorder()
+	reg.Handler()(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "text/plain; version=0.0.4; charset=utf-8", rec.Header().Get("Content-Type"))
+	assert.Equal(t, `# HELP first_gauge The first gauge.
+# TYPE first_gauge gauge
+first_gauge 3
+# HELP second_gauge The second gauge.
+# TYPE second_gauge gauge
+second_gauge 0.25
+`, rec.Body.String())
+}
+
+func TestRegistry_DuplicateGaugePanics(t *testing.T) {
+	reg := NewRegistry()
+	reg.NewGauge("dup", "Duplicate.")
+	assert.Panics(t, func() { reg.NewGauge("dup", "Duplicate.") })
+}
+
+// stubStats is a pool statistics source whose values the test controls
+type stubStats struct {
+	stats atomic.Pointer[sql.DBStats]
+}
+
+func (s *stubStats) set(stats sql.DBStats) { s.stats.Store(&stats) }
+func (s *stubStats) read() sql.DBStats     { return *s.stats.Load() }
+
+func gaugeValue(t *testing.T, reg *Registry, name string) float64 {
+	t.Helper()
+	g, ok := reg.Gauge(name)
+	require.True(t, ok, "gauge %s not registered", name)
+	return g.Value()
+}
+
+func TestDBPoolCollector_RegistersAndUpdatesGauges(t *testing.T) {
+	source := &stubStats{}
+	source.set(sql.DBStats{OpenConnections: 5, InUse: 3, Idle: 2, WaitCount: 7, WaitDuration: 1500 * time.Millisecond})
+
+	reg := NewRegistry()
+	collector := NewDBPoolCollector(reg, source.read)
+
+	for _, name := range []string{"db_open_connections", "db_in_use", "db_idle", "db_wait_count", "db_wait_duration_seconds"} {
+		assert.Zero(t, gaugeValue(t, reg, name), "%s before the first collection", name)
+	}
+
+	collector.Collect()
+
+	assert.Equal(t, 5.0, gaugeValue(t, reg, "db_open_connections"))
+	assert.Equal(t, 3.0, gaugeValue(t, reg, "db_in_use"))
+	assert.Equal(t, 2.0, gaugeValue(t, reg, "db_idle"))
+	assert.Equal(t, 7.0, gaugeValue(t, reg, "db_wait_count"))
+	assert.Equal(t, 1.5, gaugeValue(t, reg, "db_wait_duration_seconds"))
+}
+
+func TestDBPoolCollector_StartPollsUntilCanceled(t *testing.T) {
+	source := &stubStats{}
+	source.set(sql.DBStats{OpenConnections: 1})
+
+	reg := NewRegistry()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	NewDBPoolCollector(reg, source.read).Start(ctx, 10*time.Millisecond)
+
+	// The first collection happens before Start returns
+	assert.Equal(t, 1.0, gaugeValue(t, reg, "db_open_connections"))
+
+	source.set(sql.DBStats{OpenConnections: 4})
+	assert.Eventually(t, func() bool {
+		return gaugeValue(t, reg, "db_open_connections") == 4
+	}, time.Second, 5*time.Millisecond)
+}
@@ -2,6 +2,10 @@ package mocks
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/moabdelazem/k8s-app/internal/models"
@@ -34,6 +38,14 @@ func (m *MockPollRepository) GetPollOptions(ctx context.Context, pollID uuid.UUI
 	return args.Get(0).([]models.PollOption), args.Error(1)
 }
 
+func (m *MockPollRepository) GetOptionsForPolls(ctx context.Context, pollIDs []uuid.UUID) (map[uuid.UUID][]models.PollOption, error) {
+	args := m.Called(ctx, pollIDs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[uuid.UUID][]models.PollOption), args.Error(1)
+}
+
 func (m *MockPollRepository) ListPolls(ctx context.Context, limit, offset int, activeOnly bool) ([]models.Poll, error) {
 	args := m.Called(ctx, limit, offset, activeOnly)
 	if args.Get(0) == nil {
@@ -42,11 +54,45 @@ func (m *MockPollRepository) ListPolls(ctx context.Context, limit, offset int, a
 	return args.Get(0).([]models.Poll), args.Error(1)
 }
 
+func (m *MockPollRepository) ListPollsWithOptions(ctx context.Context, limit, offset int, activeOnly bool) ([]models.PollWithOptions, error) {
+	args := m.Called(ctx, limit, offset, activeOnly)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.PollWithOptions), args.Error(1)
+}
+
+// StreamPollsWithOptions passes each poll configured with Return to fn
+func (m *MockPollRepository) StreamPollsWithOptions(ctx context.Context, limit, offset int, activeOnly bool, fn func(models.PollWithOptions) error) error {
+	args := m.Called(ctx, limit, offset, activeOnly)
+	if polls, ok := args.Get(0).([]models.PollWithOptions); ok {
+		for _, poll := range polls {
+			if err := fn(poll); err != nil {
+				return err
+			}
+		}
+	}
+	return args.Error(1)
+}
+
+func (m *MockPollRepository) GetPollsByIDs(ctx context.Context, ids []uuid.UUID) ([]models.PollWithOptions, error) {
+	args := m.Called(ctx, ids)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.PollWithOptions), args.Error(1)
+}
+
 func (m *MockPollRepository) CastVote(ctx context.Context, vote *models.Vote) error {
 	args := m.Called(ctx, vote)
 	return args.Error(0)
 }
 
+func (m *MockPollRepository) CastWriteInVote(ctx context.Context, vote *models.Vote) error {
+	args := m.Called(ctx, vote)
+	return args.Error(0)
+}
+
 func (m *MockPollRepository) HasVoted(ctx context.Context, pollID uuid.UUID, voterIdentifier string) (bool, *uuid.UUID, error) {
 	args := m.Called(ctx, pollID, voterIdentifier)
 	if args.Get(1) == nil {
@@ -55,12 +101,232 @@ func (m *MockPollRepository) HasVoted(ctx context.Context, pollID uuid.UUID, vot
 	return args.Bool(0), args.Get(1).(*uuid.UUID), args.Error(2)
 }
 
+func (m *MockPollRepository) GetPollWithResults(ctx context.Context, pollID uuid.UUID, voterIdentifier string) (*models.PollWithVote, error) {
+	args := m.Called(ctx, pollID, voterIdentifier)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.PollWithVote), args.Error(1)
+}
+
+func (m *MockPollRepository) ListWriteIns(ctx context.Context, pollID uuid.UUID, grouped bool, limit int) ([]models.WriteInTally, error) {
+	args := m.Called(ctx, pollID, grouped, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.WriteInTally), args.Error(1)
+}
+
+func (m *MockPollRepository) HasVotedInGroup(ctx context.Context, group string, voterIdentifier string) (bool, error) {
+	args := m.Called(ctx, group, voterIdentifier)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockPollRepository) ListVotesByVoter(ctx context.Context, voterIdentifier string, limit, offset int) ([]models.VoterVote, error) {
+	args := m.Called(ctx, voterIdentifier, limit, offset)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.VoterVote), args.Error(1)
+}
+
+func (m *MockPollRepository) RemoveVote(ctx context.Context, pollID uuid.UUID, voterIdentifier string) error {
+	args := m.Called(ctx, pollID, voterIdentifier)
+	return args.Error(0)
+}
+
+func (m *MockPollRepository) UpdateOptionTexts(ctx context.Context, pollID uuid.UUID, options []models.PollOption) error {
+	args := m.Called(ctx, pollID, options)
+	return args.Error(0)
+}
+
 func (m *MockPollRepository) DeletePoll(ctx context.Context, id uuid.UUID) error {
 	args := m.Called(ctx, id)
 	return args.Error(0)
 }
 
+func (m *MockPollRepository) DeletePolls(ctx context.Context, filter models.PollFilter) ([]uuid.UUID, error) {
+	args := m.Called(ctx, filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]uuid.UUID), args.Error(1)
+}
+
 func (m *MockPollRepository) GetTotalPollsCount(ctx context.Context, activeOnly bool) (int64, error) {
 	args := m.Called(ctx, activeOnly)
 	return args.Get(0).(int64), args.Error(1)
 }
+
+func (m *MockPollRepository) CountActivePollsByOwner(ctx context.Context, ownerID string) (int64, error) {
+	args := m.Called(ctx, ownerID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockPollRepository) ListActiveByOwnerExcluding(ctx context.Context, ownerID string, excludePollID uuid.UUID, limit int) ([]models.PollWithOptions, error) {
+	args := m.Called(ctx, ownerID, excludePollID, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.PollWithOptions), args.Error(1)
+}
+
+func (m *MockPollRepository) SetFeatured(ctx context.Context, id uuid.UUID, featured bool, rank *int) error {
+	args := m.Called(ctx, id, featured, rank)
+	return args.Error(0)
+}
+
+func (m *MockPollRepository) ListFeaturedPolls(ctx context.Context, limit int) ([]models.PollWithOptions, error) {
+	args := m.Called(ctx, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.PollWithOptions), args.Error(1)
+}
+
+func (m *MockPollRepository) SetMetadata(ctx context.Context, id uuid.UUID, metadata json.RawMessage) error {
+	args := m.Called(ctx, id, metadata)
+	return args.Error(0)
+}
+
+func (m *MockPollRepository) ListPollsByMetadata(ctx context.Context, key, value string, limit, offset int) ([]models.PollWithOptions, error) {
+	args := m.Called(ctx, key, value, limit, offset)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.PollWithOptions), args.Error(1)
+}
+
+func (m *MockPollRepository) ListSubnetClusters(ctx context.Context, pollID uuid.UUID, minVotes int64, maxVoters int) ([]models.SubnetCluster, error) {
+	args := m.Called(ctx, pollID, minVotes, maxVoters)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.SubnetCluster), args.Error(1)
+}
+
+func (m *MockPollRepository) ListVoteBursts(ctx context.Context, pollID uuid.UUID, window time.Duration, minVotes int64, maxVoters int) ([]models.VoteBurst, error) {
+	args := m.Called(ctx, pollID, window, minVotes, maxVoters)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.VoteBurst), args.Error(1)
+}
+
+func (m *MockPollRepository) GetPeakVoteRate(ctx context.Context, pollID uuid.UUID, bucket time.Duration) (*models.VoteRatePeak, error) {
+	args := m.Called(ctx, pollID, bucket)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.VoteRatePeak), args.Error(1)
+}
+
+func (m *MockPollRepository) ExpireNow(ctx context.Context, id uuid.UUID) (time.Time, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(time.Time), args.Error(1)
+}
+
+func (m *MockPollRepository) DeactivateExpired(ctx context.Context) ([]uuid.UUID, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]uuid.UUID), args.Error(1)
+}
+
+func (m *MockPollRepository) GetVoteTimeline(ctx context.Context, pollID uuid.UUID, bucket time.Duration) ([]models.TimelineBucket, error) {
+	args := m.Called(ctx, pollID, bucket)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.TimelineBucket), args.Error(1)
+}
+
+// ImportVotes drains next like the real repository and passes the collected votes to Called
+func (m *MockPollRepository) ImportVotes(ctx context.Context, next func() (*models.Vote, error)) (int64, int64, error) {
+	var votes []models.Vote
+	for {
+		vote, err := next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return 0, 0, err
+		}
+		votes = append(votes, *vote)
+	}
+	args := m.Called(ctx, votes)
+	return args.Get(0).(int64), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockPollRepository) ArchiveClosedPolls(ctx context.Context, closedBefore time.Time, limit int) ([]uuid.UUID, error) {
+	args := m.Called(ctx, closedBefore, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]uuid.UUID), args.Error(1)
+}
+
+func (m *MockPollRepository) GetArchivedPollWithResults(ctx context.Context, pollID uuid.UUID, voterIdentifier string) (*models.PollWithVote, error) {
+	args := m.Called(ctx, pollID, voterIdentifier)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.PollWithVote), args.Error(1)
+}
+
+func (m *MockPollRepository) AddAllowedVoters(ctx context.Context, pollID uuid.UUID, voterIdentifiers []string) (int64, error) {
+	args := m.Called(ctx, pollID, voterIdentifiers)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockPollRepository) RemoveAllowedVoter(ctx context.Context, pollID uuid.UUID, voterIdentifier string) (bool, error) {
+	args := m.Called(ctx, pollID, voterIdentifier)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockPollRepository) ListAllowedVoters(ctx context.Context, pollID uuid.UUID) ([]models.AllowedVoter, error) {
+	args := m.Called(ctx, pollID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.AllowedVoter), args.Error(1)
+}
+
+func (m *MockPollRepository) IsVoterAllowed(ctx context.Context, pollID uuid.UUID, voterIdentifier string) (bool, error) {
+	args := m.Called(ctx, pollID, voterIdentifier)
+	return args.Bool(0), args.Error(1)
+}
+
+// ExportAll passes the records returned by Called to fn
+func (m *MockPollRepository) ExportAll(ctx context.Context, fn func(models.BackupRecord) error) error {
+	args := m.Called(ctx)
+	if records, ok := args.Get(0).([]models.BackupRecord); ok {
+		for _, record := range records {
+			if err := fn(record); err != nil {
+				return err
+			}
+		}
+	}
+	return args.Error(1)
+}
+
+// ImportAll drains next like the real repository and passes the collected records to Called
+func (m *MockPollRepository) ImportAll(ctx context.Context, next func() (*models.BackupRecord, error)) (*models.BackupSummary, error) {
+	var records []models.BackupRecord
+	for {
+		record, err := next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, *record)
+	}
+	args := m.Called(ctx, records)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.BackupSummary), args.Error(1)
+}
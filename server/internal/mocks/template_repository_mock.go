@@ -0,0 +1,45 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/moabdelazem/k8s-app/internal/models"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockTemplateRepository is a mock implementation of TemplateRepository
+type MockTemplateRepository struct {
+	mock.Mock
+}
+
+func (m *MockTemplateRepository) CreateTemplate(ctx context.Context, template *models.PollTemplate) error {
+	args := m.Called(ctx, template)
+	return args.Error(0)
+}
+
+func (m *MockTemplateRepository) GetTemplate(ctx context.Context, id uuid.UUID) (*models.PollTemplate, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.PollTemplate), args.Error(1)
+}
+
+func (m *MockTemplateRepository) ListTemplates(ctx context.Context) ([]models.PollTemplate, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.PollTemplate), args.Error(1)
+}
+
+func (m *MockTemplateRepository) UpdateTemplate(ctx context.Context, template *models.PollTemplate) (bool, error) {
+	args := m.Called(ctx, template)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockTemplateRepository) DeleteTemplate(ctx context.Context, id uuid.UUID) (bool, error) {
+	args := m.Called(ctx, id)
+	return args.Bool(0), args.Error(1)
+}
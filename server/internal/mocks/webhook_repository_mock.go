@@ -0,0 +1,40 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/moabdelazem/k8s-app/internal/models"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockWebhookRepository is a mock implementation of WebhookRepository
+type MockWebhookRepository struct {
+	mock.Mock
+}
+
+func (m *MockWebhookRepository) CreateWebhook(ctx context.Context, webhook *models.Webhook) error {
+	args := m.Called(ctx, webhook)
+	return args.Error(0)
+}
+
+func (m *MockWebhookRepository) ListWebhooks(ctx context.Context, pollID uuid.UUID) ([]models.Webhook, error) {
+	args := m.Called(ctx, pollID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Webhook), args.Error(1)
+}
+
+func (m *MockWebhookRepository) ListWebhooksForEvent(ctx context.Context, pollID uuid.UUID, event string) ([]models.Webhook, error) {
+	args := m.Called(ctx, pollID, event)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Webhook), args.Error(1)
+}
+
+func (m *MockWebhookRepository) DeleteWebhook(ctx context.Context, pollID, webhookID uuid.UUID) (bool, error) {
+	args := m.Called(ctx, pollID, webhookID)
+	return args.Bool(0), args.Error(1)
+}
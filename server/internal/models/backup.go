@@ -0,0 +1,116 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Backup record types. A bundle is a header followed by every poll, then every option, then every
+// vote, then every write-in vote, then every allowed voter, so each record only references earlier ones.
+// It closes with an end record counting the others, which tells a complete bundle from a truncated one.
+const (
+	BackupRecordHeader       = "header"
+	BackupRecordPoll         = "poll"
+	BackupRecordOption       = "option"
+	BackupRecordVote         = "vote"
+	BackupRecordWriteInVote  = "write_in_vote"
+	BackupRecordAllowedVoter = "allowed_voter"
+	BackupRecordEnd          = "end"
+)
+
+// BackupRecord is one line of an NDJSON backup bundle; the field named by Type is set
+type BackupRecord struct {
+	Type         string              `json:"type"`
+	Header       *BackupHeader       `json:"header,omitempty"`
+	Poll         *BackupPoll         `json:"poll,omitempty"`
+	Option       *BackupOption       `json:"option,omitempty"`
+	Vote         *BackupVote         `json:"vote,omitempty"`
+	WriteInVote  *BackupWriteInVote  `json:"write_in_vote,omitempty"`
+	AllowedVoter *BackupAllowedVoter `json:"allowed_voter,omitempty"`
+	End          *BackupSummary      `json:"end,omitempty"`
+}
+
+// BackupHeader opens a bundle
+type BackupHeader struct {
+	Version    int       `json:"version"` // Bundle format version
+	ExportedAt time.Time `json:"exported_at"`
+}
+
+// BackupPoll is a poll as stored, including the fields API responses hide
+// Vote totals are not stored; they are recounted from the votes on restore.
+type BackupPoll struct {
+	ID                  uuid.UUID       `json:"id"`
+	Question            string          `json:"question"`
+	Description         *string         `json:"description,omitempty"`
+	CreatedAt           time.Time       `json:"created_at"`
+	ExpiresAt           *time.Time      `json:"expires_at,omitempty"`
+	IsActive            bool            `json:"is_active"`
+	OwnerID             *string         `json:"owner_id,omitempty"`
+	AllowWeighted       bool            `json:"allow_weighted"`
+	RequireConfirmation bool            `json:"require_confirmation"`
+	QuizMode            bool            `json:"quiz_mode"`
+	Group               *string         `json:"group,omitempty"`
+	RandomizeOptions    bool            `json:"randomize_options"`
+	AllowlistOnly       bool            `json:"allowlist_only"`
+	AllowWriteIn        bool            `json:"allow_write_in"`
+	MaxVotes            *int64          `json:"max_votes,omitempty"`
+	VotingWindowStart   *string         `json:"voting_window_start,omitempty"`
+	VotingWindowEnd     *string         `json:"voting_window_end,omitempty"`
+	Timezone            *string         `json:"timezone,omitempty"`
+	AcknowledgementMode bool            `json:"acknowledgement_mode"`
+	Featured            bool            `json:"featured"`
+	FeaturedRank        *int            `json:"featured_rank,omitempty"`
+	Metadata            json.RawMessage `json:"metadata,omitempty"`
+	ClosedAt            *time.Time      `json:"closed_at,omitempty"`
+	DeletedAt           *time.Time      `json:"deleted_at,omitempty"`
+}
+
+// BackupOption is a poll option as stored, without its vote count
+type BackupOption struct {
+	ID         uuid.UUID `json:"id"`
+	PollID     uuid.UUID `json:"poll_id"`
+	OptionText string    `json:"option_text"`
+	Position   int       `json:"position"`
+	IsCorrect  bool      `json:"is_correct"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// BackupVote is a vote as stored, including the voter identifier
+type BackupVote struct {
+	ID              uuid.UUID `json:"id"`
+	PollID          uuid.UUID `json:"poll_id"`
+	OptionID        uuid.UUID `json:"option_id"`
+	VoterIdentifier string    `json:"voter_identifier"`
+	Weight          int64     `json:"weight"`
+	Campaign        *string   `json:"campaign,omitempty"`
+	VotedAt         time.Time `json:"voted_at"`
+}
+
+// BackupWriteInVote is a write-in vote as stored, including the voter identifier
+type BackupWriteInVote struct {
+	ID              uuid.UUID `json:"id"`
+	PollID          uuid.UUID `json:"poll_id"`
+	VoterIdentifier string    `json:"voter_identifier"`
+	WriteIn         string    `json:"write_in"`
+	Weight          int64     `json:"weight"`
+	Campaign        *string   `json:"campaign,omitempty"`
+	VotedAt         time.Time `json:"voted_at"`
+}
+
+// BackupAllowedVoter is an entry of a poll's allowlist
+type BackupAllowedVoter struct {
+	PollID          uuid.UUID `json:"poll_id"`
+	VoterIdentifier string    `json:"voter_identifier"`
+	AddedAt         time.Time `json:"added_at"`
+}
+
+// BackupSummary counts the records of a bundle, or those restored from one
+type BackupSummary struct {
+	Polls         int64 `json:"polls"`
+	Options       int64 `json:"options"`
+	Votes         int64 `json:"votes"`
+	WriteInVotes  int64 `json:"write_in_votes"`
+	AllowedVoters int64 `json:"allowed_voters"`
+}
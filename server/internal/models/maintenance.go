@@ -0,0 +1,6 @@
+package models
+
+// ReadOnlyRequest represents the request to toggle read-only mode
+type ReadOnlyRequest struct {
+	Enabled *bool `json:"enabled"` // Required; a pointer distinguishes false from missing
+}
@@ -1,6 +1,7 @@
 package models
 
 import (
+	"encoding/json"
 	"time"
 
 	"github.com/google/uuid"
@@ -8,13 +9,30 @@ import (
 
 // Poll represents a poll question
 type Poll struct {
-	ID          uuid.UUID  `json:"id"`
-	Question    string     `json:"question"`
-	Description *string    `json:"description,omitempty"`
-	CreatedAt   time.Time  `json:"created_at"`
-	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
-	IsActive    bool       `json:"is_active"`
-	TotalVotes  int64      `json:"total_votes"`
+	ID                  uuid.UUID       `json:"id"`
+	Question            string          `json:"question"`
+	Description         *string         `json:"description,omitempty"`
+	CreatedAt           time.Time       `json:"created_at"`
+	ExpiresAt           *time.Time      `json:"expires_at,omitempty"`
+	IsActive            bool            `json:"is_active"`
+	TotalVotes          int64           `json:"total_votes"`
+	AllowWeighted       bool            `json:"allow_weighted"`
+	RequireConfirmation bool            `json:"require_confirmation"`
+	QuizMode            bool            `json:"quiz_mode"`
+	Group               *string         `json:"group,omitempty"`               // Polls sharing a group accept one vote per voter across the group
+	RandomizeOptions    bool            `json:"randomize_options"`             // Each voter sees the options in their own shuffled order
+	AllowlistOnly       bool            `json:"allowlist_only"`                // Only voters on the poll's allowlist may vote
+	AllowWriteIn        bool            `json:"allow_write_in"`                // Voters may write in their own answer instead of choosing an option
+	MaxVotes            *int64          `json:"max_votes,omitempty"`           // Capacity; votes that would take total_votes past it are rejected
+	VotingWindowStart   *string         `json:"voting_window_start,omitempty"` // Time of day (HH:MM) votes open, in Timezone
+	VotingWindowEnd     *string         `json:"voting_window_end,omitempty"`   // Time of day (HH:MM) votes close; before the start for overnight windows
+	Timezone            *string         `json:"timezone,omitempty"`            // IANA zone of the voting window; UTC when absent
+	AcknowledgementMode bool            `json:"acknowledgement_mode"`          // Single-option poll; each vote acknowledges it
+	Featured            bool            `json:"featured"`                      // Pinned by editors to the featured listing
+	FeaturedRank        *int            `json:"featured_rank,omitempty"`       // Position in the featured listing, lowest first; unranked polls come last
+	Metadata            json.RawMessage `json:"metadata,omitempty"`            // Integrator-defined JSON object, e.g. campaign IDs or UI hints
+	OwnerID             *string         `json:"-"`                             // Hidden from JSON response
+	DeletedAt           *time.Time      `json:"-"`                             // Set once the poll is deleted; reads report deleted polls as gone
 }
 
 // PollOption represents a poll option/choice
@@ -24,6 +42,7 @@ type PollOption struct {
 	OptionText string    `json:"option_text"`
 	VoteCount  int64     `json:"vote_count"`
 	Position   int       `json:"position"`
+	IsCorrect  bool      `json:"-"` // Quiz answer; only revealed through OptionResult after voting
 	CreatedAt  time.Time `json:"created_at"`
 }
 
@@ -31,8 +50,11 @@ type PollOption struct {
 type Vote struct {
 	ID              uuid.UUID `json:"id"`
 	PollID          uuid.UUID `json:"poll_id"`
-	OptionID        uuid.UUID `json:"option_id"`
-	VoterIdentifier string    `json:"-"` // Hidden from JSON response
+	OptionID        uuid.UUID `json:"option_id"` // uuid.Nil for a write-in vote
+	VoterIdentifier string    `json:"-"`         // Hidden from JSON response
+	Weight          int64     `json:"weight"`
+	Campaign        *string   `json:"campaign,omitempty"` // Share link campaign the vote came through
+	WriteIn         *string   `json:"write_in,omitempty"` // Free-text answer of a write-in vote
 	VotedAt         time.Time `json:"voted_at"`
 }
 
@@ -42,30 +64,320 @@ type PollWithOptions struct {
 	Options []PollOption `json:"options"`
 }
 
+// PollWithVote is a poll with its options and a voter's vote on it, as read for results
+type PollWithVote struct {
+	PollWithOptions
+	VotedOption  *uuid.UUID     `json:"voted_option,omitempty"` // nil when the voter has not voted or none was given
+	VotedWriteIn bool           `json:"-"`                      // The voter cast a write-in vote, which has no option
+	WriteIns     []WriteInTally `json:"-"`                      // Tallies of the poll's write-in votes, when it allows them
+	Archived     bool           `json:"-"`                      // Read from the archive tables
+}
+
+// PollList is one page of polls with their options
+type PollList struct {
+	Polls  []PollWithOptions `json:"polls"`
+	Total  int64             `json:"total"`
+	Limit  int               `json:"limit"` // Effective page size, which may be lower than requested
+	Offset int               `json:"offset"`
+}
+
+// PollResultsList is one page of polls with their results
+type PollResultsList struct {
+	Results []PollResults `json:"results"`
+	Total   int64         `json:"total"`
+	Limit   int           `json:"limit"`
+	Offset  int           `json:"offset"`
+}
+
 // PollResults represents poll results with percentages
 type PollResults struct {
 	Poll
-	Options     []OptionResult `json:"options"`
-	TotalVotes  int64          `json:"total_votes"`
-	HasVoted    bool           `json:"has_voted"`
-	VotedOption *uuid.UUID     `json:"voted_option,omitempty"`
+	Options           []OptionResult `json:"options"`
+	TotalVotes        int64          `json:"total_votes"`
+	HasVoted          bool           `json:"has_voted"`
+	VotedOption       *uuid.UUID     `json:"voted_option,omitempty"`
+	AnsweredCorrectly *bool          `json:"answered_correctly,omitempty"` // Quiz polls, once the voter has voted
+	Leading           []uuid.UUID    `json:"leading"`                      // Options tied for the most votes, in option order; empty without votes
+	WinningOption     *uuid.UUID     `json:"winning_option"`               // The one option with the most votes; null on a tie or without votes
+	WriteIns          *WriteInResult `json:"write_ins,omitempty"`          // Polls allowing write-ins
+	Acknowledgements  *int64         `json:"acknowledgements,omitempty"`   // Acknowledgement polls: how many voters acknowledged
+	Receipt           *VoteReceipt   `json:"receipt,omitempty"`            // Only in the response to a recorded vote, when receipts are enabled
 }
 
 // OptionResult represents an option with calculated percentage
 type OptionResult struct {
 	PollOption
 	Percentage float64 `json:"percentage"`
+	IsCorrect  *bool   `json:"is_correct,omitempty"` // Quiz polls, once the voter has voted
+}
+
+// WriteInResult is the pseudo-option counting a poll's write-in votes
+type WriteInResult struct {
+	VoteCount  int64          `json:"vote_count"`
+	Percentage float64        `json:"percentage"`
+	Entries    []WriteInTally `json:"entries"`   // Most votes first when grouped, newest first when listed individually
+	Truncated  bool           `json:"truncated"` // True when entries beyond the cap were dropped
+}
+
+// WriteInTally counts the votes for one write-in answer
+// When write-ins are grouped, answers differing only in case share a tally under the first spelling used
+type WriteInTally struct {
+	Text      string `json:"text"`
+	VoteCount int64  `json:"vote_count"`
+}
+
+// VoteTimeline represents per-option vote counts grouped into fixed time buckets
+type VoteTimeline struct {
+	PollID    uuid.UUID        `json:"poll_id"`
+	Bucket    string           `json:"bucket"`    // Bucket width, e.g. "1h0m0s"
+	Buckets   []TimelineBucket `json:"buckets"`   // Oldest first; buckets without votes are omitted
+	Truncated bool             `json:"truncated"` // True when older buckets were dropped to respect the cap
+}
+
+// TimelineBucket holds the votes cast within one bucket
+type TimelineBucket struct {
+	Start   time.Time         `json:"start"`
+	Options []OptionVoteCount `json:"options"`
+}
+
+// OptionVoteCount is the number of votes an option received within a bucket
+type OptionVoteCount struct {
+	OptionID uuid.UUID `json:"option_id"`
+	Votes    int64     `json:"votes"`
+}
+
+// VoteImportSummary reports the outcome of a bulk vote import
+type VoteImportSummary struct {
+	PollID   uuid.UUID `json:"poll_id"`
+	Imported int64     `json:"imported"`
+	Skipped  int64     `json:"skipped"` // Rows for voters who already voted on the poll
+}
+
+// SuspiciousVoteReport lists the voting patterns on a poll worth a manual fraud review
+type SuspiciousVoteReport struct {
+	PollID         uuid.UUID           `json:"poll_id"`
+	Thresholds     SuspicionThresholds `json:"thresholds"`
+	SubnetClusters []SubnetCluster     `json:"subnet_clusters"` // Most votes first
+	Bursts         []VoteBurst         `json:"bursts"`          // Oldest first
+}
+
+// SuspicionThresholds are the limits a report flagged patterns against
+type SuspicionThresholds struct {
+	SubnetMinVotes int64  `json:"subnet_min_votes"` // Votes from one IPv4 /24 subnet that flag it
+	BurstWindow    string `json:"burst_window"`     // Width of the windows bursts are counted in, e.g. "1m0s"
+	BurstMinVotes  int64  `json:"burst_min_votes"`  // Votes within one window that flag it
+}
+
+// SubnetCluster counts the votes cast from one IPv4 /24 subnet
+// Only voters identified by an IPv4 address, possibly with a port or further X-Forwarded-For entries, can be grouped into subnets
+type SubnetCluster struct {
+	Subnet string   `json:"subnet"` // e.g. "203.0.113.0/24"
+	Votes  int64    `json:"votes"`
+	Voters []string `json:"voters"` // In identifier order, capped; votes counts them all
+}
+
+// VoteBurst counts the votes cast within one window, aligned to the Unix epoch
+type VoteBurst struct {
+	Start  time.Time `json:"start"`
+	End    time.Time `json:"end"`
+	Votes  int64     `json:"votes"`
+	Voters []string  `json:"voters"` // Earliest vote first, capped; votes counts them all
+}
+
+// VoteRateReport reports the busiest window of a poll's votes, for sizing the database ahead of big polls
+type VoteRateReport struct {
+	PollID             uuid.UUID     `json:"poll_id"`
+	Bucket             string        `json:"bucket"`                // Width of the windows votes are counted in, e.g. "1s"
+	Peak               *VoteRatePeak `json:"peak"`                  // Null before any votes
+	PeakVotesPerSecond float64       `json:"peak_votes_per_second"` // Peak votes averaged over the bucket width
+}
+
+// VoteRatePeak is the window, aligned to the Unix epoch, in which a poll received the most votes
+type VoteRatePeak struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+	Votes int64     `json:"votes"`
+}
+
+// AllowedVoter is a voter invited to vote on an allowlist-only poll
+type AllowedVoter struct {
+	VoterIdentifier string    `json:"voter_identifier"`
+	AddedAt         time.Time `json:"added_at"`
+}
+
+// AllowlistRequest is the request body for adding voters to a poll's allowlist
+type AllowlistRequest struct {
+	Voters []string `json:"voters"` // Resolved voter identifiers, e.g. "user:<subject>" for authenticated voters
+}
+
+// PollFilter selects polls for an admin bulk operation
+// Set filters combine with AND; an empty filter matches nothing.
+type PollFilter struct {
+	IDs           []uuid.UUID `json:"ids,omitempty"`
+	OwnerID       *string     `json:"owner_id,omitempty"`       // Creator as stored with the poll, e.g. "user:<subject>"
+	CreatedBefore *time.Time  `json:"created_before,omitempty"` // Polls created strictly before this time
+}
+
+// BulkDeleteRequest is the request body for deleting every poll matching a filter
+type BulkDeleteRequest struct {
+	PollFilter
+	Confirm bool `json:"confirm"` // Must be true; guards against deleting by accident
+}
+
+// FeatureRequest is the request body for pinning a poll to, or removing it from, the featured listing
+type FeatureRequest struct {
+	Featured bool `json:"featured"`
+	Rank     *int `json:"rank,omitempty"` // Position among featured polls, lowest first; only with featured
 }
 
 // CreatePollRequest represents the request to create a poll
 type CreatePollRequest struct {
-	Question    string     `json:"question"`
-	Description *string    `json:"description,omitempty"`
-	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
-	Options     []string   `json:"options"`
+	Question            string          `json:"question" validate:"min=5,max=500"`
+	Description         *string         `json:"description,omitempty"`
+	ExpiresAt           *time.Time      `json:"expires_at,omitempty"`
+	Options             []string        `json:"options" validate:"max=10,dive,min=1,max=200"` // Between 2 and 10, or exactly 1 on acknowledgement polls
+	AllowWeighted       bool            `json:"allow_weighted,omitempty"`
+	RequireConfirmation bool            `json:"require_confirmation,omitempty"`
+	QuizMode            bool            `json:"quiz_mode,omitempty"`
+	CorrectOptions      []int           `json:"correct_options,omitempty" validate:"dive,min=0"` // Zero-based indexes into Options; quiz polls only
+	Group               *string         `json:"group,omitempty"`                                 // Poll series to dedupe voters across, matched case-insensitively
+	RandomizeOptions    bool            `json:"randomize_options,omitempty"`                     // Shuffle options per voter to counter order bias
+	AllowlistOnly       bool            `json:"allowlist_only,omitempty"`                        // Restrict voting to voters added by an admin
+	AllowWriteIn        bool            `json:"allow_write_in,omitempty"`                        // Accept free-text answers besides the options
+	MaxVotes            *int64          `json:"max_votes,omitempty" validate:"omitempty,min=1"`  // Stop accepting votes once total_votes reaches it
+	VotingWindowStart   *string         `json:"voting_window_start,omitempty"`                   // Only accept votes from this time of day (HH:MM)...
+	VotingWindowEnd     *string         `json:"voting_window_end,omitempty"`                     // ...until this one; both or neither must be set
+	Timezone            *string         `json:"timezone,omitempty"`                              // IANA zone the window is in, e.g. Europe/Berlin; UTC when absent
+	AcknowledgementMode bool            `json:"acknowledgement_mode,omitempty"`                  // Take exactly one option, e.g. "I have read this notice", and count votes as acknowledgements
+	Metadata            json.RawMessage `json:"metadata,omitempty"`                              // JSON object stored with the poll and returned as is
+}
+
+// MetadataRequest is the request body for replacing a poll's metadata; null removes it
+type MetadataRequest struct {
+	Metadata json.RawMessage `json:"metadata"`
+}
+
+// OptionUpdate sets the text of one existing option
+// The option is matched by ID when given, otherwise by its position in the request
+type OptionUpdate struct {
+	ID   *uuid.UUID `json:"id,omitempty"`
+	Text string     `json:"text"`
+}
+
+// UpdateOptionsRequest represents the request to edit a poll's option texts
+// It must list every option of the poll exactly once
+type UpdateOptionsRequest struct {
+	Options []OptionUpdate `json:"options"`
 }
 
 // VoteRequest represents the request to vote on a poll
 type VoteRequest struct {
+	OptionID   uuid.UUID `json:"option_id" validate:"required_without=WriteIn,excluded_with=WriteIn"`
+	WriteIn    string    `json:"write_in,omitempty"`    // Free-text answer given instead of option_id, on polls allowing write-ins
+	Weight     int64     `json:"weight,omitempty"`      // Only honored on polls allowing weighted votes; defaults to 1
+	ShareToken string    `json:"share_token,omitempty"` // Share link token the voter arrived with, for campaign attribution
+}
+
+// ShareLinkRequest is the request body for creating a poll share link
+type ShareLinkRequest struct {
+	Campaign string `json:"campaign,omitempty"` // Attribution tag carried by the link
+}
+
+// ShareLink is a signed token resolving to a poll
+type ShareLink struct {
+	Token     string    `json:"token"`
+	Campaign  string    `json:"campaign,omitempty"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// SharedPoll is a poll reached through a share link
+// The share token should be passed back when voting so the vote is attributed to the campaign
+type SharedPoll struct {
+	Poll       *PollResults `json:"poll"`
+	Campaign   string       `json:"campaign,omitempty"`
+	ShareToken string       `json:"share_token"`
+}
+
+// OEmbed is an oEmbed "rich" response embedding a poll's results chart in an iframe
+type OEmbed struct {
+	Version      string `json:"version"` // Always "1.0"
+	Type         string `json:"type"`    // Always "rich"
+	Title        string `json:"title"`   // The poll's question
+	ProviderName string `json:"provider_name"`
+	HTML         string `json:"html"`
+	Width        int    `json:"width"`
+	Height       int    `json:"height"`
+}
+
+// VoteConfirmation is returned instead of results when a poll requires votes to be confirmed
+type VoteConfirmation struct {
+	Token     string    `json:"confirmation_token"`
+	ExpiresAt time.Time `json:"expires_at"` // The pending vote is discarded after this time
+}
+
+// VoteReceipt is signed proof that a vote was recorded; it does not reveal the voter
+type VoteReceipt struct {
+	Receipt  string    `json:"receipt"`
+	PollID   uuid.UUID `json:"poll_id"`
 	OptionID uuid.UUID `json:"option_id"`
+	VotedAt  time.Time `json:"voted_at"`
+}
+
+// VoteStatus tells a voter whether they have voted on a poll, without the poll's results
+type VoteStatus struct {
+	HasVoted    bool       `json:"has_voted"`
+	VotedOption *uuid.UUID `json:"voted_option,omitempty"`
+}
+
+// Voting statuses: whether a poll accepts votes right now, and if not, why
+const (
+	VotingOpen       = "open"
+	VotingNotStarted = "not_started" // Outside the poll's daily voting window; opens_at is when it next opens
+	VotingExpired    = "expired"
+	VotingClosed     = "closed" // Deactivated or deleted
+	VotingFull       = "full"   // max_votes reached
+)
+
+// VotingStatus tells clients whether a poll accepts votes right now
+// It does not consider the requester: an open poll may still turn away a voter who already voted
+// or is not on an allowlist-only poll's allowlist.
+type VotingStatus struct {
+	Status         string     `json:"status"`
+	AcceptingVotes bool       `json:"accepting_votes"`
+	OpensAt        *time.Time `json:"opens_at,omitempty"` // Polls not_started: when the voting window next opens
+}
+
+// Vote outcomes reported by vote validation
+const (
+	VoteOutcomeRecorded            = "recorded"
+	VoteOutcomePendingConfirmation = "pending_confirmation" // The poll requires confirmation; the vote would be held until confirmed
+)
+
+// VoteValidation is the outcome a vote request would have, found without casting the vote
+type VoteValidation struct {
+	Outcome string `json:"outcome"`
+	Weight  int64  `json:"weight"` // Weight the vote would count with
+}
+
+// VoterVote is a vote in a voter's own history, with the poll and option it was cast for
+type VoterVote struct {
+	PollID     uuid.UUID  `json:"poll_id"`
+	Question   string     `json:"question"`
+	OptionID   *uuid.UUID `json:"option_id,omitempty"`   // nil for a write-in vote
+	OptionText *string    `json:"option_text,omitempty"` // nil for a write-in vote
+	WriteIn    *string    `json:"write_in,omitempty"`    // Free-text answer of a write-in vote
+	Weight     int64      `json:"weight"`
+	VotedAt    time.Time  `json:"voted_at"`
+}
+
+// VoterVoteList is one page of a voter's votes, newest first
+type VoterVoteList struct {
+	Votes  []VoterVote `json:"votes"`
+	Limit  int         `json:"limit"`
+	Offset int         `json:"offset"`
+}
+
+// ConfirmVoteRequest represents the request to confirm a pending vote
+type ConfirmVoteRequest struct {
+	Token string `json:"confirmation_token"`
 }
@@ -0,0 +1,26 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PollTemplate is a reusable poll structure that new polls can be created from
+type PollTemplate struct {
+	ID          uuid.UUID `json:"id"`
+	Name        string    `json:"name"`
+	Question    string    `json:"question"`
+	Description *string   `json:"description,omitempty"`
+	Options     []string  `json:"options"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// TemplateRequest represents the request to create or replace a poll template
+type TemplateRequest struct {
+	Name        string   `json:"name"`
+	Question    string   `json:"question"`
+	Description *string  `json:"description,omitempty"`
+	Options     []string `json:"options"`
+}
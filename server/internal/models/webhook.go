@@ -0,0 +1,50 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Poll event types delivered to webhooks
+const (
+	EventPollCreated = "poll.created" // Published before any webhook can be registered for the poll
+	EventVoteCast    = "vote.cast"
+	EventPollClosed  = "poll.closed"
+	EventMilestone   = "poll.milestone" // The poll's total votes reached one of VOTE_MILESTONES for the first time
+)
+
+// WebhookEvents lists every event type a webhook may subscribe to
+var WebhookEvents = []string{EventVoteCast, EventPollClosed, EventMilestone}
+
+// Webhook represents a callback URL registered for a poll's events
+type Webhook struct {
+	ID        uuid.UUID `json:"id"`
+	PollID    uuid.UUID `json:"poll_id"`
+	URL       string    `json:"url"`
+	Events    []string  `json:"events"`
+	Secret    string    `json:"-"` // Used for signing, never returned
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// CreateWebhookRequest represents the request to register a webhook
+type CreateWebhookRequest struct {
+	URL    string   `json:"url"`
+	Events []string `json:"events,omitempty"`
+	Secret string   `json:"secret"`
+}
+
+// PollEvent describes something that happened to a poll
+type PollEvent struct {
+	Type       string    `json:"event"`
+	PollID     uuid.UUID `json:"poll_id"`
+	OccurredAt time.Time `json:"occurred_at"`
+	Data       any       `json:"data,omitempty"`
+}
+
+// OutboxEvent is a poll event recorded in the outbox, identified by its publication order
+type OutboxEvent struct {
+	ID       int64
+	Attempts int // Failed publish attempts so far
+	PollEvent
+}
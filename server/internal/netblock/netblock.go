@@ -0,0 +1,66 @@
+// Package netblock matches client addresses against lists of blocked network ranges.
+package netblock
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/netip"
+	"os"
+	"strings"
+
+	"github.com/moabdelazem/k8s-app/internal/geoip"
+)
+
+// List is a set of blocked network ranges, e.g. known cloud and data-center ranges.
+// A List must not be modified once it is in use.
+type List struct {
+	table *geoip.Table // Prefix lookups; regions are unused
+	size  int
+}
+
+// Contains reports whether addr falls in any of the list's ranges
+func (l *List) Contains(addr netip.Addr) bool {
+	_, ok := l.table.Region(addr)
+	return ok
+}
+
+// Len returns the number of ranges in the list
+func (l *List) Len() int {
+	return l.size
+}
+
+// Load reads a list with one CIDR range per line.
+// Blank lines and lines starting with # are ignored.
+func Load(r io.Reader) (*List, error) {
+	list := &List{table: geoip.NewTable()}
+
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		prefix, err := netip.ParsePrefix(text)
+		if err != nil {
+			return nil, fmt.Errorf("invalid blocklist: line %d: %w", line, err)
+		}
+		list.table.Add(prefix, "")
+		list.size++
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("invalid blocklist: %w", err)
+	}
+	return list, nil
+}
+
+// LoadFile reads a list from a file; see Load
+func LoadFile(path string) (*List, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open blocklist: %w", err)
+	}
+	defer f.Close()
+	return Load(f)
+}
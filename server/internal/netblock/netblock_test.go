@@ -0,0 +1,46 @@
+package netblock
+
+import (
+	"net/netip"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestList_Contains(t *testing.T) {
+	list, err := LoadFile("testdata/datacenters.txt")
+	require.NoError(t, err)
+	assert.Equal(t, 3, list.Len())
+
+	tests := []struct {
+		name string
+		addr string
+		want bool
+	}{
+		{name: "inside ipv4 range", addr: "203.0.113.7", want: true},
+		{name: "inside upper half range", addr: "198.51.100.200", want: true},
+		{name: "outside upper half range", addr: "198.51.100.1", want: false},
+		{name: "ipv4-mapped ipv6", addr: "::ffff:203.0.113.7", want: true},
+		{name: "inside ipv6 range", addr: "2001:db8:10::1", want: true},
+		{name: "outside ipv6 range", addr: "2001:db8:11::1", want: false},
+		{name: "residential address", addr: "192.0.2.1", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, list.Contains(netip.MustParseAddr(tt.addr)))
+		})
+	}
+}
+
+func TestLoad_RejectsInvalidRange(t *testing.T) {
+	_, err := Load(strings.NewReader("203.0.113.0/24\nnot-a-cidr\n"))
+	assert.ErrorContains(t, err, "line 2")
+}
+
+func TestLoadFile_Missing(t *testing.T) {
+	_, err := LoadFile("testdata/missing.txt")
+	assert.ErrorContains(t, err, "failed to open blocklist")
+}
@@ -0,0 +1,194 @@
+// Package outbox publishes the poll events that repositories write to the outbox table
+// in the same transaction as the change they describe.
+package outbox
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/moabdelazem/k8s-app/internal/models"
+	"github.com/moabdelazem/k8s-app/internal/repository"
+	"github.com/moabdelazem/k8s-app/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// Publisher delivers outbox events to their consumers
+// A nil error means the event was delivered and will not be offered again
+type Publisher interface {
+	Publish(ctx context.Context, event models.PollEvent) error
+}
+
+// Config represents outbox relay configuration
+type Config struct {
+	Interval     time.Duration // Delay between polls of the outbox once it is drained
+	BatchSize    int           // Events read per query
+	MaxAttempts  int           // Failed publishes before an event is dead-lettered
+	RetryBackoff time.Duration // Delay before a failed event's first retry, doubling with each further failure
+	ClaimTimeout time.Duration // How long other relays leave a fetched batch alone while it is published
+}
+
+// maxRetryBackoff caps the delay between retries of a failing event
+const maxRetryBackoff = time.Hour
+
+// Relay moves events from the outbox to a Publisher.
+// Delivery is at least once: an event published just before its row could be marked
+// is published again once its claim runs out. Events of a poll are published in the order
+// they were written; once one fails, the poll's later events wait until it is retried with
+// exponential backoff, or dead-lettered after MaxAttempts failures, while other polls carry on.
+// Relays on several replicas take turns fetching through the store's relay lock and claim what they
+// fetched for ClaimTimeout, keeping that order without holding the lock while they publish.
+type Relay struct {
+	store     repository.OutboxRepositoryInterface
+	publisher Publisher
+	cfg       Config
+}
+
+// NewRelay creates a relay reading store and publishing to publisher
+func NewRelay(store repository.OutboxRepositoryInterface, publisher Publisher, cfg Config) *Relay {
+	// Set default values if not provided
+	if cfg.Interval <= 0 {
+		cfg.Interval = time.Second
+	}
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = 100
+	}
+	if cfg.MaxAttempts <= 0 {
+		cfg.MaxAttempts = 10
+	}
+	if cfg.RetryBackoff <= 0 {
+		cfg.RetryBackoff = 10 * time.Second
+	}
+	if cfg.ClaimTimeout <= 0 {
+		cfg.ClaimTimeout = 5 * time.Minute
+	}
+
+	return &Relay{store: store, publisher: publisher, cfg: cfg}
+}
+
+// Start launches the relay loop; it stops when ctx is canceled
+func (r *Relay) Start(ctx context.Context) {
+	go r.run(ctx)
+
+	logger.Info("Outbox relay started",
+		zap.Duration("interval", r.cfg.Interval),
+		zap.Int("batch_size", r.cfg.BatchSize),
+		zap.Int("max_attempts", r.cfg.MaxAttempts),
+	)
+}
+
+// run drains the outbox every interval until ctx is canceled
+func (r *Relay) run(ctx context.Context) {
+	ticker := time.NewTicker(r.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			r.drain(ctx)
+		}
+	}
+}
+
+// drain relays full batches until the outbox has no more events due or the store fails,
+// leaving the outbox to the relay holding the relay lock otherwise
+func (r *Relay) drain(ctx context.Context) {
+	for {
+		fetched, locked, err := r.relayBatch(ctx)
+		if err != nil {
+			logger.Warn("Outbox relay incomplete, retrying on the next pass", zap.Error(err))
+			return
+		}
+		if !locked {
+			logger.Debug("Outbox relay lock held by another replica, skipping pass")
+			return
+		}
+		if fetched < r.cfg.BatchSize {
+			return
+		}
+	}
+}
+
+// relayBatch claims one batch of due events under the relay lock, then publishes them with the lock
+// released, so webhook deliveries and their retries never keep the lock's transaction open.
+// It returns how many events were claimed, false if another relay holds the lock,
+// and an error if the store failed; events that fail to publish are scheduled for a retry instead.
+func (r *Relay) relayBatch(ctx context.Context) (int, bool, error) {
+	var events []models.OutboxEvent
+	locked, err := r.store.WithRelayLock(ctx, func(ctx context.Context) error {
+		var err error
+		if events, err = r.store.FetchUnpublished(ctx, r.cfg.BatchSize); err != nil {
+			return err
+		}
+		ids := make([]int64, len(events))
+		for i, event := range events {
+			ids[i] = event.ID
+		}
+		return r.store.Postpone(ctx, ids, r.cfg.ClaimTimeout)
+	})
+	if err != nil || !locked {
+		return 0, locked, err
+	}
+
+	var published, held []int64
+	var errs []error
+	blocked := make(map[uuid.UUID]bool) // Polls with an earlier event that failed in this batch
+	for _, event := range events {
+		if blocked[event.PollID] {
+			held = append(held, event.ID)
+			continue
+		}
+		if err := r.publisher.Publish(ctx, event.PollEvent); err != nil {
+			blocked[event.PollID] = true
+			if err := r.recordFailure(ctx, event, err); err != nil {
+				errs = append(errs, err)
+			}
+			continue
+		}
+		published = append(published, event.ID)
+	}
+
+	if err := r.store.MarkPublished(ctx, published); err != nil {
+		errs = append(errs, err)
+	}
+	// Events held back behind a failure are released; the failed event's retry keeps them waiting
+	if err := r.store.Postpone(ctx, held, 0); err != nil {
+		errs = append(errs, err)
+	}
+
+	return len(events), true, errors.Join(errs...)
+}
+
+// recordFailure schedules the retry of an event that failed to publish,
+// or dead-letters it once it has failed MaxAttempts times
+func (r *Relay) recordFailure(ctx context.Context, event models.OutboxEvent, publishErr error) error {
+	attempts := event.Attempts + 1
+	fields := []zap.Field{
+		zap.Int64("event_id", event.ID),
+		zap.String("event_type", event.Type),
+		zap.String("poll_id", event.PollID.String()),
+		zap.Int("attempts", attempts),
+		zap.Error(publishErr),
+	}
+
+	if attempts >= r.cfg.MaxAttempts {
+		logger.Error("Outbox event dead-lettered after repeated publish failures", fields...)
+		return r.store.DeadLetter(ctx, event.ID)
+	}
+
+	retryIn := r.retryBackoff(attempts)
+	logger.Warn("Failed to publish outbox event, retrying later", append(fields, zap.Duration("retry_in", retryIn))...)
+	return r.store.RecordFailure(ctx, event.ID, retryIn)
+}
+
+// retryBackoff returns the delay before retrying an event that has failed attempts times
+func (r *Relay) retryBackoff(attempts int) time.Duration {
+	backoff := r.cfg.RetryBackoff
+	for i := 1; i < attempts && backoff < maxRetryBackoff; i++ {
+		backoff *= 2
+	}
+	return min(backoff, maxRetryBackoff)
+}
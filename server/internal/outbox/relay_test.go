@@ -0,0 +1,331 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/moabdelazem/k8s-app/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryStore is an in-memory outbox with a clock that tests move forward
+type memoryStore struct {
+	mu           sync.Mutex
+	events       []models.OutboxEvent
+	published    map[int64]bool
+	deadLettered map[int64]bool
+	attempts     map[int64]int
+	notBefore    map[int64]time.Time // Postponed events and when they are due again
+	now          time.Time
+	markErr      error
+	lockHeld     bool // Another relay holds the relay lock
+	inLock       bool // This relay is running under the relay lock
+}
+
+func newMemoryStore(events ...models.OutboxEvent) *memoryStore {
+	return &memoryStore{
+		events:       events,
+		published:    make(map[int64]bool),
+		deadLettered: make(map[int64]bool),
+		attempts:     make(map[int64]int),
+		notBefore:    make(map[int64]time.Time),
+		now:          time.Now(),
+	}
+}
+
+func (s *memoryStore) WithRelayLock(ctx context.Context, fn func(ctx context.Context) error) (bool, error) {
+	s.mu.Lock()
+	held := s.lockHeld
+	s.inLock = !held
+	s.mu.Unlock()
+
+	if held {
+		return false, nil
+	}
+	defer s.setInLock(false)
+	return true, fn(ctx)
+}
+
+func (s *memoryStore) setInLock(inLock bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.inLock = inLock
+}
+
+func (s *memoryStore) FetchUnpublished(_ context.Context, limit int) ([]models.OutboxEvent, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var events []models.OutboxEvent
+	held := make(map[uuid.UUID]bool) // Polls with an event that is not due yet
+	for _, event := range s.events {
+		if len(events) == limit {
+			break
+		}
+		if s.published[event.ID] || s.deadLettered[event.ID] {
+			continue
+		}
+		if s.notBefore[event.ID].After(s.now) {
+			held[event.PollID] = true
+		}
+		if !held[event.PollID] {
+			event.Attempts = s.attempts[event.ID]
+			events = append(events, event)
+		}
+	}
+	return events, nil
+}
+
+func (s *memoryStore) Postpone(_ context.Context, ids []int64, delay time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, id := range ids {
+		s.notBefore[id] = s.now.Add(delay)
+	}
+	return nil
+}
+
+func (s *memoryStore) MarkPublished(_ context.Context, ids []int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.markErr != nil {
+		return s.markErr
+	}
+	for _, id := range ids {
+		s.published[id] = true
+	}
+	return nil
+}
+
+func (s *memoryStore) RecordFailure(_ context.Context, id int64, retryIn time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.attempts[id]++
+	s.notBefore[id] = s.now.Add(retryIn)
+	return nil
+}
+
+func (s *memoryStore) DeadLetter(_ context.Context, id int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.attempts[id]++
+	s.deadLettered[id] = true
+	return nil
+}
+
+// advance moves the store's clock forward by d
+func (s *memoryStore) advance(d time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.now = s.now.Add(d)
+}
+
+func (s *memoryStore) unpublished() []int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var ids []int64
+	for _, event := range s.events {
+		if !s.published[event.ID] && !s.deadLettered[event.ID] {
+			ids = append(ids, event.ID)
+		}
+	}
+	return ids
+}
+
+// recordingPublisher records published event ids and fails those listed in fail
+type recordingPublisher struct {
+	mu     sync.Mutex
+	ids    []int64
+	fail   map[string]bool // Event types that fail to publish
+	store  *memoryStore    // Checked for the relay lock while publishing, if set
+	locked bool            // An event was published under the relay lock
+}
+
+func (p *recordingPublisher) Publish(_ context.Context, event models.PollEvent) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.store != nil {
+		p.store.mu.Lock()
+		p.locked = p.locked || p.store.inLock
+		p.store.mu.Unlock()
+	}
+
+	if p.fail[event.Type] {
+		return errors.New("publish failed")
+	}
+	p.ids = append(p.ids, event.Data.(int64))
+	return nil
+}
+
+func (p *recordingPublisher) published() []int64 {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]int64(nil), p.ids...)
+}
+
+// outboxEvent builds an event whose data is its id, so publishers can tell events apart
+func outboxEvent(id int64, pollID uuid.UUID, eventType string) models.OutboxEvent {
+	return models.OutboxEvent{
+		ID:        id,
+		PollEvent: models.PollEvent{Type: eventType, PollID: pollID, Data: id},
+	}
+}
+
+func TestRelayBatch_MarksPublishedEvents(t *testing.T) {
+	pollA, pollB := uuid.New(), uuid.New()
+	store := newMemoryStore(
+		outboxEvent(1, pollA, models.EventPollCreated),
+		outboxEvent(2, pollB, models.EventPollCreated),
+		outboxEvent(3, pollA, models.EventVoteCast),
+	)
+	publisher := &recordingPublisher{store: store}
+
+	fetched, locked, err := NewRelay(store, publisher, Config{}).relayBatch(context.Background())
+
+	require.NoError(t, err)
+	assert.True(t, locked)
+	assert.Equal(t, 3, fetched)
+	assert.Equal(t, []int64{1, 2, 3}, publisher.published())
+	assert.Empty(t, store.unpublished())
+	assert.False(t, publisher.locked, "events are published after the relay lock is released")
+}
+
+func TestRelayBatch_PreservesPerPollOrderOnFailure(t *testing.T) {
+	pollA, pollB := uuid.New(), uuid.New()
+	store := newMemoryStore(
+		outboxEvent(1, pollA, models.EventVoteCast),
+		outboxEvent(2, pollA, models.EventPollClosed),
+		outboxEvent(3, pollA, models.EventVoteCast),
+		outboxEvent(4, pollB, models.EventVoteCast),
+	)
+	publisher := &recordingPublisher{fail: map[string]bool{models.EventPollClosed: true}}
+	relay := NewRelay(store, publisher, Config{RetryBackoff: time.Minute})
+
+	_, _, err := relay.relayBatch(context.Background())
+
+	// Poll A's events after the failed close wait for it; poll B is unaffected
+	require.NoError(t, err, "publish failures are retried, not reported as relay errors")
+	assert.Equal(t, []int64{1, 4}, publisher.published())
+	assert.Equal(t, []int64{2, 3}, store.unpublished())
+	assert.Equal(t, 1, store.attempts[2])
+
+	// Poll A is left alone until the close is due for a retry
+	publisher.fail = nil
+	fetched, _, err := relay.relayBatch(context.Background())
+	require.NoError(t, err)
+	assert.Zero(t, fetched)
+
+	// Then it resumes in order
+	store.advance(time.Minute)
+	_, _, err = relay.relayBatch(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, []int64{1, 4, 2, 3}, publisher.published())
+	assert.Empty(t, store.unpublished())
+}
+
+func TestRelayBatch_DeadLettersAfterMaxAttempts(t *testing.T) {
+	pollA, pollB := uuid.New(), uuid.New()
+	store := newMemoryStore(
+		outboxEvent(1, pollA, models.EventPollClosed),
+		outboxEvent(2, pollA, models.EventVoteCast),
+		outboxEvent(3, pollB, models.EventVoteCast),
+	)
+	publisher := &recordingPublisher{fail: map[string]bool{models.EventPollClosed: true}}
+	relay := NewRelay(store, publisher, Config{MaxAttempts: 2, RetryBackoff: time.Minute})
+
+	_, _, err := relay.relayBatch(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []int64{3}, publisher.published())
+
+	// The second failure is the last; the poll's later events no longer wait for it
+	store.advance(time.Minute)
+	_, _, err = relay.relayBatch(context.Background())
+	require.NoError(t, err)
+	assert.True(t, store.deadLettered[1])
+	assert.Equal(t, 2, store.attempts[1])
+
+	_, _, err = relay.relayBatch(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []int64{3, 2}, publisher.published())
+	assert.Empty(t, store.unpublished())
+}
+
+func TestRelayBatch_RepublishesWhenMarkingFails(t *testing.T) {
+	store := newMemoryStore(outboxEvent(1, uuid.New(), models.EventVoteCast))
+	store.markErr = errors.New("connection reset")
+	publisher := &recordingPublisher{}
+	relay := NewRelay(store, publisher, Config{ClaimTimeout: time.Minute})
+
+	_, _, err := relay.relayBatch(context.Background())
+	assert.ErrorIs(t, err, store.markErr)
+
+	// The event stays claimed until the claim runs out
+	store.markErr = nil
+	fetched, _, err := relay.relayBatch(context.Background())
+	require.NoError(t, err)
+	assert.Zero(t, fetched)
+
+	// Delivery is at least once: the event is offered again
+	store.advance(time.Minute)
+	_, _, err = relay.relayBatch(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, []int64{1, 1}, publisher.published())
+	assert.Empty(t, store.unpublished())
+}
+
+func TestRetryBackoff_DoublesUpToTheCap(t *testing.T) {
+	relay := NewRelay(newMemoryStore(), &recordingPublisher{}, Config{RetryBackoff: 10 * time.Second})
+
+	assert.Equal(t, 10*time.Second, relay.retryBackoff(1))
+	assert.Equal(t, 20*time.Second, relay.retryBackoff(2))
+	assert.Equal(t, 80*time.Second, relay.retryBackoff(4))
+	assert.Equal(t, maxRetryBackoff, relay.retryBackoff(30))
+}
+
+func TestRelay_DrainsInBatches(t *testing.T) {
+	pollID := uuid.New()
+	var events []models.OutboxEvent
+	for id := int64(1); id <= 5; id++ {
+		events = append(events, outboxEvent(id, pollID, models.EventVoteCast))
+	}
+	store := newMemoryStore(events...)
+	publisher := &recordingPublisher{}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	NewRelay(store, publisher, Config{Interval: 10 * time.Millisecond, BatchSize: 2}).Start(ctx)
+
+	require.Eventually(t, func() bool {
+		return len(store.unpublished()) == 0
+	}, 2*time.Second, 10*time.Millisecond)
+	assert.Equal(t, []int64{1, 2, 3, 4, 5}, publisher.published())
+}
+
+func TestRelay_SkipsPassWhileAnotherRelayHoldsTheLock(t *testing.T) {
+	store := newMemoryStore(outboxEvent(1, uuid.New(), models.EventVoteCast))
+	store.lockHeld = true
+	publisher := &recordingPublisher{}
+	relay := NewRelay(store, publisher, Config{})
+
+	relay.drain(context.Background())
+	assert.Empty(t, publisher.published())
+
+	store.lockHeld = false
+	relay.drain(context.Background())
+	assert.Equal(t, []int64{1}, publisher.published())
+	assert.Empty(t, store.unpublished())
+}
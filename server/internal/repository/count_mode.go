@@ -0,0 +1,49 @@
+package repository
+
+import (
+	"fmt"
+	"strings"
+)
+
+// CountMode is how a PollRepository keeps option vote counts and poll totals
+type CountMode int
+
+const (
+	// IncrementedCounts adds each vote's weight to its option's vote_count as it is cast
+	IncrementedCounts CountMode = iota
+	// DerivedCounts leaves the vote tables as the single source of truth: votes are only ever
+	// inserted or deleted, and counts and totals are summed from them on every read.
+	// The vote_count columns are not kept up to date in this mode.
+	DerivedCounts
+)
+
+// optionVoteCount renders the vote count of the poll_options row aliased po
+// In DerivedCounts mode it sums the weights of the option's votes in votesTable
+func (r *PollRepository) optionVoteCount(votesTable string) string {
+	if r.countMode != DerivedCounts {
+		return "po.vote_count"
+	}
+	return fmt.Sprintf("(SELECT COALESCE(SUM(dv.weight), 0) FROM %s dv WHERE dv.option_id = po.id)", votesTable)
+}
+
+// selectPolls renders pollColumns for a SELECT list from the polls row aliased alias, or the polls table itself
+// In DerivedCounts mode total_votes is summed from the poll's votes and write-in votes in the given tables
+// The total_votes column is still kept by the vote triggers, as the max_votes check relies on it
+func (r *PollRepository) selectPolls(alias, votesTable, writeInsTable string) string {
+	columns := selectPollColumns(alias)
+	if r.countMode != DerivedCounts {
+		return columns
+	}
+	table := alias
+	if table == "" {
+		table = "polls"
+	}
+	total := fmt.Sprintf("(SELECT COALESCE(SUM(dv.weight), 0) FROM %[2]s dv WHERE dv.poll_id = %[1]s.id) + "+
+		"(SELECT COALESCE(SUM(dw.weight), 0) FROM %[3]s dw WHERE dw.poll_id = %[1]s.id)", table, votesTable, writeInsTable)
+
+	qualified := "total_votes"
+	if alias != "" {
+		qualified = alias + ".total_votes"
+	}
+	return strings.Replace(columns, qualified, total, 1)
+}
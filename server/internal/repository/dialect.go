@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/lib/pq"
+)
+
+// Dialect is the SQL flavour of the database behind a PollRepository or OutboxRepository
+// Queries are written for Postgres and rendered for the dialect before they run.
+// SQLite covers the core poll operations only: creating and reading polls, voting and the
+// list queries, and relaying the outbox; the rest of the repository still assumes Postgres.
+type Dialect int
+
+const (
+	PostgresDialect Dialect = iota
+	SQLiteDialect
+)
+
+var (
+	// placeholder matches a Postgres $N parameter
+	placeholder = regexp.MustCompile(`\$(\d+)`)
+	// typeCast matches a Postgres ::type cast such as ::uuid or ::text[]
+	typeCast = regexp.MustCompile(`::[a-z]+(\[\])?`)
+)
+
+// bind renders a query written for Postgres in the dialect
+// For SQLite, $N placeholders become ?N, which may also be repeated, and casts are dropped:
+// values are bound as text or integers and stored in columns of the matching affinity.
+func (d Dialect) bind(query string) string {
+	if d != SQLiteDialect {
+		return query
+	}
+	query = placeholder.ReplaceAllString(query, "?$1")
+	return typeCast.ReplaceAllString(query, "")
+}
+
+// inFuture renders a condition that the timestamp in column is later than the current time
+// SQLite keeps timestamps as text with a zone offset, so they are compared as Julian day numbers
+func (d Dialect) inFuture(column string) string {
+	if d == SQLiteDialect {
+		return "julianday(" + column + ") > julianday('now')"
+	}
+	return column + " > NOW()"
+}
+
+// fromNow renders the timestamp the number of seconds in param after the current time
+// SQLite timestamps are rendered in the text format its schema stores them in.
+func (d Dialect) fromNow(param string) string {
+	if d == SQLiteDialect {
+		return "strftime('%Y-%m-%d %H:%M:%f+00:00', 'now', printf('%+.3f seconds', " + param + "))"
+	}
+	return "NOW() + " + param + " * INTERVAL '1 second'"
+}
+
+// metadataMatches renders a condition that the JSON object in column has the string value in
+// valueParam under the key in keyParam. Postgres tests containment, which the GIN index on
+// polls.metadata serves; SQLite extracts the value, comparing it as text.
+func (d Dialect) metadataMatches(column, keyParam, valueParam string) string {
+	if d == SQLiteDialect {
+		path := "'$.\"' || " + keyParam + " || '\"'"
+		return fmt.Sprintf("(json_type(%[1]s, %[2]s) = 'text' AND json_extract(%[1]s, %[2]s) = %[3]s)", column, path, valueParam)
+	}
+	return fmt.Sprintf("%s @> jsonb_build_object(%s::text, %s::text)", column, keyParam, valueParam)
+}
+
+// idIn renders a condition that column is one of ids, with the arguments to bind from $1
+// Postgres binds the ids as one array; SQLite has no arrays, so each gets its own parameter.
+func (d Dialect) idIn(column string, ids []int64) (string, []any) {
+	if d != SQLiteDialect {
+		return column + " = ANY($1)", []any{pq.Array(ids)}
+	}
+	placeholders := make([]string, len(ids))
+	args := make([]any, len(ids))
+	for i, id := range ids {
+		placeholders[i] = fmt.Sprintf("$%d", i+1)
+		args[i] = id
+	}
+	return column + " IN (" + strings.Join(placeholders, ", ") + ")", args
+}
+
+// locksRows reports whether the dialect supports SELECT ... FOR UPDATE
+// SQLite has no row locks: a write transaction holds the whole database, which serialises votes anyway.
+func (d Dialect) locksRows() bool {
+	return d != SQLiteDialect
+}
@@ -0,0 +1,491 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/moabdelazem/k8s-app/internal/database"
+	"github.com/moabdelazem/k8s-app/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDialectBind(t *testing.T) {
+	query := `SELECT $1::uuid, $2::text[], $10 WHERE id = $1`
+
+	assert.Equal(t, query, PostgresDialect.bind(query))
+	assert.Equal(t, `SELECT ?1, ?2, ?10 WHERE id = ?1`, SQLiteDialect.bind(query))
+}
+
+func TestDialectIDIn(t *testing.T) {
+	condition, args := PostgresDialect.idIn("id", []int64{1, 2})
+	assert.Equal(t, "id = ANY($1)", condition)
+	assert.Len(t, args, 1)
+
+	condition, args = SQLiteDialect.idIn("id", []int64{1, 2})
+	assert.Equal(t, "id IN ($1, $2)", condition)
+	assert.Equal(t, []any{int64(1), int64(2)}, args)
+}
+
+// newSQLiteRepo returns a repository over a fresh in-memory SQLite database
+func newSQLiteRepo(t *testing.T) *PollRepository {
+	t.Helper()
+	db, err := database.OpenSQLite(context.Background(), ":memory:")
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	return NewPollRepository(db).WithDialect(SQLiteDialect)
+}
+
+// createSQLitePoll stores an active poll with options Yes and No
+func createSQLitePoll(t *testing.T, repo *PollRepository, poll *models.Poll) []models.PollOption {
+	t.Helper()
+	poll.IsActive = true
+	options := []models.PollOption{{OptionText: "Yes"}, {OptionText: "No"}}
+	require.NoError(t, repo.CreatePoll(context.Background(), poll, options))
+	return options
+}
+
+func TestSQLiteCreateAndGetPoll(t *testing.T) {
+	ctx := context.Background()
+	repo := newSQLiteRepo(t)
+	description := "Release planning"
+	ownerID := "owner-1"
+	expiresAt := time.Now().Add(time.Hour).Truncate(time.Microsecond)
+
+	poll := &models.Poll{Question: "Ship it on Friday?", Description: &description, ExpiresAt: &expiresAt, OwnerID: &ownerID}
+	options := createSQLitePoll(t, repo, poll)
+	assert.NotEqual(t, uuid.Nil, poll.ID)
+	assert.False(t, poll.CreatedAt.IsZero())
+	assert.NotEqual(t, uuid.Nil, options[1].ID)
+	assert.Equal(t, 1, options[1].Position)
+
+	got, err := repo.GetPollByID(ctx, poll.ID)
+	require.NoError(t, err)
+	require.NotNil(t, got)
+	assert.Equal(t, "Ship it on Friday?", got.Question)
+	assert.Equal(t, &description, got.Description)
+	require.NotNil(t, got.ExpiresAt)
+	assert.True(t, expiresAt.Equal(*got.ExpiresAt))
+	assert.True(t, got.IsActive)
+	assert.Equal(t, &ownerID, got.OwnerID)
+
+	gotOptions, err := repo.GetPollOptions(ctx, poll.ID)
+	require.NoError(t, err)
+	require.Len(t, gotOptions, 2)
+	assert.Equal(t, "Yes", gotOptions[0].OptionText)
+	assert.Equal(t, "No", gotOptions[1].OptionText)
+
+	missing, err := repo.GetPollByID(ctx, uuid.New())
+	require.NoError(t, err)
+	assert.Nil(t, missing)
+}
+
+func TestSQLiteCreatePoll_TooManyOptions(t *testing.T) {
+	repo := newSQLiteRepo(t)
+	options := make([]models.PollOption, 11)
+	for i := range options {
+		options[i].OptionText = "Option"
+	}
+
+	err := repo.CreatePoll(context.Background(), &models.Poll{Question: "Too many choices?", IsActive: true}, options)
+	assert.ErrorIs(t, err, ErrOptionCountOutOfBounds)
+}
+
+func TestSQLiteCastVote(t *testing.T) {
+	ctx := context.Background()
+	repo := newSQLiteRepo(t)
+	poll := &models.Poll{Question: "Ship it on Friday?"}
+	options := createSQLitePoll(t, repo, poll)
+
+	vote := &models.Vote{PollID: poll.ID, OptionID: options[0].ID, VoterIdentifier: "voter-1", Weight: 3}
+	require.NoError(t, repo.CastVote(ctx, vote))
+	assert.NotEqual(t, uuid.Nil, vote.ID)
+	assert.False(t, vote.VotedAt.IsZero())
+
+	got, err := repo.GetPollByID(ctx, poll.ID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), got.TotalVotes, "the vote trigger adds the weight to the poll")
+	gotOptions, err := repo.GetPollOptions(ctx, poll.ID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), gotOptions[0].VoteCount)
+
+	voted, optionID, err := repo.HasVoted(ctx, poll.ID, "voter-1")
+	require.NoError(t, err)
+	assert.True(t, voted)
+	assert.Equal(t, &options[0].ID, optionID)
+
+	err = repo.CastVote(ctx, &models.Vote{PollID: poll.ID, OptionID: options[1].ID, VoterIdentifier: "voter-1"})
+	assert.Error(t, err, "the unique constraint rejects a second vote")
+	got, err = repo.GetPollByID(ctx, poll.ID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), got.TotalVotes)
+}
+
+func TestSQLiteCastVote_PollFull(t *testing.T) {
+	ctx := context.Background()
+	repo := newSQLiteRepo(t)
+	maxVotes := int64(1)
+	poll := &models.Poll{Question: "Ship it on Friday?", MaxVotes: &maxVotes}
+	options := createSQLitePoll(t, repo, poll)
+
+	require.NoError(t, repo.CastVote(ctx, &models.Vote{PollID: poll.ID, OptionID: options[0].ID, VoterIdentifier: "voter-1"}))
+	err := repo.CastVote(ctx, &models.Vote{PollID: poll.ID, OptionID: options[0].ID, VoterIdentifier: "voter-2"})
+	assert.ErrorIs(t, err, ErrPollFull)
+}
+
+func TestSQLiteCastWriteInVote_AfterVote(t *testing.T) {
+	ctx := context.Background()
+	repo := newSQLiteRepo(t)
+	poll := &models.Poll{Question: "Ship it on Friday?", AllowWriteIn: true}
+	options := createSQLitePoll(t, repo, poll)
+	writeIn := "Thursday"
+
+	require.NoError(t, repo.CastVote(ctx, &models.Vote{PollID: poll.ID, OptionID: options[0].ID, VoterIdentifier: "voter-1"}))
+	err := repo.CastWriteInVote(ctx, &models.Vote{PollID: poll.ID, VoterIdentifier: "voter-1", WriteIn: &writeIn})
+	assert.ErrorIs(t, err, ErrAlreadyVoted)
+
+	require.NoError(t, repo.CastWriteInVote(ctx, &models.Vote{PollID: poll.ID, VoterIdentifier: "voter-2", WriteIn: &writeIn}))
+	voted, optionID, err := repo.HasVoted(ctx, poll.ID, "voter-2")
+	require.NoError(t, err)
+	assert.True(t, voted)
+	assert.Nil(t, optionID)
+}
+
+func TestSQLiteListPolls(t *testing.T) {
+	ctx := context.Background()
+	repo := newSQLiteRepo(t)
+	expired := time.Now().Add(-time.Hour)
+
+	first := &models.Poll{Question: "First poll here?"}
+	second := &models.Poll{Question: "Second poll here?", ExpiresAt: &expired}
+	third := &models.Poll{Question: "Third poll here?"}
+	for _, poll := range []*models.Poll{first, second, third} {
+		createSQLitePoll(t, repo, poll)
+		// SQLite timestamps have millisecond precision
+		time.Sleep(2 * time.Millisecond)
+	}
+
+	polls, err := repo.ListPolls(ctx, 10, 0, false)
+	require.NoError(t, err)
+	require.Len(t, polls, 3)
+	assert.Equal(t, third.ID, polls[0].ID, "newest first")
+	assert.Equal(t, first.ID, polls[2].ID)
+
+	active, err := repo.ListPolls(ctx, 10, 0, true)
+	require.NoError(t, err)
+	require.Len(t, active, 2, "the expired poll is not active")
+	assert.Equal(t, third.ID, active[0].ID)
+	assert.Equal(t, first.ID, active[1].ID)
+
+	page, err := repo.ListPolls(ctx, 1, 1, false)
+	require.NoError(t, err)
+	require.Len(t, page, 1)
+	assert.Equal(t, second.ID, page[0].ID)
+
+	withOptions, err := repo.ListPollsWithOptions(ctx, 2, 0, true)
+	require.NoError(t, err)
+	require.Len(t, withOptions, 2)
+	require.Len(t, withOptions[1].Options, 2)
+	assert.Equal(t, "No", withOptions[1].Options[1].OptionText)
+
+	total, err := repo.GetTotalPollsCount(ctx, false)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), total)
+	activeTotal, err := repo.GetTotalPollsCount(ctx, true)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), activeTotal)
+}
+
+func TestSQLiteListFeaturedPolls_Ordering(t *testing.T) {
+	ctx := context.Background()
+	repo := newSQLiteRepo(t)
+	expiry := time.Now().Add(-time.Hour)
+	first, second := 1, 2
+
+	unrankedOld := &models.Poll{Question: "Unranked old poll?"}
+	rankedSecond := &models.Poll{Question: "Ranked second poll?"}
+	rankedFirst := &models.Poll{Question: "Ranked first poll?"}
+	unrankedNew := &models.Poll{Question: "Unranked new poll?"}
+	expired := &models.Poll{Question: "Expired poll here?", ExpiresAt: &expiry}
+	unfeatured := &models.Poll{Question: "Unfeatured poll here?"}
+	for _, poll := range []*models.Poll{unrankedOld, rankedSecond, rankedFirst, unrankedNew, expired, unfeatured} {
+		createSQLitePoll(t, repo, poll)
+		// SQLite timestamps have millisecond precision
+		time.Sleep(2 * time.Millisecond)
+	}
+	require.NoError(t, repo.SetFeatured(ctx, unrankedOld.ID, true, nil))
+	require.NoError(t, repo.SetFeatured(ctx, rankedSecond.ID, true, &second))
+	require.NoError(t, repo.SetFeatured(ctx, rankedFirst.ID, true, &first))
+	require.NoError(t, repo.SetFeatured(ctx, unrankedNew.ID, true, nil))
+	require.NoError(t, repo.SetFeatured(ctx, expired.ID, true, &first))
+	require.NoError(t, repo.SetFeatured(ctx, unfeatured.ID, true, &first))
+	require.NoError(t, repo.SetFeatured(ctx, unfeatured.ID, false, nil))
+
+	featured, err := repo.ListFeaturedPolls(ctx, 10)
+	require.NoError(t, err)
+	var ids []uuid.UUID
+	for _, poll := range featured {
+		ids = append(ids, poll.ID)
+	}
+	assert.Equal(t, []uuid.UUID{rankedFirst.ID, rankedSecond.ID, unrankedNew.ID, unrankedOld.ID}, ids,
+		"ranked polls first by rank, then unranked newest first; expired and unfeatured polls are left out")
+	require.Len(t, featured[0].Options, 2)
+	require.NotNil(t, featured[0].FeaturedRank)
+	assert.Equal(t, first, *featured[0].FeaturedRank)
+
+	limited, err := repo.ListFeaturedPolls(ctx, 2)
+	require.NoError(t, err)
+	require.Len(t, limited, 2)
+	assert.Equal(t, rankedSecond.ID, limited[1].ID)
+
+	assert.ErrorIs(t, repo.SetFeatured(ctx, uuid.New(), true, nil), sql.ErrNoRows)
+}
+
+func TestSQLitePollMetadata(t *testing.T) {
+	ctx := context.Background()
+	repo := newSQLiteRepo(t)
+
+	older := &models.Poll{Question: "Older campaign poll?", Metadata: json.RawMessage(`{"campaign":"spring","rank":1}`)}
+	newer := &models.Poll{Question: "Newer campaign poll?", Metadata: json.RawMessage(`{"campaign":"spring"}`)}
+	numeric := &models.Poll{Question: "Numeric campaign poll?", Metadata: json.RawMessage(`{"campaign":1}`)}
+	nested := &models.Poll{Question: "Nested campaign poll?", Metadata: json.RawMessage(`{"ui":{"campaign":"spring"}}`)}
+	plain := &models.Poll{Question: "Poll without metadata?"}
+	deleted := &models.Poll{Question: "Deleted campaign poll?", Metadata: json.RawMessage(`{"campaign":"spring"}`)}
+	for _, poll := range []*models.Poll{older, newer, numeric, nested, plain, deleted} {
+		createSQLitePoll(t, repo, poll)
+		// SQLite timestamps have millisecond precision
+		time.Sleep(2 * time.Millisecond)
+	}
+	_, err := repo.db.ExecContext(ctx, `UPDATE polls SET deleted_at = CURRENT_TIMESTAMP WHERE id = ?1`, deleted.ID)
+	require.NoError(t, err)
+
+	stored, err := repo.GetPollByID(ctx, older.ID)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"campaign":"spring","rank":1}`, string(stored.Metadata))
+	stored, err = repo.GetPollByID(ctx, plain.ID)
+	require.NoError(t, err)
+	assert.Nil(t, stored.Metadata)
+
+	matches, err := repo.ListPollsByMetadata(ctx, "campaign", "spring", 10, 0)
+	require.NoError(t, err)
+	var ids []uuid.UUID
+	for _, poll := range matches {
+		ids = append(ids, poll.ID)
+	}
+	assert.Equal(t, []uuid.UUID{newer.ID, older.ID}, ids,
+		"only top-level string values match; deleted polls are left out")
+	assert.Len(t, matches[0].Options, 2)
+
+	matches, err = repo.ListPollsByMetadata(ctx, "campaign", "spring", 1, 1)
+	require.NoError(t, err)
+	require.Len(t, matches, 1)
+	assert.Equal(t, older.ID, matches[0].ID)
+
+	require.NoError(t, repo.SetMetadata(ctx, newer.ID, nil))
+	stored, err = repo.GetPollByID(ctx, newer.ID)
+	require.NoError(t, err)
+	assert.Nil(t, stored.Metadata)
+
+	assert.ErrorIs(t, repo.SetMetadata(ctx, deleted.ID, json.RawMessage(`{}`)), sql.ErrNoRows)
+}
+
+func TestSQLiteCastVote_Milestone(t *testing.T) {
+	ctx := context.Background()
+	repo := newSQLiteRepo(t).WithMilestones([]int64{1000, 100})
+	poll := &models.Poll{Question: "Ship it on Friday?", AllowWeighted: true}
+	options := createSQLitePoll(t, repo, poll)
+
+	milestoneEvents := func() []string {
+		t.Helper()
+		rows, err := repo.db.QueryContext(ctx, `SELECT data FROM outbox_events WHERE event_type = ? ORDER BY id`, models.EventMilestone)
+		require.NoError(t, err)
+		defer rows.Close()
+		var events []string
+		for rows.Next() {
+			var data string
+			require.NoError(t, rows.Scan(&data))
+			events = append(events, data)
+		}
+		require.NoError(t, rows.Err())
+		return events
+	}
+
+	for i, weight := range []int64{98, 1} {
+		vote := &models.Vote{PollID: poll.ID, OptionID: options[0].ID, VoterIdentifier: fmt.Sprintf("voter-%d", i), Weight: weight}
+		require.NoError(t, repo.CastVote(ctx, vote))
+	}
+	assert.Empty(t, milestoneEvents(), "99 votes reach no milestone")
+
+	require.NoError(t, repo.CastVote(ctx, &models.Vote{PollID: poll.ID, OptionID: options[1].ID, VoterIdentifier: "voter-100", Weight: 1}))
+	assert.Equal(t, []string{`{"milestone":100,"total_votes":100}`}, milestoneEvents())
+
+	require.NoError(t, repo.CastVote(ctx, &models.Vote{PollID: poll.ID, OptionID: options[1].ID, VoterIdentifier: "voter-101", Weight: 1}))
+	assert.Len(t, milestoneEvents(), 1, "a milestone is announced once")
+}
+
+func TestSQLiteListVotesByVoter(t *testing.T) {
+	ctx := context.Background()
+	repo := newSQLiteRepo(t)
+	first := &models.Poll{Question: "First poll here?", AllowWriteIn: true}
+	second := &models.Poll{Question: "Second poll here?"}
+	createSQLitePoll(t, repo, first)
+	options := createSQLitePoll(t, repo, second)
+	writeIn := "Thursday"
+
+	require.NoError(t, repo.CastWriteInVote(ctx, &models.Vote{PollID: first.ID, VoterIdentifier: "voter-1", Weight: 1, WriteIn: &writeIn}))
+	// SQLite timestamps have millisecond precision
+	time.Sleep(2 * time.Millisecond)
+	require.NoError(t, repo.CastVote(ctx, &models.Vote{PollID: second.ID, OptionID: options[1].ID, VoterIdentifier: "voter-1", Weight: 1}))
+	require.NoError(t, repo.CastVote(ctx, &models.Vote{PollID: second.ID, OptionID: options[0].ID, VoterIdentifier: "voter-2", Weight: 1}))
+
+	votes, err := repo.ListVotesByVoter(ctx, "voter-1", 10, 0)
+	require.NoError(t, err)
+	require.Len(t, votes, 2)
+	assert.Equal(t, second.ID, votes[0].PollID, "newest first")
+	assert.Equal(t, &options[1].ID, votes[0].OptionID)
+	require.NotNil(t, votes[0].OptionText)
+	assert.Equal(t, "No", *votes[0].OptionText)
+	assert.Nil(t, votes[0].WriteIn)
+	assert.Equal(t, first.ID, votes[1].PollID)
+	assert.Equal(t, "First poll here?", votes[1].Question)
+	assert.Nil(t, votes[1].OptionID)
+	assert.Equal(t, &writeIn, votes[1].WriteIn)
+
+	page, err := repo.ListVotesByVoter(ctx, "voter-1", 1, 1)
+	require.NoError(t, err)
+	require.Len(t, page, 1)
+	assert.Equal(t, first.ID, page[0].PollID)
+}
+
+func TestSQLiteDerivedCounts_MatchIncremented(t *testing.T) {
+	ctx := context.Background()
+	incremented := newSQLiteRepo(t)
+	derived := newSQLiteRepo(t).WithCountMode(DerivedCounts)
+	writeIn := "Thursday"
+
+	// Casts the same votes on a new poll of repo, returning the poll and its results
+	castVotes := func(repo *PollRepository) (*models.Poll, *models.PollWithVote) {
+		poll := &models.Poll{Question: "Ship it on Friday?", AllowWriteIn: true}
+		options := createSQLitePoll(t, repo, poll)
+		require.NoError(t, repo.CastVote(ctx, &models.Vote{PollID: poll.ID, OptionID: options[0].ID, VoterIdentifier: "voter-1", Weight: 3}))
+		require.NoError(t, repo.CastVote(ctx, &models.Vote{PollID: poll.ID, OptionID: options[0].ID, VoterIdentifier: "voter-2"}))
+		require.NoError(t, repo.CastVote(ctx, &models.Vote{PollID: poll.ID, OptionID: options[1].ID, VoterIdentifier: "voter-3", Weight: 2}))
+		require.NoError(t, repo.CastWriteInVote(ctx, &models.Vote{PollID: poll.ID, VoterIdentifier: "voter-4", WriteIn: &writeIn}))
+
+		results, err := repo.GetPollWithResults(ctx, poll.ID, "voter-1")
+		require.NoError(t, err)
+		require.NotNil(t, results)
+		return poll, results
+	}
+
+	incrementedPoll, want := castVotes(incremented)
+	derivedPoll, got := castVotes(derived)
+
+	assert.Equal(t, int64(7), want.TotalVotes)
+	assert.Equal(t, want.TotalVotes, got.TotalVotes)
+	require.Len(t, got.Options, 2)
+	for i := range want.Options {
+		assert.Equal(t, want.Options[i].VoteCount, got.Options[i].VoteCount, "option %d", i)
+	}
+	assert.Equal(t, int64(4), got.Options[0].VoteCount)
+
+	wantOptions, err := incremented.GetPollOptions(ctx, incrementedPoll.ID)
+	require.NoError(t, err)
+	gotOptions, err := derived.GetPollOptions(ctx, derivedPoll.ID)
+	require.NoError(t, err)
+	assert.Equal(t, wantOptions[0].VoteCount, gotOptions[0].VoteCount)
+	assert.Equal(t, wantOptions[1].VoteCount, gotOptions[1].VoteCount)
+
+	polls, err := derived.ListPolls(ctx, 10, 0, false)
+	require.NoError(t, err)
+	require.Len(t, polls, 1)
+	assert.Equal(t, want.TotalVotes, polls[0].TotalVotes)
+
+	var stored int64
+	require.NoError(t, derived.db.QueryRowContext(ctx, `SELECT SUM(vote_count) FROM poll_options`).Scan(&stored))
+	assert.Zero(t, stored, "votes are only appended, never counted on write")
+}
+
+func TestSQLiteOutboxRelay(t *testing.T) {
+	ctx := context.Background()
+	repo := newSQLiteRepo(t)
+	outbox := NewOutboxRepository(repo.db).WithDialect(SQLiteDialect)
+
+	first, second := &models.Poll{Question: "Ship it on Friday?"}, &models.Poll{Question: "Ship it on Monday?"}
+	createSQLitePoll(t, repo, first)
+	createSQLitePoll(t, repo, second)
+
+	events, err := outbox.FetchUnpublished(ctx, 10)
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+	assert.Equal(t, first.ID, events[0].PollID)
+	assert.Equal(t, models.EventPollCreated, events[0].Type)
+
+	require.NoError(t, outbox.MarkPublished(ctx, []int64{events[0].ID}))
+
+	events, err = outbox.FetchUnpublished(ctx, 10)
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, second.ID, events[0].PollID)
+
+	// A SQLite database belongs to one process, so the relay lock is always free
+	locked, err := outbox.WithRelayLock(ctx, func(ctx context.Context) error {
+		return outbox.MarkPublished(ctx, []int64{events[0].ID})
+	})
+	require.NoError(t, err)
+	assert.True(t, locked)
+
+	events, err = outbox.FetchUnpublished(ctx, 10)
+	require.NoError(t, err)
+	assert.Empty(t, events)
+}
+
+func TestSQLiteOutboxRetries(t *testing.T) {
+	ctx := context.Background()
+	repo := newSQLiteRepo(t)
+	outbox := NewOutboxRepository(repo.db).WithDialect(SQLiteDialect)
+
+	first, second := &models.Poll{Question: "Ship it on Friday?"}, &models.Poll{Question: "Ship it on Monday?"}
+	options := createSQLitePoll(t, repo, first)
+	createSQLitePoll(t, repo, second)
+	require.NoError(t, repo.CastVote(ctx, &models.Vote{PollID: first.ID, OptionID: options[0].ID, VoterIdentifier: "voter-1"}))
+
+	events, err := outbox.FetchUnpublished(ctx, 10)
+	require.NoError(t, err)
+	require.Len(t, events, 3)
+	created, voted := events[0], events[2]
+	require.Equal(t, first.ID, voted.PollID)
+
+	// A failed event holds back its poll's later events until it is due; other polls carry on
+	require.NoError(t, outbox.RecordFailure(ctx, created.ID, time.Hour))
+	events, err = outbox.FetchUnpublished(ctx, 10)
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, second.ID, events[0].PollID)
+
+	// A claimed event is withheld the same way, and released with a zero delay
+	require.NoError(t, outbox.Postpone(ctx, []int64{events[0].ID}, time.Hour))
+	events, err = outbox.FetchUnpublished(ctx, 10)
+	require.NoError(t, err)
+	assert.Empty(t, events)
+
+	require.NoError(t, outbox.Postpone(ctx, []int64{created.ID}, 0))
+	events, err = outbox.FetchUnpublished(ctx, 10)
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+	assert.Equal(t, created.ID, events[0].ID)
+	assert.Equal(t, 1, events[0].Attempts)
+
+	// A dead-lettered event is never offered again, and no longer holds back its poll
+	require.NoError(t, outbox.RecordFailure(ctx, created.ID, time.Hour))
+	require.NoError(t, outbox.DeadLetter(ctx, created.ID))
+	events, err = outbox.FetchUnpublished(ctx, 10)
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, voted.ID, events[0].ID)
+}
@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/lib/pq"
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+// ErrOptionCountOutOfBounds is returned when the database rejects a poll whose
+// option count is outside the bounds enforced by the poll_options_count triggers
+var ErrOptionCountOutOfBounds = errors.New("poll option count out of bounds")
+
+// ErrAlreadyVoted is returned when a voter votes twice on a poll in a way no unique constraint
+// catches: for an option after a write-in or the other way around, or at all in the in-memory repository
+var ErrAlreadyVoted = errors.New("voter has already voted on this poll")
+
+// ErrPollHasVotes is returned when a change is only allowed before a poll receives its first vote
+var ErrPollHasVotes = errors.New("poll already has votes")
+
+// ErrPollFull is returned when a vote would take a poll's total votes past its max_votes
+var ErrPollFull = errors.New("poll is full")
+
+// ErrRestoreTargetNotEmpty is returned when restoring a backup into a database that already holds polls
+var ErrRestoreTargetNotEmpty = errors.New("database already holds polls")
+
+// optionCountConstraint names the triggers guarding the number of options per poll
+const optionCountConstraint = "poll_options_count"
+
+// pollCapacityConstraint names the check keeping a poll's total votes within its max_votes
+const pollCapacityConstraint = "poll_within_capacity"
+
+// mapOptionCountError returns ErrOptionCountOutOfBounds, wrapping err, when err is an option count
+// violation, and nil otherwise
+func mapOptionCountError(err error) error {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "23514" && pqErr.Constraint == optionCountConstraint {
+		return fmt.Errorf("%w: %w", ErrOptionCountOutOfBounds, err)
+	}
+	if isSQLiteConstraint(err, optionCountConstraint) {
+		return fmt.Errorf("%w: %w", ErrOptionCountOutOfBounds, err)
+	}
+	return nil
+}
+
+// mapCapacityError returns ErrPollFull, wrapping err, when err is a capacity violation, and nil otherwise
+func mapCapacityError(err error) error {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "23514" && pqErr.Constraint == pollCapacityConstraint {
+		return fmt.Errorf("%w: %w", ErrPollFull, err)
+	}
+	if isSQLiteConstraint(err, pollCapacityConstraint) {
+		return fmt.Errorf("%w: %w", ErrPollFull, err)
+	}
+	return nil
+}
+
+// isSQLiteConstraint reports whether err is a SQLite check or trigger failure naming constraint
+// SQLite has no constraint field on its errors; the name is only part of the message.
+func isSQLiteConstraint(err error, constraint string) bool {
+	var sqliteErr *sqlite.Error
+	if !errors.As(err, &sqliteErr) {
+		return false
+	}
+	switch sqliteErr.Code() {
+	case sqlite3.SQLITE_CONSTRAINT_CHECK, sqlite3.SQLITE_CONSTRAINT_TRIGGER:
+		return strings.Contains(sqliteErr.Error(), constraint)
+	}
+	return false
+}
@@ -0,0 +1,36 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMapOptionCountError(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		mapped bool
+	}{
+		{name: "option count violation", err: &pq.Error{Code: "23514", Constraint: "poll_options_count"}, mapped: true},
+		{name: "wrapped violation", err: fmt.Errorf("commit: %w", &pq.Error{Code: "23514", Constraint: "poll_options_count"}), mapped: true},
+		{name: "other check constraint", err: &pq.Error{Code: "23514", Constraint: "poll_options_option_text_check"}},
+		{name: "unique violation", err: &pq.Error{Code: "23505", Constraint: "poll_options_count"}},
+		{name: "non-postgres error", err: errors.New("boom")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := mapOptionCountError(tt.err)
+			if !tt.mapped {
+				assert.Nil(t, got)
+				return
+			}
+			assert.ErrorIs(t, got, ErrOptionCountOutOfBounds)
+			assert.ErrorIs(t, got, tt.err)
+		})
+	}
+}
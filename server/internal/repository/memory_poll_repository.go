@@ -0,0 +1,1467 @@
+package repository
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/moabdelazem/k8s-app/internal/models"
+)
+
+// Option count bounds enforced by the poll_options_count triggers
+const (
+	minPollOptions = 2
+	maxPollOptions = 10
+)
+
+// InMemoryPollRepository keeps polls and votes in process memory, for demos and tests
+// that should run without Postgres. It follows the semantics of PollRepository, including
+// the database triggers, but writes no outbox events and loses everything on restart.
+type InMemoryPollRepository struct {
+	now   func() time.Time
+	order ListOrder
+
+	mu            sync.RWMutex
+	polls         map[uuid.UUID]*memoryPoll
+	votes         map[uuid.UUID]map[string]models.Vote // Poll ID -> voter identifier -> vote; write-ins have no option
+	archivedPolls map[uuid.UUID]*memoryPoll
+	archivedVotes map[uuid.UUID]map[string]models.Vote
+	allowedVoters map[uuid.UUID]map[string]time.Time // Poll ID -> voter identifier -> when added
+}
+
+// memoryPoll is a stored poll with its options, kept in position order
+type memoryPoll struct {
+	poll     models.Poll
+	options  []models.PollOption
+	closedAt *time.Time // When the poll was deleted or closed on expiry
+}
+
+// optionBounds returns the option count bounds of a poll; acknowledgement polls have exactly one
+func optionBounds(acknowledgement bool) (int, int) {
+	if acknowledgement {
+		return 1, 1
+	}
+	return minPollOptions, maxPollOptions
+}
+
+func NewInMemoryPollRepository() *InMemoryPollRepository {
+	return &InMemoryPollRepository{
+		now:           time.Now,
+		polls:         make(map[uuid.UUID]*memoryPoll),
+		votes:         make(map[uuid.UUID]map[string]models.Vote),
+		archivedPolls: make(map[uuid.UUID]*memoryPoll),
+		archivedVotes: make(map[uuid.UUID]map[string]models.Vote),
+		allowedVoters: make(map[uuid.UUID]map[string]time.Time),
+	}
+}
+
+// WithListOrder sets the order polls are listed in, newest first by default
+func (r *InMemoryPollRepository) WithListOrder(order ListOrder) *InMemoryPollRepository {
+	r.order = order
+	return r
+}
+
+// CreatePoll stores a new poll with options
+func (r *InMemoryPollRepository) CreatePoll(ctx context.Context, poll *models.Poll, options []models.PollOption) error {
+	if minCount, maxCount := optionBounds(poll.AcknowledgementMode); len(options) < minCount || len(options) > maxCount {
+		return fmt.Errorf("%w: poll has %d options", ErrOptionCountOutOfBounds, len(options))
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := r.now()
+	poll.ID = uuid.New()
+	poll.CreatedAt = now
+	poll.TotalVotes = 0
+
+	for i := range options {
+		options[i].ID = uuid.New()
+		options[i].PollID = poll.ID
+		options[i].Position = i
+		options[i].VoteCount = 0
+		options[i].CreatedAt = now
+	}
+
+	// Keep the owner, which is never read back, and detach the caller's pointers
+	stored := &memoryPoll{poll: *poll, options: slices.Clone(options)}
+	stored.poll = stored.publicPoll()
+	stored.poll.OwnerID = poll.OwnerID
+	r.polls[poll.ID] = stored
+
+	return nil
+}
+
+// GetPollByID retrieves a poll by ID
+func (r *InMemoryPollRepository) GetPollByID(ctx context.Context, id uuid.UUID) (*models.Poll, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	stored, ok := r.polls[id]
+	if !ok {
+		return nil, nil
+	}
+	poll := stored.publicPoll()
+	return &poll, nil
+}
+
+// GetPollOptions retrieves all options for a poll
+func (r *InMemoryPollRepository) GetPollOptions(ctx context.Context, pollID uuid.UUID) ([]models.PollOption, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	stored, ok := r.polls[pollID]
+	if !ok {
+		return nil, nil
+	}
+	return slices.Clone(stored.options), nil
+}
+
+// GetOptionsForPolls retrieves the options of several polls, grouped by poll ID
+// Each poll's options are in position order; unknown polls are absent from the map
+func (r *InMemoryPollRepository) GetOptionsForPolls(ctx context.Context, pollIDs []uuid.UUID) (map[uuid.UUID][]models.PollOption, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	options := make(map[uuid.UUID][]models.PollOption, len(pollIDs))
+	for _, id := range pollIDs {
+		if stored, ok := r.polls[id]; ok && len(stored.options) > 0 {
+			options[id] = slices.Clone(stored.options)
+		}
+	}
+	return options, nil
+}
+
+// ListPolls retrieves polls with pagination
+func (r *InMemoryPollRepository) ListPolls(ctx context.Context, limit, offset int, activeOnly bool) ([]models.Poll, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var polls []models.Poll
+	for _, stored := range r.page(limit, offset, activeOnly) {
+		polls = append(polls, stored.publicPoll())
+	}
+	return polls, nil
+}
+
+// ListPollsWithOptions retrieves polls with their options
+func (r *InMemoryPollRepository) ListPollsWithOptions(ctx context.Context, limit, offset int, activeOnly bool) ([]models.PollWithOptions, error) {
+	var result []models.PollWithOptions
+	err := r.StreamPollsWithOptions(ctx, limit, offset, activeOnly, func(poll models.PollWithOptions) error {
+		result = append(result, poll)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// StreamPollsWithOptions passes each poll of a page to fn; the page is copied first,
+// so fn runs without holding the lock
+func (r *InMemoryPollRepository) StreamPollsWithOptions(ctx context.Context, limit, offset int, activeOnly bool, fn func(models.PollWithOptions) error) error {
+	r.mu.RLock()
+	page := make([]models.PollWithOptions, 0, limit)
+	for _, stored := range r.page(limit, offset, activeOnly) {
+		page = append(page, stored.listedPoll())
+	}
+	r.mu.RUnlock()
+
+	for _, poll := range page {
+		if err := fn(poll); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetPollsByIDs retrieves the polls with the given IDs and their options
+// Polls are returned in the order of ids; duplicate IDs are returned once and missing ones are omitted
+func (r *InMemoryPollRepository) GetPollsByIDs(ctx context.Context, ids []uuid.UUID) ([]models.PollWithOptions, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	result := []models.PollWithOptions{}
+	seen := make(map[uuid.UUID]bool, len(ids))
+	for _, id := range ids {
+		stored, ok := r.polls[id]
+		if !ok || seen[id] {
+			continue
+		}
+		seen[id] = true
+		result = append(result, stored.listedPoll())
+	}
+	return result, nil
+}
+
+// CastVote records a vote for an option
+// Returns ErrAlreadyVoted when the voter has already voted on the poll,
+// and ErrPollFull when the vote would take the poll past its max_votes
+func (r *InMemoryPollRepository) CastVote(ctx context.Context, vote *models.Vote) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if vote.Weight == 0 {
+		vote.Weight = 1
+	}
+
+	stored, option, err := r.findOption(vote.PollID, vote.OptionID)
+	if err != nil {
+		return fmt.Errorf("failed to cast vote: %w", err)
+	}
+	if _, voted := r.votes[vote.PollID][vote.VoterIdentifier]; voted {
+		return fmt.Errorf("failed to cast vote: %w", ErrAlreadyVoted)
+	}
+	if !stored.hasCapacity(vote.Weight) {
+		return ErrPollFull
+	}
+
+	vote.ID = uuid.New()
+	vote.VotedAt = r.now()
+	r.addVote(stored, option, *vote)
+
+	return nil
+}
+
+// CastWriteInVote records a write-in vote, whose free-text answer is in vote.WriteIn
+// Returns ErrAlreadyVoted when the voter has already voted on the poll,
+// and ErrPollFull when the vote would take the poll past its max_votes
+func (r *InMemoryPollRepository) CastWriteInVote(ctx context.Context, vote *models.Vote) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if vote.Weight == 0 {
+		vote.Weight = 1
+	}
+
+	stored, ok := r.polls[vote.PollID]
+	if !ok {
+		return fmt.Errorf("failed to cast write-in vote: poll %s does not exist", vote.PollID)
+	}
+	if _, voted := r.votes[vote.PollID][vote.VoterIdentifier]; voted {
+		return fmt.Errorf("failed to cast write-in vote: %w", ErrAlreadyVoted)
+	}
+	if !stored.hasCapacity(vote.Weight) {
+		return ErrPollFull
+	}
+
+	vote.ID = uuid.New()
+	vote.OptionID = uuid.Nil
+	vote.VotedAt = r.now()
+	r.addWriteInVote(stored, *vote)
+
+	return nil
+}
+
+// HasVoted checks if a voter has already voted on a poll
+// The option is nil for a write-in vote
+func (r *InMemoryPollRepository) HasVoted(ctx context.Context, pollID uuid.UUID, voterIdentifier string) (bool, *uuid.UUID, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	vote, ok := r.votes[pollID][voterIdentifier]
+	if !ok {
+		return false, nil, nil
+	}
+	if vote.WriteIn != nil {
+		return true, nil, nil
+	}
+	optionID := vote.OptionID
+	return true, &optionID, nil
+}
+
+// GetPollWithResults reads a poll, its options and a voter's vote
+// An empty voterIdentifier skips the vote lookup. Returns nil if the poll does not exist.
+func (r *InMemoryPollRepository) GetPollWithResults(ctx context.Context, pollID uuid.UUID, voterIdentifier string) (*models.PollWithVote, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return pollWithResults(r.polls, r.votes, pollID, voterIdentifier), nil
+}
+
+// GetArchivedPollWithResults is GetPollWithResults for an archived poll
+// Returns nil if the poll is not archived.
+func (r *InMemoryPollRepository) GetArchivedPollWithResults(ctx context.Context, pollID uuid.UUID, voterIdentifier string) (*models.PollWithVote, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return pollWithResults(r.archivedPolls, r.archivedVotes, pollID, voterIdentifier), nil
+}
+
+// pollWithResults reads a poll with its options and a voter's vote from polls and votes
+func pollWithResults(polls map[uuid.UUID]*memoryPoll, votes map[uuid.UUID]map[string]models.Vote, pollID uuid.UUID, voterIdentifier string) *models.PollWithVote {
+	stored, ok := polls[pollID]
+	if !ok {
+		return nil
+	}
+
+	result := &models.PollWithVote{
+		PollWithOptions: models.PollWithOptions{
+			Poll:    stored.publicPoll(),
+			Options: slices.Clone(stored.options),
+		},
+	}
+	if vote, ok := votes[pollID][voterIdentifier]; ok && voterIdentifier != "" {
+		if vote.WriteIn != nil {
+			result.VotedWriteIn = true
+			return result
+		}
+		optionID := vote.OptionID
+		result.VotedOption = &optionID
+	}
+	return result
+}
+
+// ListWriteIns tallies a poll's write-in votes, live or archived, returning at most limit tallies
+// Grouped tallies merge answers differing only in case under the first spelling used, most votes
+// first; otherwise each vote is its own tally, newest first.
+func (r *InMemoryPollRepository) ListWriteIns(ctx context.Context, pollID uuid.UUID, grouped bool, limit int) ([]models.WriteInTally, error) {
+	r.mu.RLock()
+	var writeIns []models.Vote
+	for _, votes := range []map[string]models.Vote{r.votes[pollID], r.archivedVotes[pollID]} {
+		for _, vote := range votes {
+			if vote.WriteIn != nil {
+				writeIns = append(writeIns, vote)
+			}
+		}
+	}
+	r.mu.RUnlock()
+
+	slices.SortFunc(writeIns, func(a, b models.Vote) int {
+		if c := a.VotedAt.Compare(b.VotedAt); c != 0 {
+			return c
+		}
+		return bytes.Compare(a.ID[:], b.ID[:])
+	})
+
+	tallies := []models.WriteInTally{}
+	if !grouped {
+		for _, vote := range slices.Backward(writeIns) {
+			tallies = append(tallies, models.WriteInTally{Text: *vote.WriteIn, VoteCount: vote.Weight})
+		}
+		return tallies[:min(limit, len(tallies))], nil
+	}
+
+	// Ties keep the order in which the answers were first given
+	index := make(map[string]int)
+	for _, vote := range writeIns {
+		key := strings.ToLower(*vote.WriteIn)
+		i, ok := index[key]
+		if !ok {
+			i = len(tallies)
+			index[key] = i
+			tallies = append(tallies, models.WriteInTally{Text: *vote.WriteIn})
+		}
+		tallies[i].VoteCount += vote.Weight
+	}
+	slices.SortStableFunc(tallies, func(a, b models.WriteInTally) int {
+		return int(b.VoteCount - a.VoteCount)
+	})
+	return tallies[:min(limit, len(tallies))], nil
+}
+
+// HasVotedInGroup checks if a voter has already voted on any poll in a group
+// Group names are compared case-insensitively
+func (r *InMemoryPollRepository) HasVotedInGroup(ctx context.Context, group string, voterIdentifier string) (bool, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for pollID, stored := range r.polls {
+		if stored.poll.Group == nil || !strings.EqualFold(*stored.poll.Group, group) {
+			continue
+		}
+		if _, ok := r.votes[pollID][voterIdentifier]; ok {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// ListVotesByVoter returns a page of the votes, including write-ins, cast by voterIdentifier
+// across all polls, newest first. Votes on deleted or archived polls are left out.
+func (r *InMemoryPollRepository) ListVotesByVoter(ctx context.Context, voterIdentifier string, limit, offset int) ([]models.VoterVote, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	votes := []models.VoterVote{}
+	for pollID, stored := range r.polls {
+		vote, ok := r.votes[pollID][voterIdentifier]
+		if !ok || stored.poll.DeletedAt != nil {
+			continue
+		}
+		voterVote := models.VoterVote{
+			PollID:   pollID,
+			Question: stored.poll.Question,
+			Weight:   vote.Weight,
+			VotedAt:  vote.VotedAt,
+		}
+		if vote.WriteIn != nil {
+			writeIn := *vote.WriteIn
+			voterVote.WriteIn = &writeIn
+		} else {
+			optionID := vote.OptionID
+			voterVote.OptionID = &optionID
+			for _, opt := range stored.options {
+				if opt.ID == optionID {
+					optionText := opt.OptionText
+					voterVote.OptionText = &optionText
+				}
+			}
+		}
+		votes = append(votes, voterVote)
+	}
+
+	slices.SortFunc(votes, func(a, b models.VoterVote) int {
+		if c := b.VotedAt.Compare(a.VotedAt); c != 0 {
+			return c
+		}
+		return strings.Compare(a.PollID.String(), b.PollID.String())
+	})
+	start := min(offset, len(votes))
+	return votes[start:min(start+limit, len(votes))], nil
+}
+
+// RemoveVote deletes a voter's vote, or write-in vote, and takes its weight back off the option and poll counts
+// Returns sql.ErrNoRows when the voter has not voted on the poll
+func (r *InMemoryPollRepository) RemoveVote(ctx context.Context, pollID uuid.UUID, voterIdentifier string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	vote, ok := r.votes[pollID][voterIdentifier]
+	if !ok {
+		return sql.ErrNoRows
+	}
+	delete(r.votes[pollID], voterIdentifier)
+
+	stored := r.polls[pollID]
+	stored.poll.TotalVotes -= vote.Weight
+	for i := range stored.options {
+		if stored.options[i].ID == vote.OptionID {
+			stored.options[i].VoteCount -= vote.Weight
+		}
+	}
+
+	return nil
+}
+
+// UpdateOptionTexts sets the text of each given option of a poll, as long as the poll has no votes
+// Returns sql.ErrNoRows when the poll does not exist and ErrPollHasVotes once it has votes
+func (r *InMemoryPollRepository) UpdateOptionTexts(ctx context.Context, pollID uuid.UUID, options []models.PollOption) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.polls[pollID]
+	if !ok {
+		return sql.ErrNoRows
+	}
+	if stored.poll.TotalVotes > 0 {
+		return ErrPollHasVotes
+	}
+
+	for _, opt := range options {
+		for i := range stored.options {
+			if stored.options[i].ID == opt.ID {
+				stored.options[i].OptionText = opt.OptionText
+			}
+		}
+	}
+	return nil
+}
+
+// DeletePoll soft deletes a poll
+// Returns sql.ErrNoRows when the poll does not exist
+func (r *InMemoryPollRepository) DeletePoll(ctx context.Context, id uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.polls[id]
+	if !ok {
+		return sql.ErrNoRows
+	}
+	stored.poll.IsActive = false
+	now := r.now()
+	if stored.closedAt == nil {
+		stored.closedAt = &now
+	}
+	if stored.poll.DeletedAt == nil {
+		stored.poll.DeletedAt = &now
+	}
+	return nil
+}
+
+// DeletePolls soft deletes every poll matching filter, as DeletePoll does
+// Polls already deleted are skipped. Returns the IDs of the deleted polls; an empty filter deletes nothing.
+func (r *InMemoryPollRepository) DeletePolls(ctx context.Context, filter models.PollFilter) ([]uuid.UUID, error) {
+	if filter.IDs == nil && filter.OwnerID == nil && filter.CreatedBefore == nil {
+		return nil, nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := r.now()
+	var ids []uuid.UUID
+	for id, stored := range r.polls {
+		poll := &stored.poll
+		if poll.DeletedAt != nil ||
+			(filter.IDs != nil && !slices.Contains(filter.IDs, id)) ||
+			(filter.OwnerID != nil && (poll.OwnerID == nil || *poll.OwnerID != *filter.OwnerID)) ||
+			(filter.CreatedBefore != nil && !poll.CreatedAt.Before(*filter.CreatedBefore)) {
+			continue
+		}
+		poll.IsActive = false
+		if stored.closedAt == nil {
+			stored.closedAt = &now
+		}
+		poll.DeletedAt = &now
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// GetTotalPollsCount returns the total number of polls
+func (r *InMemoryPollRepository) GetTotalPollsCount(ctx context.Context, activeOnly bool) (int64, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	now := r.now()
+	var count int64
+	for _, stored := range r.polls {
+		if !activeOnly || stored.openAt(now) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// CountActivePollsByOwner returns the number of active, unexpired polls created by an owner
+func (r *InMemoryPollRepository) CountActivePollsByOwner(ctx context.Context, ownerID string) (int64, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	now := r.now()
+	var count int64
+	for _, stored := range r.polls {
+		if stored.poll.OwnerID != nil && *stored.poll.OwnerID == ownerID && stored.openAt(now) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// ListActiveByOwnerExcluding retrieves up to limit active, unexpired polls created by an owner
+// with their options, leaving out excludePollID; polls are in listing order
+func (r *InMemoryPollRepository) ListActiveByOwnerExcluding(ctx context.Context, ownerID string, excludePollID uuid.UUID, limit int) ([]models.PollWithOptions, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	result := []models.PollWithOptions{}
+	for _, stored := range r.page(len(r.polls), 0, true) {
+		if len(result) == limit {
+			break
+		}
+		if stored.poll.ID != excludePollID && stored.poll.OwnerID != nil && *stored.poll.OwnerID == ownerID {
+			result = append(result, stored.listedPoll())
+		}
+	}
+	return result, nil
+}
+
+// SetFeatured pins a poll to the featured listing at rank, or removes it when featured is false
+// Returns sql.ErrNoRows when the poll does not exist or was deleted
+func (r *InMemoryPollRepository) SetFeatured(ctx context.Context, id uuid.UUID, featured bool, rank *int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.polls[id]
+	if !ok || stored.poll.DeletedAt != nil {
+		return sql.ErrNoRows
+	}
+	stored.poll.Featured = featured
+	stored.poll.FeaturedRank = nil
+	if featured && rank != nil {
+		stored.poll.FeaturedRank = new(int)
+		*stored.poll.FeaturedRank = *rank
+	}
+	return nil
+}
+
+// ListFeaturedPolls retrieves up to limit active, unexpired featured polls with their options
+// Polls are ordered by rank, lowest first, then unranked polls; polls of equal rank are listed newest first
+func (r *InMemoryPollRepository) ListFeaturedPolls(ctx context.Context, limit int) ([]models.PollWithOptions, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	now := r.now()
+	var featured []*memoryPoll
+	for _, stored := range r.polls {
+		if stored.poll.Featured && stored.openAt(now) {
+			featured = append(featured, stored)
+		}
+	}
+	slices.SortFunc(featured, func(a, b *memoryPoll) int {
+		rankA, rankB := a.poll.FeaturedRank, b.poll.FeaturedRank
+		switch {
+		case rankA == nil && rankB != nil:
+			return 1
+		case rankA != nil && rankB == nil:
+			return -1
+		case rankA != nil && *rankA != *rankB:
+			return cmp.Compare(*rankA, *rankB)
+		}
+		if c := b.poll.CreatedAt.Compare(a.poll.CreatedAt); c != 0 {
+			return c
+		}
+		return bytes.Compare(b.poll.ID[:], a.poll.ID[:])
+	})
+
+	result := []models.PollWithOptions{}
+	for _, stored := range featured {
+		if len(result) == limit {
+			break
+		}
+		result = append(result, stored.listedPoll())
+	}
+	return result, nil
+}
+
+// SetMetadata replaces a poll's metadata; empty metadata removes it
+// Returns sql.ErrNoRows when the poll does not exist or was deleted
+func (r *InMemoryPollRepository) SetMetadata(ctx context.Context, id uuid.UUID, metadata json.RawMessage) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.polls[id]
+	if !ok || stored.poll.DeletedAt != nil {
+		return sql.ErrNoRows
+	}
+	stored.poll.Metadata = nil
+	if len(metadata) > 0 {
+		stored.poll.Metadata = slices.Clone(metadata)
+	}
+	return nil
+}
+
+// ListPollsByMetadata retrieves a page of the polls whose metadata has the string value under key,
+// with their options, in listing order. Deleted polls are left out.
+func (r *InMemoryPollRepository) ListPollsByMetadata(ctx context.Context, key, value string, limit, offset int) ([]models.PollWithOptions, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	matching := r.sorted(func(p *memoryPoll) bool {
+		return p.poll.DeletedAt == nil && metadataMatches(p.poll.Metadata, key, value)
+	})
+	result := []models.PollWithOptions{}
+	for _, stored := range paginate(matching, limit, offset) {
+		result = append(result, stored.listedPoll())
+	}
+	return result, nil
+}
+
+// metadataMatches reports whether the JSON object metadata has the string value under key
+func metadataMatches(metadata json.RawMessage, key, value string) bool {
+	var object map[string]any
+	if json.Unmarshal(metadata, &object) != nil {
+		return false
+	}
+	got, ok := object[key].(string)
+	return ok && got == value
+}
+
+// ExpireNow sets a poll's expiry to the current time, leaving it active until DeactivateExpired runs
+// Returns the new expiry, or sql.ErrNoRows when the poll does not exist
+func (r *InMemoryPollRepository) ExpireNow(ctx context.Context, id uuid.UUID) (time.Time, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.polls[id]
+	if !ok {
+		return time.Time{}, sql.ErrNoRows
+	}
+	expiresAt := r.now()
+	stored.poll.ExpiresAt = &expiresAt
+	return expiresAt, nil
+}
+
+// DeactivateExpired marks all active polls whose expiry has passed as inactive
+// Returns the IDs of the polls closed; safe to call repeatedly
+func (r *InMemoryPollRepository) DeactivateExpired(ctx context.Context) ([]uuid.UUID, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := r.now()
+	var ids []uuid.UUID
+	for id, stored := range r.polls {
+		if stored.poll.IsActive && stored.poll.ExpiresAt != nil && !stored.poll.ExpiresAt.After(now) {
+			stored.poll.IsActive = false
+			stored.closedAt = &now
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
+
+// GetVoteTimeline counts votes per option, grouped into buckets of the given width
+// Buckets are aligned to the Unix epoch and returned oldest first; empty buckets are omitted
+func (r *InMemoryPollRepository) GetVoteTimeline(ctx context.Context, pollID uuid.UUID, bucket time.Duration) ([]models.TimelineBucket, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	stored, ok := r.polls[pollID]
+	if !ok {
+		return nil, nil
+	}
+
+	width := int64(bucket.Seconds())
+	if width <= 0 {
+		return nil, fmt.Errorf("failed to get vote timeline: bucket must be at least one second")
+	}
+
+	type key struct {
+		start    int64
+		optionID uuid.UUID
+	}
+	counts := make(map[key]int64)
+	for _, vote := range r.votes[pollID] {
+		if vote.WriteIn != nil {
+			continue // Write-ins have no option to count, as in the SQL join
+		}
+		start := vote.VotedAt.Unix()
+		start -= ((start % width) + width) % width
+		counts[key{start, vote.OptionID}]++
+	}
+
+	starts := make([]int64, 0, len(counts))
+	for k := range counts {
+		starts = append(starts, k.start)
+	}
+	slices.Sort(starts)
+	starts = slices.Compact(starts)
+
+	var buckets []models.TimelineBucket
+	for _, start := range starts {
+		b := models.TimelineBucket{Start: time.Unix(start, 0).UTC()}
+		for _, opt := range stored.options {
+			if votes, ok := counts[key{start, opt.ID}]; ok {
+				b.Options = append(b.Options, models.OptionVoteCount{OptionID: opt.ID, Votes: votes})
+			}
+		}
+		buckets = append(buckets, b)
+	}
+	return buckets, nil
+}
+
+// ipv4Prefix matches an IPv4 address, optionally with a port as in a peer address, capturing its /24 prefix
+var ipv4Prefix = regexp.MustCompile(`^\s*([0-9]{1,3}\.[0-9]{1,3}\.[0-9]{1,3})\.[0-9]{1,3}(?::[0-9]+)?\s*$`)
+
+// subnetPrefix returns the /24 prefix of the IPv4 address a voter identifier names, if it names one.
+// Identifiers recorded from a peer address keep its port, and those recorded from X-Forwarded-For
+// the whole list, whose first entry is the client; both are reduced to the client's address.
+func subnetPrefix(voter string) (string, bool) {
+	first, _, _ := strings.Cut(voter, ",")
+	match := ipv4Prefix.FindStringSubmatch(first)
+	if match == nil {
+		return "", false
+	}
+	return match[1], true
+}
+
+// ListSubnetClusters counts a poll's votes, write-ins included, per IPv4 /24 subnet of the voter identifier
+// and returns the subnets with at least minVotes, most votes first, each listing up to maxVoters voters
+// as they were recorded
+func (r *InMemoryPollRepository) ListSubnetClusters(ctx context.Context, pollID uuid.UUID, minVotes int64, maxVoters int) ([]models.SubnetCluster, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	voters := make(map[string][]string) // Subnet -> voter identifiers
+	for voter := range r.votes[pollID] {
+		if prefix, ok := subnetPrefix(voter); ok {
+			subnet := prefix + ".0/24"
+			voters[subnet] = append(voters[subnet], voter)
+		}
+	}
+
+	clusters := []models.SubnetCluster{}
+	for subnet, inSubnet := range voters {
+		if int64(len(inSubnet)) < minVotes {
+			continue
+		}
+		slices.Sort(inSubnet)
+		clusters = append(clusters, models.SubnetCluster{
+			Subnet: subnet,
+			Votes:  int64(len(inSubnet)),
+			Voters: inSubnet[:min(len(inSubnet), maxVoters)],
+		})
+	}
+	slices.SortFunc(clusters, func(a, b models.SubnetCluster) int {
+		if a.Votes != b.Votes {
+			return cmp.Compare(b.Votes, a.Votes)
+		}
+		return strings.Compare(a.Subnet, b.Subnet)
+	})
+	return clusters, nil
+}
+
+// ListVoteBursts counts a poll's votes, write-ins included, in windows of the given width aligned to the Unix epoch
+// and returns the windows with at least minVotes, oldest first, each listing up to maxVoters voters
+func (r *InMemoryPollRepository) ListVoteBursts(ctx context.Context, pollID uuid.UUID, window time.Duration, minVotes int64, maxVoters int) ([]models.VoteBurst, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	width := int64(window.Seconds())
+	if width <= 0 {
+		return nil, fmt.Errorf("failed to list vote bursts: window must be at least one second")
+	}
+
+	votes := make(map[int64][]models.Vote) // Window start -> votes cast in it
+	for _, vote := range r.votes[pollID] {
+		start := vote.VotedAt.Unix()
+		start -= ((start % width) + width) % width
+		votes[start] = append(votes[start], vote)
+	}
+
+	bursts := []models.VoteBurst{}
+	for _, start := range slices.Sorted(maps.Keys(votes)) {
+		inWindow := votes[start]
+		if int64(len(inWindow)) < minVotes {
+			continue
+		}
+		slices.SortFunc(inWindow, func(a, b models.Vote) int {
+			if c := a.VotedAt.Compare(b.VotedAt); c != 0 {
+				return c
+			}
+			return strings.Compare(a.VoterIdentifier, b.VoterIdentifier)
+		})
+		burst := models.VoteBurst{
+			Start: time.Unix(start, 0).UTC(),
+			End:   time.Unix(start, 0).UTC().Add(window),
+			Votes: int64(len(inWindow)),
+		}
+		for _, vote := range inWindow[:min(len(inWindow), maxVoters)] {
+			burst.Voters = append(burst.Voters, vote.VoterIdentifier)
+		}
+		bursts = append(bursts, burst)
+	}
+	return bursts, nil
+}
+
+// GetPeakVoteRate counts a poll's votes, write-ins included, in buckets of the given width aligned to the Unix epoch
+// and returns the bucket with the most votes, the earliest on ties; nil when the poll has no votes
+func (r *InMemoryPollRepository) GetPeakVoteRate(ctx context.Context, pollID uuid.UUID, bucket time.Duration) (*models.VoteRatePeak, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	width := int64(bucket.Seconds())
+	if width <= 0 {
+		return nil, fmt.Errorf("failed to get peak vote rate: bucket must be at least one second")
+	}
+
+	counts := make(map[int64]int64) // Bucket start -> votes cast in it
+	for _, vote := range r.votes[pollID] {
+		start := vote.VotedAt.Unix()
+		start -= ((start % width) + width) % width
+		counts[start]++
+	}
+
+	var peak *models.VoteRatePeak
+	for _, start := range slices.Sorted(maps.Keys(counts)) {
+		if peak == nil || counts[start] > peak.Votes {
+			peak = &models.VoteRatePeak{
+				Start: time.Unix(start, 0).UTC(),
+				End:   time.Unix(start, 0).UTC().Add(bucket),
+				Votes: counts[start],
+			}
+		}
+	}
+	return peak, nil
+}
+
+// ImportVotes stores the votes produced by next, until next returns io.EOF
+// Votes from voters who already voted on the poll are skipped; any other error, including
+// ErrPollFull when the votes would take a poll past its max_votes, leaves the votes unchanged
+func (r *InMemoryPollRepository) ImportVotes(ctx context.Context, next func() (*models.Vote, error)) (imported, skipped int64, err error) {
+	// Read everything first so a failing source cannot leave a partial import behind
+	var votes []*models.Vote
+	for {
+		vote, err := next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return 0, 0, err
+		}
+		votes = append(votes, vote)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, vote := range votes {
+		if _, _, err := r.findOption(vote.PollID, vote.OptionID); err != nil {
+			return 0, 0, fmt.Errorf("failed to import vote: %w", err)
+		}
+	}
+
+	seen := make(map[uuid.UUID]map[string]bool)
+	added := make(map[uuid.UUID]int64) // Poll ID -> weight of the votes to import
+	var accepted []*models.Vote
+	for _, vote := range votes {
+		_, voted := r.votes[vote.PollID][vote.VoterIdentifier]
+		if voted || seen[vote.PollID][vote.VoterIdentifier] {
+			skipped++
+			continue
+		}
+		if seen[vote.PollID] == nil {
+			seen[vote.PollID] = make(map[string]bool)
+		}
+		seen[vote.PollID][vote.VoterIdentifier] = true
+
+		if vote.Weight == 0 {
+			vote.Weight = 1
+		}
+		added[vote.PollID] += vote.Weight
+		accepted = append(accepted, vote)
+	}
+
+	for pollID, weight := range added {
+		if !r.polls[pollID].hasCapacity(weight) {
+			return 0, 0, ErrPollFull
+		}
+	}
+
+	for _, vote := range accepted {
+		vote.ID = uuid.New()
+		stored, option, _ := r.findOption(vote.PollID, vote.OptionID)
+		r.addVote(stored, option, *vote)
+		imported++
+	}
+
+	return imported, skipped, nil
+}
+
+// ArchiveClosedPolls moves up to limit polls closed before closedBefore, with their votes, to the archive
+// Polls closed without a recorded time count from their expiry or creation. Returns the IDs of the polls moved.
+func (r *InMemoryPollRepository) ArchiveClosedPolls(ctx context.Context, closedBefore time.Time, limit int) ([]uuid.UUID, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var ids []uuid.UUID
+	for id, stored := range r.polls {
+		if len(ids) == limit {
+			break
+		}
+		if !stored.poll.IsActive && !stored.closedSince().After(closedBefore) {
+			ids = append(ids, id)
+		}
+	}
+
+	for _, id := range ids {
+		r.archivedPolls[id] = r.polls[id]
+		if votes, ok := r.votes[id]; ok {
+			r.archivedVotes[id] = votes
+		}
+		delete(r.polls, id)
+		delete(r.votes, id)
+		delete(r.allowedVoters, id)
+	}
+	return ids, nil
+}
+
+// AddAllowedVoters adds voters to a poll's allowlist, skipping those already on it
+// Returns the number of voters added
+func (r *InMemoryPollRepository) AddAllowedVoters(ctx context.Context, pollID uuid.UUID, voterIdentifiers []string) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.polls[pollID]; !ok {
+		return 0, fmt.Errorf("poll %s does not exist", pollID)
+	}
+	if r.allowedVoters[pollID] == nil {
+		r.allowedVoters[pollID] = make(map[string]time.Time)
+	}
+
+	now := r.now()
+	var added int64
+	for _, voter := range voterIdentifiers {
+		if _, ok := r.allowedVoters[pollID][voter]; ok {
+			continue
+		}
+		r.allowedVoters[pollID][voter] = now
+		added++
+	}
+	return added, nil
+}
+
+// RemoveAllowedVoter removes a voter from a poll's allowlist, reporting whether they were on it
+func (r *InMemoryPollRepository) RemoveAllowedVoter(ctx context.Context, pollID uuid.UUID, voterIdentifier string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.allowedVoters[pollID][voterIdentifier]; !ok {
+		return false, nil
+	}
+	delete(r.allowedVoters[pollID], voterIdentifier)
+	return true, nil
+}
+
+// ListAllowedVoters retrieves a poll's allowlist in the order voters were added
+func (r *InMemoryPollRepository) ListAllowedVoters(ctx context.Context, pollID uuid.UUID) ([]models.AllowedVoter, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	voters := []models.AllowedVoter{}
+	for voter, addedAt := range r.allowedVoters[pollID] {
+		voters = append(voters, models.AllowedVoter{VoterIdentifier: voter, AddedAt: addedAt})
+	}
+	slices.SortFunc(voters, func(a, b models.AllowedVoter) int {
+		if c := a.AddedAt.Compare(b.AddedAt); c != 0 {
+			return c
+		}
+		return strings.Compare(a.VoterIdentifier, b.VoterIdentifier)
+	})
+	return voters, nil
+}
+
+// IsVoterAllowed checks if a voter is on a poll's allowlist
+func (r *InMemoryPollRepository) IsVoterAllowed(ctx context.Context, pollID uuid.UUID, voterIdentifier string) (bool, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	_, ok := r.allowedVoters[pollID][voterIdentifier]
+	return ok, nil
+}
+
+// ExportAll passes every poll, then every option, vote, write-in vote and allowed voter to fn, in bundle order
+// The records are copied first, so fn runs without holding the lock
+func (r *InMemoryPollRepository) ExportAll(ctx context.Context, fn func(models.BackupRecord) error) error {
+	r.mu.RLock()
+	polls := make([]*memoryPoll, 0, len(r.polls))
+	for _, stored := range r.polls {
+		polls = append(polls, stored)
+	}
+	slices.SortFunc(polls, func(a, b *memoryPoll) int {
+		if c := a.poll.CreatedAt.Compare(b.poll.CreatedAt); c != 0 {
+			return c
+		}
+		return bytes.Compare(a.poll.ID[:], b.poll.ID[:])
+	})
+
+	var pollRecords, optionRecords, voteRecords, writeInRecords, allowedVoterRecords []models.BackupRecord
+	for _, stored := range polls {
+		pollRecords = append(pollRecords, models.BackupRecord{Type: models.BackupRecordPoll, Poll: stored.backupPoll()})
+		for _, opt := range stored.options {
+			optionRecords = append(optionRecords, models.BackupRecord{Type: models.BackupRecordOption, Option: &models.BackupOption{
+				ID:         opt.ID,
+				PollID:     opt.PollID,
+				OptionText: opt.OptionText,
+				Position:   opt.Position,
+				IsCorrect:  opt.IsCorrect,
+				CreatedAt:  opt.CreatedAt,
+			}})
+		}
+
+		voters := slices.Sorted(maps.Keys(r.allowedVoters[stored.poll.ID]))
+		for _, voter := range voters {
+			allowedVoterRecords = append(allowedVoterRecords, models.BackupRecord{Type: models.BackupRecordAllowedVoter, AllowedVoter: &models.BackupAllowedVoter{
+				PollID:          stored.poll.ID,
+				VoterIdentifier: voter,
+				AddedAt:         r.allowedVoters[stored.poll.ID][voter],
+			}})
+		}
+
+		for _, vote := range r.votes[stored.poll.ID] {
+			if vote.WriteIn != nil {
+				writeInRecords = append(writeInRecords, models.BackupRecord{Type: models.BackupRecordWriteInVote, WriteInVote: &models.BackupWriteInVote{
+					ID:              vote.ID,
+					PollID:          vote.PollID,
+					VoterIdentifier: vote.VoterIdentifier,
+					WriteIn:         *vote.WriteIn,
+					Weight:          vote.Weight,
+					Campaign:        vote.Campaign,
+					VotedAt:         vote.VotedAt,
+				}})
+				continue
+			}
+			voteRecords = append(voteRecords, models.BackupRecord{Type: models.BackupRecordVote, Vote: &models.BackupVote{
+				ID:              vote.ID,
+				PollID:          vote.PollID,
+				OptionID:        vote.OptionID,
+				VoterIdentifier: vote.VoterIdentifier,
+				Weight:          vote.Weight,
+				Campaign:        vote.Campaign,
+				VotedAt:         vote.VotedAt,
+			}})
+		}
+	}
+	r.mu.RUnlock()
+
+	slices.SortFunc(voteRecords, func(a, b models.BackupRecord) int {
+		if c := a.Vote.VotedAt.Compare(b.Vote.VotedAt); c != 0 {
+			return c
+		}
+		return bytes.Compare(a.Vote.ID[:], b.Vote.ID[:])
+	})
+	slices.SortFunc(writeInRecords, func(a, b models.BackupRecord) int {
+		if c := a.WriteInVote.VotedAt.Compare(b.WriteInVote.VotedAt); c != 0 {
+			return c
+		}
+		return bytes.Compare(a.WriteInVote.ID[:], b.WriteInVote.ID[:])
+	})
+
+	for _, records := range [][]models.BackupRecord{pollRecords, optionRecords, voteRecords, writeInRecords, allowedVoterRecords} {
+		for _, record := range records {
+			if err := fn(record); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// ImportAll restores the records produced by next, until it returns io.EOF, into an empty repository
+// Stored IDs are kept and vote counts are recounted from the votes. Any error leaves the repository unchanged.
+// Returns ErrRestoreTargetNotEmpty if the repository, including the archive, already holds polls.
+func (r *InMemoryPollRepository) ImportAll(ctx context.Context, next func() (*models.BackupRecord, error)) (*models.BackupSummary, error) {
+	// Read everything first so a failing source cannot leave a partial restore behind
+	var records []*models.BackupRecord
+	for {
+		record, err := next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, record)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if len(r.polls) > 0 || len(r.archivedPolls) > 0 {
+		return nil, ErrRestoreTargetNotEmpty
+	}
+
+	// Build the restored state aside, then swap it in once it is complete
+	restored := &InMemoryPollRepository{
+		now:           r.now,
+		polls:         make(map[uuid.UUID]*memoryPoll),
+		votes:         make(map[uuid.UUID]map[string]models.Vote),
+		allowedVoters: make(map[uuid.UUID]map[string]time.Time),
+	}
+	summary := &models.BackupSummary{}
+	for _, record := range records {
+		switch {
+		case record.Poll != nil:
+			p := record.Poll
+			if _, ok := restored.polls[p.ID]; ok {
+				return nil, fmt.Errorf("failed to restore poll: poll %s already exists", p.ID)
+			}
+			restored.polls[p.ID] = &memoryPoll{
+				poll: models.Poll{
+					ID:                  p.ID,
+					Question:            p.Question,
+					Description:         p.Description,
+					CreatedAt:           p.CreatedAt,
+					ExpiresAt:           p.ExpiresAt,
+					IsActive:            p.IsActive,
+					OwnerID:             p.OwnerID,
+					AllowWeighted:       p.AllowWeighted,
+					RequireConfirmation: p.RequireConfirmation,
+					QuizMode:            p.QuizMode,
+					Group:               p.Group,
+					RandomizeOptions:    p.RandomizeOptions,
+					AllowlistOnly:       p.AllowlistOnly,
+					AllowWriteIn:        p.AllowWriteIn,
+					MaxVotes:            p.MaxVotes,
+					VotingWindowStart:   p.VotingWindowStart,
+					VotingWindowEnd:     p.VotingWindowEnd,
+					Timezone:            p.Timezone,
+					AcknowledgementMode: p.AcknowledgementMode,
+					Featured:            p.Featured,
+					FeaturedRank:        p.FeaturedRank,
+					Metadata:            slices.Clone(p.Metadata),
+					DeletedAt:           p.DeletedAt,
+				},
+				closedAt: p.ClosedAt,
+			}
+			summary.Polls++
+		case record.Option != nil:
+			o := record.Option
+			stored, ok := restored.polls[o.PollID]
+			if !ok {
+				return nil, fmt.Errorf("failed to restore option: poll %s does not exist", o.PollID)
+			}
+			if len(stored.options) == maxPollOptions {
+				return nil, fmt.Errorf("%w: poll %s has more than %d options", ErrOptionCountOutOfBounds, o.PollID, maxPollOptions)
+			}
+			stored.options = append(stored.options, models.PollOption{
+				ID:         o.ID,
+				PollID:     o.PollID,
+				OptionText: o.OptionText,
+				Position:   o.Position,
+				IsCorrect:  o.IsCorrect,
+				CreatedAt:  o.CreatedAt,
+			})
+			summary.Options++
+		case record.Vote != nil:
+			v := record.Vote
+			// Options are complete once votes start, so they can be put in position order
+			if summary.Votes == 0 {
+				restored.sortOptions()
+			}
+			stored, option, err := restored.findOption(v.PollID, v.OptionID)
+			if err != nil {
+				return nil, fmt.Errorf("failed to restore vote: %w", err)
+			}
+			if _, voted := restored.votes[v.PollID][v.VoterIdentifier]; voted {
+				return nil, fmt.Errorf("failed to restore vote: %w", ErrAlreadyVoted)
+			}
+			restored.addVote(stored, option, models.Vote{
+				ID:              v.ID,
+				PollID:          v.PollID,
+				OptionID:        v.OptionID,
+				VoterIdentifier: v.VoterIdentifier,
+				Weight:          v.Weight,
+				Campaign:        v.Campaign,
+				VotedAt:         v.VotedAt,
+			})
+			summary.Votes++
+		case record.WriteInVote != nil:
+			w := record.WriteInVote
+			stored, ok := restored.polls[w.PollID]
+			if !ok {
+				return nil, fmt.Errorf("failed to restore write-in vote: poll %s does not exist", w.PollID)
+			}
+			if _, voted := restored.votes[w.PollID][w.VoterIdentifier]; voted {
+				return nil, fmt.Errorf("failed to restore write-in vote: %w", ErrAlreadyVoted)
+			}
+			writeIn := w.WriteIn
+			restored.addWriteInVote(stored, models.Vote{
+				ID:              w.ID,
+				PollID:          w.PollID,
+				VoterIdentifier: w.VoterIdentifier,
+				Weight:          w.Weight,
+				Campaign:        w.Campaign,
+				WriteIn:         &writeIn,
+				VotedAt:         w.VotedAt,
+			})
+			summary.WriteInVotes++
+		case record.AllowedVoter != nil:
+			a := record.AllowedVoter
+			if _, ok := restored.polls[a.PollID]; !ok {
+				return nil, fmt.Errorf("failed to restore allowed voter: poll %s does not exist", a.PollID)
+			}
+			if restored.allowedVoters[a.PollID] == nil {
+				restored.allowedVoters[a.PollID] = make(map[string]time.Time)
+			}
+			restored.allowedVoters[a.PollID][a.VoterIdentifier] = a.AddedAt
+			summary.AllowedVoters++
+		}
+	}
+
+	restored.sortOptions()
+	for id, stored := range restored.polls {
+		if minCount, maxCount := optionBounds(stored.poll.AcknowledgementMode); len(stored.options) < minCount || len(stored.options) > maxCount {
+			return nil, fmt.Errorf("%w: poll %s has %d options", ErrOptionCountOutOfBounds, id, len(stored.options))
+		}
+	}
+
+	r.polls = restored.polls
+	r.votes = restored.votes
+	r.allowedVoters = restored.allowedVoters
+	return summary, nil
+}
+
+// sortOptions puts every poll's options in position order; the caller must hold the write lock
+func (r *InMemoryPollRepository) sortOptions() {
+	for _, stored := range r.polls {
+		slices.SortFunc(stored.options, func(a, b models.PollOption) int {
+			return a.Position - b.Position
+		})
+	}
+}
+
+// findOption returns a poll and the index of one of its options; the caller must hold the lock
+func (r *InMemoryPollRepository) findOption(pollID, optionID uuid.UUID) (*memoryPoll, int, error) {
+	stored, ok := r.polls[pollID]
+	if !ok {
+		return nil, 0, fmt.Errorf("poll %s does not exist", pollID)
+	}
+	for i, opt := range stored.options {
+		if opt.ID == optionID {
+			return stored, i, nil
+		}
+	}
+	return nil, 0, fmt.Errorf("option %s does not belong to poll %s", optionID, pollID)
+}
+
+// addVote stores a vote and counts its weight; the caller must hold the write lock
+func (r *InMemoryPollRepository) addVote(stored *memoryPoll, option int, vote models.Vote) {
+	if r.votes[vote.PollID] == nil {
+		r.votes[vote.PollID] = make(map[string]models.Vote)
+	}
+	r.votes[vote.PollID][vote.VoterIdentifier] = vote
+	stored.options[option].VoteCount += vote.Weight
+	stored.poll.TotalVotes += vote.Weight
+}
+
+// addWriteInVote stores a write-in vote and counts its weight toward the poll; the caller must hold the write lock
+func (r *InMemoryPollRepository) addWriteInVote(stored *memoryPoll, vote models.Vote) {
+	if r.votes[vote.PollID] == nil {
+		r.votes[vote.PollID] = make(map[string]models.Vote)
+	}
+	r.votes[vote.PollID][vote.VoterIdentifier] = vote
+	stored.poll.TotalVotes += vote.Weight
+}
+
+// page returns polls newest first, ties broken by ID as in the list queries; the caller must hold the lock
+func (r *InMemoryPollRepository) page(limit, offset int, activeOnly bool) []*memoryPoll {
+	now := r.now()
+	polls := r.sorted(func(p *memoryPoll) bool {
+		return !activeOnly || p.openAt(now)
+	})
+	return paginate(polls, limit, offset)
+}
+
+// sorted returns the polls keep accepts in listing order, ties broken by ID as in the list queries
+// The caller must hold the lock
+func (r *InMemoryPollRepository) sorted(keep func(*memoryPoll) bool) []*memoryPoll {
+	var polls []*memoryPoll
+	for _, stored := range r.polls {
+		if keep(stored) {
+			polls = append(polls, stored)
+		}
+	}
+	slices.SortFunc(polls, func(a, b *memoryPoll) int {
+		c := a.poll.CreatedAt.Compare(b.poll.CreatedAt)
+		if c == 0 {
+			c = bytes.Compare(a.poll.ID[:], b.poll.ID[:])
+		}
+		if r.order == NewestFirst {
+			return -c
+		}
+		return c
+	})
+	return polls
+}
+
+// paginate returns the page of polls at offset, at most limit long
+func paginate(polls []*memoryPoll, limit, offset int) []*memoryPoll {
+	if offset >= len(polls) {
+		return nil
+	}
+	polls = polls[offset:]
+	if limit < len(polls) {
+		polls = polls[:limit]
+	}
+	return polls
+}
+
+// openAt reports whether the poll is active and unexpired at now
+func (p *memoryPoll) openAt(now time.Time) bool {
+	return p.poll.IsActive && (p.poll.ExpiresAt == nil || p.poll.ExpiresAt.After(now))
+}
+
+// closedSince returns when the poll closed, falling back to its expiry or creation as the SQL query does
+func (p *memoryPoll) closedSince() time.Time {
+	switch {
+	case p.closedAt != nil:
+		return *p.closedAt
+	case p.poll.ExpiresAt != nil:
+		return *p.poll.ExpiresAt
+	default:
+		return p.poll.CreatedAt
+	}
+}
+
+// hasCapacity reports whether votes of the given weight fit within the poll's max_votes
+func (p *memoryPoll) hasCapacity(weight int64) bool {
+	return p.poll.MaxVotes == nil || p.poll.TotalVotes+weight <= *p.poll.MaxVotes
+}
+
+// backupPoll copies the poll as stored, for a backup
+func (p *memoryPoll) backupPoll() *models.BackupPoll {
+	return &models.BackupPoll{
+		ID:                  p.poll.ID,
+		Question:            p.poll.Question,
+		Description:         p.poll.Description,
+		CreatedAt:           p.poll.CreatedAt,
+		ExpiresAt:           p.poll.ExpiresAt,
+		IsActive:            p.poll.IsActive,
+		OwnerID:             p.poll.OwnerID,
+		AllowWeighted:       p.poll.AllowWeighted,
+		RequireConfirmation: p.poll.RequireConfirmation,
+		QuizMode:            p.poll.QuizMode,
+		Group:               p.poll.Group,
+		RandomizeOptions:    p.poll.RandomizeOptions,
+		AllowlistOnly:       p.poll.AllowlistOnly,
+		AllowWriteIn:        p.poll.AllowWriteIn,
+		MaxVotes:            p.poll.MaxVotes,
+		VotingWindowStart:   p.poll.VotingWindowStart,
+		VotingWindowEnd:     p.poll.VotingWindowEnd,
+		Timezone:            p.poll.Timezone,
+		AcknowledgementMode: p.poll.AcknowledgementMode,
+		Featured:            p.poll.Featured,
+		FeaturedRank:        p.poll.FeaturedRank,
+		Metadata:            slices.Clone(p.poll.Metadata),
+		ClosedAt:            p.closedAt,
+		DeletedAt:           p.poll.DeletedAt,
+	}
+}
+
+// publicPoll copies the poll as the SQL queries read it
+func (p *memoryPoll) publicPoll() models.Poll {
+	poll := p.poll
+	if poll.OwnerID != nil {
+		ownerID := *poll.OwnerID
+		poll.OwnerID = &ownerID
+	}
+	if poll.Group != nil {
+		group := *poll.Group
+		poll.Group = &group
+	}
+	if poll.ExpiresAt != nil {
+		expiresAt := *poll.ExpiresAt
+		poll.ExpiresAt = &expiresAt
+	}
+	if poll.MaxVotes != nil {
+		maxVotes := *poll.MaxVotes
+		poll.MaxVotes = &maxVotes
+	}
+	if poll.VotingWindowStart != nil {
+		start, end := *poll.VotingWindowStart, *poll.VotingWindowEnd
+		poll.VotingWindowStart, poll.VotingWindowEnd = &start, &end
+	}
+	if poll.Timezone != nil {
+		timezone := *poll.Timezone
+		poll.Timezone = &timezone
+	}
+	poll.Metadata = slices.Clone(poll.Metadata)
+	if poll.DeletedAt != nil {
+		deletedAt := *poll.DeletedAt
+		poll.DeletedAt = &deletedAt
+	}
+	return poll
+}
+
+// listedPoll copies the poll with its options as the list queries read them, without quiz answers
+func (p *memoryPoll) listedPoll() models.PollWithOptions {
+	options := slices.Clone(p.options)
+	for i := range options {
+		options[i].IsCorrect = false
+	}
+	return models.PollWithOptions{Poll: p.publicPoll(), Options: options}
+}
@@ -0,0 +1,572 @@
+package repository
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/moabdelazem/k8s-app/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newSteppingMemoryRepo returns an in-memory repository whose clock advances a second per reading
+func newSteppingMemoryRepo() *InMemoryPollRepository {
+	repo := NewInMemoryPollRepository()
+	now := time.Date(2025, time.June, 1, 12, 0, 0, 0, time.UTC)
+	repo.now = func() time.Time {
+		now = now.Add(time.Second)
+		return now
+	}
+	return repo
+}
+
+func createMemoryPoll(t *testing.T, repo *InMemoryPollRepository, poll *models.Poll) []models.PollOption {
+	t.Helper()
+	options := []models.PollOption{{OptionText: "Yes"}, {OptionText: "No"}}
+	require.NoError(t, repo.CreatePoll(context.Background(), poll, options))
+	return options
+}
+
+func TestInMemoryCreatePoll_OptionCountBounds(t *testing.T) {
+	repo := NewInMemoryPollRepository()
+
+	for _, count := range []int{1, 11} {
+		err := repo.CreatePoll(context.Background(), &models.Poll{Question: "Q?"}, make([]models.PollOption, count))
+		assert.ErrorIs(t, err, ErrOptionCountOutOfBounds, "%d options", count)
+	}
+
+	total, err := repo.GetTotalPollsCount(context.Background(), false)
+	require.NoError(t, err)
+	assert.Zero(t, total)
+}
+
+func TestInMemoryCastVote_UniqueVoterPerPoll(t *testing.T) {
+	ctx := context.Background()
+	repo := NewInMemoryPollRepository()
+	poll := &models.Poll{Question: "Q?", IsActive: true}
+	options := createMemoryPoll(t, repo, poll)
+
+	require.NoError(t, repo.CastVote(ctx, &models.Vote{PollID: poll.ID, OptionID: options[0].ID, VoterIdentifier: "voter-1", Weight: 3}))
+	err := repo.CastVote(ctx, &models.Vote{PollID: poll.ID, OptionID: options[1].ID, VoterIdentifier: "voter-1"})
+	require.ErrorIs(t, err, ErrAlreadyVoted)
+
+	stored, err := repo.GetPollByID(ctx, poll.ID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), stored.TotalVotes)
+
+	require.NoError(t, repo.RemoveVote(ctx, poll.ID, "voter-1"))
+	assert.ErrorIs(t, repo.RemoveVote(ctx, poll.ID, "voter-1"), sql.ErrNoRows)
+
+	stored, err = repo.GetPollByID(ctx, poll.ID)
+	require.NoError(t, err)
+	assert.Zero(t, stored.TotalVotes)
+}
+
+func TestInMemoryListPolls_NewestFirst(t *testing.T) {
+	ctx := context.Background()
+	repo := newSteppingMemoryRepo()
+
+	var ids []uuid.UUID
+	for range 3 {
+		poll := &models.Poll{Question: "Q?", IsActive: true}
+		createMemoryPoll(t, repo, poll)
+		ids = append(ids, poll.ID)
+	}
+	require.NoError(t, repo.DeletePoll(ctx, ids[1]))
+
+	all, err := repo.ListPollsWithOptions(ctx, 2, 0, false)
+	require.NoError(t, err)
+	require.Len(t, all, 2)
+	assert.Equal(t, ids[2], all[0].ID)
+	assert.Equal(t, ids[1], all[1].ID)
+
+	rest, err := repo.ListPolls(ctx, 2, 2, false)
+	require.NoError(t, err)
+	require.Len(t, rest, 1)
+	assert.Equal(t, ids[0], rest[0].ID)
+
+	// Soft-deleted polls are kept but left out of active listings
+	active, err := repo.ListPolls(ctx, 10, 0, true)
+	require.NoError(t, err)
+	require.Len(t, active, 2)
+	assert.Equal(t, ids[2], active[0].ID)
+	assert.Equal(t, ids[0], active[1].ID)
+}
+
+func TestInMemoryListPolls_StableOrderForIdenticalTimestamps(t *testing.T) {
+	ctx := context.Background()
+	repo := NewInMemoryPollRepository()
+	batch := time.Date(2025, time.June, 1, 12, 0, 0, 0, time.UTC)
+	repo.now = func() time.Time { return batch } // A batch import creates every poll at the same instant
+
+	var ids []uuid.UUID
+	for range 7 {
+		poll := &models.Poll{Question: "Q?", IsActive: true}
+		createMemoryPoll(t, repo, poll)
+		ids = append(ids, poll.ID)
+	}
+	slices.SortFunc(ids, func(a, b uuid.UUID) int { return bytes.Compare(a[:], b[:]) })
+	reversed := slices.Clone(ids)
+	slices.Reverse(reversed)
+
+	for name, tc := range map[string]struct {
+		order ListOrder
+		want  []uuid.UUID
+	}{
+		"newest first": {order: NewestFirst, want: reversed},
+		"oldest first": {order: OldestFirst, want: ids},
+	} {
+		t.Run(name, func(t *testing.T) {
+			repo.WithListOrder(tc.order)
+
+			// Walking the pages yields every poll exactly once, in ID order
+			var paged []uuid.UUID
+			for offset := 0; offset < len(ids); offset += 3 {
+				page, err := repo.ListPollsWithOptions(ctx, 3, offset, false)
+				require.NoError(t, err)
+				for _, poll := range page {
+					paged = append(paged, poll.ID)
+				}
+			}
+			assert.Equal(t, tc.want, paged)
+
+			again, err := repo.ListPolls(ctx, len(ids), 0, false)
+			require.NoError(t, err)
+			for i, poll := range again {
+				assert.Equal(t, tc.want[i], poll.ID)
+			}
+		})
+	}
+}
+
+func TestInMemoryGetPollsByIDs_RequestOrder(t *testing.T) {
+	repo := NewInMemoryPollRepository()
+	first := &models.Poll{Question: "Q1?"}
+	second := &models.Poll{Question: "Q2?"}
+	createMemoryPoll(t, repo, first)
+	createMemoryPoll(t, repo, second)
+
+	polls, err := repo.GetPollsByIDs(context.Background(), []uuid.UUID{second.ID, uuid.New(), first.ID, second.ID})
+	require.NoError(t, err)
+	require.Len(t, polls, 2)
+	assert.Equal(t, second.ID, polls[0].ID)
+	assert.Equal(t, first.ID, polls[1].ID)
+}
+
+func TestInMemoryGetOptionsForPolls(t *testing.T) {
+	ctx := context.Background()
+	repo := NewInMemoryPollRepository()
+	first := &models.Poll{Question: "First?", IsActive: true}
+	second := &models.Poll{Question: "Second?", IsActive: true}
+	firstOptions := createMemoryPoll(t, repo, first)
+	secondOptions := createMemoryPoll(t, repo, second)
+	require.NoError(t, repo.CastVote(ctx, &models.Vote{PollID: second.ID, OptionID: secondOptions[1].ID, VoterIdentifier: "voter-1"}))
+
+	options, err := repo.GetOptionsForPolls(ctx, []uuid.UUID{first.ID, second.ID, uuid.New()})
+	require.NoError(t, err)
+	require.Len(t, options, 2, "unknown polls are left out")
+	assert.Equal(t, firstOptions, options[first.ID])
+	require.Len(t, options[second.ID], 2)
+	assert.Equal(t, []string{"Yes", "No"}, []string{options[second.ID][0].OptionText, options[second.ID][1].OptionText})
+	assert.Equal(t, int64(1), options[second.ID][1].VoteCount)
+
+	none, err := repo.GetOptionsForPolls(ctx, nil)
+	require.NoError(t, err)
+	assert.Empty(t, none)
+}
+
+func TestInMemoryListVotesByVoter(t *testing.T) {
+	ctx := context.Background()
+	repo := newSteppingMemoryRepo()
+	first := &models.Poll{Question: "First?", IsActive: true, AllowWriteIn: true}
+	second := &models.Poll{Question: "Second?", IsActive: true}
+	deleted := &models.Poll{Question: "Deleted?", IsActive: true}
+	createMemoryPoll(t, repo, first)
+	secondOptions := createMemoryPoll(t, repo, second)
+	deletedOptions := createMemoryPoll(t, repo, deleted)
+	writeIn := "Maybe"
+
+	require.NoError(t, repo.CastWriteInVote(ctx, &models.Vote{PollID: first.ID, VoterIdentifier: "voter-1", Weight: 1, WriteIn: &writeIn}))
+	require.NoError(t, repo.CastVote(ctx, &models.Vote{PollID: second.ID, OptionID: secondOptions[1].ID, VoterIdentifier: "voter-1", Weight: 2}))
+	require.NoError(t, repo.CastVote(ctx, &models.Vote{PollID: second.ID, OptionID: secondOptions[0].ID, VoterIdentifier: "voter-2", Weight: 1}))
+	require.NoError(t, repo.CastVote(ctx, &models.Vote{PollID: deleted.ID, OptionID: deletedOptions[0].ID, VoterIdentifier: "voter-1", Weight: 1}))
+	require.NoError(t, repo.DeletePoll(ctx, deleted.ID))
+
+	votes, err := repo.ListVotesByVoter(ctx, "voter-1", 10, 0)
+	require.NoError(t, err)
+	require.Len(t, votes, 2, "other voters and deleted polls are left out")
+	assert.Equal(t, second.ID, votes[0].PollID, "newest first")
+	assert.Equal(t, "Second?", votes[0].Question)
+	assert.Equal(t, &secondOptions[1].ID, votes[0].OptionID)
+	assert.Equal(t, "No", *votes[0].OptionText)
+	assert.Equal(t, int64(2), votes[0].Weight)
+	assert.Equal(t, first.ID, votes[1].PollID)
+	assert.Nil(t, votes[1].OptionID)
+	assert.Equal(t, &writeIn, votes[1].WriteIn)
+
+	page, err := repo.ListVotesByVoter(ctx, "voter-1", 1, 1)
+	require.NoError(t, err)
+	require.Len(t, page, 1)
+	assert.Equal(t, first.ID, page[0].PollID)
+
+	none, err := repo.ListVotesByVoter(ctx, "voter-3", 10, 0)
+	require.NoError(t, err)
+	assert.Empty(t, none)
+}
+
+func TestInMemoryDeletePolls_Filtered(t *testing.T) {
+	ctx := context.Background()
+	repo := newSteppingMemoryRepo()
+	spammer, other := "user:spammer", "user:other"
+	oldSpam := &models.Poll{Question: "Old spam?", IsActive: true, OwnerID: &spammer}
+	otherPoll := &models.Poll{Question: "Other?", IsActive: true, OwnerID: &other}
+	newSpam := &models.Poll{Question: "New spam?", IsActive: true, OwnerID: &spammer}
+	for _, poll := range []*models.Poll{oldSpam, otherPoll, newSpam} {
+		createMemoryPoll(t, repo, poll)
+	}
+	cutoff := newSpam.CreatedAt
+
+	none, err := repo.DeletePolls(ctx, models.PollFilter{})
+	require.NoError(t, err)
+	assert.Empty(t, none, "an empty filter deletes nothing")
+
+	deleted, err := repo.DeletePolls(ctx, models.PollFilter{OwnerID: &spammer, CreatedBefore: &cutoff})
+	require.NoError(t, err)
+	assert.Equal(t, []uuid.UUID{oldSpam.ID}, deleted, "filters combine with AND")
+
+	deleted, err = repo.DeletePolls(ctx, models.PollFilter{IDs: []uuid.UUID{oldSpam.ID, newSpam.ID}})
+	require.NoError(t, err)
+	assert.Equal(t, []uuid.UUID{newSpam.ID}, deleted, "deleted polls are skipped")
+
+	stored, err := repo.GetPollByID(ctx, newSpam.ID)
+	require.NoError(t, err)
+	assert.NotNil(t, stored.DeletedAt)
+	assert.False(t, stored.IsActive)
+	stored, err = repo.GetPollByID(ctx, otherPoll.ID)
+	require.NoError(t, err)
+	assert.Nil(t, stored.DeletedAt)
+}
+
+func TestInMemoryListActiveByOwnerExcluding(t *testing.T) {
+	ctx := context.Background()
+	repo := newSteppingMemoryRepo()
+	owner, other := "owner-1", "owner-2"
+
+	current := &models.Poll{Question: "Current?", IsActive: true, OwnerID: &owner}
+	older := &models.Poll{Question: "Older?", IsActive: true, OwnerID: &owner}
+	deleted := &models.Poll{Question: "Deleted?", IsActive: true, OwnerID: &owner}
+	expired := &models.Poll{Question: "Expired?", IsActive: true, OwnerID: &owner}
+	newer := &models.Poll{Question: "Newer?", IsActive: true, OwnerID: &owner}
+	foreign := &models.Poll{Question: "Someone else's?", IsActive: true, OwnerID: &other}
+	anonymous := &models.Poll{Question: "Anonymous?", IsActive: true}
+	for _, poll := range []*models.Poll{older, current, deleted, expired, newer, foreign, anonymous} {
+		createMemoryPoll(t, repo, poll)
+	}
+	require.NoError(t, repo.DeletePoll(ctx, deleted.ID))
+	_, err := repo.ExpireNow(ctx, expired.ID)
+	require.NoError(t, err)
+
+	related, err := repo.ListActiveByOwnerExcluding(ctx, owner, current.ID, 10)
+	require.NoError(t, err)
+	require.Len(t, related, 2, "the current, closed and other creators' polls are left out")
+	assert.Equal(t, newer.ID, related[0].ID)
+	assert.Equal(t, older.ID, related[1].ID)
+	assert.Len(t, related[0].Options, 2)
+
+	limited, err := repo.ListActiveByOwnerExcluding(ctx, owner, current.ID, 1)
+	require.NoError(t, err)
+	require.Len(t, limited, 1)
+	assert.Equal(t, newer.ID, limited[0].ID)
+
+	none, err := repo.ListActiveByOwnerExcluding(ctx, "owner-3", current.ID, 10)
+	require.NoError(t, err)
+	assert.Empty(t, none)
+}
+
+func TestInMemoryListPollsByMetadata(t *testing.T) {
+	ctx := context.Background()
+	repo := newSteppingMemoryRepo()
+
+	older := &models.Poll{Question: "Older campaign?", IsActive: true, Metadata: json.RawMessage(`{"campaign":"spring"}`)}
+	newer := &models.Poll{Question: "Newer campaign?", IsActive: true, Metadata: json.RawMessage(`{"campaign":"spring"}`)}
+	numeric := &models.Poll{Question: "Numeric campaign?", IsActive: true, Metadata: json.RawMessage(`{"campaign":1}`)}
+	plain := &models.Poll{Question: "No metadata?", IsActive: true}
+	for _, poll := range []*models.Poll{older, newer, numeric, plain} {
+		createMemoryPoll(t, repo, poll)
+	}
+	require.NoError(t, repo.SetMetadata(ctx, plain.ID, json.RawMessage(`{"campaign":"spring"}`)))
+	require.NoError(t, repo.DeletePoll(ctx, newer.ID))
+
+	matches, err := repo.ListPollsByMetadata(ctx, "campaign", "spring", 10, 0)
+	require.NoError(t, err)
+	var ids []uuid.UUID
+	for _, poll := range matches {
+		ids = append(ids, poll.ID)
+	}
+	assert.Equal(t, []uuid.UUID{plain.ID, older.ID}, ids)
+	assert.Len(t, matches[0].Options, 2)
+
+	// Callers cannot change the stored metadata through a returned poll
+	matches[0].Metadata[2] = 'X'
+	stored, err := repo.GetPollByID(ctx, plain.ID)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"campaign":"spring"}`, string(stored.Metadata))
+
+	assert.ErrorIs(t, repo.SetMetadata(ctx, newer.ID, nil), sql.ErrNoRows)
+}
+
+func TestInMemoryListFeaturedPolls_Ordering(t *testing.T) {
+	ctx := context.Background()
+	repo := newSteppingMemoryRepo()
+	first, second := 1, 2
+
+	unrankedOld := &models.Poll{Question: "Unranked old?", IsActive: true}
+	rankedSecond := &models.Poll{Question: "Ranked second?", IsActive: true}
+	rankedFirst := &models.Poll{Question: "Ranked first?", IsActive: true}
+	unrankedNew := &models.Poll{Question: "Unranked new?", IsActive: true}
+	inactive := &models.Poll{Question: "Inactive?"}
+	expired := &models.Poll{Question: "Expired?", IsActive: true}
+	unfeatured := &models.Poll{Question: "Unfeatured?", IsActive: true}
+	for _, poll := range []*models.Poll{unrankedOld, rankedSecond, rankedFirst, unrankedNew, inactive, expired, unfeatured} {
+		createMemoryPoll(t, repo, poll)
+	}
+	require.NoError(t, repo.SetFeatured(ctx, unrankedOld.ID, true, nil))
+	require.NoError(t, repo.SetFeatured(ctx, rankedSecond.ID, true, &second))
+	require.NoError(t, repo.SetFeatured(ctx, rankedFirst.ID, true, &first))
+	require.NoError(t, repo.SetFeatured(ctx, unrankedNew.ID, true, nil))
+	require.NoError(t, repo.SetFeatured(ctx, inactive.ID, true, &first))
+	require.NoError(t, repo.SetFeatured(ctx, expired.ID, true, &first))
+	_, err := repo.ExpireNow(ctx, expired.ID)
+	require.NoError(t, err)
+	require.NoError(t, repo.SetFeatured(ctx, unfeatured.ID, true, &first))
+	require.NoError(t, repo.SetFeatured(ctx, unfeatured.ID, false, nil))
+
+	featured, err := repo.ListFeaturedPolls(ctx, 10)
+	require.NoError(t, err)
+	var ids []uuid.UUID
+	for _, poll := range featured {
+		ids = append(ids, poll.ID)
+	}
+	assert.Equal(t, []uuid.UUID{rankedFirst.ID, rankedSecond.ID, unrankedNew.ID, unrankedOld.ID}, ids,
+		"ranked polls first by rank, then unranked newest first; closed and unfeatured polls are left out")
+	assert.Len(t, featured[0].Options, 2)
+
+	limited, err := repo.ListFeaturedPolls(ctx, 1)
+	require.NoError(t, err)
+	require.Len(t, limited, 1)
+	assert.Equal(t, rankedFirst.ID, limited[0].ID)
+
+	poll, err := repo.GetPollByID(ctx, unfeatured.ID)
+	require.NoError(t, err)
+	assert.False(t, poll.Featured)
+	assert.Nil(t, poll.FeaturedRank, "unfeaturing clears the rank")
+
+	assert.ErrorIs(t, repo.SetFeatured(ctx, uuid.New(), true, nil), sql.ErrNoRows)
+}
+
+func TestInMemoryListSubnetClusters(t *testing.T) {
+	ctx := context.Background()
+	repo := NewInMemoryPollRepository()
+	poll := &models.Poll{Question: "Q?", IsActive: true, AllowWriteIn: true}
+	options := createMemoryPoll(t, repo, poll)
+
+	for _, voter := range []string{"203.0.113.7", "203.0.113.12", "203.0.113.9", "198.51.100.4", "user:alice", "2001:db8::1", "5f2b9c0e"} {
+		require.NoError(t, repo.CastVote(ctx, &models.Vote{PollID: poll.ID, OptionID: options[0].ID, VoterIdentifier: voter}))
+	}
+	writeIn := "Maybe"
+	require.NoError(t, repo.CastWriteInVote(ctx, &models.Vote{PollID: poll.ID, WriteIn: &writeIn, VoterIdentifier: "203.0.113.200"}))
+
+	clusters, err := repo.ListSubnetClusters(ctx, poll.ID, 3, 3)
+	require.NoError(t, err)
+	require.Len(t, clusters, 1, "only the subnet reaching the threshold is flagged")
+	assert.Equal(t, "203.0.113.0/24", clusters[0].Subnet)
+	assert.Equal(t, int64(4), clusters[0].Votes, "write-in votes count too")
+	assert.Equal(t, []string{"203.0.113.12", "203.0.113.200", "203.0.113.7"}, clusters[0].Voters)
+
+	clusters, err = repo.ListSubnetClusters(ctx, poll.ID, 1, 10)
+	require.NoError(t, err)
+	require.Len(t, clusters, 2, "voters not identified by an IPv4 address are ignored")
+	assert.Equal(t, "198.51.100.0/24", clusters[1].Subnet)
+}
+
+func TestInMemoryListSubnetClusters_PeerAddressesAndForwardedLists(t *testing.T) {
+	ctx := context.Background()
+	repo := NewInMemoryPollRepository()
+	poll := &models.Poll{Question: "Q?", IsActive: true}
+	options := createMemoryPoll(t, repo, poll)
+
+	for _, voter := range []string{"203.0.113.7:51234", "203.0.113.8:40000", "203.0.113.9, 10.0.0.1", "[2001:db8::1]:443"} {
+		require.NoError(t, repo.CastVote(ctx, &models.Vote{PollID: poll.ID, OptionID: options[0].ID, VoterIdentifier: voter}))
+	}
+
+	clusters, err := repo.ListSubnetClusters(ctx, poll.ID, 1, 10)
+	require.NoError(t, err)
+	require.Len(t, clusters, 1)
+	assert.Equal(t, "203.0.113.0/24", clusters[0].Subnet)
+	assert.Equal(t, int64(3), clusters[0].Votes)
+	assert.Equal(t, []string{"203.0.113.7:51234", "203.0.113.8:40000", "203.0.113.9, 10.0.0.1"}, clusters[0].Voters, "voters are listed as recorded")
+}
+
+func TestInMemoryListVoteBursts(t *testing.T) {
+	ctx := context.Background()
+	repo := NewInMemoryPollRepository()
+	poll := &models.Poll{Question: "Q?", IsActive: true}
+	options := createMemoryPoll(t, repo, poll)
+
+	base := time.Date(2025, time.June, 1, 12, 0, 0, 0, time.UTC)
+	for i, offset := range []time.Duration{10 * time.Second, 20 * time.Second, 50 * time.Second, 90 * time.Second, 5 * time.Minute, 5*time.Minute + time.Second} {
+		repo.now = func() time.Time { return base.Add(offset) }
+		require.NoError(t, repo.CastVote(ctx, &models.Vote{PollID: poll.ID, OptionID: options[0].ID, VoterIdentifier: fmt.Sprintf("voter-%d", i)}))
+	}
+
+	bursts, err := repo.ListVoteBursts(ctx, poll.ID, time.Minute, 2, 2)
+	require.NoError(t, err)
+	require.Len(t, bursts, 2, "the lone vote at 12:01 is not a burst")
+	assert.Equal(t, models.VoteBurst{Start: base, End: base.Add(time.Minute), Votes: 3, Voters: []string{"voter-0", "voter-1"}}, bursts[0])
+	assert.Equal(t, base.Add(5*time.Minute), bursts[1].Start)
+	assert.Equal(t, int64(2), bursts[1].Votes)
+}
+
+func TestInMemoryGetPeakVoteRate(t *testing.T) {
+	ctx := context.Background()
+	repo := NewInMemoryPollRepository()
+	poll := &models.Poll{Question: "Q?", IsActive: true, AllowWriteIn: true}
+	options := createMemoryPoll(t, repo, poll)
+
+	peak, err := repo.GetPeakVoteRate(ctx, poll.ID, time.Second)
+	require.NoError(t, err)
+	assert.Nil(t, peak, "no votes, no peak")
+
+	// Two votes in the first second, then clusters of three (a write-in among them) two and five seconds later
+	base := time.Date(2025, time.June, 1, 12, 0, 0, 0, time.UTC)
+	offsets := []time.Duration{
+		0, 900 * time.Millisecond,
+		2 * time.Second, 2*time.Second + 100*time.Millisecond, 2*time.Second + 999*time.Millisecond,
+		5 * time.Second, 5*time.Second + 500*time.Millisecond, 5*time.Second + 900*time.Millisecond,
+	}
+	for i, offset := range offsets {
+		repo.now = func() time.Time { return base.Add(offset) }
+		if i == 3 {
+			writeIn := "Something else"
+			require.NoError(t, repo.CastWriteInVote(ctx, &models.Vote{PollID: poll.ID, VoterIdentifier: fmt.Sprintf("voter-%d", i), WriteIn: &writeIn}))
+			continue
+		}
+		require.NoError(t, repo.CastVote(ctx, &models.Vote{PollID: poll.ID, OptionID: options[0].ID, VoterIdentifier: fmt.Sprintf("voter-%d", i)}))
+	}
+
+	peak, err = repo.GetPeakVoteRate(ctx, poll.ID, time.Second)
+	require.NoError(t, err)
+	assert.Equal(t, &models.VoteRatePeak{Start: base.Add(2 * time.Second), End: base.Add(3 * time.Second), Votes: 3}, peak,
+		"the earliest of the tied clusters")
+
+	// Every vote falls in one minute bucket
+	peak, err = repo.GetPeakVoteRate(ctx, poll.ID, time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, &models.VoteRatePeak{Start: base, End: base.Add(time.Minute), Votes: 8}, peak)
+}
+
+func TestInMemoryDeactivateExpired(t *testing.T) {
+	ctx := context.Background()
+	repo := newSteppingMemoryRepo()
+	poll := &models.Poll{Question: "Q?", IsActive: true}
+	createMemoryPoll(t, repo, poll)
+
+	_, err := repo.ExpireNow(ctx, poll.ID)
+	require.NoError(t, err)
+
+	ids, err := repo.DeactivateExpired(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []uuid.UUID{poll.ID}, ids)
+
+	ids, err = repo.DeactivateExpired(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, ids)
+}
+
+func TestInMemoryImportVotes_SkipsDuplicates(t *testing.T) {
+	ctx := context.Background()
+	repo := NewInMemoryPollRepository()
+	poll := &models.Poll{Question: "Q?", IsActive: true}
+	options := createMemoryPoll(t, repo, poll)
+	require.NoError(t, repo.CastVote(ctx, &models.Vote{PollID: poll.ID, OptionID: options[0].ID, VoterIdentifier: "voter-1"}))
+
+	votedAt := time.Date(2025, time.June, 1, 12, 30, 0, 0, time.UTC)
+	votes := []*models.Vote{
+		{PollID: poll.ID, OptionID: options[1].ID, VoterIdentifier: "voter-1", VotedAt: votedAt},
+		{PollID: poll.ID, OptionID: options[1].ID, VoterIdentifier: "voter-2", VotedAt: votedAt},
+		{PollID: poll.ID, OptionID: options[0].ID, VoterIdentifier: "voter-2", VotedAt: votedAt},
+	}
+	next := func() (*models.Vote, error) {
+		if len(votes) == 0 {
+			return nil, io.EOF
+		}
+		vote := votes[0]
+		votes = votes[1:]
+		return vote, nil
+	}
+
+	imported, skipped, err := repo.ImportVotes(ctx, next)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), imported)
+	assert.Equal(t, int64(2), skipped)
+
+	timeline, err := repo.GetVoteTimeline(ctx, poll.ID, time.Hour)
+	require.NoError(t, err)
+	var imports []models.TimelineBucket
+	for _, bucket := range timeline {
+		if bucket.Start.Equal(time.Date(2025, time.June, 1, 12, 0, 0, 0, time.UTC)) {
+			imports = append(imports, bucket)
+		}
+	}
+	require.Len(t, imports, 1)
+	assert.Equal(t, []models.OptionVoteCount{{OptionID: options[1].ID, Votes: 1}}, imports[0].Options)
+}
+
+func TestInMemoryArchiveClosedPolls(t *testing.T) {
+	ctx := context.Background()
+	repo := newSteppingMemoryRepo()
+
+	closed := &models.Poll{Question: "Closed?", IsActive: true}
+	options := createMemoryPoll(t, repo, closed)
+	require.NoError(t, repo.CastVote(ctx, &models.Vote{PollID: closed.ID, OptionID: options[1].ID, VoterIdentifier: "voter-1"}))
+	require.NoError(t, repo.DeletePoll(ctx, closed.ID))
+
+	open := &models.Poll{Question: "Open?", IsActive: true}
+	createMemoryPoll(t, repo, open)
+
+	// Nothing has been closed long enough yet
+	ids, err := repo.ArchiveClosedPolls(ctx, time.Date(2025, time.June, 1, 12, 0, 0, 0, time.UTC), 10)
+	require.NoError(t, err)
+	assert.Empty(t, ids)
+
+	ids, err = repo.ArchiveClosedPolls(ctx, repo.now(), 10)
+	require.NoError(t, err)
+	assert.Equal(t, []uuid.UUID{closed.ID}, ids)
+
+	// The poll has left the live store but keeps its options and votes in the archive
+	live, err := repo.GetPollWithResults(ctx, closed.ID, "voter-1")
+	require.NoError(t, err)
+	assert.Nil(t, live)
+	total, err := repo.GetTotalPollsCount(ctx, false)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+
+	archived, err := repo.GetArchivedPollWithResults(ctx, closed.ID, "voter-1")
+	require.NoError(t, err)
+	require.NotNil(t, archived)
+	assert.Equal(t, int64(1), archived.TotalVotes)
+	assert.Len(t, archived.Options, 2)
+	assert.Equal(t, &options[1].ID, archived.VotedOption)
+
+	missing, err := repo.GetArchivedPollWithResults(ctx, open.ID, "")
+	require.NoError(t, err)
+	assert.Nil(t, missing)
+}
@@ -0,0 +1,210 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/moabdelazem/k8s-app/internal/database"
+	"github.com/moabdelazem/k8s-app/internal/models"
+)
+
+// OutboxRepositoryInterface defines the contract for relaying the event outbox
+type OutboxRepositoryInterface interface {
+	WithRelayLock(ctx context.Context, fn func(ctx context.Context) error) (bool, error)
+	FetchUnpublished(ctx context.Context, limit int) ([]models.OutboxEvent, error)
+	Postpone(ctx context.Context, ids []int64, delay time.Duration) error
+	MarkPublished(ctx context.Context, ids []int64) error
+	RecordFailure(ctx context.Context, id int64, retryIn time.Duration) error
+	DeadLetter(ctx context.Context, id int64) error
+}
+
+// relayLockKey identifies the Postgres advisory lock held by the relay draining the outbox
+const relayLockKey int64 = 0x6f7574626f78 // "outbox"
+
+// OutboxRepository reads the events written by the other repositories' transactions
+type OutboxRepository struct {
+	db      database.Conn
+	dialect Dialect
+}
+
+func NewOutboxRepository(db database.Conn) *OutboxRepository {
+	return &OutboxRepository{db: db}
+}
+
+// WithDialect sets the SQL dialect of the database behind db, Postgres by default
+func (r *OutboxRepository) WithDialect(dialect Dialect) *OutboxRepository {
+	r.dialect = dialect
+	return r
+}
+
+// WithRelayLock runs fn while holding the lock that lets one relay at a time claim events from the outbox,
+// so replicas sharing a database still publish each poll's events in order.
+// fn should only fetch and claim events: the lock is held until it returns, so it must not publish.
+// It returns false without running fn when another relay holds the lock.
+// On Postgres the lock is a transaction-scoped advisory lock, released however fn ends;
+// a SQLite database belongs to a single process, so fn simply runs.
+func (r *OutboxRepository) WithRelayLock(ctx context.Context, fn func(ctx context.Context) error) (bool, error) {
+	if r.dialect == SQLiteDialect {
+		return true, fn(ctx)
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var locked bool
+	if err := tx.QueryRowContext(ctx, `SELECT pg_try_advisory_xact_lock($1)`, relayLockKey).Scan(&locked); err != nil {
+		return false, fmt.Errorf("failed to take outbox relay lock: %w", err)
+	}
+	if !locked {
+		return false, nil
+	}
+
+	if err := fn(ctx); err != nil {
+		return true, err
+	}
+	if err := tx.Commit(); err != nil {
+		return true, fmt.Errorf("failed to release outbox relay lock: %w", err)
+	}
+	return true, nil
+}
+
+// writeOutboxEvent records an event in the outbox as part of tx,
+// so it is only published if the change it describes is committed
+func writeOutboxEvent(ctx context.Context, tx *sql.Tx, dialect Dialect, eventType string, pollID uuid.UUID, data any) error {
+	var payload []byte
+	if data != nil {
+		var err error
+		if payload, err = json.Marshal(data); err != nil {
+			return fmt.Errorf("failed to encode outbox event: %w", err)
+		}
+	}
+
+	query := `
+		INSERT INTO outbox_events (poll_id, event_type, data)
+		VALUES ($1, $2, $3)`
+
+	if _, err := tx.ExecContext(ctx, dialect.bind(query), pollID, eventType, payload); err != nil {
+		return fmt.Errorf("failed to write outbox event: %w", err)
+	}
+	return nil
+}
+
+// FetchUnpublished returns up to limit events due to be published, in the order they were written.
+// Published and dead-lettered events are left out, and so are events waiting for a retry or claimed
+// by a relay, along with every later event of their poll, so a poll's events are still offered in order
+// while one of them is held back and the other polls' events carry on.
+func (r *OutboxRepository) FetchUnpublished(ctx context.Context, limit int) ([]models.OutboxEvent, error) {
+	query := `
+		SELECT e.id, e.poll_id, e.event_type, e.data, e.occurred_at, e.attempts
+		FROM outbox_events e
+		WHERE e.published_at IS NULL
+		  AND e.dead_lettered_at IS NULL
+		  AND NOT EXISTS (
+			SELECT 1
+			FROM outbox_events held
+			WHERE held.poll_id = e.poll_id
+			  AND held.id <= e.id
+			  AND held.published_at IS NULL
+			  AND held.dead_lettered_at IS NULL
+			  AND ` + r.dialect.inFuture("held.next_attempt_at") + `
+		  )
+		ORDER BY e.id ASC
+		LIMIT $1`
+
+	rows, err := r.db.QueryContext(ctx, r.dialect.bind(query), limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch outbox events: %w", err)
+	}
+	defer rows.Close()
+
+	var events []models.OutboxEvent
+	for rows.Next() {
+		var event models.OutboxEvent
+		var data []byte
+		if err := rows.Scan(&event.ID, &event.PollID, &event.Type, &data, &event.OccurredAt, &event.Attempts); err != nil {
+			return nil, fmt.Errorf("failed to scan outbox event: %w", err)
+		}
+		if data != nil {
+			event.Data = json.RawMessage(data)
+		}
+		events = append(events, event)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating outbox events: %w", err)
+	}
+
+	return events, nil
+}
+
+// Postpone withholds the events with ids from FetchUnpublished until delay has passed.
+// Relays claim the batch they fetched with it, and hand back events they did not get to with a zero delay.
+func (r *OutboxRepository) Postpone(ctx context.Context, ids []int64, delay time.Duration) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	condition, args := r.dialect.idIn("id", ids)
+	delayParam := fmt.Sprintf("$%d", len(args)+1)
+	query := `
+		UPDATE outbox_events
+		SET next_attempt_at = ` + r.dialect.fromNow(delayParam) + `
+		WHERE ` + condition
+
+	if _, err := r.db.ExecContext(ctx, r.dialect.bind(query), append(args, delay.Seconds())...); err != nil {
+		return fmt.Errorf("failed to postpone outbox events: %w", err)
+	}
+	return nil
+}
+
+// MarkPublished records that the events with ids have been published
+func (r *OutboxRepository) MarkPublished(ctx context.Context, ids []int64) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	condition, args := r.dialect.idIn("id", ids)
+	query := `
+		UPDATE outbox_events
+		SET published_at = CURRENT_TIMESTAMP
+		WHERE ` + condition
+
+	if _, err := r.db.ExecContext(ctx, r.dialect.bind(query), args...); err != nil {
+		return fmt.Errorf("failed to mark outbox events published: %w", err)
+	}
+	return nil
+}
+
+// RecordFailure counts a failed publish of the event with id and withholds it until retryIn has passed
+func (r *OutboxRepository) RecordFailure(ctx context.Context, id int64, retryIn time.Duration) error {
+	query := `
+		UPDATE outbox_events
+		SET attempts = attempts + 1, next_attempt_at = ` + r.dialect.fromNow("$2") + `
+		WHERE id = $1`
+
+	if _, err := r.db.ExecContext(ctx, r.dialect.bind(query), id, retryIn.Seconds()); err != nil {
+		return fmt.Errorf("failed to record outbox event failure: %w", err)
+	}
+	return nil
+}
+
+// DeadLetter counts a failed publish of the event with id and sets it aside for good,
+// so the poll's later events are published without it
+func (r *OutboxRepository) DeadLetter(ctx context.Context, id int64) error {
+	query := `
+		UPDATE outbox_events
+		SET attempts = attempts + 1, dead_lettered_at = CURRENT_TIMESTAMP
+		WHERE id = $1`
+
+	if _, err := r.db.ExecContext(ctx, r.dialect.bind(query), id); err != nil {
+		return fmt.Errorf("failed to dead-letter outbox event: %w", err)
+	}
+	return nil
+}
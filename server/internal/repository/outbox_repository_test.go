@@ -0,0 +1,207 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/moabdelazem/k8s-app/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// txRecorder is a database/sql driver connection that records transaction boundaries and statements
+// Statements are recorded by their first three words, e.g. "INSERT INTO outbox_events"
+type txRecorder struct {
+	ops        []string
+	outboxArgs []driver.NamedValue // Arguments of the last outbox insert
+	failOn     string              // Statements containing this fail
+}
+
+func (r *txRecorder) Connect(context.Context) (driver.Conn, error) { return r, nil }
+func (r *txRecorder) Driver() driver.Driver                        { return nil }
+
+func (r *txRecorder) Prepare(string) (driver.Stmt, error) {
+	return nil, errors.New("prepare not supported")
+}
+
+func (r *txRecorder) Close() error { return nil }
+
+func (r *txRecorder) Begin() (driver.Tx, error) {
+	r.ops = append(r.ops, "BEGIN")
+	return recordedTx{r}, nil
+}
+
+func (r *txRecorder) record(query string, args []driver.NamedValue) error {
+	words := strings.Fields(query)
+	r.ops = append(r.ops, strings.Join(words[:3], " "))
+	if strings.Contains(query, "outbox_events") {
+		r.outboxArgs = args
+	}
+	if r.failOn != "" && strings.Contains(query, r.failOn) {
+		return errors.New("statement failed")
+	}
+	return nil
+}
+
+func (r *txRecorder) ExecContext(_ context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	if err := r.record(query, args); err != nil {
+		return nil, err
+	}
+	return driver.RowsAffected(1), nil
+}
+
+func (r *txRecorder) QueryContext(_ context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	if err := r.record(query, args); err != nil {
+		return nil, err
+	}
+	if strings.HasSuffix(strings.TrimSpace(query), "RETURNING id") {
+		return &idRow{}, nil
+	}
+	return &insertedRow{}, nil
+}
+
+type recordedTx struct{ r *txRecorder }
+
+func (t recordedTx) Commit() error {
+	t.r.ops = append(t.r.ops, "COMMIT")
+	return nil
+}
+
+func (t recordedTx) Rollback() error {
+	t.r.ops = append(t.r.ops, "ROLLBACK")
+	return nil
+}
+
+// insertedRow answers INSERT ... RETURNING id, <timestamp> with a single row
+type insertedRow struct{ done bool }
+
+func (*insertedRow) Columns() []string { return []string{"id", "created_at"} }
+func (*insertedRow) Close() error      { return nil }
+
+func (row *insertedRow) Next(dest []driver.Value) error {
+	if row.done {
+		return io.EOF
+	}
+	row.done = true
+	dest[0] = uuid.NewString()
+	dest[1] = time.Now()
+	return nil
+}
+
+// idRow answers UPDATE ... RETURNING id with a single row
+type idRow struct{ done bool }
+
+func (*idRow) Columns() []string { return []string{"id"} }
+func (*idRow) Close() error      { return nil }
+
+func (row *idRow) Next(dest []driver.Value) error {
+	if row.done {
+		return io.EOF
+	}
+	row.done = true
+	dest[0] = uuid.NewString()
+	return nil
+}
+
+func newRecordingRepo(t *testing.T, failOn string) (*PollRepository, *txRecorder) {
+	t.Helper()
+	recorder := &txRecorder{failOn: failOn}
+	db := sql.OpenDB(recorder)
+	t.Cleanup(func() { db.Close() })
+	return NewPollRepository(db), recorder
+}
+
+func TestCastVote_WritesOutboxEventInTransaction(t *testing.T) {
+	repo, recorder := newRecordingRepo(t, "")
+	pollID := uuid.New()
+
+	err := repo.CastVote(context.Background(), &models.Vote{PollID: pollID, OptionID: uuid.New(), VoterIdentifier: "voter-1"})
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"BEGIN",
+		"SELECT 1 FROM",
+		"INSERT INTO votes",
+		"UPDATE poll_options SET",
+		"INSERT INTO outbox_events",
+		"COMMIT",
+	}, recorder.ops)
+	require.Len(t, recorder.outboxArgs, 3)
+	assert.Equal(t, pollID.String(), recorder.outboxArgs[0].Value)
+	assert.Equal(t, models.EventVoteCast, recorder.outboxArgs[1].Value)
+}
+
+func TestDeletePoll_WritesOutboxEventInTransaction(t *testing.T) {
+	repo, recorder := newRecordingRepo(t, "")
+	pollID := uuid.New()
+
+	require.NoError(t, repo.DeletePoll(context.Background(), pollID))
+	assert.Equal(t, []string{
+		"BEGIN",
+		"UPDATE polls SET",
+		"INSERT INTO outbox_events",
+		"COMMIT",
+	}, recorder.ops)
+	require.Len(t, recorder.outboxArgs, 3)
+	assert.Equal(t, models.EventPollClosed, recorder.outboxArgs[1].Value)
+	assert.Nil(t, recorder.outboxArgs[2].Value)
+}
+
+func TestDeactivateExpired_WritesOutboxEventInTransaction(t *testing.T) {
+	repo, recorder := newRecordingRepo(t, "")
+
+	ids, err := repo.DeactivateExpired(context.Background())
+
+	require.NoError(t, err)
+	require.Len(t, ids, 1)
+	assert.Equal(t, []string{
+		"BEGIN",
+		"UPDATE polls SET",
+		"INSERT INTO outbox_events",
+		"COMMIT",
+	}, recorder.ops)
+	require.Len(t, recorder.outboxArgs, 3)
+	assert.Equal(t, ids[0].String(), recorder.outboxArgs[0].Value)
+	assert.Equal(t, models.EventPollClosed, recorder.outboxArgs[1].Value)
+}
+
+func TestOutboxWriteFailure_RollsBackChange(t *testing.T) {
+	writeIn := "Something else"
+	tests := []struct {
+		name  string
+		write func(repo *PollRepository) error
+	}{
+		{name: "cast vote", write: func(repo *PollRepository) error {
+			return repo.CastVote(context.Background(), &models.Vote{PollID: uuid.New(), OptionID: uuid.New()})
+		}},
+		{name: "cast write-in vote", write: func(repo *PollRepository) error {
+			return repo.CastWriteInVote(context.Background(), &models.Vote{PollID: uuid.New(), WriteIn: &writeIn})
+		}},
+		{name: "delete poll", write: func(repo *PollRepository) error {
+			return repo.DeletePoll(context.Background(), uuid.New())
+		}},
+		{name: "close expired polls", write: func(repo *PollRepository) error {
+			_, err := repo.DeactivateExpired(context.Background())
+			return err
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo, recorder := newRecordingRepo(t, "outbox_events")
+
+			err := tt.write(repo)
+
+			assert.ErrorContains(t, err, "failed to write outbox event")
+			assert.NotContains(t, recorder.ops, "COMMIT")
+			assert.Equal(t, "ROLLBACK", recorder.ops[len(recorder.ops)-1])
+		})
+	}
+}
@@ -3,9 +3,17 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/moabdelazem/k8s-app/internal/database"
 	"github.com/moabdelazem/k8s-app/internal/models"
 )
 
@@ -14,23 +22,180 @@ type PollRepositoryInterface interface {
 	CreatePoll(ctx context.Context, poll *models.Poll, options []models.PollOption) error
 	GetPollByID(ctx context.Context, id uuid.UUID) (*models.Poll, error)
 	GetPollOptions(ctx context.Context, pollID uuid.UUID) ([]models.PollOption, error)
+	GetOptionsForPolls(ctx context.Context, pollIDs []uuid.UUID) (map[uuid.UUID][]models.PollOption, error)
 	ListPolls(ctx context.Context, limit, offset int, activeOnly bool) ([]models.Poll, error)
 	ListPollsWithOptions(ctx context.Context, limit, offset int, activeOnly bool) ([]models.PollWithOptions, error)
+	StreamPollsWithOptions(ctx context.Context, limit, offset int, activeOnly bool, fn func(models.PollWithOptions) error) error
+	GetPollsByIDs(ctx context.Context, ids []uuid.UUID) ([]models.PollWithOptions, error)
 	CastVote(ctx context.Context, vote *models.Vote) error
+	CastWriteInVote(ctx context.Context, vote *models.Vote) error
 	HasVoted(ctx context.Context, pollID uuid.UUID, voterIdentifier string) (bool, *uuid.UUID, error)
+	GetPollWithResults(ctx context.Context, pollID uuid.UUID, voterIdentifier string) (*models.PollWithVote, error)
+	ListWriteIns(ctx context.Context, pollID uuid.UUID, grouped bool, limit int) ([]models.WriteInTally, error)
+	HasVotedInGroup(ctx context.Context, group string, voterIdentifier string) (bool, error)
+	ListVotesByVoter(ctx context.Context, voterIdentifier string, limit, offset int) ([]models.VoterVote, error)
+	RemoveVote(ctx context.Context, pollID uuid.UUID, voterIdentifier string) error
+	UpdateOptionTexts(ctx context.Context, pollID uuid.UUID, options []models.PollOption) error
 	DeletePoll(ctx context.Context, id uuid.UUID) error
+	DeletePolls(ctx context.Context, filter models.PollFilter) ([]uuid.UUID, error)
 	GetTotalPollsCount(ctx context.Context, activeOnly bool) (int64, error)
+	CountActivePollsByOwner(ctx context.Context, ownerID string) (int64, error)
+	ListActiveByOwnerExcluding(ctx context.Context, ownerID string, excludePollID uuid.UUID, limit int) ([]models.PollWithOptions, error)
+	SetFeatured(ctx context.Context, id uuid.UUID, featured bool, rank *int) error
+	ListFeaturedPolls(ctx context.Context, limit int) ([]models.PollWithOptions, error)
+	SetMetadata(ctx context.Context, id uuid.UUID, metadata json.RawMessage) error
+	ListPollsByMetadata(ctx context.Context, key, value string, limit, offset int) ([]models.PollWithOptions, error)
+	DeactivateExpired(ctx context.Context) ([]uuid.UUID, error)
+	ExpireNow(ctx context.Context, id uuid.UUID) (time.Time, error)
+	GetVoteTimeline(ctx context.Context, pollID uuid.UUID, bucket time.Duration) ([]models.TimelineBucket, error)
+	ListSubnetClusters(ctx context.Context, pollID uuid.UUID, minVotes int64, maxVoters int) ([]models.SubnetCluster, error)
+	ListVoteBursts(ctx context.Context, pollID uuid.UUID, window time.Duration, minVotes int64, maxVoters int) ([]models.VoteBurst, error)
+	GetPeakVoteRate(ctx context.Context, pollID uuid.UUID, bucket time.Duration) (*models.VoteRatePeak, error)
+	ImportVotes(ctx context.Context, next func() (*models.Vote, error)) (imported, skipped int64, err error)
+	ArchiveClosedPolls(ctx context.Context, closedBefore time.Time, limit int) ([]uuid.UUID, error)
+	GetArchivedPollWithResults(ctx context.Context, pollID uuid.UUID, voterIdentifier string) (*models.PollWithVote, error)
+	AddAllowedVoters(ctx context.Context, pollID uuid.UUID, voterIdentifiers []string) (int64, error)
+	RemoveAllowedVoter(ctx context.Context, pollID uuid.UUID, voterIdentifier string) (bool, error)
+	ListAllowedVoters(ctx context.Context, pollID uuid.UUID) ([]models.AllowedVoter, error)
+	IsVoterAllowed(ctx context.Context, pollID uuid.UUID, voterIdentifier string) (bool, error)
+	ExportAll(ctx context.Context, fn func(models.BackupRecord) error) error
+	ImportAll(ctx context.Context, next func() (*models.BackupRecord, error)) (*models.BackupSummary, error)
+}
+
+// pollColumns are the polls columns read by pollScanDest, in order
+var pollColumns = []string{
+	"id", "question", "description", "created_at", "expires_at", "is_active", "total_votes", "allow_weighted", "require_confirmation", "quiz_mode", "poll_group", "randomize_options", "allowlist_only", "allow_write_in", "max_votes", "voting_window_start", "voting_window_end", "timezone", "acknowledgement_mode", "featured", "featured_rank", "metadata", "owner_id", "deleted_at",
+}
+
+// selectPollColumns renders pollColumns for a SELECT list, qualified with alias when given
+func selectPollColumns(alias string) string {
+	if alias == "" {
+		return strings.Join(pollColumns, ", ")
+	}
+	qualified := make([]string, len(pollColumns))
+	for i, col := range pollColumns {
+		qualified[i] = alias + "." + col
+	}
+	return strings.Join(qualified, ", ")
+}
+
+// ListOrder is the creation-time direction polls are listed in
+// Polls created at the same instant are ordered by ID in the same direction, so offset pages never overlap or skip polls
+type ListOrder int
+
+const (
+	NewestFirst ListOrder = iota
+	OldestFirst
+)
+
+// orderBy renders the ORDER BY keys of a poll listing, qualified with alias when given
+func (o ListOrder) orderBy(alias string) string {
+	if alias != "" {
+		alias += "."
+	}
+	direction := "DESC"
+	if o == OldestFirst {
+		direction = "ASC"
+	}
+	return fmt.Sprintf("%[1]screated_at %[2]s, %[1]sid %[2]s", alias, direction)
+}
+
+// pollScanDest returns scan destinations matching pollColumns
+func pollScanDest(poll *models.Poll) []any {
+	return []any{
+		&poll.ID,
+		&poll.Question,
+		&poll.Description,
+		&poll.CreatedAt,
+		&poll.ExpiresAt,
+		&poll.IsActive,
+		&poll.TotalVotes,
+		&poll.AllowWeighted,
+		&poll.RequireConfirmation,
+		&poll.QuizMode,
+		&poll.Group,
+		&poll.RandomizeOptions,
+		&poll.AllowlistOnly,
+		&poll.AllowWriteIn,
+		&poll.MaxVotes,
+		&poll.VotingWindowStart,
+		&poll.VotingWindowEnd,
+		&poll.Timezone,
+		&poll.AcknowledgementMode,
+		&poll.Featured,
+		&poll.FeaturedRank,
+		jsonColumn{&poll.Metadata},
+		&poll.OwnerID,
+		&poll.DeletedAt,
+	}
+}
+
+// jsonColumn scans a JSON column, such as polls.metadata, into a json.RawMessage
+// Postgres returns jsonb as bytes and SQLite as text; NULL leaves the message empty.
+type jsonColumn struct {
+	dest *json.RawMessage
+}
+
+func (c jsonColumn) Scan(src any) error {
+	switch value := src.(type) {
+	case nil:
+		*c.dest = nil
+	case []byte:
+		*c.dest = slices.Clone(value) // The driver may reuse its buffer for the next row
+	case string:
+		*c.dest = json.RawMessage(value)
+	default:
+		return fmt.Errorf("cannot scan %T into a JSON column", src)
+	}
+	return nil
+}
+
+// jsonParam binds a JSON document as text, which both jsonb and SQLite's TEXT accept, or NULL when empty
+func jsonParam(doc json.RawMessage) any {
+	if len(doc) == 0 {
+		return nil
+	}
+	return string(doc)
 }
 
 type PollRepository struct {
-	db *sql.DB
+	db         database.Conn
+	order      ListOrder
+	dialect    Dialect
+	countMode  CountMode
+	milestones []int64 // Total vote counts announced with a poll.milestone event, ascending
 }
 
-func NewPollRepository(db *sql.DB) *PollRepository {
+func NewPollRepository(db database.Conn) *PollRepository {
 	return &PollRepository{db: db}
 }
 
+// WithListOrder sets the order polls are listed in, newest first by default
+func (r *PollRepository) WithListOrder(order ListOrder) *PollRepository {
+	r.order = order
+	return r
+}
+
+// WithMilestones sets the total vote counts whose first crossing writes a poll.milestone event
+func (r *PollRepository) WithMilestones(milestones []int64) *PollRepository {
+	r.milestones = slices.Compact(slices.Sorted(slices.Values(milestones)))
+	return r
+}
+
+// WithDialect sets the SQL dialect of the database behind db, Postgres by default
+func (r *PollRepository) WithDialect(dialect Dialect) *PollRepository {
+	r.dialect = dialect
+	return r
+}
+
+// WithCountMode sets how vote counts are kept, incremented on write by default
+func (r *PollRepository) WithCountMode(mode CountMode) *PollRepository {
+	r.countMode = mode
+	return r
+}
+
 // CreatePoll creates a new poll with options
+// A poll.created event is written to the outbox in the same transaction
 func (r *PollRepository) CreatePoll(ctx context.Context, poll *models.Poll, options []models.PollOption) error {
 	tx, err := r.db.BeginTx(ctx, nil)
 	if err != nil {
@@ -40,15 +205,30 @@ func (r *PollRepository) CreatePoll(ctx context.Context, poll *models.Poll, opti
 
 	// Insert poll
 	query := `
-		INSERT INTO polls (question, description, expires_at, is_active)
-		VALUES ($1, $2, $3, $4)
+		INSERT INTO polls (question, description, expires_at, is_active, owner_id, allow_weighted, require_confirmation, quiz_mode, poll_group, randomize_options, allowlist_only, allow_write_in, max_votes,
+		                   voting_window_start, voting_window_end, timezone, acknowledgement_mode, metadata)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18)
 		RETURNING id, created_at, total_votes`
 
-	err = tx.QueryRowContext(ctx, query,
+	err = tx.QueryRowContext(ctx, r.dialect.bind(query),
 		poll.Question,
 		poll.Description,
 		poll.ExpiresAt,
 		poll.IsActive,
+		poll.OwnerID,
+		poll.AllowWeighted,
+		poll.RequireConfirmation,
+		poll.QuizMode,
+		poll.Group,
+		poll.RandomizeOptions,
+		poll.AllowlistOnly,
+		poll.AllowWriteIn,
+		poll.MaxVotes,
+		poll.VotingWindowStart,
+		poll.VotingWindowEnd,
+		poll.Timezone,
+		poll.AcknowledgementMode,
+		jsonParam(poll.Metadata),
 	).Scan(&poll.ID, &poll.CreatedAt, &poll.TotalVotes)
 
 	if err != nil {
@@ -57,45 +237,53 @@ func (r *PollRepository) CreatePoll(ctx context.Context, poll *models.Poll, opti
 
 	// Insert options
 	optionQuery := `
-		INSERT INTO poll_options (poll_id, option_text, position)
-		VALUES ($1, $2, $3)
+		INSERT INTO poll_options (poll_id, option_text, position, is_correct)
+		VALUES ($1, $2, $3, $4)
 		RETURNING id, created_at, vote_count`
 
 	for i := range options {
 		options[i].PollID = poll.ID
 		options[i].Position = i
 
-		err = tx.QueryRowContext(ctx, optionQuery,
+		err = tx.QueryRowContext(ctx, r.dialect.bind(optionQuery),
 			options[i].PollID,
 			options[i].OptionText,
 			options[i].Position,
+			options[i].IsCorrect,
 		).Scan(&options[i].ID, &options[i].CreatedAt, &options[i].VoteCount)
 
 		if err != nil {
+			if mapped := mapOptionCountError(err); mapped != nil {
+				return mapped
+			}
 			return fmt.Errorf("failed to insert option: %w", err)
 		}
 	}
 
-	return tx.Commit()
+	if err := writeOutboxEvent(ctx, tx, r.dialect, models.EventPollCreated, poll.ID, nil); err != nil {
+		return err
+	}
+
+	// The minimum option count is checked by a deferred trigger when committing
+	if err := tx.Commit(); err != nil {
+		if mapped := mapOptionCountError(err); mapped != nil {
+			return mapped
+		}
+		return fmt.Errorf("failed to commit poll: %w", err)
+	}
+
+	return nil
 }
 
 // GetPollByID retrieves a poll by ID
 func (r *PollRepository) GetPollByID(ctx context.Context, id uuid.UUID) (*models.Poll, error) {
-	query := `
-		SELECT id, question, description, created_at, expires_at, is_active, total_votes
+	query := fmt.Sprintf(`
+		SELECT %s
 		FROM polls
-		WHERE id = $1`
+		WHERE id = $1`, r.selectPolls("", "votes", "write_in_votes"))
 
 	poll := &models.Poll{}
-	err := r.db.QueryRowContext(ctx, query, id).Scan(
-		&poll.ID,
-		&poll.Question,
-		&poll.Description,
-		&poll.CreatedAt,
-		&poll.ExpiresAt,
-		&poll.IsActive,
-		&poll.TotalVotes,
-	)
+	err := r.db.QueryRowContext(ctx, r.dialect.bind(query), id).Scan(pollScanDest(poll)...)
 
 	if err == sql.ErrNoRows {
 		return nil, nil
@@ -109,13 +297,13 @@ func (r *PollRepository) GetPollByID(ctx context.Context, id uuid.UUID) (*models
 
 // GetPollOptions retrieves all options for a poll
 func (r *PollRepository) GetPollOptions(ctx context.Context, pollID uuid.UUID) ([]models.PollOption, error) {
-	query := `
-		SELECT id, poll_id, option_text, vote_count, position, created_at
-		FROM poll_options
-		WHERE poll_id = $1
-		ORDER BY position ASC`
+	query := fmt.Sprintf(`
+		SELECT po.id, po.poll_id, po.option_text, %s, po.position, po.is_correct, po.created_at
+		FROM poll_options po
+		WHERE po.poll_id = $1
+		ORDER BY po.position ASC`, r.optionVoteCount("votes"))
 
-	rows, err := r.db.QueryContext(ctx, query, pollID)
+	rows, err := r.db.QueryContext(ctx, r.dialect.bind(query), pollID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query options: %w", err)
 	}
@@ -130,6 +318,7 @@ func (r *PollRepository) GetPollOptions(ctx context.Context, pollID uuid.UUID) (
 			&opt.OptionText,
 			&opt.VoteCount,
 			&opt.Position,
+			&opt.IsCorrect,
 			&opt.CreatedAt,
 		)
 		if err != nil {
@@ -143,14 +332,14 @@ func (r *PollRepository) GetPollOptions(ctx context.Context, pollID uuid.UUID) (
 
 // ListPolls retrieves polls with pagination
 func (r *PollRepository) ListPolls(ctx context.Context, limit, offset int, activeOnly bool) ([]models.Poll, error) {
-	query := `
-		SELECT id, question, description, created_at, expires_at, is_active, total_votes
+	query := fmt.Sprintf(`
+		SELECT %s
 		FROM polls
-		WHERE ($1 = false OR (is_active = true AND (expires_at IS NULL OR expires_at > NOW())))
-		ORDER BY created_at DESC
-		LIMIT $2 OFFSET $3`
+		WHERE ($1 = false OR (is_active = true AND (expires_at IS NULL OR %s)))
+		ORDER BY %s
+		LIMIT $2 OFFSET $3`, r.selectPolls("", "votes", "write_in_votes"), r.dialect.inFuture("expires_at"), r.order.orderBy(""))
 
-	rows, err := r.db.QueryContext(ctx, query, activeOnly, limit, offset)
+	rows, err := r.db.QueryContext(ctx, r.dialect.bind(query), activeOnly, limit, offset)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query polls: %w", err)
 	}
@@ -159,15 +348,7 @@ func (r *PollRepository) ListPolls(ctx context.Context, limit, offset int, activ
 	var polls []models.Poll
 	for rows.Next() {
 		var poll models.Poll
-		err := rows.Scan(
-			&poll.ID,
-			&poll.Question,
-			&poll.Description,
-			&poll.CreatedAt,
-			&poll.ExpiresAt,
-			&poll.IsActive,
-			&poll.TotalVotes,
-		)
+		err := rows.Scan(pollScanDest(&poll)...)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan poll: %w", err)
 		}
@@ -179,26 +360,140 @@ func (r *PollRepository) ListPolls(ctx context.Context, limit, offset int, activ
 
 // ListPollsWithOptions retrieves polls with their options in a single query (optimized)
 func (r *PollRepository) ListPollsWithOptions(ctx context.Context, limit, offset int, activeOnly bool) ([]models.PollWithOptions, error) {
-	// Query to get polls with their options using a LEFT JOIN
-	query := `
+	var result []models.PollWithOptions
+	err := r.StreamPollsWithOptions(ctx, limit, offset, activeOnly, func(poll models.PollWithOptions) error {
+		result = append(result, poll)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// StreamPollsWithOptions reads a page of polls with their options and passes each poll to fn
+// as soon as its rows have been scanned, so memory use does not grow with the page size.
+// An error returned by fn stops the iteration and is returned as is.
+func (r *PollRepository) StreamPollsWithOptions(ctx context.Context, limit, offset int, activeOnly bool, fn func(models.PollWithOptions) error) error {
+	// Paginate polls before joining so LIMIT counts polls rather than option rows
+	query := fmt.Sprintf(`
+		SELECT 
+			%s,
+			po.id, po.poll_id, po.option_text, %s, po.position, po.created_at
+		FROM (
+			SELECT *
+			FROM polls
+			WHERE ($1 = false OR (is_active = true AND (expires_at IS NULL OR %s)))
+			ORDER BY %s
+			LIMIT $2 OFFSET $3
+		) p
+		LEFT JOIN poll_options po ON p.id = po.poll_id
+		ORDER BY %s, po.position ASC`, r.selectPolls("p", "votes", "write_in_votes"), r.optionVoteCount("votes"),
+		r.dialect.inFuture("expires_at"), r.order.orderBy(""), r.order.orderBy("p"))
+
+	rows, err := r.db.QueryContext(ctx, r.dialect.bind(query), activeOnly, limit, offset)
+	if err != nil {
+		return fmt.Errorf("failed to query polls with options: %w", err)
+	}
+	defer rows.Close()
+
+	return scanPollsWithOptions(rows, fn)
+}
+
+// GetOptionsForPolls retrieves the options of several polls in one query, grouped by poll ID
+// Each poll's options are in position order; polls without options are absent from the map
+func (r *PollRepository) GetOptionsForPolls(ctx context.Context, pollIDs []uuid.UUID) (map[uuid.UUID][]models.PollOption, error) {
+	options := make(map[uuid.UUID][]models.PollOption, len(pollIDs))
+	if len(pollIDs) == 0 {
+		return options, nil
+	}
+
+	idStrings := make([]string, len(pollIDs))
+	for i, id := range pollIDs {
+		idStrings[i] = id.String()
+	}
+
+	query := fmt.Sprintf(`
+		SELECT po.id, po.poll_id, po.option_text, %s, po.position, po.is_correct, po.created_at
+		FROM poll_options po
+		WHERE po.poll_id = ANY($1::uuid[])
+		ORDER BY po.poll_id, po.position ASC`, r.optionVoteCount("votes"))
+
+	rows, err := r.db.QueryContext(ctx, query, pq.Array(idStrings))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query options for polls: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var opt models.PollOption
+		err := rows.Scan(
+			&opt.ID,
+			&opt.PollID,
+			&opt.OptionText,
+			&opt.VoteCount,
+			&opt.Position,
+			&opt.IsCorrect,
+			&opt.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan option: %w", err)
+		}
+		options[opt.PollID] = append(options[opt.PollID], opt)
+	}
+
+	return options, rows.Err()
+}
+
+// GetPollsByIDs retrieves the polls with the given IDs and their options
+// Polls are returned in the order of ids; duplicate IDs are returned once and missing ones are omitted
+func (r *PollRepository) GetPollsByIDs(ctx context.Context, ids []uuid.UUID) ([]models.PollWithOptions, error) {
+	if len(ids) == 0 {
+		return []models.PollWithOptions{}, nil
+	}
+
+	// Duplicates would split a poll's rows into separate groups
+	seen := make(map[uuid.UUID]bool, len(ids))
+	idStrings := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			idStrings = append(idStrings, id.String())
+		}
+	}
+
+	query := fmt.Sprintf(`
 		SELECT 
-			p.id, p.question, p.description, p.created_at, p.expires_at, p.is_active, p.total_votes,
-			po.id, po.poll_id, po.option_text, po.vote_count, po.position, po.created_at
+			%s,
+			po.id, po.poll_id, po.option_text, %s, po.position, po.created_at
 		FROM polls p
+		JOIN unnest($1::uuid[]) WITH ORDINALITY AS requested(id, ord) ON requested.id = p.id
 		LEFT JOIN poll_options po ON p.id = po.poll_id
-		WHERE ($1 = false OR (p.is_active = true AND (p.expires_at IS NULL OR p.expires_at > NOW())))
-		ORDER BY p.created_at DESC, po.position ASC
-		LIMIT $2 OFFSET $3`
+		WHERE p.id = ANY($1::uuid[])
+		ORDER BY requested.ord, po.position ASC`, r.selectPolls("p", "votes", "write_in_votes"), r.optionVoteCount("votes"))
 
-	rows, err := r.db.QueryContext(ctx, query, activeOnly, limit, offset)
+	rows, err := r.db.QueryContext(ctx, query, pq.Array(idStrings))
 	if err != nil {
-		return nil, fmt.Errorf("failed to query polls with options: %w", err)
+		return nil, fmt.Errorf("failed to query polls by ids: %w", err)
 	}
 	defer rows.Close()
 
-	// Map to group options by poll ID
-	pollsMap := make(map[uuid.UUID]*models.PollWithOptions)
-	var pollIDs []uuid.UUID // To maintain order
+	result := []models.PollWithOptions{}
+	err = scanPollsWithOptions(rows, func(poll models.PollWithOptions) error {
+		result = append(result, poll)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// scanPollsWithOptions groups poll/option join rows into polls and passes each to fn
+// Rows for one poll must be contiguous; an error returned by fn stops the scan
+func scanPollsWithOptions(rows *sql.Rows, fn func(models.PollWithOptions) error) error {
+	// Emit the current poll when the next one starts
+	var current *models.PollWithOptions
 
 	for rows.Next() {
 		var poll models.Poll
@@ -209,32 +504,28 @@ func (r *PollRepository) ListPollsWithOptions(ctx context.Context, limit, offset
 		var optionPosition sql.NullInt32
 		var optionCreatedAt sql.NullTime
 
-		err := rows.Scan(
-			&poll.ID,
-			&poll.Question,
-			&poll.Description,
-			&poll.CreatedAt,
-			&poll.ExpiresAt,
-			&poll.IsActive,
-			&poll.TotalVotes,
+		err := rows.Scan(append(pollScanDest(&poll),
 			&optionID,
 			&optionPollID,
 			&optionText,
 			&optionVoteCount,
 			&optionPosition,
 			&optionCreatedAt,
-		)
+		)...)
 		if err != nil {
-			return nil, fmt.Errorf("failed to scan poll with options: %w", err)
+			return fmt.Errorf("failed to scan poll with options: %w", err)
 		}
 
-		// Check if poll exists in map
-		if _, exists := pollsMap[poll.ID]; !exists {
-			pollsMap[poll.ID] = &models.PollWithOptions{
+		if current == nil || current.ID != poll.ID {
+			if current != nil {
+				if err := fn(*current); err != nil {
+					return err
+				}
+			}
+			current = &models.PollWithOptions{
 				Poll:    poll,
 				Options: []models.PollOption{},
 			}
-			pollIDs = append(pollIDs, poll.ID)
 		}
 
 		// Add option if it exists (LEFT JOIN may return NULL for polls without options)
@@ -249,24 +540,24 @@ func (r *PollRepository) ListPollsWithOptions(ctx context.Context, limit, offset
 			option.Position = int(optionPosition.Int32)
 			option.CreatedAt = optionCreatedAt.Time
 
-			pollsMap[poll.ID].Options = append(pollsMap[poll.ID].Options, option)
+			current.Options = append(current.Options, option)
 		}
 	}
 
 	if err := rows.Err(); err != nil {
-		return nil, err
+		return err
 	}
 
-	// Convert map to slice maintaining order
-	var result []models.PollWithOptions
-	for _, pollID := range pollIDs {
-		result = append(result, *pollsMap[pollID])
+	if current != nil {
+		return fn(*current)
 	}
-
-	return result, nil
+	return nil
 }
 
 // CastVote records a vote for an option
+// A vote.cast event is written to the outbox in the same transaction
+// Returns ErrAlreadyVoted when the voter has already cast a write-in vote on the poll,
+// and ErrPollFull when the vote would take the poll past its max_votes
 func (r *PollRepository) CastVote(ctx context.Context, vote *models.Vote) error {
 	tx, err := r.db.BeginTx(ctx, nil)
 	if err != nil {
@@ -274,45 +565,202 @@ func (r *PollRepository) CastVote(ctx context.Context, vote *models.Vote) error
 	}
 	defer tx.Rollback()
 
+	// Default to one-person-one-vote
+	if vote.Weight == 0 {
+		vote.Weight = 1
+	}
+
+	if err := r.lockPoll(ctx, tx, vote.PollID); err != nil {
+		return fmt.Errorf("failed to cast vote: %w", err)
+	}
+
 	// Insert vote (will fail if voter already voted due to unique constraint)
+	// Nothing is inserted when the voter wrote in an answer instead
 	voteQuery := `
-		INSERT INTO votes (poll_id, option_id, voter_identifier)
-		VALUES ($1, $2, $3)
+		INSERT INTO votes (poll_id, option_id, voter_identifier, weight, campaign)
+		SELECT $1::uuid, $2::uuid, $3, $4::bigint, $5
+		WHERE NOT EXISTS (SELECT 1 FROM write_in_votes WHERE poll_id = $1 AND voter_identifier = $3)
 		RETURNING id, voted_at`
 
-	err = tx.QueryRowContext(ctx, voteQuery,
+	err = tx.QueryRowContext(ctx, r.dialect.bind(voteQuery),
 		vote.PollID,
 		vote.OptionID,
 		vote.VoterIdentifier,
+		vote.Weight,
+		vote.Campaign,
 	).Scan(&vote.ID, &vote.VotedAt)
 
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrAlreadyVoted
+	}
+	if capacityErr := mapCapacityError(err); capacityErr != nil {
+		return capacityErr
+	}
 	if err != nil {
 		return fmt.Errorf("failed to cast vote: %w", err)
 	}
 
-	// Increment option vote count
-	updateQuery := `
-		UPDATE poll_options
-		SET vote_count = vote_count + 1
-		WHERE id = $1`
+	// Increment option vote count by the vote's weight
+	if r.countMode == IncrementedCounts {
+		updateQuery := `
+			UPDATE poll_options
+			SET vote_count = vote_count + $2
+			WHERE id = $1`
+
+		_, err = tx.ExecContext(ctx, r.dialect.bind(updateQuery), vote.OptionID, vote.Weight)
+		if err != nil {
+			return fmt.Errorf("failed to update vote count: %w", err)
+		}
+	}
+
+	err = writeOutboxEvent(ctx, tx, r.dialect, models.EventVoteCast, vote.PollID, map[string]any{
+		"option_id": vote.OptionID,
+		"weight":    vote.Weight,
+	})
+	if err != nil {
+		return err
+	}
+	if err := r.recordMilestones(ctx, tx, vote.PollID, vote.Weight); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// CastWriteInVote records a write-in vote, whose free-text answer is in vote.WriteIn
+// A vote.cast event is written to the outbox in the same transaction
+// Returns ErrAlreadyVoted when the voter has already voted for an option on the poll,
+// and ErrPollFull when the vote would take the poll past its max_votes
+func (r *PollRepository) CastWriteInVote(ctx context.Context, vote *models.Vote) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if vote.Weight == 0 {
+		vote.Weight = 1
+	}
+
+	if err := r.lockPoll(ctx, tx, vote.PollID); err != nil {
+		return fmt.Errorf("failed to cast write-in vote: %w", err)
+	}
+
+	// Insert write-in (will fail if voter already wrote one in due to unique constraint)
+	// Nothing is inserted when the voter voted for an option instead
+	query := `
+		INSERT INTO write_in_votes (poll_id, voter_identifier, write_in_text, weight, campaign)
+		SELECT $1::uuid, $2, $3, $4::bigint, $5
+		WHERE NOT EXISTS (SELECT 1 FROM votes WHERE poll_id = $1 AND voter_identifier = $2)
+		RETURNING id, voted_at`
+
+	err = tx.QueryRowContext(ctx, r.dialect.bind(query),
+		vote.PollID,
+		vote.VoterIdentifier,
+		vote.WriteIn,
+		vote.Weight,
+		vote.Campaign,
+	).Scan(&vote.ID, &vote.VotedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrAlreadyVoted
+	}
+	if capacityErr := mapCapacityError(err); capacityErr != nil {
+		return capacityErr
+	}
+	if err != nil {
+		return fmt.Errorf("failed to cast write-in vote: %w", err)
+	}
+	vote.OptionID = uuid.Nil
 
-	_, err = tx.ExecContext(ctx, updateQuery, vote.OptionID)
+	err = writeOutboxEvent(ctx, tx, r.dialect, models.EventVoteCast, vote.PollID, map[string]any{
+		"write_in": true,
+		"weight":   vote.Weight,
+	})
 	if err != nil {
-		return fmt.Errorf("failed to update vote count: %w", err)
+		return err
+	}
+	if err := r.recordMilestones(ctx, tx, vote.PollID, vote.Weight); err != nil {
+		return err
 	}
 
 	return tx.Commit()
 }
 
+// recordMilestones writes a poll.milestone event for each milestone the poll's total votes crossed
+// with a vote of weight, just recorded in tx. poll_milestones keeps the milestones a poll has reached,
+// so each is announced once even when removed votes take the total back below it; the poll lock
+// taken by the vote keeps concurrent votes from reading the same total.
+func (r *PollRepository) recordMilestones(ctx context.Context, tx *sql.Tx, pollID uuid.UUID, weight int64) error {
+	if len(r.milestones) == 0 {
+		return nil
+	}
+
+	var total int64
+	if err := tx.QueryRowContext(ctx, r.dialect.bind(`SELECT total_votes FROM polls WHERE id = $1`), pollID).Scan(&total); err != nil {
+		return fmt.Errorf("failed to read total votes: %w", err)
+	}
+
+	query := r.dialect.bind(`
+		INSERT INTO poll_milestones (poll_id, milestone)
+		VALUES ($1, $2)
+		ON CONFLICT DO NOTHING`)
+
+	for _, milestone := range r.milestones {
+		if milestone <= total-weight || milestone > total {
+			continue
+		}
+		result, err := tx.ExecContext(ctx, query, pollID, milestone)
+		if err != nil {
+			return fmt.Errorf("failed to record milestone: %w", err)
+		}
+		inserted, err := result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("failed to record milestone: %w", err)
+		}
+		if inserted == 0 {
+			continue // Reached before
+		}
+		err = writeOutboxEvent(ctx, tx, r.dialect, models.EventMilestone, pollID, map[string]any{
+			"milestone":   milestone,
+			"total_votes": total,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// lockPoll locks a poll's row for the rest of tx
+// Each vote table only keeps voters unique within itself. Every vote transaction takes the poll lock
+// before its insert checks the other table, so voters stay unique across both: the insert is a later
+// statement and sees votes committed while waiting for the lock.
+// SQLite needs no lock, as it runs one write transaction at a time.
+func (r *PollRepository) lockPoll(ctx context.Context, tx *sql.Tx, pollID uuid.UUID) error {
+	if !r.dialect.locksRows() {
+		return nil
+	}
+	if _, err := tx.ExecContext(ctx, `SELECT 1 FROM polls WHERE id = $1 FOR UPDATE`, pollID); err != nil {
+		return fmt.Errorf("failed to lock poll: %w", err)
+	}
+	return nil
+}
+
 // HasVoted checks if a voter has already voted on a poll
+// The option is nil for a write-in vote
 func (r *PollRepository) HasVoted(ctx context.Context, pollID uuid.UUID, voterIdentifier string) (bool, *uuid.UUID, error) {
 	query := `
 		SELECT option_id
 		FROM votes
-		WHERE poll_id = $1 AND voter_identifier = $2`
+		WHERE poll_id = $1 AND voter_identifier = $2
+		UNION ALL
+		SELECT NULL
+		FROM write_in_votes
+		WHERE poll_id = $1 AND voter_identifier = $2
+		LIMIT 1`
 
-	var optionID uuid.UUID
-	err := r.db.QueryRowContext(ctx, query, pollID, voterIdentifier).Scan(&optionID)
+	var optionID uuid.NullUUID
+	err := r.db.QueryRowContext(ctx, r.dialect.bind(query), pollID, voterIdentifier).Scan(&optionID)
 
 	if err == sql.ErrNoRows {
 		return false, nil, nil
@@ -321,45 +769,1290 @@ func (r *PollRepository) HasVoted(ctx context.Context, pollID uuid.UUID, voterId
 		return false, nil, fmt.Errorf("failed to check vote: %w", err)
 	}
 
-	return true, &optionID, nil
+	if !optionID.Valid {
+		return true, nil, nil
+	}
+	return true, &optionID.UUID, nil
 }
 
-// DeletePoll soft deletes a poll
-func (r *PollRepository) DeletePoll(ctx context.Context, id uuid.UUID) error {
-	query := `
-		UPDATE polls
-		SET is_active = false
-		WHERE id = $1`
+// GetPollWithResults reads a poll, its options and a voter's vote in one round trip,
+// replacing GetPollByID, GetPollOptions and HasVoted on the results path
+// An empty voterIdentifier skips the vote lookup. Returns nil if the poll does not exist.
+func (r *PollRepository) GetPollWithResults(ctx context.Context, pollID uuid.UUID, voterIdentifier string) (*models.PollWithVote, error) {
+	return r.queryPollWithResults(ctx, "polls", "poll_options", "votes", "write_in_votes", pollID, voterIdentifier)
+}
 
-	result, err := r.db.ExecContext(ctx, query, id)
+// GetArchivedPollWithResults is GetPollWithResults for a poll moved to the archive tables
+// Returns nil if the poll is not archived.
+func (r *PollRepository) GetArchivedPollWithResults(ctx context.Context, pollID uuid.UUID, voterIdentifier string) (*models.PollWithVote, error) {
+	return r.queryPollWithResults(ctx, "polls_archive", "poll_options_archive", "votes_archive", "write_in_votes_archive", pollID, voterIdentifier)
+}
+
+// queryPollWithResults reads a poll, its options and a voter's vote from the given tables
+func (r *PollRepository) queryPollWithResults(ctx context.Context, pollsTable, optionsTable, votesTable, writeInsTable string, pollID uuid.UUID, voterIdentifier string) (*models.PollWithVote, error) {
+	// A voter has at most one vote per poll, so the vote joins add no rows
+	query := fmt.Sprintf(`
+		SELECT
+			%s,
+			po.id, po.option_text, %s, po.position, po.is_correct, po.created_at,
+			v.option_id, w.id IS NOT NULL
+		FROM %s p
+		LEFT JOIN %s po ON po.poll_id = p.id
+		LEFT JOIN %s v ON v.poll_id = p.id AND v.voter_identifier = $2 AND $2 <> ''
+		LEFT JOIN %s w ON w.poll_id = p.id AND w.voter_identifier = $2 AND $2 <> ''
+		WHERE p.id = $1
+		ORDER BY po.position ASC`, r.selectPolls("p", votesTable, writeInsTable), r.optionVoteCount(votesTable),
+		pollsTable, optionsTable, votesTable, writeInsTable)
+
+	rows, err := r.db.QueryContext(ctx, query, pollID, voterIdentifier)
 	if err != nil {
-		return fmt.Errorf("failed to delete poll: %w", err)
+		return nil, fmt.Errorf("failed to query poll results: %w", err)
 	}
+	defer rows.Close()
 
-	rows, err := result.RowsAffected()
-	if err != nil {
-		return fmt.Errorf("failed to get rows affected: %w", err)
+	var result *models.PollWithVote
+	for rows.Next() {
+		var poll models.Poll
+		var optionID, votedOption uuid.NullUUID
+		var optionText sql.NullString
+		var optionVoteCount sql.NullInt64
+		var optionPosition sql.NullInt32
+		var optionIsCorrect sql.NullBool
+		var optionCreatedAt sql.NullTime
+		var votedWriteIn bool
+
+		err := rows.Scan(append(pollScanDest(&poll),
+			&optionID,
+			&optionText,
+			&optionVoteCount,
+			&optionPosition,
+			&optionIsCorrect,
+			&optionCreatedAt,
+			&votedOption,
+			&votedWriteIn,
+		)...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan poll results: %w", err)
+		}
+
+		if result == nil {
+			result = &models.PollWithVote{
+				PollWithOptions: models.PollWithOptions{Poll: poll, Options: []models.PollOption{}},
+				VotedWriteIn:    votedWriteIn,
+			}
+			if votedOption.Valid {
+				result.VotedOption = &votedOption.UUID
+			}
+		}
+
+		// Add option if it exists (LEFT JOIN returns NULL for a poll without options)
+		if optionID.Valid {
+			result.Options = append(result.Options, models.PollOption{
+				ID:         optionID.UUID,
+				PollID:     poll.ID,
+				OptionText: optionText.String,
+				VoteCount:  optionVoteCount.Int64,
+				Position:   int(optionPosition.Int32),
+				IsCorrect:  optionIsCorrect.Bool,
+				CreatedAt:  optionCreatedAt.Time,
+			})
+		}
 	}
 
-	if rows == 0 {
-		return fmt.Errorf("poll not found")
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read poll results: %w", err)
 	}
 
-	return nil
+	return result, nil
 }
 
-// GetTotalPollsCount returns the total number of polls
-func (r *PollRepository) GetTotalPollsCount(ctx context.Context, activeOnly bool) (int64, error) {
+// ListWriteIns tallies a poll's write-in votes, live or archived, returning at most limit tallies
+// Grouped tallies merge answers differing only in case under the first spelling used, most votes
+// first; otherwise each vote is its own tally, newest first.
+func (r *PollRepository) ListWriteIns(ctx context.Context, pollID uuid.UUID, grouped bool, limit int) ([]models.WriteInTally, error) {
+	// A poll is either live or archived, so at most one side of the union has rows
+	const writeIns = `
+		SELECT id, write_in_text, weight, voted_at FROM write_in_votes WHERE poll_id = $1
+		UNION ALL
+		SELECT id, write_in_text, weight, voted_at FROM write_in_votes_archive WHERE poll_id = $1`
+
 	query := `
-		SELECT COUNT(*)
-		FROM polls
-		WHERE ($1 = false OR (is_active = true AND (expires_at IS NULL OR expires_at > NOW())))`
+		SELECT write_in_text, weight
+		FROM (` + writeIns + `) w
+		ORDER BY voted_at DESC, id DESC
+		LIMIT $2`
+	if grouped {
+		query = `
+			SELECT (array_agg(write_in_text ORDER BY voted_at, id))[1], SUM(weight) AS votes
+			FROM (` + writeIns + `) w
+			GROUP BY LOWER(write_in_text)
+			ORDER BY votes DESC, MIN(voted_at) ASC
+			LIMIT $2`
+	}
 
-	var count int64
-	err := r.db.QueryRowContext(ctx, query, activeOnly).Scan(&count)
+	rows, err := r.db.QueryContext(ctx, query, pollID, limit)
 	if err != nil {
-		return 0, fmt.Errorf("failed to count polls: %w", err)
+		return nil, fmt.Errorf("failed to query write-ins: %w", err)
 	}
+	defer rows.Close()
 
-	return count, nil
+	tallies := []models.WriteInTally{}
+	for rows.Next() {
+		var tally models.WriteInTally
+		if err := rows.Scan(&tally.Text, &tally.VoteCount); err != nil {
+			return nil, fmt.Errorf("failed to scan write-in: %w", err)
+		}
+		tallies = append(tallies, tally)
+	}
+
+	return tallies, rows.Err()
+}
+
+// HasVotedInGroup checks if a voter has already voted, or written in, on any poll in a group
+// Group names are compared case-insensitively
+func (r *PollRepository) HasVotedInGroup(ctx context.Context, group string, voterIdentifier string) (bool, error) {
+	query := `
+		SELECT EXISTS (
+			SELECT 1
+			FROM votes v
+			JOIN polls p ON p.id = v.poll_id
+			WHERE LOWER(p.poll_group) = LOWER($1) AND v.voter_identifier = $2
+		) OR EXISTS (
+			SELECT 1
+			FROM write_in_votes w
+			JOIN polls p ON p.id = w.poll_id
+			WHERE LOWER(p.poll_group) = LOWER($1) AND w.voter_identifier = $2
+		)`
+
+	var voted bool
+	if err := r.db.QueryRowContext(ctx, query, group, voterIdentifier).Scan(&voted); err != nil {
+		return false, fmt.Errorf("failed to check group vote: %w", err)
+	}
+
+	return voted, nil
+}
+
+// ListVotesByVoter returns a page of the votes, including write-ins, cast by voterIdentifier
+// across all polls, newest first. Votes on deleted or archived polls are left out.
+func (r *PollRepository) ListVotesByVoter(ctx context.Context, voterIdentifier string, limit, offset int) ([]models.VoterVote, error) {
+	query := `
+		SELECT v.poll_id, p.question, v.option_id, o.option_text, NULL AS write_in_text, v.weight, v.voted_at
+		FROM votes v
+		JOIN polls p ON p.id = v.poll_id
+		JOIN poll_options o ON o.id = v.option_id
+		WHERE v.voter_identifier = $1 AND p.deleted_at IS NULL
+		UNION ALL
+		SELECT w.poll_id, p.question, NULL, NULL, w.write_in_text, w.weight, w.voted_at
+		FROM write_in_votes w
+		JOIN polls p ON p.id = w.poll_id
+		WHERE w.voter_identifier = $1 AND p.deleted_at IS NULL
+		ORDER BY voted_at DESC, poll_id
+		LIMIT $2 OFFSET $3`
+
+	rows, err := r.db.QueryContext(ctx, r.dialect.bind(query), voterIdentifier, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list votes by voter: %w", err)
+	}
+	defer rows.Close()
+
+	votes := []models.VoterVote{}
+	for rows.Next() {
+		var vote models.VoterVote
+		var optionID uuid.NullUUID
+		if err := rows.Scan(&vote.PollID, &vote.Question, &optionID, &vote.OptionText, &vote.WriteIn, &vote.Weight, &vote.VotedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan vote: %w", err)
+		}
+		if optionID.Valid {
+			vote.OptionID = &optionID.UUID
+		}
+		votes = append(votes, vote)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating votes: %w", err)
+	}
+
+	return votes, nil
+}
+
+// RemoveVote deletes a voter's vote, or write-in vote, and takes its weight back off the option's vote count
+// The poll's total_votes is decremented by the delete triggers
+// Returns sql.ErrNoRows when the voter has not voted on the poll
+func (r *PollRepository) RemoveVote(ctx context.Context, pollID uuid.UUID, voterIdentifier string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	deleteQuery := `
+		DELETE FROM votes
+		WHERE poll_id = $1 AND voter_identifier = $2
+		RETURNING option_id, weight`
+
+	var optionID uuid.UUID
+	var weight int64
+	err = tx.QueryRowContext(ctx, deleteQuery, pollID, voterIdentifier).Scan(&optionID, &weight)
+	if err == sql.ErrNoRows {
+		return r.removeWriteInVote(ctx, tx, pollID, voterIdentifier)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to remove vote: %w", err)
+	}
+
+	// Decrement option vote count by the vote's weight
+	if r.countMode == IncrementedCounts {
+		updateQuery := `
+			UPDATE poll_options
+			SET vote_count = vote_count - $2
+			WHERE id = $1`
+
+		_, err = tx.ExecContext(ctx, updateQuery, optionID, weight)
+		if err != nil {
+			return fmt.Errorf("failed to update vote count: %w", err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// removeWriteInVote deletes a voter's write-in vote and commits tx; write-ins have no option count to update
+// Returns sql.ErrNoRows when the voter has no write-in vote on the poll
+func (r *PollRepository) removeWriteInVote(ctx context.Context, tx *sql.Tx, pollID uuid.UUID, voterIdentifier string) error {
+	result, err := tx.ExecContext(ctx, `DELETE FROM write_in_votes WHERE poll_id = $1 AND voter_identifier = $2`, pollID, voterIdentifier)
+	if err != nil {
+		return fmt.Errorf("failed to remove write-in vote: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+
+	return tx.Commit()
+}
+
+// UpdateOptionTexts sets the text of each given option of a poll, as long as the poll has no votes
+// The poll row is locked first so no vote can be counted between the check and the update
+// Returns sql.ErrNoRows when the poll does not exist and ErrPollHasVotes once it has votes
+func (r *PollRepository) UpdateOptionTexts(ctx context.Context, pollID uuid.UUID, options []models.PollOption) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	lockQuery := `
+		SELECT total_votes
+		FROM polls
+		WHERE id = $1
+		FOR UPDATE`
+
+	var totalVotes int64
+	err = tx.QueryRowContext(ctx, lockQuery, pollID).Scan(&totalVotes)
+	if err == sql.ErrNoRows {
+		return sql.ErrNoRows
+	}
+	if err != nil {
+		return fmt.Errorf("failed to lock poll: %w", err)
+	}
+	if totalVotes > 0 {
+		return ErrPollHasVotes
+	}
+
+	updateQuery := `
+		UPDATE poll_options
+		SET option_text = $3
+		WHERE id = $1 AND poll_id = $2`
+
+	for _, opt := range options {
+		if _, err := tx.ExecContext(ctx, updateQuery, opt.ID, pollID, opt.OptionText); err != nil {
+			return fmt.Errorf("failed to update option: %w", err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// DeletePoll soft deletes a poll
+// A poll.closed event is written to the outbox in the same transaction
+// Returns sql.ErrNoRows when the poll does not exist
+func (r *PollRepository) DeletePoll(ctx context.Context, id uuid.UUID) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `
+		UPDATE polls
+		SET is_active = false, closed_at = COALESCE(closed_at, NOW()), deleted_at = COALESCE(deleted_at, NOW())
+		WHERE id = $1`
+
+	result, err := tx.ExecContext(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete poll: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+
+	if err := writeOutboxEvent(ctx, tx, r.dialect, models.EventPollClosed, id, nil); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// DeletePolls soft deletes every poll matching filter in one transaction, as DeletePoll does
+// Polls already deleted are skipped. Returns the IDs of the deleted polls; an empty filter deletes nothing.
+func (r *PollRepository) DeletePolls(ctx context.Context, filter models.PollFilter) ([]uuid.UUID, error) {
+	// Only the filters below reach the query, each as a bound parameter
+	conditions := []string{"deleted_at IS NULL"}
+	var args []any
+	if filter.IDs != nil {
+		idStrings := make([]string, len(filter.IDs))
+		for i, id := range filter.IDs {
+			idStrings[i] = id.String()
+		}
+		args = append(args, pq.Array(idStrings))
+		conditions = append(conditions, fmt.Sprintf("id = ANY($%d::uuid[])", len(args)))
+	}
+	if filter.OwnerID != nil {
+		args = append(args, *filter.OwnerID)
+		conditions = append(conditions, fmt.Sprintf("owner_id = $%d", len(args)))
+	}
+	if filter.CreatedBefore != nil {
+		args = append(args, *filter.CreatedBefore)
+		conditions = append(conditions, fmt.Sprintf("created_at < $%d", len(args)))
+	}
+	if len(args) == 0 {
+		return nil, nil
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `
+		UPDATE polls
+		SET is_active = false, closed_at = COALESCE(closed_at, NOW()), deleted_at = NOW()
+		WHERE ` + strings.Join(conditions, " AND ") + `
+		RETURNING id`
+
+	rows, err := tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete polls: %w", err)
+	}
+	var ids []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan poll id: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating deleted polls: %w", err)
+	}
+
+	for _, id := range ids {
+		if err := writeOutboxEvent(ctx, tx, r.dialect, models.EventPollClosed, id, nil); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return ids, nil
+}
+
+// GetTotalPollsCount returns the total number of polls
+func (r *PollRepository) GetTotalPollsCount(ctx context.Context, activeOnly bool) (int64, error) {
+	query := fmt.Sprintf(`
+		SELECT COUNT(*)
+		FROM polls
+		WHERE ($1 = false OR (is_active = true AND (expires_at IS NULL OR %s)))`, r.dialect.inFuture("expires_at"))
+
+	var count int64
+	err := r.db.QueryRowContext(ctx, r.dialect.bind(query), activeOnly).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count polls: %w", err)
+	}
+
+	return count, nil
+}
+
+// CountActivePollsByOwner returns the number of active, unexpired polls created by an owner
+func (r *PollRepository) CountActivePollsByOwner(ctx context.Context, ownerID string) (int64, error) {
+	query := `
+		SELECT COUNT(*)
+		FROM polls
+		WHERE owner_id = $1
+		  AND is_active = true
+		  AND (expires_at IS NULL OR expires_at > NOW())`
+
+	var count int64
+	err := r.db.QueryRowContext(ctx, query, ownerID).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count polls by owner: %w", err)
+	}
+
+	return count, nil
+}
+
+// ListActiveByOwnerExcluding retrieves up to limit active, unexpired polls created by an owner
+// with their options, leaving out excludePollID; polls are in listing order
+func (r *PollRepository) ListActiveByOwnerExcluding(ctx context.Context, ownerID string, excludePollID uuid.UUID, limit int) ([]models.PollWithOptions, error) {
+	query := fmt.Sprintf(`
+		SELECT 
+			%s,
+			po.id, po.poll_id, po.option_text, %s, po.position, po.created_at
+		FROM (
+			SELECT *
+			FROM polls
+			WHERE owner_id = $1
+			  AND id <> $2
+			  AND is_active = true
+			  AND (expires_at IS NULL OR expires_at > NOW())
+			ORDER BY %s
+			LIMIT $3
+		) p
+		LEFT JOIN poll_options po ON p.id = po.poll_id
+		ORDER BY %s, po.position ASC`, r.selectPolls("p", "votes", "write_in_votes"), r.optionVoteCount("votes"),
+		r.order.orderBy(""), r.order.orderBy("p"))
+
+	rows, err := r.db.QueryContext(ctx, query, ownerID, excludePollID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query polls by owner: %w", err)
+	}
+	defer rows.Close()
+
+	result := []models.PollWithOptions{}
+	err = scanPollsWithOptions(rows, func(poll models.PollWithOptions) error {
+		result = append(result, poll)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// SetFeatured pins a poll to the featured listing at rank, or removes it when featured is false
+// A poll removed from the listing loses its rank. Returns sql.ErrNoRows when the poll does not exist or was deleted.
+func (r *PollRepository) SetFeatured(ctx context.Context, id uuid.UUID, featured bool, rank *int) error {
+	if !featured {
+		rank = nil
+	}
+	query := `
+		UPDATE polls
+		SET featured = $2, featured_rank = $3
+		WHERE id = $1 AND deleted_at IS NULL`
+
+	result, err := r.db.ExecContext(ctx, r.dialect.bind(query), id, featured, rank)
+	if err != nil {
+		return fmt.Errorf("failed to set featured: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// ListFeaturedPolls retrieves up to limit active, unexpired featured polls with their options
+// Polls are ordered by rank, lowest first, then unranked polls; polls of equal rank are listed newest first
+func (r *PollRepository) ListFeaturedPolls(ctx context.Context, limit int) ([]models.PollWithOptions, error) {
+	query := fmt.Sprintf(`
+		SELECT
+			%s,
+			po.id, po.poll_id, po.option_text, %s, po.position, po.created_at
+		FROM (
+			SELECT *
+			FROM polls
+			WHERE featured = true
+			  AND is_active = true
+			  AND (expires_at IS NULL OR %s)
+			ORDER BY %s
+			LIMIT $1
+		) p
+		LEFT JOIN poll_options po ON p.id = po.poll_id
+		ORDER BY %s, po.position ASC`, r.selectPolls("p", "votes", "write_in_votes"), r.optionVoteCount("votes"),
+		r.dialect.inFuture("expires_at"), featuredOrder(""), featuredOrder("p"))
+
+	rows, err := r.db.QueryContext(ctx, r.dialect.bind(query), limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query featured polls: %w", err)
+	}
+	defer rows.Close()
+
+	result := []models.PollWithOptions{}
+	err = scanPollsWithOptions(rows, func(poll models.PollWithOptions) error {
+		result = append(result, poll)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// featuredOrder renders the ORDER BY keys of the featured listing, qualified with alias when given
+func featuredOrder(alias string) string {
+	if alias != "" {
+		alias += "."
+	}
+	return fmt.Sprintf("%[1]sfeatured_rank IS NULL, %[1]sfeatured_rank ASC, %[1]screated_at DESC, %[1]sid DESC", alias)
+}
+
+// SetMetadata replaces a poll's metadata; empty metadata removes it
+// Returns sql.ErrNoRows when the poll does not exist or was deleted
+func (r *PollRepository) SetMetadata(ctx context.Context, id uuid.UUID, metadata json.RawMessage) error {
+	query := `
+		UPDATE polls
+		SET metadata = $2
+		WHERE id = $1 AND deleted_at IS NULL`
+
+	result, err := r.db.ExecContext(ctx, r.dialect.bind(query), id, jsonParam(metadata))
+	if err != nil {
+		return fmt.Errorf("failed to set metadata: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// ListPollsByMetadata retrieves a page of the polls whose metadata has the string value under key,
+// with their options, in listing order. Deleted polls are left out.
+func (r *PollRepository) ListPollsByMetadata(ctx context.Context, key, value string, limit, offset int) ([]models.PollWithOptions, error) {
+	query := fmt.Sprintf(`
+		SELECT
+			%s,
+			po.id, po.poll_id, po.option_text, %s, po.position, po.created_at
+		FROM (
+			SELECT *
+			FROM polls
+			WHERE deleted_at IS NULL
+			  AND %s
+			ORDER BY %s
+			LIMIT $3 OFFSET $4
+		) p
+		LEFT JOIN poll_options po ON p.id = po.poll_id
+		ORDER BY %s, po.position ASC`, r.selectPolls("p", "votes", "write_in_votes"), r.optionVoteCount("votes"),
+		r.dialect.metadataMatches("metadata", "$1", "$2"), r.order.orderBy(""), r.order.orderBy("p"))
+
+	rows, err := r.db.QueryContext(ctx, r.dialect.bind(query), key, value, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query polls by metadata: %w", err)
+	}
+	defer rows.Close()
+
+	result := []models.PollWithOptions{}
+	err = scanPollsWithOptions(rows, func(poll models.PollWithOptions) error {
+		result = append(result, poll)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// ExpireNow sets a poll's expiry to the current time, so it behaves as if it had expired naturally
+// The poll stays active until DeactivateExpired closes it. Returns the new expiry, or sql.ErrNoRows
+// when the poll does not exist.
+func (r *PollRepository) ExpireNow(ctx context.Context, id uuid.UUID) (time.Time, error) {
+	query := `
+		UPDATE polls
+		SET expires_at = NOW()
+		WHERE id = $1
+		RETURNING expires_at`
+
+	var expiresAt time.Time
+	err := r.db.QueryRowContext(ctx, query, id).Scan(&expiresAt)
+	if err == sql.ErrNoRows {
+		return time.Time{}, sql.ErrNoRows
+	}
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to expire poll: %w", err)
+	}
+
+	return expiresAt, nil
+}
+
+// DeactivateExpired marks all active polls whose expiry has passed as inactive,
+// writing a poll.closed event for each to the outbox in the same transaction
+// Returns the IDs of the polls closed; safe to call repeatedly
+func (r *PollRepository) DeactivateExpired(ctx context.Context) ([]uuid.UUID, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `
+		UPDATE polls
+		SET is_active = false, closed_at = NOW()
+		WHERE is_active = true
+		  AND expires_at IS NOT NULL
+		  AND expires_at <= NOW()
+		RETURNING id`
+
+	rows, err := tx.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to deactivate expired polls: %w", err)
+	}
+	var ids []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan poll id: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating expired polls: %w", err)
+	}
+
+	for _, id := range ids {
+		if err := writeOutboxEvent(ctx, tx, r.dialect, models.EventPollClosed, id, nil); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return ids, nil
+}
+
+// GetVoteTimeline counts votes per option, grouped into buckets of the given width
+// Buckets are aligned to the Unix epoch and returned oldest first; empty buckets are omitted
+func (r *PollRepository) GetVoteTimeline(ctx context.Context, pollID uuid.UUID, bucket time.Duration) ([]models.TimelineBucket, error) {
+	query := `
+		SELECT to_timestamp(floor(extract(epoch FROM v.voted_at) / $2) * $2) AS bucket_start,
+		       v.option_id,
+		       COUNT(*)
+		FROM votes v
+		JOIN poll_options po ON po.id = v.option_id
+		WHERE v.poll_id = $1
+		GROUP BY bucket_start, v.option_id, po.position
+		ORDER BY bucket_start, po.position`
+
+	rows, err := r.db.QueryContext(ctx, query, pollID, int64(bucket.Seconds()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get vote timeline: %w", err)
+	}
+	defer rows.Close()
+
+	var buckets []models.TimelineBucket
+	for rows.Next() {
+		var start time.Time
+		var count models.OptionVoteCount
+		if err := rows.Scan(&start, &count.OptionID, &count.Votes); err != nil {
+			return nil, fmt.Errorf("failed to scan timeline row: %w", err)
+		}
+
+		// Rows are ordered by bucket, so a new start time opens a new bucket
+		if n := len(buckets); n == 0 || !buckets[n-1].Start.Equal(start) {
+			buckets = append(buckets, models.TimelineBucket{Start: start.UTC()})
+		}
+		last := &buckets[len(buckets)-1]
+		last.Options = append(last.Options, count)
+	}
+
+	return buckets, rows.Err()
+}
+
+// ListSubnetClusters counts a poll's votes, write-ins included, per IPv4 /24 subnet of the voter identifier
+// and returns the subnets with at least minVotes, most votes first, each listing up to maxVoters voters as they were recorded.
+// Identifiers keeping a peer address's port or a whole X-Forwarded-For list are grouped by the client address they start with.
+// Voters identified otherwise, e.g. authenticated users or hashed anonymous voters, cannot be grouped and are ignored.
+func (r *PollRepository) ListSubnetClusters(ctx context.Context, pollID uuid.UUID, minVotes int64, maxVoters int) ([]models.SubnetCluster, error) {
+	query := `
+		WITH poll_voters AS (
+			SELECT voter_identifier FROM votes WHERE poll_id = $1
+			UNION ALL
+			SELECT voter_identifier FROM write_in_votes WHERE poll_id = $1
+		), prefixed AS (
+			SELECT voter_identifier,
+			       substring(split_part(voter_identifier, ',', 1) FROM '^\s*([0-9]{1,3}\.[0-9]{1,3}\.[0-9]{1,3})\.[0-9]{1,3}(?::[0-9]+)?\s*$') AS prefix
+			FROM poll_voters
+		)
+		SELECT prefix || '.0/24' AS subnet,
+		       COUNT(*) AS votes,
+		       (array_agg(voter_identifier ORDER BY voter_identifier COLLATE "C"))[1:$3]
+		FROM prefixed
+		WHERE prefix IS NOT NULL
+		GROUP BY prefix
+		HAVING COUNT(*) >= $2
+		ORDER BY votes DESC, subnet`
+
+	rows, err := r.db.QueryContext(ctx, query, pollID, minVotes, maxVoters)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list subnet clusters: %w", err)
+	}
+	defer rows.Close()
+
+	clusters := []models.SubnetCluster{}
+	for rows.Next() {
+		var cluster models.SubnetCluster
+		if err := rows.Scan(&cluster.Subnet, &cluster.Votes, pq.Array(&cluster.Voters)); err != nil {
+			return nil, fmt.Errorf("failed to scan subnet cluster: %w", err)
+		}
+		clusters = append(clusters, cluster)
+	}
+
+	return clusters, rows.Err()
+}
+
+// ListVoteBursts counts a poll's votes, write-ins included, in windows of the given width aligned to the Unix epoch
+// and returns the windows with at least minVotes, oldest first, each listing up to maxVoters voters
+func (r *PollRepository) ListVoteBursts(ctx context.Context, pollID uuid.UUID, window time.Duration, minVotes int64, maxVoters int) ([]models.VoteBurst, error) {
+	query := `
+		WITH poll_votes AS (
+			SELECT voter_identifier, voted_at FROM votes WHERE poll_id = $1
+			UNION ALL
+			SELECT voter_identifier, voted_at FROM write_in_votes WHERE poll_id = $1
+		)
+		SELECT to_timestamp(floor(extract(epoch FROM voted_at) / $2) * $2) AS window_start,
+		       COUNT(*) AS votes,
+		       (array_agg(voter_identifier ORDER BY voted_at, voter_identifier COLLATE "C"))[1:$4]
+		FROM poll_votes
+		GROUP BY window_start
+		HAVING COUNT(*) >= $3
+		ORDER BY window_start`
+
+	rows, err := r.db.QueryContext(ctx, query, pollID, int64(window.Seconds()), minVotes, maxVoters)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list vote bursts: %w", err)
+	}
+	defer rows.Close()
+
+	bursts := []models.VoteBurst{}
+	for rows.Next() {
+		var burst models.VoteBurst
+		if err := rows.Scan(&burst.Start, &burst.Votes, pq.Array(&burst.Voters)); err != nil {
+			return nil, fmt.Errorf("failed to scan vote burst: %w", err)
+		}
+		burst.Start = burst.Start.UTC()
+		burst.End = burst.Start.Add(window)
+		bursts = append(bursts, burst)
+	}
+
+	return bursts, rows.Err()
+}
+
+// GetPeakVoteRate counts a poll's votes, write-ins included, in buckets of the given width aligned to the Unix epoch
+// and returns the bucket with the most votes, the earliest on ties; nil when the poll has no votes
+func (r *PollRepository) GetPeakVoteRate(ctx context.Context, pollID uuid.UUID, bucket time.Duration) (*models.VoteRatePeak, error) {
+	query := `
+		WITH poll_votes AS (
+			SELECT voted_at FROM votes WHERE poll_id = $1
+			UNION ALL
+			SELECT voted_at FROM write_in_votes WHERE poll_id = $1
+		)
+		SELECT to_timestamp(floor(extract(epoch FROM voted_at) / $2) * $2) AS bucket_start,
+		       COUNT(*) AS votes
+		FROM poll_votes
+		GROUP BY bucket_start
+		ORDER BY votes DESC, bucket_start
+		LIMIT 1`
+
+	var peak models.VoteRatePeak
+	err := r.db.QueryRowContext(ctx, query, pollID, int64(bucket.Seconds())).Scan(&peak.Start, &peak.Votes)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get peak vote rate: %w", err)
+	}
+	peak.Start = peak.Start.UTC()
+	peak.End = peak.Start.Add(bucket)
+	return &peak, nil
+}
+
+// ImportVotes inserts the votes produced by next in a single transaction, until next returns io.EOF
+// Votes from voters who already voted on the poll are skipped; any other error, including
+// ErrPollFull when the votes would take a poll past its max_votes, rolls back the import
+func (r *PollRepository) ImportVotes(ctx context.Context, next func() (*models.Vote, error)) (imported, skipped int64, err error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Voters who wrote in an answer have voted too
+	insertStmt, err := tx.PrepareContext(ctx, `
+		INSERT INTO votes (poll_id, option_id, voter_identifier, weight, voted_at)
+		SELECT $1::uuid, $2::uuid, $3, $4::bigint, $5::timestamptz
+		WHERE NOT EXISTS (SELECT 1 FROM write_in_votes WHERE poll_id = $1 AND voter_identifier = $3)
+		ON CONFLICT ON CONSTRAINT unique_voter_per_poll DO NOTHING
+		RETURNING id`)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to prepare vote insert: %w", err)
+	}
+	defer insertStmt.Close()
+
+	updateStmt, err := tx.PrepareContext(ctx, `
+		UPDATE poll_options
+		SET vote_count = vote_count + $2
+		WHERE id = $1`)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to prepare vote count update: %w", err)
+	}
+	defer updateStmt.Close()
+
+	for {
+		vote, err := next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return 0, 0, err
+		}
+
+		if vote.Weight == 0 {
+			vote.Weight = 1
+		}
+
+		err = insertStmt.QueryRowContext(ctx,
+			vote.PollID,
+			vote.OptionID,
+			vote.VoterIdentifier,
+			vote.Weight,
+			vote.VotedAt,
+		).Scan(&vote.ID)
+		if err == sql.ErrNoRows {
+			skipped++
+			continue
+		}
+		if capacityErr := mapCapacityError(err); capacityErr != nil {
+			return 0, 0, capacityErr
+		}
+		if err != nil {
+			return 0, 0, fmt.Errorf("failed to import vote: %w", err)
+		}
+
+		if r.countMode == IncrementedCounts {
+			if _, err := updateStmt.ExecContext(ctx, vote.OptionID, vote.Weight); err != nil {
+				return 0, 0, fmt.Errorf("failed to update vote count: %w", err)
+			}
+		}
+		imported++
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, 0, fmt.Errorf("failed to commit import: %w", err)
+	}
+
+	return imported, skipped, nil
+}
+
+// ArchiveClosedPolls moves up to limit polls closed before closedBefore, with their options and votes,
+// to the archive tables. Polls closed without a recorded time count from their expiry or creation.
+// Polls locked by another transaction are left for the next call. Returns the IDs of the polls moved.
+func (r *PollRepository) ArchiveClosedPolls(ctx context.Context, closedBefore time.Time, limit int) ([]uuid.UUID, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	selectQuery := `
+		SELECT id
+		FROM polls
+		WHERE is_active = false
+		  AND COALESCE(closed_at, expires_at, created_at) <= $1
+		ORDER BY COALESCE(closed_at, expires_at, created_at)
+		LIMIT $2
+		FOR UPDATE SKIP LOCKED`
+
+	rows, err := tx.QueryContext(ctx, selectQuery, closedBefore, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to select polls to archive: %w", err)
+	}
+	var ids []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan poll id: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to select polls to archive: %w", err)
+	}
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	idStrings := make([]string, len(ids))
+	for i, id := range ids {
+		idStrings[i] = id.String()
+	}
+
+	// Deleting the polls cascades to their options, votes, write-ins, webhooks and allowed voters
+	statements := []struct {
+		query  string
+		action string
+	}{
+		{`INSERT INTO polls_archive SELECT p.*, NOW() FROM polls p WHERE p.id = ANY($1::uuid[])`, "archive polls"},
+		{`INSERT INTO poll_options_archive SELECT * FROM poll_options WHERE poll_id = ANY($1::uuid[])`, "archive options"},
+		{`INSERT INTO votes_archive SELECT * FROM votes WHERE poll_id = ANY($1::uuid[])`, "archive votes"},
+		{`INSERT INTO write_in_votes_archive SELECT * FROM write_in_votes WHERE poll_id = ANY($1::uuid[])`, "archive write-in votes"},
+		{`DELETE FROM polls WHERE id = ANY($1::uuid[])`, "delete archived polls"},
+	}
+	for _, stmt := range statements {
+		if _, err := tx.ExecContext(ctx, stmt.query, pq.Array(idStrings)); err != nil {
+			return nil, fmt.Errorf("failed to %s: %w", stmt.action, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit archival: %w", err)
+	}
+
+	return ids, nil
+}
+
+// AddAllowedVoters adds voters to a poll's allowlist, skipping those already on it
+// Returns the number of voters added
+func (r *PollRepository) AddAllowedVoters(ctx context.Context, pollID uuid.UUID, voterIdentifiers []string) (int64, error) {
+	query := `
+		INSERT INTO poll_allowed_voters (poll_id, voter_identifier)
+		SELECT $1, unnest($2::text[])
+		ON CONFLICT (poll_id, voter_identifier) DO NOTHING`
+
+	result, err := r.db.ExecContext(ctx, query, pollID, pq.Array(voterIdentifiers))
+	if err != nil {
+		return 0, fmt.Errorf("failed to add allowed voters: %w", err)
+	}
+
+	added, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return added, nil
+}
+
+// RemoveAllowedVoter removes a voter from a poll's allowlist, reporting whether they were on it
+// A vote the voter has already cast is kept
+func (r *PollRepository) RemoveAllowedVoter(ctx context.Context, pollID uuid.UUID, voterIdentifier string) (bool, error) {
+	query := `
+		DELETE FROM poll_allowed_voters
+		WHERE poll_id = $1 AND voter_identifier = $2`
+
+	result, err := r.db.ExecContext(ctx, query, pollID, voterIdentifier)
+	if err != nil {
+		return false, fmt.Errorf("failed to remove allowed voter: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return rows > 0, nil
+}
+
+// ListAllowedVoters retrieves a poll's allowlist in the order voters were added
+func (r *PollRepository) ListAllowedVoters(ctx context.Context, pollID uuid.UUID) ([]models.AllowedVoter, error) {
+	query := `
+		SELECT voter_identifier, added_at
+		FROM poll_allowed_voters
+		WHERE poll_id = $1
+		ORDER BY added_at ASC, voter_identifier ASC`
+
+	rows, err := r.db.QueryContext(ctx, query, pollID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query allowed voters: %w", err)
+	}
+	defer rows.Close()
+
+	voters := []models.AllowedVoter{}
+	for rows.Next() {
+		var voter models.AllowedVoter
+		if err := rows.Scan(&voter.VoterIdentifier, &voter.AddedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan allowed voter: %w", err)
+		}
+		voters = append(voters, voter)
+	}
+
+	return voters, rows.Err()
+}
+
+// IsVoterAllowed checks if a voter is on a poll's allowlist
+func (r *PollRepository) IsVoterAllowed(ctx context.Context, pollID uuid.UUID, voterIdentifier string) (bool, error) {
+	query := `
+		SELECT EXISTS (
+			SELECT 1 FROM poll_allowed_voters
+			WHERE poll_id = $1 AND voter_identifier = $2
+		)`
+
+	var allowed bool
+	if err := r.db.QueryRowContext(ctx, query, pollID, voterIdentifier).Scan(&allowed); err != nil {
+		return false, fmt.Errorf("failed to check allowed voter: %w", err)
+	}
+
+	return allowed, nil
+}
+
+// ExportAll passes every poll, then every option, vote, write-in vote and allowed voter to fn, in bundle order
+// The reads share one read-only snapshot, so votes never reference options missing from the export.
+func (r *PollRepository) ExportAll(ctx context.Context, fn func(models.BackupRecord) error) error {
+	tx, err := r.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	exports := []struct {
+		entity string
+		query  string
+		scan   func(rows *sql.Rows) (models.BackupRecord, error)
+	}{
+		{"polls", `
+			SELECT id, question, description, created_at, expires_at, is_active, owner_id, allow_weighted,
+			       require_confirmation, quiz_mode, poll_group, randomize_options, allowlist_only, allow_write_in, max_votes,
+			       voting_window_start, voting_window_end, timezone, acknowledgement_mode, featured, featured_rank, metadata, closed_at, deleted_at
+			FROM polls
+			ORDER BY created_at, id`,
+			func(rows *sql.Rows) (models.BackupRecord, error) {
+				var p models.BackupPoll
+				err := rows.Scan(&p.ID, &p.Question, &p.Description, &p.CreatedAt, &p.ExpiresAt, &p.IsActive, &p.OwnerID,
+					&p.AllowWeighted, &p.RequireConfirmation, &p.QuizMode, &p.Group, &p.RandomizeOptions, &p.AllowlistOnly, &p.AllowWriteIn, &p.MaxVotes,
+					&p.VotingWindowStart, &p.VotingWindowEnd, &p.Timezone, &p.AcknowledgementMode, &p.Featured, &p.FeaturedRank, jsonColumn{&p.Metadata}, &p.ClosedAt, &p.DeletedAt)
+				return models.BackupRecord{Type: models.BackupRecordPoll, Poll: &p}, err
+			}},
+		{"options", `
+			SELECT id, poll_id, option_text, position, is_correct, created_at
+			FROM poll_options
+			ORDER BY poll_id, position`,
+			func(rows *sql.Rows) (models.BackupRecord, error) {
+				var o models.BackupOption
+				err := rows.Scan(&o.ID, &o.PollID, &o.OptionText, &o.Position, &o.IsCorrect, &o.CreatedAt)
+				return models.BackupRecord{Type: models.BackupRecordOption, Option: &o}, err
+			}},
+		{"votes", `
+			SELECT id, poll_id, option_id, voter_identifier, weight, campaign, voted_at
+			FROM votes
+			ORDER BY voted_at, id`,
+			func(rows *sql.Rows) (models.BackupRecord, error) {
+				var v models.BackupVote
+				err := rows.Scan(&v.ID, &v.PollID, &v.OptionID, &v.VoterIdentifier, &v.Weight, &v.Campaign, &v.VotedAt)
+				return models.BackupRecord{Type: models.BackupRecordVote, Vote: &v}, err
+			}},
+		{"write-in votes", `
+			SELECT id, poll_id, voter_identifier, write_in_text, weight, campaign, voted_at
+			FROM write_in_votes
+			ORDER BY voted_at, id`,
+			func(rows *sql.Rows) (models.BackupRecord, error) {
+				var w models.BackupWriteInVote
+				err := rows.Scan(&w.ID, &w.PollID, &w.VoterIdentifier, &w.WriteIn, &w.Weight, &w.Campaign, &w.VotedAt)
+				return models.BackupRecord{Type: models.BackupRecordWriteInVote, WriteInVote: &w}, err
+			}},
+		{"allowed voters", `
+			SELECT poll_id, voter_identifier, added_at
+			FROM poll_allowed_voters
+			ORDER BY poll_id, added_at, voter_identifier`,
+			func(rows *sql.Rows) (models.BackupRecord, error) {
+				var a models.BackupAllowedVoter
+				err := rows.Scan(&a.PollID, &a.VoterIdentifier, &a.AddedAt)
+				return models.BackupRecord{Type: models.BackupRecordAllowedVoter, AllowedVoter: &a}, err
+			}},
+	}
+
+	for _, export := range exports {
+		if err := exportRows(ctx, tx, export.query, export.scan, fn); err != nil {
+			return fmt.Errorf("failed to export %s: %w", export.entity, err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// exportRows runs query and passes each row, converted by scan, to fn
+func exportRows(ctx context.Context, tx *sql.Tx, query string, scan func(*sql.Rows) (models.BackupRecord, error), fn func(models.BackupRecord) error) error {
+	rows, err := tx.QueryContext(ctx, query)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		record, err := scan(rows)
+		if err != nil {
+			return err
+		}
+		if err := fn(record); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
+// ImportAll restores the records produced by next, until it returns io.EOF, into an empty database
+// Stored IDs are kept, so votes keep referencing their options. Everything is restored in one
+// transaction: the option count check on each poll runs at commit, so a poll cannot be committed
+// before its options. Vote totals and counts are recounted from the restored votes.
+// Returns ErrRestoreTargetNotEmpty if the database, including the archive, already holds polls.
+func (r *PollRepository) ImportAll(ctx context.Context, next func() (*models.BackupRecord, error)) (*models.BackupSummary, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Keeps polls from being created while the restore checks the database is empty and runs
+	if _, err := tx.ExecContext(ctx, `LOCK TABLE polls IN SHARE ROW EXCLUSIVE MODE`); err != nil {
+		return nil, fmt.Errorf("failed to lock polls: %w", err)
+	}
+
+	var populated bool
+	err = tx.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM polls) OR EXISTS (SELECT 1 FROM polls_archive)`).Scan(&populated)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check for existing polls: %w", err)
+	}
+	if populated {
+		return nil, ErrRestoreTargetNotEmpty
+	}
+
+	pollStmt, err := tx.PrepareContext(ctx, `
+		INSERT INTO polls (id, question, description, created_at, expires_at, is_active, owner_id, allow_weighted,
+		                   require_confirmation, quiz_mode, poll_group, randomize_options, allowlist_only, allow_write_in, max_votes,
+		                   voting_window_start, voting_window_end, timezone, acknowledgement_mode, featured, featured_rank, metadata, closed_at, deleted_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23, $24)`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare poll insert: %w", err)
+	}
+	defer pollStmt.Close()
+
+	optionStmt, err := tx.PrepareContext(ctx, `
+		INSERT INTO poll_options (id, poll_id, option_text, position, is_correct, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare option insert: %w", err)
+	}
+	defer optionStmt.Close()
+
+	// The vote triggers recount poll totals as votes and write-ins are inserted
+	voteStmt, err := tx.PrepareContext(ctx, `
+		INSERT INTO votes (id, poll_id, option_id, voter_identifier, weight, campaign, voted_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare vote insert: %w", err)
+	}
+	defer voteStmt.Close()
+
+	writeInStmt, err := tx.PrepareContext(ctx, `
+		INSERT INTO write_in_votes (id, poll_id, voter_identifier, write_in_text, weight, campaign, voted_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare write-in vote insert: %w", err)
+	}
+	defer writeInStmt.Close()
+
+	allowedVoterStmt, err := tx.PrepareContext(ctx, `
+		INSERT INTO poll_allowed_voters (poll_id, voter_identifier, added_at)
+		VALUES ($1, $2, $3)`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare allowed voter insert: %w", err)
+	}
+	defer allowedVoterStmt.Close()
+
+	summary := &models.BackupSummary{}
+	for {
+		record, err := next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		switch {
+		case record.Poll != nil:
+			p := record.Poll
+			if _, err := pollStmt.ExecContext(ctx, p.ID, p.Question, p.Description, p.CreatedAt, p.ExpiresAt, p.IsActive, p.OwnerID,
+				p.AllowWeighted, p.RequireConfirmation, p.QuizMode, p.Group, p.RandomizeOptions, p.AllowlistOnly, p.AllowWriteIn, p.MaxVotes,
+				p.VotingWindowStart, p.VotingWindowEnd, p.Timezone, p.AcknowledgementMode, p.Featured, p.FeaturedRank, jsonParam(p.Metadata), p.ClosedAt, p.DeletedAt); err != nil {
+				return nil, fmt.Errorf("failed to restore poll %s: %w", p.ID, err)
+			}
+			summary.Polls++
+		case record.Option != nil:
+			o := record.Option
+			if _, err := optionStmt.ExecContext(ctx, o.ID, o.PollID, o.OptionText, o.Position, o.IsCorrect, o.CreatedAt); err != nil {
+				if countErr := mapOptionCountError(err); countErr != nil {
+					return nil, countErr
+				}
+				return nil, fmt.Errorf("failed to restore option %s: %w", o.ID, err)
+			}
+			summary.Options++
+		case record.Vote != nil:
+			v := record.Vote
+			if _, err := voteStmt.ExecContext(ctx, v.ID, v.PollID, v.OptionID, v.VoterIdentifier, v.Weight, v.Campaign, v.VotedAt); err != nil {
+				return nil, fmt.Errorf("failed to restore vote %s: %w", v.ID, err)
+			}
+			summary.Votes++
+		case record.WriteInVote != nil:
+			w := record.WriteInVote
+			if _, err := writeInStmt.ExecContext(ctx, w.ID, w.PollID, w.VoterIdentifier, w.WriteIn, w.Weight, w.Campaign, w.VotedAt); err != nil {
+				return nil, fmt.Errorf("failed to restore write-in vote %s: %w", w.ID, err)
+			}
+			summary.WriteInVotes++
+		case record.AllowedVoter != nil:
+			a := record.AllowedVoter
+			if _, err := allowedVoterStmt.ExecContext(ctx, a.PollID, a.VoterIdentifier, a.AddedAt); err != nil {
+				return nil, fmt.Errorf("failed to restore allowed voter: %w", err)
+			}
+			summary.AllowedVoters++
+		}
+	}
+
+	recountQuery := `
+		UPDATE poll_options o
+		SET vote_count = v.total
+		FROM (SELECT option_id, SUM(weight) AS total FROM votes GROUP BY option_id) v
+		WHERE o.id = v.option_id`
+	if _, err := tx.ExecContext(ctx, recountQuery); err != nil {
+		return nil, fmt.Errorf("failed to recount option votes: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		if countErr := mapOptionCountError(err); countErr != nil {
+			return nil, countErr
+		}
+		return nil, fmt.Errorf("failed to commit restore: %w", err)
+	}
+
+	return summary, nil
 }
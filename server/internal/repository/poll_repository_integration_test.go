@@ -0,0 +1,527 @@
+//go:build integration
+
+package repository
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/moabdelazem/k8s-app/internal/database"
+	"github.com/moabdelazem/k8s-app/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// integrationEnv reads a connection setting for the integration database
+func integrationEnv(key, fallback string) string {
+	if value, ok := os.LookupEnv(key); ok {
+		return value
+	}
+	return fallback
+}
+
+func newIntegrationRepo(t *testing.T) *PollRepository {
+	t.Helper()
+	db, err := database.NewConnection(&database.Config{
+		Host:            integrationEnv("DB_HOST", "localhost"),
+		Port:            integrationEnv("DB_PORT", "5432"),
+		User:            integrationEnv("DB_USER", "devuser"),
+		Password:        integrationEnv("DB_PASSWORD", "devpassword"),
+		DBName:          integrationEnv("DB_NAME", "k8s_app_dev"),
+		SSLMode:         integrationEnv("DB_SSLMODE", "disable"),
+		MaxOpenConns:    20,
+		MaxIdleConns:    4,
+		ConnMaxLifetime: time.Minute,
+		MaxRetries:      1,
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() { database.Close() })
+	return NewPollRepository(db)
+}
+
+func TestCastVote_MaxVotesHoldsUnderConcurrency_Integration(t *testing.T) {
+	ctx := context.Background()
+	repo := newIntegrationRepo(t)
+
+	maxVotes := int64(5)
+	poll := &models.Poll{Question: "Who is coming to the meetup?", IsActive: true, MaxVotes: &maxVotes}
+	options := []models.PollOption{{OptionText: "Yes"}, {OptionText: "Maybe"}}
+	require.NoError(t, repo.CreatePoll(ctx, poll, options))
+	t.Cleanup(func() { repo.db.ExecContext(context.Background(), `DELETE FROM polls WHERE id = $1`, poll.ID) })
+
+	const voters = 25
+	var cast, full atomic.Int64
+	var wg sync.WaitGroup
+	for i := range voters {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := repo.CastVote(ctx, &models.Vote{
+				PollID:          poll.ID,
+				OptionID:        options[i%len(options)].ID,
+				VoterIdentifier: fmt.Sprintf("voter-%d", i),
+			})
+			switch {
+			case err == nil:
+				cast.Add(1)
+			case errors.Is(err, ErrPollFull):
+				full.Add(1)
+			default:
+				t.Errorf("unexpected vote error: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, maxVotes, cast.Load())
+	assert.Equal(t, voters-maxVotes, full.Load())
+
+	stored, err := repo.GetPollByID(ctx, poll.ID)
+	require.NoError(t, err)
+	assert.Equal(t, maxVotes, stored.TotalVotes, "the cap is never exceeded")
+
+	// Write-ins share the capacity
+	writeIn := "Someone else"
+	err = repo.CastWriteInVote(ctx, &models.Vote{PollID: poll.ID, VoterIdentifier: "voter-late", WriteIn: &writeIn})
+	assert.ErrorIs(t, err, ErrPollFull)
+}
+
+func TestCastVote_MilestoneOnceUnderConcurrency_Integration(t *testing.T) {
+	ctx := context.Background()
+	repo := newIntegrationRepo(t).WithMilestones([]int64{10})
+
+	poll := &models.Poll{Question: "Who is coming to the meetup?", IsActive: true}
+	options := []models.PollOption{{OptionText: "Yes"}, {OptionText: "Maybe"}}
+	require.NoError(t, repo.CreatePoll(ctx, poll, options))
+	t.Cleanup(func() {
+		repo.db.ExecContext(context.Background(), `DELETE FROM outbox_events WHERE poll_id = $1`, poll.ID)
+		repo.db.ExecContext(context.Background(), `DELETE FROM polls WHERE id = $1`, poll.ID)
+	})
+
+	var wg sync.WaitGroup
+	for i := range 25 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := repo.CastVote(ctx, &models.Vote{
+				PollID:          poll.ID,
+				OptionID:        options[i%len(options)].ID,
+				VoterIdentifier: fmt.Sprintf("voter-%d", i),
+			})
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+
+	var events int
+	err := repo.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM outbox_events WHERE poll_id = $1 AND event_type = $2`, poll.ID, models.EventMilestone).Scan(&events)
+	require.NoError(t, err)
+	assert.Equal(t, 1, events, "concurrent votes announce the milestone once")
+}
+
+func TestListPolls_StableOrderForIdenticalTimestamps_Integration(t *testing.T) {
+	ctx := context.Background()
+	repo := newIntegrationRepo(t)
+
+	for name, tc := range map[string]struct {
+		order   ListOrder
+		batchAt time.Time // Sorts the batch ahead of any other poll in the database
+	}{
+		"newest first": {order: NewestFirst, batchAt: time.Date(2999, time.January, 1, 0, 0, 0, 0, time.UTC)},
+		"oldest first": {order: OldestFirst, batchAt: time.Date(1999, time.January, 1, 0, 0, 0, 0, time.UTC)},
+	} {
+		t.Run(name, func(t *testing.T) {
+			repo.WithListOrder(tc.order)
+
+			// A batch import creates every poll at the same instant
+			var ids []uuid.UUID
+			for range 7 {
+				poll := &models.Poll{Question: "Imported poll?", IsActive: true}
+				require.NoError(t, repo.CreatePoll(ctx, poll, []models.PollOption{{OptionText: "Yes"}, {OptionText: "No"}}))
+				ids = append(ids, poll.ID)
+				t.Cleanup(func() { repo.db.ExecContext(context.Background(), `DELETE FROM polls WHERE id = $1`, poll.ID) })
+			}
+			_, err := repo.db.ExecContext(ctx, `UPDATE polls SET created_at = $1 WHERE id = ANY($2::uuid[])`, tc.batchAt, pq.Array(ids))
+			require.NoError(t, err)
+
+			slices.SortFunc(ids, func(a, b uuid.UUID) int { return bytes.Compare(a[:], b[:]) })
+			if tc.order == NewestFirst {
+				slices.Reverse(ids)
+			}
+
+			// Walking the pages yields every poll exactly once, in ID order
+			var paged []uuid.UUID
+			for offset := 0; offset < len(ids); offset += 3 {
+				page, err := repo.ListPollsWithOptions(ctx, min(3, len(ids)-offset), offset, false)
+				require.NoError(t, err)
+				for _, poll := range page {
+					paged = append(paged, poll.ID)
+				}
+			}
+			assert.Equal(t, ids, paged)
+
+			polls, err := repo.ListPolls(ctx, len(ids), 0, false)
+			require.NoError(t, err)
+			require.Len(t, polls, len(ids))
+			for i, poll := range polls {
+				assert.Equal(t, ids[i], poll.ID)
+			}
+		})
+	}
+}
+
+func TestListActiveByOwnerExcluding_Integration(t *testing.T) {
+	ctx := context.Background()
+	repo := newIntegrationRepo(t)
+	owner, other := "owner-"+uuid.NewString(), "owner-"+uuid.NewString()
+
+	create := func(question string, ownerID *string) *models.Poll {
+		poll := &models.Poll{Question: question, IsActive: true, OwnerID: ownerID}
+		require.NoError(t, repo.CreatePoll(ctx, poll, []models.PollOption{{OptionText: "Yes"}, {OptionText: "No"}}))
+		t.Cleanup(func() { repo.db.ExecContext(context.Background(), `DELETE FROM polls WHERE id = $1`, poll.ID) })
+		return poll
+	}
+	current := create("Current?", &owner)
+	related := create("Related?", &owner)
+	deleted := create("Deleted?", &owner)
+	expired := create("Expired?", &owner)
+	create("Someone else's?", &other)
+	create("Anonymous?", nil)
+
+	require.NoError(t, repo.DeletePoll(ctx, deleted.ID))
+	_, err := repo.ExpireNow(ctx, expired.ID)
+	require.NoError(t, err)
+
+	polls, err := repo.ListActiveByOwnerExcluding(ctx, owner, current.ID, 10)
+	require.NoError(t, err)
+	require.Len(t, polls, 1, "the current, closed and other creators' polls are left out")
+	assert.Equal(t, related.ID, polls[0].ID)
+	assert.Len(t, polls[0].Options, 2)
+
+	stored, err := repo.GetPollByID(ctx, current.ID)
+	require.NoError(t, err)
+	require.NotNil(t, stored.OwnerID)
+	assert.Equal(t, owner, *stored.OwnerID)
+}
+
+func TestListSubnetClusters_Integration(t *testing.T) {
+	ctx := context.Background()
+	repo := newIntegrationRepo(t)
+
+	poll := &models.Poll{Question: "Who is coming to the meetup?", IsActive: true, AllowWriteIn: true}
+	options := []models.PollOption{{OptionText: "Yes"}, {OptionText: "No"}}
+	require.NoError(t, repo.CreatePoll(ctx, poll, options))
+	t.Cleanup(func() { repo.db.ExecContext(context.Background(), `DELETE FROM polls WHERE id = $1`, poll.ID) })
+
+	for _, voter := range []string{"203.0.113.7", "203.0.113.12:51234", "203.0.113.9, 10.0.0.1", "198.51.100.4", "user:alice", "2001:db8::1"} {
+		require.NoError(t, repo.CastVote(ctx, &models.Vote{PollID: poll.ID, OptionID: options[0].ID, VoterIdentifier: voter}))
+	}
+	writeIn := "Maybe"
+	require.NoError(t, repo.CastWriteInVote(ctx, &models.Vote{PollID: poll.ID, WriteIn: &writeIn, VoterIdentifier: "203.0.113.200"}))
+
+	clusters, err := repo.ListSubnetClusters(ctx, poll.ID, 3, 3)
+	require.NoError(t, err)
+	require.Len(t, clusters, 1)
+	assert.Equal(t, "203.0.113.0/24", clusters[0].Subnet)
+	assert.Equal(t, int64(4), clusters[0].Votes, "write-in votes count too")
+	assert.Equal(t, []string{"203.0.113.12:51234", "203.0.113.200", "203.0.113.7"}, clusters[0].Voters, "peer addresses and forwarded lists group by client address")
+}
+
+func TestListVoteBursts_Integration(t *testing.T) {
+	ctx := context.Background()
+	repo := newIntegrationRepo(t)
+
+	poll := &models.Poll{Question: "Who is coming to the meetup?", IsActive: true}
+	options := []models.PollOption{{OptionText: "Yes"}, {OptionText: "No"}}
+	require.NoError(t, repo.CreatePoll(ctx, poll, options))
+	t.Cleanup(func() { repo.db.ExecContext(context.Background(), `DELETE FROM polls WHERE id = $1`, poll.ID) })
+
+	base := time.Date(2025, time.June, 1, 12, 0, 0, 0, time.UTC)
+	var votes []*models.Vote
+	for i, offset := range []time.Duration{10 * time.Second, 20 * time.Second, 50 * time.Second, 90 * time.Second} {
+		votes = append(votes, &models.Vote{PollID: poll.ID, OptionID: options[0].ID, VoterIdentifier: fmt.Sprintf("voter-%d", i), VotedAt: base.Add(offset)})
+	}
+	_, _, err := repo.ImportVotes(ctx, func() (*models.Vote, error) {
+		if len(votes) == 0 {
+			return nil, io.EOF
+		}
+		vote := votes[0]
+		votes = votes[1:]
+		return vote, nil
+	})
+	require.NoError(t, err)
+
+	bursts, err := repo.ListVoteBursts(ctx, poll.ID, time.Minute, 2, 2)
+	require.NoError(t, err)
+	require.Len(t, bursts, 1, "the lone vote at 12:01 is not a burst")
+	assert.True(t, base.Equal(bursts[0].Start))
+	assert.Equal(t, int64(3), bursts[0].Votes)
+	assert.Equal(t, []string{"voter-0", "voter-1"}, bursts[0].Voters)
+}
+
+func TestDeletePoll_RecordsDeletion_Integration(t *testing.T) {
+	ctx := context.Background()
+	repo := newIntegrationRepo(t)
+
+	poll := &models.Poll{Question: "Who is coming to the meetup?", IsActive: true}
+	require.NoError(t, repo.CreatePoll(ctx, poll, []models.PollOption{{OptionText: "Yes"}, {OptionText: "No"}}))
+	t.Cleanup(func() { repo.db.ExecContext(context.Background(), `DELETE FROM polls WHERE id = $1`, poll.ID) })
+
+	stored, err := repo.GetPollByID(ctx, poll.ID)
+	require.NoError(t, err)
+	assert.Nil(t, stored.DeletedAt)
+
+	require.NoError(t, repo.DeletePoll(ctx, poll.ID))
+
+	results, err := repo.GetPollWithResults(ctx, poll.ID, "")
+	require.NoError(t, err)
+	require.NotNil(t, results.DeletedAt)
+	assert.False(t, results.IsActive)
+}
+
+func TestCreatePoll_AcknowledgementOptionCount_Integration(t *testing.T) {
+	ctx := context.Background()
+	repo := newIntegrationRepo(t)
+
+	poll := &models.Poll{Question: "Have you read the notice?", IsActive: true, AcknowledgementMode: true}
+	require.NoError(t, repo.CreatePoll(ctx, poll, []models.PollOption{{OptionText: "I acknowledge"}}))
+	t.Cleanup(func() { repo.db.ExecContext(context.Background(), `DELETE FROM polls WHERE id = $1`, poll.ID) })
+
+	stored, err := repo.GetPollByID(ctx, poll.ID)
+	require.NoError(t, err)
+	assert.True(t, stored.AcknowledgementMode)
+
+	err = repo.CreatePoll(ctx, &models.Poll{Question: "Have you read the notice?", IsActive: true}, []models.PollOption{{OptionText: "I acknowledge"}})
+	assert.ErrorIs(t, err, ErrOptionCountOutOfBounds, "other polls still need two options")
+	err = repo.CreatePoll(ctx, &models.Poll{Question: "Have you read the notice?", IsActive: true, AcknowledgementMode: true},
+		[]models.PollOption{{OptionText: "Yes"}, {OptionText: "No"}})
+	assert.ErrorIs(t, err, ErrOptionCountOutOfBounds)
+}
+
+func TestPollMetadata_Integration(t *testing.T) {
+	ctx := context.Background()
+	repo := newIntegrationRepo(t)
+	// A campaign no other test uses keeps the lookup to this test's polls
+	campaign := uuid.NewString()
+
+	tagged := &models.Poll{Question: "Which campaign banner works?", IsActive: true,
+		Metadata: json.RawMessage(fmt.Sprintf(`{"campaign":%q,"ui":{"color":"teal"}}`, campaign))}
+	nested := &models.Poll{Question: "Which nested banner works?", IsActive: true,
+		Metadata: json.RawMessage(fmt.Sprintf(`{"ui":{"campaign":%q}}`, campaign))}
+	options := []models.PollOption{{OptionText: "Yes"}, {OptionText: "No"}}
+	for _, poll := range []*models.Poll{tagged, nested} {
+		require.NoError(t, repo.CreatePoll(ctx, poll, options))
+		t.Cleanup(func() { repo.db.ExecContext(context.Background(), `DELETE FROM polls WHERE id = $1`, poll.ID) })
+	}
+
+	stored, err := repo.GetPollByID(ctx, tagged.ID)
+	require.NoError(t, err)
+	assert.JSONEq(t, string(tagged.Metadata), string(stored.Metadata))
+
+	matches, err := repo.ListPollsByMetadata(ctx, "campaign", campaign, 10, 0)
+	require.NoError(t, err)
+	require.Len(t, matches, 1, "only top-level keys match")
+	assert.Equal(t, tagged.ID, matches[0].ID)
+
+	require.NoError(t, repo.SetMetadata(ctx, tagged.ID, nil))
+	matches, err = repo.ListPollsByMetadata(ctx, "campaign", campaign, 10, 0)
+	require.NoError(t, err)
+	assert.Empty(t, matches)
+}
+
+func TestGetPeakVoteRate_Integration(t *testing.T) {
+	ctx := context.Background()
+	repo := newIntegrationRepo(t)
+
+	poll := &models.Poll{Question: "Who is coming to the launch?", IsActive: true}
+	options := []models.PollOption{{OptionText: "Yes"}, {OptionText: "No"}}
+	require.NoError(t, repo.CreatePoll(ctx, poll, options))
+	t.Cleanup(func() { repo.db.ExecContext(context.Background(), `DELETE FROM polls WHERE id = $1`, poll.ID) })
+
+	peak, err := repo.GetPeakVoteRate(ctx, poll.ID, time.Second)
+	require.NoError(t, err)
+	assert.Nil(t, peak, "no votes, no peak")
+
+	// Two votes in the first second, then clusters of three two and five seconds later
+	base := time.Date(2025, time.June, 1, 12, 0, 0, 0, time.UTC)
+	offsets := []time.Duration{
+		0, 900 * time.Millisecond,
+		2 * time.Second, 2*time.Second + 100*time.Millisecond, 2*time.Second + 999*time.Millisecond,
+		5 * time.Second, 5*time.Second + 500*time.Millisecond, 5*time.Second + 900*time.Millisecond,
+	}
+	var votes []*models.Vote
+	for i, offset := range offsets {
+		votes = append(votes, &models.Vote{PollID: poll.ID, OptionID: options[i%2].ID, VoterIdentifier: fmt.Sprintf("voter-%d", i), VotedAt: base.Add(offset)})
+	}
+	_, _, err = repo.ImportVotes(ctx, func() (*models.Vote, error) {
+		if len(votes) == 0 {
+			return nil, io.EOF
+		}
+		vote := votes[0]
+		votes = votes[1:]
+		return vote, nil
+	})
+	require.NoError(t, err)
+
+	peak, err = repo.GetPeakVoteRate(ctx, poll.ID, time.Second)
+	require.NoError(t, err)
+	require.NotNil(t, peak)
+	assert.True(t, base.Add(2*time.Second).Equal(peak.Start))
+	assert.True(t, base.Add(3*time.Second).Equal(peak.End))
+	assert.Equal(t, int64(3), peak.Votes, "the earliest of the tied clusters")
+
+	// Every vote falls in one minute bucket
+	peak, err = repo.GetPeakVoteRate(ctx, poll.ID, time.Minute)
+	require.NoError(t, err)
+	require.NotNil(t, peak)
+	assert.True(t, base.Equal(peak.Start))
+	assert.Equal(t, int64(8), peak.Votes)
+}
+
+func TestDeletePolls_Filtered_Integration(t *testing.T) {
+	ctx := context.Background()
+	repo := newIntegrationRepo(t)
+	spammer, other := "user:spammer-"+uuid.NewString(), "user:other-"+uuid.NewString()
+
+	create := func(owner *string) *models.Poll {
+		t.Helper()
+		poll := &models.Poll{Question: "Who is coming to the meetup?", IsActive: true, OwnerID: owner}
+		require.NoError(t, repo.CreatePoll(ctx, poll, []models.PollOption{{OptionText: "Yes"}, {OptionText: "No"}}))
+		t.Cleanup(func() { repo.db.ExecContext(context.Background(), `DELETE FROM polls WHERE id = $1`, poll.ID) })
+		return poll
+	}
+	oldSpam := create(&spammer)
+	otherPoll := create(&other)
+	time.Sleep(time.Millisecond)
+	cutoff := time.Now()
+	time.Sleep(time.Millisecond)
+	newSpam := create(&spammer)
+
+	deleted, err := repo.DeletePolls(ctx, models.PollFilter{OwnerID: &spammer, CreatedBefore: &cutoff})
+	require.NoError(t, err)
+	assert.Equal(t, []uuid.UUID{oldSpam.ID}, deleted, "filters combine with AND")
+
+	deleted, err = repo.DeletePolls(ctx, models.PollFilter{IDs: []uuid.UUID{oldSpam.ID, newSpam.ID}})
+	require.NoError(t, err)
+	assert.Equal(t, []uuid.UUID{newSpam.ID}, deleted, "deleted polls are skipped")
+
+	// A filter value is bound as a parameter, never spliced into the query
+	injected := "' OR '1'='1"
+	deleted, err = repo.DeletePolls(ctx, models.PollFilter{OwnerID: &injected})
+	require.NoError(t, err)
+	assert.Empty(t, deleted)
+
+	stored, err := repo.GetPollByID(ctx, otherPoll.ID)
+	require.NoError(t, err)
+	assert.Nil(t, stored.DeletedAt)
+
+	var events int
+	require.NoError(t, repo.db.QueryRowContext(ctx,
+		`SELECT COUNT(*) FROM outbox_events WHERE event_type = $1 AND poll_id = ANY($2::uuid[])`,
+		models.EventPollClosed, pq.Array([]string{oldSpam.ID.String(), newSpam.ID.String()})).Scan(&events))
+	assert.Equal(t, 2, events)
+}
+
+func TestGetOptionsForPolls_Integration(t *testing.T) {
+	ctx := context.Background()
+	repo := newIntegrationRepo(t)
+
+	create := func(question string, texts ...string) (*models.Poll, []models.PollOption) {
+		poll := &models.Poll{Question: question, IsActive: true}
+		options := make([]models.PollOption, len(texts))
+		for i, text := range texts {
+			options[i] = models.PollOption{OptionText: text}
+		}
+		require.NoError(t, repo.CreatePoll(ctx, poll, options))
+		t.Cleanup(func() { repo.db.ExecContext(context.Background(), `DELETE FROM polls WHERE id = $1`, poll.ID) })
+		return poll, options
+	}
+	first, _ := create("Which day works best?", "Monday", "Tuesday", "Wednesday")
+	second, secondOptions := create("Who is coming to the meetup?", "Yes", "No")
+	require.NoError(t, repo.CastVote(ctx, &models.Vote{PollID: second.ID, OptionID: secondOptions[1].ID, VoterIdentifier: "voter-1"}))
+
+	options, err := repo.GetOptionsForPolls(ctx, []uuid.UUID{first.ID, second.ID, uuid.New()})
+	require.NoError(t, err)
+	require.Len(t, options, 2, "unknown polls are left out")
+
+	texts := func(options []models.PollOption) []string {
+		var out []string
+		for _, opt := range options {
+			out = append(out, opt.OptionText)
+		}
+		return out
+	}
+	assert.Equal(t, []string{"Monday", "Tuesday", "Wednesday"}, texts(options[first.ID]))
+	assert.Equal(t, []string{"Yes", "No"}, texts(options[second.ID]))
+	for _, opt := range options[first.ID] {
+		assert.Equal(t, first.ID, opt.PollID)
+	}
+	assert.Equal(t, int64(1), options[second.ID][1].VoteCount)
+}
+
+func TestDerivedCounts_MatchIncremented_Integration(t *testing.T) {
+	ctx := context.Background()
+	incremented := newIntegrationRepo(t)
+	derived := NewPollRepository(incremented.db).WithCountMode(DerivedCounts)
+	writeIn := "Thursday"
+
+	// Runs the same vote sequence on a new poll of repo, returning the poll as repo reads it back
+	castVotes := func(repo *PollRepository) models.PollWithOptions {
+		poll := &models.Poll{Question: "Ship it on Friday?", IsActive: true, AllowWriteIn: true}
+		options := []models.PollOption{{OptionText: "Yes"}, {OptionText: "No"}}
+		require.NoError(t, repo.CreatePoll(ctx, poll, options))
+		t.Cleanup(func() { repo.db.ExecContext(context.Background(), `DELETE FROM polls WHERE id = $1`, poll.ID) })
+
+		require.NoError(t, repo.CastVote(ctx, &models.Vote{PollID: poll.ID, OptionID: options[0].ID, VoterIdentifier: "voter-1", Weight: 3}))
+		require.NoError(t, repo.CastVote(ctx, &models.Vote{PollID: poll.ID, OptionID: options[0].ID, VoterIdentifier: "voter-2"}))
+		require.NoError(t, repo.CastVote(ctx, &models.Vote{PollID: poll.ID, OptionID: options[1].ID, VoterIdentifier: "voter-3", Weight: 2}))
+		require.NoError(t, repo.CastWriteInVote(ctx, &models.Vote{PollID: poll.ID, VoterIdentifier: "voter-4", WriteIn: &writeIn}))
+		require.NoError(t, repo.RemoveVote(ctx, poll.ID, "voter-2"))
+
+		imports := []*models.Vote{{PollID: poll.ID, OptionID: options[1].ID, VoterIdentifier: "voter-5", Weight: 4, VotedAt: time.Now()}}
+		imported, _, err := repo.ImportVotes(ctx, func() (*models.Vote, error) {
+			if len(imports) == 0 {
+				return nil, io.EOF
+			}
+			vote := imports[0]
+			imports = imports[1:]
+			return vote, nil
+		})
+		require.NoError(t, err)
+		require.Equal(t, int64(1), imported)
+
+		polls, err := repo.GetPollsByIDs(ctx, []uuid.UUID{poll.ID})
+		require.NoError(t, err)
+		require.Len(t, polls, 1)
+		return polls[0]
+	}
+
+	want := castVotes(incremented)
+	got := castVotes(derived)
+
+	assert.Equal(t, int64(10), want.TotalVotes)
+	assert.Equal(t, want.TotalVotes, got.TotalVotes)
+	require.Len(t, got.Options, 2)
+	assert.Equal(t, []int64{3, 6}, []int64{want.Options[0].VoteCount, want.Options[1].VoteCount})
+	assert.Equal(t, []int64{3, 6}, []int64{got.Options[0].VoteCount, got.Options[1].VoteCount})
+
+	options, err := derived.GetOptionsForPolls(ctx, []uuid.UUID{got.ID})
+	require.NoError(t, err)
+	assert.Equal(t, int64(6), options[got.ID][1].VoteCount)
+
+	var stored int64
+	require.NoError(t, derived.db.QueryRowContext(ctx, `SELECT SUM(vote_count) FROM poll_options WHERE poll_id = $1`, got.ID).Scan(&stored))
+	assert.Zero(t, stored, "votes are only appended, never counted on write")
+}
@@ -3,7 +3,11 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"fmt"
+	"io"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/moabdelazem/k8s-app/internal/models"
@@ -119,6 +123,50 @@ func TestCastVote_Integration(t *testing.T) {
 	assert.False(t, vote.VotedAt.IsZero())
 }
 
+func TestRemoveVote_Integration(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	repo := NewPollRepository(db)
+	ctx := context.Background()
+
+	poll := &models.Poll{
+		Question:      "Test poll?",
+		IsActive:      true,
+		AllowWeighted: true,
+	}
+	options := []models.PollOption{
+		{OptionText: "Yes", Position: 0},
+		{OptionText: "No", Position: 1},
+	}
+	require.NoError(t, repo.CreatePoll(ctx, poll, options))
+
+	require.NoError(t, repo.CastVote(ctx, &models.Vote{PollID: poll.ID, OptionID: options[0].ID, VoterIdentifier: "fraudster", Weight: 3}))
+	require.NoError(t, repo.CastVote(ctx, &models.Vote{PollID: poll.ID, OptionID: options[0].ID, VoterIdentifier: "honest-voter"}))
+
+	// Act
+	err := repo.RemoveVote(ctx, poll.ID, "fraudster")
+
+	// Assert
+	require.NoError(t, err)
+
+	got, err := repo.GetPollByID(ctx, poll.ID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), got.TotalVotes)
+
+	gotOptions, err := repo.GetPollOptions(ctx, poll.ID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), gotOptions[0].VoteCount)
+	assert.Equal(t, int64(0), gotOptions[1].VoteCount)
+
+	hasVoted, _, err := repo.HasVoted(ctx, poll.ID, "fraudster")
+	require.NoError(t, err)
+	assert.False(t, hasVoted)
+
+	// Removing it again reports the missing vote
+	assert.ErrorIs(t, repo.RemoveVote(ctx, poll.ID, "fraudster"), sql.ErrNoRows)
+}
+
 func TestHasVoted_Integration(t *testing.T) {
 	db := setupTestDB(t)
 	defer db.Close()
@@ -160,3 +208,443 @@ func TestHasVoted_Integration(t *testing.T) {
 	assert.False(t, hasVoted2)
 	assert.Nil(t, optionID2)
 }
+
+func TestDeactivateExpired_Integration(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	repo := NewPollRepository(db)
+	ctx := context.Background()
+
+	// Seed one expired and one still-active poll
+	past := time.Now().Add(-time.Hour)
+	future := time.Now().Add(time.Hour)
+
+	expired := &models.Poll{Question: "Expired poll?", IsActive: true, ExpiresAt: &past}
+	err := repo.CreatePoll(ctx, expired, []models.PollOption{{OptionText: "Yes"}, {OptionText: "No"}})
+	require.NoError(t, err)
+
+	active := &models.Poll{Question: "Active poll?", IsActive: true, ExpiresAt: &future}
+	err = repo.CreatePoll(ctx, active, []models.PollOption{{OptionText: "Yes"}, {OptionText: "No"}})
+	require.NoError(t, err)
+
+	// Act
+	closed, err := repo.DeactivateExpired(ctx)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Contains(t, closed, expired.ID)
+	assert.NotContains(t, closed, active.ID)
+
+	retrievedExpired, err := repo.GetPollByID(ctx, expired.ID)
+	require.NoError(t, err)
+	assert.False(t, retrievedExpired.IsActive)
+
+	retrievedActive, err := repo.GetPollByID(ctx, active.ID)
+	require.NoError(t, err)
+	assert.True(t, retrievedActive.IsActive)
+
+	// Calling again is a no-op
+	closed, err = repo.DeactivateExpired(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, closed)
+}
+
+func TestExpireNow_Integration(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	repo := NewPollRepository(db)
+	ctx := context.Background()
+
+	future := time.Now().Add(time.Hour)
+	poll := &models.Poll{Question: "Expire me?", IsActive: true, ExpiresAt: &future}
+	require.NoError(t, repo.CreatePoll(ctx, poll, []models.PollOption{{OptionText: "Yes"}, {OptionText: "No"}}))
+
+	// Act
+	expiresAt, err := repo.ExpireNow(ctx, poll.ID)
+
+	// Assert: the poll reads as expired but stays active until it is closed
+	require.NoError(t, err)
+	retrieved, err := repo.GetPollByID(ctx, poll.ID)
+	require.NoError(t, err)
+	require.NotNil(t, retrieved.ExpiresAt)
+	assert.True(t, retrieved.ExpiresAt.Equal(expiresAt))
+	assert.False(t, retrieved.ExpiresAt.After(time.Now()))
+	assert.True(t, retrieved.IsActive)
+
+	// Votes are rejected by the service, which checks expires_at; the poll is closed like any expired one
+	closed, err := repo.DeactivateExpired(ctx)
+	require.NoError(t, err)
+	assert.Contains(t, closed, poll.ID)
+
+	_, err = repo.ExpireNow(ctx, uuid.New())
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+}
+
+func TestGetPollWithResults_Integration(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	repo := NewPollRepository(db)
+	ctx := context.Background()
+
+	poll := &models.Poll{Question: "One query or three?", IsActive: true, QuizMode: true}
+	options := []models.PollOption{{OptionText: "One", IsCorrect: true}, {OptionText: "Three"}}
+	require.NoError(t, repo.CreatePoll(ctx, poll, options))
+	require.NoError(t, repo.CastVote(ctx, &models.Vote{PollID: poll.ID, OptionID: options[1].ID, VoterIdentifier: "voter-1"}))
+	require.NoError(t, repo.CastVote(ctx, &models.Vote{PollID: poll.ID, OptionID: options[1].ID, VoterIdentifier: "voter-2"}))
+
+	// The multi-query path GetPollWithResults replaces
+	wantPoll, err := repo.GetPollByID(ctx, poll.ID)
+	require.NoError(t, err)
+	wantOptions, err := repo.GetPollOptions(ctx, poll.ID)
+	require.NoError(t, err)
+
+	for _, voter := range []string{"voter-1", "voter-3", ""} {
+		t.Run("voter "+voter, func(t *testing.T) {
+			got, err := repo.GetPollWithResults(ctx, poll.ID, voter)
+			require.NoError(t, err)
+			require.NotNil(t, got)
+
+			assert.Equal(t, *wantPoll, got.Poll)
+			assert.Equal(t, wantOptions, got.Options)
+
+			hasVoted, votedOption, err := repo.HasVoted(ctx, poll.ID, voter)
+			require.NoError(t, err)
+			if hasVoted && voter != "" {
+				assert.Equal(t, votedOption, got.VotedOption)
+			} else {
+				assert.Nil(t, got.VotedOption)
+			}
+		})
+	}
+
+	missing, err := repo.GetPollWithResults(ctx, uuid.New(), "voter-1")
+	require.NoError(t, err)
+	assert.Nil(t, missing)
+}
+
+func TestHasVotedInGroup_Integration(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	repo := NewPollRepository(db)
+	ctx := context.Background()
+
+	group := "Series " + uuid.NewString()
+	otherGroup := "Other " + uuid.NewString()
+	first := &models.Poll{Question: "First in series?", IsActive: true, Group: &group}
+	options := []models.PollOption{{OptionText: "Yes"}, {OptionText: "No"}}
+	require.NoError(t, repo.CreatePoll(ctx, first, options))
+
+	voted, err := repo.HasVotedInGroup(ctx, group, "voter-1")
+	require.NoError(t, err)
+	assert.False(t, voted)
+
+	require.NoError(t, repo.CastVote(ctx, &models.Vote{PollID: first.ID, OptionID: options[0].ID, VoterIdentifier: "voter-1"}))
+
+	// Group names match case-insensitively; other groups and voters are independent
+	voted, err = repo.HasVotedInGroup(ctx, strings.ToUpper(group), "voter-1")
+	require.NoError(t, err)
+	assert.True(t, voted)
+
+	voted, err = repo.HasVotedInGroup(ctx, otherGroup, "voter-1")
+	require.NoError(t, err)
+	assert.False(t, voted)
+
+	voted, err = repo.HasVotedInGroup(ctx, group, "voter-2")
+	require.NoError(t, err)
+	assert.False(t, voted)
+}
+
+func TestGetVoteTimeline_Integration(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	repo := NewPollRepository(db)
+	ctx := context.Background()
+
+	poll := &models.Poll{Question: "Timeline poll?", IsActive: true}
+	options := []models.PollOption{{OptionText: "Yes", Position: 0}, {OptionText: "No", Position: 1}}
+	require.NoError(t, repo.CreatePoll(ctx, poll, options))
+
+	// Seed votes with known timestamps across two hourly buckets
+	base := time.Date(2025, time.June, 1, 10, 0, 0, 0, time.UTC)
+	seed := []struct {
+		optionID uuid.UUID
+		votedAt  time.Time
+	}{
+		{options[0].ID, base.Add(5 * time.Minute)},
+		{options[0].ID, base.Add(40 * time.Minute)},
+		{options[1].ID, base.Add(59 * time.Minute)},
+		{options[1].ID, base.Add(61 * time.Minute)},
+	}
+	for i, v := range seed {
+		_, err := db.ExecContext(ctx,
+			`INSERT INTO votes (poll_id, option_id, voter_identifier, voted_at) VALUES ($1, $2, $3, $4)`,
+			poll.ID, v.optionID, fmt.Sprintf("timeline-voter-%d", i), v.votedAt,
+		)
+		require.NoError(t, err)
+	}
+
+	// Act
+	buckets, err := repo.GetVoteTimeline(ctx, poll.ID, time.Hour)
+
+	// Assert
+	require.NoError(t, err)
+	require.Len(t, buckets, 2)
+
+	assert.True(t, buckets[0].Start.Equal(base))
+	assert.Equal(t, []models.OptionVoteCount{
+		{OptionID: options[0].ID, Votes: 2},
+		{OptionID: options[1].ID, Votes: 1},
+	}, buckets[0].Options)
+
+	assert.True(t, buckets[1].Start.Equal(base.Add(time.Hour)))
+	assert.Equal(t, []models.OptionVoteCount{
+		{OptionID: options[1].ID, Votes: 1},
+	}, buckets[1].Options)
+}
+
+func TestImportVotes_Integration(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	repo := NewPollRepository(db)
+	ctx := context.Background()
+
+	poll := &models.Poll{Question: "Imported poll?", IsActive: true}
+	options := []models.PollOption{{OptionText: "Yes", Position: 0}, {OptionText: "No", Position: 1}}
+	require.NoError(t, repo.CreatePoll(ctx, poll, options))
+
+	votedAt := time.Date(2024, time.January, 2, 10, 0, 0, 0, time.UTC)
+	votes := []models.Vote{
+		{PollID: poll.ID, OptionID: options[0].ID, VoterIdentifier: "alice", VotedAt: votedAt},
+		{PollID: poll.ID, OptionID: options[1].ID, VoterIdentifier: "bob", VotedAt: votedAt},
+		{PollID: poll.ID, OptionID: options[1].ID, VoterIdentifier: "alice", VotedAt: votedAt}, // duplicate voter
+	}
+	i := 0
+	next := func() (*models.Vote, error) {
+		if i == len(votes) {
+			return nil, io.EOF
+		}
+		i++
+		return &votes[i-1], nil
+	}
+
+	// Act
+	imported, skipped, err := repo.ImportVotes(ctx, next)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), imported)
+	assert.Equal(t, int64(1), skipped)
+
+	retrieved, err := repo.GetPollOptions(ctx, poll.ID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), retrieved[0].VoteCount)
+	assert.Equal(t, int64(1), retrieved[1].VoteCount)
+
+	retrievedPoll, err := repo.GetPollByID(ctx, poll.ID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), retrievedPoll.TotalVotes)
+}
+
+func TestStreamPollsWithOptions_Integration(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	repo := NewPollRepository(db)
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		poll := &models.Poll{Question: fmt.Sprintf("Streamed poll %d?", i), IsActive: true}
+		options := []models.PollOption{{OptionText: "Yes", Position: 0}, {OptionText: "No", Position: 1}}
+		require.NoError(t, repo.CreatePoll(ctx, poll, options))
+	}
+
+	// Act
+	var streamed []models.PollWithOptions
+	err := repo.StreamPollsWithOptions(ctx, 2, 0, false, func(poll models.PollWithOptions) error {
+		streamed = append(streamed, poll)
+		return nil
+	})
+
+	// Assert: the limit counts polls, not joined option rows
+	require.NoError(t, err)
+	require.Len(t, streamed, 2)
+	for _, poll := range streamed {
+		assert.Len(t, poll.Options, 2)
+	}
+
+	buffered, err := repo.ListPollsWithOptions(ctx, 2, 0, false)
+	require.NoError(t, err)
+	assert.Equal(t, streamed, buffered)
+}
+
+func TestGetPollsByIDs_Integration(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	repo := NewPollRepository(db)
+	ctx := context.Background()
+
+	var created []*models.Poll
+	for i := 0; i < 3; i++ {
+		poll := &models.Poll{Question: fmt.Sprintf("Batch poll %d?", i), IsActive: true}
+		options := []models.PollOption{{OptionText: "Yes", Position: 0}, {OptionText: "No", Position: 1}}
+		require.NoError(t, repo.CreatePoll(ctx, poll, options))
+		created = append(created, poll)
+	}
+
+	// Request out of creation order, with a missing ID and a duplicate
+	ids := []uuid.UUID{created[2].ID, uuid.New(), created[0].ID, created[2].ID}
+
+	// Act
+	polls, err := repo.GetPollsByIDs(ctx, ids)
+
+	// Assert
+	require.NoError(t, err)
+	require.Len(t, polls, 2)
+	assert.Equal(t, created[2].ID, polls[0].ID)
+	assert.Equal(t, created[0].ID, polls[1].ID)
+	for _, poll := range polls {
+		require.Len(t, poll.Options, 2)
+		assert.Equal(t, "Yes", poll.Options[0].OptionText)
+		assert.Equal(t, "No", poll.Options[1].OptionText)
+	}
+}
+
+func TestCreatePoll_OptionCountConstraint_Integration(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	repo := NewPollRepository(db)
+	ctx := context.Background()
+
+	tests := []struct {
+		name  string
+		count int
+	}{
+		{name: "too many options", count: 11},
+		{name: "too few options", count: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			poll := &models.Poll{Question: "Constraint poll?", IsActive: true}
+			options := make([]models.PollOption, tt.count)
+			for i := range options {
+				options[i] = models.PollOption{OptionText: fmt.Sprintf("Option %d", i+1)}
+			}
+
+			err := repo.CreatePoll(ctx, poll, options)
+
+			require.ErrorIs(t, err, ErrOptionCountOutOfBounds)
+
+			// The transaction is rolled back, so the poll does not exist
+			got, err := repo.GetPollByID(ctx, poll.ID)
+			require.NoError(t, err)
+			assert.Nil(t, got)
+		})
+	}
+}
+
+func TestOutboxRelay_Integration(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	pollRepo := NewPollRepository(db)
+	outboxRepo := NewOutboxRepository(db)
+	ctx := context.Background()
+
+	poll := &models.Poll{Question: "Outbox poll?", IsActive: true}
+	require.NoError(t, pollRepo.CreatePoll(ctx, poll, []models.PollOption{{OptionText: "Yes"}, {OptionText: "No"}}))
+	require.NoError(t, pollRepo.DeletePoll(ctx, poll.ID))
+
+	events, err := outboxRepo.FetchUnpublished(ctx, 100)
+	require.NoError(t, err)
+
+	var ids []int64
+	var types []string
+	for _, event := range events {
+		if event.PollID == poll.ID {
+			ids = append(ids, event.ID)
+			types = append(types, event.Type)
+		}
+	}
+	assert.Equal(t, []string{models.EventPollCreated, models.EventPollClosed}, types)
+
+	require.NoError(t, outboxRepo.MarkPublished(ctx, ids))
+	events, err = outboxRepo.FetchUnpublished(ctx, 100)
+	require.NoError(t, err)
+	for _, event := range events {
+		assert.NotEqual(t, poll.ID, event.PollID)
+	}
+}
+
+func TestUpdateOptionTexts_Integration(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	repo := NewPollRepository(db)
+	ctx := context.Background()
+
+	poll := &models.Poll{Question: "Favourite colour?", IsActive: true}
+	options := []models.PollOption{{OptionText: "Redd"}, {OptionText: "Blue"}}
+	require.NoError(t, repo.CreatePoll(ctx, poll, options))
+
+	options[0].OptionText = "Red"
+	require.NoError(t, repo.UpdateOptionTexts(ctx, poll.ID, options))
+
+	stored, err := repo.GetPollOptions(ctx, poll.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Red", stored[0].OptionText)
+
+	// Once a vote is in, the options are locked
+	require.NoError(t, repo.CastVote(ctx, &models.Vote{PollID: poll.ID, OptionID: options[1].ID, VoterIdentifier: "voter-1"}))
+	assert.ErrorIs(t, repo.UpdateOptionTexts(ctx, poll.ID, options), ErrPollHasVotes)
+}
+
+func TestArchiveClosedPolls_Integration(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	repo := NewPollRepository(db)
+	ctx := context.Background()
+
+	closed := &models.Poll{Question: "Archive me?", IsActive: true}
+	options := []models.PollOption{{OptionText: "Yes"}, {OptionText: "No"}}
+	require.NoError(t, repo.CreatePoll(ctx, closed, options))
+	require.NoError(t, repo.CastVote(ctx, &models.Vote{PollID: closed.ID, OptionID: options[0].ID, VoterIdentifier: "voter-1"}))
+	require.NoError(t, repo.DeletePoll(ctx, closed.ID))
+
+	open := &models.Poll{Question: "Keep me?", IsActive: true}
+	require.NoError(t, repo.CreatePoll(ctx, open, []models.PollOption{{OptionText: "Yes"}, {OptionText: "No"}}))
+
+	// Polls closed after the cutoff stay put
+	ids, err := repo.ArchiveClosedPolls(ctx, time.Now().Add(-time.Hour), 100)
+	require.NoError(t, err)
+	assert.NotContains(t, ids, closed.ID)
+
+	// Act
+	ids, err = repo.ArchiveClosedPolls(ctx, time.Now(), 100)
+
+	// Assert: the poll left the live tables with its options and votes
+	require.NoError(t, err)
+	assert.Contains(t, ids, closed.ID)
+	assert.NotContains(t, ids, open.ID)
+
+	live, err := repo.GetPollWithResults(ctx, closed.ID, "voter-1")
+	require.NoError(t, err)
+	assert.Nil(t, live)
+
+	archived, err := repo.GetArchivedPollWithResults(ctx, closed.ID, "voter-1")
+	require.NoError(t, err)
+	require.NotNil(t, archived)
+	assert.Equal(t, int64(1), archived.TotalVotes)
+	require.Len(t, archived.Options, 2)
+	assert.Equal(t, int64(1), archived.Options[0].VoteCount)
+	assert.Equal(t, &options[0].ID, archived.VotedOption)
+}
@@ -0,0 +1,158 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/moabdelazem/k8s-app/internal/database"
+	"github.com/moabdelazem/k8s-app/internal/models"
+)
+
+// TemplateRepositoryInterface defines the contract for poll template data access
+type TemplateRepositoryInterface interface {
+	CreateTemplate(ctx context.Context, template *models.PollTemplate) error
+	GetTemplate(ctx context.Context, id uuid.UUID) (*models.PollTemplate, error)
+	ListTemplates(ctx context.Context) ([]models.PollTemplate, error)
+	UpdateTemplate(ctx context.Context, template *models.PollTemplate) (bool, error)
+	DeleteTemplate(ctx context.Context, id uuid.UUID) (bool, error)
+}
+
+type TemplateRepository struct {
+	db database.Conn
+}
+
+func NewTemplateRepository(db database.Conn) *TemplateRepository {
+	return &TemplateRepository{db: db}
+}
+
+// CreateTemplate stores a new poll template
+func (r *TemplateRepository) CreateTemplate(ctx context.Context, template *models.PollTemplate) error {
+	query := `
+		INSERT INTO poll_templates (name, question, description, options)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, created_at, updated_at`
+
+	err := r.db.QueryRowContext(ctx, query,
+		template.Name,
+		template.Question,
+		template.Description,
+		pq.Array(template.Options),
+	).Scan(&template.ID, &template.CreatedAt, &template.UpdatedAt)
+
+	if err != nil {
+		return fmt.Errorf("failed to insert template: %w", err)
+	}
+
+	return nil
+}
+
+// GetTemplate retrieves a poll template by ID
+// Returns nil without an error when the template does not exist
+func (r *TemplateRepository) GetTemplate(ctx context.Context, id uuid.UUID) (*models.PollTemplate, error) {
+	query := `
+		SELECT id, name, question, description, options, created_at, updated_at
+		FROM poll_templates
+		WHERE id = $1`
+
+	var template models.PollTemplate
+	err := r.db.QueryRowContext(ctx, query, id).Scan(templateScanDest(&template)...)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get template: %w", err)
+	}
+
+	return &template, nil
+}
+
+// ListTemplates retrieves every poll template, by name
+func (r *TemplateRepository) ListTemplates(ctx context.Context) ([]models.PollTemplate, error) {
+	query := `
+		SELECT id, name, question, description, options, created_at, updated_at
+		FROM poll_templates
+		ORDER BY name ASC, created_at ASC, id ASC`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query templates: %w", err)
+	}
+	defer rows.Close()
+
+	templates := []models.PollTemplate{}
+	for rows.Next() {
+		var template models.PollTemplate
+		if err := rows.Scan(templateScanDest(&template)...); err != nil {
+			return nil, fmt.Errorf("failed to scan template: %w", err)
+		}
+		templates = append(templates, template)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating templates: %w", err)
+	}
+
+	return templates, nil
+}
+
+// UpdateTemplate replaces the contents of a poll template, reporting whether it existed
+func (r *TemplateRepository) UpdateTemplate(ctx context.Context, template *models.PollTemplate) (bool, error) {
+	query := `
+		UPDATE poll_templates
+		SET name = $2, question = $3, description = $4, options = $5, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1
+		RETURNING created_at, updated_at`
+
+	err := r.db.QueryRowContext(ctx, query,
+		template.ID,
+		template.Name,
+		template.Question,
+		template.Description,
+		pq.Array(template.Options),
+	).Scan(&template.CreatedAt, &template.UpdatedAt)
+
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to update template: %w", err)
+	}
+
+	return true, nil
+}
+
+// DeleteTemplate removes a poll template, reporting whether it existed
+// Polls created from the template are not affected
+func (r *TemplateRepository) DeleteTemplate(ctx context.Context, id uuid.UUID) (bool, error) {
+	query := `
+		DELETE FROM poll_templates
+		WHERE id = $1`
+
+	result, err := r.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return false, fmt.Errorf("failed to delete template: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return rows > 0, nil
+}
+
+// templateScanDest returns scan destinations matching the template columns selected above
+func templateScanDest(template *models.PollTemplate) []any {
+	return []any{
+		&template.ID,
+		&template.Name,
+		&template.Question,
+		&template.Description,
+		pq.Array(&template.Options),
+		&template.CreatedAt,
+		&template.UpdatedAt,
+	}
+}
@@ -0,0 +1,117 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/moabdelazem/k8s-app/internal/database"
+	"github.com/moabdelazem/k8s-app/internal/models"
+)
+
+// WebhookRepositoryInterface defines the contract for webhook data access
+type WebhookRepositoryInterface interface {
+	CreateWebhook(ctx context.Context, webhook *models.Webhook) error
+	ListWebhooks(ctx context.Context, pollID uuid.UUID) ([]models.Webhook, error)
+	ListWebhooksForEvent(ctx context.Context, pollID uuid.UUID, event string) ([]models.Webhook, error)
+	DeleteWebhook(ctx context.Context, pollID, webhookID uuid.UUID) (bool, error)
+}
+
+type WebhookRepository struct {
+	db database.Conn
+}
+
+func NewWebhookRepository(db database.Conn) *WebhookRepository {
+	return &WebhookRepository{db: db}
+}
+
+// CreateWebhook registers a webhook for a poll
+func (r *WebhookRepository) CreateWebhook(ctx context.Context, webhook *models.Webhook) error {
+	query := `
+		INSERT INTO webhooks (poll_id, url, events, secret)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, created_at`
+
+	err := r.db.QueryRowContext(ctx, query,
+		webhook.PollID,
+		webhook.URL,
+		pq.Array(webhook.Events),
+		webhook.Secret,
+	).Scan(&webhook.ID, &webhook.CreatedAt)
+
+	if err != nil {
+		return fmt.Errorf("failed to insert webhook: %w", err)
+	}
+
+	return nil
+}
+
+// ListWebhooks retrieves all webhooks registered for a poll
+func (r *WebhookRepository) ListWebhooks(ctx context.Context, pollID uuid.UUID) ([]models.Webhook, error) {
+	query := `
+		SELECT id, poll_id, url, events, secret, created_at
+		FROM webhooks
+		WHERE poll_id = $1
+		ORDER BY created_at ASC, id ASC`
+
+	return r.queryWebhooks(ctx, query, pollID)
+}
+
+// ListWebhooksForEvent retrieves the webhooks of a poll subscribed to an event
+func (r *WebhookRepository) ListWebhooksForEvent(ctx context.Context, pollID uuid.UUID, event string) ([]models.Webhook, error) {
+	query := `
+		SELECT id, poll_id, url, events, secret, created_at
+		FROM webhooks
+		WHERE poll_id = $1 AND $2 = ANY(events)
+		ORDER BY created_at ASC, id ASC`
+
+	return r.queryWebhooks(ctx, query, pollID, event)
+}
+
+// DeleteWebhook removes a webhook, reporting whether it existed
+func (r *WebhookRepository) DeleteWebhook(ctx context.Context, pollID, webhookID uuid.UUID) (bool, error) {
+	query := `
+		DELETE FROM webhooks
+		WHERE id = $1 AND poll_id = $2`
+
+	result, err := r.db.ExecContext(ctx, query, webhookID, pollID)
+	if err != nil {
+		return false, fmt.Errorf("failed to delete webhook: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return rows > 0, nil
+}
+
+// queryWebhooks runs a webhook SELECT and scans the rows
+func (r *WebhookRepository) queryWebhooks(ctx context.Context, query string, args ...any) ([]models.Webhook, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query webhooks: %w", err)
+	}
+	defer rows.Close()
+
+	webhooks := []models.Webhook{}
+	for rows.Next() {
+		var webhook models.Webhook
+		err := rows.Scan(
+			&webhook.ID,
+			&webhook.PollID,
+			&webhook.URL,
+			pq.Array(&webhook.Events),
+			&webhook.Secret,
+			&webhook.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan webhook: %w", err)
+		}
+		webhooks = append(webhooks, webhook)
+	}
+
+	return webhooks, rows.Err()
+}
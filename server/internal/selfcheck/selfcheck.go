@@ -0,0 +1,150 @@
+// Package selfcheck verifies at startup that the service has what it needs to run, so a
+// misconfigured pod exits with a clear report instead of flapping between ready and unready.
+// Values are validated by config.NewConfig; the checks here cover what lies around them:
+// secrets that features depend on, files the config points at, the database and its schema.
+package selfcheck
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/moabdelazem/k8s-app/internal/config"
+	"github.com/moabdelazem/k8s-app/internal/database"
+	"github.com/moabdelazem/k8s-app/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// Check is one startup requirement
+// A failing required check stops startup; a failing optional one only disables a feature.
+type Check struct {
+	Name     string
+	Required bool
+	Run      func(ctx context.Context) error
+}
+
+// Result is the outcome of a check; Err is nil when it passed
+type Result struct {
+	Name     string
+	Required bool
+	Err      error
+}
+
+// Report collects the results of every check, in the order they ran
+type Report struct {
+	Results []Result
+}
+
+// Run runs every check, including those after a failure, so the report lists all problems at once
+func Run(ctx context.Context, checks []Check) Report {
+	report := Report{Results: make([]Result, 0, len(checks))}
+	for _, check := range checks {
+		report.Results = append(report.Results, Result{
+			Name:     check.Name,
+			Required: check.Required,
+			Err:      check.Run(ctx),
+		})
+	}
+	return report
+}
+
+// Err joins the failures of required checks; nil when startup may continue
+func (r Report) Err() error {
+	var errs []error
+	for _, result := range r.Results {
+		if result.Required && result.Err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", result.Name, result.Err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Log writes one line per check and a summary line
+func (r Report) Log() {
+	failed := 0
+	for _, result := range r.Results {
+		switch {
+		case result.Err == nil:
+			logger.Info("Self-check passed", zap.String("check", result.Name))
+		case result.Required:
+			failed++
+			logger.Error("Self-check failed", zap.String("check", result.Name), zap.Error(result.Err))
+		default:
+			logger.Warn("Self-check failed; continuing without the feature", zap.String("check", result.Name), zap.Error(result.Err))
+		}
+	}
+
+	logger.Info("Self-check complete",
+		zap.Int("checks", len(r.Results)),
+		zap.Int("required_failures", failed),
+	)
+}
+
+// Dependencies are the runtime probes the checks use, replaceable in tests
+type Dependencies struct {
+	Ping            func() error
+	MigrationStatus func(ctx context.Context) (database.MigrationVersions, error)
+}
+
+// DefaultDependencies probes the shared database connection
+func DefaultDependencies() Dependencies {
+	return Dependencies{Ping: database.Ping, MigrationStatus: database.MigrationStatus}
+}
+
+// Checks returns the startup checks for cfg
+// Database checks are skipped when polls are kept in memory.
+func Checks(cfg *config.Config, deps Dependencies) []Check {
+	checks := []Check{
+		{Name: "auth", Required: true, Run: func(context.Context) error {
+			if cfg.Auth.RequireAuthForCreate && cfg.Auth.JWTSecret == "" {
+				return errors.New("JWT_SECRET is required when REQUIRE_AUTH_FOR_CREATE is enabled")
+			}
+			return nil
+		}},
+		{Name: "vote_blocklist", Required: true, Run: func(context.Context) error {
+			return fileReadable("VOTE_BLOCKLIST_FILE", cfg.Poll.VoteBlocklistFile)
+		}},
+		{Name: "geoip_regions", Required: false, Run: func(context.Context) error {
+			return fileReadable("GEOIP_REGIONS_FILE", cfg.Log.GeoIPFile)
+		}},
+		{Name: "admin_api", Required: false, Run: func(context.Context) error {
+			if cfg.Admin.APIKey == "" {
+				return errors.New("ADMIN_API_KEY is not set; admin endpoints reject every request")
+			}
+			return nil
+		}},
+	}
+
+	if cfg.RepoBackend == config.RepoBackendMemory {
+		return checks
+	}
+
+	return append(checks,
+		Check{Name: "database", Required: true, Run: func(context.Context) error {
+			return deps.Ping()
+		}},
+		Check{Name: "migrations", Required: true, Run: func(ctx context.Context) error {
+			versions, err := deps.MigrationStatus(ctx)
+			if err != nil {
+				return err
+			}
+			if versions.Behind() {
+				return fmt.Errorf("schema version %d is behind the required version %d", versions.Current, versions.Latest)
+			}
+			return nil
+		}},
+	)
+}
+
+// fileReadable reports an error when a configured file cannot be opened; an unset path passes
+func fileReadable(setting, path string) error {
+	if path == "" {
+		return nil
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("%s: %w", setting, err)
+	}
+	return f.Close()
+}
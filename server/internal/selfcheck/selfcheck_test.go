@@ -0,0 +1,119 @@
+package selfcheck
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/moabdelazem/k8s-app/internal/config"
+	"github.com/moabdelazem/k8s-app/internal/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// healthyDeps reports a reachable database with an up-to-date schema
+func healthyDeps() Dependencies {
+	return Dependencies{
+		Ping: func() error { return nil },
+		MigrationStatus: func(context.Context) (database.MigrationVersions, error) {
+			return database.MigrationVersions{Current: database.SchemaVersion, Latest: database.SchemaVersion}, nil
+		},
+	}
+}
+
+func validConfig() *config.Config {
+	return &config.Config{
+		RepoBackend: config.RepoBackendPostgres,
+		Admin:       config.AdminConfig{APIKey: "admin-key"},
+	}
+}
+
+// failedChecks returns the names of the failing checks, split by whether they are required
+func failedChecks(report Report) (required, optional []string) {
+	for _, result := range report.Results {
+		if result.Err == nil {
+			continue
+		}
+		if result.Required {
+			required = append(required, result.Name)
+		} else {
+			optional = append(optional, result.Name)
+		}
+	}
+	return required, optional
+}
+
+func TestChecks_AllPass(t *testing.T) {
+	report := Run(context.Background(), Checks(validConfig(), healthyDeps()))
+
+	assert.NoError(t, report.Err())
+	required, optional := failedChecks(report)
+	assert.Empty(t, required)
+	assert.Empty(t, optional)
+}
+
+func TestChecks_ReportsEveryMissingRequirement(t *testing.T) {
+	cfg := validConfig()
+	cfg.Auth.RequireAuthForCreate = true
+	cfg.Poll.VoteBlocklistFile = filepath.Join(t.TempDir(), "missing.txt")
+	cfg.Admin.APIKey = ""
+
+	deps := healthyDeps()
+	deps.Ping = func() error { return errors.New("connection refused") }
+	deps.MigrationStatus = func(context.Context) (database.MigrationVersions, error) {
+		return database.MigrationVersions{Current: database.SchemaVersion - 1, Latest: database.SchemaVersion}, nil
+	}
+
+	report := Run(context.Background(), Checks(cfg, deps))
+
+	required, optional := failedChecks(report)
+	assert.Equal(t, []string{"auth", "vote_blocklist", "database", "migrations"}, required)
+	assert.Equal(t, []string{"admin_api"}, optional)
+
+	err := report.Err()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "JWT_SECRET is required")
+	assert.Contains(t, err.Error(), "VOTE_BLOCKLIST_FILE")
+	assert.Contains(t, err.Error(), "connection refused")
+	assert.Contains(t, err.Error(), "behind the required version")
+}
+
+func TestChecks_OptionalFailuresDoNotStopStartup(t *testing.T) {
+	cfg := validConfig()
+	cfg.Log.GeoIPFile = filepath.Join(t.TempDir(), "missing.csv")
+	cfg.Admin.APIKey = ""
+
+	report := Run(context.Background(), Checks(cfg, healthyDeps()))
+
+	_, optional := failedChecks(report)
+	assert.Equal(t, []string{"geoip_regions", "admin_api"}, optional)
+	assert.NoError(t, report.Err())
+}
+
+func TestChecks_ConfiguredFilesReadable(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "blocklist.txt")
+	require.NoError(t, os.WriteFile(path, []byte("10.0.0.0/8\n"), 0o600))
+
+	cfg := validConfig()
+	cfg.Poll.VoteBlocklistFile = path
+
+	assert.NoError(t, Run(context.Background(), Checks(cfg, healthyDeps())).Err())
+}
+
+func TestChecks_MemoryBackendSkipsDatabase(t *testing.T) {
+	cfg := validConfig()
+	cfg.RepoBackend = config.RepoBackendMemory
+
+	deps := healthyDeps()
+	deps.Ping = func() error { return errors.New("no database") }
+
+	report := Run(context.Background(), Checks(cfg, deps))
+
+	assert.NoError(t, report.Err())
+	for _, result := range report.Results {
+		assert.NotEqual(t, "database", result.Name)
+		assert.NotEqual(t, "migrations", result.Name)
+	}
+}
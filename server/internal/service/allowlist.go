@@ -0,0 +1,113 @@
+package service
+
+import (
+	"context"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/moabdelazem/k8s-app/internal/models"
+	"github.com/moabdelazem/k8s-app/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// Allowlist limits; maxVoterIdentifierLength matches the poll_allowed_voters.voter_identifier column
+const (
+	maxAllowlistBatch        = 1000
+	maxVoterIdentifierLength = 255
+)
+
+// AddAllowedVoters adds resolved voter identifiers to a poll's allowlist
+// Voters already on it are skipped. The allowlist is only enforced on polls created with
+// allowlist_only, but may be filled in for any poll. Returns the number of voters added.
+func (s *PollService) AddAllowedVoters(ctx context.Context, pollID uuid.UUID, voterIdentifiers []string) (int64, error) {
+	voters, err := normalizeAllowedVoters(voterIdentifiers)
+	if err != nil {
+		return 0, err
+	}
+
+	poll, err := s.repo.GetPollByID(ctx, pollID)
+	if err != nil {
+		return 0, wrapRepoError("failed to get poll", err)
+	}
+	if poll == nil {
+		return 0, ErrPollNotFound
+	}
+
+	added, err := s.repo.AddAllowedVoters(ctx, pollID, voters)
+	if err != nil {
+		logger.Error("Failed to add allowed voters",
+			zap.Error(err),
+			zap.String("poll_id", pollID.String()),
+		)
+		return 0, wrapRepoError("failed to add allowed voters", err)
+	}
+
+	logger.Info("Allowed voters added",
+		zap.String("poll_id", pollID.String()),
+		zap.Int64("added", added),
+		zap.Int("requested", len(voters)),
+	)
+
+	return added, nil
+}
+
+// normalizeAllowedVoters trims and deduplicates voter identifiers, rejecting blank or oversized ones
+func normalizeAllowedVoters(voterIdentifiers []string) ([]string, error) {
+	if len(voterIdentifiers) == 0 {
+		return nil, validationErrorf("voters must list at least one voter identifier")
+	}
+	if len(voterIdentifiers) > maxAllowlistBatch {
+		return nil, validationErrorf("at most %d voters can be added at once", maxAllowlistBatch)
+	}
+
+	seen := make(map[string]bool, len(voterIdentifiers))
+	voters := make([]string, 0, len(voterIdentifiers))
+	for i, voter := range voterIdentifiers {
+		voter = strings.TrimSpace(voter)
+		if voter == "" || len(voter) > maxVoterIdentifierLength {
+			return nil, validationErrorf("voter %d must be between 1 and %d characters", i+1, maxVoterIdentifierLength)
+		}
+		if seen[voter] {
+			continue
+		}
+		seen[voter] = true
+		voters = append(voters, voter)
+	}
+	return voters, nil
+}
+
+// RemoveAllowedVoter removes a voter from a poll's allowlist
+// A vote they already cast is kept; remove it separately with RemoveVote
+func (s *PollService) RemoveAllowedVoter(ctx context.Context, pollID uuid.UUID, voterIdentifier string) error {
+	removed, err := s.repo.RemoveAllowedVoter(ctx, pollID, voterIdentifier)
+	if err != nil {
+		return wrapRepoError("failed to remove allowed voter", err)
+	}
+	if !removed {
+		return ErrAllowedVoterNotFound
+	}
+
+	logger.Info("Allowed voter removed",
+		zap.String("poll_id", pollID.String()),
+		zap.String("voter", voterIdentifier),
+	)
+
+	return nil
+}
+
+// ListAllowedVoters lists the voters on a poll's allowlist
+func (s *PollService) ListAllowedVoters(ctx context.Context, pollID uuid.UUID) ([]models.AllowedVoter, error) {
+	poll, err := s.repo.GetPollByID(ctx, pollID)
+	if err != nil {
+		return nil, wrapRepoError("failed to get poll", err)
+	}
+	if poll == nil {
+		return nil, ErrPollNotFound
+	}
+
+	voters, err := s.repo.ListAllowedVoters(ctx, pollID)
+	if err != nil {
+		return nil, wrapRepoError("failed to list allowed voters", err)
+	}
+	return voters, nil
+}
@@ -0,0 +1,94 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/moabdelazem/k8s-app/internal/mocks"
+	"github.com/moabdelazem/k8s-app/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// allowlistVoteRepo returns a repository holding an open allowlist-only poll with a single option,
+// whose allowlist contains only "invited"
+func allowlistVoteRepo() (*mocks.MockPollRepository, *models.Poll) {
+	poll := &models.Poll{ID: uuid.New(), IsActive: true, AllowlistOnly: true}
+
+	repo := new(mocks.MockPollRepository)
+	repo.On("GetPollByID", mock.Anything, poll.ID).Return(poll, nil)
+	repo.On("GetPollOptions", mock.Anything, poll.ID).Return([]models.PollOption{{ID: poll.ID, PollID: poll.ID}}, nil)
+	repo.On("HasVoted", mock.Anything, poll.ID, mock.Anything).Return(false, nil, nil)
+	repo.On("IsVoterAllowed", mock.Anything, poll.ID, "invited").Return(true, nil)
+	repo.On("IsVoterAllowed", mock.Anything, poll.ID, mock.Anything).Return(false, nil)
+	repo.On("CastVote", mock.Anything, mock.Anything).Return(nil)
+	return repo, poll
+}
+
+func TestCastVote_AllowlistAllowsInvitedVoter(t *testing.T) {
+	repo, poll := allowlistVoteRepo()
+	svc := NewPollService(repo, PollServiceConfig{})
+
+	_, _, err := svc.CastVote(context.Background(), poll.ID, poll.ID, "invited", 0, "")
+	require.NoError(t, err)
+	repo.AssertNumberOfCalls(t, "CastVote", 1)
+}
+
+func TestCastVote_AllowlistRejectsOtherVoters(t *testing.T) {
+	repo, poll := allowlistVoteRepo()
+	svc := NewPollService(repo, PollServiceConfig{})
+
+	_, _, err := svc.CastVote(context.Background(), poll.ID, poll.ID, "stranger", 0, "")
+	require.ErrorIs(t, err, ErrVoterNotAllowed)
+	repo.AssertNotCalled(t, "CastVote", mock.Anything, mock.Anything)
+}
+
+func TestCastVote_AllowlistIgnoredOnOpenPolls(t *testing.T) {
+	poll := &models.Poll{ID: uuid.New(), IsActive: true}
+	repo := groupVoteRepo(poll)
+	svc := NewPollService(repo, PollServiceConfig{})
+
+	_, _, err := svc.CastVote(context.Background(), poll.ID, poll.ID, "anyone", 0, "")
+	require.NoError(t, err)
+	repo.AssertNotCalled(t, "IsVoterAllowed", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestAddAllowedVoters_NormalizesVoters(t *testing.T) {
+	pollID := uuid.New()
+	repo := new(mocks.MockPollRepository)
+	repo.On("GetPollByID", mock.Anything, pollID).Return(&models.Poll{ID: pollID, IsActive: true}, nil)
+	repo.On("AddAllowedVoters", mock.Anything, pollID, []string{"user:alice", "user:bob"}).Return(int64(2), nil)
+	svc := NewPollService(repo, PollServiceConfig{})
+
+	added, err := svc.AddAllowedVoters(context.Background(), pollID, []string{" user:alice ", "user:bob", "user:alice"})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), added)
+}
+
+func TestAddAllowedVoters_Validation(t *testing.T) {
+	svc := NewPollService(new(mocks.MockPollRepository), PollServiceConfig{})
+
+	for name, voters := range map[string][]string{
+		"empty list":  nil,
+		"blank voter": {"user:alice", "  "},
+		"too many":    make([]string, maxAllowlistBatch+1),
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := svc.AddAllowedVoters(context.Background(), uuid.New(), voters)
+			var validationErr *ValidationError
+			assert.ErrorAs(t, err, &validationErr)
+		})
+	}
+}
+
+func TestRemoveAllowedVoter_NotOnAllowlist(t *testing.T) {
+	pollID := uuid.New()
+	repo := new(mocks.MockPollRepository)
+	repo.On("RemoveAllowedVoter", mock.Anything, pollID, "user:alice").Return(false, nil)
+	svc := NewPollService(repo, PollServiceConfig{})
+
+	err := svc.RemoveAllowedVoter(context.Background(), pollID, "user:alice")
+	assert.ErrorIs(t, err, ErrAllowedVoterNotFound)
+}
@@ -0,0 +1,359 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/google/uuid"
+	"github.com/moabdelazem/k8s-app/internal/models"
+	"github.com/moabdelazem/k8s-app/internal/repository"
+	"github.com/moabdelazem/k8s-app/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// BackupFormatVersion is the bundle format written by ExportAll and read by ImportAll
+const BackupFormatVersion = 1
+
+// backupStages orders the record types within a bundle
+var backupStages = map[string]int{
+	models.BackupRecordPoll:         1,
+	models.BackupRecordOption:       2,
+	models.BackupRecordVote:         3,
+	models.BackupRecordWriteInVote:  4,
+	models.BackupRecordAllowedVoter: 5,
+	models.BackupRecordEnd:          6,
+}
+
+// ExportAll writes every poll with its options, votes, write-in votes and allowlist to w as an NDJSON bundle.
+// Records are written as they are read, so the database is never held in memory.
+// Archived polls, webhooks and templates are not part of the bundle.
+func (s *PollService) ExportAll(ctx context.Context, w io.Writer) error {
+	enc := json.NewEncoder(w)
+	header := models.BackupRecord{
+		Type:   models.BackupRecordHeader,
+		Header: &models.BackupHeader{Version: BackupFormatVersion, ExportedAt: s.clock.Now().UTC()},
+	}
+	if err := enc.Encode(header); err != nil {
+		return fmt.Errorf("failed to write backup: %w", err)
+	}
+
+	var summary models.BackupSummary
+	err := s.repo.ExportAll(ctx, func(record models.BackupRecord) error {
+		switch record.Type {
+		case models.BackupRecordPoll:
+			summary.Polls++
+		case models.BackupRecordOption:
+			summary.Options++
+		case models.BackupRecordVote:
+			summary.Votes++
+		case models.BackupRecordWriteInVote:
+			summary.WriteInVotes++
+		case models.BackupRecordAllowedVoter:
+			summary.AllowedVoters++
+		}
+		return enc.Encode(record)
+	})
+	if err != nil {
+		logger.Error("Failed to export backup", zap.Error(err))
+		return wrapRepoError("failed to export backup", err)
+	}
+
+	if err := enc.Encode(models.BackupRecord{Type: models.BackupRecordEnd, End: &summary}); err != nil {
+		return fmt.Errorf("failed to write backup: %w", err)
+	}
+
+	logger.Info("Backup exported",
+		zap.Int64("polls", summary.Polls),
+		zap.Int64("votes", summary.Votes),
+	)
+	return nil
+}
+
+// ImportAll restores an NDJSON bundle written by ExportAll into an empty database.
+// Records are checked as they are read, one at a time: each may only reference records
+// before it, and the end record must match what was read, so a truncated bundle is rejected.
+// Any invalid record aborts the whole restore.
+func (s *PollService) ImportAll(ctx context.Context, r io.Reader) (*models.BackupSummary, error) {
+	dec := json.NewDecoder(r)
+	line := 0
+	read := func() (*models.BackupRecord, error) {
+		var record models.BackupRecord
+		if err := dec.Decode(&record); err != nil {
+			if err == io.EOF {
+				return nil, io.EOF
+			}
+			return nil, validationErrorf("line %d: invalid JSON: %v", line+1, err)
+		}
+		line++
+		return &record, nil
+	}
+
+	header, err := read()
+	if err == io.EOF {
+		return nil, validationErrorf("backup is empty")
+	}
+	if err != nil {
+		return nil, err
+	}
+	if header.Type != models.BackupRecordHeader || header.Header == nil {
+		return nil, validationErrorf("line 1: backup must start with a header record")
+	}
+	if header.Header.Version != BackupFormatVersion {
+		return nil, validationErrorf("line 1: unsupported backup version %d", header.Header.Version)
+	}
+
+	check := newBackupChecker()
+	next := func() (*models.BackupRecord, error) {
+		record, err := read()
+		if err == io.EOF {
+			if !check.ended {
+				return nil, validationErrorf("backup is truncated: it has no end record")
+			}
+			return nil, io.EOF
+		}
+		if err != nil {
+			return nil, err
+		}
+		if err := check.record(record); err != nil {
+			return nil, validationErrorf("line %d: %v", line, err)
+		}
+		return record, nil
+	}
+
+	summary, err := s.repo.ImportAll(ctx, next)
+	if err != nil {
+		var validationErr *ValidationError
+		if errors.As(err, &validationErr) {
+			return nil, validationErr
+		}
+		if errors.Is(err, repository.ErrRestoreTargetNotEmpty) {
+			return nil, ErrRestoreTargetNotEmpty
+		}
+		logger.Error("Failed to restore backup", zap.Error(err))
+		return nil, wrapRepoError("failed to restore backup", err)
+	}
+	for pollID := range check.optionCounts {
+		s.invalidateResults(pollID)
+	}
+
+	logger.Info("Backup restored",
+		zap.Int64("polls", summary.Polls),
+		zap.Int64("votes", summary.Votes),
+	)
+	return summary, nil
+}
+
+// backupChecker checks that each record of a bundle only references records before it
+type backupChecker struct {
+	stage        int
+	last         string // Type of the previous record
+	ended        bool
+	seen         models.BackupSummary
+	optionCounts map[uuid.UUID]int       // Poll ID -> number of options
+	optionPolls  map[uuid.UUID]uuid.UUID // Option ID -> poll ID
+	writeInPolls map[uuid.UUID]bool      // Polls allowing write-ins
+	ackPolls     map[uuid.UUID]bool      // Acknowledgement polls, which have exactly one option
+	capacity     map[uuid.UUID]int64     // Poll ID -> votes left before max_votes, for capped polls
+	voters       map[uuid.UUID]map[string]bool
+	allowed      map[uuid.UUID]map[string]bool
+}
+
+func newBackupChecker() *backupChecker {
+	return &backupChecker{
+		optionCounts: make(map[uuid.UUID]int),
+		optionPolls:  make(map[uuid.UUID]uuid.UUID),
+		writeInPolls: make(map[uuid.UUID]bool),
+		ackPolls:     make(map[uuid.UUID]bool),
+		capacity:     make(map[uuid.UUID]int64),
+		voters:       make(map[uuid.UUID]map[string]bool),
+		allowed:      make(map[uuid.UUID]map[string]bool),
+	}
+}
+
+func (c *backupChecker) record(record *models.BackupRecord) error {
+	if c.ended {
+		return errors.New("records must not follow the end record")
+	}
+	stage, ok := backupStages[record.Type]
+	if !ok {
+		return fmt.Errorf("unexpected record type %q", record.Type)
+	}
+	if stage < c.stage {
+		return fmt.Errorf("%s records must come before %s records", record.Type, c.last)
+	}
+	if stage > backupStages[models.BackupRecordOption] && c.stage <= backupStages[models.BackupRecordOption] {
+		// Options are complete, so every poll's option count is final
+		for pollID, count := range c.optionCounts {
+			if c.ackPolls[pollID] && count != 1 {
+				return fmt.Errorf("acknowledgement poll %s must have exactly 1 option", pollID)
+			}
+			if !c.ackPolls[pollID] && count < 2 {
+				return fmt.Errorf("poll %s must have at least 2 options", pollID)
+			}
+		}
+	}
+	c.stage = stage
+	c.last = record.Type
+
+	switch record.Type {
+	case models.BackupRecordPoll:
+		return c.poll(record.Poll)
+	case models.BackupRecordOption:
+		return c.option(record.Option)
+	case models.BackupRecordVote:
+		return c.vote(record.Vote)
+	case models.BackupRecordWriteInVote:
+		return c.writeInVote(record.WriteInVote)
+	case models.BackupRecordAllowedVoter:
+		return c.allowedVoter(record.AllowedVoter)
+	default:
+		return c.end(record.End)
+	}
+}
+
+func (c *backupChecker) poll(p *models.BackupPoll) error {
+	if p == nil || p.ID == uuid.Nil {
+		return errors.New("poll record needs a poll with an id")
+	}
+	if _, ok := c.optionCounts[p.ID]; ok {
+		return fmt.Errorf("duplicate poll %s", p.ID)
+	}
+	if err := validateQuestion(p.Question); err != nil {
+		return fmt.Errorf("poll %s: %v", p.ID, err)
+	}
+	if p.MaxVotes != nil {
+		if *p.MaxVotes < 1 {
+			return fmt.Errorf("poll %s: max_votes must be at least 1", p.ID)
+		}
+		c.capacity[p.ID] = *p.MaxVotes
+	}
+	if err := validateVotingWindow(p.VotingWindowStart, p.VotingWindowEnd, p.Timezone); err != nil {
+		return fmt.Errorf("poll %s: %v", p.ID, err)
+	}
+	c.optionCounts[p.ID] = 0
+	c.writeInPolls[p.ID] = p.AllowWriteIn
+	c.ackPolls[p.ID] = p.AcknowledgementMode
+	c.seen.Polls++
+	return nil
+}
+
+func (c *backupChecker) option(o *models.BackupOption) error {
+	if o == nil || o.ID == uuid.Nil {
+		return errors.New("option record needs an option with an id")
+	}
+	count, ok := c.optionCounts[o.PollID]
+	if !ok {
+		return fmt.Errorf("option %s references unknown poll %s", o.ID, o.PollID)
+	}
+	if _, ok := c.optionPolls[o.ID]; ok {
+		return fmt.Errorf("duplicate option %s", o.ID)
+	}
+	if count == 10 {
+		return fmt.Errorf("poll %s can have at most 10 options", o.PollID)
+	}
+	if len(o.OptionText) < 1 || len(o.OptionText) > 200 {
+		return fmt.Errorf("option %s must be between 1 and 200 characters", o.ID)
+	}
+	c.optionCounts[o.PollID] = count + 1
+	c.optionPolls[o.ID] = o.PollID
+	c.seen.Options++
+	return nil
+}
+
+func (c *backupChecker) vote(v *models.BackupVote) error {
+	if v == nil || v.ID == uuid.Nil {
+		return errors.New("vote record needs a vote with an id")
+	}
+	if pollID, ok := c.optionPolls[v.OptionID]; !ok || pollID != v.PollID {
+		return fmt.Errorf("vote %s references option %s, which is not an option of poll %s", v.ID, v.OptionID, v.PollID)
+	}
+	if v.VoterIdentifier == "" || len(v.VoterIdentifier) > 255 {
+		return fmt.Errorf("vote %s: voter_identifier must be between 1 and 255 characters", v.ID)
+	}
+	if v.Weight < 1 {
+		return fmt.Errorf("vote %s: weight must be at least 1", v.ID)
+	}
+	if err := c.voter(v.PollID, v.VoterIdentifier, v.Weight); err != nil {
+		return fmt.Errorf("vote %s: %v", v.ID, err)
+	}
+	c.seen.Votes++
+	return nil
+}
+
+func (c *backupChecker) writeInVote(w *models.BackupWriteInVote) error {
+	if w == nil || w.ID == uuid.Nil {
+		return errors.New("write_in_vote record needs a write-in vote with an id")
+	}
+	allowWriteIn, ok := c.writeInPolls[w.PollID]
+	if !ok {
+		return fmt.Errorf("write-in vote %s references unknown poll %s", w.ID, w.PollID)
+	}
+	if !allowWriteIn {
+		return fmt.Errorf("write-in vote %s is on poll %s, which does not allow write-ins", w.ID, w.PollID)
+	}
+	if text, err := sanitizeWriteIn(w.WriteIn); err != nil || text != w.WriteIn {
+		return fmt.Errorf("write-in vote %s: write_in must be sanitized text of 1 to %d characters", w.ID, MaxWriteInLength)
+	}
+	if w.VoterIdentifier == "" || len(w.VoterIdentifier) > 255 {
+		return fmt.Errorf("write-in vote %s: voter_identifier must be between 1 and 255 characters", w.ID)
+	}
+	if w.Weight < 1 {
+		return fmt.Errorf("write-in vote %s: weight must be at least 1", w.ID)
+	}
+	if err := c.voter(w.PollID, w.VoterIdentifier, w.Weight); err != nil {
+		return fmt.Errorf("write-in vote %s: %v", w.ID, err)
+	}
+	c.seen.WriteInVotes++
+	return nil
+}
+
+// voter records that a voter voted on a poll with weight; a voter has one vote or write-in vote
+// per poll, and the votes of a capped poll must fit within its max_votes
+func (c *backupChecker) voter(pollID uuid.UUID, voterIdentifier string, weight int64) error {
+	if c.voters[pollID][voterIdentifier] {
+		return fmt.Errorf("voter already voted on poll %s", pollID)
+	}
+	if left, capped := c.capacity[pollID]; capped {
+		if weight > left {
+			return fmt.Errorf("votes exceed the max_votes of poll %s", pollID)
+		}
+		c.capacity[pollID] = left - weight
+	}
+	if c.voters[pollID] == nil {
+		c.voters[pollID] = make(map[string]bool)
+	}
+	c.voters[pollID][voterIdentifier] = true
+	return nil
+}
+
+func (c *backupChecker) allowedVoter(a *models.BackupAllowedVoter) error {
+	if a == nil {
+		return errors.New("allowed_voter record needs an allowed voter")
+	}
+	if _, ok := c.optionCounts[a.PollID]; !ok {
+		return fmt.Errorf("allowed voter references unknown poll %s", a.PollID)
+	}
+	if a.VoterIdentifier == "" || len(a.VoterIdentifier) > 255 {
+		return errors.New("allowed voter: voter_identifier must be between 1 and 255 characters")
+	}
+	if c.allowed[a.PollID][a.VoterIdentifier] {
+		return fmt.Errorf("duplicate allowed voter on poll %s", a.PollID)
+	}
+	if c.allowed[a.PollID] == nil {
+		c.allowed[a.PollID] = make(map[string]bool)
+	}
+	c.allowed[a.PollID][a.VoterIdentifier] = true
+	c.seen.AllowedVoters++
+	return nil
+}
+
+func (c *backupChecker) end(summary *models.BackupSummary) error {
+	if summary == nil || *summary != c.seen {
+		return errors.New("end record does not match the records read; the backup is incomplete or was edited")
+	}
+	c.ended = true
+	return nil
+}
@@ -0,0 +1,177 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/moabdelazem/k8s-app/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// seedBackupService returns an in-memory service holding a weighted poll with votes and
+// an allowlist-only poll with an allowlist, along with the polls
+func seedBackupService(t *testing.T) (*PollService, []*models.PollWithOptions) {
+	t.Helper()
+	ctx := context.Background()
+	svc := newMemoryTestService(PollServiceConfig{Clock: fixedClock{now: testNow}, MinVoteWeight: 1, MaxVoteWeight: 10})
+
+	weighted := validCreateRequest()
+	weighted.AllowWeighted = true
+	first := createMemoryPoll(t, svc, weighted)
+
+	private := validCreateRequest()
+	private.Options = []string{"Yes", "No", "Abstain"}
+	private.AllowlistOnly = true
+	second := createMemoryPoll(t, svc, private)
+
+	_, _, err := svc.CastVote(ctx, first.ID, first.Options[0].ID, "voter-1", 3, "")
+	require.NoError(t, err)
+	_, _, err = svc.CastVote(ctx, first.ID, first.Options[1].ID, "voter-2", 0, "newsletter")
+	require.NoError(t, err)
+
+	_, err = svc.AddAllowedVoters(ctx, second.ID, []string{"voter-1", "voter-3"})
+	require.NoError(t, err)
+	_, _, err = svc.CastVote(ctx, second.ID, second.Options[2].ID, "voter-3", 0, "")
+	require.NoError(t, err)
+
+	return svc, []*models.PollWithOptions{first, second}
+}
+
+// exportLines exports svc and splits the bundle into its lines
+func exportLines(t *testing.T, svc *PollService) []string {
+	t.Helper()
+	var buf bytes.Buffer
+	require.NoError(t, svc.ExportAll(context.Background(), &buf))
+	return strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+}
+
+func TestBackup_RoundTrip(t *testing.T) {
+	ctx := context.Background()
+	source, polls := seedBackupService(t)
+
+	var bundle bytes.Buffer
+	require.NoError(t, source.ExportAll(ctx, &bundle))
+	exported := bundle.String()
+
+	restored := newMemoryTestService(PollServiceConfig{Clock: fixedClock{now: testNow}})
+	summary, err := restored.ImportAll(ctx, &bundle)
+	require.NoError(t, err)
+	assert.Equal(t, &models.BackupSummary{Polls: 2, Options: 5, Votes: 3, AllowedVoters: 2}, summary)
+
+	for _, poll := range polls {
+		want, err := source.GetPollResults(ctx, poll.ID, "voter-1")
+		require.NoError(t, err)
+		got, err := restored.GetPollResults(ctx, poll.ID, "voter-1")
+		require.NoError(t, err)
+		assert.Equal(t, want.TotalVotes, got.TotalVotes)
+		require.Len(t, got.Options, len(want.Options))
+		for i, opt := range want.Options {
+			assert.Equal(t, opt.ID, got.Options[i].ID)
+			assert.Equal(t, opt.OptionText, got.Options[i].OptionText)
+			assert.Equal(t, opt.VoteCount, got.Options[i].VoteCount)
+			assert.True(t, opt.CreatedAt.Equal(got.Options[i].CreatedAt))
+		}
+		assert.Equal(t, want.HasVoted, got.HasVoted)
+		assert.Equal(t, want.VotedOption, got.VotedOption)
+	}
+
+	first, err := restored.GetPollResults(ctx, polls[0].ID, "")
+	require.NoError(t, err)
+	assert.Equal(t, int64(4), first.TotalVotes, "weights are recounted from the votes")
+
+	// Restored votes still reference their options, so voters cannot vote again
+	_, _, err = restored.CastVote(ctx, polls[0].ID, polls[0].Options[1].ID, "voter-2", 0, "")
+	requireValidationCode(t, err, CodeAlreadyVoted)
+
+	voters, err := restored.ListAllowedVoters(ctx, polls[1].ID)
+	require.NoError(t, err)
+	require.Len(t, voters, 2)
+	_, _, err = restored.CastVote(ctx, polls[1].ID, polls[1].Options[0].ID, "voter-2", 0, "")
+	require.ErrorIs(t, err, ErrVoterNotAllowed)
+
+	// Exporting the restored state reproduces the bundle
+	assert.Equal(t, strings.Split(strings.TrimSuffix(exported, "\n"), "\n"), exportLines(t, restored))
+}
+
+func TestImportAll_RejectsNonEmptyTarget(t *testing.T) {
+	source, _ := seedBackupService(t)
+	bundle := strings.Join(exportLines(t, source), "\n")
+
+	_, err := source.ImportAll(context.Background(), strings.NewReader(bundle))
+	assert.ErrorIs(t, err, ErrRestoreTargetNotEmpty)
+}
+
+func TestImportAll_InvalidBundle(t *testing.T) {
+	source, _ := seedBackupService(t)
+	lines := exportLines(t, source)
+	// lines: header, 2 polls, 5 options, 3 votes, 2 allowed voters, end
+
+	without := func(i int) []string {
+		return append(append([]string{}, lines[:i]...), lines[i+1:]...)
+	}
+	swapped := func(i, j int) []string {
+		out := append([]string{}, lines...)
+		out[i], out[j] = out[j], out[i]
+		return out
+	}
+	edited := func(i int, edit func(*models.BackupRecord)) []string {
+		var record models.BackupRecord
+		require.NoError(t, json.Unmarshal([]byte(lines[i]), &record))
+		edit(&record)
+		line, err := json.Marshal(record)
+		require.NoError(t, err)
+		out := append([]string{}, lines...)
+		out[i] = string(line)
+		return out
+	}
+
+	tests := []struct {
+		name   string
+		lines  []string
+		errMsg string
+	}{
+		{"empty", nil, "backup is empty"},
+		{"no header", lines[1:], "line 1: backup must start with a header record"},
+		{"unsupported version", edited(0, func(r *models.BackupRecord) { r.Header.Version = 2 }), "unsupported backup version 2"},
+		{"invalid JSON", append(append([]string{}, lines[:3]...), "{"), "line 4: invalid JSON"},
+		{"poll after options", swapped(2, 3), "line 4: poll records must come before option records"},
+		{"vote after allowed voters", swapped(10, 11), "line 12: vote records must come before allowed_voter records"},
+		{"option of unknown poll", edited(3, func(r *models.BackupRecord) { r.Option.PollID = r.Option.ID }), "references unknown poll"},
+		{"vote for another poll's option", edited(8, func(r *models.BackupRecord) { r.Vote.OptionID = optionID(t, lines[7]) }), "is not an option of poll"},
+		{"too few options", without(3), "must have at least 2 options"},
+		{"truncated", lines[:len(lines)-1], "backup is truncated"},
+		{"end record mismatch", without(9), "end record does not match"},
+		{"record after end", append(append([]string{}, lines...), lines[1]), "records must not follow the end record"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			svc := newMemoryTestService(PollServiceConfig{})
+
+			_, err := svc.ImportAll(ctx, strings.NewReader(strings.Join(tt.lines, "\n")))
+			var validationErr *ValidationError
+			require.ErrorAs(t, err, &validationErr)
+			assert.Contains(t, validationErr.Message, tt.errMsg)
+
+			// A rejected bundle leaves nothing behind
+			count, err := svc.repo.GetTotalPollsCount(ctx, false)
+			require.NoError(t, err)
+			assert.Zero(t, count)
+		})
+	}
+}
+
+// optionID returns the ID of the option record on line
+func optionID(t *testing.T, line string) uuid.UUID {
+	t.Helper()
+	var record models.BackupRecord
+	require.NoError(t, json.Unmarshal([]byte(line), &record))
+	require.NotNil(t, record.Option)
+	return record.Option.ID
+}
@@ -0,0 +1,15 @@
+package service
+
+import "time"
+
+// Clock provides the current time so time-dependent rules can be tested deterministically
+type Clock interface {
+	Now() time.Time
+}
+
+// realClock is the default Clock backed by time.Now
+type realClock struct{}
+
+func (realClock) Now() time.Time {
+	return time.Now()
+}
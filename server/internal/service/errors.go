@@ -0,0 +1,189 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"net"
+
+	"github.com/lib/pq"
+	"github.com/moabdelazem/k8s-app/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// Domain errors returned by the service layer.
+// Handlers check these with errors.Is to choose the right HTTP status.
+var (
+	// ErrPollNotFound is returned when the requested poll does not exist
+	ErrPollNotFound = errors.New("poll not found")
+
+	// ErrPollDeleted is returned when the requested poll existed but has been deleted
+	ErrPollDeleted = errors.New("poll has been deleted")
+
+	// ErrActivePollLimitReached is returned when a creator already has the maximum number of active polls
+	ErrActivePollLimitReached = errors.New("active poll limit reached")
+
+	// ErrPollDeleteProtected is returned when a non-admin deletes a poll with too many votes to be deleted
+	ErrPollDeleteProtected = errors.New("poll has too many votes to be deleted; ask an admin")
+
+	// ErrVoteNotFound is returned when the voter has not voted on the poll
+	ErrVoteNotFound = errors.New("vote not found")
+
+	// ErrWebhookNotFound is returned when the requested webhook does not exist
+	ErrWebhookNotFound = errors.New("webhook not found")
+
+	// ErrTemplateNotFound is returned when the requested poll template does not exist
+	ErrTemplateNotFound = errors.New("template not found")
+
+	// ErrVoterNetworkBlocked is returned when a vote comes from a blocked network range, e.g. a data center
+	ErrVoterNetworkBlocked = errors.New("votes from this network are not accepted")
+
+	// ErrVoterNotAllowed is returned when a voter who is not on an allowlist-only poll's allowlist votes
+	ErrVoterNotAllowed = errors.New("you are not on the allowlist for this poll")
+
+	// ErrAllowedVoterNotFound is returned when removing a voter who is not on the poll's allowlist
+	ErrAllowedVoterNotFound = errors.New("voter is not on the poll's allowlist")
+
+	// ErrRestoreTargetNotEmpty is returned when restoring a backup into a database that already holds polls
+	ErrRestoreTargetNotEmpty = errors.New("backups can only be restored into an empty database")
+
+	// ErrTemporarilyUnavailable wraps transient database failures the client may retry
+	ErrTemporarilyUnavailable = errors.New("service temporarily unavailable")
+)
+
+// Error codes identify client-facing errors independently of their English message
+// so the response renderer can localize them
+const (
+	CodePollNotFound           = "poll_not_found"
+	CodePollDeleted            = "poll_deleted"
+	CodePollDeleteProtected    = "poll_delete_protected"
+	CodeVoteNotFound           = "vote_not_found"
+	CodeWebhookNotFound        = "webhook_not_found"
+	CodeTemplateNotFound       = "template_not_found"
+	CodeActivePollLimitReached = "active_poll_limit_reached"
+	CodeVoterNetworkBlocked    = "voter_network_blocked"
+	CodeVoterNotAllowed        = "voter_not_allowed"
+	CodeAllowedVoterNotFound   = "allowed_voter_not_found"
+	CodeRestoreTargetNotEmpty  = "restore_target_not_empty"
+	CodeTemporarilyUnavailable = "temporarily_unavailable"
+
+	CodeQuestionLength             = "question_length"
+	CodeTooFewOptions              = "too_few_options"
+	CodeTooManyOptions             = "too_many_options"
+	CodeOptionLength               = "option_length"
+	CodeExpiryNotInFuture          = "expiry_not_in_future"
+	CodeExpiryBeyondHorizon        = "expiry_beyond_horizon"
+	CodeGroupLength                = "group_length"
+	CodeQuizNeedsCorrectOption     = "quiz_needs_correct_option"
+	CodeCorrectOptionsWithoutQuiz  = "correct_options_without_quiz"
+	CodeInvalidCorrectOption       = "invalid_correct_option"
+	CodeOptionCountOutOfBounds     = "option_count_out_of_bounds"
+	CodeOptionsLocked              = "options_locked"
+	CodeOptionSetMismatch          = "option_set_mismatch"
+	CodeInvalidOptionUpdate        = "invalid_option_update"
+	CodeInvalidBucket              = "invalid_bucket"
+	CodeConfirmationRequired       = "confirmation_token_required"
+	CodeConfirmationInvalid        = "confirmation_token_invalid"
+	CodePollInactive               = "poll_inactive"
+	CodePollExpired                = "poll_expired"
+	CodeAlreadyVoted               = "already_voted"
+	CodeAlreadyVotedInGroup        = "already_voted_in_group"
+	CodeInvalidOption              = "invalid_option"
+	CodeWeightedVotingDisabled     = "weighted_voting_disabled"
+	CodeVoteWeightOutOfRange       = "vote_weight_out_of_range"
+	CodeBatchIDsRequired           = "batch_ids_required"
+	CodeBatchTooManyIDs            = "batch_too_many_ids"
+	CodeCampaignLength             = "campaign_length"
+	CodeShareLinkInvalid           = "share_link_invalid"
+	CodeReceiptInvalid             = "receipt_invalid"
+	CodeWriteInDisabled            = "write_in_disabled"
+	CodeWriteInLength              = "write_in_length"
+	CodeWriteInWithQuiz            = "write_in_with_quiz"
+	CodeMaxVotesInvalid            = "max_votes_invalid"
+	CodePollFull                   = "poll_full"
+	CodeVotingWindowInvalid        = "voting_window_invalid"
+	CodeTimezoneInvalid            = "timezone_invalid"
+	CodeOutsideVotingWindow        = "outside_voting_window"
+	CodeAcknowledgementOptionCount = "acknowledgement_option_count"
+	CodeAcknowledgementConflict    = "acknowledgement_conflict"
+	CodeTemplateNameLength         = "template_name_length"
+	CodeMetadataInvalid            = "metadata_invalid"
+	CodeMetadataTooLarge           = "metadata_too_large"
+	CodeVoteChoiceRequired         = "vote_choice_required"
+	CodeVoteChoiceConflict         = "vote_choice_conflict"
+	CodeInvalidField               = "invalid_field" // A validate tag rule without a dedicated code
+	CodeInvalidPollID              = "invalid_poll_id"
+)
+
+// ValidationError reports invalid input or a violated business rule.
+// Its message is safe to show to API clients in every environment.
+// Code and Args, when set, let the message be rendered in another language;
+// errors from operator-only endpoints carry no code and are always English.
+type ValidationError struct {
+	Code    string
+	Message string // English message
+	Args    []any  // Values interpolated into the message, in order
+}
+
+func (e *ValidationError) Error() string {
+	return e.Message
+}
+
+// validationErrorf formats a ValidationError without a code
+func validationErrorf(format string, args ...any) error {
+	return &ValidationError{Message: fmt.Sprintf(format, args...)}
+}
+
+// newValidationError formats a ValidationError identified by code
+func newValidationError(code, format string, args ...any) error {
+	return &ValidationError{Code: code, Message: fmt.Sprintf(format, args...), Args: args}
+}
+
+// wrapRepoError annotates a repository error, additionally marking it with
+// ErrTemporarilyUnavailable when the failure is transient
+func wrapRepoError(message string, err error) error {
+	if isTransientDBError(err) {
+		return fmt.Errorf("%s: %w: %w", message, ErrTemporarilyUnavailable, err)
+	}
+	return fmt.Errorf("%s: %w", message, err)
+}
+
+// logRepoError logs a failed repository call, at debug level when the caller went away and
+// canceled ctx: the failure is then the cancellation itself, not a problem worth an error
+func logRepoError(ctx context.Context, msg string, fields ...zap.Field) {
+	if errors.Is(ctx.Err(), context.Canceled) {
+		logger.Debug(msg, fields...)
+		return
+	}
+	logger.Error(msg, fields...)
+}
+
+// isTransientDBError reports whether err is a connection or capacity problem
+// that is likely to succeed on retry, such as pool wait timeouts or dropped connections
+func isTransientDBError(err error) bool {
+	if errors.Is(err, context.DeadlineExceeded) ||
+		errors.Is(err, driver.ErrBadConn) ||
+		errors.Is(err, sql.ErrConnDone) {
+		return true
+	}
+
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		switch pqErr.Code.Class() {
+		case "08", "53": // connection_exception, insufficient_resources
+			return true
+		}
+		switch pqErr.Code {
+		case "40001", "40P01", "57P01", "57P03": // serialization_failure, deadlock_detected, admin_shutdown, cannot_connect_now
+			return true
+		case "57014": // query_canceled, e.g. when the request deadline passes mid-query
+			return true
+		}
+		return false
+	}
+
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
@@ -0,0 +1,38 @@
+package service
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"net"
+	"testing"
+
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWrapRepoError(t *testing.T) {
+	tests := []struct {
+		name          string
+		err           error
+		wantTransient bool
+	}{
+		{name: "pool wait timeout", err: context.DeadlineExceeded, wantTransient: true},
+		{name: "bad connection", err: driver.ErrBadConn, wantTransient: true},
+		{name: "network error", err: &net.OpError{Op: "dial", Err: errors.New("connection refused")}, wantTransient: true},
+		{name: "too many connections", err: &pq.Error{Code: "53300"}, wantTransient: true},
+		{name: "server shutting down", err: &pq.Error{Code: "57P01"}, wantTransient: true},
+		{name: "query canceled at deadline", err: &pq.Error{Code: "57014"}, wantTransient: true},
+		{name: "constraint violation", err: &pq.Error{Code: "23505"}, wantTransient: false},
+		{name: "generic error", err: errors.New("boom"), wantTransient: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := wrapRepoError("failed to cast vote", tt.err)
+
+			assert.ErrorIs(t, err, tt.err)
+			assert.Equal(t, tt.wantTransient, errors.Is(err, ErrTemporarilyUnavailable))
+		})
+	}
+}
@@ -0,0 +1,83 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/moabdelazem/k8s-app/internal/models"
+	"github.com/moabdelazem/k8s-app/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// MaxMetadataBytes bounds a poll's metadata, measured once insignificant whitespace is removed
+const MaxMetadataBytes = 4096
+
+// SetMetadata replaces a poll's metadata; empty or null metadata removes it
+// Returns the updated poll
+func (s *PollService) SetMetadata(ctx context.Context, pollID uuid.UUID, metadata json.RawMessage) (*models.Poll, error) {
+	metadata, err := normalizeMetadata(metadata)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.repo.SetMetadata(ctx, pollID, metadata)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrPollNotFound
+	}
+	if err != nil {
+		logger.Error("Failed to set metadata",
+			zap.Error(err),
+			zap.String("poll_id", pollID.String()),
+		)
+		return nil, wrapRepoError("failed to set metadata", err)
+	}
+	s.invalidateResults(pollID)
+
+	poll, err := s.repo.GetPollByID(ctx, pollID)
+	if err != nil {
+		return nil, wrapRepoError("failed to get poll", err)
+	}
+	if poll == nil {
+		return nil, ErrPollNotFound
+	}
+	return poll, nil
+}
+
+// ListPollsByMetadata returns a page of the polls whose metadata has the string value under key, in listing order
+func (s *PollService) ListPollsByMetadata(ctx context.Context, key, value string, limit, offset int) ([]models.PollWithOptions, error) {
+	limit, offset = normalizePage(limit, offset)
+
+	polls, err := s.repo.ListPollsByMetadata(ctx, key, value, limit, offset)
+	if err != nil {
+		return nil, wrapRepoError("failed to list polls by metadata", err)
+	}
+	if polls == nil {
+		polls = []models.PollWithOptions{}
+	}
+	return polls, nil
+}
+
+// normalizeMetadata checks that metadata is a JSON object of at most MaxMetadataBytes and returns it compacted
+// Empty or null metadata means none and is returned as nil.
+func normalizeMetadata(metadata json.RawMessage) (json.RawMessage, error) {
+	trimmed := bytes.TrimSpace(metadata)
+	if len(trimmed) == 0 || bytes.Equal(trimmed, []byte("null")) {
+		return nil, nil
+	}
+	if trimmed[0] != '{' || !json.Valid(trimmed) {
+		return nil, newValidationError(CodeMetadataInvalid, "metadata must be a JSON object")
+	}
+
+	var compacted bytes.Buffer
+	if err := json.Compact(&compacted, trimmed); err != nil {
+		return nil, newValidationError(CodeMetadataInvalid, "metadata must be a JSON object")
+	}
+	if compacted.Len() > MaxMetadataBytes {
+		return nil, newValidationError(CodeMetadataTooLarge, "metadata must be at most %d bytes", MaxMetadataBytes)
+	}
+	return compacted.Bytes(), nil
+}
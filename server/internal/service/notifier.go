@@ -0,0 +1,14 @@
+package service
+
+import "github.com/moabdelazem/k8s-app/internal/models"
+
+// Notifier receives poll lifecycle events.
+// Implementations must not block the request path.
+type Notifier interface {
+	Notify(event models.PollEvent)
+}
+
+// noopNotifier discards all events
+type noopNotifier struct{}
+
+func (noopNotifier) Notify(models.PollEvent) {}
@@ -0,0 +1,25 @@
+package service
+
+import (
+	"hash/fnv"
+	"math/rand/v2"
+
+	"github.com/google/uuid"
+)
+
+// shuffleOptions puts options in the order a voter sees them on a poll that randomizes
+// options, countering the bias toward whatever is listed first. The order is derived from
+// the poll and voter, so a voter sees the same order on every request while other voters,
+// and the same voter on other polls, get independent orders.
+// Options are identified by ID, so vote counts and votes are unaffected by the order.
+func shuffleOptions[T any](options []T, pollID uuid.UUID, voterIdentifier string) {
+	h := fnv.New64a()
+	h.Write(pollID[:])
+	h.Write([]byte(voterIdentifier))
+	seed := h.Sum64()
+
+	rng := rand.New(rand.NewPCG(seed, seed>>32|seed<<32))
+	rng.Shuffle(len(options), func(i, j int) {
+		options[i], options[j] = options[j], options[i]
+	})
+}
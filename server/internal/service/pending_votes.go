@@ -0,0 +1,87 @@
+package service
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/moabdelazem/k8s-app/internal/models"
+)
+
+// DefaultVoteConfirmationTTL is how long a pending vote waits for confirmation when none is configured
+const DefaultVoteConfirmationTTL = 2 * time.Minute
+
+// PendingVoteStore holds votes awaiting confirmation, keyed by confirmation token
+type PendingVoteStore interface {
+	// Put stores vote under token until expiresAt
+	Put(token string, vote models.Vote, expiresAt time.Time)
+	// Take removes and returns the vote stored under token; ok is false when it is unknown or expired
+	Take(token string) (vote models.Vote, ok bool)
+}
+
+// pendingVote is a vote held by MemoryPendingVoteStore
+type pendingVote struct {
+	vote      models.Vote
+	expiresAt time.Time
+}
+
+// MemoryPendingVoteStore is an in-process PendingVoteStore.
+// Pending votes are not shared between replicas, so confirmations must reach the
+// replica that issued the token (e.g. via session affinity).
+type MemoryPendingVoteStore struct {
+	mu    sync.Mutex
+	clock Clock
+	votes map[string]pendingVote
+}
+
+// NewMemoryPendingVoteStore creates an empty store; clock defaults to the system clock when nil
+func NewMemoryPendingVoteStore(clock Clock) *MemoryPendingVoteStore {
+	if clock == nil {
+		clock = realClock{}
+	}
+	return &MemoryPendingVoteStore{clock: clock, votes: make(map[string]pendingVote)}
+}
+
+// Put stores a pending vote, discarding any that have already expired
+func (m *MemoryPendingVoteStore) Put(token string, vote models.Vote, expiresAt time.Time) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	// Sweep on write so abandoned votes cannot accumulate
+	now := m.clock.Now()
+	for t, p := range m.votes {
+		if !p.expiresAt.After(now) {
+			delete(m.votes, t)
+		}
+	}
+
+	m.votes[token] = pendingVote{vote: vote, expiresAt: expiresAt}
+}
+
+// Take removes and returns a pending vote if it has not expired
+func (m *MemoryPendingVoteStore) Take(token string) (models.Vote, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	p, ok := m.votes[token]
+	if !ok {
+		return models.Vote{}, false
+	}
+	delete(m.votes, token)
+
+	if !p.expiresAt.After(m.clock.Now()) {
+		return models.Vote{}, false
+	}
+	return p.vote, true
+}
+
+// newConfirmationToken returns a random 128-bit token, hex encoded
+func newConfirmationToken() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate confirmation token: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
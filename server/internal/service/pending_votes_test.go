@@ -0,0 +1,191 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/moabdelazem/k8s-app/internal/mocks"
+	"github.com/moabdelazem/k8s-app/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// manualClock is a Clock that only moves when advanced
+type manualClock struct {
+	now time.Time
+}
+
+func (c *manualClock) Now() time.Time {
+	return c.now
+}
+
+func (c *manualClock) Advance(d time.Duration) {
+	c.now = c.now.Add(d)
+}
+
+// newConfirmationTestService returns a service over a poll requiring confirmation with a single option
+func newConfirmationTestService(clock Clock) (*PollService, *mocks.MockPollRepository, uuid.UUID, uuid.UUID) {
+	pollID := uuid.New()
+	optionID := uuid.New()
+
+	repo := new(mocks.MockPollRepository)
+	repo.On("GetPollByID", mock.Anything, pollID).Return(&models.Poll{
+		ID:                  pollID,
+		IsActive:            true,
+		RequireConfirmation: true,
+	}, nil)
+	repo.On("HasVoted", mock.Anything, pollID, "voter-1").Return(false, nil, nil)
+	repo.On("GetPollOptions", mock.Anything, pollID).Return([]models.PollOption{{ID: optionID, PollID: pollID}}, nil)
+
+	svc := NewPollService(repo, PollServiceConfig{Clock: clock, VoteConfirmationTTL: time.Minute})
+	return svc, repo, pollID, optionID
+}
+
+func TestConfirmVote_Flow(t *testing.T) {
+	svc, repo, pollID, optionID := newConfirmationTestService(&manualClock{now: testNow})
+	repo.On("CastVote", mock.Anything, mock.MatchedBy(func(v *models.Vote) bool {
+		return v.PollID == pollID && v.OptionID == optionID && v.VoterIdentifier == "voter-1" && v.Weight == 1
+	})).Return(nil).Once()
+
+	confirmation, _, err := svc.CastVote(context.Background(), pollID, optionID, "voter-1", 0, "")
+	require.NoError(t, err)
+	require.NotNil(t, confirmation)
+	assert.NotEmpty(t, confirmation.Token)
+	assert.Equal(t, testNow.Add(time.Minute), confirmation.ExpiresAt)
+
+	// Nothing is recorded until the token comes back
+	repo.AssertNotCalled(t, "CastVote", mock.Anything, mock.Anything)
+
+	_, err = svc.ConfirmVote(context.Background(), pollID, confirmation.Token, "voter-1")
+	require.NoError(t, err)
+	repo.AssertExpectations(t)
+
+	// Tokens are single use
+	_, err = svc.ConfirmVote(context.Background(), pollID, confirmation.Token, "voter-1")
+	assert.EqualError(t, err, "confirmation token is invalid or has expired")
+}
+
+func TestConfirmVote_TokenExpiry(t *testing.T) {
+	tests := []struct {
+		name    string
+		elapsed time.Duration
+		wantErr bool
+	}{
+		{name: "confirmed just in time", elapsed: time.Minute - time.Second, wantErr: false},
+		{name: "confirmed exactly at expiry", elapsed: time.Minute, wantErr: true},
+		{name: "confirmed after expiry", elapsed: 2 * time.Minute, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clock := &manualClock{now: testNow}
+			svc, repo, pollID, optionID := newConfirmationTestService(clock)
+			if !tt.wantErr {
+				repo.On("CastVote", mock.Anything, mock.Anything).Return(nil)
+			}
+
+			confirmation, _, err := svc.CastVote(context.Background(), pollID, optionID, "voter-1", 0, "")
+			require.NoError(t, err)
+
+			clock.Advance(tt.elapsed)
+			_, err = svc.ConfirmVote(context.Background(), pollID, confirmation.Token, "voter-1")
+
+			if tt.wantErr {
+				assert.EqualError(t, err, "confirmation token is invalid or has expired")
+				repo.AssertNotCalled(t, "CastVote", mock.Anything, mock.Anything)
+			} else {
+				assert.NoError(t, err)
+			}
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestConfirmVote_RejectsMismatchedToken(t *testing.T) {
+	tests := []struct {
+		name  string
+		poll  func(pollID uuid.UUID) uuid.UUID
+		voter string
+		token func(token string) string
+	}{
+		{name: "unknown token", poll: func(id uuid.UUID) uuid.UUID { return id }, voter: "voter-1", token: func(string) string { return "deadbeef" }},
+		{name: "different voter", poll: func(id uuid.UUID) uuid.UUID { return id }, voter: "voter-2", token: func(tok string) string { return tok }},
+		{name: "different poll", poll: func(uuid.UUID) uuid.UUID { return uuid.New() }, voter: "voter-1", token: func(tok string) string { return tok }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, repo, pollID, optionID := newConfirmationTestService(&manualClock{now: testNow})
+
+			confirmation, _, err := svc.CastVote(context.Background(), pollID, optionID, "voter-1", 0, "")
+			require.NoError(t, err)
+
+			_, err = svc.ConfirmVote(context.Background(), tt.poll(pollID), tt.token(confirmation.Token), tt.voter)
+			assert.EqualError(t, err, "confirmation token is invalid or has expired")
+			repo.AssertNotCalled(t, "CastVote", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestConfirmVote_PollClosedBeforeConfirmation(t *testing.T) {
+	pollID := uuid.New()
+	optionID := uuid.New()
+	expiresAt := testNow.Add(30 * time.Second)
+	clock := &manualClock{now: testNow}
+
+	repo := new(mocks.MockPollRepository)
+	repo.On("GetPollByID", mock.Anything, pollID).Return(&models.Poll{
+		ID:                  pollID,
+		IsActive:            true,
+		ExpiresAt:           &expiresAt,
+		RequireConfirmation: true,
+	}, nil)
+	repo.On("HasVoted", mock.Anything, pollID, "voter-1").Return(false, nil, nil)
+	repo.On("GetPollOptions", mock.Anything, pollID).Return([]models.PollOption{{ID: optionID, PollID: pollID}}, nil)
+
+	svc := NewPollService(repo, PollServiceConfig{Clock: clock, VoteConfirmationTTL: time.Minute})
+
+	confirmation, _, err := svc.CastVote(context.Background(), pollID, optionID, "voter-1", 0, "")
+	require.NoError(t, err)
+
+	// The token is still valid but the poll has expired in the meantime
+	clock.Advance(45 * time.Second)
+	_, err = svc.ConfirmVote(context.Background(), pollID, confirmation.Token, "voter-1")
+	assert.EqualError(t, err, "poll has expired")
+	repo.AssertNotCalled(t, "CastVote", mock.Anything, mock.Anything)
+}
+
+func TestCastVote_WithoutConfirmationRecordsImmediately(t *testing.T) {
+	pollID := uuid.New()
+	optionID := uuid.New()
+
+	repo := new(mocks.MockPollRepository)
+	repo.On("GetPollByID", mock.Anything, pollID).Return(&models.Poll{ID: pollID, IsActive: true}, nil)
+	repo.On("HasVoted", mock.Anything, pollID, "voter-1").Return(false, nil, nil)
+	repo.On("GetPollOptions", mock.Anything, pollID).Return([]models.PollOption{{ID: optionID, PollID: pollID}}, nil)
+	repo.On("CastVote", mock.Anything, mock.Anything).Return(nil)
+
+	svc := NewPollService(repo, PollServiceConfig{})
+	confirmation, _, err := svc.CastVote(context.Background(), pollID, optionID, "voter-1", 0, "")
+
+	require.NoError(t, err)
+	assert.Nil(t, confirmation)
+	repo.AssertExpectations(t)
+}
+
+func TestMemoryPendingVoteStore_SweepsExpiredOnPut(t *testing.T) {
+	clock := &manualClock{now: testNow}
+	store := NewMemoryPendingVoteStore(clock)
+
+	store.Put("old", models.Vote{VoterIdentifier: "voter-1"}, testNow.Add(time.Minute))
+	clock.Advance(2 * time.Minute)
+	store.Put("new", models.Vote{VoterIdentifier: "voter-2"}, clock.Now().Add(time.Minute))
+
+	assert.Len(t, store.votes, 1)
+	vote, ok := store.Take("new")
+	assert.True(t, ok)
+	assert.Equal(t, "voter-2", vote.VoterIdentifier)
+}
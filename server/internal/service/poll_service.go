@@ -2,57 +2,188 @@ package service
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"fmt"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/moabdelazem/k8s-app/internal/cache"
 	"github.com/moabdelazem/k8s-app/internal/models"
 	"github.com/moabdelazem/k8s-app/internal/repository"
 	"github.com/moabdelazem/k8s-app/pkg/logger"
 	"go.uber.org/zap"
 )
 
+// PollServiceConfig holds tunable business rules for the poll service
+type PollServiceConfig struct {
+	MaxActivePollsPerOwner   int              // 0 = unlimited
+	MaxListOptionRows        int              // Most options across the polls of a listed page; 0 = unlimited
+	MinVoteWeight            int64            // Lowest weight accepted on weighted polls (defaults to 1)
+	MaxVoteWeight            int64            // Highest weight accepted on weighted polls (defaults to MinVoteWeight)
+	GroupVoterDedup          bool             // Reject voters who already voted on another poll in the same group
+	ResultsCacheTTL          time.Duration    // How long a poll's counts are reused for results reads; 0 = always read the database
+	ArchiveRetention         time.Duration    // How long closed polls stay in the live tables before ArchiveClosedPolls moves them; 0 = never
+	DefaultPollTTL           time.Duration    // Expiry assigned to polls created without one; 0 = never expire
+	MaxExpiryHorizon         time.Duration    // Furthest from now a poll may be set to expire; 0 = unlimited
+	VoteConfirmationTTL      time.Duration    // How long votes on confirmation-required polls await confirmation (defaults to DefaultVoteConfirmationTTL)
+	PendingVotes             PendingVoteStore // Holds unconfirmed votes; defaults to an in-memory store
+	ShareSecret              string           // HMAC secret signing share links; share links are disabled when empty
+	ShareLinkTTL             time.Duration    // How long share links stay valid (defaults to DefaultShareLinkTTL)
+	ReceiptSecret            string           // HMAC secret signing vote receipts; receipts are disabled when empty
+	ListWriteInsIndividually bool             // List each write-in vote in results instead of tallying answers by text
+	DeletedPollsGone         bool             // Report reads of deleted polls as ErrPollDeleted instead of ErrPollNotFound
+	DeleteProtectVotes       int64            // Polls with more votes than this are only deleted by DeletePollAsAdmin; 0 = never protected
+	SuspiciousSubnetMinVotes int64            // Votes from one /24 subnet that flag it as suspicious (defaults to DefaultSuspiciousSubnetMinVotes)
+	SuspiciousBurstWindow    time.Duration    // Window votes are bucketed into when looking for bursts (defaults to DefaultSuspiciousBurstWindow)
+	SuspiciousBurstMinVotes  int64            // Votes within one window that flag it as a burst (defaults to DefaultSuspiciousBurstMinVotes)
+	Clock                    Clock            // Defaults to the system clock when nil
+	LiveResults              Notifier         // Told about every recorded vote as it happens, e.g. to push live results; discarded when nil
+}
+
 type PollService struct {
-	repo repository.PollRepositoryInterface
+	repo         repository.PollRepositoryInterface
+	cfg          PollServiceConfig
+	clock        Clock
+	liveResults  Notifier
+	pendingVotes PendingVoteStore
+	results      *cache.Cache[uuid.UUID, *models.PollWithVote] // Polls read without a voter; nil when results caching is disabled
 }
 
-func NewPollService(repo repository.PollRepositoryInterface) *PollService {
-	return &PollService{repo: repo}
+func NewPollService(repo repository.PollRepositoryInterface, cfg PollServiceConfig) *PollService {
+	clock := cfg.Clock
+	if clock == nil {
+		clock = realClock{}
+	}
+	liveResults := cfg.LiveResults
+	if liveResults == nil {
+		liveResults = noopNotifier{}
+	}
+	if cfg.MinVoteWeight < 1 {
+		cfg.MinVoteWeight = 1
+	}
+	if cfg.MaxVoteWeight < cfg.MinVoteWeight {
+		cfg.MaxVoteWeight = cfg.MinVoteWeight
+	}
+	if cfg.VoteConfirmationTTL <= 0 {
+		cfg.VoteConfirmationTTL = DefaultVoteConfirmationTTL
+	}
+	if cfg.ShareLinkTTL <= 0 {
+		cfg.ShareLinkTTL = DefaultShareLinkTTL
+	}
+	if cfg.SuspiciousSubnetMinVotes <= 0 {
+		cfg.SuspiciousSubnetMinVotes = DefaultSuspiciousSubnetMinVotes
+	}
+	if cfg.SuspiciousBurstWindow <= 0 {
+		cfg.SuspiciousBurstWindow = DefaultSuspiciousBurstWindow
+	}
+	if cfg.SuspiciousBurstMinVotes <= 0 {
+		cfg.SuspiciousBurstMinVotes = DefaultSuspiciousBurstMinVotes
+	}
+	pendingVotes := cfg.PendingVotes
+	if pendingVotes == nil {
+		pendingVotes = NewMemoryPendingVoteStore(clock)
+	}
+	s := &PollService{repo: repo, cfg: cfg, clock: clock, liveResults: liveResults, pendingVotes: pendingVotes}
+	if cfg.ResultsCacheTTL > 0 {
+		s.results = cache.New[uuid.UUID, *models.PollWithVote](cfg.ResultsCacheTTL, clock.Now)
+	}
+	return s
 }
 
 // CreatePoll creates a new poll with validation
-func (s *PollService) CreatePoll(ctx context.Context, req *models.CreatePollRequest) (*models.PollWithOptions, error) {
-	// Validate request
-	if len(req.Question) < 5 || len(req.Question) > 500 {
-		return nil, fmt.Errorf("question must be between 5 and 500 characters")
+// ownerID identifies the creator (user ID or voter identifier) and may be empty
+// Invalid input is rejected with an error; questionable but valid input is reported
+// as non-blocking warnings alongside the created poll
+func (s *PollService) CreatePoll(ctx context.Context, req *models.CreatePollRequest, ownerID string) (*models.PollWithOptions, []string, error) {
+	// Validate request: field rules come from its validate tags, rules spanning fields follow
+	if err := ValidateRequest(req); err != nil {
+		return nil, nil, err
 	}
-
-	if len(req.Options) < 2 {
-		return nil, fmt.Errorf("poll must have at least 2 options")
+	if err := validateOptionCount(req.Options, req.AcknowledgementMode); err != nil {
+		return nil, nil, err
 	}
-
-	if len(req.Options) > 10 {
-		return nil, fmt.Errorf("poll can have at most 10 options")
+	// An acknowledgement is a plain yes; there is nothing to weigh, grade or write in
+	if req.AcknowledgementMode && (req.AllowWeighted || req.QuizMode || req.AllowWriteIn) {
+		return nil, nil, newValidationError(CodeAcknowledgementConflict, "acknowledgement polls cannot be weighted, quizzes or take write-ins")
 	}
 
-	// Validate each option
-	for i, opt := range req.Options {
-		if len(opt) < 1 || len(opt) > 200 {
-			return nil, fmt.Errorf("option %d must be between 1 and 200 characters", i+1)
+	// Quiz polls need an answer key; other polls must not carry one
+	if req.QuizMode && len(req.CorrectOptions) == 0 {
+		return nil, nil, newValidationError(CodeQuizNeedsCorrectOption, "quiz polls must mark at least one correct option")
+	}
+	if !req.QuizMode && len(req.CorrectOptions) > 0 {
+		return nil, nil, newValidationError(CodeCorrectOptionsWithoutQuiz, "correct options can only be set on quiz polls")
+	}
+	// A write-in has no answer key to be graded against
+	if req.QuizMode && req.AllowWriteIn {
+		return nil, nil, newValidationError(CodeWriteInWithQuiz, "write-in votes cannot be enabled on quiz polls")
+	}
+	for _, idx := range req.CorrectOptions {
+		if idx >= len(req.Options) {
+			return nil, nil, newValidationError(CodeInvalidCorrectOption, "correct option %d does not exist", idx)
 		}
 	}
+	if err := validateVotingWindow(req.VotingWindowStart, req.VotingWindowEnd, req.Timezone); err != nil {
+		return nil, nil, err
+	}
 
 	// Check expiration date
-	if req.ExpiresAt != nil && req.ExpiresAt.Before(time.Now()) {
-		return nil, fmt.Errorf("expiration date must be in the future")
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(s.clock.Now()) {
+		return nil, nil, newValidationError(CodeExpiryNotInFuture, "expiration date must be in the future")
+	}
+	// Catches expiries set years ahead by mistake
+	if req.ExpiresAt != nil && s.cfg.MaxExpiryHorizon > 0 {
+		latest := s.clock.Now().Add(s.cfg.MaxExpiryHorizon)
+		if req.ExpiresAt.After(latest) {
+			return nil, nil, newValidationError(CodeExpiryBeyondHorizon, "expiration date must be no later than %s", latest.UTC().Format(time.RFC3339))
+		}
+	}
+
+	group, err := normalizeGroup(req.Group)
+	if err != nil {
+		return nil, nil, err
+	}
+	metadata, err := normalizeMetadata(req.Metadata)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Enforce per-owner active poll cap
+	if err := s.checkActivePollLimit(ctx, ownerID); err != nil {
+		return nil, nil, err
 	}
 
 	// Create poll
 	poll := &models.Poll{
-		Question:    req.Question,
-		Description: req.Description,
-		ExpiresAt:   req.ExpiresAt,
-		IsActive:    true,
+		Question:            req.Question,
+		Description:         req.Description,
+		ExpiresAt:           req.ExpiresAt,
+		IsActive:            true,
+		AllowWeighted:       req.AllowWeighted,
+		RequireConfirmation: req.RequireConfirmation,
+		QuizMode:            req.QuizMode,
+		Group:               group,
+		RandomizeOptions:    req.RandomizeOptions,
+		AllowlistOnly:       req.AllowlistOnly,
+		AllowWriteIn:        req.AllowWriteIn,
+		MaxVotes:            req.MaxVotes,
+		VotingWindowStart:   req.VotingWindowStart,
+		VotingWindowEnd:     req.VotingWindowEnd,
+		Timezone:            req.Timezone,
+		AcknowledgementMode: req.AcknowledgementMode,
+		Metadata:            metadata,
+	}
+	if ownerID != "" {
+		poll.OwnerID = &ownerID
+	}
+
+	// Apply the default TTL when the creator did not choose an expiry
+	if poll.ExpiresAt == nil && s.cfg.DefaultPollTTL > 0 {
+		expiresAt := s.clock.Now().Add(s.cfg.DefaultPollTTL)
+		poll.ExpiresAt = &expiresAt
 	}
 
 	// Create options
@@ -63,12 +194,20 @@ func (s *PollService) CreatePoll(ctx context.Context, req *models.CreatePollRequ
 			Position:   i,
 		}
 	}
+	for _, idx := range req.CorrectOptions {
+		options[idx].IsCorrect = true
+	}
 
 	// Save to database
-	err := s.repo.CreatePoll(ctx, poll, options)
+	err = s.repo.CreatePoll(ctx, poll, options)
+	if errors.Is(err, repository.ErrOptionCountOutOfBounds) {
+		// Only reachable if the service rules drift from the database constraint
+		logger.Warn("Database rejected poll option count", zap.Error(err))
+		return nil, nil, newValidationError(CodeOptionCountOutOfBounds, "poll must have between 2 and 10 options")
+	}
 	if err != nil {
-		logger.Error("Failed to create poll", zap.Error(err))
-		return nil, fmt.Errorf("failed to create poll: %w", err)
+		logRepoError(ctx, "Failed to create poll", zap.Error(err))
+		return nil, nil, wrapRepoError("failed to create poll", err)
 	}
 
 	logger.Info("Poll created successfully",
@@ -80,160 +219,1009 @@ func (s *PollService) CreatePoll(ctx context.Context, req *models.CreatePollRequ
 	return &models.PollWithOptions{
 		Poll:    *poll,
 		Options: options,
-	}, nil
+	}, s.createWarnings(req, poll), nil
+}
+
+// validateQuestion checks a poll question against the CreatePollRequest tags
+func validateQuestion(question string) error {
+	return validateFields(&models.CreatePollRequest{Question: question}, "Question")
+}
+
+// validateOptionTexts checks the option count and each option against the CreatePollRequest tags
+func validateOptionTexts(options []string, acknowledgement bool) error {
+	if err := validateOptionCount(options, acknowledgement); err != nil {
+		return err
+	}
+	return validateFields(&models.CreatePollRequest{Options: options}, "Options")
+}
+
+// validateOptionCount checks the lower bound of the option count, which depends on the poll's mode:
+// acknowledgement polls take exactly one option, other polls at least 2
+func validateOptionCount(options []string, acknowledgement bool) error {
+	if acknowledgement && len(options) != 1 {
+		return newValidationError(CodeAcknowledgementOptionCount, "acknowledgement polls must have exactly 1 option")
+	}
+	if !acknowledgement && len(options) < 2 {
+		return newValidationError(CodeTooFewOptions, "poll must have at least 2 options")
+	}
+	return nil
+}
+
+// maxGroupLength matches the polls.poll_group column
+const maxGroupLength = 100
+
+// normalizeGroup trims a poll group name, treating a blank one as no group
+func normalizeGroup(group *string) (*string, error) {
+	if group == nil {
+		return nil, nil
+	}
+	trimmed := strings.TrimSpace(*group)
+	if trimmed == "" {
+		return nil, nil
+	}
+	if len(trimmed) > maxGroupLength {
+		return nil, newValidationError(CodeGroupLength, "group must be at most %d characters", maxGroupLength)
+	}
+	return &trimmed, nil
+}
+
+// Thresholds for non-blocking creation warnings
+const (
+	fewOptionsWarningThreshold = 2
+	expiresSoonWarningWindow   = time.Hour
+)
+
+// createWarnings reports valid but likely unintended choices in a poll being created
+// poll carries the effective expiry, including any default TTL
+func (s *PollService) createWarnings(req *models.CreatePollRequest, poll *models.Poll) []string {
+	var warnings []string
+
+	if len(req.Options) <= fewOptionsWarningThreshold {
+		warnings = append(warnings, fmt.Sprintf("poll has only %d options", len(req.Options)))
+	}
+
+	if poll.ExpiresAt != nil && poll.ExpiresAt.Sub(s.clock.Now()) < expiresSoonWarningWindow {
+		warnings = append(warnings, "poll expires in less than an hour")
+	}
+
+	seen := make(map[string]int, len(req.Options))
+	for i, opt := range req.Options {
+		key := strings.ToLower(strings.TrimSpace(opt))
+		if first, ok := seen[key]; ok {
+			warnings = append(warnings, fmt.Sprintf("option %d duplicates option %d", i+1, first+1))
+			continue
+		}
+		seen[key] = i
+	}
+
+	return warnings
+}
+
+// checkActivePollLimit rejects creation when the owner already has too many active polls
+func (s *PollService) checkActivePollLimit(ctx context.Context, ownerID string) error {
+	if s.cfg.MaxActivePollsPerOwner <= 0 || ownerID == "" {
+		return nil
+	}
+
+	count, err := s.repo.CountActivePollsByOwner(ctx, ownerID)
+	if err != nil {
+		return wrapRepoError("failed to count active polls", err)
+	}
+
+	if count >= int64(s.cfg.MaxActivePollsPerOwner) {
+		logger.Warn("Active poll limit reached",
+			zap.String("owner", ownerID),
+			zap.Int64("active_polls", count),
+			zap.Int("limit", s.cfg.MaxActivePollsPerOwner),
+		)
+		return fmt.Errorf("%w: at most %d active polls allowed", ErrActivePollLimitReached, s.cfg.MaxActivePollsPerOwner)
+	}
+
+	return nil
 }
 
 // GetPollResults retrieves poll with results and checks if voter has voted
+// An empty voterIdentifier returns results without the caller's vote status, with
+// options in position order even on polls that randomize them for voters
+// Deleted polls are reported as ErrPollDeleted or ErrPollNotFound, depending on DeletedPollsGone
 func (s *PollService) GetPollResults(ctx context.Context, pollID uuid.UUID, voterIdentifier string) (*models.PollResults, error) {
-	// Get poll
+	poll, err := s.pollWithVote(ctx, pollID, voterIdentifier)
+	if err != nil {
+		return nil, err
+	}
+	if poll.DeletedAt != nil {
+		return nil, s.deletedPollError()
+	}
+	hasVoted := poll.VotedOption != nil || poll.VotedWriteIn
+
+	// Calculate percentages
+	results := make([]models.OptionResult, len(poll.Options))
+	for i, opt := range poll.Options {
+		results[i] = models.OptionResult{PollOption: opt}
+	}
+	setPercentages(results, poll.TotalVotes)
+
+	pollResults := &models.PollResults{
+		Poll:        poll.Poll,
+		Options:     results,
+		TotalVotes:  poll.TotalVotes,
+		HasVoted:    hasVoted,
+		VotedOption: poll.VotedOption,
+		Leading:     leadingOptions(results, poll.TotalVotes),
+	}
+	pollResults.WinningOption = winningOption(pollResults.Leading)
+	if poll.AllowWriteIn {
+		pollResults.WriteIns = writeInResults(poll)
+	}
+	if poll.AcknowledgementMode {
+		pollResults.Acknowledgements = acknowledgements(poll.Poll)
+	}
+	if poll.QuizMode && hasVoted {
+		revealAnswers(pollResults)
+	}
+	if poll.RandomizeOptions && voterIdentifier != "" {
+		shuffleOptions(pollResults.Options, pollID, voterIdentifier)
+	}
+	return pollResults, nil
+}
+
+// acknowledgements counts the voters who acknowledged an acknowledgement poll
+// Votes on such polls are never weighted, so each adds one to the total
+func acknowledgements(poll models.Poll) *int64 {
+	count := poll.TotalVotes
+	return &count
+}
+
+// deletedPollError is the error reads of a deleted poll fail with, so clients can tell a
+// removed poll from one that never existed when DeletedPollsGone is set
+func (s *PollService) deletedPollError() error {
+	if s.cfg.DeletedPollsGone {
+		return ErrPollDeleted
+	}
+	return ErrPollNotFound
+}
+
+// pollWithVote reads a poll, its options and voterIdentifier's vote on it
+// Without a results cache this is a single database round trip. With one, the poll and
+// its options come from the cache, where concurrent misses share one read, and only the
+// vote is looked up; anonymous lookups skip it.
+func (s *PollService) pollWithVote(ctx context.Context, pollID uuid.UUID, voterIdentifier string) (*models.PollWithVote, error) {
+	if s.results == nil {
+		return s.loadPollWithVote(ctx, pollID, voterIdentifier)
+	}
+
+	// The read is shared with other callers, so it must not fail because this caller went away
+	cached, err := s.results.Get(pollID, func() (*models.PollWithVote, error) {
+		return s.loadPollWithVote(context.WithoutCancel(ctx), pollID, "")
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Archived votes are not in the table HasVoted reads
+	if cached.Archived && voterIdentifier != "" {
+		return s.loadPollWithVote(ctx, pollID, voterIdentifier)
+	}
+
+	// Copy before adding the caller's vote; the options stay shared and must not be modified
+	poll := *cached
+	if voterIdentifier != "" {
+		hasVoted, votedOptionID, err := s.repo.HasVoted(ctx, pollID, voterIdentifier)
+		if err != nil {
+			logger.Warn("Failed to check vote status", zap.Error(err))
+		}
+		if hasVoted {
+			poll.VotedOption = votedOptionID
+			poll.VotedWriteIn = votedOptionID == nil
+		}
+	}
+	return &poll, nil
+}
+
+// loadPollWithVote reads a poll with its results, falling back to the archive for polls moved there
+func (s *PollService) loadPollWithVote(ctx context.Context, pollID uuid.UUID, voterIdentifier string) (*models.PollWithVote, error) {
+	poll, err := s.repo.GetPollWithResults(ctx, pollID, voterIdentifier)
+	if err != nil {
+		return nil, wrapRepoError("failed to get poll results", err)
+	}
+	if poll != nil {
+		return poll, s.loadWriteIns(ctx, poll)
+	}
+
+	poll, err = s.repo.GetArchivedPollWithResults(ctx, pollID, voterIdentifier)
+	if err != nil {
+		return nil, wrapRepoError("failed to get archived poll results", err)
+	}
+	if poll == nil {
+		return nil, ErrPollNotFound
+	}
+	poll.Archived = true
+	return poll, s.loadWriteIns(ctx, poll)
+}
+
+// invalidateResults drops the cached counts of a poll after it changed
+func (s *PollService) invalidateResults(pollID uuid.UUID) {
+	if s.results != nil {
+		s.results.Invalidate(pollID)
+	}
+}
+
+// revealAnswers marks the correct options of a quiz and whether the voter's choice was one of them
+// Callers must only reveal answers to voters who have already voted
+func revealAnswers(results *models.PollResults) {
+	for i := range results.Options {
+		isCorrect := results.Options[i].PollOption.IsCorrect
+		results.Options[i].IsCorrect = &isCorrect
+		if results.VotedOption != nil && results.Options[i].ID == *results.VotedOption {
+			results.AnsweredCorrectly = &isCorrect
+		}
+	}
+}
+
+// setPercentages computes each option's share of total
+func setPercentages(results []models.OptionResult, total int64) {
+	for i := range results {
+		results[i].Percentage = percentage(results[i].VoteCount, total)
+	}
+}
+
+// percentage returns count as a share of total, or 0 without votes
+func percentage(count, total int64) float64 {
+	if total <= 0 {
+		return 0
+	}
+	return float64(count) / float64(total) * 100
+}
+
+// leadingOptions returns every option sharing the highest vote count, so ties are reported
+// rather than resolved arbitrarily; it is empty while the poll has no votes
+func leadingOptions(results []models.OptionResult, total int64) []uuid.UUID {
+	leading := []uuid.UUID{}
+	if total == 0 {
+		return leading
+	}
+
+	var top int64
+	for _, opt := range results {
+		top = max(top, opt.VoteCount)
+	}
+	for _, opt := range results {
+		if opt.VoteCount == top {
+			leading = append(leading, opt.ID)
+		}
+	}
+	return leading
+}
+
+// winningOption is the option leading alone, or nil when several options tie or there are no votes
+func winningOption(leading []uuid.UUID) *uuid.UUID {
+	if len(leading) != 1 {
+		return nil
+	}
+	winner := leading[0]
+	return &winner
+}
+
+// PreviewVote projects the results as if one more vote were cast for optionID, persisting nothing
+// The projection ignores whether the poll is still open so it can back "what-if" views on any poll
+func (s *PollService) PreviewVote(ctx context.Context, pollID uuid.UUID, optionID uuid.UUID) (*models.PollResults, error) {
+	results, err := s.GetPollResults(ctx, pollID, "")
+	if err != nil {
+		return nil, err
+	}
+
+	found := false
+	for i := range results.Options {
+		if results.Options[i].ID == optionID {
+			results.Options[i].VoteCount++
+			found = true
+			break
+		}
+	}
+	if !found {
+		return nil, newValidationError(CodeInvalidOption, "invalid option for this poll")
+	}
+
+	results.TotalVotes++
+	results.Poll.TotalVotes = results.TotalVotes
+	setPercentages(results.Options, results.TotalVotes)
+	results.Leading = leadingOptions(results.Options, results.TotalVotes)
+	results.WinningOption = winningOption(results.Leading)
+	if results.WriteIns != nil {
+		results.WriteIns.Percentage = percentage(results.WriteIns.VoteCount, results.TotalVotes)
+	}
+
+	return results, nil
+}
+
+// GetPollOptions retrieves only the options of a poll, without results or vote status
+// Polls that randomize options return them in voterIdentifier's order, as GetPollResults does
+func (s *PollService) GetPollOptions(ctx context.Context, pollID uuid.UUID, voterIdentifier string) ([]models.PollOption, error) {
 	poll, err := s.repo.GetPollByID(ctx, pollID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get poll: %w", err)
+		return nil, wrapRepoError("failed to get poll", err)
 	}
 	if poll == nil {
-		return nil, fmt.Errorf("poll not found")
+		return nil, ErrPollNotFound
 	}
 
-	// Get options
 	options, err := s.repo.GetPollOptions(ctx, pollID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get options: %w", err)
+		return nil, wrapRepoError("failed to get options", err)
+	}
+	if options == nil {
+		options = []models.PollOption{}
+	}
+	if poll.RandomizeOptions && voterIdentifier != "" {
+		shuffleOptions(options, pollID, voterIdentifier)
+	}
+
+	return options, nil
+}
+
+// GetVoteStatus reports whether voterIdentifier has voted on a poll and for which option
+// It skips the options and counts that GetPollResults reads
+func (s *PollService) GetVoteStatus(ctx context.Context, pollID uuid.UUID, voterIdentifier string) (*models.VoteStatus, error) {
+	poll, err := s.repo.GetPollByID(ctx, pollID)
+	if err != nil {
+		return nil, wrapRepoError("failed to get poll", err)
+	}
+	if poll == nil {
+		return nil, ErrPollNotFound
 	}
 
-	// Check if voter has voted
-	hasVoted, votedOptionID, err := s.repo.HasVoted(ctx, pollID, voterIdentifier)
+	hasVoted, votedOption, err := s.repo.HasVoted(ctx, pollID, voterIdentifier)
 	if err != nil {
-		logger.Warn("Failed to check vote status", zap.Error(err))
+		return nil, wrapRepoError("failed to check vote status", err)
 	}
 
-	// Calculate percentages
-	results := make([]models.OptionResult, len(options))
-	for i, opt := range options {
-		percentage := 0.0
-		if poll.TotalVotes > 0 {
-			percentage = float64(opt.VoteCount) / float64(poll.TotalVotes) * 100
-		}
-		results[i] = models.OptionResult{
-			PollOption: opt,
-			Percentage: percentage,
-		}
+	return &models.VoteStatus{HasVoted: hasVoted, VotedOption: votedOption}, nil
+}
+
+// VotingStatus tells whether a poll accepts votes right now, and if not, why
+// Deleted polls report closed, as they no longer accept votes.
+func (s *PollService) VotingStatus(ctx context.Context, pollID uuid.UUID) (*models.VotingStatus, error) {
+	poll, err := s.repo.GetPollByID(ctx, pollID)
+	if err != nil {
+		return nil, wrapRepoError("failed to get poll", err)
+	}
+	if poll == nil {
+		return nil, ErrPollNotFound
 	}
 
-	return &models.PollResults{
-		Poll:        *poll,
-		Options:     results,
-		TotalVotes:  poll.TotalVotes,
-		HasVoted:    hasVoted,
-		VotedOption: votedOptionID,
-	}, nil
+	status := votingStatus(poll, s.clock.Now())
+	return &status, nil
+}
+
+// votingStatus is whether poll accepts votes at now, checked in the order CastVote turns votes away
+func votingStatus(poll *models.Poll, now time.Time) models.VotingStatus {
+	switch {
+	case !poll.IsActive:
+		return models.VotingStatus{Status: models.VotingClosed}
+	// A poll expiring exactly now is closed, matching the list queries
+	case poll.ExpiresAt != nil && !poll.ExpiresAt.After(now):
+		return models.VotingStatus{Status: models.VotingExpired}
+	}
+	// Polls with a voting window only accept votes during those hours of the day in its timezone
+	if open, opensAt := votingWindowOpen(poll, now); !open {
+		return models.VotingStatus{Status: models.VotingNotStarted, OpensAt: &opensAt}
+	}
+	if poll.MaxVotes != nil && poll.TotalVotes >= *poll.MaxVotes {
+		return models.VotingStatus{Status: models.VotingFull}
+	}
+	return models.VotingStatus{Status: models.VotingOpen, AcceptingVotes: true}
+}
+
+// ListVotesByVoter returns a page of the votes voterIdentifier has cast across all polls, newest first
+func (s *PollService) ListVotesByVoter(ctx context.Context, voterIdentifier string, limit, offset int) (*models.VoterVoteList, error) {
+	limit, offset = normalizePage(limit, offset)
+
+	votes, err := s.repo.ListVotesByVoter(ctx, voterIdentifier, limit, offset)
+	if err != nil {
+		return nil, wrapRepoError("failed to list votes", err)
+	}
+
+	return &models.VoterVoteList{Votes: votes, Limit: limit, Offset: offset}, nil
+}
+
+// Vote timeline limits
+const (
+	MinTimelineBucket  = time.Minute
+	MaxTimelineBucket  = 7 * 24 * time.Hour
+	MaxTimelineBuckets = 500
+)
+
+// GetVoteTimeline returns per-option vote counts grouped into buckets of the given width
+// The bucket is clamped to [MinTimelineBucket, MaxTimelineBucket]; only the most recent
+// MaxTimelineBuckets buckets are returned
+func (s *PollService) GetVoteTimeline(ctx context.Context, pollID uuid.UUID, bucket time.Duration) (*models.VoteTimeline, error) {
+	if bucket <= 0 {
+		return nil, newValidationError(CodeInvalidBucket, "bucket must be a positive duration")
+	}
+	bucket = min(max(bucket, MinTimelineBucket), MaxTimelineBucket).Truncate(time.Second)
+
+	poll, err := s.repo.GetPollByID(ctx, pollID)
+	if err != nil {
+		return nil, wrapRepoError("failed to get poll", err)
+	}
+	if poll == nil {
+		return nil, ErrPollNotFound
+	}
+
+	buckets, err := s.repo.GetVoteTimeline(ctx, pollID, bucket)
+	if err != nil {
+		return nil, wrapRepoError("failed to get vote timeline", err)
+	}
+
+	timeline := &models.VoteTimeline{
+		PollID:  pollID,
+		Bucket:  bucket.String(),
+		Buckets: buckets,
+	}
+	if len(buckets) > MaxTimelineBuckets {
+		timeline.Buckets = buckets[len(buckets)-MaxTimelineBuckets:]
+		timeline.Truncated = true
+	}
+	if timeline.Buckets == nil {
+		timeline.Buckets = []models.TimelineBucket{}
+	}
+
+	return timeline, nil
 }
 
 // CastVote casts a vote on a poll
-func (s *PollService) CastVote(ctx context.Context, pollID uuid.UUID, optionID uuid.UUID, voterIdentifier string) error {
+// weight is only honored on polls allowing weighted votes; 0 means the default weight of 1
+// On polls requiring confirmation the vote is held as pending and a confirmation is returned
+// instead; it only counts once passed back to ConfirmVote. Otherwise the confirmation is nil
+// and the vote's receipt is returned, or nil when receipts are disabled.
+// shareToken, when set, attributes the vote to the campaign of the share link the voter arrived with.
+func (s *PollService) CastVote(ctx context.Context, pollID uuid.UUID, optionID uuid.UUID, voterIdentifier string, weight int64, shareToken string) (*models.VoteConfirmation, *models.VoteReceipt, error) {
+	vote := &models.Vote{
+		PollID:          pollID,
+		OptionID:        optionID,
+		VoterIdentifier: voterIdentifier,
+		Weight:          weight,
+	}
+	return s.submitVote(ctx, vote, shareToken)
+}
+
+// submitVote validates a vote, then holds it for confirmation on polls requiring it and records it otherwise
+func (s *PollService) submitVote(ctx context.Context, vote *models.Vote, shareToken string) (*models.VoteConfirmation, *models.VoteReceipt, error) {
+	poll, err := s.validateVote(ctx, vote)
+	if err != nil {
+		return nil, nil, err
+	}
+	vote.Campaign = s.shareCampaign(vote.PollID, shareToken)
+
+	if poll.RequireConfirmation {
+		confirmation, err := s.holdVote(vote)
+		return confirmation, nil, err
+	}
+
+	if err := s.recordVote(ctx, vote); err != nil {
+		return nil, nil, err
+	}
+	return nil, s.issueReceipt(vote), nil
+}
+
+// ValidateVote runs every check CastVote, or CastWriteInVote when writeIn is set, makes on a vote without casting it
+// It returns the outcome the vote would have, or the error casting it would fail with. The checks race with
+// other votes, so a vote that validates may still be turned away, e.g. once the poll fills up.
+func (s *PollService) ValidateVote(ctx context.Context, pollID uuid.UUID, optionID uuid.UUID, writeIn string, voterIdentifier string, weight int64) (*models.VoteValidation, error) {
+	vote := &models.Vote{
+		PollID:          pollID,
+		OptionID:        optionID,
+		VoterIdentifier: voterIdentifier,
+		Weight:          weight,
+	}
+	if writeIn != "" {
+		sanitized, err := sanitizeWriteIn(writeIn)
+		if err != nil {
+			return nil, err
+		}
+		vote.OptionID = uuid.Nil
+		vote.WriteIn = &sanitized
+	}
+
+	poll, err := s.validateVote(ctx, vote)
+	if err != nil {
+		return nil, err
+	}
+
+	validation := &models.VoteValidation{Outcome: models.VoteOutcomeRecorded, Weight: vote.Weight}
+	if poll.RequireConfirmation {
+		validation.Outcome = models.VoteOutcomePendingConfirmation
+	}
+	return validation, nil
+}
+
+// ConfirmVote commits a vote held by CastVote and returns its receipt, or nil when receipts are disabled
+// The token must have been issued to the same voter for the same poll and not have expired;
+// the vote is re-validated since the poll may have closed in the meantime
+func (s *PollService) ConfirmVote(ctx context.Context, pollID uuid.UUID, token string, voterIdentifier string) (*models.VoteReceipt, error) {
+	if token == "" {
+		return nil, newValidationError(CodeConfirmationRequired, "confirmation token is required")
+	}
+
+	pending, ok := s.pendingVotes.Take(token)
+	if !ok || pending.PollID != pollID || pending.VoterIdentifier != voterIdentifier {
+		return nil, newValidationError(CodeConfirmationInvalid, "confirmation token is invalid or has expired")
+	}
+
+	if _, err := s.validateVote(ctx, &pending); err != nil {
+		return nil, err
+	}
+
+	if err := s.recordVote(ctx, &pending); err != nil {
+		return nil, err
+	}
+	return s.issueReceipt(&pending), nil
+}
+
+// validateVote checks that the voter may cast vote, for its option or as a write-in, and resolves its weight
+// It returns the poll
+func (s *PollService) validateVote(ctx context.Context, vote *models.Vote) (*models.Poll, error) {
+	pollID, voterIdentifier := vote.PollID, vote.VoterIdentifier
+
 	// Get poll
 	poll, err := s.repo.GetPollByID(ctx, pollID)
 	if err != nil {
-		return fmt.Errorf("failed to get poll: %w", err)
+		return nil, wrapRepoError("failed to get poll", err)
 	}
 	if poll == nil {
-		return fmt.Errorf("poll not found")
+		return nil, ErrPollNotFound
 	}
 
-	// Check if poll is active
-	if !poll.IsActive {
-		return fmt.Errorf("poll is not active")
+	// Closed, expired and out-of-window polls turn every voter away; a full poll is
+	// turned away below, once the vote's weight is known
+	switch status := votingStatus(poll, s.clock.Now()); status.Status {
+	case models.VotingClosed:
+		return nil, newValidationError(CodePollInactive, "poll is not active")
+	case models.VotingExpired:
+		return nil, newValidationError(CodePollExpired, "poll has expired")
+	case models.VotingNotStarted:
+		return nil, errOutsideVotingWindow(poll)
 	}
 
-	// Check if poll is expired
-	if poll.ExpiresAt != nil && poll.ExpiresAt.Before(time.Now()) {
-		return fmt.Errorf("poll has expired")
+	// Private polls only accept voters an admin has added to the allowlist
+	if poll.AllowlistOnly {
+		allowed, err := s.repo.IsVoterAllowed(ctx, pollID, voterIdentifier)
+		if err != nil {
+			return nil, wrapRepoError("failed to check voter allowlist", err)
+		}
+		if !allowed {
+			return nil, ErrVoterNotAllowed
+		}
+	}
+
+	// Validate vote weight
+	vote.Weight, err = s.resolveVoteWeight(poll, vote.Weight)
+	if err != nil {
+		return nil, err
+	}
+
+	// Turn voters away early once the poll is full; the vote transaction enforces the cap
+	if poll.MaxVotes != nil && poll.TotalVotes+vote.Weight > *poll.MaxVotes {
+		return nil, newValidationError(CodePollFull, "poll is full")
 	}
 
 	// Check if voter has already voted
 	hasVoted, _, err := s.repo.HasVoted(ctx, pollID, voterIdentifier)
 	if err != nil {
-		return fmt.Errorf("failed to check vote status: %w", err)
+		return nil, wrapRepoError("failed to check vote status", err)
 	}
 	if hasVoted {
-		return fmt.Errorf("you have already voted on this poll")
+		return nil, newValidationError(CodeAlreadyVoted, "you have already voted on this poll")
+	}
+
+	// Polls in a group share one vote per voter; concurrent votes on different
+	// polls of the group are not serialized, so this is best effort
+	if s.cfg.GroupVoterDedup && poll.Group != nil {
+		votedInGroup, err := s.repo.HasVotedInGroup(ctx, *poll.Group, voterIdentifier)
+		if err != nil {
+			return nil, wrapRepoError("failed to check group vote status", err)
+		}
+		if votedInGroup {
+			return nil, newValidationError(CodeAlreadyVotedInGroup, "you have already voted on a poll in this group")
+		}
+	}
+
+	if vote.WriteIn != nil {
+		if !poll.AllowWriteIn {
+			return nil, newValidationError(CodeWriteInDisabled, "write-in votes are not enabled for this poll")
+		}
+		return poll, nil
 	}
 
 	// Verify option belongs to this poll
 	options, err := s.repo.GetPollOptions(ctx, pollID)
 	if err != nil {
-		return fmt.Errorf("failed to get poll options: %w", err)
+		return nil, wrapRepoError("failed to get poll options", err)
 	}
 
 	validOption := false
 	for _, opt := range options {
-		if opt.ID == optionID {
+		if opt.ID == vote.OptionID {
 			validOption = true
 			break
 		}
 	}
 	if !validOption {
-		return fmt.Errorf("invalid option for this poll")
+		return nil, newValidationError(CodeInvalidOption, "invalid option for this poll")
 	}
 
-	// Cast vote
-	vote := &models.Vote{
-		PollID:          pollID,
-		OptionID:        optionID,
-		VoterIdentifier: voterIdentifier,
+	return poll, nil
+}
+
+// holdVote stores vote as pending and returns the token needed to confirm it
+func (s *PollService) holdVote(vote *models.Vote) (*models.VoteConfirmation, error) {
+	token, err := newConfirmationToken()
+	if err != nil {
+		logger.Error("Failed to generate confirmation token", zap.Error(err))
+		return nil, err
 	}
 
-	err = s.repo.CastVote(ctx, vote)
+	expiresAt := s.clock.Now().Add(s.cfg.VoteConfirmationTTL)
+	s.pendingVotes.Put(token, *vote, expiresAt)
+
+	logger.Info("Vote pending confirmation",
+		zap.String("poll_id", vote.PollID.String()),
+		zap.String("option_id", vote.OptionID.String()),
+		zap.String("voter", vote.VoterIdentifier),
+		zap.Time("expires_at", expiresAt),
+	)
+
+	return &models.VoteConfirmation{Token: token, ExpiresAt: expiresAt}, nil
+}
+
+// recordVote persists a validated vote, or write-in vote, and notifies subscribers
+func (s *PollService) recordVote(ctx context.Context, vote *models.Vote) error {
+	var err error
+	if vote.WriteIn != nil {
+		err = s.repo.CastWriteInVote(ctx, vote)
+	} else {
+		err = s.repo.CastVote(ctx, vote)
+	}
+	if errors.Is(err, repository.ErrAlreadyVoted) {
+		// The voter's other vote was recorded after validateVote checked
+		return newValidationError(CodeAlreadyVoted, "you have already voted on this poll")
+	}
+	if errors.Is(err, repository.ErrPollFull) {
+		// Concurrent votes filled the poll after validateVote checked
+		return newValidationError(CodePollFull, "poll is full")
+	}
 	if err != nil {
-		logger.Error("Failed to cast vote",
+		logRepoError(ctx, "Failed to cast vote",
 			zap.Error(err),
-			zap.String("poll_id", pollID.String()),
-			zap.String("option_id", optionID.String()),
+			zap.String("poll_id", vote.PollID.String()),
+			zap.String("option_id", vote.OptionID.String()),
 		)
-		return fmt.Errorf("failed to cast vote: %w", err)
+		return wrapRepoError("failed to cast vote", err)
 	}
+	s.invalidateResults(vote.PollID)
 
 	logger.Info("Vote cast successfully",
-		zap.String("poll_id", pollID.String()),
-		zap.String("option_id", optionID.String()),
-		zap.String("voter", voterIdentifier),
+		zap.String("poll_id", vote.PollID.String()),
+		zap.String("option_id", vote.OptionID.String()),
+		zap.String("voter", vote.VoterIdentifier),
+		zap.Int64("weight", vote.Weight),
 	)
 
+	// Webhooks get the event through the outbox; live results are told directly to skip the relay delay
+	s.liveResults.Notify(models.PollEvent{
+		Type:       models.EventVoteCast,
+		PollID:     vote.PollID,
+		OccurredAt: s.clock.Now(),
+	})
+
 	return nil
 }
 
-// ListPolls lists polls with pagination and includes options
-func (s *PollService) ListPolls(ctx context.Context, limit, offset int, activeOnly bool) ([]models.PollWithOptions, int64, error) {
-	if limit <= 0 || limit > 100 {
-		limit = 20 // Default limit
+// resolveVoteWeight applies the default weight and enforces the poll's weighting rules
+func (s *PollService) resolveVoteWeight(poll *models.Poll, weight int64) (int64, error) {
+	if weight == 0 {
+		weight = 1
 	}
-	if offset < 0 {
-		offset = 0
+
+	if !poll.AllowWeighted {
+		if weight != 1 {
+			return 0, newValidationError(CodeWeightedVotingDisabled, "weighted voting is not enabled for this poll")
+		}
+		return weight, nil
+	}
+
+	if weight < s.cfg.MinVoteWeight || weight > s.cfg.MaxVoteWeight {
+		return 0, newValidationError(CodeVoteWeightOutOfRange, "vote weight must be between %d and %d", s.cfg.MinVoteWeight, s.cfg.MaxVoteWeight)
 	}
 
+	return weight, nil
+}
+
+// ListPolls lists polls with pagination and includes options
+// The page's limit is the effective one, lowered when option-heavy polls hit MaxListOptionRows
+func (s *PollService) ListPolls(ctx context.Context, limit, offset int, activeOnly bool) (*models.PollList, error) {
+	limit, offset = normalizePage(limit, offset)
+
 	polls, err := s.repo.ListPollsWithOptions(ctx, limit, offset, activeOnly)
 	if err != nil {
-		return nil, 0, fmt.Errorf("failed to list polls: %w", err)
+		return nil, wrapRepoError("failed to list polls", err)
+	}
+	if polls == nil {
+		polls = []models.PollWithOptions{}
+	}
+
+	polls, limit = s.capOptionRows(polls, limit)
+
+	return &models.PollList{
+		Polls:  polls,
+		Total:  s.CountPolls(ctx, activeOnly),
+		Limit:  limit,
+		Offset: offset,
+	}, nil
+}
+
+// ListPollResults lists one page of polls with their results, as read without a voter
+// The page takes two queries however many polls it holds: one for the polls and one for all their options.
+// Write-in tallies are left out since they would take a query per poll.
+func (s *PollService) ListPollResults(ctx context.Context, limit, offset int, activeOnly bool) (*models.PollResultsList, error) {
+	limit, offset = normalizePage(limit, offset)
+
+	polls, err := s.repo.ListPolls(ctx, limit, offset, activeOnly)
+	if err != nil {
+		return nil, wrapRepoError("failed to list polls", err)
+	}
+
+	ids := make([]uuid.UUID, len(polls))
+	for i, poll := range polls {
+		ids[i] = poll.ID
+	}
+	options, err := s.repo.GetOptionsForPolls(ctx, ids)
+	if err != nil {
+		return nil, wrapRepoError("failed to get options for polls", err)
+	}
+
+	results := make([]models.PollResults, len(polls))
+	for i, poll := range polls {
+		optionResults := make([]models.OptionResult, len(options[poll.ID]))
+		for j, opt := range options[poll.ID] {
+			optionResults[j] = models.OptionResult{PollOption: opt}
+		}
+		setPercentages(optionResults, poll.TotalVotes)
+
+		results[i] = models.PollResults{
+			Poll:       poll,
+			Options:    optionResults,
+			TotalVotes: poll.TotalVotes,
+			Leading:    leadingOptions(optionResults, poll.TotalVotes),
+		}
+		results[i].WinningOption = winningOption(results[i].Leading)
+		if poll.AcknowledgementMode {
+			results[i].Acknowledgements = acknowledgements(poll)
+		}
+	}
+
+	return &models.PollResultsList{
+		Results: results,
+		Total:   s.CountPolls(ctx, activeOnly),
+		Limit:   limit,
+		Offset:  offset,
+	}, nil
+}
+
+// capOptionRows trims a page to the polls whose options fit in MaxListOptionRows, keeping at least one poll
+// The limit drops to the number of polls kept so the next page starts right after them
+func (s *PollService) capOptionRows(polls []models.PollWithOptions, limit int) ([]models.PollWithOptions, int) {
+	if s.cfg.MaxListOptionRows <= 0 {
+		return polls, limit
+	}
+
+	rows := 0
+	for i, poll := range polls {
+		rows += len(poll.Options)
+		if rows > s.cfg.MaxListOptionRows && i > 0 {
+			logger.Debug("Poll page clamped by option rows",
+				zap.Int("requested_limit", limit),
+				zap.Int("effective_limit", i),
+				zap.Int("max_option_rows", s.cfg.MaxListOptionRows),
+			)
+			return polls[:i], i
+		}
+	}
+	return polls, limit
+}
+
+// MaxBatchPollIDs is the maximum number of polls that can be fetched in one batch request
+const MaxBatchPollIDs = 50
+
+// GetPollsByIDs retrieves several polls with their options in the requested order
+// Polls that do not exist are omitted from the result
+func (s *PollService) GetPollsByIDs(ctx context.Context, ids []uuid.UUID) ([]models.PollWithOptions, error) {
+	if len(ids) == 0 {
+		return nil, newValidationError(CodeBatchIDsRequired, "at least one poll ID is required")
+	}
+	if len(ids) > MaxBatchPollIDs {
+		return nil, newValidationError(CodeBatchTooManyIDs, "at most %d poll IDs can be requested at once", MaxBatchPollIDs)
+	}
+
+	polls, err := s.repo.GetPollsByIDs(ctx, ids)
+	if err != nil {
+		return nil, wrapRepoError("failed to get polls", err)
+	}
+	if polls == nil {
+		polls = []models.PollWithOptions{}
+	}
+
+	return polls, nil
+}
+
+// MaxRelatedPolls is the maximum number of a creator's other polls returned alongside a poll
+const MaxRelatedPolls = 5
+
+// GetRelatedPolls retrieves other active polls by the creator of a poll, in listing order
+// Polls created without an owner have no related polls
+func (s *PollService) GetRelatedPolls(ctx context.Context, pollID uuid.UUID) ([]models.PollWithOptions, error) {
+	poll, err := s.repo.GetPollByID(ctx, pollID)
+	if err != nil {
+		return nil, wrapRepoError("failed to get poll", err)
+	}
+	if poll == nil {
+		return nil, ErrPollNotFound
+	}
+	if poll.OwnerID == nil {
+		return []models.PollWithOptions{}, nil
+	}
+
+	polls, err := s.repo.ListActiveByOwnerExcluding(ctx, *poll.OwnerID, pollID, MaxRelatedPolls)
+	if err != nil {
+		return nil, wrapRepoError("failed to list related polls", err)
 	}
+	if polls == nil {
+		polls = []models.PollWithOptions{}
+	}
+
+	return polls, nil
+}
+
+// MaxFeaturedPolls caps the featured listing; it is meant for a homepage, not for paging through
+const MaxFeaturedPolls = 50
+
+// ListFeaturedPolls returns up to limit open featured polls, in the order editors ranked them
+// A limit outside 1..MaxFeaturedPolls lists MaxFeaturedPolls polls
+func (s *PollService) ListFeaturedPolls(ctx context.Context, limit int) ([]models.PollWithOptions, error) {
+	if limit <= 0 || limit > MaxFeaturedPolls {
+		limit = MaxFeaturedPolls
+	}
+
+	polls, err := s.repo.ListFeaturedPolls(ctx, limit)
+	if err != nil {
+		return nil, wrapRepoError("failed to list featured polls", err)
+	}
+	if polls == nil {
+		polls = []models.PollWithOptions{}
+	}
+	return polls, nil
+}
+
+// StreamPolls passes each poll of a page to fn as it is read, without buffering the page
+// Streamed pages are not capped by MaxListOptionRows since they are never held in memory
+// Pagination is normalized the same way as ListPolls; errors returned by fn are passed through
+func (s *PollService) StreamPolls(ctx context.Context, limit, offset int, activeOnly bool, fn func(models.PollWithOptions) error) error {
+	limit, offset = normalizePage(limit, offset)
 
+	var fnErr error
+	err := s.repo.StreamPollsWithOptions(ctx, limit, offset, activeOnly, func(poll models.PollWithOptions) error {
+		fnErr = fn(poll)
+		return fnErr
+	})
+	if err != nil && fnErr == nil {
+		return wrapRepoError("failed to list polls", err)
+	}
+	return err
+}
+
+// CountPolls returns the number of polls matching the filter
+// Counting is best-effort for pagination metadata, so failures are logged and reported as 0
+func (s *PollService) CountPolls(ctx context.Context, activeOnly bool) int64 {
 	total, err := s.repo.GetTotalPollsCount(ctx, activeOnly)
 	if err != nil {
 		logger.Warn("Failed to get total count", zap.Error(err))
-		total = 0
+		return 0
 	}
+	return total
+}
 
-	return polls, total, nil
+// MaxBulkDeleteIDs caps the poll IDs a bulk delete may list
+const MaxBulkDeleteIDs = 1000
+
+// BulkDeletePolls soft deletes every poll matching the request's filter, e.g. to clean up spam
+// The request must be confirmed and set at least one filter, so a bare call cannot wipe all polls.
+// Returns the number of polls deleted.
+func (s *PollService) BulkDeletePolls(ctx context.Context, req *models.BulkDeleteRequest) (int64, error) {
+	if !req.Confirm {
+		return 0, validationErrorf("bulk delete must be confirmed with \"confirm\": true")
+	}
+	if req.IDs == nil && req.OwnerID == nil && req.CreatedBefore == nil {
+		return 0, validationErrorf("bulk delete needs at least one of ids, owner_id or created_before")
+	}
+	if req.IDs != nil && len(req.IDs) == 0 {
+		return 0, validationErrorf("ids must list at least one poll")
+	}
+	if len(req.IDs) > MaxBulkDeleteIDs {
+		return 0, validationErrorf("at most %d poll IDs can be deleted at once", MaxBulkDeleteIDs)
+	}
+
+	deletedIDs, err := s.repo.DeletePolls(ctx, req.PollFilter)
+	if err != nil {
+		logger.Error("Failed to bulk delete polls", zap.Error(err))
+		return 0, wrapRepoError("failed to delete polls", err)
+	}
+	for _, id := range deletedIDs {
+		s.invalidateResults(id)
+	}
+
+	logger.Info("Polls bulk deleted",
+		zap.Int("deleted", len(deletedIDs)),
+		zap.Int("ids", len(req.IDs)),
+		zap.Bool("by_owner", req.OwnerID != nil),
+		zap.Bool("by_created_before", req.CreatedBefore != nil),
+	)
+
+	return int64(len(deletedIDs)), nil
+}
+
+// normalizePage applies the default page size and bounds to list pagination
+func normalizePage(limit, offset int) (int, int) {
+	if limit <= 0 || limit > 100 {
+		limit = 20 // Default limit
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	return limit, offset
 }
 
-// DeletePoll soft deletes a poll
+// DeletePoll soft deletes a poll on behalf of its creator
+// Polls with more than DeleteProtectVotes votes are refused with ErrPollDeleteProtected, so
+// the results of a poll many people took part in are not erased by one person
 func (s *PollService) DeletePoll(ctx context.Context, pollID uuid.UUID) error {
+	if s.cfg.DeleteProtectVotes > 0 {
+		poll, err := s.repo.GetPollByID(ctx, pollID)
+		if err != nil {
+			return wrapRepoError("failed to get poll", err)
+		}
+		if poll == nil {
+			return ErrPollNotFound
+		}
+		if poll.TotalVotes > s.cfg.DeleteProtectVotes {
+			logger.Warn("Refused to delete a protected poll",
+				zap.String("poll_id", pollID.String()),
+				zap.Int64("total_votes", poll.TotalVotes),
+				zap.Int64("threshold", s.cfg.DeleteProtectVotes),
+			)
+			return ErrPollDeleteProtected
+		}
+	}
+	return s.deletePoll(ctx, pollID)
+}
+
+// DeletePollAsAdmin soft deletes a poll regardless of how many votes it has
+func (s *PollService) DeletePollAsAdmin(ctx context.Context, pollID uuid.UUID) error {
+	return s.deletePoll(ctx, pollID)
+}
+
+func (s *PollService) deletePoll(ctx context.Context, pollID uuid.UUID) error {
 	err := s.repo.DeletePoll(ctx, pollID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrPollNotFound
+	}
 	if err != nil {
 		logger.Error("Failed to delete poll",
 			zap.Error(err),
 			zap.String("poll_id", pollID.String()),
 		)
-		return fmt.Errorf("failed to delete poll: %w", err)
+		return wrapRepoError("failed to delete poll", err)
 	}
+	s.invalidateResults(pollID)
 
 	logger.Info("Poll deleted successfully",
 		zap.String("poll_id", pollID.String()),
@@ -241,3 +1229,233 @@ func (s *PollService) DeletePoll(ctx context.Context, pollID uuid.UUID) error {
 
 	return nil
 }
+
+// UpdateOptions replaces the texts of a poll's options, e.g. to fix a typo right after creation
+// Options are only editable while the poll has no votes; the new texts follow the creation rules
+// Returns the options with their new texts
+func (s *PollService) UpdateOptions(ctx context.Context, pollID uuid.UUID, newOptions []models.OptionUpdate) ([]models.PollOption, error) {
+	poll, err := s.repo.GetPollByID(ctx, pollID)
+	if err != nil {
+		return nil, wrapRepoError("failed to get poll", err)
+	}
+	if poll == nil {
+		return nil, ErrPollNotFound
+	}
+
+	texts := make([]string, len(newOptions))
+	for i, upd := range newOptions {
+		texts[i] = upd.Text
+	}
+	if err := validateOptionTexts(texts, poll.AcknowledgementMode); err != nil {
+		return nil, err
+	}
+	if poll.TotalVotes > 0 {
+		return nil, errOptionsLocked()
+	}
+
+	options, err := s.repo.GetPollOptions(ctx, pollID)
+	if err != nil {
+		return nil, wrapRepoError("failed to get poll options", err)
+	}
+	if len(newOptions) != len(options) {
+		return nil, newValidationError(CodeOptionSetMismatch, "options must list each of the poll's %d options", len(options))
+	}
+
+	// Match each update to an option by ID, or by position when no ID is given
+	updated := make([]bool, len(options))
+	for i, upd := range newOptions {
+		target := i
+		if upd.ID != nil {
+			target = slices.IndexFunc(options, func(opt models.PollOption) bool { return opt.ID == *upd.ID })
+		}
+		if target < 0 || updated[target] {
+			return nil, newValidationError(CodeInvalidOptionUpdate, "option %d does not match a distinct option of this poll", i+1)
+		}
+		updated[target] = true
+		options[target].OptionText = upd.Text
+	}
+
+	err = s.repo.UpdateOptionTexts(ctx, pollID, options)
+	if errors.Is(err, repository.ErrPollHasVotes) {
+		// A vote arrived after the check above
+		return nil, errOptionsLocked()
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrPollNotFound
+	}
+	if err != nil {
+		logger.Error("Failed to update poll options",
+			zap.Error(err),
+			zap.String("poll_id", pollID.String()),
+		)
+		return nil, wrapRepoError("failed to update poll options", err)
+	}
+	s.invalidateResults(pollID)
+
+	logger.Info("Poll options updated",
+		zap.String("poll_id", pollID.String()),
+	)
+
+	return options, nil
+}
+
+// errOptionsLocked reports an option edit on a poll that already has votes
+func errOptionsLocked() error {
+	return newValidationError(CodeOptionsLocked, "options cannot be edited once the poll has votes")
+}
+
+// RemoveVote deletes a voter's vote from a poll, e.g. to discard a fraudulent one
+// The option and poll totals are decremented by the vote's weight
+func (s *PollService) RemoveVote(ctx context.Context, pollID uuid.UUID, voterIdentifier string) error {
+	poll, err := s.repo.GetPollByID(ctx, pollID)
+	if err != nil {
+		return wrapRepoError("failed to get poll", err)
+	}
+	if poll == nil {
+		return ErrPollNotFound
+	}
+
+	err = s.repo.RemoveVote(ctx, pollID, voterIdentifier)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrVoteNotFound
+	}
+	if err != nil {
+		logger.Error("Failed to remove vote",
+			zap.Error(err),
+			zap.String("poll_id", pollID.String()),
+		)
+		return wrapRepoError("failed to remove vote", err)
+	}
+	s.invalidateResults(pollID)
+
+	logger.Info("Vote removed",
+		zap.String("poll_id", pollID.String()),
+		zap.String("voter", voterIdentifier),
+	)
+
+	return nil
+}
+
+// ExpirePoll sets a poll's expiry to now, e.g. to stop voting in an emergency or to exercise expiry flows
+// Unlike DeletePoll, the poll is left active, so it is treated exactly as a naturally expired poll
+// and closed by the next CloseExpiredPolls. Returns the new expiry.
+func (s *PollService) ExpirePoll(ctx context.Context, pollID uuid.UUID) (time.Time, error) {
+	expiresAt, err := s.repo.ExpireNow(ctx, pollID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return time.Time{}, ErrPollNotFound
+	}
+	if err != nil {
+		logger.Error("Failed to expire poll",
+			zap.Error(err),
+			zap.String("poll_id", pollID.String()),
+		)
+		return time.Time{}, wrapRepoError("failed to expire poll", err)
+	}
+	s.invalidateResults(pollID)
+
+	logger.Info("Poll force-expired",
+		zap.String("poll_id", pollID.String()),
+		zap.Time("expires_at", expiresAt),
+	)
+
+	return expiresAt, nil
+}
+
+// SetFeatured pins a poll to the featured listing, or removes it from the listing
+// Inactive and expired polls keep the flag but are left out of the listing. Returns the updated poll.
+func (s *PollService) SetFeatured(ctx context.Context, pollID uuid.UUID, req *models.FeatureRequest) (*models.Poll, error) {
+	if req.Rank != nil && !req.Featured {
+		return nil, validationErrorf("rank can only be set when featuring a poll")
+	}
+	if req.Rank != nil && *req.Rank < 0 {
+		return nil, validationErrorf("rank must not be negative")
+	}
+
+	err := s.repo.SetFeatured(ctx, pollID, req.Featured, req.Rank)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrPollNotFound
+	}
+	if err != nil {
+		logger.Error("Failed to set featured",
+			zap.Error(err),
+			zap.String("poll_id", pollID.String()),
+		)
+		return nil, wrapRepoError("failed to set featured", err)
+	}
+	s.invalidateResults(pollID)
+
+	poll, err := s.repo.GetPollByID(ctx, pollID)
+	if err != nil {
+		return nil, wrapRepoError("failed to get poll", err)
+	}
+	if poll == nil {
+		return nil, ErrPollNotFound
+	}
+
+	logger.Info("Poll featured status changed",
+		zap.String("poll_id", pollID.String()),
+		zap.Bool("featured", req.Featured),
+	)
+	return poll, nil
+}
+
+// CloseExpiredPolls deactivates every active poll that has passed its expiry
+// The repository writes a poll.closed event to the outbox for each. Returns the number of polls closed
+func (s *PollService) CloseExpiredPolls(ctx context.Context) (int64, error) {
+	closedIDs, err := s.repo.DeactivateExpired(ctx)
+	if err != nil {
+		logger.Error("Failed to close expired polls", zap.Error(err))
+		return 0, wrapRepoError("failed to close expired polls", err)
+	}
+
+	logger.Info("Expired polls closed",
+		zap.Int("closed", len(closedIDs)),
+	)
+
+	for _, id := range closedIDs {
+		s.invalidateResults(id)
+	}
+
+	return int64(len(closedIDs)), nil
+}
+
+// ArchiveBatchSize is the number of polls moved to the archive per transaction
+const ArchiveBatchSize = 100
+
+// ArchiveClosedPolls moves polls closed for longer than ArchiveRetention, with their options and votes,
+// to the archive tables, where GetPollResults still finds them. Returns the number of polls moved.
+func (s *PollService) ArchiveClosedPolls(ctx context.Context) (int64, error) {
+	if s.cfg.ArchiveRetention <= 0 {
+		return 0, nil
+	}
+
+	closedBefore := s.clock.Now().Add(-s.cfg.ArchiveRetention)
+	var archived int64
+	for {
+		ids, err := s.repo.ArchiveClosedPolls(ctx, closedBefore, ArchiveBatchSize)
+		if err != nil {
+			logger.Error("Failed to archive closed polls",
+				zap.Error(err),
+				zap.Int64("archived", archived),
+			)
+			return archived, wrapRepoError("failed to archive closed polls", err)
+		}
+		for _, id := range ids {
+			s.invalidateResults(id)
+		}
+		archived += int64(len(ids))
+
+		if len(ids) < ArchiveBatchSize {
+			break
+		}
+	}
+
+	if archived > 0 {
+		logger.Info("Archived closed polls",
+			zap.Int64("count", archived),
+			zap.Time("closed_before", closedBefore),
+		)
+	}
+
+	return archived, nil
+}
@@ -0,0 +1,649 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/moabdelazem/k8s-app/internal/models"
+	"github.com/moabdelazem/k8s-app/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// These tests run the service flows covered with mocks elsewhere against the in-memory
+// repository, confirming it behaves like the Postgres one where the service relies on it
+
+func newMemoryTestService(cfg PollServiceConfig) *PollService {
+	return NewPollService(repository.NewInMemoryPollRepository(), cfg)
+}
+
+func createMemoryPoll(t *testing.T, svc *PollService, req *models.CreatePollRequest) *models.PollWithOptions {
+	t.Helper()
+	poll, _, err := svc.CreatePoll(context.Background(), req, "owner-1")
+	require.NoError(t, err)
+	return poll
+}
+
+func requireValidationCode(t *testing.T, err error, code string) {
+	t.Helper()
+	var validationErr *ValidationError
+	require.ErrorAs(t, err, &validationErr)
+	assert.Equal(t, code, validationErr.Code)
+}
+
+func TestInMemoryRepository_VoteAndResults(t *testing.T) {
+	ctx := context.Background()
+	svc := newMemoryTestService(PollServiceConfig{})
+	poll := createMemoryPoll(t, svc, validCreateRequest())
+
+	_, _, err := svc.CastVote(ctx, poll.ID, poll.Options[1].ID, "voter-1", 0, "")
+	require.NoError(t, err)
+	_, _, err = svc.CastVote(ctx, poll.ID, poll.Options[1].ID, "voter-2", 0, "")
+	require.NoError(t, err)
+
+	_, _, err = svc.CastVote(ctx, poll.ID, poll.Options[0].ID, "voter-1", 0, "")
+	requireValidationCode(t, err, CodeAlreadyVoted)
+
+	results, err := svc.GetPollResults(ctx, poll.ID, "voter-1")
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), results.TotalVotes)
+	assert.True(t, results.HasVoted)
+	assert.Equal(t, &poll.Options[1].ID, results.VotedOption)
+	assert.Equal(t, []uuid.UUID{poll.Options[1].ID}, results.Leading)
+	assert.Equal(t, int64(0), results.Options[0].VoteCount)
+	assert.Equal(t, int64(2), results.Options[1].VoteCount)
+
+	// Removing a vote frees the voter to vote again
+	require.NoError(t, svc.RemoveVote(ctx, poll.ID, "voter-1"))
+	require.ErrorIs(t, svc.RemoveVote(ctx, poll.ID, "voter-1"), ErrVoteNotFound)
+	_, _, err = svc.CastVote(ctx, poll.ID, poll.Options[0].ID, "voter-1", 0, "")
+	require.NoError(t, err)
+
+	results, err = svc.GetPollResults(ctx, poll.ID, "")
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), results.TotalVotes)
+	assert.False(t, results.HasVoted)
+}
+
+func TestInMemoryRepository_InvalidOption(t *testing.T) {
+	ctx := context.Background()
+	svc := newMemoryTestService(PollServiceConfig{})
+	poll := createMemoryPoll(t, svc, validCreateRequest())
+	other := createMemoryPoll(t, svc, validCreateRequest())
+
+	_, _, err := svc.CastVote(ctx, poll.ID, other.Options[0].ID, "voter-1", 0, "")
+	requireValidationCode(t, err, CodeInvalidOption)
+}
+
+func TestInMemoryRepository_SoftDelete(t *testing.T) {
+	ctx := context.Background()
+	svc := newMemoryTestService(PollServiceConfig{})
+	poll := createMemoryPoll(t, svc, validCreateRequest())
+
+	require.NoError(t, svc.DeletePoll(ctx, poll.ID))
+	require.ErrorIs(t, svc.DeletePoll(ctx, uuid.New()), ErrPollNotFound)
+
+	// Reads report the poll as not found unless deleted polls are reported as gone
+	_, err := svc.GetPollResults(ctx, poll.ID, "")
+	require.ErrorIs(t, err, ErrPollNotFound)
+
+	_, _, err = svc.CastVote(ctx, poll.ID, poll.Options[0].ID, "voter-1", 0, "")
+	requireValidationCode(t, err, CodePollInactive)
+
+	active, err := svc.ListPolls(ctx, 10, 0, true)
+	require.NoError(t, err)
+	assert.Empty(t, active.Polls)
+	assert.Equal(t, int64(0), active.Total)
+
+	all, err := svc.ListPolls(ctx, 10, 0, false)
+	require.NoError(t, err)
+	assert.Len(t, all.Polls, 1)
+}
+
+func TestInMemoryRepository_DeletedPollsGone(t *testing.T) {
+	ctx := context.Background()
+	svc := newMemoryTestService(PollServiceConfig{DeletedPollsGone: true})
+	poll := createMemoryPoll(t, svc, validCreateRequest())
+	require.NoError(t, svc.DeletePoll(ctx, poll.ID))
+
+	_, err := svc.GetPollResults(ctx, poll.ID, "")
+	require.ErrorIs(t, err, ErrPollDeleted)
+
+	_, err = svc.GetPollResults(ctx, uuid.New(), "")
+	require.ErrorIs(t, err, ErrPollNotFound)
+}
+
+func TestDeletePoll_DeleteProtectVotes(t *testing.T) {
+	ctx := context.Background()
+	svc := newMemoryTestService(PollServiceConfig{DeleteProtectVotes: 2})
+
+	// castVotes has count different voters vote on poll
+	castVotes := func(poll *models.PollWithOptions, count int) {
+		for i := 0; i < count; i++ {
+			_, _, err := svc.CastVote(ctx, poll.ID, poll.Options[0].ID, fmt.Sprintf("voter-%d", i), 0, "")
+			require.NoError(t, err)
+		}
+	}
+
+	atThreshold := createMemoryPoll(t, svc, validCreateRequest())
+	castVotes(atThreshold, 2)
+	require.NoError(t, svc.DeletePoll(ctx, atThreshold.ID), "polls up to the threshold can be deleted")
+
+	protected := createMemoryPoll(t, svc, validCreateRequest())
+	castVotes(protected, 3)
+	require.ErrorIs(t, svc.DeletePoll(ctx, protected.ID), ErrPollDeleteProtected)
+	_, err := svc.GetPollResults(ctx, protected.ID, "")
+	require.NoError(t, err, "the refused poll is left in place")
+
+	require.NoError(t, svc.DeletePollAsAdmin(ctx, protected.ID), "admins can delete protected polls")
+	require.ErrorIs(t, svc.DeletePoll(ctx, uuid.New()), ErrPollNotFound)
+}
+
+func TestInMemoryRepository_ListPagination(t *testing.T) {
+	ctx := context.Background()
+	svc := newMemoryTestService(PollServiceConfig{})
+
+	var created []uuid.UUID
+	for range 5 {
+		created = append(created, createMemoryPoll(t, svc, validCreateRequest()).ID)
+	}
+
+	var listed []uuid.UUID
+	for offset := 0; offset < 5; offset += 2 {
+		page, err := svc.ListPolls(ctx, 2, offset, false)
+		require.NoError(t, err)
+		assert.Equal(t, int64(5), page.Total)
+		for _, poll := range page.Polls {
+			listed = append(listed, poll.ID)
+		}
+	}
+
+	// Every poll is listed exactly once across the pages
+	assert.ElementsMatch(t, created, listed)
+}
+
+func TestInMemoryRepository_OptionsLockedAfterVote(t *testing.T) {
+	ctx := context.Background()
+	svc := newMemoryTestService(PollServiceConfig{})
+	poll := createMemoryPoll(t, svc, validCreateRequest())
+
+	updates := []models.OptionUpdate{
+		{ID: &poll.Options[0].ID, Text: "Feature A+"},
+		{ID: &poll.Options[1].ID, Text: "Feature B"},
+	}
+	options, err := svc.UpdateOptions(ctx, poll.ID, updates)
+	require.NoError(t, err)
+	assert.Equal(t, "Feature A+", options[0].OptionText)
+
+	_, _, err = svc.CastVote(ctx, poll.ID, poll.Options[0].ID, "voter-1", 0, "")
+	require.NoError(t, err)
+
+	_, err = svc.UpdateOptions(ctx, poll.ID, updates)
+	requireValidationCode(t, err, CodeOptionsLocked)
+}
+
+func TestInMemoryRepository_GroupDedup(t *testing.T) {
+	ctx := context.Background()
+	svc := newMemoryTestService(PollServiceConfig{GroupVoterDedup: true})
+
+	req := validCreateRequest()
+	req.Group = ptr("Weekly")
+	first := createMemoryPoll(t, svc, req)
+	req = validCreateRequest()
+	req.Group = ptr("weekly")
+	second := createMemoryPoll(t, svc, req)
+
+	_, _, err := svc.CastVote(ctx, first.ID, first.Options[0].ID, "voter-1", 0, "")
+	require.NoError(t, err)
+
+	_, _, err = svc.CastVote(ctx, second.ID, second.Options[0].ID, "voter-1", 0, "")
+	requireValidationCode(t, err, CodeAlreadyVotedInGroup)
+}
+
+func TestInMemoryRepository_ActivePollLimit(t *testing.T) {
+	ctx := context.Background()
+	svc := newMemoryTestService(PollServiceConfig{MaxActivePollsPerOwner: 1})
+	poll := createMemoryPoll(t, svc, validCreateRequest())
+
+	_, _, err := svc.CreatePoll(ctx, validCreateRequest(), "owner-1")
+	require.ErrorIs(t, err, ErrActivePollLimitReached)
+
+	// Closing the first poll frees the owner's slot
+	require.NoError(t, svc.DeletePoll(ctx, poll.ID))
+	createMemoryPoll(t, svc, validCreateRequest())
+}
+
+func TestInMemoryRepository_ArchivedPollStaysReadable(t *testing.T) {
+	ctx := context.Background()
+	// The service's clock is a day ahead of the repository's, past the retention
+	svc := newMemoryTestService(PollServiceConfig{
+		ArchiveRetention: time.Hour,
+		Clock:            fixedClock{time.Now().Add(24 * time.Hour)},
+	})
+	poll := createMemoryPoll(t, svc, validCreateRequest())
+
+	_, _, err := svc.CastVote(ctx, poll.ID, poll.Options[0].ID, "voter-1", 0, "")
+	require.NoError(t, err)
+	_, err = svc.ExpirePoll(ctx, poll.ID)
+	require.NoError(t, err)
+	_, err = svc.CloseExpiredPolls(ctx)
+	require.NoError(t, err)
+
+	archived, err := svc.ArchiveClosedPolls(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), archived)
+
+	list, err := svc.ListPolls(ctx, 10, 0, false)
+	require.NoError(t, err)
+	assert.Empty(t, list.Polls)
+
+	results, err := svc.GetPollResults(ctx, poll.ID, "voter-1")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), results.TotalVotes)
+	assert.Equal(t, &poll.Options[0].ID, results.VotedOption)
+}
+
+func TestInMemoryRepository_Allowlist(t *testing.T) {
+	ctx := context.Background()
+	svc := newMemoryTestService(PollServiceConfig{})
+	req := validCreateRequest()
+	req.AllowlistOnly = true
+	poll := createMemoryPoll(t, svc, req)
+
+	added, err := svc.AddAllowedVoters(ctx, poll.ID, []string{"voter-1", "voter-1"})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), added)
+
+	_, _, err = svc.CastVote(ctx, poll.ID, poll.Options[0].ID, "voter-2", 0, "")
+	require.ErrorIs(t, err, ErrVoterNotAllowed)
+	_, _, err = svc.CastVote(ctx, poll.ID, poll.Options[0].ID, "voter-1", 0, "")
+	require.NoError(t, err)
+
+	voters, err := svc.ListAllowedVoters(ctx, poll.ID)
+	require.NoError(t, err)
+	require.Len(t, voters, 1)
+	assert.Equal(t, "voter-1", voters[0].VoterIdentifier)
+
+	require.NoError(t, svc.RemoveAllowedVoter(ctx, poll.ID, "voter-1"))
+	require.ErrorIs(t, svc.RemoveAllowedVoter(ctx, poll.ID, "voter-1"), ErrAllowedVoterNotFound)
+}
+
+func TestInMemoryRepository_MaxVotesUnderConcurrency(t *testing.T) {
+	ctx := context.Background()
+	svc := newMemoryTestService(PollServiceConfig{})
+	req := validCreateRequest()
+	maxVotes := int64(3)
+	req.MaxVotes = &maxVotes
+	poll := createMemoryPoll(t, svc, req)
+
+	const voters = 20
+	var cast, full atomic.Int64
+	var wg sync.WaitGroup
+	for i := range voters {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, _, err := svc.CastVote(ctx, poll.ID, poll.Options[0].ID, fmt.Sprintf("voter-%d", i), 0, "")
+			var validationErr *ValidationError
+			switch {
+			case err == nil:
+				cast.Add(1)
+			case errors.As(err, &validationErr) && validationErr.Code == CodePollFull:
+				full.Add(1)
+			default:
+				t.Errorf("unexpected vote error: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, maxVotes, cast.Load())
+	assert.Equal(t, voters-maxVotes, full.Load())
+	results, err := svc.GetPollResults(ctx, poll.ID, "")
+	require.NoError(t, err)
+	assert.Equal(t, maxVotes, results.TotalVotes)
+	assert.Equal(t, &maxVotes, results.MaxVotes)
+}
+
+func TestInMemoryRepository_MaxVotesCountsWeight(t *testing.T) {
+	ctx := context.Background()
+	svc := newMemoryTestService(PollServiceConfig{MinVoteWeight: 1, MaxVoteWeight: 10})
+	req := validCreateRequest()
+	req.AllowWeighted = true
+	maxVotes := int64(5)
+	req.MaxVotes = &maxVotes
+	poll := createMemoryPoll(t, svc, req)
+
+	_, _, err := svc.CastVote(ctx, poll.ID, poll.Options[0].ID, "voter-1", 4, "")
+	require.NoError(t, err)
+	_, _, err = svc.CastVote(ctx, poll.ID, poll.Options[1].ID, "voter-2", 2, "")
+	requireValidationCode(t, err, CodePollFull)
+	_, _, err = svc.CastVote(ctx, poll.ID, poll.Options[1].ID, "voter-2", 1, "")
+	require.NoError(t, err)
+}
+
+func TestCreatePoll_MaxVotesMustBePositive(t *testing.T) {
+	svc := newMemoryTestService(PollServiceConfig{})
+	req := validCreateRequest()
+	zero := int64(0)
+	req.MaxVotes = &zero
+
+	_, _, err := svc.CreatePoll(context.Background(), req, "owner-1")
+	requireValidationCode(t, err, CodeMaxVotesInvalid)
+}
+
+func TestGetRelatedPolls(t *testing.T) {
+	ctx := context.Background()
+	svc := newMemoryTestService(PollServiceConfig{})
+	current := createMemoryPoll(t, svc, validCreateRequest())
+	other := createMemoryPoll(t, svc, validCreateRequest())
+
+	related, err := svc.GetRelatedPolls(ctx, current.ID)
+	require.NoError(t, err)
+	require.Len(t, related, 1)
+	assert.Equal(t, other.ID, related[0].ID)
+
+	// Polls created without an owner have no creator to relate them by
+	anonymous, _, err := svc.CreatePoll(ctx, validCreateRequest(), "")
+	require.NoError(t, err)
+	related, err = svc.GetRelatedPolls(ctx, anonymous.ID)
+	require.NoError(t, err)
+	assert.NotNil(t, related)
+	assert.Empty(t, related)
+
+	_, err = svc.GetRelatedPolls(ctx, uuid.New())
+	assert.ErrorIs(t, err, ErrPollNotFound)
+}
+
+func TestGetSuspiciousVotes(t *testing.T) {
+	ctx := context.Background()
+	svc := newMemoryTestService(PollServiceConfig{SuspiciousSubnetMinVotes: 2, SuspiciousBurstMinVotes: 100})
+	poll := createMemoryPoll(t, svc, validCreateRequest())
+
+	for _, voter := range []string{"203.0.113.7", "203.0.113.9", "198.51.100.4"} {
+		_, _, err := svc.CastVote(ctx, poll.ID, poll.Options[0].ID, voter, 0, "")
+		require.NoError(t, err)
+	}
+
+	report, err := svc.GetSuspiciousVotes(ctx, poll.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.SuspicionThresholds{SubnetMinVotes: 2, BurstWindow: "1m0s", BurstMinVotes: 100}, report.Thresholds)
+	require.Len(t, report.SubnetClusters, 1)
+	assert.Equal(t, "203.0.113.0/24", report.SubnetClusters[0].Subnet)
+	assert.NotNil(t, report.Bursts)
+	assert.Empty(t, report.Bursts)
+
+	_, err = svc.GetSuspiciousVotes(ctx, uuid.New())
+	assert.ErrorIs(t, err, ErrPollNotFound)
+}
+
+func TestListPollResults(t *testing.T) {
+	ctx := context.Background()
+	svc := newMemoryTestService(PollServiceConfig{})
+	older := createMemoryPoll(t, svc, validCreateRequest())
+	newer := createMemoryPoll(t, svc, validCreateRequest())
+
+	for _, voter := range []string{"voter-1", "voter-2", "voter-3"} {
+		_, _, err := svc.CastVote(ctx, newer.ID, newer.Options[1].ID, voter, 0, "")
+		require.NoError(t, err)
+	}
+	_, _, err := svc.CastVote(ctx, newer.ID, newer.Options[0].ID, "voter-4", 0, "")
+	require.NoError(t, err)
+
+	list, err := svc.ListPollResults(ctx, 10, 0, false)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), list.Total)
+	require.Len(t, list.Results, 2)
+
+	results := list.Results[0]
+	assert.Equal(t, newer.ID, results.ID)
+	assert.Equal(t, int64(4), results.TotalVotes)
+	require.Len(t, results.Options, 2)
+	assert.Equal(t, 25.0, results.Options[0].Percentage)
+	assert.Equal(t, 75.0, results.Options[1].Percentage)
+	assert.Equal(t, []uuid.UUID{newer.Options[1].ID}, results.Leading)
+
+	assert.Equal(t, older.ID, list.Results[1].ID)
+	assert.Len(t, list.Results[1].Options, 2)
+	assert.Empty(t, list.Results[1].Leading)
+}
+
+func TestCastVote_VotingWindow(t *testing.T) {
+	tests := []struct {
+		name     string
+		start    string
+		end      string
+		timezone string
+		now      time.Time
+		wantOpen bool
+	}{
+		// New York is UTC-4 in June, so its office hours run 13:00-21:00 UTC
+		{"before the window in the poll's zone though inside it in UTC", "09:00", "17:00", "America/New_York", time.Date(2025, time.June, 1, 12, 30, 0, 0, time.UTC), false},
+		{"inside the window in the poll's zone though past it in UTC", "09:00", "17:00", "America/New_York", time.Date(2025, time.June, 1, 20, 30, 0, 0, time.UTC), true},
+		{"the end is exclusive", "09:00", "17:00", "America/New_York", time.Date(2025, time.June, 1, 21, 0, 0, 0, time.UTC), false},
+		{"no timezone means UTC", "09:00", "17:00", "", time.Date(2025, time.June, 1, 12, 30, 0, 0, time.UTC), true},
+		// 16:30 UTC is 01:30 the next day in Tokyo
+		{"overnight window after midnight", "22:00", "02:00", "Asia/Tokyo", time.Date(2025, time.June, 1, 16, 30, 0, 0, time.UTC), true},
+		{"overnight window during the day", "22:00", "02:00", "Asia/Tokyo", time.Date(2025, time.June, 1, 3, 0, 0, 0, time.UTC), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			svc := newMemoryTestService(PollServiceConfig{Clock: fixedClock{now: tt.now}})
+			req := validCreateRequest()
+			req.VotingWindowStart = &tt.start
+			req.VotingWindowEnd = &tt.end
+			if tt.timezone != "" {
+				req.Timezone = &tt.timezone
+			}
+			poll := createMemoryPoll(t, svc, req)
+
+			_, _, err := svc.CastVote(ctx, poll.ID, poll.Options[0].ID, "voter-1", 0, "")
+			if tt.wantOpen {
+				require.NoError(t, err)
+				return
+			}
+			requireValidationCode(t, err, CodeOutsideVotingWindow)
+		})
+	}
+}
+
+func TestCreatePoll_VotingWindowValidation(t *testing.T) {
+	ptr := func(s string) *string { return &s }
+	tests := []struct {
+		name     string
+		start    *string
+		end      *string
+		timezone *string
+		wantCode string
+	}{
+		{"start without end", ptr("09:00"), nil, nil, CodeVotingWindowInvalid},
+		{"not a time of day", ptr("9am"), ptr("17:00"), nil, CodeVotingWindowInvalid},
+		{"hour out of range", ptr("09:00"), ptr("24:00"), nil, CodeVotingWindowInvalid},
+		{"empty window", ptr("09:00"), ptr("09:00"), nil, CodeVotingWindowInvalid},
+		{"timezone without window", nil, nil, ptr("Europe/Berlin"), CodeVotingWindowInvalid},
+		{"unknown timezone", ptr("09:00"), ptr("17:00"), ptr("Mars/Olympus_Mons"), CodeTimezoneInvalid},
+		{"local timezone", ptr("09:00"), ptr("17:00"), ptr("Local"), CodeTimezoneInvalid},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := newMemoryTestService(PollServiceConfig{})
+			req := validCreateRequest()
+			req.VotingWindowStart, req.VotingWindowEnd, req.Timezone = tt.start, tt.end, tt.timezone
+
+			_, _, err := svc.CreatePoll(context.Background(), req, "owner-1")
+			requireValidationCode(t, err, tt.wantCode)
+		})
+	}
+}
+
+func TestAcknowledgementPoll_CreateAndVote(t *testing.T) {
+	ctx := context.Background()
+	svc := newMemoryTestService(PollServiceConfig{})
+	poll := createMemoryPoll(t, svc, &models.CreatePollRequest{
+		Question:            "Have you read the updated security policy?",
+		Options:             []string{"I acknowledge"},
+		AcknowledgementMode: true,
+	})
+	require.Len(t, poll.Options, 1)
+	assert.True(t, poll.AcknowledgementMode)
+
+	for _, voter := range []string{"voter-1", "voter-2"} {
+		_, _, err := svc.CastVote(ctx, poll.ID, poll.Options[0].ID, voter, 0, "")
+		require.NoError(t, err)
+	}
+	_, _, err := svc.CastVote(ctx, poll.ID, poll.Options[0].ID, "voter-1", 0, "")
+	requireValidationCode(t, err, CodeAlreadyVoted)
+
+	results, err := svc.GetPollResults(ctx, poll.ID, "voter-1")
+	require.NoError(t, err)
+	require.NotNil(t, results.Acknowledgements)
+	assert.Equal(t, int64(2), *results.Acknowledgements)
+	assert.True(t, results.HasVoted)
+	assert.Equal(t, 100.0, results.Options[0].Percentage)
+
+	list, err := svc.ListPollResults(ctx, 10, 0, false)
+	require.NoError(t, err)
+	require.Len(t, list.Results, 1)
+	assert.Equal(t, results.Acknowledgements, list.Results[0].Acknowledgements)
+}
+
+func TestCreatePoll_AcknowledgementValidation(t *testing.T) {
+	tests := []struct {
+		name     string
+		modify   func(req *models.CreatePollRequest)
+		wantCode string
+	}{
+		{"normal polls still need two options", func(req *models.CreatePollRequest) {
+			req.Options = []string{"Only one"}
+		}, CodeTooFewOptions},
+		{"acknowledgement polls take exactly one option", func(req *models.CreatePollRequest) {
+			req.AcknowledgementMode = true
+		}, CodeAcknowledgementOptionCount},
+		{"acknowledgements are not weighted", func(req *models.CreatePollRequest) {
+			req.AcknowledgementMode = true
+			req.Options = []string{"I acknowledge"}
+			req.AllowWeighted = true
+		}, CodeAcknowledgementConflict},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := newMemoryTestService(PollServiceConfig{})
+			req := validCreateRequest()
+			tt.modify(req)
+
+			_, _, err := svc.CreatePoll(context.Background(), req, "owner-1")
+			requireValidationCode(t, err, tt.wantCode)
+		})
+	}
+}
+
+func TestPollMetadata_RoundTrip(t *testing.T) {
+	ctx := context.Background()
+	svc := newMemoryTestService(PollServiceConfig{})
+	req := validCreateRequest()
+	req.Metadata = json.RawMessage(`{ "campaign": "spring-launch", "ui": {"color": "teal"} }`)
+	created := createMemoryPoll(t, svc, req)
+	assert.JSONEq(t, `{"campaign":"spring-launch","ui":{"color":"teal"}}`, string(created.Metadata))
+
+	results, err := svc.GetPollResults(ctx, created.ID, "")
+	require.NoError(t, err)
+	assert.Equal(t, `{"campaign":"spring-launch","ui":{"color":"teal"}}`, string(results.Metadata), "metadata is stored compacted")
+
+	matches, err := svc.ListPollsByMetadata(ctx, "campaign", "spring-launch", 0, 0)
+	require.NoError(t, err)
+	require.Len(t, matches, 1)
+	assert.Equal(t, created.ID, matches[0].ID)
+
+	updated, err := svc.SetMetadata(ctx, created.ID, json.RawMessage(`{"campaign":"summer"}`))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"campaign":"summer"}`, string(updated.Metadata))
+	matches, err = svc.ListPollsByMetadata(ctx, "campaign", "spring-launch", 0, 0)
+	require.NoError(t, err)
+	assert.Empty(t, matches)
+
+	cleared, err := svc.SetMetadata(ctx, created.ID, json.RawMessage(`null`))
+	require.NoError(t, err)
+	assert.Nil(t, cleared.Metadata)
+	body, err := json.Marshal(cleared)
+	require.NoError(t, err)
+	assert.NotContains(t, string(body), `"metadata"`)
+
+	_, err = svc.SetMetadata(ctx, uuid.New(), json.RawMessage(`{}`))
+	assert.ErrorIs(t, err, ErrPollNotFound)
+}
+
+func TestPollMetadata_Validation(t *testing.T) {
+	tooLarge := `{"blob":"` + strings.Repeat("x", MaxMetadataBytes) + `"}`
+	// Whitespace is not counted against the limit
+	padded := `{"blob":"` + strings.Repeat("x", MaxMetadataBytes-len(`{"blob":""}`)) + `"` + strings.Repeat(" ", 100) + `}`
+
+	tests := []struct {
+		name     string
+		metadata string
+		wantCode string
+	}{
+		{"too large", tooLarge, CodeMetadataTooLarge},
+		{"array", `["a","b"]`, CodeMetadataInvalid},
+		{"string", `"campaign"`, CodeMetadataInvalid},
+		{"malformed", `{"campaign":`, CodeMetadataInvalid},
+		{"at the limit once compacted", padded, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			svc := newMemoryTestService(PollServiceConfig{})
+			req := validCreateRequest()
+			req.Metadata = json.RawMessage(tt.metadata)
+
+			poll, _, err := svc.CreatePoll(ctx, req, "owner-1")
+			if tt.wantCode == "" {
+				require.NoError(t, err)
+				assert.Len(t, poll.Metadata, MaxMetadataBytes)
+				return
+			}
+			requireValidationCode(t, err, tt.wantCode)
+
+			existing := createMemoryPoll(t, svc, validCreateRequest())
+			_, err = svc.SetMetadata(ctx, existing.ID, json.RawMessage(tt.metadata))
+			requireValidationCode(t, err, tt.wantCode)
+		})
+	}
+}
+
+func TestBulkDeletePolls(t *testing.T) {
+	ctx := context.Background()
+	svc := newMemoryTestService(PollServiceConfig{})
+	spam := createMemoryPoll(t, svc, validCreateRequest())
+	kept, _, err := svc.CreatePoll(ctx, validCreateRequest(), "owner-2")
+	require.NoError(t, err)
+	owner := "owner-1"
+
+	_, err = svc.BulkDeletePolls(ctx, &models.BulkDeleteRequest{PollFilter: models.PollFilter{OwnerID: &owner}})
+	var validationErr *ValidationError
+	require.ErrorAs(t, err, &validationErr, "the request must be confirmed")
+	_, err = svc.BulkDeletePolls(ctx, &models.BulkDeleteRequest{Confirm: true})
+	require.ErrorAs(t, err, &validationErr, "a filter is required")
+
+	deleted, err := svc.BulkDeletePolls(ctx, &models.BulkDeleteRequest{PollFilter: models.PollFilter{OwnerID: &owner}, Confirm: true})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), deleted)
+
+	_, err = svc.GetPollResults(ctx, spam.ID, "")
+	assert.ErrorIs(t, err, ErrPollNotFound)
+	results, err := svc.GetPollResults(ctx, kept.ID, "")
+	require.NoError(t, err)
+	assert.True(t, results.IsActive)
+}
@@ -0,0 +1,1361 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/moabdelazem/k8s-app/internal/mocks"
+	"github.com/moabdelazem/k8s-app/internal/models"
+	"github.com/moabdelazem/k8s-app/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// fixedClock is a Clock frozen at a single instant
+type fixedClock struct {
+	now time.Time
+}
+
+func (c fixedClock) Now() time.Time {
+	return c.now
+}
+
+var testNow = time.Date(2025, time.June, 1, 12, 0, 0, 0, time.UTC)
+
+// validCreateRequest returns a request that passes all creation rules
+func validCreateRequest() *models.CreatePollRequest {
+	return &models.CreatePollRequest{
+		Question: "What should we build next?",
+		Options:  []string{"Feature A", "Feature B"},
+	}
+}
+
+func TestCreatePoll_ActivePollLimit(t *testing.T) {
+	tests := []struct {
+		name        string
+		limit       int
+		activeCount int64
+		wantErr     bool
+	}{
+		{name: "below limit", limit: 3, activeCount: 2, wantErr: false},
+		{name: "at limit", limit: 3, activeCount: 3, wantErr: true},
+		{name: "above limit", limit: 3, activeCount: 4, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(mocks.MockPollRepository)
+			repo.On("CountActivePollsByOwner", mock.Anything, "owner-1").Return(tt.activeCount, nil)
+			if !tt.wantErr {
+				repo.On("CreatePoll", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+			}
+
+			svc := NewPollService(repo, PollServiceConfig{MaxActivePollsPerOwner: tt.limit})
+			poll, _, err := svc.CreatePoll(context.Background(), validCreateRequest(), "owner-1")
+
+			if tt.wantErr {
+				require.ErrorIs(t, err, ErrActivePollLimitReached)
+				assert.Nil(t, poll)
+				repo.AssertNotCalled(t, "CreatePoll", mock.Anything, mock.Anything, mock.Anything)
+			} else {
+				require.NoError(t, err)
+				require.NotNil(t, poll.OwnerID)
+				assert.Equal(t, "owner-1", *poll.OwnerID)
+			}
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestCreatePoll_ActivePollLimitDisabled(t *testing.T) {
+	repo := new(mocks.MockPollRepository)
+	repo.On("CreatePoll", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	svc := NewPollService(repo, PollServiceConfig{MaxActivePollsPerOwner: 0})
+	_, _, err := svc.CreatePoll(context.Background(), validCreateRequest(), "owner-1")
+
+	require.NoError(t, err)
+	repo.AssertNotCalled(t, "CountActivePollsByOwner", mock.Anything, mock.Anything)
+}
+
+func TestCreatePoll_OptionCountConstraintViolation(t *testing.T) {
+	repo := new(mocks.MockPollRepository)
+	dbErr := fmt.Errorf("%w: %w", repository.ErrOptionCountOutOfBounds, &pq.Error{Code: "23514", Constraint: "poll_options_count"})
+	repo.On("CreatePoll", mock.Anything, mock.Anything, mock.Anything).Return(dbErr)
+
+	svc := NewPollService(repo, PollServiceConfig{})
+	poll, _, err := svc.CreatePoll(context.Background(), validCreateRequest(), "owner-1")
+
+	var validationErr *ValidationError
+	require.ErrorAs(t, err, &validationErr)
+	assert.Equal(t, "poll must have between 2 and 10 options", validationErr.Message)
+	assert.Nil(t, poll)
+}
+
+func TestCreatePoll_ExpiryBoundary(t *testing.T) {
+	tests := []struct {
+		name      string
+		expiresAt time.Time
+		wantErr   bool
+	}{
+		{name: "in the past", expiresAt: testNow.Add(-time.Second), wantErr: true},
+		{name: "exactly now", expiresAt: testNow, wantErr: true},
+		{name: "in the future", expiresAt: testNow.Add(time.Second), wantErr: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(mocks.MockPollRepository)
+			if !tt.wantErr {
+				repo.On("CreatePoll", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+			}
+
+			req := validCreateRequest()
+			req.ExpiresAt = &tt.expiresAt
+
+			svc := NewPollService(repo, PollServiceConfig{Clock: fixedClock{now: testNow}})
+			_, _, err := svc.CreatePoll(context.Background(), req, "")
+
+			if tt.wantErr {
+				assert.EqualError(t, err, "expiration date must be in the future")
+			} else {
+				assert.NoError(t, err)
+			}
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestCreatePoll_MaxExpiryHorizon(t *testing.T) {
+	const horizon = 365 * 24 * time.Hour
+
+	tests := []struct {
+		name      string
+		horizon   time.Duration
+		expiresAt time.Time
+		wantErr   bool
+	}{
+		{name: "just within", horizon: horizon, expiresAt: testNow.Add(horizon), wantErr: false},
+		{name: "just beyond", horizon: horizon, expiresAt: testNow.Add(horizon + time.Second), wantErr: true},
+		{name: "unlimited", horizon: 0, expiresAt: testNow.Add(10 * horizon), wantErr: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(mocks.MockPollRepository)
+			if !tt.wantErr {
+				repo.On("CreatePoll", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+			}
+
+			req := validCreateRequest()
+			req.ExpiresAt = &tt.expiresAt
+
+			svc := NewPollService(repo, PollServiceConfig{Clock: fixedClock{now: testNow}, MaxExpiryHorizon: tt.horizon})
+			_, _, err := svc.CreatePoll(context.Background(), req, "")
+
+			if tt.wantErr {
+				requireValidationCode(t, err, CodeExpiryBeyondHorizon)
+				assert.EqualError(t, err, "expiration date must be no later than "+testNow.Add(horizon).UTC().Format(time.RFC3339))
+			} else {
+				assert.NoError(t, err)
+			}
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestCastVote_ExpiryBoundary(t *testing.T) {
+	tests := []struct {
+		name      string
+		expiresAt time.Time
+		wantErr   bool
+	}{
+		{name: "expired a second ago", expiresAt: testNow.Add(-time.Second), wantErr: true},
+		{name: "expires exactly now", expiresAt: testNow, wantErr: true},
+		{name: "expires in a second", expiresAt: testNow.Add(time.Second), wantErr: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pollID := uuid.New()
+			optionID := uuid.New()
+
+			repo := new(mocks.MockPollRepository)
+			repo.On("GetPollByID", mock.Anything, pollID).Return(&models.Poll{
+				ID:        pollID,
+				IsActive:  true,
+				ExpiresAt: &tt.expiresAt,
+			}, nil)
+			if !tt.wantErr {
+				repo.On("HasVoted", mock.Anything, pollID, "voter-1").Return(false, nil, nil)
+				repo.On("GetPollOptions", mock.Anything, pollID).Return([]models.PollOption{{ID: optionID, PollID: pollID}}, nil)
+				repo.On("CastVote", mock.Anything, mock.Anything).Return(nil)
+			}
+
+			svc := NewPollService(repo, PollServiceConfig{Clock: fixedClock{now: testNow}})
+			_, _, err := svc.CastVote(context.Background(), pollID, optionID, "voter-1", 0, "")
+
+			if tt.wantErr {
+				assert.EqualError(t, err, "poll has expired")
+			} else {
+				assert.NoError(t, err)
+			}
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestCastVote_Weighted(t *testing.T) {
+	tests := []struct {
+		name          string
+		allowWeighted bool
+		weight        int64
+		wantWeight    int64
+		wantErr       string
+	}{
+		{name: "default weight on unweighted poll", allowWeighted: false, weight: 0, wantWeight: 1},
+		{name: "explicit weight on unweighted poll", allowWeighted: false, weight: 3, wantErr: "weighted voting is not enabled for this poll"},
+		{name: "weight within bounds", allowWeighted: true, weight: 5, wantWeight: 5},
+		{name: "default weight on weighted poll", allowWeighted: true, weight: 0, wantWeight: 1},
+		{name: "weight above bounds", allowWeighted: true, weight: 11, wantErr: "vote weight must be between 1 and 10"},
+		{name: "negative weight", allowWeighted: true, weight: -2, wantErr: "vote weight must be between 1 and 10"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pollID := uuid.New()
+			optionID := uuid.New()
+
+			repo := new(mocks.MockPollRepository)
+			repo.On("GetPollByID", mock.Anything, pollID).Return(&models.Poll{
+				ID:            pollID,
+				IsActive:      true,
+				AllowWeighted: tt.allowWeighted,
+			}, nil)
+			if tt.wantErr == "" {
+				repo.On("HasVoted", mock.Anything, pollID, "voter-1").Return(false, nil, nil)
+				repo.On("GetPollOptions", mock.Anything, pollID).Return([]models.PollOption{{ID: optionID, PollID: pollID}}, nil)
+				repo.On("CastVote", mock.Anything, mock.MatchedBy(func(v *models.Vote) bool {
+					return v.Weight == tt.wantWeight
+				})).Return(nil)
+			}
+
+			svc := NewPollService(repo, PollServiceConfig{MinVoteWeight: 1, MaxVoteWeight: 10})
+			_, _, err := svc.CastVote(context.Background(), pollID, optionID, "voter-1", tt.weight, "")
+
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				repo.AssertNotCalled(t, "CastVote", mock.Anything, mock.Anything)
+			} else {
+				assert.NoError(t, err)
+			}
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestGetPollResults_WeightedAggregation(t *testing.T) {
+	pollID := uuid.New()
+	options := []models.PollOption{
+		{ID: uuid.New(), PollID: pollID, OptionText: "A", VoteCount: 6}, // e.g. one vote of weight 5 plus one of weight 1
+		{ID: uuid.New(), PollID: pollID, OptionText: "B", VoteCount: 2}, // two unweighted votes
+	}
+
+	repo := new(mocks.MockPollRepository)
+	repo.On("GetPollWithResults", mock.Anything, pollID, "voter-1").Return(withVote(&models.Poll{
+		ID:            pollID,
+		IsActive:      true,
+		AllowWeighted: true,
+		TotalVotes:    8,
+	}, options, nil), nil)
+
+	svc := NewPollService(repo, PollServiceConfig{})
+	results, err := svc.GetPollResults(context.Background(), pollID, "voter-1")
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(8), results.TotalVotes)
+	assert.InDelta(t, 75.0, results.Options[0].Percentage, 0.001)
+	assert.InDelta(t, 25.0, results.Options[1].Percentage, 0.001)
+}
+
+func TestGetVoteTimeline_BucketClamping(t *testing.T) {
+	tests := []struct {
+		name       string
+		bucket     time.Duration
+		wantBucket time.Duration
+		wantErr    bool
+	}{
+		{name: "within bounds", bucket: time.Hour, wantBucket: time.Hour},
+		{name: "below minimum", bucket: time.Second, wantBucket: MinTimelineBucket},
+		{name: "above maximum", bucket: 30 * 24 * time.Hour, wantBucket: MaxTimelineBucket},
+		{name: "non-positive", bucket: 0, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pollID := uuid.New()
+
+			repo := new(mocks.MockPollRepository)
+			if !tt.wantErr {
+				repo.On("GetPollByID", mock.Anything, pollID).Return(&models.Poll{ID: pollID}, nil)
+				repo.On("GetVoteTimeline", mock.Anything, pollID, tt.wantBucket).Return(nil, nil)
+			}
+
+			svc := NewPollService(repo, PollServiceConfig{})
+			timeline, err := svc.GetVoteTimeline(context.Background(), pollID, tt.bucket)
+
+			if tt.wantErr {
+				var validationErr *ValidationError
+				assert.ErrorAs(t, err, &validationErr)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tt.wantBucket.String(), timeline.Bucket)
+				assert.NotNil(t, timeline.Buckets)
+				assert.False(t, timeline.Truncated)
+			}
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestGetVoteTimeline_CapsBuckets(t *testing.T) {
+	pollID := uuid.New()
+	buckets := make([]models.TimelineBucket, MaxTimelineBuckets+10)
+	for i := range buckets {
+		buckets[i].Start = testNow.Add(time.Duration(i) * time.Hour)
+	}
+
+	repo := new(mocks.MockPollRepository)
+	repo.On("GetPollByID", mock.Anything, pollID).Return(&models.Poll{ID: pollID}, nil)
+	repo.On("GetVoteTimeline", mock.Anything, pollID, time.Hour).Return(buckets, nil)
+
+	svc := NewPollService(repo, PollServiceConfig{})
+	timeline, err := svc.GetVoteTimeline(context.Background(), pollID, time.Hour)
+
+	require.NoError(t, err)
+	assert.True(t, timeline.Truncated)
+	require.Len(t, timeline.Buckets, MaxTimelineBuckets)
+	// The most recent buckets are kept
+	assert.Equal(t, buckets[len(buckets)-1].Start, timeline.Buckets[MaxTimelineBuckets-1].Start)
+}
+
+func TestGetPeakVoteRate(t *testing.T) {
+	tests := []struct {
+		name       string
+		bucket     time.Duration
+		peak       *models.VoteRatePeak
+		wantBucket time.Duration
+		wantRate   float64
+	}{
+		{name: "per second", bucket: time.Second, peak: &models.VoteRatePeak{Start: testNow, End: testNow.Add(time.Second), Votes: 42}, wantBucket: time.Second, wantRate: 42},
+		{name: "averaged over wider buckets", bucket: time.Minute, peak: &models.VoteRatePeak{Start: testNow, End: testNow.Add(time.Minute), Votes: 90}, wantBucket: time.Minute, wantRate: 1.5},
+		{name: "below minimum", bucket: time.Millisecond, wantBucket: MinVoteRateBucket},
+		{name: "above maximum", bucket: 30 * 24 * time.Hour, wantBucket: MaxTimelineBucket},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pollID := uuid.New()
+
+			repo := new(mocks.MockPollRepository)
+			repo.On("GetPollByID", mock.Anything, pollID).Return(&models.Poll{ID: pollID}, nil)
+			repo.On("GetPeakVoteRate", mock.Anything, pollID, tt.wantBucket).Return(tt.peak, nil)
+
+			svc := NewPollService(repo, PollServiceConfig{})
+			report, err := svc.GetPeakVoteRate(context.Background(), pollID, tt.bucket)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantBucket.String(), report.Bucket)
+			assert.Equal(t, tt.peak, report.Peak)
+			assert.InDelta(t, tt.wantRate, report.PeakVotesPerSecond, 0.001)
+			repo.AssertExpectations(t)
+		})
+	}
+
+	_, err := NewPollService(new(mocks.MockPollRepository), PollServiceConfig{}).GetPeakVoteRate(context.Background(), uuid.New(), 0)
+	requireValidationCode(t, err, CodeInvalidBucket)
+}
+
+func TestCreatePoll_DefaultTTL(t *testing.T) {
+	explicit := testNow.Add(2 * time.Hour)
+
+	tests := []struct {
+		name          string
+		ttl           time.Duration
+		expiresAt     *time.Time
+		wantExpiresAt *time.Time
+	}{
+		{name: "default applied", ttl: 24 * time.Hour, expiresAt: nil, wantExpiresAt: ptr(testNow.Add(24 * time.Hour))},
+		{name: "explicit expiry honored", ttl: 24 * time.Hour, expiresAt: &explicit, wantExpiresAt: &explicit},
+		{name: "zero TTL never expires", ttl: 0, expiresAt: nil, wantExpiresAt: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(mocks.MockPollRepository)
+			repo.On("CreatePoll", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+			req := validCreateRequest()
+			req.ExpiresAt = tt.expiresAt
+
+			svc := NewPollService(repo, PollServiceConfig{
+				Clock:          fixedClock{now: testNow},
+				DefaultPollTTL: tt.ttl,
+			})
+			poll, _, err := svc.CreatePoll(context.Background(), req, "")
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantExpiresAt, poll.ExpiresAt)
+		})
+	}
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+func TestCreatePoll_Warnings(t *testing.T) {
+	soon := testNow.Add(30 * time.Minute)
+	later := testNow.Add(48 * time.Hour)
+
+	tests := []struct {
+		name         string
+		options      []string
+		expiresAt    *time.Time
+		wantWarnings []string
+	}{
+		{
+			name:         "no warnings",
+			options:      []string{"Red", "Green", "Blue"},
+			expiresAt:    &later,
+			wantWarnings: nil,
+		},
+		{
+			name:         "only two options",
+			options:      []string{"Yes", "No"},
+			wantWarnings: []string{"poll has only 2 options"},
+		},
+		{
+			name:         "expires soon",
+			options:      []string{"Red", "Green", "Blue"},
+			expiresAt:    &soon,
+			wantWarnings: []string{"poll expires in less than an hour"},
+		},
+		{
+			name:         "duplicate options",
+			options:      []string{"Red", "Green", " red "},
+			wantWarnings: []string{"option 3 duplicates option 1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(mocks.MockPollRepository)
+			repo.On("CreatePoll", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+			req := validCreateRequest()
+			req.Options = tt.options
+			req.ExpiresAt = tt.expiresAt
+
+			svc := NewPollService(repo, PollServiceConfig{Clock: fixedClock{now: testNow}})
+			poll, warnings, err := svc.CreatePoll(context.Background(), req, "")
+
+			// Warnings never block creation
+			require.NoError(t, err)
+			require.NotNil(t, poll)
+			assert.Equal(t, tt.wantWarnings, warnings)
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestGetPollsByIDs_Validation(t *testing.T) {
+	tooMany := make([]uuid.UUID, MaxBatchPollIDs+1)
+	for i := range tooMany {
+		tooMany[i] = uuid.New()
+	}
+
+	tests := []struct {
+		name    string
+		ids     []uuid.UUID
+		wantErr string
+	}{
+		{name: "no IDs", ids: nil, wantErr: "at least one poll ID is required"},
+		{name: "too many IDs", ids: tooMany, wantErr: "at most 50 poll IDs can be requested at once"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(mocks.MockPollRepository)
+
+			svc := NewPollService(repo, PollServiceConfig{})
+			_, err := svc.GetPollsByIDs(context.Background(), tt.ids)
+
+			assert.EqualError(t, err, tt.wantErr)
+			repo.AssertNotCalled(t, "GetPollsByIDs", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestPreviewVote(t *testing.T) {
+	pollID := uuid.New()
+	optionA := uuid.New()
+	optionB := uuid.New()
+
+	repo := new(mocks.MockPollRepository)
+	repo.On("GetPollWithResults", mock.Anything, pollID, "").Return(withVote(&models.Poll{ID: pollID, IsActive: true, TotalVotes: 3}, []models.PollOption{
+		{ID: optionA, PollID: pollID, VoteCount: 3},
+		{ID: optionB, PollID: pollID, VoteCount: 0},
+	}, nil), nil)
+
+	svc := NewPollService(repo, PollServiceConfig{})
+	preview, err := svc.PreviewVote(context.Background(), pollID, optionB)
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(4), preview.TotalVotes)
+	assert.Equal(t, int64(4), preview.Poll.TotalVotes)
+	assert.Equal(t, int64(1), preview.Options[1].VoteCount)
+	assert.InDelta(t, 75.0, preview.Options[0].Percentage, 0.001)
+	assert.InDelta(t, 25.0, preview.Options[1].Percentage, 0.001)
+
+	// Nothing is persisted and the caller's vote status is not looked up
+	repo.AssertNotCalled(t, "CastVote", mock.Anything, mock.Anything)
+	repo.AssertNotCalled(t, "HasVoted", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestPreviewVote_OptionFromAnotherPoll(t *testing.T) {
+	pollID := uuid.New()
+
+	repo := new(mocks.MockPollRepository)
+	repo.On("GetPollWithResults", mock.Anything, pollID, "").Return(withVote(&models.Poll{ID: pollID, IsActive: true}, []models.PollOption{{ID: uuid.New(), PollID: pollID}}, nil), nil)
+
+	svc := NewPollService(repo, PollServiceConfig{})
+	preview, err := svc.PreviewVote(context.Background(), pollID, uuid.New())
+
+	assert.EqualError(t, err, "invalid option for this poll")
+	assert.Nil(t, preview)
+	repo.AssertNotCalled(t, "CastVote", mock.Anything, mock.Anything)
+}
+
+func TestCreatePoll_QuizValidation(t *testing.T) {
+	tests := []struct {
+		name           string
+		quizMode       bool
+		correctOptions []int
+		wantErr        string
+	}{
+		{name: "quiz with a correct option", quizMode: true, correctOptions: []int{1}},
+		{name: "quiz without a correct option", quizMode: true, wantErr: "quiz polls must mark at least one correct option"},
+		{name: "correct option out of range", quizMode: true, correctOptions: []int{2}, wantErr: "correct option 2 does not exist"},
+		{name: "correct options on a regular poll", correctOptions: []int{0}, wantErr: "correct options can only be set on quiz polls"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(mocks.MockPollRepository)
+			repo.On("CreatePoll", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+			req := validCreateRequest()
+			req.QuizMode = tt.quizMode
+			req.CorrectOptions = tt.correctOptions
+
+			svc := NewPollService(repo, PollServiceConfig{})
+			poll, _, err := svc.CreatePoll(context.Background(), req, "")
+
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				repo.AssertNotCalled(t, "CreatePoll", mock.Anything, mock.Anything, mock.Anything)
+				return
+			}
+			require.NoError(t, err)
+			assert.True(t, poll.QuizMode)
+			assert.False(t, poll.Options[0].IsCorrect)
+			assert.True(t, poll.Options[1].IsCorrect)
+		})
+	}
+}
+
+// newQuizResultsTestService returns a service over a quiz poll whose second option is correct
+// Callers mock GetPollWithResults with the voter's vote
+func newQuizResultsTestService() (*PollService, *mocks.MockPollRepository, *models.Poll, []models.PollOption) {
+	pollID := uuid.New()
+	options := []models.PollOption{
+		{ID: uuid.New(), PollID: pollID, OptionText: "Paris", VoteCount: 1},
+		{ID: uuid.New(), PollID: pollID, OptionText: "Lyon", VoteCount: 1, IsCorrect: true},
+	}
+	poll := &models.Poll{ID: pollID, IsActive: true, QuizMode: true, TotalVotes: 2}
+
+	repo := new(mocks.MockPollRepository)
+	return NewPollService(repo, PollServiceConfig{}), repo, poll, options
+}
+
+func TestGetPollResults_QuizFeedback(t *testing.T) {
+	tests := []struct {
+		name        string
+		votedOption int
+		wantCorrect bool
+	}{
+		{name: "correct answer", votedOption: 1, wantCorrect: true},
+		{name: "incorrect answer", votedOption: 0, wantCorrect: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, repo, poll, options := newQuizResultsTestService()
+			voted := options[tt.votedOption].ID
+			repo.On("GetPollWithResults", mock.Anything, poll.ID, "voter-1").Return(withVote(poll, options, &voted), nil)
+
+			results, err := svc.GetPollResults(context.Background(), poll.ID, "voter-1")
+
+			require.NoError(t, err)
+			require.NotNil(t, results.AnsweredCorrectly)
+			assert.Equal(t, tt.wantCorrect, *results.AnsweredCorrectly)
+			require.NotNil(t, results.Options[0].IsCorrect)
+			require.NotNil(t, results.Options[1].IsCorrect)
+			assert.False(t, *results.Options[0].IsCorrect)
+			assert.True(t, *results.Options[1].IsCorrect)
+		})
+	}
+}
+
+func TestGetPollResults_QuizConcealsAnswerBeforeVoting(t *testing.T) {
+	tests := []struct {
+		name  string
+		voter string
+	}{
+		{name: "voter who has not voted", voter: "voter-1"},
+		{name: "anonymous lookup", voter: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, repo, poll, options := newQuizResultsTestService()
+			repo.On("GetPollWithResults", mock.Anything, poll.ID, tt.voter).Return(withVote(poll, options, nil), nil)
+
+			results, err := svc.GetPollResults(context.Background(), poll.ID, tt.voter)
+
+			require.NoError(t, err)
+			assert.Nil(t, results.AnsweredCorrectly)
+			for _, opt := range results.Options {
+				assert.Nil(t, opt.IsCorrect)
+			}
+
+			body, err := json.Marshal(results)
+			require.NoError(t, err)
+			assert.NotContains(t, string(body), "is_correct")
+			assert.NotContains(t, string(body), "answered_correctly")
+		})
+	}
+}
+
+func TestRemoveVote(t *testing.T) {
+	tests := []struct {
+		name    string
+		poll    *models.Poll
+		repoErr error
+		wantErr error
+	}{
+		{name: "removed", poll: &models.Poll{IsActive: true}},
+		{name: "voter has not voted", poll: &models.Poll{IsActive: true}, repoErr: sql.ErrNoRows, wantErr: ErrVoteNotFound},
+		{name: "poll not found", poll: nil, wantErr: ErrPollNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pollID := uuid.New()
+
+			repo := new(mocks.MockPollRepository)
+			repo.On("GetPollByID", mock.Anything, pollID).Return(tt.poll, nil)
+			repo.On("RemoveVote", mock.Anything, pollID, "voter-1").Return(tt.repoErr)
+
+			svc := NewPollService(repo, PollServiceConfig{})
+			err := svc.RemoveVote(context.Background(), pollID, "voter-1")
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			if tt.poll == nil {
+				repo.AssertNotCalled(t, "RemoveVote", mock.Anything, mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
+// newOptionsTestService returns a service over a poll with two options and totalVotes votes
+func newOptionsTestService(totalVotes int64) (*PollService, *mocks.MockPollRepository, uuid.UUID, []models.PollOption) {
+	pollID := uuid.New()
+	options := []models.PollOption{
+		{ID: uuid.New(), PollID: pollID, OptionText: "Redd", Position: 0},
+		{ID: uuid.New(), PollID: pollID, OptionText: "Blue", Position: 1},
+	}
+
+	repo := new(mocks.MockPollRepository)
+	repo.On("GetPollByID", mock.Anything, pollID).Return(&models.Poll{ID: pollID, IsActive: true, TotalVotes: totalVotes}, nil)
+	repo.On("GetPollOptions", mock.Anything, pollID).Return(options, nil)
+
+	return NewPollService(repo, PollServiceConfig{}), repo, pollID, options
+}
+
+func TestUpdateOptions_AllowedWithoutVotes(t *testing.T) {
+	svc, repo, pollID, options := newOptionsTestService(0)
+	repo.On("UpdateOptionTexts", mock.Anything, pollID, mock.MatchedBy(func(opts []models.PollOption) bool {
+		return len(opts) == 2 && opts[0].OptionText == "Red" && opts[1].OptionText == "Blue"
+	})).Return(nil)
+
+	// The first option is matched by ID, the second by position
+	updated, err := svc.UpdateOptions(context.Background(), pollID, []models.OptionUpdate{
+		{ID: &options[0].ID, Text: "Red"},
+		{Text: "Blue"},
+	})
+
+	require.NoError(t, err)
+	require.Len(t, updated, 2)
+	assert.Equal(t, options[0].ID, updated[0].ID)
+	assert.Equal(t, "Red", updated[0].OptionText)
+	assert.Equal(t, "Blue", updated[1].OptionText)
+	repo.AssertExpectations(t)
+}
+
+func TestUpdateOptions_RejectedWithVotes(t *testing.T) {
+	svc, repo, pollID, _ := newOptionsTestService(3)
+
+	_, err := svc.UpdateOptions(context.Background(), pollID, []models.OptionUpdate{{Text: "Red"}, {Text: "Blue"}})
+
+	assert.EqualError(t, err, "options cannot be edited once the poll has votes")
+	repo.AssertNotCalled(t, "UpdateOptionTexts", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestUpdateOptions_RejectedWhenVoteArrivesFirst(t *testing.T) {
+	svc, repo, pollID, _ := newOptionsTestService(0)
+	repo.On("UpdateOptionTexts", mock.Anything, pollID, mock.Anything).Return(repository.ErrPollHasVotes)
+
+	_, err := svc.UpdateOptions(context.Background(), pollID, []models.OptionUpdate{{Text: "Red"}, {Text: "Blue"}})
+
+	var validationErr *ValidationError
+	require.ErrorAs(t, err, &validationErr)
+	assert.Equal(t, CodeOptionsLocked, validationErr.Code)
+}
+
+func TestUpdateOptions_Validation(t *testing.T) {
+	unknownID := uuid.New()
+
+	tests := []struct {
+		name    string
+		updates func(options []models.PollOption) []models.OptionUpdate
+		wantErr string
+	}{
+		{
+			name:    "too few options",
+			updates: func([]models.PollOption) []models.OptionUpdate { return []models.OptionUpdate{{Text: "Red"}} },
+			wantErr: "poll must have at least 2 options",
+		},
+		{
+			name: "empty option text",
+			updates: func([]models.PollOption) []models.OptionUpdate {
+				return []models.OptionUpdate{{Text: "Red"}, {Text: ""}}
+			},
+			wantErr: "option 2 must be between 1 and 200 characters",
+		},
+		{
+			name: "option missing from the set",
+			updates: func([]models.PollOption) []models.OptionUpdate {
+				return []models.OptionUpdate{{Text: "Red"}, {Text: "Blue"}, {Text: "Green"}}
+			},
+			wantErr: "options must list each of the poll's 2 options",
+		},
+		{
+			name: "unknown option ID",
+			updates: func([]models.PollOption) []models.OptionUpdate {
+				return []models.OptionUpdate{{Text: "Red"}, {ID: &unknownID, Text: "Blue"}}
+			},
+			wantErr: "option 2 does not match a distinct option of this poll",
+		},
+		{
+			name: "option edited twice",
+			updates: func(options []models.PollOption) []models.OptionUpdate {
+				return []models.OptionUpdate{{Text: "Red"}, {ID: &options[0].ID, Text: "Blue"}}
+			},
+			wantErr: "option 2 does not match a distinct option of this poll",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, repo, pollID, options := newOptionsTestService(0)
+
+			_, err := svc.UpdateOptions(context.Background(), pollID, tt.updates(options))
+
+			assert.EqualError(t, err, tt.wantErr)
+			repo.AssertNotCalled(t, "UpdateOptionTexts", mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+// pollsWithOptionCounts builds a page of polls carrying the given number of options each
+func pollsWithOptionCounts(counts ...int) []models.PollWithOptions {
+	polls := make([]models.PollWithOptions, len(counts))
+	for i, count := range counts {
+		polls[i].ID = uuid.New()
+		polls[i].Options = make([]models.PollOption, count)
+	}
+	return polls
+}
+
+func TestListPolls_ClampsOptionHeavyPages(t *testing.T) {
+	tests := []struct {
+		name         string
+		maxRows      int
+		optionCounts []int
+		wantPolls    int
+		wantLimit    int
+	}{
+		{name: "unlimited", maxRows: 0, optionCounts: []int{10, 10, 10}, wantPolls: 3, wantLimit: 5},
+		{name: "page within the cap", maxRows: 30, optionCounts: []int{10, 10, 10}, wantPolls: 3, wantLimit: 5},
+		{name: "option-heavy page is clamped", maxRows: 25, optionCounts: []int{10, 10, 10}, wantPolls: 2, wantLimit: 2},
+		{name: "light polls fit more per page", maxRows: 20, optionCounts: []int{2, 2, 10, 10}, wantPolls: 3, wantLimit: 3},
+		{name: "first poll is always returned", maxRows: 5, optionCounts: []int{10, 2}, wantPolls: 1, wantLimit: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			polls := pollsWithOptionCounts(tt.optionCounts...)
+
+			repo := new(mocks.MockPollRepository)
+			repo.On("ListPollsWithOptions", mock.Anything, 5, 10, false).Return(polls, nil)
+			repo.On("GetTotalPollsCount", mock.Anything, false).Return(int64(40), nil)
+
+			svc := NewPollService(repo, PollServiceConfig{MaxListOptionRows: tt.maxRows})
+			page, err := svc.ListPolls(context.Background(), 5, 10, false)
+
+			require.NoError(t, err)
+			assert.Equal(t, polls[:tt.wantPolls], page.Polls)
+			assert.Equal(t, tt.wantLimit, page.Limit)
+			assert.Equal(t, 10, page.Offset)
+			assert.Equal(t, int64(40), page.Total)
+		})
+	}
+}
+
+func TestExpirePoll_RejectsLaterVotes(t *testing.T) {
+	pollID := uuid.New()
+	optionID := uuid.New()
+	poll := &models.Poll{ID: pollID, IsActive: true}
+
+	repo := new(mocks.MockPollRepository)
+	repo.On("GetPollByID", mock.Anything, pollID).Return(poll, nil)
+	repo.On("ExpireNow", mock.Anything, pollID).Return(testNow, nil).Run(func(mock.Arguments) {
+		// Mirror the database update so later reads see the expiry
+		expiresAt := testNow
+		poll.ExpiresAt = &expiresAt
+	})
+
+	svc := NewPollService(repo, PollServiceConfig{Clock: fixedClock{now: testNow}})
+	expiresAt, err := svc.ExpirePoll(context.Background(), pollID)
+	require.NoError(t, err)
+	assert.Equal(t, testNow, expiresAt)
+
+	_, _, err = svc.CastVote(context.Background(), pollID, optionID, "voter-1", 0, "")
+
+	var validationErr *ValidationError
+	require.ErrorAs(t, err, &validationErr)
+	assert.Equal(t, CodePollExpired, validationErr.Code)
+	assert.EqualError(t, err, "poll has expired")
+	repo.AssertNotCalled(t, "CastVote", mock.Anything, mock.Anything)
+}
+
+func TestExpirePoll_NotFound(t *testing.T) {
+	repo := new(mocks.MockPollRepository)
+	repo.On("ExpireNow", mock.Anything, mock.Anything).Return(time.Time{}, sql.ErrNoRows)
+
+	svc := NewPollService(repo, PollServiceConfig{})
+	_, err := svc.ExpirePoll(context.Background(), uuid.New())
+
+	assert.ErrorIs(t, err, ErrPollNotFound)
+}
+
+func TestGetPollResults_Leading(t *testing.T) {
+	a, b, c := uuid.New(), uuid.New(), uuid.New()
+
+	tests := []struct {
+		name       string
+		counts     []int64
+		want       []uuid.UUID
+		wantWinner *uuid.UUID
+	}{
+		{name: "clear winner", counts: []int64{5, 2, 1}, want: []uuid.UUID{a}, wantWinner: &a},
+		{name: "two-way tie", counts: []int64{3, 1, 3}, want: []uuid.UUID{a, c}},
+		{name: "no votes", counts: []int64{0, 0, 0}, want: []uuid.UUID{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pollID := uuid.New()
+			options := []models.PollOption{
+				{ID: a, PollID: pollID, OptionText: "A", VoteCount: tt.counts[0]},
+				{ID: b, PollID: pollID, OptionText: "B", VoteCount: tt.counts[1]},
+				{ID: c, PollID: pollID, OptionText: "C", VoteCount: tt.counts[2]},
+			}
+
+			repo := new(mocks.MockPollRepository)
+			poll := &models.Poll{ID: pollID, IsActive: true, TotalVotes: tt.counts[0] + tt.counts[1] + tt.counts[2]}
+			repo.On("GetPollWithResults", mock.Anything, pollID, "").Return(withVote(poll, options, nil), nil)
+
+			svc := NewPollService(repo, PollServiceConfig{})
+			results, err := svc.GetPollResults(context.Background(), pollID, "")
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, results.Leading)
+			assert.Equal(t, tt.wantWinner, results.WinningOption)
+
+			// leading and winning_option are always present, even when empty
+			body, err := json.Marshal(results)
+			require.NoError(t, err)
+			assert.Contains(t, string(body), `"leading":[`)
+			if tt.wantWinner == nil {
+				assert.Contains(t, string(body), `"winning_option":null`)
+			}
+		})
+	}
+}
+
+func TestPreviewVote_UpdatesLeading(t *testing.T) {
+	pollID := uuid.New()
+	a, b := uuid.New(), uuid.New()
+
+	repo := new(mocks.MockPollRepository)
+	repo.On("GetPollWithResults", mock.Anything, pollID, "").Return(withVote(&models.Poll{ID: pollID, IsActive: true, TotalVotes: 4}, []models.PollOption{
+		{ID: a, PollID: pollID, VoteCount: 2},
+		{ID: b, PollID: pollID, VoteCount: 2},
+	}, nil), nil)
+
+	svc := NewPollService(repo, PollServiceConfig{})
+	results, err := svc.PreviewVote(context.Background(), pollID, b)
+
+	require.NoError(t, err)
+	assert.Equal(t, []uuid.UUID{b}, results.Leading)
+	assert.Equal(t, &b, results.WinningOption, "the previewed vote breaks the tie")
+}
+
+func TestCreatePoll_Group(t *testing.T) {
+	tests := []struct {
+		name      string
+		group     *string
+		wantGroup *string
+		wantErr   string
+	}{
+		{name: "no group", group: nil, wantGroup: nil},
+		{name: "trimmed", group: ptr("  Election 2026 "), wantGroup: ptr("Election 2026")},
+		{name: "blank means no group", group: ptr("   "), wantGroup: nil},
+		{name: "too long", group: ptr(strings.Repeat("g", 101)), wantErr: "group must be at most 100 characters"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(mocks.MockPollRepository)
+			repo.On("CreatePoll", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+			req := validCreateRequest()
+			req.Group = tt.group
+
+			svc := NewPollService(repo, PollServiceConfig{})
+			poll, _, err := svc.CreatePoll(context.Background(), req, "")
+
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				repo.AssertNotCalled(t, "CreatePoll", mock.Anything, mock.Anything, mock.Anything)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantGroup, poll.Group)
+		})
+	}
+}
+
+// withVote is what GetPollWithResults returns for a poll, its options and the voter's vote
+func withVote(poll *models.Poll, options []models.PollOption, voted *uuid.UUID) *models.PollWithVote {
+	return &models.PollWithVote{
+		PollWithOptions: models.PollWithOptions{Poll: *poll, Options: options},
+		VotedOption:     voted,
+	}
+}
+
+// groupVoteRepo returns a repository mock over polls whose votes are recorded
+// by CastVote and looked up case-insensitively by HasVotedInGroup, like the database
+func groupVoteRepo(polls ...*models.Poll) *mocks.MockPollRepository {
+	repo := new(mocks.MockPollRepository)
+	voted := make(map[string]map[string]bool) // lowercased group -> voters
+	groupOf := make(map[uuid.UUID]*string)
+
+	for _, poll := range polls {
+		groupOf[poll.ID] = poll.Group
+		repo.On("GetPollByID", mock.Anything, poll.ID).Return(poll, nil)
+		repo.On("GetPollOptions", mock.Anything, poll.ID).Return([]models.PollOption{{ID: poll.ID, PollID: poll.ID}}, nil)
+		repo.On("HasVoted", mock.Anything, poll.ID, mock.Anything).Return(false, nil, nil)
+	}
+
+	repo.On("CastVote", mock.Anything, mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		vote := args.Get(1).(*models.Vote)
+		if group := groupOf[vote.PollID]; group != nil {
+			key := strings.ToLower(*group)
+			if voted[key] == nil {
+				voted[key] = make(map[string]bool)
+			}
+			voted[key][vote.VoterIdentifier] = true
+		}
+	})
+
+	call := repo.On("HasVotedInGroup", mock.Anything, mock.Anything, mock.Anything)
+	call.Run(func(args mock.Arguments) {
+		call.ReturnArguments = mock.Arguments{voted[strings.ToLower(args.String(1))][args.String(2)], nil}
+	})
+
+	return repo
+}
+
+func TestCastVote_GroupDedup(t *testing.T) {
+	first := &models.Poll{ID: uuid.New(), IsActive: true, Group: ptr("Board Election")}
+	second := &models.Poll{ID: uuid.New(), IsActive: true, Group: ptr("board election")}
+
+	repo := groupVoteRepo(first, second)
+	svc := NewPollService(repo, PollServiceConfig{GroupVoterDedup: true})
+
+	_, _, err := svc.CastVote(context.Background(), first.ID, first.ID, "voter-1", 0, "")
+	require.NoError(t, err)
+
+	// The group is shared regardless of case, so the second poll rejects the same voter
+	_, _, err = svc.CastVote(context.Background(), second.ID, second.ID, "voter-1", 0, "")
+	var validationErr *ValidationError
+	require.ErrorAs(t, err, &validationErr)
+	assert.Equal(t, CodeAlreadyVotedInGroup, validationErr.Code)
+
+	// Other voters are unaffected
+	_, _, err = svc.CastVote(context.Background(), second.ID, second.ID, "voter-2", 0, "")
+	require.NoError(t, err)
+	repo.AssertNumberOfCalls(t, "CastVote", 2)
+}
+
+func TestCastVote_GroupDedupIndependentGroups(t *testing.T) {
+	spring := &models.Poll{ID: uuid.New(), IsActive: true, Group: ptr("spring")}
+	autumn := &models.Poll{ID: uuid.New(), IsActive: true, Group: ptr("autumn")}
+	ungrouped := &models.Poll{ID: uuid.New(), IsActive: true}
+
+	repo := groupVoteRepo(spring, autumn, ungrouped)
+	svc := NewPollService(repo, PollServiceConfig{GroupVoterDedup: true})
+
+	for _, poll := range []*models.Poll{spring, autumn, ungrouped} {
+		_, _, err := svc.CastVote(context.Background(), poll.ID, poll.ID, "voter-1", 0, "")
+		require.NoError(t, err)
+	}
+	repo.AssertNumberOfCalls(t, "CastVote", 3)
+	repo.AssertNumberOfCalls(t, "HasVotedInGroup", 2) // The ungrouped poll skips the group check
+}
+
+func TestCastVote_GroupDedupDisabled(t *testing.T) {
+	first := &models.Poll{ID: uuid.New(), IsActive: true, Group: ptr("series")}
+	second := &models.Poll{ID: uuid.New(), IsActive: true, Group: ptr("series")}
+
+	repo := groupVoteRepo(first, second)
+	svc := NewPollService(repo, PollServiceConfig{})
+
+	for _, poll := range []*models.Poll{first, second} {
+		_, _, err := svc.CastVote(context.Background(), poll.ID, poll.ID, "voter-1", 0, "")
+		require.NoError(t, err)
+	}
+	repo.AssertNotCalled(t, "HasVotedInGroup", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestGetPollResults_CacheLoadsConcurrentMissesOnce(t *testing.T) {
+	pollID := uuid.New()
+	release := make(chan time.Time)
+
+	repo := new(mocks.MockPollRepository)
+	// The first read blocks until every request has missed the cache
+	repo.On("GetPollWithResults", mock.Anything, pollID, "").Return(withVote(&models.Poll{ID: pollID, IsActive: true, TotalVotes: 3}, []models.PollOption{
+		{ID: uuid.New(), PollID: pollID, VoteCount: 2},
+		{ID: uuid.New(), PollID: pollID, VoteCount: 1},
+	}, nil), nil).WaitUntil(release)
+
+	svc := NewPollService(repo, PollServiceConfig{ResultsCacheTTL: time.Minute})
+
+	const requests = 20
+	var wg sync.WaitGroup
+	wg.Add(requests)
+	for range requests {
+		go func() {
+			defer wg.Done()
+			results, err := svc.GetPollResults(context.Background(), pollID, "")
+			assert.NoError(t, err)
+			assert.Equal(t, int64(3), results.TotalVotes)
+		}()
+	}
+
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	repo.AssertNumberOfCalls(t, "GetPollWithResults", 1)
+}
+
+func TestGetPollResults_CacheRefreshedByVote(t *testing.T) {
+	pollID := uuid.New()
+	optionID := uuid.New()
+
+	repo := new(mocks.MockPollRepository)
+	// Results reads see the vote once it has been cast
+	repo.On("GetPollWithResults", mock.Anything, pollID, "").Return(withVote(&models.Poll{ID: pollID, IsActive: true}, []models.PollOption{{ID: optionID, PollID: pollID}}, nil), nil).Once()
+	repo.On("GetPollWithResults", mock.Anything, pollID, "").Return(withVote(&models.Poll{ID: pollID, IsActive: true, TotalVotes: 1}, []models.PollOption{{ID: optionID, PollID: pollID, VoteCount: 1}}, nil), nil).Once()
+	repo.On("GetPollByID", mock.Anything, pollID).Return(&models.Poll{ID: pollID, IsActive: true}, nil)
+	repo.On("GetPollOptions", mock.Anything, pollID).Return([]models.PollOption{{ID: optionID, PollID: pollID}}, nil)
+	repo.On("HasVoted", mock.Anything, pollID, "voter-1").Return(false, nil, nil)
+	repo.On("CastVote", mock.Anything, mock.Anything).Return(nil)
+
+	svc := NewPollService(repo, PollServiceConfig{ResultsCacheTTL: time.Minute})
+
+	before, err := svc.GetPollResults(context.Background(), pollID, "")
+	require.NoError(t, err)
+	assert.Zero(t, before.TotalVotes)
+
+	_, _, err = svc.CastVote(context.Background(), pollID, optionID, "voter-1", 0, "")
+	require.NoError(t, err)
+
+	// The vote drops the cached counts, so the next read goes back to the database
+	after, err := svc.GetPollResults(context.Background(), pollID, "")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), after.TotalVotes)
+	assert.Equal(t, int64(1), after.Options[0].VoteCount)
+	repo.AssertExpectations(t)
+}
+
+func TestGetPollResults_CachedPollLooksUpEachVoter(t *testing.T) {
+	pollID := uuid.New()
+	optionID := uuid.New()
+
+	repo := new(mocks.MockPollRepository)
+	repo.On("GetPollWithResults", mock.Anything, pollID, "").Return(withVote(&models.Poll{ID: pollID, IsActive: true, TotalVotes: 1}, []models.PollOption{{ID: optionID, PollID: pollID, VoteCount: 1}}, nil), nil).Once()
+	repo.On("HasVoted", mock.Anything, pollID, "voter-1").Return(true, &optionID, nil)
+	repo.On("HasVoted", mock.Anything, pollID, "voter-2").Return(false, nil, nil)
+
+	svc := NewPollService(repo, PollServiceConfig{ResultsCacheTTL: time.Minute})
+
+	voted, err := svc.GetPollResults(context.Background(), pollID, "voter-1")
+	require.NoError(t, err)
+	assert.True(t, voted.HasVoted)
+	assert.Equal(t, &optionID, voted.VotedOption)
+
+	// The cached poll is shared, so one voter's status never leaks to another
+	notVoted, err := svc.GetPollResults(context.Background(), pollID, "voter-2")
+	require.NoError(t, err)
+	assert.False(t, notVoted.HasVoted)
+	assert.Nil(t, notVoted.VotedOption)
+	repo.AssertExpectations(t)
+}
+
+// newRandomizedTestService returns a service over a poll of ten options with the given RandomizeOptions flag
+func newRandomizedTestService(randomize bool) (*PollService, uuid.UUID, []uuid.UUID) {
+	pollID := uuid.MustParse("5b0c3f8e-7a43-4c36-9d1f-2f5e9b7f4a10")
+	options := make([]models.PollOption, 10)
+	ids := make([]uuid.UUID, len(options))
+	for i := range options {
+		ids[i] = uuid.New()
+		options[i] = models.PollOption{ID: ids[i], PollID: pollID, OptionText: fmt.Sprintf("Option %d", i), VoteCount: int64(i), Position: i}
+	}
+	poll := &models.Poll{ID: pollID, IsActive: true, TotalVotes: 45, RandomizeOptions: randomize}
+
+	repo := new(mocks.MockPollRepository)
+	repo.On("GetPollWithResults", mock.Anything, pollID, mock.Anything).Return(withVote(poll, options, nil), nil)
+	repo.On("GetPollByID", mock.Anything, pollID).Return(poll, nil)
+	call := repo.On("GetPollOptions", mock.Anything, pollID)
+	call.Run(func(mock.Arguments) {
+		// A fresh slice per read, as the database would return
+		call.ReturnArguments = mock.Arguments{slices.Clone(options), nil}
+	})
+
+	return NewPollService(repo, PollServiceConfig{}), pollID, ids
+}
+
+// resultOrder lists the option IDs of results in the order returned
+func resultOrder(t *testing.T, svc *PollService, pollID uuid.UUID, voter string) []uuid.UUID {
+	t.Helper()
+	results, err := svc.GetPollResults(context.Background(), pollID, voter)
+	require.NoError(t, err)
+	order := make([]uuid.UUID, len(results.Options))
+	for i, opt := range results.Options {
+		order[i] = opt.ID
+		// Counts and percentages travel with their option
+		assert.Equal(t, int64(opt.Position), opt.VoteCount)
+		assert.InDelta(t, float64(opt.Position)/45*100, opt.Percentage, 0.001)
+	}
+	return order
+}
+
+func TestGetPollResults_RandomizeOptions(t *testing.T) {
+	svc, pollID, ids := newRandomizedTestService(true)
+
+	first := resultOrder(t, svc, pollID, "voter-1")
+	assert.NotEqual(t, ids, first, "options are shuffled")
+	assert.ElementsMatch(t, ids, first)
+
+	// The same voter always sees the same order, on both endpoints
+	assert.Equal(t, first, resultOrder(t, svc, pollID, "voter-1"))
+	options, err := svc.GetPollOptions(context.Background(), pollID, "voter-1")
+	require.NoError(t, err)
+	for i, opt := range options {
+		assert.Equal(t, first[i], opt.ID)
+	}
+
+	// Other voters get their own order
+	assert.NotEqual(t, first, resultOrder(t, svc, pollID, "voter-2"))
+
+	// Anonymous reads, such as charts and exports, keep position order
+	assert.Equal(t, ids, resultOrder(t, svc, pollID, ""))
+}
+
+func TestGetPollResults_RandomizeOptionsOff(t *testing.T) {
+	svc, pollID, ids := newRandomizedTestService(false)
+
+	assert.Equal(t, ids, resultOrder(t, svc, pollID, "voter-1"))
+	options, err := svc.GetPollOptions(context.Background(), pollID, "voter-1")
+	require.NoError(t, err)
+	for i, opt := range options {
+		assert.Equal(t, ids[i], opt.ID)
+	}
+}
+
+func TestArchiveClosedPolls(t *testing.T) {
+	repo := new(mocks.MockPollRepository)
+	closedBefore := testNow.Add(-24 * time.Hour)
+
+	// A full batch is followed by another until one comes back short
+	fullBatch := make([]uuid.UUID, ArchiveBatchSize)
+	for i := range fullBatch {
+		fullBatch[i] = uuid.New()
+	}
+	repo.On("ArchiveClosedPolls", mock.Anything, closedBefore, ArchiveBatchSize).Return(fullBatch, nil).Once()
+	repo.On("ArchiveClosedPolls", mock.Anything, closedBefore, ArchiveBatchSize).Return([]uuid.UUID{uuid.New()}, nil).Once()
+
+	svc := NewPollService(repo, PollServiceConfig{ArchiveRetention: 24 * time.Hour, Clock: fixedClock{testNow}})
+	archived, err := svc.ArchiveClosedPolls(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(ArchiveBatchSize+1), archived)
+	repo.AssertExpectations(t)
+}
+
+func TestArchiveClosedPolls_Disabled(t *testing.T) {
+	repo := new(mocks.MockPollRepository)
+
+	svc := NewPollService(repo, PollServiceConfig{})
+	archived, err := svc.ArchiveClosedPolls(context.Background())
+
+	require.NoError(t, err)
+	assert.Zero(t, archived)
+	repo.AssertNotCalled(t, "ArchiveClosedPolls", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestGetPollResults_ArchiveFallback(t *testing.T) {
+	for _, cacheTTL := range []time.Duration{0, time.Minute} {
+		t.Run(fmt.Sprintf("cache ttl %s", cacheTTL), func(t *testing.T) {
+			repo := new(mocks.MockPollRepository)
+			poll := &models.Poll{ID: uuid.New(), Question: "Archived?", TotalVotes: 3}
+			options := []models.PollOption{
+				{ID: uuid.New(), PollID: poll.ID, VoteCount: 1},
+				{ID: uuid.New(), PollID: poll.ID, VoteCount: 2},
+			}
+			repo.On("GetPollWithResults", mock.Anything, poll.ID, mock.Anything).Return(nil, nil)
+			repo.On("GetArchivedPollWithResults", mock.Anything, poll.ID, "voter-1").Return(withVote(poll, options, &options[1].ID), nil)
+			repo.On("GetArchivedPollWithResults", mock.Anything, poll.ID, "").Return(withVote(poll, options, nil), nil)
+
+			svc := NewPollService(repo, PollServiceConfig{ResultsCacheTTL: cacheTTL})
+			results, err := svc.GetPollResults(context.Background(), poll.ID, "voter-1")
+
+			require.NoError(t, err)
+			assert.Equal(t, "Archived?", results.Question)
+			assert.Equal(t, int64(3), results.TotalVotes)
+			assert.True(t, results.HasVoted)
+			assert.Equal(t, &options[1].ID, results.VotedOption)
+
+			// The live votes table no longer holds archived votes
+			repo.AssertNotCalled(t, "HasVoted", mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestGetPollResults_NotArchived(t *testing.T) {
+	repo := new(mocks.MockPollRepository)
+	pollID := uuid.New()
+	repo.On("GetPollWithResults", mock.Anything, pollID, "").Return(nil, nil)
+	repo.On("GetArchivedPollWithResults", mock.Anything, pollID, "").Return(nil, nil)
+
+	svc := NewPollService(repo, PollServiceConfig{})
+	_, err := svc.GetPollResults(context.Background(), pollID, "")
+
+	require.ErrorIs(t, err, ErrPollNotFound)
+}
+
+func TestVotingStatus(t *testing.T) {
+	tests := []struct {
+		name        string
+		poll        models.Poll
+		want        string
+		wantOpensAt *time.Time
+	}{
+		{name: "open", poll: models.Poll{IsActive: true, ExpiresAt: ptr(testNow.Add(time.Hour))}, want: models.VotingOpen},
+		{name: "closed", poll: models.Poll{IsActive: false}, want: models.VotingClosed},
+		{name: "expired", poll: models.Poll{IsActive: true, ExpiresAt: ptr(testNow.Add(-time.Hour))}, want: models.VotingExpired},
+		{name: "expiring exactly now", poll: models.Poll{IsActive: true, ExpiresAt: ptr(testNow)}, want: models.VotingExpired},
+		{
+			name: "before today's voting window",
+			poll: models.Poll{IsActive: true, VotingWindowStart: ptr("13:00"), VotingWindowEnd: ptr("17:00")},
+			want: models.VotingNotStarted, wantOpensAt: ptr(testNow.Add(time.Hour)),
+		},
+		{
+			// testNow is 08:00 in New York, after a window that closed at 07:00 local time
+			name: "after today's voting window",
+			poll: models.Poll{IsActive: true, VotingWindowStart: ptr("06:00"), VotingWindowEnd: ptr("07:00"), Timezone: ptr("America/New_York")},
+			want: models.VotingNotStarted, wantOpensAt: ptr(time.Date(2025, time.June, 2, 10, 0, 0, 0, time.UTC)),
+		},
+		{name: "inside the voting window", poll: models.Poll{IsActive: true, VotingWindowStart: ptr("11:00"), VotingWindowEnd: ptr("13:00")}, want: models.VotingOpen},
+		{name: "full", poll: models.Poll{IsActive: true, TotalVotes: 5, MaxVotes: ptr(int64(5))}, want: models.VotingFull},
+		{name: "below max votes", poll: models.Poll{IsActive: true, TotalVotes: 4, MaxVotes: ptr(int64(5))}, want: models.VotingOpen},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pollID := uuid.New()
+			tt.poll.ID = pollID
+			repo := new(mocks.MockPollRepository)
+			repo.On("GetPollByID", mock.Anything, pollID).Return(&tt.poll, nil)
+
+			svc := NewPollService(repo, PollServiceConfig{Clock: fixedClock{now: testNow}})
+			status, err := svc.VotingStatus(context.Background(), pollID)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, status.Status)
+			assert.Equal(t, tt.want == models.VotingOpen, status.AcceptingVotes)
+			assert.Equal(t, tt.wantOpensAt, status.OpensAt)
+		})
+	}
+}
+
+func TestVotingStatus_PollNotFound(t *testing.T) {
+	pollID := uuid.New()
+	repo := new(mocks.MockPollRepository)
+	repo.On("GetPollByID", mock.Anything, pollID).Return(nil, nil)
+
+	svc := NewPollService(repo, PollServiceConfig{})
+	_, err := svc.VotingStatus(context.Background(), pollID)
+
+	assert.ErrorIs(t, err, ErrPollNotFound)
+}
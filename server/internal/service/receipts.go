@@ -0,0 +1,43 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/moabdelazem/k8s-app/internal/models"
+	"github.com/moabdelazem/k8s-app/pkg/receipt"
+)
+
+// errReceiptsDisabled is returned when no receipt signing secret is configured
+var errReceiptsDisabled = errors.New("vote receipts are not configured")
+
+// issueReceipt signs a receipt for a recorded vote, or returns nil when receipts are disabled
+func (s *PollService) issueReceipt(vote *models.Vote) *models.VoteReceipt {
+	if s.cfg.ReceiptSecret == "" {
+		return nil
+	}
+
+	// Report the time as the receipt carries it, so verifying it echoes the same value
+	signed := receipt.Receipt{PollID: vote.PollID, OptionID: vote.OptionID, VotedAt: vote.VotedAt.Truncate(time.Microsecond).UTC()}
+	token := receipt.Sign(s.cfg.ReceiptSecret, signed, vote.VoterIdentifier)
+
+	return &models.VoteReceipt{Receipt: token, PollID: signed.PollID, OptionID: signed.OptionID, VotedAt: signed.VotedAt}
+}
+
+// VerifyReceipt checks that a receipt was issued by this server for a vote on pollID
+// and returns what it attests to. The voter's identity is never part of the answer.
+// A receipt proves the vote was recorded; it stays valid if the vote is later removed.
+func (s *PollService) VerifyReceipt(ctx context.Context, pollID uuid.UUID, token string) (*models.VoteReceipt, error) {
+	if s.cfg.ReceiptSecret == "" {
+		return nil, errReceiptsDisabled
+	}
+
+	verified, err := receipt.Parse(s.cfg.ReceiptSecret, token)
+	if err != nil || verified.PollID != pollID {
+		return nil, newValidationError(CodeReceiptInvalid, "receipt is invalid or was not issued for this poll")
+	}
+
+	return &models.VoteReceipt{Receipt: token, PollID: verified.PollID, OptionID: verified.OptionID, VotedAt: verified.VotedAt}, nil
+}
@@ -0,0 +1,93 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/moabdelazem/k8s-app/pkg/receipt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCastVote_IssuesVerifiableReceipt(t *testing.T) {
+	ctx := context.Background()
+	svc := newMemoryTestService(PollServiceConfig{ReceiptSecret: "receipt-secret"})
+	poll := createMemoryPoll(t, svc, validCreateRequest())
+
+	_, receipt, err := svc.CastVote(ctx, poll.ID, poll.Options[1].ID, "voter-1", 0, "")
+	require.NoError(t, err)
+	require.NotNil(t, receipt)
+	assert.Equal(t, poll.ID, receipt.PollID)
+	assert.Equal(t, poll.Options[1].ID, receipt.OptionID)
+	assert.NotContains(t, receipt.Receipt, "voter-1")
+
+	verified, err := svc.VerifyReceipt(ctx, poll.ID, receipt.Receipt)
+	require.NoError(t, err)
+	assert.Equal(t, receipt, verified)
+}
+
+func TestConfirmVote_IssuesReceipt(t *testing.T) {
+	ctx := context.Background()
+	svc := newMemoryTestService(PollServiceConfig{ReceiptSecret: "receipt-secret"})
+	req := validCreateRequest()
+	req.RequireConfirmation = true
+	poll := createMemoryPoll(t, svc, req)
+
+	confirmation, receipt, err := svc.CastVote(ctx, poll.ID, poll.Options[0].ID, "voter-1", 0, "")
+	require.NoError(t, err)
+	assert.Nil(t, receipt, "pending votes get no receipt")
+
+	receipt, err = svc.ConfirmVote(ctx, poll.ID, confirmation.Token, "voter-1")
+	require.NoError(t, err)
+	require.NotNil(t, receipt)
+
+	_, err = svc.VerifyReceipt(ctx, poll.ID, receipt.Receipt)
+	assert.NoError(t, err)
+}
+
+func TestCastVote_NoReceiptWithoutSecret(t *testing.T) {
+	ctx := context.Background()
+	svc := newMemoryTestService(PollServiceConfig{})
+	poll := createMemoryPoll(t, svc, validCreateRequest())
+
+	_, receipt, err := svc.CastVote(ctx, poll.ID, poll.Options[0].ID, "voter-1", 0, "")
+	require.NoError(t, err)
+	assert.Nil(t, receipt)
+
+	_, err = svc.VerifyReceipt(ctx, poll.ID, "anything")
+	assert.ErrorIs(t, err, errReceiptsDisabled)
+}
+
+func TestVerifyReceipt_Rejects(t *testing.T) {
+	ctx := context.Background()
+	svc := newMemoryTestService(PollServiceConfig{ReceiptSecret: "receipt-secret"})
+	poll := createMemoryPoll(t, svc, validCreateRequest())
+
+	_, issued, err := svc.CastVote(ctx, poll.ID, poll.Options[0].ID, "voter-1", 0, "")
+	require.NoError(t, err)
+
+	// Point the receipt at the other option by flipping a bit of the option ID
+	tampered := []byte(issued.Receipt)
+	tampered[25] ^= 1
+
+	forged := receipt.Sign("other-secret", receipt.Receipt{PollID: poll.ID, OptionID: poll.Options[1].ID}, "voter-2")
+
+	tests := []struct {
+		name    string
+		pollID  uuid.UUID
+		receipt string
+	}{
+		{"tampered receipt", poll.ID, string(tampered)},
+		{"receipt for another poll", uuid.New(), issued.Receipt},
+		{"receipt signed with another secret", poll.ID, forged},
+		{"malformed receipt", poll.ID, "not a receipt"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := svc.VerifyReceipt(ctx, tt.pollID, tt.receipt)
+			requireValidationCode(t, err, CodeReceiptInvalid)
+		})
+	}
+}
@@ -0,0 +1,90 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/moabdelazem/k8s-app/internal/models"
+	"github.com/moabdelazem/k8s-app/pkg/logger"
+	"github.com/moabdelazem/k8s-app/pkg/sharelink"
+	"go.uber.org/zap"
+)
+
+// DefaultShareLinkTTL is how long share links stay valid when no TTL is configured
+const DefaultShareLinkTTL = 30 * 24 * time.Hour
+
+// errShareLinksDisabled is returned when no signing secret is configured
+var errShareLinksDisabled = errors.New("share links are not configured")
+
+// SharePoll issues a signed share link for a poll, optionally tagged with a campaign for attribution
+func (s *PollService) SharePoll(ctx context.Context, pollID uuid.UUID, campaign string) (*models.ShareLink, error) {
+	if s.cfg.ShareSecret == "" {
+		return nil, errShareLinksDisabled
+	}
+	if len(campaign) > sharelink.MaxCampaignLength {
+		return nil, newValidationError(CodeCampaignLength, "campaign must be at most %d characters", sharelink.MaxCampaignLength)
+	}
+
+	poll, err := s.repo.GetPollByID(ctx, pollID)
+	if err != nil {
+		return nil, wrapRepoError("failed to get poll", err)
+	}
+	if poll == nil {
+		return nil, ErrPollNotFound
+	}
+
+	// Tokens only carry whole seconds
+	expiresAt := s.clock.Now().Add(s.cfg.ShareLinkTTL).Truncate(time.Second)
+	token, err := sharelink.Sign(s.cfg.ShareSecret, sharelink.Link{
+		PollID:    pollID,
+		Campaign:  campaign,
+		ExpiresAt: expiresAt,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.ShareLink{Token: token, Campaign: campaign, ExpiresAt: expiresAt}, nil
+}
+
+// OpenShareLink resolves a share token to the poll's results as seen by voterIdentifier
+func (s *PollService) OpenShareLink(ctx context.Context, token string, voterIdentifier string) (*models.SharedPoll, error) {
+	if s.cfg.ShareSecret == "" {
+		return nil, errShareLinksDisabled
+	}
+
+	link, err := sharelink.Parse(s.cfg.ShareSecret, token, s.clock.Now())
+	if err != nil {
+		return nil, newValidationError(CodeShareLinkInvalid, "share link is invalid or has expired")
+	}
+
+	results, err := s.GetPollResults(ctx, link.PollID, voterIdentifier)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.SharedPoll{Poll: results, Campaign: link.Campaign, ShareToken: token}, nil
+}
+
+// shareCampaign returns the campaign of a share token presented when voting on pollID, or nil.
+// Attribution is best effort: invalid or expired tokens, or tokens for another poll, never block a vote.
+func (s *PollService) shareCampaign(pollID uuid.UUID, token string) *string {
+	if token == "" || s.cfg.ShareSecret == "" {
+		return nil
+	}
+
+	link, err := sharelink.Parse(s.cfg.ShareSecret, token, s.clock.Now())
+	if err != nil || link.PollID != pollID {
+		logger.Debug("Ignoring share token presented with vote",
+			zap.String("poll_id", pollID.String()),
+			zap.Error(err),
+		)
+		return nil
+	}
+	if link.Campaign == "" {
+		return nil
+	}
+	return &link.Campaign
+}
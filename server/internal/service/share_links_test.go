@@ -0,0 +1,166 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/moabdelazem/k8s-app/internal/mocks"
+	"github.com/moabdelazem/k8s-app/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// newShareTestService returns a service signing share links for an open poll with a single option
+func newShareTestService(clock Clock) (*PollService, *mocks.MockPollRepository, uuid.UUID, uuid.UUID) {
+	pollID := uuid.New()
+	optionID := uuid.New()
+
+	repo := new(mocks.MockPollRepository)
+	repo.On("GetPollByID", mock.Anything, pollID).Return(&models.Poll{ID: pollID, IsActive: true}, nil)
+	repo.On("GetPollOptions", mock.Anything, pollID).Return([]models.PollOption{{ID: optionID, PollID: pollID}}, nil)
+	repo.On("HasVoted", mock.Anything, pollID, "voter-1").Return(false, nil, nil)
+	repo.On("GetPollWithResults", mock.Anything, pollID, "voter-1").Return(&models.PollWithVote{
+		PollWithOptions: models.PollWithOptions{
+			Poll:    models.Poll{ID: pollID, IsActive: true},
+			Options: []models.PollOption{{ID: optionID, PollID: pollID}},
+		},
+	}, nil)
+
+	svc := NewPollService(repo, PollServiceConfig{Clock: clock, ShareSecret: "share-secret", ShareLinkTTL: time.Hour})
+	return svc, repo, pollID, optionID
+}
+
+func TestSharePoll_RoundTrip(t *testing.T) {
+	svc, _, pollID, _ := newShareTestService(fixedClock{now: testNow})
+
+	link, err := svc.SharePoll(context.Background(), pollID, "newsletter")
+	require.NoError(t, err)
+	assert.NotEmpty(t, link.Token)
+	assert.Equal(t, "newsletter", link.Campaign)
+	assert.Equal(t, testNow.Add(time.Hour), link.ExpiresAt)
+
+	shared, err := svc.OpenShareLink(context.Background(), link.Token, "voter-1")
+	require.NoError(t, err)
+	assert.Equal(t, pollID, shared.Poll.ID)
+	assert.Equal(t, "newsletter", shared.Campaign)
+	assert.Equal(t, link.Token, shared.ShareToken)
+}
+
+func TestSharePoll_Validation(t *testing.T) {
+	svc, repo, pollID, _ := newShareTestService(fixedClock{now: testNow})
+
+	_, err := svc.SharePoll(context.Background(), pollID, strings.Repeat("c", 65))
+	assert.EqualError(t, err, "campaign must be at most 64 characters")
+
+	missing := uuid.New()
+	repo.On("GetPollByID", mock.Anything, missing).Return(nil, nil)
+	_, err = svc.SharePoll(context.Background(), missing, "")
+	assert.ErrorIs(t, err, ErrPollNotFound)
+}
+
+func TestSharePoll_DisabledWithoutSecret(t *testing.T) {
+	svc := NewPollService(new(mocks.MockPollRepository), PollServiceConfig{})
+
+	_, err := svc.SharePoll(context.Background(), uuid.New(), "")
+	assert.ErrorIs(t, err, errShareLinksDisabled)
+}
+
+func TestOpenShareLink_RejectsInvalidToken(t *testing.T) {
+	tests := []struct {
+		name    string
+		elapsed time.Duration
+		token   func(token string) string
+	}{
+		{name: "expired", elapsed: time.Hour, token: func(tok string) string { return tok }},
+		{name: "tampered", token: func(tok string) string {
+			if tok[0] == 'A' {
+				return "B" + tok[1:]
+			}
+			return "A" + tok[1:]
+		}},
+		{name: "garbage", token: func(string) string { return "nope" }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clock := &manualClock{now: testNow}
+			svc, _, pollID, _ := newShareTestService(clock)
+
+			link, err := svc.SharePoll(context.Background(), pollID, "")
+			require.NoError(t, err)
+
+			clock.Advance(tt.elapsed)
+			shared, err := svc.OpenShareLink(context.Background(), tt.token(link.Token), "voter-1")
+			assert.EqualError(t, err, "share link is invalid or has expired")
+			assert.Nil(t, shared)
+		})
+	}
+}
+
+func TestCastVote_AttributesShareCampaign(t *testing.T) {
+	tests := []struct {
+		name         string
+		token        func(svc *PollService, pollID uuid.UUID) string
+		wantCampaign string
+	}{
+		{
+			name: "campaign link",
+			token: func(svc *PollService, pollID uuid.UUID) string {
+				link, _ := svc.SharePoll(context.Background(), pollID, "newsletter")
+				return link.Token
+			},
+			wantCampaign: "newsletter",
+		},
+		{
+			name: "link without campaign",
+			token: func(svc *PollService, pollID uuid.UUID) string {
+				link, _ := svc.SharePoll(context.Background(), pollID, "")
+				return link.Token
+			},
+		},
+		{
+			name:  "invalid token is ignored",
+			token: func(*PollService, uuid.UUID) string { return "nope" },
+		},
+		{
+			name: "token for another poll is ignored",
+			token: func(svc *PollService, _ uuid.UUID) string {
+				other := uuid.New()
+				svc.repo.(*mocks.MockPollRepository).On("GetPollByID", mock.Anything, other).Return(&models.Poll{ID: other, IsActive: true}, nil)
+				link, _ := svc.SharePoll(context.Background(), other, "newsletter")
+				return link.Token
+			},
+		},
+		{
+			name:  "no token",
+			token: func(*PollService, uuid.UUID) string { return "" },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, repo, pollID, optionID := newShareTestService(fixedClock{now: testNow})
+			token := tt.token(svc, pollID)
+
+			var recorded *models.Vote
+			repo.On("CastVote", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+				recorded = args.Get(1).(*models.Vote)
+			}).Return(nil)
+
+			_, _, err := svc.CastVote(context.Background(), pollID, optionID, "voter-1", 0, token)
+			require.NoError(t, err)
+			require.NotNil(t, recorded)
+
+			if tt.wantCampaign == "" {
+				assert.Nil(t, recorded.Campaign)
+			} else {
+				require.NotNil(t, recorded.Campaign)
+				assert.Equal(t, tt.wantCampaign, *recorded.Campaign)
+			}
+		})
+	}
+}
@@ -0,0 +1,57 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/moabdelazem/k8s-app/internal/models"
+)
+
+// Thresholds used by the suspicious vote report when none are configured
+const (
+	DefaultSuspiciousSubnetMinVotes int64 = 10
+	DefaultSuspiciousBurstWindow          = time.Minute
+	DefaultSuspiciousBurstMinVotes  int64 = 20
+)
+
+// MaxSuspiciousVoters is the most voter identifiers listed per flagged subnet or burst
+const MaxSuspiciousVoters = 50
+
+// GetSuspiciousVotes reports vote patterns on a poll worth a moderator's look: /24 subnets
+// casting many votes and windows receiving a burst of votes. It only reports; nothing is removed.
+func (s *PollService) GetSuspiciousVotes(ctx context.Context, pollID uuid.UUID) (*models.SuspiciousVoteReport, error) {
+	poll, err := s.repo.GetPollByID(ctx, pollID)
+	if err != nil {
+		return nil, wrapRepoError("failed to get poll", err)
+	}
+	if poll == nil {
+		return nil, ErrPollNotFound
+	}
+
+	clusters, err := s.repo.ListSubnetClusters(ctx, pollID, s.cfg.SuspiciousSubnetMinVotes, MaxSuspiciousVoters)
+	if err != nil {
+		return nil, wrapRepoError("failed to list subnet clusters", err)
+	}
+	bursts, err := s.repo.ListVoteBursts(ctx, pollID, s.cfg.SuspiciousBurstWindow, s.cfg.SuspiciousBurstMinVotes, MaxSuspiciousVoters)
+	if err != nil {
+		return nil, wrapRepoError("failed to list vote bursts", err)
+	}
+	if clusters == nil {
+		clusters = []models.SubnetCluster{}
+	}
+	if bursts == nil {
+		bursts = []models.VoteBurst{}
+	}
+
+	return &models.SuspiciousVoteReport{
+		PollID: pollID,
+		Thresholds: models.SuspicionThresholds{
+			SubnetMinVotes: s.cfg.SuspiciousSubnetMinVotes,
+			BurstWindow:    s.cfg.SuspiciousBurstWindow.String(),
+			BurstMinVotes:  s.cfg.SuspiciousBurstMinVotes,
+		},
+		SubnetClusters: clusters,
+		Bursts:         bursts,
+	}, nil
+}
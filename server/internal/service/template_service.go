@@ -0,0 +1,159 @@
+package service
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/moabdelazem/k8s-app/internal/models"
+	"github.com/moabdelazem/k8s-app/internal/repository"
+	"github.com/moabdelazem/k8s-app/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// maxTemplateNameLength matches the poll_templates.name column
+const maxTemplateNameLength = 100
+
+type TemplateService struct {
+	repo  repository.TemplateRepositoryInterface
+	polls *PollService
+}
+
+// NewTemplateService creates a TemplateService instantiating templates through polls,
+// so template polls follow the same creation rules as any other poll
+func NewTemplateService(repo repository.TemplateRepositoryInterface, polls *PollService) *TemplateService {
+	return &TemplateService{repo: repo, polls: polls}
+}
+
+// CreateTemplate validates and stores a poll template
+func (s *TemplateService) CreateTemplate(ctx context.Context, req *models.TemplateRequest) (*models.PollTemplate, error) {
+	if err := validateTemplate(req); err != nil {
+		return nil, err
+	}
+
+	template := &models.PollTemplate{
+		Name:        req.Name,
+		Question:    req.Question,
+		Description: req.Description,
+		Options:     req.Options,
+	}
+
+	if err := s.repo.CreateTemplate(ctx, template); err != nil {
+		logger.Error("Failed to create template", zap.Error(err))
+		return nil, wrapRepoError("failed to create template", err)
+	}
+
+	logger.Info("Template created",
+		zap.String("template_id", template.ID.String()),
+		zap.String("name", template.Name),
+	)
+
+	return template, nil
+}
+
+// GetTemplate retrieves a poll template
+func (s *TemplateService) GetTemplate(ctx context.Context, id uuid.UUID) (*models.PollTemplate, error) {
+	template, err := s.repo.GetTemplate(ctx, id)
+	if err != nil {
+		return nil, wrapRepoError("failed to get template", err)
+	}
+	if template == nil {
+		return nil, ErrTemplateNotFound
+	}
+	return template, nil
+}
+
+// ListTemplates lists every poll template
+func (s *TemplateService) ListTemplates(ctx context.Context) ([]models.PollTemplate, error) {
+	templates, err := s.repo.ListTemplates(ctx)
+	if err != nil {
+		return nil, wrapRepoError("failed to list templates", err)
+	}
+	return templates, nil
+}
+
+// UpdateTemplate validates and replaces the contents of a poll template
+// Polls already created from the template keep their question and options
+func (s *TemplateService) UpdateTemplate(ctx context.Context, id uuid.UUID, req *models.TemplateRequest) (*models.PollTemplate, error) {
+	if err := validateTemplate(req); err != nil {
+		return nil, err
+	}
+
+	template := &models.PollTemplate{
+		ID:          id,
+		Name:        req.Name,
+		Question:    req.Question,
+		Description: req.Description,
+		Options:     req.Options,
+	}
+
+	updated, err := s.repo.UpdateTemplate(ctx, template)
+	if err != nil {
+		logger.Error("Failed to update template",
+			zap.Error(err),
+			zap.String("template_id", id.String()),
+		)
+		return nil, wrapRepoError("failed to update template", err)
+	}
+	if !updated {
+		return nil, ErrTemplateNotFound
+	}
+
+	logger.Info("Template updated",
+		zap.String("template_id", id.String()),
+	)
+
+	return template, nil
+}
+
+// DeleteTemplate removes a poll template
+func (s *TemplateService) DeleteTemplate(ctx context.Context, id uuid.UUID) error {
+	deleted, err := s.repo.DeleteTemplate(ctx, id)
+	if err != nil {
+		return wrapRepoError("failed to delete template", err)
+	}
+	if !deleted {
+		return ErrTemplateNotFound
+	}
+
+	logger.Info("Template deleted",
+		zap.String("template_id", id.String()),
+	)
+
+	return nil
+}
+
+// Instantiate creates a new live poll from a template's question and options
+// ownerID identifies the creator of the poll, as for PollService.CreatePoll
+func (s *TemplateService) Instantiate(ctx context.Context, id uuid.UUID, ownerID string) (*models.PollWithOptions, []string, error) {
+	template, err := s.GetTemplate(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	poll, warnings, err := s.polls.CreatePoll(ctx, &models.CreatePollRequest{
+		Question:    template.Question,
+		Description: template.Description,
+		Options:     template.Options,
+	}, ownerID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	logger.Info("Poll created from template",
+		zap.String("template_id", id.String()),
+		zap.String("poll_id", poll.ID.String()),
+	)
+
+	return poll, warnings, nil
+}
+
+// validateTemplate applies the poll creation rules to a template, plus a name
+func validateTemplate(req *models.TemplateRequest) error {
+	if len(req.Name) < 1 || len(req.Name) > maxTemplateNameLength {
+		return newValidationError(CodeTemplateNameLength, "template name must be between 1 and %d characters", maxTemplateNameLength)
+	}
+	if err := validateQuestion(req.Question); err != nil {
+		return err
+	}
+	return validateOptionTexts(req.Options, false)
+}
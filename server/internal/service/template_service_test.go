@@ -0,0 +1,108 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/moabdelazem/k8s-app/internal/mocks"
+	"github.com/moabdelazem/k8s-app/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInstantiate_CreatesPollFromTemplate(t *testing.T) {
+	description := "Quarterly planning"
+	template := &models.PollTemplate{
+		ID:          uuid.New(),
+		Name:        "Roadmap",
+		Question:    "What should we build next?",
+		Description: &description,
+		Options:     []string{"Feature A", "Feature B", "Feature C"},
+	}
+
+	templates := new(mocks.MockTemplateRepository)
+	templates.On("GetTemplate", mock.Anything, template.ID).Return(template, nil)
+	polls := new(mocks.MockPollRepository)
+	polls.On("CreatePoll", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	svc := NewTemplateService(templates, NewPollService(polls, PollServiceConfig{Clock: fixedClock{now: testNow}}))
+	poll, _, err := svc.Instantiate(context.Background(), template.ID, "owner-1")
+
+	require.NoError(t, err)
+	assert.Equal(t, template.Question, poll.Question)
+	assert.Equal(t, &description, poll.Description)
+	assert.True(t, poll.IsActive)
+	assert.Nil(t, poll.ExpiresAt)
+	require.NotNil(t, poll.OwnerID)
+	assert.Equal(t, "owner-1", *poll.OwnerID)
+	require.Len(t, poll.Options, 3)
+	for i, option := range poll.Options {
+		assert.Equal(t, template.Options[i], option.OptionText)
+		assert.Equal(t, poll.ID, option.PollID)
+	}
+	polls.AssertExpectations(t)
+}
+
+func TestInstantiate_TemplateNotFound(t *testing.T) {
+	templates := new(mocks.MockTemplateRepository)
+	templates.On("GetTemplate", mock.Anything, mock.Anything).Return(nil, nil)
+	polls := new(mocks.MockPollRepository)
+
+	svc := NewTemplateService(templates, NewPollService(polls, PollServiceConfig{}))
+	poll, _, err := svc.Instantiate(context.Background(), uuid.New(), "owner-1")
+
+	require.ErrorIs(t, err, ErrTemplateNotFound)
+	assert.Nil(t, poll)
+	polls.AssertNotCalled(t, "CreatePoll", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestInstantiate_AppliesPollCreationRules(t *testing.T) {
+	template := &models.PollTemplate{
+		ID:       uuid.New(),
+		Name:     "Roadmap",
+		Question: "What should we build next?",
+		Options:  []string{"Feature A", "Feature B"},
+	}
+
+	templates := new(mocks.MockTemplateRepository)
+	templates.On("GetTemplate", mock.Anything, template.ID).Return(template, nil)
+	polls := new(mocks.MockPollRepository)
+	polls.On("CountActivePollsByOwner", mock.Anything, "owner-1").Return(int64(1), nil)
+
+	svc := NewTemplateService(templates, NewPollService(polls, PollServiceConfig{MaxActivePollsPerOwner: 1}))
+	_, _, err := svc.Instantiate(context.Background(), template.ID, "owner-1")
+
+	require.ErrorIs(t, err, ErrActivePollLimitReached)
+	polls.AssertNotCalled(t, "CreatePoll", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestCreateTemplate_Validation(t *testing.T) {
+	tests := []struct {
+		name     string
+		req      models.TemplateRequest
+		wantCode string
+	}{
+		{name: "empty name", req: models.TemplateRequest{Question: "What should we build next?", Options: []string{"A", "B"}}, wantCode: CodeTemplateNameLength},
+		{name: "name too long", req: models.TemplateRequest{Name: strings.Repeat("n", 101), Question: "What should we build next?", Options: []string{"A", "B"}}, wantCode: CodeTemplateNameLength},
+		{name: "short question", req: models.TemplateRequest{Name: "Roadmap", Question: "Why", Options: []string{"A", "B"}}, wantCode: CodeQuestionLength},
+		{name: "too few options", req: models.TemplateRequest{Name: "Roadmap", Question: "What should we build next?", Options: []string{"A"}}, wantCode: CodeTooFewOptions},
+		{name: "empty option", req: models.TemplateRequest{Name: "Roadmap", Question: "What should we build next?", Options: []string{"A", ""}}, wantCode: CodeOptionLength},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(mocks.MockTemplateRepository)
+			svc := NewTemplateService(repo, nil)
+
+			_, err := svc.CreateTemplate(context.Background(), &tt.req)
+
+			var validationErr *ValidationError
+			require.ErrorAs(t, err, &validationErr)
+			assert.Equal(t, tt.wantCode, validationErr.Code)
+			repo.AssertNotCalled(t, "CreateTemplate", mock.Anything, mock.Anything)
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package service
+
+import (
+	"errors"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// requestValidator checks the validate tags of request models; it caches each struct's rules, so there is one
+var requestValidator = newRequestValidator()
+
+// newRequestValidator returns a validator naming fields as clients send them, by their JSON names
+func newRequestValidator() *validator.Validate {
+	v := validator.New(validator.WithRequiredStructEnabled())
+	v.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			return ""
+		}
+		return name
+	})
+	return v
+}
+
+// ValidateRequest checks a decoded request model against its validate tags.
+// The first violation is returned as a ValidationError carrying the code the service has always used
+// for that field, so clients and translations see the same errors as before the tags existed.
+// Rules that depend on configuration or stored polls stay in the service methods.
+func ValidateRequest(req any) error {
+	return fieldError(requestValidator.Struct(req))
+}
+
+// validateFields checks only the named fields of req, for input that is not a whole request,
+// such as template option lists and restored polls
+func validateFields(req any, fields ...string) error {
+	return fieldError(requestValidator.StructPartial(req, fields...))
+}
+
+// fieldError converts the first of a validator's field errors to a coded ValidationError
+// Errors that are not field errors, such as a nil request, are returned as they are.
+func fieldError(err error) error {
+	var fieldErrs validator.ValidationErrors
+	if !errors.As(err, &fieldErrs) || len(fieldErrs) == 0 {
+		return err
+	}
+	fe := fieldErrs[0]
+	field, index := splitIndex(fe.StructField())
+
+	switch field {
+	case "Question":
+		return newValidationError(CodeQuestionLength, "question must be between 5 and 500 characters")
+	case "Options":
+		if index >= 0 {
+			return newValidationError(CodeOptionLength, "option %d must be between 1 and 200 characters", index+1)
+		}
+		return newValidationError(CodeTooManyOptions, "poll can have at most 10 options")
+	case "CorrectOptions":
+		return newValidationError(CodeInvalidCorrectOption, "correct option %d does not exist", fe.Value())
+	case "MaxVotes":
+		return newValidationError(CodeMaxVotesInvalid, "max votes must be at least 1")
+	case "OptionID":
+		if fe.Tag() == "excluded_with" {
+			return newValidationError(CodeVoteChoiceConflict, "give either option_id or write_in, not both")
+		}
+		return newValidationError(CodeVoteChoiceRequired, "option_id or write_in is required")
+	}
+	return newValidationError(CodeInvalidField, "%s does not satisfy %s", fe.Field(), ruleName(fe))
+}
+
+// splitIndex splits a list element's field name, e.g. "Options[2]", into the field and its index;
+// the index is -1 for the field itself
+func splitIndex(name string) (string, int) {
+	field, rest, ok := strings.Cut(name, "[")
+	if !ok {
+		return name, -1
+	}
+	index, err := strconv.Atoi(strings.TrimSuffix(rest, "]"))
+	if err != nil {
+		return field, -1
+	}
+	return field, index
+}
+
+// ruleName renders a failed rule with its parameter, e.g. "max=10"
+func ruleName(fe validator.FieldError) string {
+	if fe.Param() == "" {
+		return fe.Tag()
+	}
+	return fe.Tag() + "=" + fe.Param()
+}
@@ -0,0 +1,92 @@
+package service
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/moabdelazem/k8s-app/internal/mocks"
+	"github.com/moabdelazem/k8s-app/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateRequest(t *testing.T) {
+	valid := func() *models.CreatePollRequest {
+		return &models.CreatePollRequest{Question: "Tabs or spaces?", Options: []string{"Tabs", "Spaces"}}
+	}
+	zero, negative := int64(0), int64(-3)
+
+	tests := []struct {
+		name     string
+		req      any
+		wantCode string
+		wantMsg  string
+		wantArgs []any
+	}{
+		{name: "valid poll", req: valid()},
+		{name: "question too short", req: &models.CreatePollRequest{Question: "Why", Options: []string{"A", "B"}},
+			wantCode: CodeQuestionLength, wantMsg: "question must be between 5 and 500 characters"},
+		{name: "question too long", req: &models.CreatePollRequest{Question: strings.Repeat("q", 501), Options: []string{"A", "B"}},
+			wantCode: CodeQuestionLength, wantMsg: "question must be between 5 and 500 characters"},
+		{name: "too many options", req: &models.CreatePollRequest{Question: "Pick a number", Options: strings.Split("1,2,3,4,5,6,7,8,9,10,11", ",")},
+			wantCode: CodeTooManyOptions, wantMsg: "poll can have at most 10 options"},
+		{name: "empty option names its position", req: &models.CreatePollRequest{Question: "Tabs or spaces?", Options: []string{"Tabs", "Spaces", ""}},
+			wantCode: CodeOptionLength, wantMsg: "option 3 must be between 1 and 200 characters", wantArgs: []any{3}},
+		{name: "option too long", req: &models.CreatePollRequest{Question: "Tabs or spaces?", Options: []string{strings.Repeat("o", 201), "Spaces"}},
+			wantCode: CodeOptionLength, wantMsg: "option 1 must be between 1 and 200 characters", wantArgs: []any{1}},
+		{name: "negative correct option", req: &models.CreatePollRequest{Question: "Tabs or spaces?", Options: []string{"Tabs", "Spaces"}, CorrectOptions: []int{-1}},
+			wantCode: CodeInvalidCorrectOption, wantMsg: "correct option -1 does not exist", wantArgs: []any{-1}},
+		{name: "zero max votes", req: &models.CreatePollRequest{Question: "Tabs or spaces?", Options: []string{"Tabs", "Spaces"}, MaxVotes: &zero},
+			wantCode: CodeMaxVotesInvalid, wantMsg: "max votes must be at least 1"},
+		{name: "negative max votes", req: &models.CreatePollRequest{Question: "Tabs or spaces?", Options: []string{"Tabs", "Spaces"}, MaxVotes: &negative},
+			wantCode: CodeMaxVotesInvalid, wantMsg: "max votes must be at least 1"},
+		{name: "vote for an option", req: &models.VoteRequest{OptionID: uuid.New()}},
+		{name: "write-in vote", req: &models.VoteRequest{WriteIn: "Something else"}},
+		{name: "vote without a choice", req: &models.VoteRequest{},
+			wantCode: CodeVoteChoiceRequired, wantMsg: "option_id or write_in is required"},
+		{name: "vote with both choices", req: &models.VoteRequest{OptionID: uuid.New(), WriteIn: "Something else"},
+			wantCode: CodeVoteChoiceConflict, wantMsg: "give either option_id or write_in, not both"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateRequest(tt.req)
+			if tt.wantCode == "" {
+				assert.NoError(t, err)
+				return
+			}
+			var validationErr *ValidationError
+			require.ErrorAs(t, err, &validationErr)
+			assert.Equal(t, tt.wantCode, validationErr.Code)
+			assert.Equal(t, tt.wantMsg, validationErr.Message)
+			if tt.wantArgs != nil {
+				assert.Equal(t, tt.wantArgs, validationErr.Args, "args let the message be localized")
+			}
+		})
+	}
+}
+
+func TestValidateRequest_UncodedRuleNamesField(t *testing.T) {
+	type request struct {
+		Campaign string `json:"campaign" validate:"max=3"`
+	}
+
+	err := ValidateRequest(&request{Campaign: "spring"})
+	var validationErr *ValidationError
+	require.ErrorAs(t, err, &validationErr)
+	assert.Equal(t, CodeInvalidField, validationErr.Code)
+	assert.Equal(t, "campaign does not satisfy max=3", validationErr.Message)
+}
+
+func TestCreatePoll_TagValidationRunsBeforeRepository(t *testing.T) {
+	repo := new(mocks.MockPollRepository)
+	svc := NewPollService(repo, PollServiceConfig{})
+
+	_, _, err := svc.CreatePoll(t.Context(), &models.CreatePollRequest{Question: "Tabs or spaces?", Options: []string{"Tabs", ""}}, "owner-1")
+
+	var validationErr *ValidationError
+	require.ErrorAs(t, err, &validationErr)
+	assert.Equal(t, CodeOptionLength, validationErr.Code)
+	repo.AssertNotCalled(t, "CreatePoll")
+}
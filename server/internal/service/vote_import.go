@@ -0,0 +1,145 @@
+package service
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/moabdelazem/k8s-app/internal/models"
+	"github.com/moabdelazem/k8s-app/internal/repository"
+	"github.com/moabdelazem/k8s-app/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// voteImportColumns are the CSV columns required by ImportVotes; their order may vary
+var voteImportColumns = []string{"option_id", "voter_identifier", "voted_at"}
+
+// ImportVotes loads historical votes for a poll from CSV.
+// The first row must be a header naming the option_id, voter_identifier and voted_at (RFC 3339) columns.
+// Rows are parsed one at a time as they are inserted, so large files are never held in memory.
+// Any invalid row aborts the whole import, as do votes taking the poll past its max_votes;
+// rows for voters who already voted are skipped.
+func (s *PollService) ImportVotes(ctx context.Context, pollID uuid.UUID, r io.Reader) (*models.VoteImportSummary, error) {
+	poll, err := s.repo.GetPollByID(ctx, pollID)
+	if err != nil {
+		return nil, wrapRepoError("failed to get poll", err)
+	}
+	if poll == nil {
+		return nil, ErrPollNotFound
+	}
+
+	options, err := s.repo.GetPollOptions(ctx, pollID)
+	if err != nil {
+		return nil, wrapRepoError("failed to get options", err)
+	}
+	validOptions := make(map[uuid.UUID]bool, len(options))
+	for _, opt := range options {
+		validOptions[opt.ID] = true
+	}
+
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+	reader.ReuseRecord = true
+
+	columns, err := readVoteImportHeader(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	next := func() (*models.Vote, error) {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return nil, io.EOF
+		}
+		if err != nil {
+			return nil, validationErrorf("invalid CSV: %v", err)
+		}
+		line, _ := reader.FieldPos(0)
+
+		optionID, err := uuid.Parse(strings.TrimSpace(record[columns["option_id"]]))
+		if err != nil {
+			return nil, validationErrorf("line %d: invalid option_id", line)
+		}
+		if !validOptions[optionID] {
+			return nil, validationErrorf("line %d: option %s does not belong to this poll", line, optionID)
+		}
+
+		voterIdentifier := strings.TrimSpace(record[columns["voter_identifier"]])
+		if voterIdentifier == "" || len(voterIdentifier) > 255 {
+			return nil, validationErrorf("line %d: voter_identifier must be between 1 and 255 characters", line)
+		}
+
+		votedAt, err := time.Parse(time.RFC3339, strings.TrimSpace(record[columns["voted_at"]]))
+		if err != nil {
+			return nil, validationErrorf("line %d: voted_at must be an RFC 3339 timestamp", line)
+		}
+
+		return &models.Vote{
+			PollID:          pollID,
+			OptionID:        optionID,
+			VoterIdentifier: voterIdentifier,
+			Weight:          1,
+			VotedAt:         votedAt,
+		}, nil
+	}
+
+	imported, skipped, err := s.repo.ImportVotes(ctx, next)
+	if err != nil {
+		var validationErr *ValidationError
+		if errors.As(err, &validationErr) {
+			return nil, validationErr
+		}
+		if errors.Is(err, repository.ErrPollFull) {
+			return nil, newValidationError(CodePollFull, "poll is full")
+		}
+		logger.Error("Failed to import votes",
+			zap.Error(err),
+			zap.String("poll_id", pollID.String()),
+		)
+		return nil, wrapRepoError("failed to import votes", err)
+	}
+	s.invalidateResults(pollID)
+
+	logger.Info("Votes imported",
+		zap.String("poll_id", pollID.String()),
+		zap.Int64("imported", imported),
+		zap.Int64("skipped", skipped),
+	)
+
+	return &models.VoteImportSummary{
+		PollID:   pollID,
+		Imported: imported,
+		Skipped:  skipped,
+	}, nil
+}
+
+// readVoteImportHeader reads the header row and maps each column to its index
+// csv.Reader then requires every following row to have as many fields as the header
+func readVoteImportHeader(reader *csv.Reader) (map[string]int, error) {
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, validationErrorf("CSV is empty")
+	}
+	if err != nil {
+		return nil, validationErrorf("invalid CSV: %v", err)
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		// Tolerate a UTF-8 byte order mark written by spreadsheet exports
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		columns[name] = i
+	}
+
+	for _, required := range voteImportColumns {
+		if _, ok := columns[required]; !ok {
+			return nil, validationErrorf("CSV header must include %s", strings.Join(voteImportColumns, ", "))
+		}
+	}
+
+	return columns, nil
+}
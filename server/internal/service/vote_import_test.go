@@ -0,0 +1,126 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/moabdelazem/k8s-app/internal/mocks"
+	"github.com/moabdelazem/k8s-app/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// newImportTestRepo returns a repository mock holding a poll with two options
+func newImportTestRepo(pollID uuid.UUID) (*mocks.MockPollRepository, []models.PollOption) {
+	options := []models.PollOption{
+		{ID: uuid.New(), PollID: pollID, OptionText: "Yes"},
+		{ID: uuid.New(), PollID: pollID, OptionText: "No"},
+	}
+
+	repo := new(mocks.MockPollRepository)
+	repo.On("GetPollByID", mock.Anything, pollID).Return(&models.Poll{ID: pollID}, nil)
+	repo.On("GetPollOptions", mock.Anything, pollID).Return(options, nil)
+	return repo, options
+}
+
+func TestImportVotes(t *testing.T) {
+	pollID := uuid.New()
+	repo, options := newImportTestRepo(pollID)
+
+	// Columns may appear in any order
+	csv := "voter_identifier,option_id,voted_at\n" +
+		"alice," + options[0].ID.String() + ",2024-01-02T10:00:00Z\n" +
+		"bob," + options[1].ID.String() + ",2024-01-02T11:30:00+01:00\n"
+
+	repo.On("ImportVotes", mock.Anything, mock.MatchedBy(func(votes []models.Vote) bool {
+		return len(votes) == 2 &&
+			votes[0].PollID == pollID &&
+			votes[0].OptionID == options[0].ID &&
+			votes[0].VoterIdentifier == "alice" &&
+			votes[0].VotedAt.Equal(time.Date(2024, 1, 2, 10, 0, 0, 0, time.UTC)) &&
+			votes[1].VoterIdentifier == "bob" &&
+			votes[1].VotedAt.Equal(time.Date(2024, 1, 2, 10, 30, 0, 0, time.UTC))
+	})).Return(int64(1), int64(1), nil)
+
+	svc := NewPollService(repo, PollServiceConfig{})
+	summary, err := svc.ImportVotes(context.Background(), pollID, strings.NewReader(csv))
+
+	require.NoError(t, err)
+	assert.Equal(t, pollID, summary.PollID)
+	assert.Equal(t, int64(1), summary.Imported)
+	assert.Equal(t, int64(1), summary.Skipped)
+	repo.AssertExpectations(t)
+}
+
+func TestImportVotes_InvalidInput(t *testing.T) {
+	validRow := func(optionID uuid.UUID) string {
+		return optionID.String() + ",alice,2024-01-02T10:00:00Z\n"
+	}
+	header := "option_id,voter_identifier,voted_at\n"
+
+	tests := []struct {
+		name    string
+		csv     func(options []models.PollOption) string
+		wantErr string
+	}{
+		{
+			name:    "empty body",
+			csv:     func([]models.PollOption) string { return "" },
+			wantErr: "CSV is empty",
+		},
+		{
+			name:    "missing column",
+			csv:     func([]models.PollOption) string { return "option_id,voted_at\n" },
+			wantErr: "CSV header must include option_id, voter_identifier, voted_at",
+		},
+		{
+			name:    "option from another poll",
+			csv:     func(options []models.PollOption) string { return header + validRow(options[0].ID) + validRow(uuid.Nil) },
+			wantErr: "line 3: option 00000000-0000-0000-0000-000000000000 does not belong to this poll",
+		},
+		{
+			name: "bad timestamp",
+			csv: func(options []models.PollOption) string {
+				return header + options[0].ID.String() + ",alice,yesterday\n"
+			},
+			wantErr: "line 2: voted_at must be an RFC 3339 timestamp",
+		},
+		{
+			name: "missing voter",
+			csv: func(options []models.PollOption) string {
+				return header + options[0].ID.String() + ",,2024-01-02T10:00:00Z\n"
+			},
+			wantErr: "line 2: voter_identifier must be between 1 and 255 characters",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pollID := uuid.New()
+			repo, options := newImportTestRepo(pollID)
+
+			svc := NewPollService(repo, PollServiceConfig{})
+			_, err := svc.ImportVotes(context.Background(), pollID, strings.NewReader(tt.csv(options)))
+
+			var validationErr *ValidationError
+			require.ErrorAs(t, err, &validationErr)
+			assert.Equal(t, tt.wantErr, validationErr.Message)
+			repo.AssertNotCalled(t, "ImportVotes", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestImportVotes_PollNotFound(t *testing.T) {
+	pollID := uuid.New()
+	repo := new(mocks.MockPollRepository)
+	repo.On("GetPollByID", mock.Anything, pollID).Return(nil, nil)
+
+	svc := NewPollService(repo, PollServiceConfig{})
+	_, err := svc.ImportVotes(context.Background(), pollID, strings.NewReader(""))
+
+	assert.ErrorIs(t, err, ErrPollNotFound)
+}
@@ -0,0 +1,47 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/moabdelazem/k8s-app/internal/models"
+)
+
+// Vote rate report limits; the bucket defaults to a second, giving the peak votes per second
+const (
+	DefaultVoteRateBucket = time.Second
+	MinVoteRateBucket     = time.Second
+)
+
+// GetPeakVoteRate reports the busiest bucket of a poll's votes over its lifetime, so the database can be
+// sized for upcoming big polls. The bucket is clamped to [MinVoteRateBucket, MaxTimelineBucket].
+func (s *PollService) GetPeakVoteRate(ctx context.Context, pollID uuid.UUID, bucket time.Duration) (*models.VoteRateReport, error) {
+	if bucket <= 0 {
+		return nil, newValidationError(CodeInvalidBucket, "bucket must be a positive duration")
+	}
+	bucket = min(max(bucket, MinVoteRateBucket), MaxTimelineBucket).Truncate(time.Second)
+
+	poll, err := s.repo.GetPollByID(ctx, pollID)
+	if err != nil {
+		return nil, wrapRepoError("failed to get poll", err)
+	}
+	if poll == nil {
+		return nil, ErrPollNotFound
+	}
+
+	peak, err := s.repo.GetPeakVoteRate(ctx, pollID, bucket)
+	if err != nil {
+		return nil, wrapRepoError("failed to get peak vote rate", err)
+	}
+
+	report := &models.VoteRateReport{
+		PollID: pollID,
+		Bucket: bucket.String(),
+		Peak:   peak,
+	}
+	if peak != nil {
+		report.PeakVotesPerSecond = float64(peak.Votes) / bucket.Seconds()
+	}
+	return report, nil
+}
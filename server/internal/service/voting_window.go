@@ -0,0 +1,117 @@
+package service
+
+import (
+	"time"
+	// Embeds the zone database: the runtime image ships without one
+	_ "time/tzdata"
+
+	"github.com/moabdelazem/k8s-app/internal/models"
+)
+
+// votingWindowLayout is the time-of-day format of voting window bounds
+const votingWindowLayout = "15:04"
+
+// errVotingWindowInvalid reports a voting window that is incomplete or malformed
+var errVotingWindowInvalid = newValidationError(CodeVotingWindowInvalid, "voting window must have a start and an end as different HH:MM times")
+
+// validateVotingWindow checks the optional voting window and timezone of a new poll
+// Both bounds must be given together and differ; an end before the start is a window
+// that runs past midnight. A timezone without a window would have no effect.
+func validateVotingWindow(start, end, timezone *string) error {
+	if (start == nil) != (end == nil) {
+		return errVotingWindowInvalid
+	}
+	if start == nil {
+		if timezone != nil {
+			return errVotingWindowInvalid
+		}
+		return nil
+	}
+	startMinute, ok := parseTimeOfDay(*start)
+	if !ok {
+		return errVotingWindowInvalid
+	}
+	endMinute, ok := parseTimeOfDay(*end)
+	if !ok {
+		return errVotingWindowInvalid
+	}
+	if startMinute == endMinute {
+		return errVotingWindowInvalid
+	}
+	if timezone != nil {
+		if _, err := time.LoadLocation(*timezone); err != nil || *timezone == "" || *timezone == "Local" {
+			return newValidationError(CodeTimezoneInvalid, "unknown timezone %q", *timezone)
+		}
+	}
+	return nil
+}
+
+// checkVotingWindow rejects a vote cast at now outside the poll's voting window
+func checkVotingWindow(poll *models.Poll, now time.Time) error {
+	if open, _ := votingWindowOpen(poll, now); !open {
+		return errOutsideVotingWindow(poll)
+	}
+	return nil
+}
+
+// errOutsideVotingWindow is the error votes cast outside the poll's voting window fail with
+func errOutsideVotingWindow(poll *models.Poll) error {
+	return newValidationError(CodeOutsideVotingWindow, "poll only accepts votes between %s and %s (%s)",
+		*poll.VotingWindowStart, *poll.VotingWindowEnd, windowZone(poll))
+}
+
+// votingWindowOpen reports whether now falls within the poll's voting window and, when it
+// does not, when the window next opens. Polls without a window are always open.
+func votingWindowOpen(poll *models.Poll, now time.Time) (bool, time.Time) {
+	if poll.VotingWindowStart == nil || poll.VotingWindowEnd == nil {
+		return true, time.Time{}
+	}
+	startMinute, okStart := parseTimeOfDay(*poll.VotingWindowStart)
+	endMinute, okEnd := parseTimeOfDay(*poll.VotingWindowEnd)
+	if !okStart || !okEnd {
+		return true, time.Time{}
+	}
+	location, err := time.LoadLocation(windowZone(poll))
+	if err != nil {
+		location = time.UTC
+	}
+
+	local := now.In(location)
+	minute := local.Hour()*60 + local.Minute()
+	var open bool
+	if startMinute < endMinute {
+		open = minute >= startMinute && minute < endMinute
+	} else {
+		open = minute >= startMinute || minute < endMinute
+	}
+	if open {
+		return true, time.Time{}
+	}
+
+	// Closed, so the window opens later today or, past today's end, tomorrow
+	opensAt := time.Date(local.Year(), local.Month(), local.Day(), startMinute/60, startMinute%60, 0, 0, location)
+	if !opensAt.After(local) {
+		opensAt = opensAt.AddDate(0, 0, 1)
+	}
+	return false, opensAt.UTC()
+}
+
+// windowZone is the name of the timezone the poll's voting window is in
+func windowZone(poll *models.Poll) string {
+	if poll.Timezone != nil {
+		return *poll.Timezone
+	}
+	return "UTC"
+}
+
+// parseTimeOfDay returns the minute of the day of an HH:MM time
+func parseTimeOfDay(value string) (int, bool) {
+	if len(value) != len(votingWindowLayout) {
+		return 0, false
+	}
+	t, err := time.Parse(votingWindowLayout, value)
+	if err != nil {
+		return 0, false
+	}
+	return t.Hour()*60 + t.Minute(), true
+}
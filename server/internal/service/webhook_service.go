@@ -0,0 +1,104 @@
+package service
+
+import (
+	"context"
+	"net/url"
+	"slices"
+
+	"github.com/google/uuid"
+	"github.com/moabdelazem/k8s-app/internal/models"
+	"github.com/moabdelazem/k8s-app/internal/repository"
+	"github.com/moabdelazem/k8s-app/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// minWebhookSecretLength is the shortest accepted HMAC signing secret
+const minWebhookSecretLength = 16
+
+type WebhookService struct {
+	repo     repository.WebhookRepositoryInterface
+	pollRepo repository.PollRepositoryInterface
+}
+
+func NewWebhookService(repo repository.WebhookRepositoryInterface, pollRepo repository.PollRepositoryInterface) *WebhookService {
+	return &WebhookService{repo: repo, pollRepo: pollRepo}
+}
+
+// RegisterWebhook validates and registers a webhook for a poll
+func (s *WebhookService) RegisterWebhook(ctx context.Context, pollID uuid.UUID, req *models.CreateWebhookRequest) (*models.Webhook, error) {
+	// Validate request
+	target, err := url.Parse(req.URL)
+	if err != nil || (target.Scheme != "http" && target.Scheme != "https") || target.Host == "" {
+		return nil, validationErrorf("url must be an absolute http or https URL")
+	}
+
+	if len(req.Secret) < minWebhookSecretLength {
+		return nil, validationErrorf("secret must be at least %d characters", minWebhookSecretLength)
+	}
+
+	events := req.Events
+	if len(events) == 0 {
+		events = models.WebhookEvents
+	}
+	for _, event := range events {
+		if !slices.Contains(models.WebhookEvents, event) {
+			return nil, validationErrorf("unsupported event %q", event)
+		}
+	}
+
+	// Make sure the poll exists
+	poll, err := s.pollRepo.GetPollByID(ctx, pollID)
+	if err != nil {
+		return nil, wrapRepoError("failed to get poll", err)
+	}
+	if poll == nil {
+		return nil, ErrPollNotFound
+	}
+
+	webhook := &models.Webhook{
+		PollID: pollID,
+		URL:    target.String(),
+		Events: events,
+		Secret: req.Secret,
+	}
+
+	if err := s.repo.CreateWebhook(ctx, webhook); err != nil {
+		logger.Error("Failed to register webhook", zap.Error(err))
+		return nil, wrapRepoError("failed to register webhook", err)
+	}
+
+	logger.Info("Webhook registered",
+		zap.String("poll_id", pollID.String()),
+		zap.String("webhook_id", webhook.ID.String()),
+		zap.Strings("events", events),
+	)
+
+	return webhook, nil
+}
+
+// ListWebhooks lists the webhooks registered for a poll
+func (s *WebhookService) ListWebhooks(ctx context.Context, pollID uuid.UUID) ([]models.Webhook, error) {
+	webhooks, err := s.repo.ListWebhooks(ctx, pollID)
+	if err != nil {
+		return nil, wrapRepoError("failed to list webhooks", err)
+	}
+	return webhooks, nil
+}
+
+// DeleteWebhook removes a webhook from a poll
+func (s *WebhookService) DeleteWebhook(ctx context.Context, pollID, webhookID uuid.UUID) error {
+	deleted, err := s.repo.DeleteWebhook(ctx, pollID, webhookID)
+	if err != nil {
+		return wrapRepoError("failed to delete webhook", err)
+	}
+	if !deleted {
+		return ErrWebhookNotFound
+	}
+
+	logger.Info("Webhook deleted",
+		zap.String("poll_id", pollID.String()),
+		zap.String("webhook_id", webhookID.String()),
+	)
+
+	return nil
+}
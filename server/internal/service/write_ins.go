@@ -0,0 +1,91 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/moabdelazem/k8s-app/internal/models"
+)
+
+// MaxWriteInLength matches the write_in_votes.write_in_text column
+const MaxWriteInLength = 200
+
+// MaxWriteInEntries caps the write-in tallies listed with a poll's results
+const MaxWriteInEntries = 100
+
+// CastWriteInVote casts a vote answering a poll with free text instead of one of its options
+// The poll must allow write-ins. The text is sanitized before it is checked and stored;
+// otherwise the vote is handled as by CastVote, including confirmation and receipts.
+func (s *PollService) CastWriteInVote(ctx context.Context, pollID uuid.UUID, text string, voterIdentifier string, weight int64, shareToken string) (*models.VoteConfirmation, *models.VoteReceipt, error) {
+	writeIn, err := sanitizeWriteIn(text)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	vote := &models.Vote{
+		PollID:          pollID,
+		VoterIdentifier: voterIdentifier,
+		Weight:          weight,
+		WriteIn:         &writeIn,
+	}
+	return s.submitVote(ctx, vote, shareToken)
+}
+
+// sanitizeWriteIn drops control and formatting characters, which could hide or reorder the
+// text others see, and collapses whitespace, so equal answers group together
+func sanitizeWriteIn(text string) (string, error) {
+	cleaned := strings.Map(func(r rune) rune {
+		if (unicode.IsControl(r) && !unicode.IsSpace(r)) || unicode.Is(unicode.Cf, r) {
+			return -1
+		}
+		return r
+	}, strings.ToValidUTF8(text, ""))
+	cleaned = strings.Join(strings.Fields(cleaned), " ")
+
+	if n := utf8.RuneCountInString(cleaned); n < 1 || n > MaxWriteInLength {
+		return "", newValidationError(CodeWriteInLength, "write-in must be between 1 and %d characters", MaxWriteInLength)
+	}
+	return cleaned, nil
+}
+
+// loadWriteIns attaches the write-in tallies of a poll allowing write-ins, one more than
+// MaxWriteInEntries so truncation shows; they are cached along with the rest of the poll
+func (s *PollService) loadWriteIns(ctx context.Context, poll *models.PollWithVote) error {
+	if !poll.AllowWriteIn {
+		return nil
+	}
+
+	tallies, err := s.repo.ListWriteIns(ctx, poll.ID, !s.cfg.ListWriteInsIndividually, MaxWriteInEntries+1)
+	if err != nil {
+		return wrapRepoError("failed to list write-ins", err)
+	}
+	poll.WriteIns = tallies
+	return nil
+}
+
+// writeInResults reports a poll's write-in votes as a pseudo-option
+// Their count is whatever the options do not account for, so it matches the total even when
+// the tallies are truncated
+func writeInResults(poll *models.PollWithVote) *models.WriteInResult {
+	count := poll.TotalVotes
+	for _, opt := range poll.Options {
+		count -= opt.VoteCount
+	}
+
+	result := &models.WriteInResult{
+		VoteCount:  count,
+		Percentage: percentage(count, poll.TotalVotes),
+		Entries:    poll.WriteIns,
+	}
+	if result.Entries == nil {
+		result.Entries = []models.WriteInTally{}
+	}
+	if len(result.Entries) > MaxWriteInEntries {
+		result.Entries = result.Entries[:MaxWriteInEntries]
+		result.Truncated = true
+	}
+	return result
+}
@@ -0,0 +1,158 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/moabdelazem/k8s-app/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeInPoll(t *testing.T, svc *PollService) *models.PollWithOptions {
+	t.Helper()
+	req := validCreateRequest()
+	req.AllowWriteIn = true
+	return createMemoryPoll(t, svc, req)
+}
+
+func TestCastWriteInVote_GroupedResults(t *testing.T) {
+	ctx := context.Background()
+	svc := newMemoryTestService(PollServiceConfig{Clock: fixedClock{now: testNow}})
+	poll := writeInPoll(t, svc)
+
+	_, _, err := svc.CastVote(ctx, poll.ID, poll.Options[0].ID, "voter-1", 0, "")
+	require.NoError(t, err)
+	for voter, text := range map[string]string{"voter-2": "Feature  C", "voter-3": "feature c", "voter-4": "Feature D"} {
+		_, _, err := svc.CastWriteInVote(ctx, poll.ID, text, voter, 0, "")
+		require.NoError(t, err)
+	}
+
+	results, err := svc.GetPollResults(ctx, poll.ID, "voter-3")
+	require.NoError(t, err)
+	assert.Equal(t, int64(4), results.TotalVotes)
+	assert.True(t, results.HasVoted)
+	assert.Nil(t, results.VotedOption)
+	require.NotNil(t, results.WriteIns)
+	assert.Equal(t, int64(3), results.WriteIns.VoteCount)
+	assert.Equal(t, 75.0, results.WriteIns.Percentage)
+	assert.False(t, results.WriteIns.Truncated)
+	require.Len(t, results.WriteIns.Entries, 2)
+	assert.Equal(t, int64(2), results.WriteIns.Entries[0].VoteCount)
+	assert.Equal(t, "feature c", strings.ToLower(results.WriteIns.Entries[0].Text), "spelling variants share a tally")
+	assert.Equal(t, models.WriteInTally{Text: "Feature D", VoteCount: 1}, results.WriteIns.Entries[1])
+}
+
+func TestCastWriteInVote_IndividualResults(t *testing.T) {
+	ctx := context.Background()
+	svc := newMemoryTestService(PollServiceConfig{Clock: fixedClock{now: testNow}, ListWriteInsIndividually: true})
+	poll := writeInPoll(t, svc)
+
+	for _, voter := range []string{"voter-1", "voter-2"} {
+		_, _, err := svc.CastWriteInVote(ctx, poll.ID, "Feature C", voter, 0, "")
+		require.NoError(t, err)
+	}
+
+	results, err := svc.GetPollResults(ctx, poll.ID, "")
+	require.NoError(t, err)
+	require.NotNil(t, results.WriteIns)
+	assert.Equal(t, []models.WriteInTally{{Text: "Feature C", VoteCount: 1}, {Text: "Feature C", VoteCount: 1}}, results.WriteIns.Entries)
+}
+
+func TestCastWriteInVote_OneVotePerVoter(t *testing.T) {
+	ctx := context.Background()
+	svc := newMemoryTestService(PollServiceConfig{})
+	poll := writeInPoll(t, svc)
+
+	_, _, err := svc.CastWriteInVote(ctx, poll.ID, "Feature C", "voter-1", 0, "")
+	require.NoError(t, err)
+	_, _, err = svc.CastWriteInVote(ctx, poll.ID, "Feature D", "voter-1", 0, "")
+	requireValidationCode(t, err, CodeAlreadyVoted)
+	_, _, err = svc.CastVote(ctx, poll.ID, poll.Options[0].ID, "voter-1", 0, "")
+	requireValidationCode(t, err, CodeAlreadyVoted)
+
+	_, _, err = svc.CastVote(ctx, poll.ID, poll.Options[0].ID, "voter-2", 0, "")
+	require.NoError(t, err)
+	_, _, err = svc.CastWriteInVote(ctx, poll.ID, "Feature C", "voter-2", 0, "")
+	requireValidationCode(t, err, CodeAlreadyVoted)
+}
+
+func TestCastWriteInVote_Rejected(t *testing.T) {
+	ctx := context.Background()
+	svc := newMemoryTestService(PollServiceConfig{})
+	closed := createMemoryPoll(t, svc, validCreateRequest())
+	open := writeInPoll(t, svc)
+
+	_, _, err := svc.CastWriteInVote(ctx, closed.ID, "Feature C", "voter-1", 0, "")
+	requireValidationCode(t, err, CodeWriteInDisabled)
+
+	for name, text := range map[string]string{
+		"blank":         " \t\n",
+		"only controls": "\x00\u200b\u202e",
+		"too long":      strings.Repeat("a", MaxWriteInLength+1),
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, _, err := svc.CastWriteInVote(ctx, open.ID, text, "voter-1", 0, "")
+			requireValidationCode(t, err, CodeWriteInLength)
+		})
+	}
+}
+
+func TestSanitizeWriteIn(t *testing.T) {
+	got, err := sanitizeWriteIn("  Feature\u202e\x07 \n C\xff ")
+	require.NoError(t, err)
+	assert.Equal(t, "Feature C", got)
+
+	got, err = sanitizeWriteIn(strings.Repeat("é", MaxWriteInLength))
+	require.NoError(t, err, "length is counted in characters, not bytes")
+	assert.Equal(t, strings.Repeat("é", MaxWriteInLength), got)
+}
+
+func TestCreatePoll_WriteInQuizRejected(t *testing.T) {
+	svc := newMemoryTestService(PollServiceConfig{})
+	req := validCreateRequest()
+	req.AllowWriteIn = true
+	req.QuizMode = true
+	req.CorrectOptions = []int{0}
+
+	_, _, err := svc.CreatePoll(context.Background(), req, "owner-1")
+	requireValidationCode(t, err, CodeWriteInWithQuiz)
+}
+
+func TestWriteInResults_Truncated(t *testing.T) {
+	poll := &models.PollWithVote{}
+	poll.TotalVotes = MaxWriteInEntries + 1
+	for i := 0; i <= MaxWriteInEntries; i++ {
+		poll.WriteIns = append(poll.WriteIns, models.WriteInTally{Text: "answer", VoteCount: 1})
+	}
+
+	result := writeInResults(poll)
+	assert.Equal(t, int64(MaxWriteInEntries+1), result.VoteCount, "the count covers entries beyond the cap")
+	assert.Len(t, result.Entries, MaxWriteInEntries)
+	assert.True(t, result.Truncated)
+}
+
+func TestBackup_WriteInVotesRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	source := newMemoryTestService(PollServiceConfig{Clock: fixedClock{now: testNow}})
+	poll := writeInPoll(t, source)
+	_, _, err := source.CastVote(ctx, poll.ID, poll.Options[0].ID, "voter-1", 0, "")
+	require.NoError(t, err)
+	_, _, err = source.CastWriteInVote(ctx, poll.ID, "Feature C", "voter-2", 0, "campaign")
+	require.NoError(t, err)
+	lines := exportLines(t, source)
+
+	restored := newMemoryTestService(PollServiceConfig{Clock: fixedClock{now: testNow}})
+	summary, err := restored.ImportAll(ctx, strings.NewReader(strings.Join(lines, "\n")))
+	require.NoError(t, err)
+	assert.Equal(t, &models.BackupSummary{Polls: 1, Options: 2, Votes: 1, WriteInVotes: 1}, summary)
+
+	results, err := restored.GetPollResults(ctx, poll.ID, "voter-2")
+	require.NoError(t, err)
+	assert.True(t, results.HasVoted)
+	assert.Equal(t, int64(2), results.TotalVotes)
+	require.NotNil(t, results.WriteIns)
+	assert.Equal(t, []models.WriteInTally{{Text: "Feature C", VoteCount: 1}}, results.WriteIns.Entries)
+	assert.Equal(t, lines, exportLines(t, restored))
+}
@@ -0,0 +1,158 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/moabdelazem/k8s-app/internal/models"
+	"github.com/moabdelazem/k8s-app/internal/repository"
+	"github.com/moabdelazem/k8s-app/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// Delivery headers sent with every webhook request
+const (
+	SignatureHeader = "X-Webhook-Signature"
+	EventHeader     = "X-Webhook-Event"
+)
+
+// Config represents webhook delivery configuration
+type Config struct {
+	MaxRetries int           // Delivery attempts per webhook
+	RetryDelay time.Duration // Initial delay between attempts
+	Timeout    time.Duration // Per-request HTTP timeout
+}
+
+// Dispatcher delivers poll events relayed from the outbox to registered webhooks
+type Dispatcher struct {
+	repo   repository.WebhookRepositoryInterface
+	client *http.Client
+	cfg    Config
+}
+
+// NewDispatcher creates a dispatcher delivering with the given retry policy
+func NewDispatcher(repo repository.WebhookRepositoryInterface, cfg Config) *Dispatcher {
+	// Set default values if not provided
+	if cfg.MaxRetries <= 0 {
+		cfg.MaxRetries = 3
+	}
+	if cfg.RetryDelay <= 0 {
+		cfg.RetryDelay = time.Second
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 5 * time.Second
+	}
+
+	return &Dispatcher{
+		repo:   repo,
+		client: &http.Client{Timeout: cfg.Timeout},
+		cfg:    cfg,
+	}
+}
+
+// Publish delivers an event to every webhook subscribed to it before returning.
+// It fails unless every delivery succeeded, so the relay keeps the event and offers it again;
+// webhooks that already received it then receive it twice.
+func (d *Dispatcher) Publish(ctx context.Context, event models.PollEvent) error {
+	webhooks, err := d.repo.ListWebhooksForEvent(ctx, event.PollID, event.Type)
+	if err != nil {
+		return fmt.Errorf("failed to load webhooks: %w", err)
+	}
+	if len(webhooks) == 0 {
+		return nil
+	}
+
+	body, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to encode webhook payload: %w", err)
+	}
+
+	var errs []error
+	for _, webhook := range webhooks {
+		if err := d.deliverWithRetry(ctx, webhook, event.Type, body); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// deliverWithRetry posts the payload, retrying with exponential backoff
+// It returns the last error once every attempt failed, or ctx's error if it is canceled first.
+func (d *Dispatcher) deliverWithRetry(ctx context.Context, webhook models.Webhook, eventType string, body []byte) error {
+	var err error
+	for attempt := 1; attempt <= d.cfg.MaxRetries; attempt++ {
+		err = d.deliver(ctx, webhook, eventType, body)
+		if err == nil {
+			logger.Debug("Webhook delivered",
+				zap.String("webhook_id", webhook.ID.String()),
+				zap.String("event", eventType),
+				zap.Int("attempt", attempt),
+			)
+			return nil
+		}
+
+		logger.Warn("Webhook delivery attempt failed",
+			zap.Error(err),
+			zap.String("webhook_id", webhook.ID.String()),
+			zap.Int("attempt", attempt),
+			zap.Int("max_retries", d.cfg.MaxRetries),
+		)
+
+		if attempt == d.cfg.MaxRetries {
+			break
+		}
+
+		// Exponential backoff: delay, 2*delay, 4*delay, ...
+		backoffDelay := d.cfg.RetryDelay * time.Duration(1<<(attempt-1))
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoffDelay):
+		}
+	}
+
+	logger.Error("Webhook delivery failed",
+		zap.String("webhook_id", webhook.ID.String()),
+		zap.String("event", eventType),
+		zap.Int("attempts", d.cfg.MaxRetries),
+	)
+	return fmt.Errorf("webhook %s: %w", webhook.ID, err)
+}
+
+// deliver performs a single signed POST to the webhook URL
+func (d *Dispatcher) deliver(ctx context.Context, webhook models.Webhook, eventType string, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to build request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(EventHeader, eventType)
+	req.Header.Set(SignatureHeader, Sign(webhook.Secret, body))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// Sign computes the signature header value for a payload: "sha256=" + hex(HMAC-SHA256(secret, body))
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
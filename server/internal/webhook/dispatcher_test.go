@@ -0,0 +1,109 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/moabdelazem/k8s-app/internal/mocks"
+	"github.com/moabdelazem/k8s-app/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSign(t *testing.T) {
+	// Reference value: echo -n '{"event":"vote.cast"}' | openssl dgst -sha256 -hmac 'super-secret-key'
+	signature := Sign("super-secret-key", []byte(`{"event":"vote.cast"}`))
+
+	assert.Equal(t, "sha256=2e4bb352b2f53ef1dc6fe880cd5511fbc3ab57726d2a3cc40af58f3fa272130a", signature)
+	assert.NotEqual(t, signature, Sign("another-secret-key", []byte(`{"event":"vote.cast"}`)))
+}
+
+func TestDispatcher_DeliversSignedPayload(t *testing.T) {
+	type delivery struct {
+		header http.Header
+		body   []byte
+	}
+	received := make(chan delivery, 1)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		received <- delivery{header: r.Header.Clone(), body: body}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	pollID := uuid.New()
+	optionID := uuid.New()
+	secret := "super-secret-key"
+
+	repo := new(mocks.MockWebhookRepository)
+	repo.On("ListWebhooksForEvent", mock.Anything, pollID, models.EventVoteCast).Return([]models.Webhook{
+		{ID: uuid.New(), PollID: pollID, URL: server.URL, Events: []string{models.EventVoteCast}, Secret: secret},
+	}, nil)
+
+	dispatcher := NewDispatcher(repo, Config{})
+	err := dispatcher.Publish(context.Background(), models.PollEvent{
+		Type:       models.EventVoteCast,
+		PollID:     pollID,
+		OccurredAt: time.Date(2025, time.June, 1, 12, 0, 0, 0, time.UTC),
+		Data:       map[string]any{"option_id": optionID},
+	})
+	require.NoError(t, err)
+
+	select {
+	case got := <-received:
+		assert.Equal(t, "application/json", got.header.Get("Content-Type"))
+		assert.Equal(t, models.EventVoteCast, got.header.Get(EventHeader))
+		assert.Equal(t, Sign(secret, got.body), got.header.Get(SignatureHeader))
+
+		var payload map[string]any
+		require.NoError(t, json.Unmarshal(got.body, &payload))
+		assert.Equal(t, map[string]any{
+			"event":       models.EventVoteCast,
+			"poll_id":     pollID.String(),
+			"occurred_at": "2025-06-01T12:00:00Z",
+			"data":        map[string]any{"option_id": optionID.String()},
+		}, payload)
+	case <-time.After(2 * time.Second):
+		t.Fatal("webhook was not delivered")
+	}
+}
+
+func TestDispatcher_PublishFailsUntilDelivered(t *testing.T) {
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	pollID := uuid.New()
+	repo := new(mocks.MockWebhookRepository)
+	repo.On("ListWebhooksForEvent", mock.Anything, pollID, models.EventPollClosed).Return([]models.Webhook{
+		{ID: uuid.New(), PollID: pollID, URL: server.URL, Events: []string{models.EventPollClosed}},
+	}, nil)
+
+	dispatcher := NewDispatcher(repo, Config{MaxRetries: 2, RetryDelay: time.Millisecond})
+	err := dispatcher.Publish(context.Background(), models.PollEvent{Type: models.EventPollClosed, PollID: pollID})
+
+	// The relay keeps the event for the next pass
+	assert.ErrorContains(t, err, "unexpected status code 503")
+	assert.Equal(t, 2, attempts)
+}
+
+func TestDispatcher_PublishWithoutWebhooks(t *testing.T) {
+	pollID := uuid.New()
+	repo := new(mocks.MockWebhookRepository)
+	repo.On("ListWebhooksForEvent", mock.Anything, pollID, models.EventVoteCast).Return([]models.Webhook(nil), nil)
+
+	err := NewDispatcher(repo, Config{}).Publish(context.Background(), models.PollEvent{Type: models.EventVoteCast, PollID: pollID})
+
+	assert.NoError(t, err)
+}
@@ -0,0 +1,47 @@
+package auth
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testNow = time.Date(2025, time.June, 1, 12, 0, 0, 0, time.UTC)
+
+func TestParseToken(t *testing.T) {
+	valid, err := SignToken("secret", Claims{Subject: "user-1", ExpiresAt: testNow.Add(time.Hour).Unix()})
+	require.NoError(t, err)
+	expired, err := SignToken("secret", Claims{Subject: "user-1", ExpiresAt: testNow.Unix()})
+	require.NoError(t, err)
+	noSubject, err := SignToken("secret", Claims{ExpiresAt: testNow.Add(time.Hour).Unix()})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		secret  string
+		token   string
+		wantErr error
+	}{
+		{name: "valid token", secret: "secret", token: valid},
+		{name: "wrong secret", secret: "other", token: valid, wantErr: ErrInvalidToken},
+		{name: "expired token", secret: "secret", token: expired, wantErr: ErrTokenExpired},
+		{name: "missing subject", secret: "secret", token: noSubject, wantErr: ErrInvalidToken},
+		{name: "malformed token", secret: "secret", token: "not-a-jwt", wantErr: ErrInvalidToken},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims, err := ParseToken(tt.secret, tt.token, testNow)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, claims)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, "user-1", claims.Subject)
+			}
+		})
+	}
+}
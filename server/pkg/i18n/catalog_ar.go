@@ -0,0 +1,64 @@
+package i18n
+
+// arabic holds Arabic translations of the english catalog
+var arabic = map[string]string{
+	"poll_not_found":            "الاستطلاع غير موجود",
+	"poll_deleted":              "تم حذف الاستطلاع",
+	"poll_delete_protected":     "لا يمكن حذف الاستطلاع لكثرة الأصوات فيه؛ اطلب ذلك من المسؤول",
+	"vote_not_found":            "التصويت غير موجود",
+	"webhook_not_found":         "خطاف الويب غير موجود",
+	"template_not_found":        "القالب غير موجود",
+	"active_poll_limit_reached": "تم بلوغ الحد الأقصى للاستطلاعات النشطة",
+	"voter_network_blocked":     "لا تُقبل الأصوات من هذه الشبكة",
+	"voter_not_allowed":         "أنت لست ضمن قائمة المسموح لهم بالتصويت في هذا الاستطلاع",
+	"allowed_voter_not_found":   "المصوّت ليس ضمن قائمة المسموح لهم في الاستطلاع",
+	"restore_target_not_empty":  "لا يمكن استعادة النسخ الاحتياطية إلا في قاعدة بيانات فارغة",
+	"temporarily_unavailable":   "الخدمة غير متاحة مؤقتًا، يرجى المحاولة مرة أخرى",
+
+	"question_length":              "يجب أن يتراوح طول السؤال بين 5 و500 حرف",
+	"too_few_options":              "يجب أن يحتوي الاستطلاع على خيارين على الأقل",
+	"too_many_options":             "لا يمكن أن يحتوي الاستطلاع على أكثر من 10 خيارات",
+	"option_length":                "يجب أن يتراوح طول الخيار %d بين 1 و200 حرف",
+	"group_length":                 "يجب ألا تتجاوز المجموعة %d حرفًا",
+	"expiry_not_in_future":         "يجب أن يكون تاريخ الانتهاء في المستقبل",
+	"expiry_beyond_horizon":        "يجب ألا يتجاوز تاريخ الانتهاء %s",
+	"quiz_needs_correct_option":    "يجب أن تحدد استطلاعات الاختبار خيارًا صحيحًا واحدًا على الأقل",
+	"correct_options_without_quiz": "لا يمكن تحديد الخيارات الصحيحة إلا في استطلاعات الاختبار",
+	"invalid_correct_option":       "الخيار الصحيح %d غير موجود",
+	"option_count_out_of_bounds":   "يجب أن يحتوي الاستطلاع على ما بين 2 و10 خيارات",
+	"options_locked":               "لا يمكن تعديل الخيارات بعد أن يتلقى الاستطلاع أصواتًا",
+	"option_set_mismatch":          "يجب أن تتضمن الخيارات كل خيارات الاستطلاع البالغ عددها %d",
+	"invalid_option_update":        "الخيار %d لا يطابق خيارًا مستقلًا في هذا الاستطلاع",
+	"invalid_bucket":               "يجب أن تكون مدة الفترة الزمنية قيمة موجبة",
+	"confirmation_token_required":  "رمز التأكيد مطلوب",
+	"confirmation_token_invalid":   "رمز التأكيد غير صالح أو منتهي الصلاحية",
+	"poll_inactive":                "الاستطلاع غير نشط",
+	"poll_expired":                 "انتهت صلاحية الاستطلاع",
+	"already_voted":                "لقد قمت بالتصويت في هذا الاستطلاع بالفعل",
+	"already_voted_in_group":       "لقد قمت بالتصويت في استطلاع من هذه المجموعة بالفعل",
+	"invalid_option":               "خيار غير صالح لهذا الاستطلاع",
+	"weighted_voting_disabled":     "التصويت المرجّح غير مفعّل لهذا الاستطلاع",
+	"vote_weight_out_of_range":     "يجب أن يكون وزن الصوت بين %d و%d",
+	"batch_ids_required":           "يلزم تحديد معرّف استطلاع واحد على الأقل",
+	"batch_too_many_ids":           "يمكن طلب %d معرّفًا للاستطلاعات كحد أقصى في المرة الواحدة",
+	"campaign_length":              "يجب ألا تتجاوز الحملة %d حرفًا",
+	"share_link_invalid":           "رابط المشاركة غير صالح أو منتهي الصلاحية",
+	"receipt_invalid":              "الإيصال غير صالح أو لم يصدر لهذا الاستطلاع",
+	"write_in_disabled":            "التصويت بإجابة مكتوبة غير مفعل لهذا الاستطلاع",
+	"write_in_length":              "يجب أن تكون الإجابة المكتوبة بين 1 و%d حرفًا",
+	"write_in_with_quiz":           "لا يمكن تفعيل الإجابات المكتوبة في استطلاعات الاختبار",
+	"max_votes_invalid":            "يجب أن يكون الحد الأقصى للأصوات 1 على الأقل",
+	"poll_full":                    "اكتمل عدد الأصوات في هذا الاستطلاع",
+	"voting_window_invalid":        "يجب أن تكون لنافذة التصويت بداية ونهاية مختلفتان بصيغة HH:MM",
+	"timezone_invalid":             "المنطقة الزمنية %q غير معروفة",
+	"outside_voting_window":        "لا يقبل هذا الاستطلاع الأصوات إلا بين %s و%s (%s)",
+	"acknowledgement_option_count": "يجب أن يحتوي استطلاع الإقرار على خيار واحد فقط",
+	"acknowledgement_conflict":     "لا يمكن أن يكون استطلاع الإقرار موزونًا أو اختبارًا أو أن يقبل إجابات حرة",
+	"template_name_length":         "يجب أن يتراوح طول اسم القالب بين 1 و%d حرفًا",
+	"metadata_invalid":             "يجب أن تكون البيانات الوصفية كائن JSON",
+	"metadata_too_large":           "يجب ألا يتجاوز حجم البيانات الوصفية %d بايت",
+	"vote_choice_required":         "يجب تحديد option_id أو write_in",
+	"vote_choice_conflict":         "حدد إما option_id أو write_in، وليس كليهما",
+	"invalid_field":                "الحقل %s لا يستوفي القاعدة %s",
+	"invalid_poll_id":              "معرّف الاستطلاع غير صالح",
+}
@@ -0,0 +1,64 @@
+package i18n
+
+// english holds the default messages; they match the service layer's own error text
+var english = map[string]string{
+	"poll_not_found":            "poll not found",
+	"poll_deleted":              "poll has been deleted",
+	"poll_delete_protected":     "poll has too many votes to be deleted; ask an admin",
+	"vote_not_found":            "vote not found",
+	"webhook_not_found":         "webhook not found",
+	"template_not_found":        "template not found",
+	"active_poll_limit_reached": "active poll limit reached",
+	"voter_network_blocked":     "votes from this network are not accepted",
+	"voter_not_allowed":         "you are not on the allowlist for this poll",
+	"allowed_voter_not_found":   "voter is not on the poll's allowlist",
+	"restore_target_not_empty":  "backups can only be restored into an empty database",
+	"temporarily_unavailable":   "Service temporarily unavailable, please retry",
+
+	"question_length":              "question must be between 5 and 500 characters",
+	"too_few_options":              "poll must have at least 2 options",
+	"too_many_options":             "poll can have at most 10 options",
+	"option_length":                "option %d must be between 1 and 200 characters",
+	"group_length":                 "group must be at most %d characters",
+	"expiry_not_in_future":         "expiration date must be in the future",
+	"expiry_beyond_horizon":        "expiration date must be no later than %s",
+	"quiz_needs_correct_option":    "quiz polls must mark at least one correct option",
+	"correct_options_without_quiz": "correct options can only be set on quiz polls",
+	"invalid_correct_option":       "correct option %d does not exist",
+	"option_count_out_of_bounds":   "poll must have between 2 and 10 options",
+	"options_locked":               "options cannot be edited once the poll has votes",
+	"option_set_mismatch":          "options must list each of the poll's %d options",
+	"invalid_option_update":        "option %d does not match a distinct option of this poll",
+	"invalid_bucket":               "bucket must be a positive duration",
+	"confirmation_token_required":  "confirmation token is required",
+	"confirmation_token_invalid":   "confirmation token is invalid or has expired",
+	"poll_inactive":                "poll is not active",
+	"poll_expired":                 "poll has expired",
+	"already_voted":                "you have already voted on this poll",
+	"already_voted_in_group":       "you have already voted on a poll in this group",
+	"invalid_option":               "invalid option for this poll",
+	"weighted_voting_disabled":     "weighted voting is not enabled for this poll",
+	"vote_weight_out_of_range":     "vote weight must be between %d and %d",
+	"batch_ids_required":           "at least one poll ID is required",
+	"batch_too_many_ids":           "at most %d poll IDs can be requested at once",
+	"campaign_length":              "campaign must be at most %d characters",
+	"share_link_invalid":           "share link is invalid or has expired",
+	"receipt_invalid":              "receipt is invalid or was not issued for this poll",
+	"write_in_disabled":            "write-in votes are not enabled for this poll",
+	"write_in_length":              "write-in must be between 1 and %d characters",
+	"write_in_with_quiz":           "write-in votes cannot be enabled on quiz polls",
+	"max_votes_invalid":            "max votes must be at least 1",
+	"poll_full":                    "poll is full",
+	"voting_window_invalid":        "voting window must have a start and an end as different HH:MM times",
+	"timezone_invalid":             "unknown timezone %q",
+	"outside_voting_window":        "poll only accepts votes between %s and %s (%s)",
+	"acknowledgement_option_count": "acknowledgement polls must have exactly 1 option",
+	"acknowledgement_conflict":     "acknowledgement polls cannot be weighted, quizzes or take write-ins",
+	"template_name_length":         "template name must be between 1 and %d characters",
+	"metadata_invalid":             "metadata must be a JSON object",
+	"metadata_too_large":           "metadata must be at most %d bytes",
+	"vote_choice_required":         "option_id or write_in is required",
+	"vote_choice_conflict":         "give either option_id or write_in, not both",
+	"invalid_field":                "%s does not satisfy %s",
+	"invalid_poll_id":              "Invalid poll ID",
+}
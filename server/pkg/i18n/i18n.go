@@ -0,0 +1,83 @@
+// Package i18n renders client-facing messages in the language negotiated from Accept-Language.
+package i18n
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// DefaultLanguage is used when the client accepts none of the supported languages
+const DefaultLanguage = "en"
+
+// catalogs maps a language tag to its messages, keyed by error code.
+// Messages are fmt templates; use explicit argument indexes (%[2]d) when a
+// language needs the arguments in a different order.
+var catalogs = map[string]map[string]string{
+	"en": english,
+	"ar": arabic,
+}
+
+// Supported reports whether lang has a message catalog
+func Supported(lang string) bool {
+	_, ok := catalogs[lang]
+	return ok
+}
+
+// Message renders the message for code in lang.
+// ok is false when lang or code is unknown, so callers can fall back to their own text.
+func Message(lang, code string, args ...any) (msg string, ok bool) {
+	template, ok := catalogs[lang][code]
+	if !ok {
+		return "", false
+	}
+	if len(args) == 0 {
+		return template, true
+	}
+	return fmt.Sprintf(template, args...), true
+}
+
+// Negotiate picks the supported language the client prefers most from an
+// Accept-Language header value, falling back to DefaultLanguage.
+// Region subtags are ignored, so "ar-EG" selects "ar".
+func Negotiate(acceptLanguage string) string {
+	type candidate struct {
+		lang    string
+		quality float64
+		order   int
+	}
+
+	var candidates []candidate
+	for i, part := range strings.Split(acceptLanguage, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		lang, _, _ := strings.Cut(strings.ToLower(strings.TrimSpace(tag)), "-")
+		if !Supported(lang) {
+			continue
+		}
+
+		quality := 1.0
+		if q, found := strings.CutPrefix(strings.TrimSpace(params), "q="); found {
+			parsed, err := strconv.ParseFloat(q, 64)
+			if err != nil {
+				continue
+			}
+			quality = parsed
+		}
+		if quality <= 0 {
+			continue
+		}
+
+		candidates = append(candidates, candidate{lang: lang, quality: quality, order: i})
+	}
+
+	if len(candidates) == 0 {
+		return DefaultLanguage
+	}
+
+	// Highest quality wins; ties go to the language listed first
+	sort.SliceStable(candidates, func(a, b int) bool {
+		return candidates[a].quality > candidates[b].quality
+	})
+	return candidates[0].lang
+}
@@ -0,0 +1,58 @@
+package i18n
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNegotiate(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		want   string
+	}{
+		{name: "empty header", header: "", want: "en"},
+		{name: "exact match", header: "ar", want: "ar"},
+		{name: "region subtag", header: "ar-EG", want: "ar"},
+		{name: "case insensitive", header: "AR-eg", want: "ar"},
+		{name: "unsupported only", header: "fr-FR, de", want: "en"},
+		{name: "first supported wins", header: "fr, ar, en", want: "ar"},
+		{name: "quality ordering", header: "ar;q=0.5, en;q=0.9", want: "en"},
+		{name: "ties keep header order", header: "en;q=0.8, ar;q=0.8", want: "en"},
+		{name: "zero quality excluded", header: "ar;q=0, en;q=0.1", want: "en"},
+		{name: "wildcard ignored", header: "*", want: "en"},
+		{name: "malformed quality skipped", header: "ar;q=abc, en", want: "en"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Negotiate(tt.header))
+		})
+	}
+}
+
+func TestMessage(t *testing.T) {
+	msg, ok := Message("en", "vote_weight_out_of_range", int64(1), int64(10))
+	assert.True(t, ok)
+	assert.Equal(t, "vote weight must be between 1 and 10", msg)
+
+	msg, ok = Message("ar", "vote_weight_out_of_range", int64(1), int64(10))
+	assert.True(t, ok)
+	assert.Equal(t, "يجب أن يكون وزن الصوت بين 1 و10", msg)
+
+	_, ok = Message("en", "no_such_code")
+	assert.False(t, ok)
+
+	_, ok = Message("fr", "poll_not_found")
+	assert.False(t, ok)
+}
+
+func TestCatalogsCoverEnglish(t *testing.T) {
+	for lang, catalog := range catalogs {
+		for code := range english {
+			assert.Contains(t, catalog, code, "%s catalog is missing %s", lang, code)
+		}
+		assert.Len(t, catalog, len(english), "%s catalog has codes unknown to english", lang)
+	}
+}
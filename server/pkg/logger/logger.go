@@ -84,6 +84,11 @@ func GetLogger() *zap.Logger {
 	return Log
 }
 
+// DebugEnabled reports whether debug level messages are being logged
+func DebugEnabled() bool {
+	return GetLogger().Core().Enabled(zapcore.DebugLevel)
+}
+
 // Info logs an info message
 func Info(msg string, fields ...zap.Field) {
 	GetLogger().Info(msg, fields...)
@@ -0,0 +1,74 @@
+// Package receipt signs and verifies vote receipts.
+// A receipt carries a poll ID, an option ID, the vote time and a voter tag, followed by a
+// truncated HMAC-SHA256 signature, all base64url-encoded. The voter tag is a keyed hash of
+// the voter identifier, so a receipt is bound to one voter without revealing who they are.
+package receipt
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var ErrInvalidReceipt = errors.New("invalid vote receipt")
+
+const (
+	payloadSize   = 16 + 16 + 8 + tagSize // Poll ID, option ID, vote time and voter tag
+	tagSize       = 16
+	signatureSize = 16 // Truncated HMAC-SHA256; 128 bits is ample for a MAC
+)
+
+// Receipt is the content of a receipt token
+type Receipt struct {
+	PollID   uuid.UUID
+	OptionID uuid.UUID
+	VotedAt  time.Time // Kept to the microsecond, the precision votes are stored with
+}
+
+var encoding = base64.RawURLEncoding
+
+// Sign encodes a receipt for the vote voterIdentifier cast into a signed token
+func Sign(secret string, receipt Receipt, voterIdentifier string) string {
+	payload := make([]byte, payloadSize, payloadSize+signatureSize)
+	copy(payload, receipt.PollID[:])
+	copy(payload[16:], receipt.OptionID[:])
+	binary.BigEndian.PutUint64(payload[32:], uint64(receipt.VotedAt.UnixMicro()))
+	copy(payload[40:], voterTag(secret, voterIdentifier))
+
+	return encoding.EncodeToString(append(payload, sign(secret, payload)...))
+}
+
+// Parse verifies a token signed with secret and returns its receipt
+func Parse(secret, token string) (*Receipt, error) {
+	data, err := encoding.DecodeString(token)
+	if err != nil || len(data) != payloadSize+signatureSize {
+		return nil, ErrInvalidReceipt
+	}
+
+	payload, signature := data[:payloadSize], data[payloadSize:]
+	if !hmac.Equal(signature, sign(secret, payload)) {
+		return nil, ErrInvalidReceipt
+	}
+
+	return &Receipt{
+		PollID:   uuid.UUID(payload[:16]),
+		OptionID: uuid.UUID(payload[16:32]),
+		VotedAt:  time.UnixMicro(int64(binary.BigEndian.Uint64(payload[32:40]))).UTC(),
+	}, nil
+}
+
+// voterTag derives the voter tag; the prefix keeps it apart from receipt signatures made with the same secret
+func voterTag(secret, voterIdentifier string) []byte {
+	return sign(secret, []byte("voter:"+voterIdentifier))
+}
+
+func sign(secret string, payload []byte) []byte {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+	return mac.Sum(nil)[:signatureSize]
+}
@@ -0,0 +1,65 @@
+package receipt
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testReceipt = Receipt{
+	PollID:   uuid.New(),
+	OptionID: uuid.New(),
+	VotedAt:  time.Date(2025, time.June, 1, 12, 0, 0, 123456000, time.UTC),
+}
+
+func TestSignAndParse(t *testing.T) {
+	token := Sign("secret", testReceipt, "voter-1")
+
+	receipt, err := Parse("secret", token)
+	require.NoError(t, err)
+	assert.Equal(t, testReceipt, *receipt)
+}
+
+func TestSign_BindsVoter(t *testing.T) {
+	token := Sign("secret", testReceipt, "voter-1")
+
+	assert.NotEqual(t, token, Sign("secret", testReceipt, "voter-2"))
+	assert.NotContains(t, token, "voter-1")
+}
+
+func TestParse(t *testing.T) {
+	valid := Sign("secret", testReceipt, "voter-1")
+
+	// Flip a bit inside the payload so the signature no longer matches
+	tampered := []byte(valid)
+	tampered[10] ^= 1
+
+	tests := []struct {
+		name    string
+		secret  string
+		token   string
+		wantErr bool
+	}{
+		{name: "valid receipt", secret: "secret", token: valid},
+		{name: "wrong secret", secret: "other", token: valid, wantErr: true},
+		{name: "tampered receipt", secret: "secret", token: string(tampered), wantErr: true},
+		{name: "truncated receipt", secret: "secret", token: valid[:40], wantErr: true},
+		{name: "malformed receipt", secret: "secret", token: "not a receipt!", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			receipt, err := Parse(tt.secret, tt.token)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidReceipt)
+				assert.Nil(t, receipt)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, testReceipt.PollID, receipt.PollID)
+			}
+		})
+	}
+}
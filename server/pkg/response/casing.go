@@ -0,0 +1,101 @@
+package response
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"strings"
+)
+
+// camelCaseWriter marks a response whose JSON object keys are written in camelCase
+type camelCaseWriter struct {
+	http.ResponseWriter
+}
+
+// Unwrap exposes the underlying writer to http.ResponseController, so flushing still works
+func (w camelCaseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// WithCamelCaseJSON returns w marked so JSON writes object keys in camelCase, e.g. totalVotes for total_votes
+// The mark is found through writers wrapping w, as long as they implement Unwrap
+func WithCamelCaseJSON(w http.ResponseWriter) http.ResponseWriter {
+	return camelCaseWriter{w}
+}
+
+// isCamelCase reports whether w, or a writer it wraps, was marked by WithCamelCaseJSON
+func isCamelCase(w http.ResponseWriter) bool {
+	for {
+		switch inner := w.(type) {
+		case camelCaseWriter:
+			return true
+		case interface{ Unwrap() http.ResponseWriter }:
+			w = inner.Unwrap()
+		default:
+			return false
+		}
+	}
+}
+
+// camelCaseJSON returns v as decoded JSON whose object keys, at every depth, are in camelCase
+// Models keep their snake_case tags; only the encoded keys change, so the storage layer is untouched.
+func camelCaseJSON(v any) (any, error) {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber() // Large counts and IDs keep their exact digits
+	var decoded any
+	if err := dec.Decode(&decoded); err != nil {
+		return nil, err
+	}
+	return camelCaseKeys(decoded), nil
+}
+
+// verbatimKeys name members holding client-defined JSON, returned with their own keys untouched
+// Poll metadata keys are looked up by the name the integrator stored, e.g. ?key=cost_center.
+var verbatimKeys = map[string]bool{"metadata": true}
+
+// camelCaseKeys renames the keys of the objects in decoded JSON, leaving the values of verbatimKeys as they are
+func camelCaseKeys(v any) any {
+	switch value := v.(type) {
+	case map[string]any:
+		renamed := make(map[string]any, len(value))
+		for key, inner := range value {
+			if verbatimKeys[key] {
+				renamed[key] = inner
+				continue
+			}
+			renamed[camelCase(key)] = camelCaseKeys(inner)
+		}
+		return renamed
+	case []any:
+		for i, inner := range value {
+			value[i] = camelCaseKeys(inner)
+		}
+		return value
+	default:
+		return v
+	}
+}
+
+// camelCase converts a snake_case name to camelCase; names without underscores are returned as is
+// Leading underscores are kept, so reserved members such as _links keep their name.
+func camelCase(name string) string {
+	if !strings.Contains(name, "_") {
+		return name
+	}
+	body := strings.TrimLeft(name, "_")
+	parts := strings.Split(body, "_")
+	var b strings.Builder
+	b.WriteString(name[:len(name)-len(body)])
+	b.WriteString(parts[0])
+	for _, part := range parts[1:] {
+		if part == "" {
+			continue
+		}
+		b.WriteString(strings.ToUpper(part[:1]) + part[1:])
+	}
+	return b.String()
+}
@@ -0,0 +1,60 @@
+package response
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCamelCase(t *testing.T) {
+	assert.Equal(t, "totalVotes", camelCase("total_votes"))
+	assert.Equal(t, "requestId", camelCase("request_id"))
+	assert.Equal(t, "success", camelCase("success"))
+	assert.Equal(t, "voteCount", camelCase("vote__count_"))
+	assert.Equal(t, "_links", camelCase("_links"))
+	assert.Equal(t, "_embeddedPolls", camelCase("_embedded_polls"))
+}
+
+func TestJSON_CamelCaseKeys(t *testing.T) {
+	rec := httptest.NewRecorder()
+	// Middlewares below the one marking the writer wrap it again
+	w := middleware.NewWrapResponseWriter(WithCamelCaseJSON(rec), 1)
+	Success(w, "", map[string]any{
+		"total_votes": int64(9007199254740993),
+		"options":     []map[string]any{{"option_text": "Yes", "vote_count": 2}},
+	})
+
+	assert.Equal(t, `{"data":{"options":[{"optionText":"Yes","voteCount":2}],"totalVotes":9007199254740993},"success":true}`+"\n", rec.Body.String())
+}
+
+func TestJSON_CamelCaseKeepsMetadataKeys(t *testing.T) {
+	rec := httptest.NewRecorder()
+	Success(WithCamelCaseJSON(rec), "", []map[string]any{{
+		"total_votes": 1,
+		"metadata":    map[string]any{"cost_center": "eng", "costCenter": "ops", "ui_hints": map[string]any{"bar_color": "red"}},
+	}})
+
+	assert.Equal(t, `{"data":[{"metadata":{"costCenter":"ops","cost_center":"eng","ui_hints":{"bar_color":"red"}},"totalVotes":1}],"success":true}`+"\n", rec.Body.String())
+}
+
+func TestStreamArray_CamelCaseKeys(t *testing.T) {
+	rec := httptest.NewRecorder()
+	err := StreamArray(WithCamelCaseJSON(rec), map[string]any{"total_count": 1}, "poll_list", "failed", func(emit func(any) error) error {
+		return emit(map[string]any{"created_at": "2026-01-01T00:00:00Z"})
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, `{"data":{"totalCount":1,"pollList":[{"createdAt":"2026-01-01T00:00:00Z"}`+"\n"+`]},"success":true}`+"\n", rec.Body.String())
+}
+
+func TestJSON_CamelCaseEncodeError(t *testing.T) {
+	rec := httptest.NewRecorder()
+	JSON(WithCamelCaseJSON(rec), http.StatusOK, map[string]any{"bad": func() {}})
+
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	assert.Contains(t, rec.Body.String(), "Failed to encode response")
+}
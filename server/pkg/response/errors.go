@@ -0,0 +1,48 @@
+package response
+
+import (
+	"net/http"
+	"sync/atomic"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/moabdelazem/k8s-app/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// exposeInternalErrors controls whether 5xx responses carry the underlying error text
+var exposeInternalErrors atomic.Bool
+
+func init() {
+	exposeInternalErrors.Store(true)
+}
+
+// SetExposeInternalErrors toggles detailed internal error messages.
+// Disable it in production so wrapped SQL and driver errors never reach clients.
+func SetExposeInternalErrors(expose bool) {
+	exposeInternalErrors.Store(expose)
+}
+
+// InternalError logs err with the request ID and sends a 500 response.
+// With exposure disabled the client only receives message and the request ID
+// to correlate with the logs; otherwise the detailed error is returned.
+func InternalError(w http.ResponseWriter, r *http.Request, message string, err error) {
+	requestID := middleware.GetReqID(r.Context())
+
+	logger.Error(message,
+		zap.Error(err),
+		zap.String("request_id", requestID),
+		zap.String("method", r.Method),
+		zap.String("path", r.URL.Path),
+	)
+
+	errMessage := message
+	if exposeInternalErrors.Load() && err != nil {
+		errMessage = err.Error()
+	}
+
+	JSON(w, http.StatusInternalServerError, Response{
+		Success:   false,
+		Error:     errMessage,
+		RequestID: requestID,
+	})
+}
@@ -0,0 +1,46 @@
+package response
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInternalError(t *testing.T) {
+	tests := []struct {
+		name      string
+		expose    bool
+		wantError string
+	}{
+		{name: "production hides details", expose: false, wantError: "Failed to delete poll"},
+		{name: "development exposes details", expose: true, wantError: "pq: connection refused"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			SetExposeInternalErrors(tt.expose)
+			t.Cleanup(func() { SetExposeInternalErrors(true) })
+
+			req := httptest.NewRequest(http.MethodDelete, "/api/v1/polls/123", nil)
+			req = req.WithContext(context.WithValue(req.Context(), middleware.RequestIDKey, "req-42"))
+			rec := httptest.NewRecorder()
+
+			InternalError(rec, req, "Failed to delete poll", errors.New("pq: connection refused"))
+
+			assert.Equal(t, http.StatusInternalServerError, rec.Code)
+
+			var body Response
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+			assert.False(t, body.Success)
+			assert.Equal(t, tt.wantError, body.Error)
+			assert.Equal(t, "req-42", body.RequestID)
+		})
+	}
+}
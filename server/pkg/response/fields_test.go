@@ -0,0 +1,57 @@
+package response
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseFields(t *testing.T) {
+	allowed := []string{"id", "question", "total_votes"}
+
+	tests := []struct {
+		name    string
+		raw     string
+		want    []string
+		wantErr string
+	}{
+		{name: "empty selects everything", raw: "", want: nil},
+		{name: "subset", raw: "question,total_votes", want: []string{"question", "total_votes"}},
+		{name: "spaces and duplicates", raw: " question , question,,id", want: []string{"question", "id"}},
+		{name: "unknown field", raw: "question,owner_id", wantErr: `unknown field "owner_id"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fields, err := ParseFields(tt.raw, allowed)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, fields)
+		})
+	}
+}
+
+func TestSelectFields(t *testing.T) {
+	type item struct {
+		ID          int     `json:"id"`
+		Question    string  `json:"question"`
+		Description *string `json:"description,omitempty"`
+		TotalVotes  int64   `json:"total_votes"`
+	}
+	v := item{ID: 1, Question: "Tabs or spaces?", TotalVotes: 42}
+
+	projected, err := SelectFields(v, []string{"question", "total_votes", "description"})
+	require.NoError(t, err)
+	out, err := json.Marshal(projected)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"question":"Tabs or spaces?","total_votes":42}`, string(out))
+
+	unchanged, err := SelectFields(v, nil)
+	require.NoError(t, err)
+	assert.Equal(t, v, unchanged)
+}
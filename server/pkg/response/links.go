@@ -0,0 +1,70 @@
+package response
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// LinksMember is the member of a resource holding its hypermedia links
+const LinksMember = "_links"
+
+// Link is a hypermedia link to a related resource or to an action on the resource
+type Link struct {
+	Href   string `json:"href"`
+	Method string `json:"method"`
+}
+
+// linksWriter marks a response whose resources carry their hypermedia links
+type linksWriter struct {
+	http.ResponseWriter
+}
+
+// Unwrap exposes the underlying writer to http.ResponseController, so flushing still works
+func (w linksWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// WithLinks returns w marked so AddLinks adds links to the resources written through it
+// The mark is found through writers wrapping w, as long as they implement Unwrap
+func WithLinks(w http.ResponseWriter) http.ResponseWriter {
+	return linksWriter{w}
+}
+
+// WantsLinks reports whether w, or a writer it wraps, was marked by WithLinks
+func WantsLinks(w http.ResponseWriter) bool {
+	for {
+		switch inner := w.(type) {
+		case linksWriter:
+			return true
+		case interface{ Unwrap() http.ResponseWriter }:
+			w = inner.Unwrap()
+		default:
+			return false
+		}
+	}
+}
+
+// AddLinks returns v with a _links member holding links when w was marked by WithLinks, and v unchanged otherwise
+// v must marshal to a JSON object; links is only called when the links are wanted.
+func AddLinks(w http.ResponseWriter, v any, links func() map[string]Link) (any, error) {
+	if !WantsLinks(w) {
+		return v, nil
+	}
+
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var members map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &members); err != nil {
+		return nil, fmt.Errorf("cannot add links to %T: %w", v, err)
+	}
+	if members == nil {
+		return nil, fmt.Errorf("cannot add links to %T: not a JSON object", v)
+	}
+	if members[LinksMember], err = json.Marshal(links()); err != nil {
+		return nil, err
+	}
+	return members, nil
+}
@@ -0,0 +1,39 @@
+package response
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAddLinks(t *testing.T) {
+	links := func() map[string]Link {
+		return map[string]Link{"self": {Href: "/api/v1/polls/p1", Method: http.MethodGet}}
+	}
+	poll := struct {
+		ID string `json:"id"`
+	}{ID: "p1"}
+
+	unmarked := httptest.NewRecorder()
+	data, err := AddLinks(unmarked, poll, links)
+	require.NoError(t, err)
+	assert.Equal(t, poll, data, "unmarked responses are left alone")
+
+	// Middlewares below the one marking the writer wrap it again
+	rec := httptest.NewRecorder()
+	w := middleware.NewWrapResponseWriter(WithLinks(rec), 1)
+	require.True(t, WantsLinks(w))
+	data, err = AddLinks(w, poll, links)
+	require.NoError(t, err)
+	Success(w, "", data)
+	assert.JSONEq(t, `{"success":true,"data":{"id":"p1","_links":{"self":{"href":"/api/v1/polls/p1","method":"GET"}}}}`, rec.Body.String())
+
+	_, err = AddLinks(w, []string{"p1"}, links)
+	assert.Error(t, err, "only objects can carry links")
+	_, err = AddLinks(w, (*struct{})(nil), links)
+	assert.Error(t, err)
+}
@@ -0,0 +1,60 @@
+package response
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// PaginationLinks builds an RFC 5988 Link header value for offset-based pagination.
+// Links are relative to the request URL and keep any other query parameters intact.
+// Returns an empty string when limit is not positive.
+func PaginationLinks(u *url.URL, limit, offset int, total int64) string {
+	if limit <= 0 {
+		return ""
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	lastOffset := 0
+	if total > 0 {
+		lastOffset = int((total - 1) / int64(limit) * int64(limit))
+	}
+
+	var links []string
+
+	if int64(offset+limit) < total {
+		links = append(links, pageLink(u, limit, offset+limit, "next"))
+	}
+
+	if offset > 0 {
+		prevOffset := offset - limit
+		if prevOffset < 0 {
+			prevOffset = 0
+		}
+		links = append(links, pageLink(u, limit, prevOffset, "prev"))
+	}
+
+	links = append(links, pageLink(u, limit, 0, "first"))
+	links = append(links, pageLink(u, limit, lastOffset, "last"))
+
+	return strings.Join(links, ", ")
+}
+
+// pageLink formats a single link-value pointing at the given page
+func pageLink(u *url.URL, limit, offset int, rel string) string {
+	query := u.Query()
+	query.Set("limit", strconv.Itoa(limit))
+	query.Set("offset", strconv.Itoa(offset))
+
+	target := url.URL{
+		Scheme:   u.Scheme,
+		Host:     u.Host,
+		Path:     u.Path,
+		RawQuery: query.Encode(),
+	}
+
+	return fmt.Sprintf(`<%s>; rel="%s"`, target.String(), rel)
+}
@@ -0,0 +1,68 @@
+package response
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func mustParseURL(t *testing.T, raw string) *url.URL {
+	u, err := url.Parse(raw)
+	require.NoError(t, err)
+	return u
+}
+
+func TestPaginationLinks_FirstPage(t *testing.T) {
+	u := mustParseURL(t, "/api/v1/polls?active=true")
+
+	links := PaginationLinks(u, 10, 0, 35)
+
+	assert.Equal(t,
+		`</api/v1/polls?active=true&limit=10&offset=10>; rel="next", `+
+			`</api/v1/polls?active=true&limit=10&offset=0>; rel="first", `+
+			`</api/v1/polls?active=true&limit=10&offset=30>; rel="last"`,
+		links,
+	)
+	assert.NotContains(t, links, `rel="prev"`)
+}
+
+func TestPaginationLinks_MiddlePage(t *testing.T) {
+	u := mustParseURL(t, "/api/v1/polls?limit=10&offset=10")
+
+	links := PaginationLinks(u, 10, 10, 35)
+
+	assert.Equal(t,
+		`</api/v1/polls?limit=10&offset=20>; rel="next", `+
+			`</api/v1/polls?limit=10&offset=0>; rel="prev", `+
+			`</api/v1/polls?limit=10&offset=0>; rel="first", `+
+			`</api/v1/polls?limit=10&offset=30>; rel="last"`,
+		links,
+	)
+}
+
+func TestPaginationLinks_LastPage(t *testing.T) {
+	u := mustParseURL(t, "/api/v1/polls?limit=10&offset=30")
+
+	links := PaginationLinks(u, 10, 30, 35)
+
+	assert.Equal(t,
+		`</api/v1/polls?limit=10&offset=20>; rel="prev", `+
+			`</api/v1/polls?limit=10&offset=0>; rel="first", `+
+			`</api/v1/polls?limit=10&offset=30>; rel="last"`,
+		links,
+	)
+	assert.NotContains(t, links, `rel="next"`)
+}
+
+func TestPaginationLinks_Empty(t *testing.T) {
+	u := mustParseURL(t, "/api/v1/polls")
+
+	assert.Equal(t,
+		`</api/v1/polls?limit=20&offset=0>; rel="first", `+
+			`</api/v1/polls?limit=20&offset=0>; rel="last"`,
+		PaginationLinks(u, 20, 0, 0),
+	)
+	assert.Empty(t, PaginationLinks(u, 0, 0, 10))
+}
@@ -0,0 +1,33 @@
+package response
+
+import "net/http"
+
+// prettyWriter marks a response whose JSON body is indented for reading
+type prettyWriter struct {
+	http.ResponseWriter
+}
+
+// Unwrap exposes the underlying writer to http.ResponseController, so flushing still works
+func (w prettyWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// WithPrettyJSON returns w marked so JSON indents the bodies written through it
+// The mark is found through writers wrapping w, as long as they implement Unwrap
+func WithPrettyJSON(w http.ResponseWriter) http.ResponseWriter {
+	return prettyWriter{w}
+}
+
+// isPretty reports whether w, or a writer it wraps, was marked by WithPrettyJSON
+func isPretty(w http.ResponseWriter) bool {
+	for {
+		switch inner := w.(type) {
+		case prettyWriter:
+			return true
+		case interface{ Unwrap() http.ResponseWriter }:
+			w = inner.Unwrap()
+		default:
+			return false
+		}
+	}
+}
@@ -0,0 +1,33 @@
+package response
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJSON_PrettyWriterIndents(t *testing.T) {
+	rec := httptest.NewRecorder()
+	Success(WithPrettyJSON(rec), "", map[string]int{"total": 1})
+
+	assert.Equal(t, "{\n  \"success\": true,\n  \"data\": {\n    \"total\": 1\n  }\n}\n", rec.Body.String())
+}
+
+func TestJSON_CompactByDefault(t *testing.T) {
+	rec := httptest.NewRecorder()
+	Success(rec, "", map[string]int{"total": 1})
+
+	assert.Equal(t, "{\"success\":true,\"data\":{\"total\":1}}\n", rec.Body.String())
+}
+
+func TestJSON_PrettyThroughWrappingWriter(t *testing.T) {
+	rec := httptest.NewRecorder()
+	// Middlewares below the one marking the writer wrap it again
+	w := middleware.NewWrapResponseWriter(WithPrettyJSON(rec), 1)
+	JSON(w, http.StatusOK, map[string]int{"total": 1})
+
+	assert.Equal(t, "{\n  \"total\": 1\n}\n", rec.Body.String())
+}
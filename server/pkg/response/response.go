@@ -2,23 +2,43 @@ package response
 
 import (
 	"encoding/json"
+	"math"
 	"net/http"
+	"strconv"
+	"time"
 )
 
 // Response represents a standard API response structure
 type Response struct {
-	Success bool   `json:"success"`
-	Message string `json:"message,omitempty"`
-	Data    any    `json:"data,omitempty"`
-	Error   string `json:"error,omitempty"`
+	Success   bool     `json:"success"`
+	Message   string   `json:"message,omitempty"`
+	Data      any      `json:"data,omitempty"`
+	Error     string   `json:"error,omitempty"`
+	RequestID string   `json:"request_id,omitempty"`
+	Warnings  []string `json:"warnings,omitempty"` // Non-blocking issues with an otherwise successful request
 }
 
 // JSON sends a JSON response with the given status code and data
+// The body is indented when w was marked by WithPrettyJSON, and compact otherwise;
+// its object keys are in camelCase when w was marked by WithCamelCaseJSON
 func JSON(w http.ResponseWriter, statusCode int, data any) {
+	if isCamelCase(w) {
+		camel, err := camelCaseJSON(data)
+		if err != nil {
+			http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+			return
+		}
+		data = camel
+	}
+
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(statusCode)
 
-	if err := json.NewEncoder(w).Encode(data); err != nil {
+	enc := json.NewEncoder(w)
+	if isPretty(w) {
+		enc.SetIndent("", "  ")
+	}
+	if err := enc.Encode(data); err != nil {
 		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
 	}
 }
@@ -41,6 +61,25 @@ func Created(w http.ResponseWriter, message string, data any) {
 	})
 }
 
+// CreatedWithWarnings sends a 201 Created response carrying non-blocking warnings
+func CreatedWithWarnings(w http.ResponseWriter, message string, data any, warnings []string) {
+	JSON(w, http.StatusCreated, Response{
+		Success:  true,
+		Message:  message,
+		Data:     data,
+		Warnings: warnings,
+	})
+}
+
+// Accepted sends a 202 Accepted response for requests that still need a follow-up to take effect
+func Accepted(w http.ResponseWriter, message string, data any) {
+	JSON(w, http.StatusAccepted, Response{
+		Success: true,
+		Message: message,
+		Data:    data,
+	})
+}
+
 // Error sends an error JSON response
 func Error(w http.ResponseWriter, statusCode int, message string) {
 	JSON(w, statusCode, Response{
@@ -73,3 +112,24 @@ func NotFound(w http.ResponseWriter, message string) {
 func InternalServerError(w http.ResponseWriter, message string) {
 	Error(w, http.StatusInternalServerError, message)
 }
+
+// ServiceUnavailable sends a 503 Service Unavailable response with a Retry-After hint
+func ServiceUnavailable(w http.ResponseWriter, message string, retryAfter time.Duration) {
+	setRetryAfter(w, retryAfter)
+	Error(w, http.StatusServiceUnavailable, message)
+}
+
+// TooManyRequests sends a 429 Too Many Requests response with a Retry-After hint
+func TooManyRequests(w http.ResponseWriter, message string, retryAfter time.Duration) {
+	setRetryAfter(w, retryAfter)
+	Error(w, http.StatusTooManyRequests, message)
+}
+
+// setRetryAfter sets the Retry-After header in whole seconds, rounding up to at least 1
+func setRetryAfter(w http.ResponseWriter, retryAfter time.Duration) {
+	seconds := int(math.Ceil(retryAfter.Seconds()))
+	if seconds < 1 {
+		seconds = 1
+	}
+	w.Header().Set("Retry-After", strconv.Itoa(seconds))
+}
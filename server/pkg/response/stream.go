@@ -0,0 +1,113 @@
+package response
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/moabdelazem/k8s-app/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// StreamArray writes a success envelope whose data object holds fields plus an array
+// under name, encoding each array element as it is produced instead of buffering them:
+//
+//	{"data":{<fields>,"<name>":[<items>]},"success":true}
+//
+// items receives an emit callback and calls it once per element. Nothing is written until
+// the first element is emitted (or items returns), so if items fails before that the error
+// is returned and the caller can still render an error response. A failure after output has
+// started closes the array and reports success=false with message, keeping the body valid JSON.
+// When w was marked by WithCamelCaseJSON, the keys of fields, name and the elements are in camelCase.
+func StreamArray(w http.ResponseWriter, fields map[string]any, name, message string, items func(emit func(any) error) error) error {
+	camel := isCamelCase(w)
+	if camel {
+		name = camelCase(name)
+		if fields != nil {
+			converted, err := camelCaseJSON(fields)
+			if err != nil {
+				return err
+			}
+			fields = converted.(map[string]any)
+		}
+	}
+	prefix, err := streamPrefix(fields, name)
+	if err != nil {
+		return err
+	}
+
+	started := false
+	count := 0
+	start := func() {
+		if started {
+			return
+		}
+		started = true
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		w.Write(prefix)
+	}
+
+	enc := json.NewEncoder(w)
+	err = items(func(item any) error {
+		start()
+		if count > 0 {
+			if _, err := w.Write([]byte(",")); err != nil {
+				return err
+			}
+		}
+		count++
+		if camel {
+			converted, err := camelCaseJSON(item)
+			if err != nil {
+				return err
+			}
+			item = converted
+		}
+		return enc.Encode(item)
+	})
+
+	if err != nil && !started {
+		return err
+	}
+
+	start()
+	if err != nil {
+		logger.Error("Streaming response failed after output started",
+			zap.Error(err),
+			zap.Int("items_written", count),
+		)
+		errJSON, _ := json.Marshal(message)
+		fmt.Fprintf(w, `]},"success":false,"error":%s}`+"\n", errJSON)
+		return nil
+	}
+
+	w.Write([]byte(`]},"success":true}` + "\n"))
+	return nil
+}
+
+// streamPrefix renders `{"data":{<fields>,"<name>":[` with fields in sorted key order
+func streamPrefix(fields map[string]any, name string) ([]byte, error) {
+	if fields == nil {
+		fields = map[string]any{}
+	}
+	fieldsJSON, err := json.Marshal(fields)
+	if err != nil {
+		return nil, err
+	}
+	nameJSON, err := json.Marshal(name)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(`{"data":`)
+	buf.Write(bytes.TrimSuffix(fieldsJSON, []byte("}")))
+	if len(fields) > 0 {
+		buf.WriteByte(',')
+	}
+	buf.Write(nameJSON)
+	buf.WriteString(":[")
+	return buf.Bytes(), nil
+}
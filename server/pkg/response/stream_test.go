@@ -0,0 +1,60 @@
+package response
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStreamArray(t *testing.T) {
+	rec := httptest.NewRecorder()
+
+	err := StreamArray(rec, map[string]any{"total": 2}, "items", "Failed", func(emit func(any) error) error {
+		for _, item := range []string{"a", "b"} {
+			if err := emit(item); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+	assert.JSONEq(t, `{"success":true,"data":{"total":2,"items":["a","b"]}}`, rec.Body.String())
+}
+
+func TestStreamArray_ErrorBeforeOutput(t *testing.T) {
+	rec := httptest.NewRecorder()
+	boom := errors.New("boom")
+
+	err := StreamArray(rec, nil, "items", "Failed", func(emit func(any) error) error {
+		return boom
+	})
+
+	// Nothing was written, so the caller can still send a proper error response
+	assert.ErrorIs(t, err, boom)
+	assert.Empty(t, rec.Body.String())
+}
+
+func TestStreamArray_ErrorAfterOutputKeepsValidJSON(t *testing.T) {
+	rec := httptest.NewRecorder()
+
+	err := StreamArray(rec, nil, "items", "Failed to retrieve items", func(emit func(any) error) error {
+		require.NoError(t, emit("a"))
+		return errors.New("connection reset")
+	})
+
+	require.NoError(t, err)
+
+	var body Response
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	assert.False(t, body.Success)
+	assert.Equal(t, "Failed to retrieve items", body.Error)
+	assert.Equal(t, map[string]any{"items": []any{"a"}}, body.Data)
+}
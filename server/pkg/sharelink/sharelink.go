@@ -0,0 +1,81 @@
+// Package sharelink signs and verifies compact share link tokens.
+// A token carries a poll ID, an expiry and an optional campaign tag, followed by a
+// truncated HMAC-SHA256 signature, all base64url-encoded so it fits in a short URL.
+package sharelink
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrInvalidToken = errors.New("invalid share token")
+	ErrTokenExpired = errors.New("share token has expired")
+)
+
+// MaxCampaignLength bounds the campaign tag so tokens stay short
+const MaxCampaignLength = 64
+
+const (
+	headerSize    = 16 + 8 // Poll ID and expiry
+	signatureSize = 16     // Truncated HMAC-SHA256; 128 bits is ample for a MAC
+)
+
+// Link is the content of a share token
+type Link struct {
+	PollID    uuid.UUID
+	Campaign  string // Attribution tag; may be empty
+	ExpiresAt time.Time
+}
+
+var encoding = base64.RawURLEncoding
+
+// Sign encodes link into a signed token
+func Sign(secret string, link Link) (string, error) {
+	if len(link.Campaign) > MaxCampaignLength {
+		return "", errors.New("campaign is too long")
+	}
+
+	payload := make([]byte, headerSize, headerSize+len(link.Campaign)+signatureSize)
+	copy(payload, link.PollID[:])
+	binary.BigEndian.PutUint64(payload[16:], uint64(link.ExpiresAt.Unix()))
+	payload = append(payload, link.Campaign...)
+
+	return encoding.EncodeToString(append(payload, sign(secret, payload)...)), nil
+}
+
+// Parse verifies a token signed with secret and returns its link.
+// Tokens whose expiry is not after now are rejected.
+func Parse(secret, token string, now time.Time) (*Link, error) {
+	data, err := encoding.DecodeString(token)
+	if err != nil || len(data) < headerSize+signatureSize || len(data) > headerSize+MaxCampaignLength+signatureSize {
+		return nil, ErrInvalidToken
+	}
+
+	payload, signature := data[:len(data)-signatureSize], data[len(data)-signatureSize:]
+	if !hmac.Equal(signature, sign(secret, payload)) {
+		return nil, ErrInvalidToken
+	}
+
+	link := &Link{
+		PollID:    uuid.UUID(payload[:16]),
+		Campaign:  string(payload[headerSize:]),
+		ExpiresAt: time.Unix(int64(binary.BigEndian.Uint64(payload[16:headerSize])), 0),
+	}
+	if !link.ExpiresAt.After(now) {
+		return nil, ErrTokenExpired
+	}
+	return link, nil
+}
+
+func sign(secret string, payload []byte) []byte {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+	return mac.Sum(nil)[:signatureSize]
+}
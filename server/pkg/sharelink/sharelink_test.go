@@ -0,0 +1,82 @@
+package sharelink
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testNow = time.Date(2025, time.June, 1, 12, 0, 0, 0, time.UTC)
+
+func TestSignAndParse(t *testing.T) {
+	pollID := uuid.New()
+
+	tests := []struct {
+		name     string
+		campaign string
+	}{
+		{name: "without campaign", campaign: ""},
+		{name: "with campaign", campaign: "spring-newsletter"},
+		{name: "longest campaign", campaign: strings.Repeat("c", MaxCampaignLength)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token, err := Sign("secret", Link{PollID: pollID, Campaign: tt.campaign, ExpiresAt: testNow.Add(time.Hour)})
+			require.NoError(t, err)
+
+			link, err := Parse("secret", token, testNow)
+			require.NoError(t, err)
+			assert.Equal(t, pollID, link.PollID)
+			assert.Equal(t, tt.campaign, link.Campaign)
+			assert.True(t, link.ExpiresAt.Equal(testNow.Add(time.Hour)))
+		})
+	}
+}
+
+func TestSign_RejectsLongCampaign(t *testing.T) {
+	_, err := Sign("secret", Link{PollID: uuid.New(), Campaign: strings.Repeat("c", MaxCampaignLength+1), ExpiresAt: testNow})
+	assert.Error(t, err)
+}
+
+func TestParse(t *testing.T) {
+	valid, err := Sign("secret", Link{PollID: uuid.New(), Campaign: "ads", ExpiresAt: testNow.Add(time.Hour)})
+	require.NoError(t, err)
+	expired, err := Sign("secret", Link{PollID: uuid.New(), ExpiresAt: testNow})
+	require.NoError(t, err)
+
+	// Flip a bit inside the payload so the signature no longer matches
+	tampered := []byte(valid)
+	tampered[30] ^= 1
+
+	tests := []struct {
+		name    string
+		secret  string
+		token   string
+		wantErr error
+	}{
+		{name: "valid token", secret: "secret", token: valid},
+		{name: "wrong secret", secret: "other", token: valid, wantErr: ErrInvalidToken},
+		{name: "tampered token", secret: "secret", token: string(tampered), wantErr: ErrInvalidToken},
+		{name: "expired token", secret: "secret", token: expired, wantErr: ErrTokenExpired},
+		{name: "truncated token", secret: "secret", token: valid[:20], wantErr: ErrInvalidToken},
+		{name: "malformed token", secret: "secret", token: "not a token!", wantErr: ErrInvalidToken},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			link, err := Parse(tt.secret, tt.token, testNow)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, link)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, "ads", link.Campaign)
+			}
+		})
+	}
+}